@@ -1,23 +1,44 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"log"
+	"os"
+	"time"
 
+	"vm-controller/internal/api/grpcapi"
 	"vm-controller/internal/api/routes"
+	"vm-controller/internal/backup"
 	"vm-controller/internal/config"
 	"vm-controller/internal/db"
+	"vm-controller/internal/logger"
+	"vm-controller/internal/middleware"
+	"vm-controller/internal/server"
 	"vm-controller/internal/services/k8s_service"
 )
 
 func main() {
 	// 1. 설정 로드 (Configuration)
+	restoreKey := flag.String("restore", "", "restore the controller database from a backup key (or \"latest\") and exit")
+	listBackups := flag.Bool("list-backups", false, "list controller database backups in storage and exit")
+	flag.Parse()
+
 	config := config.Load()
+	logger.Init(config)
 
+	// 백업 복원/조회 모드 (서버를 시작하지 않음)
+	if *listBackups || *restoreKey != "" {
+		runBackupCommand(*restoreKey, *listBackups)
+		return
+	}
 
+	// DB 서비스 생성 (K8sService, 컨트롤러, 백그라운드 루프에 주입)
+	services := routes.NewServices()
 
 	// 2. K8s 연결 확인 (K8s Connection Check)
-	k8sService, err := k8s_service.GetK8sService()
+	k8sService, err := k8s_service.NewK8sService(services.K8sDependencies())
 	if err != nil {
 		log.Fatalf("Failed to initialize K8s Service: %v", err)
 		panic(err)
@@ -28,6 +49,15 @@ func main() {
 	}
 	log.Println("Successfully connected to Kubernetes cluster")
 
+	// KubeVirt/CDI 설치 여부 감지 (없으면 VM 기능만 끄고 사용자 관리/배포는 계속 제공)
+	capabilities := k8sService.DetectCapabilities()
+	if capabilities.Error != "" {
+		log.Printf("Failed to detect cluster capabilities, assuming KubeVirt and CDI are installed: %s", capabilities.Error)
+	} else if !capabilities.VMs {
+		_, reason := k8s_service.VMFeaturesAvailable()
+		log.Printf("%s; VM endpoints will return 501 until the server is restarted after installation", reason)
+	}
+
 	// 3. 데이터베이스 초기화 (Database Initialization)
 	err = db.InitDB()
 
@@ -37,12 +67,150 @@ func main() {
 		panic(err)
 	}
 
+	// VM 상태 전이를 이벤트 스트림과 생성 시도 기록에 반영
+	services.VmEventService.SubscribeTransitions()
+	services.BundleService.SubscribeTransitions()
+
+	// 요금제 테이블이 비어 있으면 기본 요금제 등록
+	if err := services.FlavorService.SeedDefaults(); err != nil {
+		log.Fatalf("Failed to seed default flavors: %v", err)
+	}
+	if err := services.ImageService.SeedDefaults(); err != nil {
+		log.Fatalf("Failed to seed default images: %v", err)
+	}
+
+	// 백그라운드 작업 워커 시작 (서버 재시작 전에 남은 작업도 이어서 실행)
+	if pending, err := services.OperationService.CountActiveOperations(); err != nil {
+		log.Printf("Failed to count pending operations: %v", err)
+	} else if pending > 0 {
+		log.Printf("Resuming %d pending operations", pending)
+	}
+	k8sService.StartAsyncWorkers()
+
+	// 커넥션 풀 대기 시간 감시 (임계값 초과 시 풀 교체)
+	db.StartPoolWatchdog(30 * time.Second)
+
+	// 플랫폼 공용 리소스(네임스페이스, PriorityClass, 인터셉터 Middleware, 인증서 발급자) 확인/설치
+	results, err := k8sService.Bootstrap()
+	for _, result := range results {
+		if result.Action != k8s_service.BootstrapUnchanged {
+			log.Printf("Bootstrap: %s %s %s/%s %s", result.Action, result.Kind, result.Namespace, result.Name, result.Detail)
+		}
+	}
+	if err != nil {
+		log.Printf("Bootstrap incomplete: %v", err)
+	}
+
+	// 테넌트 네임스페이스 admission 정책 설치 (실패해도 서버는 시작)
+	if err := k8sService.EnsureAdmissionPolicies(); err != nil {
+		log.Printf("Failed to install admission policies: %v", err)
+	}
+
+	// VM 파드/일반 파드 네임스페이스 쿼터 적용 (실패해도 서버는 시작)
+	if err := k8sService.EnsureWorkloadQuotas(); err != nil {
+		log.Printf("Failed to apply workload quotas: %v", err)
+	}
+
+	// VM 관련 루프는 KubeVirt/CDI가 있을 때만 시작 (없으면 매 주기 실패 로그만 남으므로)
+	if capabilities.VMs {
+		// spec.running 으로 만들어진 VM을 runStrategy로 이전 (KubeVirt v1.3+)
+		if err := k8sService.MigrateRunStrategy(); err != nil {
+			log.Printf("Failed to migrate VM run strategy: %v", err)
+		}
+
+		// VM 목표 상태(desired_state) 수렴 루프 시작
+		k8sService.StartVMConverger(1 * time.Minute)
+
+		// DB와 클러스터의 VM 상태 차이를 바로잡는 루프 시작
+		k8sService.StartVMReconciler(5 * time.Minute)
+
+		// 사용 기간이 지난 VM 정지/삭제 루프 시작
+		k8sService.StartVMReaper(10 * time.Minute)
+
+		// 예약된 VM 요금제 변경 실행 루프 시작
+		k8sService.StartFlavorChangeScheduler(1 * time.Minute)
+
+		// 멈추거나 실패한 VM 디스크(DataVolume) 정리 루프 시작
+		k8sService.StartDataVolumeWatchdog(5 * time.Minute)
+
+		// VM CPU 사용량 샘플링 (limit 포화 리포트용)
+		k8sService.StartCPUSaturationSampler(1 * time.Minute)
+	}
+
+	// 보존 기간이 지난 콘솔 세션 기록 삭제
+	services.ConsoleService.StartRetentionCleanup(1 * time.Hour)
+
+	// 보존 기간이 지난 작업(operation) 기록 삭제
+	services.OperationService.StartRetentionCleanup(1 * time.Hour)
+
+	// 보존 기간이 지난 Idempotency-Key 삭제
+	middleware.StartIdempotencyKeyCleanup(1 * time.Hour)
+
+	// 유휴 배포 sleep-on-idle 루프 시작
+	k8sService.StartIdleReaper(10 * time.Minute)
+
+	// 컨트롤러 DB 정기 백업 (BACKUP_INTERVAL 설정 시에만)
+	backup.StartScheduler()
+
+	// 웹 Ingress 도달성 검사 (INGRESS_CHECK_INTERVAL 설정 시에만)
+	if interval := k8s_service.IngressCheckInterval(); interval > 0 {
+		k8sService.StartIngressChecker(interval)
+	}
+
+	// 암호화폐 채굴 자동 탐지 (MINING_DETECT_INTERVAL=0이면 사용 안 함)
+	if interval := k8s_service.MiningDetectInterval(); interval > 0 && capabilities.VMs {
+		k8sService.StartMiningDetector(interval)
+	}
+
 	// 4. 라우터 설정 (Router)
-	r := routes.SetupRouter()
+	container := routes.NewContainer(services, k8sService)
+	r := routes.SetupRouter(container)
+
+	// Operator 모드: UserVM CRD 설치 및 reconcile 컨트롤러 시작 (REST와 같은 생성 경로 사용)
+	if config.OperatorMode {
+		if !capabilities.VMs {
+			log.Printf("Operator not started: KubeVirt and CDI are required")
+		} else if err := container.LifecycleService.StartOperator(5 * time.Minute); err != nil {
+			log.Fatalf("Failed to start operator: %v", err)
+		}
+	}
+
+	// 내부 관리자 리스너 (pprof, 런타임 진단) - ADMIN_PORT 설정 시에만
+	go server.RunAdmin(config, routes.SetupAdminRouter())
+
+	// 내부 자동화용 gRPC API (REST와 같은 서비스 계층) - GRPC_PORT 설정 시에만
+	go grpcapi.Run(config, container)
 
-	// 5. 서버 시작 (Start Server)
-	log.Printf("Starting server on port %s", config.Port)
-	if err := r.Run(fmt.Sprintf(":%s", config.Port)); err != nil {
+	// 5. 서버 시작 (Start Server) - TLS 설정 시 HTTPS로 직접 제공
+	if err := server.Run(config, r); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}
 }
+
+// runBackupCommand는 -list-backups, -restore 플래그를 처리합니다.
+// 복원은 테이블을 다시 만들므로 모든 API 서버 인스턴스를 멈춘 상태에서 실행해야 합니다.
+func runBackupCommand(restoreKey string, list bool) {
+	store, err := backup.NewStoreFromEnv()
+	if err != nil {
+		log.Fatalf("Backup storage: %v", err)
+	}
+	ctx := context.Background()
+
+	if list {
+		objects, err := store.List(ctx)
+		if err != nil {
+			log.Fatalf("Failed to list backups: %v", err)
+		}
+		for _, object := range objects {
+			fmt.Fprintf(os.Stdout, "%s\t%d\t%s\n", object.Key, object.Size, object.LastModified.Format(time.RFC3339))
+		}
+		return
+	}
+
+	// 복원된 스키마가 현재 버전보다 오래되었으면 다음 서버 시작 시 AutoMigrate가 맞춤
+	object, err := backup.Restore(ctx, store, restoreKey)
+	if err != nil {
+		log.Fatalf("Failed to restore backup: %v", err)
+	}
+	log.Printf("Restored controller database from %s (%d bytes, taken %s)", object.Key, object.Size, object.LastModified.Format(time.RFC3339))
+}
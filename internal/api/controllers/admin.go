@@ -0,0 +1,1699 @@
+package controllers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	http "net/http"
+	"slices"
+	"strings"
+	"time"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/config"
+	"vm-controller/internal/logger"
+	"vm-controller/internal/metrics"
+	"vm-controller/internal/middleware"
+	"vm-controller/internal/models"
+	"vm-controller/internal/policy"
+	approvalservice "vm-controller/internal/services/approval_service"
+	auditservice "vm-controller/internal/services/audit_service"
+	blocklistservice "vm-controller/internal/services/blocklist_service"
+	bundleservice "vm-controller/internal/services/bundle_service"
+	consoleservice "vm-controller/internal/services/console_service"
+	deploymentservice "vm-controller/internal/services/deployment_service"
+	flavorservice "vm-controller/internal/services/flavor_service"
+	imageservice "vm-controller/internal/services/image_service"
+	k8s_service "vm-controller/internal/services/k8s_service"
+	networkservice "vm-controller/internal/services/network_service"
+	nodepoolservice "vm-controller/internal/services/nodepool_service"
+	notificationservice "vm-controller/internal/services/notification_service"
+	operationservice "vm-controller/internal/services/operation_service"
+	quotaservice "vm-controller/internal/services/quota_service"
+	reportservice "vm-controller/internal/services/report_service"
+	statsservice "vm-controller/internal/services/stats_service"
+	userservice "vm-controller/internal/services/user_service"
+	vmeventservice "vm-controller/internal/services/vm_event_service"
+	vmlifecycleservice "vm-controller/internal/services/vm_lifecycle_service"
+	vm_service "vm-controller/internal/services/vm_service"
+	volumeservice "vm-controller/internal/services/volume_service"
+	"vm-controller/internal/version"
+
+	gin "github.com/gin-gonic/gin"
+	cast "github.com/spf13/cast"
+	yaml "sigs.k8s.io/yaml"
+)
+
+type AdminController struct {
+	k8sService  *k8s_service.K8sService
+	provisioner k8s_service.K8sProvisioner // VM 삭제 (VirtualMachineController와 같은 인스턴스)
+
+	approvalService     *approvalservice.ApprovalService
+	auditService        *auditservice.AuditService
+	blocklistService    *blocklistservice.BlocklistService
+	bundleService       *bundleservice.BundleService
+	consoleService      *consoleservice.ConsoleService
+	deploymentService   *deploymentservice.DeploymentService
+	flavorService       *flavorservice.FlavorService
+	imageService        *imageservice.ImageService
+	networkService      *networkservice.NetworkService
+	nodePoolService     *nodepoolservice.NodePoolService
+	notificationService *notificationservice.NotificationService
+	operationService    *operationservice.OperationService
+	quotaService        *quotaservice.QuotaService
+	reportService       *reportservice.ReportService
+	statsService        *statsservice.StatsService
+	userService         *userservice.UserService
+	vmEventService      *vmeventservice.VmEventService
+	vmService           *vm_service.VmService
+	volumeService       *volumeservice.VolumeService
+
+	vmController *VirtualMachineController // 승인된 요청의 VM 생성 (createVM 재사용)
+}
+
+// AdminDependencies는 AdminController가 사용하는 서비스입니다.
+type AdminDependencies struct {
+	K8sService  *k8s_service.K8sService
+	Provisioner k8s_service.K8sProvisioner
+
+	ApprovalService     *approvalservice.ApprovalService
+	AuditService        *auditservice.AuditService
+	BlocklistService    *blocklistservice.BlocklistService
+	BundleService       *bundleservice.BundleService
+	ConsoleService      *consoleservice.ConsoleService
+	DeploymentService   *deploymentservice.DeploymentService
+	FlavorService       *flavorservice.FlavorService
+	ImageService        *imageservice.ImageService
+	NetworkService      *networkservice.NetworkService
+	NodePoolService     *nodepoolservice.NodePoolService
+	NotificationService *notificationservice.NotificationService
+	OperationService    *operationservice.OperationService
+	QuotaService        *quotaservice.QuotaService
+	ReportService       *reportservice.ReportService
+	StatsService        *statsservice.StatsService
+	UserService         *userservice.UserService
+	VmEventService      *vmeventservice.VmEventService
+	VmService           *vm_service.VmService
+	VolumeService       *volumeservice.VolumeService
+}
+
+func NewAdminController(deps AdminDependencies, vmController *VirtualMachineController) *AdminController {
+	return &AdminController{
+		k8sService:  deps.K8sService,
+		provisioner: deps.Provisioner,
+
+		approvalService:     deps.ApprovalService,
+		auditService:        deps.AuditService,
+		blocklistService:    deps.BlocklistService,
+		bundleService:       deps.BundleService,
+		consoleService:      deps.ConsoleService,
+		deploymentService:   deps.DeploymentService,
+		flavorService:       deps.FlavorService,
+		imageService:        deps.ImageService,
+		networkService:      deps.NetworkService,
+		nodePoolService:     deps.NodePoolService,
+		notificationService: deps.NotificationService,
+		operationService:    deps.OperationService,
+		quotaService:        deps.QuotaService,
+		reportService:       deps.ReportService,
+		statsService:        deps.StatsService,
+		userService:         deps.UserService,
+		vmEventService:      deps.VmEventService,
+		vmService:           deps.VmService,
+		volumeService:       deps.VolumeService,
+
+		vmController: vmController,
+	}
+}
+
+func (aC *AdminController) RegisterRoutes(r *gin.RouterGroup) {
+	// auditor는 관리자 화면을 조회만 할 수 있음 (정책 엔진이 변경 요청 거부)
+	admin := r.Group("/admin", middleware.AuthGuard(), middleware.Authorize(policy.ResourceAdmin), middleware.Idempotency())
+
+	// KubeVirt 리소스를 직접 다루는 라우트 (KubeVirt/CDI가 없으면 501, DB 기록만 다루는 조회/이전은 계속 제공)
+	requireVMs := middleware.RequireCapability("vms", k8s_service.VMFeaturesAvailable)
+
+	admin.GET("/vms", aC.FetchVMs)
+	admin.GET("/vms/export", aC.ExportVMs)
+	admin.GET("/vms/:name", aC.FetchVM)
+	admin.GET("/vms/:name/events", aC.FetchVMEvents)
+	admin.POST("/vms/:name/owner", aC.TransferVM)
+	admin.POST("/vms/:name/recreate", requireVMs, aC.RecreateVM)
+	admin.DELETE("/vms/:name", requireVMs, aC.DeleteVM)
+	admin.GET("/vms/cpu-saturation", requireVMs, aC.FetchCPUSaturation)
+	admin.GET("/vms/unmanaged", requireVMs, aC.FetchUnmanagedVMs)
+	admin.GET("/drift", requireVMs, aC.FetchDrift)
+	admin.POST("/drift/fix", requireVMs, aC.FixDrift)
+	admin.GET("/node-pools", aC.FetchNodePools)
+	admin.PUT("/node-pools/:pool/maintenance", aC.SetNodePoolMaintenance)
+	admin.DELETE("/node-pools/:pool/maintenance", aC.ClearNodePoolMaintenance)
+	admin.GET("/bootstrap", aC.FetchBootstrap)
+	admin.POST("/bootstrap", aC.RunBootstrap)
+	admin.GET("/alerts/rules", aC.FetchAlertRules)
+	admin.GET("/operations/:id", aC.FetchOperation)
+	admin.GET("/features", aC.FetchFeatures)
+	admin.PUT("/features/:name", aC.UpdateFeature)
+	admin.GET("/public-stats", aC.FetchPublicStatSettings)
+	admin.PUT("/public-stats", aC.UpdatePublicStats)
+	admin.GET("/notification-templates", aC.FetchNotificationTemplates)
+	admin.PUT("/notification-templates/:key/:locale", aC.UpdateNotificationTemplate)
+	admin.DELETE("/notification-templates/:key/:locale", aC.ResetNotificationTemplate)
+	admin.POST("/vm/migrate", requireVMs, aC.MigrateVM)
+	admin.GET("/vm/migrate", requireVMs, aC.FetchMigrations)
+	admin.GET("/console-sessions", aC.FetchConsoleSessions)
+	admin.GET("/networks", aC.FetchNetworks)
+	admin.POST("/networks", aC.SaveNetwork)
+	admin.DELETE("/networks/:name", aC.DeleteNetwork)
+	admin.GET("/policies", aC.FetchAdmissionPolicies)
+	admin.POST("/policies/:key", aC.SetAdmissionPolicy)
+	admin.GET("/storage", aC.FetchStorageStats)
+	admin.GET("/tenants/:tenant", aC.FetchTenant)
+	admin.GET("/namespaces/:namespace/pod-security", aC.FetchPodSecurity)
+	admin.POST("/namespaces/:namespace/pod-security", aC.SetPodSecurity)
+	admin.GET("/permissions", aC.FetchPermissions)
+	admin.GET("/bundles/report", aC.FetchBundleReport)
+	admin.GET("/users", aC.FetchUsers)
+	admin.POST("/users/:id/role", aC.SetUserRole)
+	admin.POST("/users/:id/vm-lease", aC.SetUserVMLease)
+	admin.POST("/users/:id/suspend", aC.SuspendUser)
+	admin.DELETE("/users/:id/suspend", aC.UnsuspendUser)
+	admin.GET("/audit-logs", aC.FetchAuditLogs)
+	admin.GET("/security-events", aC.FetchSecurityEvents)
+	admin.GET("/security/bans", aC.FetchBans)
+	admin.POST("/security/bans", aC.BanIP)
+	admin.DELETE("/security/bans/:ip", aC.UnbanIP)
+	admin.GET("/security/offenders", aC.FetchOffenders)
+	admin.GET("/reports", aC.FetchAbuseReports)
+	admin.POST("/reports/:id/actions", aC.HandleAbuseReport)
+	admin.GET("/approvals", aC.FetchApprovals)
+	admin.POST("/approvals/:id/approve", requireVMs, aC.ApproveVM)
+	admin.POST("/approvals/:id/reject", aC.RejectVM)
+	admin.GET("/flavors", aC.FetchFlavors)
+	admin.POST("/flavors", aC.CreateFlavor)
+	admin.PUT("/flavors/:name", aC.UpdateFlavor)
+	admin.DELETE("/flavors/:name", aC.DeleteFlavor)
+	admin.GET("/images", aC.FetchImages)
+	admin.POST("/images", aC.CreateImage)
+	admin.PUT("/images/:name", aC.UpdateImage)
+	admin.DELETE("/images/:name", aC.DeleteImage)
+}
+
+// AdminVMResponse는 관리자 VM 목록의 한 항목입니다. (소유자 정보 포함)
+type AdminVMResponse struct {
+	VMResponse
+	UserID    uint   `json:"user_id"`
+	Owner     string `json:"owner"`
+	StudentID string `json:"student_id"`
+}
+
+// FetchVMs는 모든 사용자의 VM을 사용자 목록과 같은 조건(페이지/상태/이름 검색/정렬)으로 반환합니다.
+// user_id를 지정하면 그 사용자의 VM만 반환합니다.
+// GET /api/admin/vms?page=1&limit=50&status=Failed&q=lab&sort=-updated_at&user_id=3
+func (aC *AdminController) FetchVMs(c *gin.Context) {
+	query, err := parseVMQuery(c)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeInvalidRequest))
+		return
+	}
+	if raw := c.Query("user_id"); raw != "" {
+		userId, err := cast.ToUintE(raw)
+		if err != nil {
+			middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid user_id"))
+			return
+		}
+		query.UserID = &userId
+	}
+	query.WithOwner = true
+
+	page, err := aC.vmService.WithContext(c.Request.Context()).QueryVMs(query)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch VMs"))
+		return
+	}
+
+	vms := make([]AdminVMResponse, 0, len(page.VMs))
+	for i := range page.VMs {
+		vm := &page.VMs[i]
+		vms = append(vms, AdminVMResponse{
+			VMResponse: newVMResponse(vm),
+			UserID:     vm.UserID,
+			Owner:      vm.User.Username,
+			StudentID:  vm.User.UserStudentId,
+		})
+	}
+
+	response := vmPageResponse(page)
+	response["vms"] = vms
+	c.JSON(http.StatusOK, response)
+}
+
+// VMReportRow는 용량 계획용 VM 리포트의 한 행입니다.
+type VMReportRow struct {
+	Owner        string `json:"owner"`
+	StudentID    string `json:"student_id"`
+	Name         string `json:"name"`
+	Namespace    string `json:"namespace"`
+	Image        string `json:"image"`
+	Flavor       string `json:"flavor"`
+	Status       string `json:"status"`
+	Node         string `json:"node"`
+	UptimeSec    int64  `json:"uptime_seconds"`
+	LastActivity string `json:"last_activity"`
+	Port         int32  `json:"port"`
+}
+
+// VM 리포트를 DB에서 읽고 응답으로 전송하는 행 단위
+const vmReportBatchRows = 500
+
+var vmReportHeader = []string{"owner", "student_id", "name", "namespace", "image", "flavor", "status", "node", "uptime_seconds", "last_activity", "port"}
+
+func (row VMReportRow) csvRecord() []string {
+	return []string{
+		row.Owner, row.StudentID, row.Name, row.Namespace, row.Image, row.Flavor, row.Status,
+		row.Node, fmt.Sprintf("%d", row.UptimeSec), row.LastActivity, fmt.Sprintf("%d", row.Port),
+	}
+}
+
+// streamVMReport는 DB의 VM 목록을 vmReportBatchRows개씩 읽어 클러스터의 VMI 정보와 합친 리포트 행을 emit에 넘깁니다.
+// 요청이 취소되면(클라이언트 연결 종료) 중단합니다.
+func (aC *AdminController) streamVMReport(ctx context.Context, emit func(row VMReportRow) error) error {
+	// 클러스터 조회 실패 시에도 DB 정보만으로 리포트 생성
+	vmis, err := aC.k8sService.WithContext(ctx).ListVMIs()
+	if err != nil {
+		logger.FromContext(ctx).Warn("VM report: failed to list VMIs, node/uptime will be empty", "error", err)
+		vmis = map[string]k8s_service.VMIInfo{}
+	}
+
+	now := time.Now()
+	return aC.vmService.WithContext(ctx).EachVMBatch(vmReportBatchRows, func(vms []models.VirtualMachine) error {
+		for _, vm := range vms {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			row := VMReportRow{
+				Owner:        vm.User.Username,
+				StudentID:    vm.User.UserStudentId,
+				Name:         vm.Name,
+				Namespace:    vm.Namespace,
+				Image:        vm.Image,
+				Flavor:       vm.Flavor,
+				Status:       string(vm.Status),
+				LastActivity: vm.UpdatedAt.Format(time.RFC3339),
+				Port:         vm.NodePort,
+			}
+
+			if vmi, ok := vmis[vm.Namespace+"/"+vm.Name]; ok {
+				row.Node = vmi.NodeName
+				if vmi.Phase == "Running" {
+					row.UptimeSec = int64(now.Sub(vmi.StartedAt).Seconds())
+				}
+			}
+
+			if err := emit(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// vmReportEncoder는 리포트 형식별로 시작/행/끝 부분을 응답에 씁니다.
+type vmReportEncoder struct {
+	contentType string
+	begin       func(w io.Writer) error
+	row         func(w io.Writer, i int, row VMReportRow) error
+	flush       func() error // 버퍼에 쌓인 행을 응답으로 보냄
+	end         func(w io.Writer, rows int) error
+}
+
+func newVMReportEncoder(format string) vmReportEncoder {
+	switch format {
+	case "json":
+		// 배열 전체를 Marshal하지 않고 원소를 하나씩 기록
+		return vmReportEncoder{
+			contentType: "application/json; charset=utf-8",
+			begin:       func(w io.Writer) error { _, err := io.WriteString(w, "["); return err },
+			row: func(w io.Writer, i int, row VMReportRow) error {
+				out, err := json.Marshal(row)
+				if err != nil {
+					return err
+				}
+				if i > 0 {
+					out = append([]byte(","), out...)
+				}
+				_, err = w.Write(out)
+				return err
+			},
+			flush: func() error { return nil },
+			end:   func(w io.Writer, _ int) error { _, err := io.WriteString(w, "]\n"); return err },
+		}
+	case "yaml":
+		// 원소 하나짜리 시퀀스("- ...")를 이어 붙이면 전체 시퀀스와 같은 문서가 됨
+		return vmReportEncoder{
+			contentType: "application/yaml; charset=utf-8",
+			begin:       func(io.Writer) error { return nil },
+			row: func(w io.Writer, _ int, row VMReportRow) error {
+				out, err := yaml.Marshal([]VMReportRow{row})
+				if err != nil {
+					return err
+				}
+				_, err = w.Write(out)
+				return err
+			},
+			flush: func() error { return nil },
+			end: func(w io.Writer, rows int) error {
+				if rows > 0 {
+					return nil
+				}
+				_, err := io.WriteString(w, "[]\n")
+				return err
+			},
+		}
+	}
+
+	var writer *csv.Writer
+	flush := func() error {
+		writer.Flush()
+		return writer.Error()
+	}
+	return vmReportEncoder{
+		contentType: "text/csv; charset=utf-8",
+		begin: func(w io.Writer) error {
+			writer = csv.NewWriter(w)
+			return writer.Write(vmReportHeader)
+		},
+		row: func(_ io.Writer, _ int, row VMReportRow) error {
+			return writer.Write(row.csvRecord())
+		},
+		flush: flush,
+		end:   func(io.Writer, int) error { return flush() },
+	}
+}
+
+// ExportVMs는 전체 VM 리포트를 CSV(기본), JSON 또는 YAML로 스트리밍합니다.
+// format을 생략하고 Accept: application/yaml 로 요청하면 YAML로 응답합니다.
+// 행은 DB에서 배치 단위로 읽는 대로 전송하므로 리포트 전체를 메모리에 모으지 않습니다.
+// 전송을 시작한 뒤 실패하면 상태 코드를 바꿀 수 없으므로 응답이 중간에 끊깁니다.
+// GET /api/admin/vms/export?format=csv|json|yaml
+func (aC *AdminController) ExportVMs(c *gin.Context) {
+	format := c.Query("format")
+	if format == "" {
+		format = "csv"
+		if wantsYAML(c) {
+			format = "yaml"
+		}
+	}
+	if format != "csv" && format != "json" && format != "yaml" {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "format must be csv, json or yaml"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	log := logger.FromContext(ctx)
+
+	// 리포트 크기에 비례해 오래 걸리므로 서버 WriteTimeout을 이 요청에 한해 해제
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		log.Warn("VM report: failed to clear write deadline", "error", err)
+	}
+
+	encoder := newVMReportEncoder(format)
+	filename := fmt.Sprintf("vms-%s.%s", time.Now().Format("20060102-150405"), format)
+
+	// 첫 행을 읽은 뒤에 응답을 시작해, DB 조회가 바로 실패하면 에러 응답을 보낼 수 있게 함
+	started := false
+	start := func() error {
+		started = true
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		c.Header("Content-Type", encoder.contentType)
+		c.Status(http.StatusOK)
+		return encoder.begin(c.Writer)
+	}
+
+	rows := 0
+	err := aC.streamVMReport(ctx, func(row VMReportRow) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if err := encoder.row(c.Writer, rows, row); err != nil {
+			return err
+		}
+		rows++
+		if rows%vmReportBatchRows == 0 {
+			if err := encoder.flush(); err != nil {
+				return err
+			}
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	if err == nil && !started {
+		err = start()
+	}
+	if err == nil {
+		err = encoder.end(c.Writer, rows)
+	}
+	if err != nil {
+		if !started {
+			middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to build VM report"))
+			return
+		}
+		log.Error("VM report: export interrupted", "format", format, "rows", rows, "error", err)
+		c.Abort()
+	}
+}
+
+// FetchVMEvents는 VM의 이벤트 스트림과, 이를 재생하여 재구성한 상태를 반환합니다.
+// 재구성한 상태와 DB의 현재 상태가 다르면 이벤트 누락/경합을 의심할 수 있습니다.
+// GET /api/admin/vms/:name/events
+func (aC *AdminController) FetchVMEvents(c *gin.Context) {
+	name := c.Param("name")
+
+	replay, err := aC.vmEventService.Replay(name)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch VM events"))
+		return
+	}
+
+	if len(replay.Events) == 0 {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeNotFound, "VM events not found"))
+		return
+	}
+
+	currentStatus := ""
+	if vm, err := aC.vmService.WithContext(c.Request.Context()).FetchVmNameIncludingDeleted(name); err == nil && vm != nil {
+		currentStatus = string(vm.Status)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"vm_name":        name,
+		"current_status": currentStatus,
+		"consistent":     replay.ReplayedStatus == "" || replay.ReplayedStatus == currentStatus,
+		"replay":         replay,
+	})
+}
+
+// RecreateVM은 클러스터에서 사라진 VM 리소스를 DB에 저장된 MAC 주소/호스트 이름/NodePort/DNS로 다시 생성합니다.
+// 남아 있는 디스크는 그대로 재사용하므로 게스트 내부 네트워크 설정이 유지됩니다.
+// POST /api/admin/vms/:name/recreate
+func (aC *AdminController) RecreateVM(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	actorId, err := cast.ToUintE(user_id)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid user_id"))
+		return
+	}
+
+	vm, err := aC.vmService.WithContext(c.Request.Context()).FetchVmName(c.Param("name"), true)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch VM"))
+		return
+	}
+	if vm == nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeVMNotFound, "VM not found"))
+		return
+	}
+
+	aC.vmEventService.RecordOperation(vm.Name, "recreate", actorId)
+	if err := aC.auditService.Record(&actorId, "vm.recreate", "vm/"+vm.Name, vm.MacAddress); err != nil {
+		logger.FromContext(c.Request.Context()).Warn("failed to record audit log", "vm", vm.Name, "error", err)
+	}
+	operationID := aC.k8sService.WithRequest(c.Request.Context()).RecreateVMAsync(vm, c.GetString("trace_id"))
+
+	c.JSON(http.StatusAccepted, gin.H{"vm": newVMResponse(vm), "operation_id": operationID})
+}
+
+type MigrateVMParams struct {
+	VmName string `json:"vm_name" binding:"required"`
+}
+
+// MigrateVM은 실행 중인 VM을 다른 노드로 라이브 마이그레이션합니다. (감사 로그 기록)
+// 노드 드레인 전에 학생 VM을 중단 없이 옮기는 용도이며, 진행 상황은 GET /api/admin/vm/migrate로 확인합니다.
+// POST /api/admin/vm/migrate {"vm_name": "..."}
+func (aC *AdminController) MigrateVM(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	var req MigrateVMParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	actorId, err := cast.ToUintE(user_id)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid user_id"))
+		return
+	}
+
+	vm, err := aC.vmService.WithContext(c.Request.Context()).FetchVmName(req.VmName, false)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch VM"))
+		return
+	}
+	if vm == nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeVMNotFound, "VM not found"))
+		return
+	}
+
+	if vm.Status != models.VmStatusRunning {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidState, "현재 상태("+string(vm.Status)+")에서는 요청을 처리할 수 없습니다."))
+		return
+	}
+
+	migration, err := aC.k8sService.WithContext(c.Request.Context()).MigrateVM(vm)
+	if err != nil {
+		switch {
+		case errors.Is(err, k8s_service.ErrVMNotRunning), errors.Is(err, k8s_service.ErrNotMigratable), errors.Is(err, k8s_service.ErrMigrationInProgress):
+			middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeConflict))
+		default:
+			logger.FromContext(c.Request.Context()).Error("failed to migrate VM", "vm", vm.Name, "error", err)
+			middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to migrate VM"))
+		}
+		return
+	}
+
+	aC.vmEventService.RecordOperation(vm.Name, "migrate", actorId)
+	if err := aC.auditService.Record(&actorId, "vm.migrate", "vm/"+vm.Name, migration.Name); err != nil {
+		logger.FromContext(c.Request.Context()).Warn("failed to record audit log", "vm", vm.Name, "error", err)
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"migration": migration})
+}
+
+// FetchMigrations는 VM의 라이브 마이그레이션 목록과 진행 상황(단계, 원본/대상 노드, 실패 사유)을 최신순으로 반환합니다.
+// GET /api/admin/vm/migrate?vm_name=
+func (aC *AdminController) FetchMigrations(c *gin.Context) {
+	vm, err := aC.vmService.WithContext(c.Request.Context()).FetchVmName(c.Query("vm_name"), false)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch VM"))
+		return
+	}
+	if vm == nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeVMNotFound, "VM not found"))
+		return
+	}
+
+	migrations, err := aC.k8sService.WithContext(c.Request.Context()).ListVMMigrations(vm)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("failed to list VM migrations", "vm", vm.Name, "error", err)
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch migrations"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"migrations": migrations})
+}
+
+// deletePolicyFromQuery는 관리자 삭제 요청의 옵션을 읽습니다. 생략한 항목은 kind의 설정(DELETE_POLICIES)을 따릅니다.
+func deletePolicyFromQuery(c *gin.Context, kind string) (config.DeletePolicy, error) {
+	policy := config.Get().DeletePolicyFor(kind)
+
+	if propagation := c.Query("propagation"); propagation != "" {
+		policy.Propagation = propagation
+	}
+	if value := c.Query("grace_period_seconds"); value != "" {
+		seconds, err := cast.ToInt64E(value)
+		if err != nil {
+			return policy, fmt.Errorf("grace_period_seconds must be an integer")
+		}
+		policy.GracePeriodSeconds = &seconds
+	}
+	if value := c.Query("force"); value != "" {
+		force, err := cast.ToBoolE(value)
+		if err != nil {
+			return policy, fmt.Errorf("force must be true or false")
+		}
+		policy.Force = force
+	}
+
+	return policy, policy.Validate()
+}
+
+// DeleteVM은 관리자가 삭제 옵션(전파 정책, 유예 시간, 강제 삭제)을 지정하여 VM을 삭제합니다. (감사 로그 기록)
+// 종료되지 않는 VM을 정리할 때 사용하며, 옵션을 생략하면 DELETE_POLICIES 설정을 따릅니다.
+// DELETE /api/admin/vms/:name?propagation=Background|Foreground|Orphan&grace_period_seconds=0&force=true
+func (aC *AdminController) DeleteVM(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	actorId, err := cast.ToUintE(user_id)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid user_id"))
+		return
+	}
+
+	if config.Get().OperatorMode {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Delete options are not supported in operator mode"))
+		return
+	}
+
+	policy, err := deletePolicyFromQuery(c, "VirtualMachine")
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeInvalidRequest))
+		return
+	}
+
+	vm, err := aC.vmService.WithContext(c.Request.Context()).FetchVmName(c.Param("name"), false)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch VM"))
+		return
+	}
+	if vm == nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeVMNotFound, "VM not found"))
+		return
+	}
+
+	// 목표 상태를 먼저 저장 (작업이 중단되면 converger가 설정된 옵션으로 이어서 삭제)
+	if err := aC.vmService.SetDesiredState(vm.Name, models.VmDesiredDeleted); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to update VM"))
+		return
+	}
+
+	detail, _ := json.Marshal(policy)
+	aC.vmEventService.RecordOperation(vm.Name, "delete", actorId)
+	if err := aC.auditService.Record(&actorId, "vm.delete", "vm/"+vm.Name, string(detail)); err != nil {
+		logger.FromContext(c.Request.Context()).Warn("failed to record audit log", "vm", vm.Name, "error", err)
+	}
+	operationID := aC.provisioner.ProvisionerWithRequest(c.Request.Context()).DeleteVMWithPolicyAsync(vm, policy, c.GetString("trace_id"))
+
+	c.JSON(http.StatusAccepted, gin.H{"vm": newVMResponse(vm), "delete_policy": policy, "operation_id": operationID})
+}
+
+// FetchCPUSaturation은 CPU limit에 지속적으로 도달하는 VM을 소유자/요금제 정보와 함께 반환합니다.
+// 상위 요금제 전환을 안내할 대상을 찾는 데 사용합니다.
+// GET /api/admin/vms/cpu-saturation
+func (aC *AdminController) FetchCPUSaturation(c *gin.Context) {
+	vms, err := aC.vmService.WithContext(c.Request.Context()).FetchAllVMs(false)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch VMs"))
+		return
+	}
+
+	byKey := make(map[string]models.VirtualMachine, len(vms))
+	for _, vm := range vms {
+		byKey[vm.Namespace+"/"+vm.Name] = vm
+	}
+
+	rows := []gin.H{}
+	for _, saturation := range aC.k8sService.ListCPUSaturatedVMs() {
+		vm, ok := byKey[saturation.Namespace+"/"+saturation.VmName]
+		if !ok {
+			continue
+		}
+
+		rows = append(rows, gin.H{
+			"owner":      vm.User.Username,
+			"student_id": vm.User.UserStudentId,
+			"flavor":     vm.Flavor,
+			"saturation": saturation,
+		})
+	}
+
+	flavors, err := k8s_service.ListFlavors(aC.flavorService)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch flavors"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"vms": rows, "flavors": flavors})
+}
+
+// FetchUnmanagedVMs는 reconciler가 마지막으로 확인한 시점에 클러스터에는 있지만 DB에 없는 VM을 반환합니다.
+// managed가 true면 이 컨트롤러가 만든 리소스가 남은 것이므로 정리 대상입니다.
+// GET /api/admin/vms/unmanaged
+func (aC *AdminController) FetchUnmanagedVMs(c *gin.Context) {
+	vms, checkedAt := aC.k8sService.ListUnmanagedVMs()
+
+	response := gin.H{"vms": vms}
+	if !checkedAt.IsZero() {
+		response["checked_at"] = checkedAt.Format(time.RFC3339)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// FetchConsoleSessions는 콘솔 세션 기록을 최신순으로 반환합니다. (vm_name 으로 필터링 가능)
+// GET /api/admin/console-sessions?vm_name=&limit=
+func (aC *AdminController) FetchConsoleSessions(c *gin.Context) {
+	consoleService := aC.consoleService
+
+	limit := cast.ToInt(c.DefaultQuery("limit", "100"))
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+
+	sessions, err := consoleService.FetchSessions(c.Query("vm_name"), limit)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch console sessions"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"recording_enabled": consoleService.RecordingEnabled(),
+		"retention_days":    int(consoleService.Retention().Hours() / 24),
+		"sessions":          sessions,
+	})
+}
+
+// FetchNetworks는 보조 NIC 네트워크 카탈로그 전체(비활성 포함)를 반환합니다.
+// GET /api/admin/networks
+func (aC *AdminController) FetchNetworks(c *gin.Context) {
+	networks, err := aC.networkService.FetchNetworks(false)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch networks"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"networks": networks})
+}
+
+type SaveNetworkParams struct {
+	Name          string `json:"name" binding:"required"`
+	Description   string `json:"description"`
+	NadNamespace  string `json:"nad_namespace" binding:"required"` // Multus NetworkAttachmentDefinition 위치
+	NadName       string `json:"nad_name" binding:"required"`
+	AllowStaticIP bool   `json:"allow_static_ip"`
+	Enabled       bool   `json:"enabled"`
+}
+
+// SaveNetwork는 카탈로그에 네트워크를 등록하거나 수정합니다.
+// POST /api/admin/networks
+func (aC *AdminController) SaveNetwork(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	var req SaveNetworkParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	network, err := aC.networkService.SaveNetwork(networkservice.SaveNetworkParams{
+		Name:          req.Name,
+		Description:   req.Description,
+		NadNamespace:  req.NadNamespace,
+		NadName:       req.NadName,
+		AllowStaticIP: req.AllowStaticIP,
+		Enabled:       req.Enabled,
+	})
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeInvalidRequest))
+		return
+	}
+
+	if actorId, err := cast.ToUintE(user_id); err == nil {
+		detail := fmt.Sprintf("%s/%s enabled=%t static_ip=%t", req.NadNamespace, req.NadName, req.Enabled, req.AllowStaticIP)
+		if err := aC.auditService.Record(&actorId, "network.save", "network/"+req.Name, detail); err != nil {
+			logger.FromContext(c.Request.Context()).Warn("failed to record audit log", "network", req.Name, "error", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"network": network})
+}
+
+// DeleteNetwork는 카탈로그에서 네트워크를 삭제합니다. 이미 연결된 VM의 NIC는 유지됩니다.
+// DELETE /api/admin/networks/:name
+func (aC *AdminController) DeleteNetwork(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	name := c.Param("name")
+	if err := aC.networkService.DeleteNetwork(name); err != nil {
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeNotFound))
+		return
+	}
+
+	if actorId, err := cast.ToUintE(user_id); err == nil {
+		if err := aC.auditService.Record(&actorId, "network.delete", "network/"+name, ""); err != nil {
+			logger.FromContext(c.Request.Context()).Warn("failed to record audit log", "network", name, "error", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Network deleted"})
+}
+
+// FetchAdmissionPolicies는 테넌트 네임스페이스에 적용되는 admission 정책 상태를 반환합니다.
+// GET /api/admin/policies
+func (aC *AdminController) FetchAdmissionPolicies(c *gin.Context) {
+	policies, err := aC.k8sService.WithContext(c.Request.Context()).ListAdmissionPolicies()
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch admission policies"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"policies": policies})
+}
+
+type SetAdmissionPolicyParams struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// SetAdmissionPolicy는 admission 정책을 활성화/비활성화합니다.
+// POST /api/admin/policies/:key {"enabled": true|false}
+func (aC *AdminController) SetAdmissionPolicy(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	var req SetAdmissionPolicyParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	key := c.Param("key")
+	if err := aC.k8sService.WithContext(c.Request.Context()).SetAdmissionPolicyEnabled(key, *req.Enabled); err != nil {
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeInvalidRequest))
+		return
+	}
+
+	logger.FromContext(c.Request.Context()).Info("admission policy changed", "user_id", user_id, "policy", key, "enabled", *req.Enabled)
+	c.JSON(http.StatusOK, gin.H{"key": key, "enabled": *req.Enabled})
+}
+
+// FetchPodSecurity는 네임스페이스의 Pod Security 레벨과 변경 이력을 반환합니다.
+// GET /api/admin/namespaces/:namespace/pod-security
+func (aC *AdminController) FetchPodSecurity(c *gin.Context) {
+	namespace := c.Param("namespace")
+
+	level, err := aC.k8sService.WithContext(c.Request.Context()).GetPodSecurityLevel(namespace)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeNotFound))
+		return
+	}
+
+	history, err := aC.auditService.FetchAuditLogs("namespace/"+namespace, 50)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch audit logs"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"namespace": namespace, "level": level, "history": history})
+}
+
+type SetPodSecurityParams struct {
+	Level string `json:"level" binding:"required"`
+}
+
+// SetPodSecurity는 네임스페이스의 Pod Security 레벨을 변경합니다. (감사 로그 기록)
+// POST /api/admin/namespaces/:namespace/pod-security {"level": "privileged|baseline|restricted"}
+func (aC *AdminController) SetPodSecurity(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	var req SetPodSecurityParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	actorId, err := cast.ToUintE(user_id)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid user_id"))
+		return
+	}
+
+	namespace := c.Param("namespace")
+	if !k8s_service.IsValidPodSecurityLevel(req.Level) {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "level must be one of privileged, baseline, restricted"))
+		return
+	}
+
+	if err := aC.k8sService.WithContext(c.Request.Context()).SetPodSecurityLevel(namespace, req.Level, actorId); err != nil {
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeInternal))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"namespace": namespace, "level": req.Level})
+}
+
+// UserStorageStats는 관리자용 사용자별 스토리지 통계입니다.
+type UserStorageStats struct {
+	UserID        uint                         `json:"user_id"`
+	StudentID     string                       `json:"student_id"`
+	Namespace     string                       `json:"namespace"`
+	Quota         *quotaservice.StorageSummary `json:"quota"`
+	CapacityBytes int64                        `json:"capacity_bytes"`
+	UsedBytes     int64                        `json:"used_bytes"`
+	PVCs          []k8s_service.PVCUsage       `json:"pvcs"`
+}
+
+// FetchStorageStats는 전체 사용자의 쿼터 점유량과 클러스터 PVC 용량/사용량을 반환합니다.
+// GET /api/admin/storage
+func (aC *AdminController) FetchStorageStats(c *gin.Context) {
+	users, err := aC.userService.FetchAllUsers()
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch users"))
+		return
+	}
+
+	storage, err := aC.k8sService.WithContext(c.Request.Context()).ListStorageUsage()
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("failed to list storage usage", "error", err)
+		storage = map[string]*k8s_service.NamespaceStorage{}
+	}
+
+	stats := make([]UserStorageStats, 0, len(users))
+	var totalCapacity, totalUsed int64
+	for _, user := range users {
+		row := UserStorageStats{
+			UserID:    user.ID,
+			StudentID: user.UserStudentId,
+			Namespace: user.Namespace,
+			PVCs:      []k8s_service.PVCUsage{},
+		}
+
+		if summary, err := aC.quotaService.StorageSummary(user.ID); err == nil {
+			row.Quota = summary
+		}
+		if usage, ok := storage[user.Namespace]; ok {
+			row.CapacityBytes = usage.CapacityBytes
+			row.UsedBytes = usage.UsedBytes
+			row.PVCs = usage.PVCs
+		}
+
+		totalCapacity += row.CapacityBytes
+		totalUsed += row.UsedBytes
+		stats = append(stats, row)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users":                stats,
+		"total_capacity_bytes": totalCapacity,
+		"total_used_bytes":     totalUsed,
+	})
+}
+
+// FetchTenant는 메트릭/로그의 테넌트 라벨(해시)에 해당하는 사용자를 찾습니다. (남용 조사용)
+// 라벨은 단방향 해시이므로 전체 사용자의 라벨을 계산해 비교하며, 조회 자체를 감사 로그에 남깁니다.
+// GET /api/admin/tenants/:tenant
+func (aC *AdminController) FetchTenant(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	actorId, err := cast.ToUintE(user_id)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid user_id"))
+		return
+	}
+
+	tenant := c.Param("tenant")
+
+	users, err := aC.userService.FetchAllUsers()
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch users"))
+		return
+	}
+
+	if err := aC.auditService.Record(&actorId, "tenant.lookup", "tenant/"+tenant, ""); err != nil {
+		logger.FromContext(c.Request.Context()).Warn("failed to record audit log", "tenant", tenant, "error", err)
+	}
+
+	for _, user := range users {
+		if metrics.TenantHash(fmt.Sprintf("%d", user.ID)) == tenant {
+			c.JSON(http.StatusOK, gin.H{
+				"tenant":     tenant,
+				"user_id":    user.ID,
+				"student_id": user.UserStudentId,
+				"namespace":  user.Namespace,
+			})
+			return
+		}
+	}
+
+	middleware.AbortWithError(c, apperrors.New(apperrors.CodeNotFound, "Tenant not found"))
+}
+
+// FetchPermissions는 컨트롤러가 수행하는 모든 작업의 RBAC 권한을 SelfSubjectAccessReview로 확인하고,
+// 누락된 권한을 따로 모아 반환합니다. (개발/운영 클러스터 간 RBAC 차이 점검용)
+// GET /api/admin/permissions
+func (aC *AdminController) FetchPermissions(c *gin.Context) {
+	checks := aC.k8sService.CheckPermissions(c.Request.Context(), config.Get().OperatorMode)
+
+	missing := []k8s_service.PermissionCheck{}
+	for _, check := range checks {
+		if !check.Allowed {
+			missing = append(missing, check)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"ok":      len(missing) == 0,
+		"missing": missing,
+		"checks":  checks,
+	})
+}
+
+// FetchBundleReport는 최근 days일 동안 VM 생성 시도를 매니페스트 번들(stable/canary)별로 집계해
+// 실패율을 비교합니다. canary 번들을 전체로 확대하기 전에 확인하는 용도입니다.
+// GET /api/admin/bundles/report?days=7
+func (aC *AdminController) FetchBundleReport(c *gin.Context) {
+	days := cast.ToInt(c.DefaultQuery("days", "7"))
+	if days <= 0 {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "days must be positive"))
+		return
+	}
+
+	bundleService := aC.bundleService
+	since := time.Now().AddDate(0, 0, -days)
+
+	reports, err := bundleService.Report(since)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to build bundle report"))
+		return
+	}
+
+	var canary gin.H
+	if root := bundleService.CanaryRoot(); root != "" {
+		canary = gin.H{
+			"root":    root,
+			"bundle":  version.Bundle(root),
+			"percent": bundleService.CanaryPercent(),
+			"users":   len(bundleService.CanaryUsers()),
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"since":   since,
+		"stable":  version.Bundle(version.ManifestBundleDir),
+		"canary":  canary,
+		"reports": reports,
+	})
+}
+
+// FetchUsers는 전체 사용자 목록(비밀번호 해시 제외)을 반환합니다.
+// GET /api/admin/users
+func (aC *AdminController) FetchUsers(c *gin.Context) {
+	users, err := aC.userService.FetchAllUsers()
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch users"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"users": users})
+}
+
+type SetUserRoleParams struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// SetUserRole은 사용자 권한(user / admin / auditor)을 변경합니다. (감사 로그 기록)
+// 관리자가 실수로 자신의 권한을 잃지 않도록 자기 자신은 변경할 수 없습니다.
+// POST /api/admin/users/:id/role {"role": "user|admin|auditor"}
+func (aC *AdminController) SetUserRole(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	var req SetUserRoleParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	actorId, err := cast.ToUintE(user_id)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid user_id"))
+		return
+	}
+
+	if !slices.Contains(models.Roles, req.Role) {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "role must be one of user, admin, auditor"))
+		return
+	}
+
+	targetId, err := cast.ToUintE(c.Param("id"))
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid user id"))
+		return
+	}
+	if targetId == actorId {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "자신의 권한은 변경할 수 없습니다."))
+		return
+	}
+
+	userService := aC.userService
+	target, err := userService.FetchUserById(c.Param("id"), true)
+	if err != nil || target == nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeUserNotFound, "User not found"))
+		return
+	}
+
+	if err := userService.UpdateUserRole(targetId, req.Role); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to update role"))
+		return
+	}
+
+	detail := target.Role + " -> " + req.Role
+	if err := aC.auditService.Record(&actorId, "user.role.update", fmt.Sprintf("user/%d", targetId), detail); err != nil {
+		logger.FromContext(c.Request.Context()).Warn("failed to record audit log", "user_id", targetId, "error", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user_id": targetId, "role": req.Role})
+}
+
+type SetUserVMLeaseParams struct {
+	Days *int `json:"days"` // null이면 기본 정책(VM_LEASE_DAYS), 0이면 만료 없음
+}
+
+// SetUserVMLease는 사용자별 VM 사용 기간(일)을 설정합니다. (감사 로그 기록)
+// 학기 단위로 운영할 때 조교/연구실 계정 등 예외 사용자에게 다른 기간을 적용합니다.
+// POST /api/admin/users/:id/vm-lease {"days": 120}
+func (aC *AdminController) SetUserVMLease(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	var req SetUserVMLeaseParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+	if req.Days != nil && *req.Days < 0 {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "days must not be negative"))
+		return
+	}
+
+	targetId, err := cast.ToUintE(c.Param("id"))
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid user id"))
+		return
+	}
+
+	if err := aC.userService.UpdateVmLeaseDays(targetId, req.Days); err != nil {
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeNotFound))
+		return
+	}
+
+	if actorId, err := cast.ToUintE(user_id); err == nil {
+		detail := "default"
+		if req.Days != nil {
+			detail = fmt.Sprintf("%d days", *req.Days)
+		}
+		if err := aC.auditService.Record(&actorId, "user.vm_lease.update", fmt.Sprintf("user/%d", targetId), detail); err != nil {
+			logger.FromContext(c.Request.Context()).Warn("failed to record audit log", "user_id", targetId, "error", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user_id": targetId, "vm_lease_days": req.Days})
+}
+
+// auditLogLimit는 limit 쿼리를 1~1000 범위로 읽습니다. (기본 100)
+func auditLogLimit(c *gin.Context) int {
+	limit := cast.ToInt(c.DefaultQuery("limit", "100"))
+	if limit <= 0 {
+		return 100
+	}
+	if limit > 1000 {
+		return 1000
+	}
+	return limit
+}
+
+// FetchAuditLogs는 감사 로그를 최신순으로 반환합니다. target을 지정하면 해당 대상만 반환합니다.
+// GET /api/admin/audit-logs?target=vm/name&limit=100
+func (aC *AdminController) FetchAuditLogs(c *gin.Context) {
+	logs, err := aC.auditService.FetchAuditLogs(c.Query("target"), auditLogLimit(c))
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch audit logs"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"audit_logs": logs})
+}
+
+// FetchSecurityEvents는 인터셉터가 차단한 요청 등 보안 이벤트(security.*)를 최신순으로 반환합니다.
+// GET /api/admin/security-events?limit=100
+func (aC *AdminController) FetchSecurityEvents(c *gin.Context) {
+	events, err := aC.auditService.FetchAuditLogsByAction("security.", auditLogLimit(c))
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch security events"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"security_events": events})
+}
+
+// FetchApprovals는 VM 생성 승인 요청 목록을 반환합니다. (기본: 대기 중인 요청)
+// GET /api/admin/approvals?status=PendingApproval|Approved|Rejected|Failed|all
+func (aC *AdminController) FetchApprovals(c *gin.Context) {
+	status := c.DefaultQuery("status", string(models.ApprovalStatusPending))
+	if status == "all" {
+		status = ""
+	}
+
+	approvals, err := aC.approvalService.FetchApprovals(status)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch approvals"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"approvals": approvals})
+}
+
+// fetchPendingApproval은 :id 승인 요청을 조회하고 대기 상태인지 확인합니다. 응답을 작성한 경우 false를 반환합니다.
+func (aC *AdminController) fetchPendingApproval(c *gin.Context) (*models.VmApproval, uint, bool) {
+	user_id, _ := c.Get("user_id")
+
+	actorId, err := cast.ToUintE(user_id)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid user_id"))
+		return nil, 0, false
+	}
+
+	id, err := cast.ToUintE(c.Param("id"))
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid approval id"))
+		return nil, 0, false
+	}
+
+	approval, err := aC.approvalService.FetchApproval(id)
+	if err != nil {
+		if errors.Is(err, approvalservice.ErrApprovalNotFound) {
+			middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeNotFound))
+			return nil, 0, false
+		}
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch approval"))
+		return nil, 0, false
+	}
+
+	if approval.Status != models.ApprovalStatusPending {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidState, "현재 상태("+string(approval.Status)+")에서는 요청을 처리할 수 없습니다."))
+		return nil, 0, false
+	}
+
+	return approval, actorId, true
+}
+
+// ApproveVM은 대기 중인 요청을 승인하고 저장된 요청으로 VM을 생성합니다. (감사 로그 기록, 요청자에게 알림)
+// 쿼터/이름 중복 등은 승인 시점에 다시 확인하며, 생성에 실패하면 요청은 Failed가 되어 다시 요청해야 합니다.
+// POST /api/admin/approvals/:id/approve
+func (aC *AdminController) ApproveVM(c *gin.Context) {
+	approval, actorId, ok := aC.fetchPendingApproval(c)
+	if !ok {
+		return
+	}
+
+	req, err := vmlifecycleservice.ApprovalParams(approval)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("failed to read approval request", "approval_id", approval.ID, "error", err)
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to read approval request"))
+		return
+	}
+
+	approvalService := aC.approvalService
+	if err := approvalService.Review(approval.ID, models.ApprovalStatusApproved, actorId, ""); err != nil {
+		if errors.Is(err, approvalservice.ErrApprovalNotPending) {
+			middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeConflict))
+			return
+		}
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to approve request"))
+		return
+	}
+
+	target := fmt.Sprintf("approval/%d", approval.ID)
+
+	// 생성 실패 시 createVM이 작성한 에러 응답을 그대로 관리자에게 반환
+	response, code, ok := aC.vmController.createVM(c, &approval.User, req, approval.Template)
+	if !ok {
+		reason := "승인 후 VM 생성에 실패했습니다."
+		if err := approvalService.MarkFailed(approval.ID, reason); err != nil {
+			logger.FromContext(c.Request.Context()).Error("failed to mark approval as Failed", "approval_id", approval.ID, "error", err)
+		}
+		if err := aC.auditService.Record(&actorId, "vm.approval.approve", target, "create failed"); err != nil {
+			logger.FromContext(c.Request.Context()).Warn("failed to record audit log", "approval_id", approval.ID, "error", err)
+		}
+		approvalService.NotifyRequester(approval, "approval.create_failed", notificationservice.Data{"vm": approval.VmName})
+		return
+	}
+
+	if err := aC.auditService.Record(&actorId, "vm.approval.approve", target, approval.VmName); err != nil {
+		logger.FromContext(c.Request.Context()).Warn("failed to record audit log", "approval_id", approval.ID, "error", err)
+	}
+	approvalService.NotifyRequester(approval, "approval.approved", notificationservice.Data{"vm": approval.VmName})
+
+	// Operator 모드에서는 UserVM으로 접수만 되므로 202
+	c.JSON(code, gin.H{"approval_id": approval.ID, "status": models.ApprovalStatusApproved, "vm": response["vm"]})
+}
+
+type RejectVMParams struct {
+	Reason string `json:"reason"` // 요청자에게 전달되는 거절 사유
+}
+
+// RejectVM은 대기 중인 요청을 거절하고 요청자에게 사유를 알립니다. (감사 로그 기록)
+// POST /api/admin/approvals/:id/reject {"reason": "..."}
+func (aC *AdminController) RejectVM(c *gin.Context) {
+	var req RejectVMParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	approval, actorId, ok := aC.fetchPendingApproval(c)
+	if !ok {
+		return
+	}
+
+	approvalService := aC.approvalService
+	if err := approvalService.Review(approval.ID, models.ApprovalStatusRejected, actorId, req.Reason); err != nil {
+		if errors.Is(err, approvalservice.ErrApprovalNotPending) {
+			middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeConflict))
+			return
+		}
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to reject request"))
+		return
+	}
+
+	if err := aC.auditService.Record(&actorId, "vm.approval.reject", fmt.Sprintf("approval/%d", approval.ID), req.Reason); err != nil {
+		logger.FromContext(c.Request.Context()).Warn("failed to record audit log", "approval_id", approval.ID, "error", err)
+	}
+
+	approvalService.NotifyRequester(approval, "approval.rejected", notificationservice.Data{"vm": approval.VmName, "reason": req.Reason})
+
+	c.JSON(http.StatusOK, gin.H{"approval_id": approval.ID, "status": models.ApprovalStatusRejected})
+}
+
+// FetchFlavors는 요금제 목록을 반환합니다.
+// GET /api/admin/flavors
+func (aC *AdminController) FetchFlavors(c *gin.Context) {
+	flavors, err := k8s_service.ListFlavors(aC.flavorService)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch flavors"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"flavors": flavors})
+}
+
+type FlavorParams struct {
+	Name         string `json:"name"` // 생성 시에만 사용 (수정 시 경로의 이름 사용)
+	Cores        int    `json:"cores" binding:"required"`
+	CPURequest   string `json:"cpu_request" binding:"required"`
+	CPULimit     string `json:"cpu_limit" binding:"required"`
+	Memory       string `json:"memory" binding:"required"`
+	DiskGi       int    `json:"disk_gi" binding:"required"`
+	DedicatedCPU bool   `json:"dedicated_cpu"`
+	HourlyPrice  int    `json:"hourly_price"`
+	MonthlyPrice int    `json:"monthly_price"`
+}
+
+func (req FlavorParams) flavor(name string) k8s_service.Flavor {
+	return k8s_service.Flavor{
+		Name:         name,
+		Cores:        req.Cores,
+		CPURequest:   req.CPURequest,
+		CPULimit:     req.CPULimit,
+		Memory:       req.Memory,
+		DiskGi:       req.DiskGi,
+		DedicatedCPU: req.DedicatedCPU,
+		HourlyPrice:  req.HourlyPrice,
+		MonthlyPrice: req.MonthlyPrice,
+	}
+}
+
+func flavorAuditDetail(flavor k8s_service.Flavor) string {
+	return fmt.Sprintf("cores=%d cpu=%s/%s memory=%s disk=%dGi dedicated=%t price=%d/%d",
+		flavor.Cores, flavor.CPURequest, flavor.CPULimit, flavor.Memory, flavor.DiskGi, flavor.DedicatedCPU, flavor.HourlyPrice, flavor.MonthlyPrice)
+}
+
+// CreateFlavor는 요금제를 등록합니다.
+// POST /api/admin/flavors
+func (aC *AdminController) CreateFlavor(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	var req FlavorParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	flavor := req.flavor(req.Name)
+	if err := flavor.Validate(); err != nil {
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeInvalidRequest))
+		return
+	}
+
+	if err := aC.flavorService.CreateFlavor(flavor.Model()); err != nil {
+		if errors.Is(err, flavorservice.ErrFlavorExists) {
+			middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeConflict))
+			return
+		}
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to create flavor"))
+		return
+	}
+
+	if actorId, err := cast.ToUintE(user_id); err == nil {
+		if err := aC.auditService.Record(&actorId, "flavor.create", "flavor/"+flavor.Name, flavorAuditDetail(flavor)); err != nil {
+			logger.FromContext(c.Request.Context()).Warn("failed to record audit log", "flavor", flavor.Name, "error", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"flavor": flavor})
+}
+
+// UpdateFlavor는 요금제의 자원/가격을 변경합니다.
+// 이미 생성된 VM의 CPU/메모리는 재생성할 때 반영되며, 디스크 크기는 생성 당시 값을 유지합니다.
+// PUT /api/admin/flavors/:name
+func (aC *AdminController) UpdateFlavor(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	var req FlavorParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	flavor := req.flavor(c.Param("name"))
+	if err := flavor.Validate(); err != nil {
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeInvalidRequest))
+		return
+	}
+
+	if err := aC.flavorService.UpdateFlavor(flavor.Name, flavor.Model()); err != nil {
+		if errors.Is(err, flavorservice.ErrFlavorNotFound) {
+			middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeNotFound))
+			return
+		}
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to update flavor"))
+		return
+	}
+
+	if actorId, err := cast.ToUintE(user_id); err == nil {
+		if err := aC.auditService.Record(&actorId, "flavor.update", "flavor/"+flavor.Name, flavorAuditDetail(flavor)); err != nil {
+			logger.FromContext(c.Request.Context()).Warn("failed to record audit log", "flavor", flavor.Name, "error", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"flavor": flavor})
+}
+
+// DeleteFlavor는 요금제를 삭제합니다. 삭제되지 않은 VM이 사용 중이면 409를 반환합니다.
+// DELETE /api/admin/flavors/:name
+func (aC *AdminController) DeleteFlavor(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	name := c.Param("name")
+	if name == k8s_service.DefaultFlavor {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "The default flavor cannot be deleted"))
+		return
+	}
+
+	if err := aC.flavorService.DeleteFlavor(name); err != nil {
+		switch {
+		case errors.Is(err, flavorservice.ErrFlavorNotFound):
+			middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeNotFound))
+		case errors.Is(err, flavorservice.ErrFlavorInUse):
+			middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeConflict))
+		default:
+			middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to delete flavor"))
+		}
+		return
+	}
+
+	if actorId, err := cast.ToUintE(user_id); err == nil {
+		if err := aC.auditService.Record(&actorId, "flavor.delete", "flavor/"+name, ""); err != nil {
+			logger.FromContext(c.Request.Context()).Warn("failed to record audit log", "flavor", name, "error", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Flavor deleted"})
+}
+
+// FetchImages는 이미지 카탈로그를 반환합니다.
+// GET /api/admin/images
+func (aC *AdminController) FetchImages(c *gin.Context) {
+	images, err := k8s_service.ListImages(aC.imageService)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch images"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"images": images})
+}
+
+type ImageParams struct {
+	Name            string   `json:"name"` // 생성 시에만 사용 (수정 시 경로의 이름 사용)
+	DisplayName     string   `json:"display_name"`
+	SourcePVC       string   `json:"source_pvc" binding:"required"`
+	SourceNamespace string   `json:"source_namespace" binding:"required"`
+	SSHUser         string   `json:"ssh_user" binding:"required"`
+	AuthMethods     []string `json:"auth_methods" binding:"required"` // password, publickey
+}
+
+func (req ImageParams) image(name string) k8s_service.Image {
+	return k8s_service.Image{
+		Name:            name,
+		DisplayName:     req.DisplayName,
+		SourcePVC:       req.SourcePVC,
+		SourceNamespace: req.SourceNamespace,
+		SSHUser:         req.SSHUser,
+		AuthMethods:     req.AuthMethods,
+	}
+}
+
+func imageAuditDetail(image k8s_service.Image) string {
+	return fmt.Sprintf("source=%s/%s ssh_user=%s auth=%s",
+		image.SourceNamespace, image.SourcePVC, image.SSHUser, strings.Join(image.AuthMethods, ","))
+}
+
+// CreateImage는 이미지를 카탈로그에 등록합니다.
+// POST /api/admin/images
+func (aC *AdminController) CreateImage(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	var req ImageParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	image := req.image(req.Name)
+	if err := image.Validate(); err != nil {
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeInvalidRequest))
+		return
+	}
+
+	if err := aC.imageService.CreateImage(image.Model()); err != nil {
+		if errors.Is(err, imageservice.ErrImageExists) {
+			middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeConflict))
+			return
+		}
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to create image"))
+		return
+	}
+
+	if actorId, err := cast.ToUintE(user_id); err == nil {
+		if err := aC.auditService.Record(&actorId, "image.create", "image/"+image.Name, imageAuditDetail(image)); err != nil {
+			logger.FromContext(c.Request.Context()).Warn("failed to record audit log", "image", image.Name, "error", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"image": image})
+}
+
+// UpdateImage는 이미지의 원본 디스크/기본 사용자/인증 방식을 변경합니다.
+// 이후 생성하는 VM부터 적용되며, 기존 VM의 SSH 사용자는 생성 당시 값을 유지합니다.
+// PUT /api/admin/images/:name
+func (aC *AdminController) UpdateImage(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	var req ImageParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	image := req.image(c.Param("name"))
+	if err := image.Validate(); err != nil {
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeInvalidRequest))
+		return
+	}
+
+	if err := aC.imageService.UpdateImage(image.Name, image.Model()); err != nil {
+		if errors.Is(err, imageservice.ErrImageNotFound) {
+			middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeNotFound))
+			return
+		}
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to update image"))
+		return
+	}
+
+	if actorId, err := cast.ToUintE(user_id); err == nil {
+		if err := aC.auditService.Record(&actorId, "image.update", "image/"+image.Name, imageAuditDetail(image)); err != nil {
+			logger.FromContext(c.Request.Context()).Warn("failed to record audit log", "image", image.Name, "error", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"image": image})
+}
+
+// DeleteImage는 이미지를 카탈로그에서 삭제합니다. 삭제되지 않은 VM이 사용 중이면 409를 반환합니다.
+// DELETE /api/admin/images/:name
+func (aC *AdminController) DeleteImage(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	name := c.Param("name")
+	if name == k8s_service.DefaultImage {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "The default image cannot be deleted"))
+		return
+	}
+
+	if err := aC.imageService.DeleteImage(name); err != nil {
+		switch {
+		case errors.Is(err, imageservice.ErrImageNotFound):
+			middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeNotFound))
+		case errors.Is(err, imageservice.ErrImageInUse):
+			middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeConflict))
+		default:
+			middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to delete image"))
+		}
+		return
+	}
+
+	if actorId, err := cast.ToUintE(user_id); err == nil {
+		if err := aC.auditService.Record(&actorId, "image.delete", "image/"+name, ""); err != nil {
+			logger.FromContext(c.Request.Context()).Warn("failed to record audit log", "image", name, "error", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Image deleted"})
+}
+
+// FetchOperation은 모든 사용자와 시스템(converger 등) 작업의 진행 상태를 반환합니다.
+// GET /api/admin/operations/:id
+func (aC *AdminController) FetchOperation(c *gin.Context) {
+	operation := fetchOperation(c, aC.operationService)
+	if operation == nil {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"operation": newOperationResponse(operation), "user_id": operation.UserID, "trace_id": operation.TraceID})
+}
+
+// FetchFeatures는 기능 플래그와 현재 상태를 반환합니다.
+// GET /api/admin/features
+func (aC *AdminController) FetchFeatures(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"features": config.Features()})
+}
+
+type UpdateFeatureParams struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// UpdateFeature는 재시작 없이 기능 플래그를 켜거나 끕니다. (재시작하면 FEATURE_FLAGS 값으로 돌아감)
+// PUT /api/admin/features/:name
+func (aC *AdminController) UpdateFeature(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	var req UpdateFeatureParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	name := c.Param("name")
+	if err := config.SetFeature(name, *req.Enabled); err != nil {
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeNotFound))
+		return
+	}
+
+	if actorId, err := cast.ToUintE(user_id); err == nil {
+		if err := aC.auditService.Record(&actorId, "feature.update", "feature/"+name, fmt.Sprintf("enabled=%t", *req.Enabled)); err != nil {
+			logger.FromContext(c.Request.Context()).Warn("failed to record audit log", "feature", name, "error", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"features": config.Features()})
+}
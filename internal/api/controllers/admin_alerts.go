@@ -0,0 +1,41 @@
+package controllers
+
+import (
+	http "net/http"
+	"strings"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/middleware"
+	k8s_service "vm-controller/internal/services/k8s_service"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+// FetchAlertRules는 현재 설정을 반영한 플랫폼 SLO 알림 규칙(생성 실패율, 작업 대기 시간, 포트 풀 고갈 등)을 반환합니다.
+// 기본은 prometheus-operator PrometheusRule 리소스이며, format=rules면 Prometheus rule_files 형식입니다.
+// Accept: application/yaml이면 kubectl apply나 규칙 파일에 바로 쓸 수 있는 YAML로 응답합니다.
+// job은 모든 메트릭 선택자에 추가할 job 라벨, namespace와 label(key=value, 여러 개 가능)은 PrometheusRule 메타데이터입니다.
+// GET /api/admin/alerts/rules?format=prometheusrule|rules&job=&namespace=&label=
+func (aC *AdminController) FetchAlertRules(c *gin.Context) {
+	opts := k8s_service.AlertRuleOptions{
+		Job:       c.Query("job"),
+		Namespace: c.Query("namespace"),
+		Labels:    map[string]string{},
+	}
+	for _, label := range c.QueryArray("label") {
+		key, value, ok := strings.Cut(label, "=")
+		if !ok || key == "" {
+			middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "label must be in key=value format"))
+			return
+		}
+		opts.Labels[key] = value
+	}
+
+	switch c.DefaultQuery("format", "prometheusrule") {
+	case "prometheusrule":
+		respondNegotiated(c, http.StatusOK, k8s_service.PrometheusRuleFor(opts))
+	case "rules":
+		respondNegotiated(c, http.StatusOK, k8s_service.AlertRuleFile{Groups: k8s_service.AlertRuleGroups(opts)})
+	default:
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "format must be prometheusrule or rules"))
+	}
+}
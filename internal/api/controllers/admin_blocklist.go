@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"fmt"
+	"net"
+	http "net/http"
+	"time"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/logger"
+	"vm-controller/internal/middleware"
+
+	gin "github.com/gin-gonic/gin"
+	cast "github.com/spf13/cast"
+)
+
+// FetchBans는 인터셉터가 일시 차단 중인 IP 목록을 반환합니다. (REDIS_URL 설정 시 모든 인터셉터가 공유하는 목록)
+// GET /api/admin/security/bans
+func (aC *AdminController) FetchBans(c *gin.Context) {
+	bans, err := aC.blocklistService.Bans(c.Request.Context())
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch bans"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"bans": bans})
+}
+
+type BanIPParams struct {
+	IP       string `json:"ip"`
+	Reason   string `json:"reason"`
+	Duration string `json:"duration"` // 예: 1h (비우면 INTERCEPT_BAN_DURATION)
+}
+
+// BanIP는 IP를 수동으로 일시 차단합니다. (감사 로그 기록)
+// POST /api/admin/security/bans {"ip": "203.0.113.7", "reason": "...", "duration": "1h"}
+func (aC *AdminController) BanIP(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	var req BanIPParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+	if net.ParseIP(req.IP) == nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "ip must be a valid IP address"))
+		return
+	}
+	var duration time.Duration
+	if req.Duration != "" {
+		parsed, err := time.ParseDuration(req.Duration)
+		if err != nil || parsed <= 0 {
+			middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "duration must be a positive duration (e.g., 30m, 2h)"))
+			return
+		}
+		duration = parsed
+	}
+	if req.Reason == "" {
+		req.Reason = "Banned by administrator"
+	}
+
+	ban, err := aC.blocklistService.Ban(c.Request.Context(), req.IP, req.Reason, duration)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to ban ip"))
+		return
+	}
+
+	var actorId *uint
+	if id, err := cast.ToUintE(user_id); err == nil {
+		actorId = &id
+	}
+	if err := aC.auditService.Record(actorId, "security.ban", "ip/"+ban.IP, fmt.Sprintf("%s until %s", ban.Reason, ban.Until.Format(time.RFC3339))); err != nil {
+		logger.FromContext(c.Request.Context()).Warn("failed to record audit log", "ip", ban.IP, "error", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"ban": ban})
+}
+
+// UnbanIP는 IP의 일시 차단을 해제합니다. 다른 인터셉터에는 로컬 캐시가 만료된 뒤(BLOCKLIST_CACHE_TTL) 반영됩니다. (감사 로그 기록)
+// DELETE /api/admin/security/bans/:ip
+func (aC *AdminController) UnbanIP(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	ip := c.Param("ip")
+	if err := aC.blocklistService.Unban(c.Request.Context(), ip); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeUnavailable, "Failed to unban ip"))
+		return
+	}
+
+	var actorId *uint
+	if id, err := cast.ToUintE(user_id); err == nil {
+		actorId = &id
+	}
+	if err := aC.auditService.Record(actorId, "security.unban", "ip/"+ip, ""); err != nil {
+		logger.FromContext(c.Request.Context()).Warn("failed to record audit log", "ip", ip, "error", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"ip": ip, "banned": false})
+}
+
+// FetchOffenders는 최근(INTERCEPT_OFFENDER_RETENTION) 보안 검사에 걸린 IP를 마지막으로 본 순서로 반환합니다.
+// GET /api/admin/security/offenders?limit=100
+func (aC *AdminController) FetchOffenders(c *gin.Context) {
+	offenders, err := aC.blocklistService.RecentOffenders(c.Request.Context(), auditLogLimit(c))
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch offenders"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"offenders": offenders})
+}
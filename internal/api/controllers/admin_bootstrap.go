@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"fmt"
+	http "net/http"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/logger"
+	"vm-controller/internal/middleware"
+	k8s_service "vm-controller/internal/services/k8s_service"
+
+	gin "github.com/gin-gonic/gin"
+	cast "github.com/spf13/cast"
+)
+
+// FetchBootstrap은 서버 시작 시(또는 마지막 재실행) 플랫폼 공용 리소스 부트스트랩 결과를 반환합니다.
+// GET /api/admin/bootstrap
+func (aC *AdminController) FetchBootstrap(c *gin.Context) {
+	results := k8s_service.LastBootstrap()
+	if results == nil {
+		results = []k8s_service.BootstrapResult{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"resources": results})
+}
+
+// RunBootstrap은 부트스트랩을 다시 실행합니다. (CRD를 나중에 설치했거나 설정 값을 채운 경우, 감사 로그 기록)
+// 일부 리소스가 실패해도 리소스별 결과를 함께 반환합니다.
+// POST /api/admin/bootstrap
+func (aC *AdminController) RunBootstrap(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	results, err := aC.k8sService.WithContext(c.Request.Context()).Bootstrap()
+	if results == nil {
+		middleware.AbortWithError(c, apperrors.Newf(apperrors.CodeInternal, "Failed to run bootstrap: %v", err))
+		return
+	}
+
+	changed := 0
+	for _, result := range results {
+		if result.Action == k8s_service.BootstrapCreated || result.Action == k8s_service.BootstrapUpdated {
+			changed++
+		}
+	}
+	if actorId, errCast := cast.ToUintE(user_id); errCast == nil {
+		if errAudit := aC.auditService.Record(&actorId, "platform.bootstrap", "bootstrap", fmt.Sprintf("%d changed", changed)); errAudit != nil {
+			logger.FromContext(c.Request.Context()).Warn("failed to record audit log for bootstrap", "error", errAudit)
+		}
+	}
+
+	response := gin.H{"resources": results, "changed": changed}
+	if err != nil {
+		response["error"] = err.Error()
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	http "net/http"
+	"strings"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/logger"
+	"vm-controller/internal/middleware"
+	k8s_service "vm-controller/internal/services/k8s_service"
+
+	gin "github.com/gin-gonic/gin"
+	cast "github.com/spf13/cast"
+)
+
+// FetchDrift는 DB와 클러스터 사이의 불일치 목록을 반환합니다. kind로 종류를 거를 수 있습니다.
+// (missing_instance: DB는 Running인데 VMI 없음, orphan_instance: DB 레코드 없는 VMI, port_mismatch: SSH NodePort 불일치)
+// GET /api/admin/drift?kind=missing_instance,port_mismatch
+func (aC *AdminController) FetchDrift(c *gin.Context) {
+	kinds, err := k8s_service.ParseDriftKinds(c.Query("kind"))
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeInvalidRequest))
+		return
+	}
+
+	drifts, err := aC.k8sService.WithContext(c.Request.Context()).DetectDrift()
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to compare database with cluster"))
+		return
+	}
+
+	filtered := []k8s_service.Drift{}
+	for _, drift := range drifts {
+		if kinds == nil || kinds[drift.Kind] {
+			filtered = append(filtered, drift)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"drifts": filtered, "count": len(filtered)})
+}
+
+type FixDriftParams struct {
+	Kinds []string `json:"kinds" binding:"required"` // 자동 수정할 불일치 종류
+	Names []string `json:"names"`                    // 지정하면 이 VM 이름만 수정
+}
+
+// FixDrift는 불일치를 다시 확인한 뒤 지정한 종류를 자동으로 수정합니다. (감사 로그 기록)
+// 자동 수정 방법이 없는 항목(이 컨트롤러가 만들지 않은 VMI 등)은 에러로 보고하고 건너뜁니다.
+// POST /api/admin/drift/fix {"kinds": ["port_mismatch"], "names": ["my-vm"]}
+func (aC *AdminController) FixDrift(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	actorId, err := cast.ToUintE(user_id)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid user_id"))
+		return
+	}
+
+	var req FixDriftParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	kinds, err := k8s_service.ParseDriftKinds(strings.Join(req.Kinds, ","))
+	if err != nil || kinds == nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "kinds must list at least one known drift kind"))
+		return
+	}
+	names := map[string]bool{}
+	for _, name := range req.Names {
+		names[name] = true
+	}
+
+	k8sService := aC.k8sService.WithContext(c.Request.Context())
+	drifts, err := k8sService.DetectDrift()
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to compare database with cluster"))
+		return
+	}
+
+	results := []k8s_service.DriftFixResult{}
+	for _, drift := range drifts {
+		if !kinds[drift.Kind] || (len(names) > 0 && !names[drift.Name]) {
+			continue
+		}
+
+		result := k8s_service.DriftFixResult{Drift: drift}
+		result.OperationID, err = k8sService.FixDrift(drift, c.GetString("trace_id"))
+		if err != nil {
+			result.Error = err.Error()
+		}
+		if err := aC.auditService.Record(&actorId, "vm.drift.fix", drift.Namespace+"/"+drift.Name, string(drift.Kind)+" "+drift.Fix); err != nil {
+			logger.FromContext(c.Request.Context()).Warn("failed to record audit log", "namespace", drift.Namespace, "drift", drift.Name, "error", err)
+		}
+		results = append(results, result)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": results, "count": len(results)})
+}
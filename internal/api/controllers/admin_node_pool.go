@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"errors"
+	http "net/http"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/logger"
+	"vm-controller/internal/middleware"
+	nodepoolservice "vm-controller/internal/services/nodepool_service"
+
+	gin "github.com/gin-gonic/gin"
+	cast "github.com/spf13/cast"
+)
+
+// FetchNodePools는 노드 풀별 노드/VM 수와 유지보수 상태를 반환합니다.
+// GET /api/admin/node-pools
+func (aC *AdminController) FetchNodePools(c *gin.Context) {
+	pools, err := aC.k8sService.WithContext(c.Request.Context()).ListNodePools()
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch node pools"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"node_pools": pools})
+}
+
+type SetNodePoolMaintenanceParams struct {
+	Reason string `json:"reason"`
+}
+
+// SetNodePoolMaintenance는 노드 풀에 신규 VM을 배치하지 않도록 표시합니다. (감사 로그 기록)
+// 풀에서 실행 중인 VM은 옮기지 않으며, 이후 생성/재생성되는 VM만 다른 풀로 스케줄링됩니다.
+// PUT /api/admin/node-pools/:pool/maintenance {"reason": "..."}
+func (aC *AdminController) SetNodePoolMaintenance(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	var req SetNodePoolMaintenanceParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	pool := c.Param("pool")
+	var actorId *uint
+	if id, err := cast.ToUintE(user_id); err == nil {
+		actorId = &id
+	}
+
+	maintenance, err := aC.nodePoolService.SetMaintenance(pool, req.Reason, actorId)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to set node pool maintenance"))
+		return
+	}
+	if err := aC.auditService.Record(actorId, "node_pool.maintenance", "node-pool/"+pool, req.Reason); err != nil {
+		logger.FromContext(c.Request.Context()).Warn("failed to record audit log", "node_pool", pool, "error", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"pool": maintenance.Pool, "maintenance": true, "reason": maintenance.Reason})
+}
+
+// ClearNodePoolMaintenance는 노드 풀의 유지보수를 해제하여 다시 신규 VM을 배치합니다. (감사 로그 기록)
+// DELETE /api/admin/node-pools/:pool/maintenance
+func (aC *AdminController) ClearNodePoolMaintenance(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	pool := c.Param("pool")
+	if err := aC.nodePoolService.ClearMaintenance(pool); err != nil {
+		if errors.Is(err, nodepoolservice.ErrNotInMaintenance) {
+			middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeNotFound))
+			return
+		}
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to clear node pool maintenance"))
+		return
+	}
+
+	if actorId, err := cast.ToUintE(user_id); err == nil {
+		if err := aC.auditService.Record(&actorId, "node_pool.maintenance.clear", "node-pool/"+pool, ""); err != nil {
+			logger.FromContext(c.Request.Context()).Warn("failed to record audit log", "node_pool", pool, "error", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"pool": pool, "maintenance": false})
+}
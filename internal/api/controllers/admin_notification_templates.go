@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"errors"
+	http "net/http"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/logger"
+	"vm-controller/internal/middleware"
+	notificationservice "vm-controller/internal/services/notification_service"
+
+	gin "github.com/gin-gonic/gin"
+	cast "github.com/spf13/cast"
+)
+
+// FetchNotificationTemplates는 모든 알림 종류와 언어의 현재 문구, 기본 문구, 사용할 수 있는 값을 반환합니다.
+// GET /api/admin/notification-templates
+func (aC *AdminController) FetchNotificationTemplates(c *gin.Context) {
+	templates, err := aC.notificationService.ListTemplates()
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch notification templates"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"templates": templates, "locales": notificationservice.Locales, "default_locale": notificationservice.DefaultLocale()})
+}
+
+type UpdateNotificationTemplateParams struct {
+	Title   string `json:"title" binding:"required"`
+	Message string `json:"message" binding:"required"`
+}
+
+// UpdateNotificationTemplate은 재배포 없이 알림 문구를 수정합니다. 저장 전에 문법과 사용한 값을 검사합니다. (감사 로그 기록)
+// PUT /api/admin/notification-templates/:key/:locale {"title": "...", "message": "VM {{.vm}} ..."}
+func (aC *AdminController) UpdateNotificationTemplate(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	actorId, err := cast.ToUintE(user_id)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid user_id"))
+		return
+	}
+
+	var req UpdateNotificationTemplateParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	key, locale := c.Param("key"), c.Param("locale")
+	tmpl := notificationservice.Template{Title: req.Title, Message: req.Message}
+	if err := aC.notificationService.SetTemplate(key, locale, tmpl, actorId); err != nil {
+		switch {
+		case errors.Is(err, notificationservice.ErrUnknownTemplate), errors.Is(err, notificationservice.ErrUnknownLocale):
+			middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeNotFound))
+		case errors.Is(err, notificationservice.ErrInvalidTemplate):
+			middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeInvalidRequest))
+		default:
+			middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to update notification template"))
+		}
+		return
+	}
+
+	if err := aC.auditService.Record(&actorId, "notification_template.update", "notification-template/"+key+"/"+locale, req.Title); err != nil {
+		logger.FromContext(c.Request.Context()).Warn("failed to record audit log", "template", key, "locale", locale, "error", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"key": key, "locale": locale, "template": tmpl})
+}
+
+// ResetNotificationTemplate은 수정한 알림 문구를 지워 기본 문구로 되돌립니다. (감사 로그 기록)
+// DELETE /api/admin/notification-templates/:key/:locale
+func (aC *AdminController) ResetNotificationTemplate(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	key, locale := c.Param("key"), c.Param("locale")
+	if err := aC.notificationService.ResetTemplate(key, locale); err != nil {
+		if errors.Is(err, notificationservice.ErrTemplateNotFound) {
+			middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeNotFound))
+			return
+		}
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to reset notification template"))
+		return
+	}
+
+	if actorId, err := cast.ToUintE(user_id); err == nil {
+		if err := aC.auditService.Record(&actorId, "notification_template.reset", "notification-template/"+key+"/"+locale, ""); err != nil {
+			logger.FromContext(c.Request.Context()).Warn("failed to record audit log", "template", key, "locale", locale, "error", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification template reset"})
+}
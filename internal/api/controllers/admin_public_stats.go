@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	http "net/http"
+	"sort"
+	"strings"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/logger"
+	"vm-controller/internal/middleware"
+	statsservice "vm-controller/internal/services/stats_service"
+
+	gin "github.com/gin-gonic/gin"
+	cast "github.com/spf13/cast"
+)
+
+// FetchPublicStatSettings는 공개 통계 지표별 공개 여부와 현재 값을 반환합니다.
+// GET /api/admin/public-stats
+func (aC *AdminController) FetchPublicStatSettings(c *gin.Context) {
+	settings, err := aC.statsService.Settings()
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch public stat settings"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"metrics": settings})
+}
+
+type UpdatePublicStatsParams struct {
+	Metrics map[string]bool `json:"metrics" binding:"required"`
+}
+
+// UpdatePublicStats는 공개 통계에 포함할 지표를 지정합니다. 지정하지 않은 지표는 기존 설정을 유지합니다. (감사 로그 기록)
+// PUT /api/admin/public-stats {"metrics": {"users": true, "vms_total": false}}
+func (aC *AdminController) UpdatePublicStats(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	var req UpdatePublicStatsParams
+	if err := c.ShouldBindJSON(&req); err != nil || len(req.Metrics) == 0 {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	actorId, err := cast.ToUintE(user_id)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid user_id"))
+		return
+	}
+
+	statsService := aC.statsService
+	if err := statsService.SetPublic(req.Metrics, actorId); err != nil {
+		if errors.Is(err, statsservice.ErrUnknownMetric) {
+			middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, err.Error()))
+			return
+		}
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to update public stat settings"))
+		return
+	}
+
+	changes := make([]string, 0, len(req.Metrics))
+	for name, public := range req.Metrics {
+		changes = append(changes, fmt.Sprintf("%s=%t", name, public))
+	}
+	sort.Strings(changes)
+	if err := aC.auditService.Record(&actorId, "public_stats.update", "public-stats", strings.Join(changes, ", ")); err != nil {
+		logger.FromContext(c.Request.Context()).Warn("failed to record audit log for public stats", "error", err)
+	}
+
+	settings, err := statsService.Settings()
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch public stat settings"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"metrics": settings})
+}
@@ -0,0 +1,265 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	http "net/http"
+	"strconv"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/logger"
+	"vm-controller/internal/middleware"
+	"vm-controller/internal/models"
+	notificationservice "vm-controller/internal/services/notification_service"
+	reportservice "vm-controller/internal/services/report_service"
+	vmlifecycleservice "vm-controller/internal/services/vm_lifecycle_service"
+
+	gin "github.com/gin-gonic/gin"
+	cast "github.com/spf13/cast"
+)
+
+// 신고에 대한 관리자 조치
+const (
+	ReportActionStop        = "stop"         // VM 정지 또는 웹 배포 일시 중지
+	ReportActionSuspendUser = "suspend_user" // 대상 소유자 계정 정지
+	ReportActionDismiss     = "dismiss"      // 문제 없음으로 종료
+)
+
+// FetchAbuseReports는 사용자 신고와 자동 탐지 신고 목록을 반환합니다. (기본: 처리 대기 중인 신고)
+// GET /api/admin/reports?status=Open|Resolved|Dismissed|all&source=user|detector&limit=100
+func (aC *AdminController) FetchAbuseReports(c *gin.Context) {
+	status := c.DefaultQuery("status", string(models.ReportStatusOpen))
+	if status == "all" {
+		status = ""
+	}
+
+	reports, err := aC.reportService.FetchReports(status, c.Query("source"), auditLogLimit(c))
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch reports"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reports": reports})
+}
+
+type ReportActionParams struct {
+	Action string `json:"action" binding:"required"` // stop / suspend_user / dismiss
+	Note   string `json:"note"`
+}
+
+// HandleAbuseReport는 신고에 조치하고 신고를 종료합니다. (감사 로그 기록, 신고자와 대상 소유자에게 알림)
+// 신고를 먼저 종료 상태로 바꾸므로 두 관리자가 동시에 처리해도 조치는 한 번만 실행되며, 조치가 실패하면 신고는 다시 대기 상태가 됩니다.
+// POST /api/admin/reports/:id/actions {"action": "stop|suspend_user|dismiss", "note": "..."}
+func (aC *AdminController) HandleAbuseReport(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	actorId, err := cast.ToUintE(user_id)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid user_id"))
+		return
+	}
+
+	var req ReportActionParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	status := models.ReportStatusResolved
+	switch req.Action {
+	case ReportActionStop, ReportActionSuspendUser:
+	case ReportActionDismiss:
+		status = models.ReportStatusDismissed
+	default:
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "action must be one of stop, suspend_user, dismiss"))
+		return
+	}
+
+	id, err := cast.ToUintE(c.Param("id"))
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid report id"))
+		return
+	}
+
+	reportService := aC.reportService
+	report, err := reportService.FetchReport(id)
+	if errors.Is(err, reportservice.ErrReportNotFound) {
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeNotFound))
+		return
+	}
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch report"))
+		return
+	}
+	if req.Action == ReportActionSuspendUser && report.OwnerID == actorId {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "자신의 계정은 정지할 수 없습니다."))
+		return
+	}
+
+	if err := reportService.Close(id, status, req.Action, actorId, req.Note); err != nil {
+		if errors.Is(err, reportservice.ErrReportClosed) {
+			middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeConflict))
+			return
+		}
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to update report"))
+		return
+	}
+
+	notice, auditTarget, err := aC.applyReportAction(c, report, req.Action, actorId)
+	if err != nil {
+		if reopenErr := reportService.Reopen(id); reopenErr != nil {
+			logger.FromContext(c.Request.Context()).Error("failed to reopen report", "report_id", id, "error", reopenErr)
+		}
+		respondLifecycleError(c, err, "Failed to apply report action")
+		return
+	}
+
+	detail := fmt.Sprintf("report #%d (%s)", report.ID, report.Category)
+	if req.Note != "" {
+		detail += ": " + req.Note
+	}
+	if err := aC.auditService.Record(&actorId, "report."+req.Action, auditTarget, detail); err != nil {
+		logger.FromContext(c.Request.Context()).Warn("failed to record audit log", "report_id", report.ID, "error", err)
+	}
+
+	if status == models.ReportStatusResolved {
+		reportService.Notify(report.ReporterID, report, "report.resolved", notificationservice.Data{"report_id": report.ID})
+	} else {
+		reportService.Notify(report.ReporterID, report, "report.dismissed", notificationservice.Data{"report_id": report.ID})
+	}
+	if notice != nil {
+		reportService.Notify(report.OwnerID, report, notice.key, notice.data)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"report_id": report.ID, "status": status, "action": req.Action})
+}
+
+// reportNotice는 신고 조치 후 대상 소유자에게 보낼 알림입니다.
+type reportNotice struct {
+	key  string
+	data notificationservice.Data
+}
+
+// applyReportAction은 신고 대상에 조치를 실행하고, 소유자에게 보낼 알림(없으면 nil)과 감사 로그 대상을 반환합니다.
+func (aC *AdminController) applyReportAction(c *gin.Context, report *models.AbuseReport, action string, actorId uint) (*reportNotice, string, error) {
+	switch action {
+	case ReportActionStop:
+		if report.TargetType == models.ReportTargetVM {
+			if _, _, err := aC.vmController.lifecycleService.WithContext(c.Request.Context()).StopAsAdmin(actorId, report.TargetName, c.GetString("trace_id")); err != nil {
+				return nil, "", err
+			}
+			return &reportNotice{"report.vm_stopped", notificationservice.Data{"category": report.Category, "vm": report.TargetName}}, "vm/" + report.TargetName, nil
+		}
+
+		deployment, err := aC.deploymentService.FetchDeploymentById(report.TargetID)
+		if err != nil || deployment == nil {
+			return nil, "", errors.New("deployment not found")
+		}
+		owner, err := aC.userService.FetchUserById(strconv.FormatUint(uint64(deployment.UserID), 10), true)
+		if err != nil {
+			return nil, "", err
+		}
+		if err := aC.k8sService.WithContext(c.Request.Context()).PauseDeployment(deployment, owner.Namespace); err != nil {
+			return nil, "", err
+		}
+		return &reportNotice{"report.deployment_paused", notificationservice.Data{"category": report.Category, "domain": deployment.Domain}}, "deployment/" + deployment.Domain, nil
+
+	case ReportActionSuspendUser:
+		if err := aC.userService.SuspendUser(report.OwnerID, fmt.Sprintf("신고 #%d (%s)", report.ID, report.Category)); err != nil {
+			return nil, "", err
+		}
+		return &reportNotice{"report.user_suspended", notificationservice.Data{"category": report.Category}}, fmt.Sprintf("user/%d", report.OwnerID), nil
+
+	case ReportActionDismiss:
+		// 자동 탐지로 일시 정지된 VM은 기각하면 다시 실행
+		if report.ThrottledAt != nil && report.TargetType == models.ReportTargetVM {
+			if err := aC.releaseThrottledVM(c, report, actorId); err != nil {
+				return nil, "", err
+			}
+			return &reportNotice{"report.vm_released", notificationservice.Data{"vm": report.TargetName}}, "vm/" + report.TargetName, nil
+		}
+	}
+
+	return nil, report.TargetType + "/" + report.TargetName, nil
+}
+
+// releaseThrottledVM은 탐지 신고로 일시 정지된 VM을 다시 실행하고 격리 기록을 지웁니다.
+func (aC *AdminController) releaseThrottledVM(c *gin.Context, report *models.AbuseReport, actorId uint) error {
+	vm, err := aC.vmController.vmService.FetchVmName(report.TargetName, false)
+	if err != nil || vm == nil {
+		return vmlifecycleservice.ErrVMNotFound
+	}
+	if vm.Status == models.VmStatusPaused {
+		aC.vmController.vmEventService.RecordOperation(vm.Name, "mining.release", actorId)
+		aC.k8sService.WithRequest(c.Request.Context()).UnpauseVMAsync(vm, c.GetString("trace_id"))
+	}
+
+	return aC.reportService.MarkThrottled(report.ID, nil)
+}
+
+type SuspendUserParams struct {
+	Reason string `json:"reason"`
+}
+
+// SuspendUser는 사용자 계정을 정지합니다. 정지된 계정은 로그인과 모든 API 호출이 거부되며, VM은 그대로 둡니다. (감사 로그 기록)
+// POST /api/admin/users/:id/suspend {"reason": "..."}
+func (aC *AdminController) SuspendUser(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	actorId, err := cast.ToUintE(user_id)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid user_id"))
+		return
+	}
+
+	var req SuspendUserParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	targetId, err := cast.ToUintE(c.Param("id"))
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid user id"))
+		return
+	}
+	if targetId == actorId {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "자신의 계정은 정지할 수 없습니다."))
+		return
+	}
+
+	if err := aC.userService.SuspendUser(targetId, req.Reason); err != nil {
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeNotFound))
+		return
+	}
+
+	if err := aC.auditService.Record(&actorId, "user.suspend", fmt.Sprintf("user/%d", targetId), req.Reason); err != nil {
+		logger.FromContext(c.Request.Context()).Warn("failed to record audit log", "user_id", targetId, "error", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user_id": targetId, "suspended": true})
+}
+
+// UnsuspendUser는 계정 정지를 해제합니다. (감사 로그 기록)
+// DELETE /api/admin/users/:id/suspend
+func (aC *AdminController) UnsuspendUser(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	targetId, err := cast.ToUintE(c.Param("id"))
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid user id"))
+		return
+	}
+
+	if err := aC.userService.UnsuspendUser(targetId); err != nil {
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeNotFound))
+		return
+	}
+
+	if actorId, err := cast.ToUintE(user_id); err == nil {
+		if err := aC.auditService.Record(&actorId, "user.unsuspend", fmt.Sprintf("user/%d", targetId), ""); err != nil {
+			logger.FromContext(c.Request.Context()).Warn("failed to record audit log", "user_id", targetId, "error", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user_id": targetId, "suspended": false})
+}
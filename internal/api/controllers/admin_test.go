@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	yaml "sigs.k8s.io/yaml"
+)
+
+// 행을 하나씩 기록한 리포트가 형식별로 전체 목록을 한 번에 인코딩한 것과 같은 값으로 읽혀야 함
+func TestVMReportEncoderStreamsValidDocuments(t *testing.T) {
+	sample := []VMReportRow{
+		{Owner: "alice", StudentID: "2024001", Name: "vm-a", Namespace: "alice", Status: "Running", Node: "node-1", UptimeSec: 42, Port: 30001},
+		{Owner: "bob", Name: "vm-b", Namespace: "bob", Status: "Stopped", LastActivity: "2026-01-02T03:04:05Z"},
+	}
+
+	for _, format := range []string{"csv", "json", "yaml"} {
+		for n := 0; n <= len(sample); n++ {
+			rows := sample[:n]
+
+			var buf bytes.Buffer
+			encoder := newVMReportEncoder(format)
+			if err := encoder.begin(&buf); err != nil {
+				t.Fatalf("%s/%d: begin: %v", format, n, err)
+			}
+			for i, row := range rows {
+				if err := encoder.row(&buf, i, row); err != nil {
+					t.Fatalf("%s/%d: row %d: %v", format, n, i, err)
+				}
+				if err := encoder.flush(); err != nil {
+					t.Fatalf("%s/%d: flush: %v", format, n, err)
+				}
+			}
+			if err := encoder.end(&buf, len(rows)); err != nil {
+				t.Fatalf("%s/%d: end: %v", format, n, err)
+			}
+
+			switch format {
+			case "csv":
+				records, err := csv.NewReader(&buf).ReadAll()
+				if err != nil {
+					t.Fatalf("csv/%d: %v", n, err)
+				}
+				if len(records) != n+1 {
+					t.Fatalf("csv/%d: got %d records, want header + %d rows", n, len(records), n)
+				}
+				for i, row := range rows {
+					if got, want := records[i+1], row.csvRecord(); !slices.Equal(got, want) {
+						t.Errorf("csv/%d: row %d = %v, want %v", n, i, got, want)
+					}
+				}
+			case "json", "yaml":
+				var got []VMReportRow
+				var err error
+				if format == "json" {
+					err = json.Unmarshal(buf.Bytes(), &got)
+				} else {
+					err = yaml.Unmarshal(buf.Bytes(), &got)
+				}
+				if err != nil {
+					t.Fatalf("%s/%d: %v\n%s", format, n, err, buf.String())
+				}
+				if got == nil {
+					t.Fatalf("%s/%d: decoded nil, want a list\n%s", format, n, buf.String())
+				}
+				if len(got) != n {
+					t.Fatalf("%s/%d: got %d rows, want %d", format, n, len(got), n)
+				}
+				for i := range rows {
+					if got[i] != rows[i] {
+						t.Errorf("%s/%d: row %d = %+v, want %+v", format, n, i, got[i], rows[i])
+					}
+				}
+			}
+		}
+	}
+}
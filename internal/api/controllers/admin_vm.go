@@ -0,0 +1,178 @@
+package controllers
+
+import (
+	"fmt"
+	http "net/http"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/logger"
+	"vm-controller/internal/middleware"
+	k8s_service "vm-controller/internal/services/k8s_service"
+	notificationservice "vm-controller/internal/services/notification_service"
+	quotaservice "vm-controller/internal/services/quota_service"
+
+	gin "github.com/gin-gonic/gin"
+	cast "github.com/spf13/cast"
+)
+
+// FetchVM은 소유자와 관계없이 VM 상세 정보를 반환합니다. 삭제된 VM도 조회할 수 있습니다.
+// 클러스터에서 관측한 VMI(실행 노드, 단계)와 추가 디스크를 함께 반환하므로 종료되지 않는 VM을 진단할 때 사용합니다.
+// GET /api/admin/vms/:name
+func (aC *AdminController) FetchVM(c *gin.Context) {
+	vm, err := aC.vmService.WithContext(c.Request.Context()).FetchVmNameIncludingDeleted(c.Param("name"))
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch VM"))
+		return
+	}
+	if vm == nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeVMNotFound, "VM not found"))
+		return
+	}
+
+	owner, err := aC.userService.FetchUserById(cast.ToString(vm.UserID), true)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch VM owner"))
+		return
+	}
+
+	volumes, err := aC.volumeService.FetchVmVolumes(vm.Name)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch volumes"))
+		return
+	}
+
+	response := newVMResponse(vm)
+	response.Reachability = k8s_service.GetIngressReachability(vm)
+
+	result := gin.H{
+		"vm": AdminVMResponse{
+			VMResponse: response,
+			UserID:     vm.UserID,
+			Owner:      owner.Username,
+			StudentID:  owner.UserStudentId,
+		},
+		"deleted":  vm.IsDeleted,
+		"volumes":  volumes,
+		"instance": nil,
+	}
+
+	// 클러스터 조회 실패는 DB 정보만으로 응답 (진단 중인 VM은 클러스터 상태가 불안정할 수 있음)
+	vmis, err := aC.k8sService.WithContext(c.Request.Context()).ListVMIs()
+	if err != nil {
+		result["instance_error"] = err.Error()
+	} else if vmi, ok := vmis[vm.Namespace+"/"+vm.Name]; ok {
+		result["instance"] = gin.H{
+			"phase":      vmi.Phase,
+			"node_name":  vmi.NodeName,
+			"started_at": vmi.StartedAt,
+		}
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+type TransferVMParams struct {
+	UserID         uint `json:"user_id" binding:"required"`
+	SkipQuotaCheck bool `json:"skip_quota_check"` // 새 소유자의 쿼터를 넘더라도 이전
+}
+
+// TransferVM은 VM의 소유자를 다른 사용자로 바꿉니다. 추가 디스크와 스냅샷도 함께 이전됩니다. (감사 로그 기록)
+// 클러스터 리소스는 원래 네임스페이스에 남으므로 VM은 재시작되지 않으며, 새 소유자의 쿼터(VM 수, 스토리지)에 포함됩니다.
+// POST /api/admin/vms/:name/owner {"user_id": 7, "skip_quota_check": false}
+func (aC *AdminController) TransferVM(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	actorId, err := cast.ToUintE(user_id)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid user_id"))
+		return
+	}
+
+	var req TransferVMParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	vmService := aC.vmService.WithContext(c.Request.Context())
+	vm, err := vmService.FetchVmName(c.Param("name"), false)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch VM"))
+		return
+	}
+	if vm == nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeVMNotFound, "VM not found"))
+		return
+	}
+	if vm.UserID == req.UserID {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeConflict, "VM is already owned by this user"))
+		return
+	}
+
+	newOwner, err := aC.userService.FetchUserById(cast.ToString(req.UserID), true)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeUserNotFound, "User not found"))
+		return
+	}
+	if newOwner.SuspendedAt != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeConflict, "Cannot transfer a VM to a suspended user"))
+		return
+	}
+
+	volumes, err := aC.volumeService.FetchVmVolumes(vm.Name)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch volumes"))
+		return
+	}
+
+	if !req.SkipQuotaCheck {
+		storageGi := vm.DiskGi
+		if storageGi == 0 {
+			storageGi = quotaservice.VmDiskSizeGi
+		}
+		for _, volume := range volumes {
+			storageGi += volume.SizeGi
+		}
+
+		if _, err := aC.quotaService.Check(req.UserID, map[quotaservice.Dimension]int{
+			quotaservice.DimensionVMs:     1,
+			quotaservice.DimensionStorage: storageGi,
+		}); err != nil {
+			middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeInternal))
+			return
+		}
+	}
+
+	previousOwner := vm.UserID
+	if err := vmService.TransferVm(vm.Name, req.UserID); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to transfer VM"))
+		return
+	}
+	vm.UserID = req.UserID
+
+	aC.vmEventService.RecordOperation(vm.Name, "transfer", actorId)
+	detail := fmt.Sprintf("user %d -> user %d", previousOwner, req.UserID)
+	if req.SkipQuotaCheck {
+		detail += " (quota check skipped)"
+	}
+	if err := aC.auditService.Record(&actorId, "vm.transfer", "vm/"+vm.Name, detail); err != nil {
+		logger.FromContext(c.Request.Context()).Warn("failed to record audit log", "vm", vm.Name, "error", err)
+	}
+
+	notificationService := aC.notificationService
+	if err := notificationService.NotifyTemplate(previousOwner, "vm.transfer.removed", notificationservice.Data{"vm": vm.Name}); err != nil {
+		logger.FromContext(c.Request.Context()).Warn("failed to notify user of VM transfer", "user_id", previousOwner, "vm", vm.Name, "error", err)
+	}
+	if err := notificationService.NotifyTemplate(req.UserID, "vm.transfer.received", notificationservice.Data{"vm": vm.Name}); err != nil {
+		logger.FromContext(c.Request.Context()).Warn("failed to notify user of VM transfer", "user_id", req.UserID, "vm", vm.Name, "error", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"vm": AdminVMResponse{
+			VMResponse: newVMResponse(vm),
+			UserID:     newOwner.ID,
+			Owner:      newOwner.Username,
+			StudentID:  newOwner.UserStudentId,
+		},
+		"previous_user_id": previousOwner,
+	})
+}
@@ -2,14 +2,15 @@ package controllers
 
 import (
 	time "time"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/config"
+	"vm-controller/internal/middleware"
 	userservice "vm-controller/internal/services/user_service"
 
 	"net/http"
 
 	os "os"
 
-	sync "sync"
-
 	gin "github.com/gin-gonic/gin"
 	jwt "github.com/golang-jwt/jwt/v5"
 )
@@ -18,19 +19,10 @@ type AuthController struct {
 	userService *userservice.UserService
 }
 
-var (
-	authController *AuthController
-	once           sync.Once
-)
-
-func GetAuthController() *AuthController {
-	once.Do(func() {
-		authController = &AuthController{
-			userService: &userservice.UserService{},
-		}
-	})
-
-	return authController
+func NewAuthController(userService *userservice.UserService) *AuthController {
+	return &AuthController{
+		userService: userService,
+	}
 }
 
 func (authController *AuthController) RegisterRoutes(r *gin.RouterGroup) {
@@ -47,14 +39,18 @@ func (authController *AuthController) Login(c *gin.Context) {
 	var loginParams LoginParams
 
 	if err := c.ShouldBindJSON(&loginParams); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "로그인 정보를 정확하게 전달하세요."})
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "로그인 정보를 정확하게 전달하세요."))
 		return
 	}
 
-	user, err := authController.userService.AuthenticateUser(loginParams.StudentId, loginParams.Password)
+	user, err := authController.userService.WithContext(c.Request.Context()).AuthenticateUser(loginParams.StudentId, loginParams.Password)
 
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "인증 실패"})
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeUnauthenticated, "인증 실패"))
+		return
+	}
+	if user.SuspendedAt != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeUserSuspended, "정지된 계정입니다.").WithDetail("reason", user.SuspendedReason))
 		return
 	}
 
@@ -64,11 +60,12 @@ func (authController *AuthController) Login(c *gin.Context) {
 	}).SignedString([]byte(os.Getenv("JWT_SECRET")))
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "토큰 생성 실패"})
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "토큰 생성 실패"))
 		return
 	}
 
-	c.SetCookie("authorization", "Bearer "+tokenString, 86400, "/", "", true, true)
+	c.SetSameSite(config.Get().CookieSameSite)
+	c.SetCookie("authorization", "Bearer "+tokenString, 86400, "/", "", config.Get().SecureCookies, true)
 	c.JSON(http.StatusOK, gin.H{"message": "로그인 성공"})
 }
 
@@ -83,7 +80,7 @@ func (a *AuthController) CreateAccount(c *gin.Context) {
 	var createAccountParams CreateAccountParams
 
 	if err := c.ShouldBindJSON(&createAccountParams); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "계정 생성 정보를 정확하게 전달하세요."})
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "계정 생성 정보를 정확하게 전달하세요."))
 		return
 	}
 
@@ -95,7 +92,7 @@ func (a *AuthController) CreateAccount(c *gin.Context) {
 	})
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "계정 생성 실패"})
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "계정 생성 실패"))
 		return
 	}
 
@@ -0,0 +1,162 @@
+package controllers
+
+import (
+	http "net/http"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/middleware"
+	"vm-controller/internal/policy"
+	databaseservice "vm-controller/internal/services/database_service"
+	deploymentservice "vm-controller/internal/services/deployment_service"
+	k8s_service "vm-controller/internal/services/k8s_service"
+	userservice "vm-controller/internal/services/user_service"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+type DatabaseController struct {
+	k8sService        *k8s_service.K8sService
+	userService       *userservice.UserService
+	databaseService   *databaseservice.DatabaseService
+	deploymentService *deploymentservice.DeploymentService
+}
+
+func NewDatabaseController(k8sService *k8s_service.K8sService, userService *userservice.UserService, databaseService *databaseservice.DatabaseService, deploymentService *deploymentservice.DeploymentService) *DatabaseController {
+	return &DatabaseController{
+		k8sService:        k8sService,
+		userService:       userService,
+		databaseService:   databaseService,
+		deploymentService: deploymentService,
+	}
+}
+
+func (dbC *DatabaseController) RegisterRoutes(r *gin.RouterGroup) {
+	database := r.Group("/database", middleware.AuthGuard(), middleware.Idempotency())
+
+	database.POST("/create", dbC.CreateDatabase)
+	database.GET("/fetch", dbC.FetchUserDatabases)
+	database.DELETE("/delete", dbC.DeleteDatabase)
+	database.POST("/attach", dbC.AttachDatabase)
+}
+
+type CreateDatabaseParams struct {
+	Name      string `json:"name" binding:"required"`
+	Engine    string `json:"engine" binding:"required"` // postgres / mysql
+	StorageGi int    `json:"storage_gi" binding:"required"`
+}
+
+func (dbC *DatabaseController) CreateDatabase(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	var req CreateDatabaseParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	user, err := dbC.userService.WithContext(c.Request.Context()).FetchUserById(user_id.(string), true)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeUserNotFound, "User not found"))
+		return
+	}
+
+	database, err := dbC.databaseService.CreateDatabase(databaseservice.CreateDatabaseParams{
+		UserID:    user.ID,
+		Namespace: user.Namespace,
+		Name:      req.Name,
+		Engine:    req.Engine,
+		StorageGi: req.StorageGi,
+	})
+
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeInvalidRequest))
+		return
+	}
+
+	operationID := dbC.k8sService.WithRequest(c.Request.Context()).CreateManagedDatabaseAsync(database, c.GetString("trace_id"))
+
+	// Password Is Not Sent To Client (배포에 Secret으로만 주입)
+	database.Password = ""
+	c.JSON(http.StatusOK, gin.H{"database": database, "operation_id": operationID})
+}
+
+func (dbC *DatabaseController) FetchUserDatabases(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	databases, err := dbC.databaseService.FetchUserDatabases(user_id.(string), false)
+
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch databases"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"databases": databases})
+}
+
+type DeleteDatabaseParams struct {
+	DatabaseID uint `json:"database_id" binding:"required"`
+}
+
+func (dbC *DatabaseController) DeleteDatabase(c *gin.Context) {
+	var req DeleteDatabaseParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	database, err := dbC.databaseService.FetchDatabaseById(req.DatabaseID, false)
+	// 소유권 확인.
+	if err != nil || database == nil || !middleware.AuthorizeOwned(c, policy.ResourceDatabase, database.UserID) {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeDatabaseNotFound, "Database not found"))
+		return
+	}
+
+	operationID := dbC.k8sService.WithRequest(c.Request.Context()).DeleteManagedDatabaseAsync(database, c.GetString("trace_id"))
+
+	c.JSON(http.StatusOK, gin.H{"database": database, "operation_id": operationID})
+}
+
+type AttachDatabaseParams struct {
+	DatabaseID   uint `json:"database_id" binding:"required"`
+	DeploymentID uint `json:"deployment_id" binding:"required"`
+}
+
+// AttachDatabase는 관리형 데이터베이스 접속 정보를 배포에 Secret으로 주입합니다.
+func (dbC *DatabaseController) AttachDatabase(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	var req AttachDatabaseParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	user, err := dbC.userService.WithContext(c.Request.Context()).FetchUserById(user_id.(string), true)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeUserNotFound, "User not found"))
+		return
+	}
+
+	database, err := dbC.databaseService.FetchDatabaseById(req.DatabaseID, true)
+	if err != nil || database == nil || !middleware.AuthorizeOwned(c, policy.ResourceDatabase, database.UserID) {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeDatabaseNotFound, "Database not found"))
+		return
+	}
+
+	deployment, err := dbC.deploymentService.FetchDeploymentById(req.DeploymentID)
+	if err != nil || deployment == nil || !middleware.AuthorizeOwned(c, policy.ResourceDeployment, deployment.UserID) {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeDeploymentNotFound, "Deployment not found"))
+		return
+	}
+
+	if err := dbC.k8sService.WithContext(c.Request.Context()).InjectDatabaseSecret(deployment, database, user.Namespace); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to inject database credentials"))
+		return
+	}
+
+	if err := dbC.databaseService.AttachToDeployment(database.ID, deployment.ID); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to attach database"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Database attached", "deployment_id": deployment.ID, "database_id": database.ID})
+}
@@ -0,0 +1,41 @@
+package controllers
+
+import (
+	http "net/http"
+	"net/http/pprof"
+	"vm-controller/internal/metrics"
+	"vm-controller/internal/middleware"
+	"vm-controller/internal/policy"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+// DebugController는 운영 중 장애 진단용 pprof/런타임 엔드포인트를 제공합니다.
+// 내부 관리자 리스너(ADMIN_PORT)에만 등록되며, 관리자 권한이 필요합니다.
+type DebugController struct{}
+
+func NewDebugController() *DebugController {
+	return &DebugController{}
+}
+
+func (dC *DebugController) RegisterRoutes(r *gin.RouterGroup) {
+	debug := r.Group("/debug", middleware.AuthGuard(), middleware.Authorize(policy.ResourceDebug))
+
+	debug.GET("/runtime", dC.Runtime)
+
+	// net/http/pprof 핸들러 (goroutine, heap, profile, trace ...)
+	debug.GET("/pprof/", gin.WrapF(pprof.Index))
+	debug.GET("/pprof/cmdline", gin.WrapF(pprof.Cmdline))
+	debug.GET("/pprof/profile", gin.WrapF(pprof.Profile))
+	debug.POST("/pprof/symbol", gin.WrapF(pprof.Symbol))
+	debug.GET("/pprof/symbol", gin.WrapF(pprof.Symbol))
+	debug.GET("/pprof/trace", gin.WrapF(pprof.Trace))
+	debug.GET("/pprof/:profile", func(c *gin.Context) {
+		pprof.Handler(c.Param("profile")).ServeHTTP(c.Writer, c.Request)
+	})
+}
+
+// Runtime은 현재 고루틴 수, 힙 사용량 등 런타임 상태를 반환합니다.
+func (dC *DebugController) Runtime(c *gin.Context) {
+	c.JSON(http.StatusOK, metrics.GetRuntimeStats())
+}
@@ -0,0 +1,246 @@
+package controllers
+
+import (
+	http "net/http"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/middleware"
+	"vm-controller/internal/models"
+	"vm-controller/internal/policy"
+	deploymentservice "vm-controller/internal/services/deployment_service"
+	k8s_service "vm-controller/internal/services/k8s_service"
+	userservice "vm-controller/internal/services/user_service"
+
+	gin "github.com/gin-gonic/gin"
+	cast "github.com/spf13/cast"
+)
+
+type DeploymentController struct {
+	k8sService        *k8s_service.K8sService
+	userService       *userservice.UserService
+	deploymentService *deploymentservice.DeploymentService
+}
+
+func NewDeploymentController(k8sService *k8s_service.K8sService, userService *userservice.UserService, deploymentService *deploymentservice.DeploymentService) *DeploymentController {
+	return &DeploymentController{
+		k8sService:        k8sService,
+		userService:       userService,
+		deploymentService: deploymentService,
+	}
+}
+
+func (dC *DeploymentController) RegisterRoutes(r *gin.RouterGroup) {
+	deployment := r.Group("/deployment", middleware.AuthGuard(), middleware.Idempotency())
+
+	deployment.POST("/create", dC.CreateDeployment)
+	deployment.GET("/fetch", dC.FetchUserDeployments)
+	deployment.GET("/runs", dC.FetchJobRuns)
+	deployment.POST("/pause", dC.PauseDeployment)
+	deployment.POST("/resume", dC.ResumeDeployment)
+	deployment.POST("/sleep-policy", dC.UpdateSleepPolicy)
+}
+
+type CreateDeploymentRequest struct {
+	Type           string `json:"type"`     // web (기본값) / cronjob
+	Schedule       string `json:"schedule"` // cronjob 유형에서 필수
+	RepoURL        string `json:"repo_url" binding:"required"`
+	Domain         string `json:"domain" binding:"required"`
+	Branch         string `json:"branch"`
+	ContextDir     string `json:"context_dir"`
+	DockerfilePath string `json:"dockerfile_path"`
+}
+
+func (dC *DeploymentController) CreateDeployment(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	var req CreateDeploymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	u64, err := cast.ToUintE(user_id)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid user_id"))
+		return
+	}
+
+	user, err := dC.userService.WithContext(c.Request.Context()).FetchUserById(user_id.(string), true)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeUserNotFound, "User not found"))
+		return
+	}
+
+	deployment, err := dC.deploymentService.CreateDeployment(deploymentservice.CreateDeploymentParams{
+		UserID:         u64,
+		Type:           req.Type,
+		Schedule:       req.Schedule,
+		RepoURL:        req.RepoURL,
+		Domain:         req.Domain,
+		Branch:         req.Branch,
+		ContextDir:     req.ContextDir,
+		DockerfilePath: req.DockerfilePath,
+	})
+
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeInvalidRequest))
+		return
+	}
+
+	// 빌드 Job 생성은 백그라운드에서 진행 (Dockerfile 유무에 따라 kaniko / buildpacks)
+	operationID := dC.k8sService.WithRequest(c.Request.Context()).BuildDeploymentAsync(deployment, user.Namespace, c.GetString("trace_id"))
+
+	c.JSON(http.StatusOK, gin.H{"deployment": deployment, "operation_id": operationID})
+}
+
+func (dC *DeploymentController) FetchUserDeployments(c *gin.Context) {
+	user_id, ok := c.Get("user_id")
+
+	if !ok {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid user_id"))
+		return
+	}
+
+	deployments, err := dC.deploymentService.FetchUserDeployments(user_id.(string))
+
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch deployments"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deployments": deployments})
+}
+
+// FetchJobRuns는 스케줄 작업 배포의 실행 이력과 마지막 실행 로그를 반환합니다.
+func (dC *DeploymentController) FetchJobRuns(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	deploymentId, err := cast.ToUintE(c.Query("deployment_id"))
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid deployment_id"))
+		return
+	}
+
+	deployment, err := dC.deploymentService.FetchDeploymentById(deploymentId)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch deployment"))
+		return
+	}
+
+	// 소유권 확인.
+	if deployment == nil || !middleware.AuthorizeOwned(c, policy.ResourceDeployment, deployment.UserID) {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeDeploymentNotFound, "Deployment not found"))
+		return
+	}
+
+	if deployment.Type != models.DeploymentTypeCronJob {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Deployment is not a scheduled job"))
+		return
+	}
+
+	user, err := dC.userService.WithContext(c.Request.Context()).FetchUserById(user_id.(string), true)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeUserNotFound, "User not found"))
+		return
+	}
+
+	runs, err := dC.k8sService.WithContext(c.Request.Context()).SyncJobRuns(deployment, user.Namespace)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch job runs"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"runs": runs})
+}
+
+type DeploymentActionParams struct {
+	DeploymentID uint `json:"deployment_id" binding:"required"`
+}
+
+// fetchOwnedDeployment는 요청자가 소유한 배포와 사용자 정보를 함께 조회합니다.
+func (dC *DeploymentController) fetchOwnedDeployment(c *gin.Context, deploymentId uint) (*models.Deployment, string, bool) {
+	user_id, _ := c.Get("user_id")
+
+	user, err := dC.userService.WithContext(c.Request.Context()).FetchUserById(user_id.(string), true)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeUserNotFound, "User not found"))
+		return nil, "", false
+	}
+
+	deployment, err := dC.deploymentService.FetchDeploymentById(deploymentId)
+	if err != nil || deployment == nil || !middleware.AuthorizeOwned(c, policy.ResourceDeployment, deployment.UserID) {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeDeploymentNotFound, "Deployment not found"))
+		return nil, "", false
+	}
+
+	return deployment, user.Namespace, true
+}
+
+func (dC *DeploymentController) PauseDeployment(c *gin.Context) {
+	var req DeploymentActionParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	deployment, namespace, ok := dC.fetchOwnedDeployment(c, req.DeploymentID)
+	if !ok {
+		return
+	}
+
+	if err := dC.k8sService.WithContext(c.Request.Context()).PauseDeployment(deployment, namespace); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to pause deployment"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Deployment paused"})
+}
+
+func (dC *DeploymentController) ResumeDeployment(c *gin.Context) {
+	var req DeploymentActionParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	deployment, namespace, ok := dC.fetchOwnedDeployment(c, req.DeploymentID)
+	if !ok {
+		return
+	}
+
+	if err := dC.k8sService.WithContext(c.Request.Context()).ResumeDeployment(deployment, namespace); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to resume deployment"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Deployment resumed"})
+}
+
+type SleepPolicyParams struct {
+	DeploymentID    uint `json:"deployment_id" binding:"required"`
+	SleepAfterHours int  `json:"sleep_after_hours"` // 0이면 sleep-on-idle 비활성
+}
+
+func (dC *DeploymentController) UpdateSleepPolicy(c *gin.Context) {
+	var req SleepPolicyParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	deployment, _, ok := dC.fetchOwnedDeployment(c, req.DeploymentID)
+	if !ok {
+		return
+	}
+
+	if deployment.Type != models.DeploymentTypeWeb {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Sleep-on-idle is only supported for web deployments"))
+		return
+	}
+
+	if err := dC.deploymentService.UpdateSleepPolicy(deployment.ID, req.SleepAfterHours); err != nil {
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeInvalidRequest))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Sleep policy updated", "sleep_after_hours": req.SleepAfterHours})
+}
@@ -3,6 +3,7 @@ package controllers
 import (
 	"net/http"
 
+	"vm-controller/internal/db"
 	"vm-controller/internal/services/k8s_service"
 
 	"github.com/gin-gonic/gin"
@@ -12,15 +13,9 @@ type HealthController struct {
 	K8sService *k8s_service.K8sService
 }
 
-func GetHealthController() *HealthController {
-	k8s_service, _ := k8s_service.GetK8sService()
-	return &HealthController{
-		K8sService: k8s_service,
-	}
-}
-
 func (h *HealthController) RegisterRoutes(group *gin.RouterGroup) {
 	group.GET("/health", h.Check)
+	group.GET("/readyz", h.Ready)
 }
 
 func NewHealthController(k8sService *k8s_service.K8sService) *HealthController {
@@ -44,3 +39,52 @@ func (h *HealthController) Check(c *gin.Context) {
 		"k8s_connectivity": status,
 	})
 }
+
+// Ready는 트래픽을 받을 준비가 되었는지 의존성(K8s, DB)별 상세 상태와 함께 반환합니다.
+func (h *HealthController) Ready(c *gin.Context) {
+	ready := true
+	checks := gin.H{}
+
+	if status, err := h.K8sService.CheckConnectivity(); err != nil {
+		ready = false
+		checks["kubernetes"] = gin.H{"status": status, "error": err.Error()}
+	} else {
+		checks["kubernetes"] = gin.H{"status": status}
+	}
+
+	databaseCheck := gin.H{"status": "healthy", "pool": db.GetPoolStats()}
+	if err := db.Ping(); err != nil {
+		ready = false
+		databaseCheck["status"] = "unhealthy"
+		databaseCheck["error"] = err.Error()
+	}
+	checks["database"] = databaseCheck
+
+	// 작업 큐 과부하는 준비 상태를 바꾸지 않음 (조회/정지 요청은 계속 받아야 하므로 비싼 요청만 429로 거부)
+	backpressure := k8s_service.GetBackpressure()
+	jobsCheck := gin.H{"status": "healthy", "queue": backpressure}
+	if backpressure.Overloaded {
+		jobsCheck["status"] = "overloaded"
+	}
+	checks["jobs"] = jobsCheck
+
+	// KubeVirt/CDI가 없어도 준비 상태는 바꾸지 않음 (VM API만 501로 거부하고 사용자/배포 API는 계속 제공)
+	capabilities := k8s_service.GetCapabilities()
+	capabilitiesCheck := gin.H{"status": "healthy", "capabilities": capabilities}
+	if !capabilities.VMs {
+		capabilitiesCheck["status"] = "degraded"
+	}
+	checks["capabilities"] = capabilitiesCheck
+
+	code := http.StatusOK
+	status := "ready"
+	if !ready {
+		code = http.StatusServiceUnavailable
+		status = "not_ready"
+	}
+
+	c.JSON(code, gin.H{
+		"status": status,
+		"checks": checks,
+	})
+}
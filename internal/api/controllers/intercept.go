@@ -1,10 +1,19 @@
 package controllers
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"math"
 	"regexp" // Added for regular expressions
-	"sync"
+	"strconv"
 	"time"
+	"vm-controller/internal/logger"
+	"vm-controller/internal/models"
+	auditservice "vm-controller/internal/services/audit_service"
+	blocklistservice "vm-controller/internal/services/blocklist_service"
+	deploymentservice "vm-controller/internal/services/deployment_service"
+	k8s_service "vm-controller/internal/services/k8s_service"
 
 	"github.com/gin-gonic/gin"
 )
@@ -19,23 +28,22 @@ type securityEngine struct {
 }
 
 type Interceptor struct {
-	securityEngine *securityEngine // 보안 엔진 추가
+	securityEngine    *securityEngine // 보안 엔진 추가
+	k8sService        *k8s_service.K8sService
+	blocklistService  *blocklistservice.BlocklistService // IP별 요청 제한 / 일시 차단 (인터셉터 간 공유)
+	auditService      *auditservice.AuditService
+	deploymentService *deploymentservice.DeploymentService
 }
 
-var (
-	interceptor *Interceptor
-	onceInter   sync.Once
-)
-
-// GetInterceptor: Interceptor 싱글톤 인스턴스 반환
-func GetInterceptor() *Interceptor {
-	onceInter.Do(func() {
-		interceptor = &Interceptor{
-			securityEngine: NewSecurityEngine(), // 보안 엔진 초기화
-		}
-	})
-
-	return interceptor
+// NewInterceptor: 보안 엔진과 배포 깨우기에 사용할 K8sService로 Interceptor 생성
+func NewInterceptor(k8sService *k8s_service.K8sService, blocklistService *blocklistservice.BlocklistService, auditService *auditservice.AuditService, deploymentService *deploymentservice.DeploymentService) *Interceptor {
+	return &Interceptor{
+		securityEngine:    NewSecurityEngine(), // 보안 엔진 초기화
+		k8sService:        k8sService,
+		blocklistService:  blocklistService,
+		auditService:      auditService,
+		deploymentService: deploymentService,
+	}
 }
 
 // NewSecurityEngine: 보안 엔진 초기화 및 규칙 컴파일
@@ -79,6 +87,20 @@ func (i *Interceptor) handleIntercept(c *gin.Context) {
 	origQuery := c.GetHeader("X-Forwarded-Query") // 쿼리 스트링도 검사 필요
 	clientIP := c.ClientIP()
 	userAgent := c.Request.UserAgent()
+	ctx := c.Request.Context()
+
+	// 일시 차단된 IP와 요청 수 제한을 넘긴 IP는 검사 없이 거부
+	if ban := i.blocklistService.Check(ctx, clientIP); ban != nil {
+		i.rejectBanned(c, ban)
+		return
+	}
+	if ban, created := i.blocklistService.CountRequest(ctx, clientIP); ban != nil {
+		if created {
+			i.recordBan(ctx, ban)
+		}
+		i.rejectBanned(c, ban)
+		return
+	}
 
 	// 2. 보안 분석 수행
 	// 빠르고 효율적인 룰 기반 검사
@@ -90,18 +112,25 @@ func (i *Interceptor) handleIntercept(c *gin.Context) {
 		status = "BLOCKED"
 	}
 
-	fmt.Printf("\n[Security Audit] %s | IP: %s | %s %s | UA: %s | Result: %s (%s)\n",
-		time.Now().Format("2006-01-02 15:04:05"),
-		clientIP,
-		origMethod,
-		origPath,
-		userAgent,
-		status,
-		reason,
+	logger.FromContext(ctx).Info("security audit",
+		"ip", clientIP,
+		"method", origMethod,
+		"path", origPath,
+		"user_agent", userAgent,
+		"result", status,
+		"reason", reason,
 	)
 
 	if !isSecure {
-		// 3. 차단: 보안 위협 감지됨
+		// 3. 차단: 보안 위협 감지됨 (관리자/auditor가 보안 이벤트로 조회)
+		detail := fmt.Sprintf("%s %s | UA: %s | %s", origMethod, origPath, userAgent, reason)
+		if err := i.auditService.Record(nil, "security.blocked", "ip/"+clientIP, detail); err != nil {
+			logger.FromContext(ctx).Warn("failed to record security event", "ip", clientIP, "error", err)
+		}
+		// 위반이 반복되면 이후 요청은 검사 없이 차단
+		if ban, created := i.blocklistService.RecordOffense(ctx, clientIP, reason); created {
+			i.recordBan(ctx, ban)
+		}
 		c.Header("X-Block-Reason", reason)
 		c.AbortWithStatusJSON(403, gin.H{
 			"status": "blocked",
@@ -111,10 +140,69 @@ func (i *Interceptor) handleIntercept(c *gin.Context) {
 		return
 	}
 
-	// 4. 승인: 안전한 트래픽
+	// 4. Activator: 배포 도메인 트래픽 기록 및 Sleeping 배포 깨우기
+	if origHost := c.GetHeader("X-Forwarded-Host"); origHost != "" {
+		if waking := i.activate(origHost); waking {
+			// 파드가 뜰 때까지 클라이언트에게 재시도 요청
+			c.Header("Retry-After", "10")
+			c.AbortWithStatusJSON(503, gin.H{
+				"status":  "waking",
+				"message": "서비스를 깨우는 중입니다. 잠시 후 다시 시도하세요.",
+			})
+			return
+		}
+	}
+
+	// 5. 승인: 안전한 트래픽
 	c.Status(200)
 }
 
+// rejectBanned: 차단 해제 시각을 Retry-After로 알리고 요청을 거부합니다.
+func (i *Interceptor) rejectBanned(c *gin.Context, ban *blocklistservice.Ban) {
+	retryAfter := int(math.Ceil(time.Until(ban.Until).Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.Header("X-Block-Reason", ban.Reason)
+	c.AbortWithStatusJSON(403, gin.H{
+		"status": "banned",
+		"reason": ban.Reason,
+		"ip":     ban.IP,
+		"until":  ban.Until,
+	})
+}
+
+// recordBan: 새로 차단한 IP를 보안 이벤트로 기록합니다. (차단 중 거부된 요청은 기록하지 않음)
+func (i *Interceptor) recordBan(ctx context.Context, ban *blocklistservice.Ban) {
+	logger.FromContext(ctx).Warn("security audit: ip banned", "ip", ban.IP, "until", ban.Until.Format(time.RFC3339), "reason", ban.Reason)
+
+	detail := fmt.Sprintf("%s until %s", ban.Reason, ban.Until.Format(time.RFC3339))
+	if err := i.auditService.Record(nil, "security.banned", "ip/"+ban.IP, detail); err != nil {
+		logger.FromContext(ctx).Warn("failed to record security event", "ip", ban.IP, "error", err)
+	}
+}
+
+// activate: 도메인의 마지막 활동 시각을 기록하고, 배포가 Sleeping 상태라면 깨웁니다.
+// 반환: 배포를 깨우는 중인지 여부
+func (i *Interceptor) activate(host string) bool {
+	deploymentService := i.deploymentService
+	deploymentService.RecordActivity(host)
+
+	deployment, err := deploymentService.FetchDeploymentByDomain(host)
+	if err != nil || deployment == nil || deployment.Status != models.DeploymentStatusSleeping {
+		return false
+	}
+
+	if err := i.k8sService.WakeDeployment(deployment); err != nil {
+		slog.Error("activator: failed to wake deployment", "deployment_id", deployment.ID, "error", err)
+		return false
+	}
+
+	return true
+}
+
 // Analyze: 트래픽 종합 분석
 // path: 요청 경로
 // query: 쿼리 스트링
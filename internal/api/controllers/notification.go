@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	http "net/http"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/middleware"
+	notificationservice "vm-controller/internal/services/notification_service"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+type NotificationController struct {
+	notificationService *notificationservice.NotificationService
+}
+
+func NewNotificationController(notificationService *notificationservice.NotificationService) *NotificationController {
+	return &NotificationController{
+		notificationService: notificationService,
+	}
+}
+
+func (nC *NotificationController) RegisterRoutes(r *gin.RouterGroup) {
+	notification := r.Group("/notifications", middleware.AuthGuard())
+
+	notification.GET("/fetch", nC.FetchNotifications)
+	notification.POST("/read", nC.MarkRead)
+}
+
+func (nC *NotificationController) FetchNotifications(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	notifications, err := nC.notificationService.FetchUserNotifications(user_id.(string), c.Query("unread") == "true")
+
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch notifications"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"notifications": notifications})
+}
+
+type MarkReadParams struct {
+	NotificationID uint `json:"notification_id" binding:"required"`
+}
+
+func (nC *NotificationController) MarkRead(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	var req MarkReadParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	if err := nC.notificationService.MarkRead(user_id.(string), req.NotificationID); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to update notification"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "ok"})
+}
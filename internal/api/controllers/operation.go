@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"errors"
+	http "net/http"
+	"time"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/middleware"
+	"vm-controller/internal/models"
+	"vm-controller/internal/policy"
+	operationservice "vm-controller/internal/services/operation_service"
+
+	gin "github.com/gin-gonic/gin"
+	cast "github.com/spf13/cast"
+)
+
+type OperationController struct {
+	operationService *operationservice.OperationService
+}
+
+func NewOperationController(operationService *operationservice.OperationService) *OperationController {
+	return &OperationController{
+		operationService: operationService,
+	}
+}
+
+func (oC *OperationController) RegisterRoutes(r *gin.RouterGroup) {
+	operation := r.Group("/operations", middleware.AuthGuard())
+
+	operation.GET("/:id", oC.FetchOperation)
+}
+
+// OperationResponse는 백그라운드 작업의 진행 상태입니다.
+type OperationResponse struct {
+	ID         uint                       `json:"id"`
+	Name       string                     `json:"name"`
+	Target     string                     `json:"target"`
+	Status     models.EnumOperationStatus `json:"status"`
+	Attempts   int                        `json:"attempts"`
+	Error      string                     `json:"error,omitempty"`       // 마지막 실패 사유 (재시도 중이면 직전 시도의 사유)
+	RequestID  string                     `json:"request_id,omitempty"`  // 작업을 요청한 API 요청의 ID (서버 로그 검색용)
+	NextRunAt  *time.Time                 `json:"next_run_at,omitempty"` // 재시도 대기 중이면 다음 시도 시각
+	CreatedAt  time.Time                  `json:"created_at"`
+	StartedAt  *time.Time                 `json:"started_at"`
+	FinishedAt *time.Time                 `json:"finished_at"`
+}
+
+func newOperationResponse(operation *models.Operation) OperationResponse {
+	return OperationResponse{
+		ID:         operation.ID,
+		Name:       operation.Name,
+		Target:     operation.Target,
+		Status:     operation.Status,
+		Attempts:   operation.Attempts,
+		Error:      operation.Error,
+		RequestID:  operation.RequestID,
+		NextRunAt:  operation.NextRunAt,
+		CreatedAt:  operation.CreatedAt,
+		StartedAt:  operation.StartedAt,
+		FinishedAt: operation.FinishedAt,
+	}
+}
+
+// fetchOperation은 경로의 작업을 조회합니다. 실패하면 응답을 쓰고 nil을 반환합니다.
+func fetchOperation(c *gin.Context, operationService *operationservice.OperationService) *models.Operation {
+	id, err := cast.ToUintE(c.Param("id"))
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid operation id"))
+		return nil
+	}
+
+	operation, err := operationService.FetchOperation(id)
+	if err != nil {
+		if errors.Is(err, operationservice.ErrOperationNotFound) {
+			middleware.AbortWithError(c, apperrors.New(apperrors.CodeOperationNotFound, "Operation not found"))
+			return nil
+		}
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch operation"))
+		return nil
+	}
+
+	return operation
+}
+
+// FetchOperation은 작업 ID(start/stop/delete 등의 응답 operation_id)의 진행 상태와 실패 사유를 반환합니다.
+// GET /api/operations/:id
+func (oC *OperationController) FetchOperation(c *gin.Context) {
+	operation := fetchOperation(c, oC.operationService)
+	if operation == nil {
+		return
+	}
+
+	// 소유권 확인. (다른 사용자의 작업은 존재 여부도 알리지 않음)
+	if operation.UserID == nil || !middleware.AuthorizeOwned(c, policy.ResourceOperation, *operation.UserID) {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeOperationNotFound, "Operation not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"operation": newOperationResponse(operation)})
+}
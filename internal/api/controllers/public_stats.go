@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"fmt"
+	http "net/http"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/middleware"
+	statsservice "vm-controller/internal/services/stats_service"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+type StatsController struct {
+	statsService *statsservice.StatsService
+}
+
+func NewStatsController(statsService *statsservice.StatsService) *StatsController {
+	return &StatsController{
+		statsService: statsService,
+	}
+}
+
+func (sC *StatsController) RegisterRoutes(r *gin.RouterGroup) {
+	r.GET("/public/stats", sC.FetchPublicStats)
+}
+
+// FetchPublicStats는 로그인 없이 볼 수 있는 플랫폼 집계 통계를 반환합니다. (강의/실습 소개 페이지 삽입용)
+// 관리자가 공개로 지정한 지표만 포함하며, 결과는 PUBLIC_STATS_CACHE_TTL 동안 캐시됩니다.
+// GET /api/public/stats
+func (sC *StatsController) FetchPublicStats(c *gin.Context) {
+	stats, err := sC.statsService.PublicStats()
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch stats"))
+		return
+	}
+
+	// 어느 페이지에서든 쿠키 없이 가져갈 수 있도록 모든 출처를 허용하고, 브라우저/CDN도 캐시하도록 함
+	c.Header("Access-Control-Allow-Origin", "*")
+	c.Writer.Header().Del("Access-Control-Allow-Credentials")
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(statsservice.CacheTTL().Seconds())))
+
+	c.JSON(http.StatusOK, stats)
+}
@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"strings"
+
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/logger"
+	"vm-controller/internal/middleware"
+
+	gin "github.com/gin-gonic/gin"
+	yaml "sigs.k8s.io/yaml"
+)
+
+// APIPrefix는 현재 API 버전의 경로입니다. 버전 없는 /api 경로는 폐기 예정으로 같은 핸들러를 제공합니다.
+const APIPrefix = "/api/v1"
+
+// apiURL은 응답에 넣을 API 경로를 요청이 들어온 경로와 같은 버전으로 만듭니다. (path는 "/vm/..." 형식)
+func apiURL(c *gin.Context, path string) string {
+	if strings.HasPrefix(c.FullPath(), APIPrefix+"/") {
+		return APIPrefix + path
+	}
+	return "/api" + path
+}
+
+// wantsYAML은 Accept 헤더가 YAML 응답을 요청하는지 확인합니다.
+// (kubectl이나 문서에 그대로 붙여넣을 수 있도록 application/yaml, application/x-yaml, text/yaml 허용)
+func wantsYAML(c *gin.Context) bool {
+	for _, part := range strings.Split(c.GetHeader("Accept"), ",") {
+		mediaType := strings.TrimSpace(strings.SplitN(part, ";", 2)[0])
+		switch strings.ToLower(mediaType) {
+		case "application/yaml", "application/x-yaml", "text/yaml":
+			return true
+		}
+	}
+	return false
+}
+
+// respondNegotiated는 Accept 헤더에 따라 obj를 YAML 또는 JSON으로 응답합니다.
+// YAML은 JSON 태그를 그대로 따르므로 두 형식의 필드 이름이 같습니다.
+func respondNegotiated(c *gin.Context, code int, obj interface{}) {
+	c.Header("Vary", "Accept")
+	if !wantsYAML(c) {
+		c.JSON(code, obj)
+		return
+	}
+
+	out, err := yaml.Marshal(obj)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("failed to marshal yaml response", "error", err)
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to encode yaml"))
+		return
+	}
+
+	c.Data(code, "application/yaml; charset=utf-8", out)
+}
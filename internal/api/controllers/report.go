@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	http "net/http"
+	"slices"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/logger"
+	"vm-controller/internal/middleware"
+	"vm-controller/internal/models"
+	auditservice "vm-controller/internal/services/audit_service"
+	reportservice "vm-controller/internal/services/report_service"
+
+	gin "github.com/gin-gonic/gin"
+	cast "github.com/spf13/cast"
+)
+
+// 신고 내용 최대 길이
+const maxReportDescription = 2000
+
+type ReportController struct {
+	reportService *reportservice.ReportService
+	auditService  *auditservice.AuditService
+}
+
+func NewReportController(reportService *reportservice.ReportService, auditService *auditservice.AuditService) *ReportController {
+	return &ReportController{
+		reportService: reportService,
+		auditService:  auditService,
+	}
+}
+
+func (rC *ReportController) RegisterRoutes(r *gin.RouterGroup) {
+	report := r.Group("/reports", middleware.AuthGuard(), middleware.Idempotency())
+
+	report.POST("", rC.CreateReport)
+	report.GET("", rC.FetchReports)
+}
+
+// CreateReport는 스팸, 채굴, 피싱 등에 사용되는 VM/웹 배포를 신고합니다. 관리자에게 알림이 전달됩니다.
+// POST /api/reports {"category": "phishing", "hostname": "login.example.hy3on.site", "description": "..."}
+func (rC *ReportController) CreateReport(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	reporterId, err := cast.ToUintE(user_id)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid user_id"))
+		return
+	}
+
+	var req reportservice.CreateReportParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+	if !slices.Contains(models.ReportCategories, req.Category) {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "category must be one of spam, crypto_mining, phishing, other"))
+		return
+	}
+	if req.VmName == "" && req.Hostname == "" {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "vm_name or hostname is required"))
+		return
+	}
+	if len(req.Description) > maxReportDescription {
+		middleware.AbortWithError(c, apperrors.Newf(apperrors.CodeInvalidRequest, "description must be at most %d characters", maxReportDescription))
+		return
+	}
+
+	report, err := rC.reportService.CreateReport(reporterId, req)
+	switch {
+	case errors.Is(err, reportservice.ErrReportTargetNotFound):
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeNotFound))
+		return
+	case errors.Is(err, reportservice.ErrReportExists):
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeConflict))
+		return
+	case errors.Is(err, reportservice.ErrTooManyReports):
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeRateLimited))
+		return
+	case err != nil:
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to create report"))
+		return
+	}
+
+	target := report.TargetType + "/" + report.TargetName
+	if err := rC.auditService.Record(&reporterId, "report.create", target, fmt.Sprintf("report #%d: %s", report.ID, report.Category)); err != nil {
+		logger.FromContext(c.Request.Context()).Warn("failed to record audit log", "report_id", report.ID, "error", err)
+	}
+	rC.reportService.NotifyAdmins(report)
+
+	// 신고자에게는 대상 소유자 등 내부 정보를 노출하지 않음
+	c.JSON(http.StatusCreated, gin.H{"report": gin.H{
+		"id":         report.ID,
+		"category":   report.Category,
+		"status":     report.Status,
+		"created_at": report.CreatedAt,
+	}})
+}
+
+// FetchReports는 사용자가 접수한 신고와 처리 상태를 반환합니다.
+// GET /api/reports
+func (rC *ReportController) FetchReports(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	reporterId, err := cast.ToUintE(user_id)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid user_id"))
+		return
+	}
+
+	reports, err := rC.reportService.FetchUserReports(reporterId)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch reports"))
+		return
+	}
+
+	items := make([]gin.H, 0, len(reports))
+	for _, report := range reports {
+		items = append(items, gin.H{
+			"id":          report.ID,
+			"category":    report.Category,
+			"hostname":    report.Hostname,
+			"description": report.Description,
+			"status":      report.Status,
+			"created_at":  report.CreatedAt,
+			"updated_at":  report.UpdatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reports": items})
+}
@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	http "net/http"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/middleware"
+	resourceservice "vm-controller/internal/services/resource_service"
+
+	gin "github.com/gin-gonic/gin"
+	cast "github.com/spf13/cast"
+)
+
+type ResourceController struct {
+	resourceService *resourceservice.ResourceService
+}
+
+func NewResourceController(resourceService *resourceservice.ResourceService) *ResourceController {
+	return &ResourceController{
+		resourceService: resourceService,
+	}
+}
+
+func (rC *ResourceController) RegisterRoutes(r *gin.RouterGroup) {
+	r.GET("/resources", middleware.AuthGuard(), rC.FetchResources)
+}
+
+// FetchResources는 VM, 배포, 관리형 DB 등 사용자의 모든 리소스를 한 번에 반환합니다.
+func (rC *ResourceController) FetchResources(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	page := cast.ToInt(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit := cast.ToInt(c.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	resources, err := rC.resourceService.FetchUserResources(user_id.(string), page, limit)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch resources"))
+		return
+	}
+
+	c.JSON(http.StatusOK, resources)
+}
@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"errors"
+	http "net/http"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/middleware"
+	sshkeyservice "vm-controller/internal/services/ssh_key_service"
+
+	gin "github.com/gin-gonic/gin"
+	cast "github.com/spf13/cast"
+)
+
+type SSHKeyController struct {
+	sshKeyService *sshkeyservice.SSHKeyService
+}
+
+func NewSSHKeyController(sshKeyService *sshkeyservice.SSHKeyService) *SSHKeyController {
+	return &SSHKeyController{
+		sshKeyService: sshKeyService,
+	}
+}
+
+func (kC *SSHKeyController) RegisterRoutes(r *gin.RouterGroup) {
+	sshKey := r.Group("/ssh-keys", middleware.AuthGuard(), middleware.Idempotency())
+
+	sshKey.GET("", kC.FetchKeys)
+	sshKey.POST("", kC.CreateKey)
+	sshKey.DELETE("/:id", kC.DeleteKey)
+}
+
+type CreateSSHKeyParams struct {
+	Name      string `json:"name" binding:"required"`
+	PublicKey string `json:"public_key" binding:"required"` // authorized_keys 형식 (예: ssh-ed25519 AAAA... user@host)
+}
+
+// FetchKeys는 사용자가 등록한 SSH 공개 키 목록을 반환합니다.
+// GET /api/ssh-keys
+func (kC *SSHKeyController) FetchKeys(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	keys, err := kC.sshKeyService.FetchUserKeys(user_id.(string))
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch ssh keys"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"ssh_keys": keys})
+}
+
+// CreateKey는 SSH 공개 키를 등록합니다. 등록한 키는 VM 생성 시 ssh_key_ids로 선택합니다.
+// POST /api/ssh-keys
+func (kC *SSHKeyController) CreateKey(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	var req CreateSSHKeyParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	key, err := kC.sshKeyService.CreateKey(cast.ToUint(user_id), req.Name, req.PublicKey)
+	if err != nil {
+		switch {
+		case errors.Is(err, sshkeyservice.ErrInvalidKey), errors.Is(err, sshkeyservice.ErrKeyLimit):
+			middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeInvalidRequest))
+		case errors.Is(err, sshkeyservice.ErrDuplicateKey):
+			middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeConflict))
+		default:
+			middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to register ssh key"))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"ssh_key": key})
+}
+
+// DeleteKey는 SSH 공개 키를 삭제합니다. 이미 생성된 VM의 authorized_keys에는 남아 있습니다.
+// DELETE /api/ssh-keys/:id
+func (kC *SSHKeyController) DeleteKey(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	if err := kC.sshKeyService.DeleteKey(user_id.(string), cast.ToUint(c.Param("id"))); err != nil {
+		if errors.Is(err, sshkeyservice.ErrKeyNotFound) {
+			middleware.AbortWithError(c, apperrors.New(apperrors.CodeSSHKeyNotFound, "SSH key not found"))
+			return
+		}
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to delete ssh key"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "SSH key deleted"})
+}
@@ -0,0 +1,41 @@
+package controllers
+
+import (
+	http "net/http"
+	"os"
+	consoleservice "vm-controller/internal/services/console_service"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+type TermsController struct {
+	consoleService *consoleservice.ConsoleService
+}
+
+func NewTermsController(consoleService *consoleservice.ConsoleService) *TermsController {
+	return &TermsController{
+		consoleService: consoleService,
+	}
+}
+
+func (tC *TermsController) RegisterRoutes(r *gin.RouterGroup) {
+	r.GET("/terms", tC.FetchTerms)
+}
+
+// FetchTerms는 이용 약관 위치와 현재 적용 중인 운영 정책 고지를 반환합니다. (로그인 없이 조회 가능)
+func (tC *TermsController) FetchTerms(c *gin.Context) {
+	notices := []gin.H{}
+
+	if tC.consoleService.RecordingEnabled() {
+		notices = append(notices, gin.H{
+			"key":            "console_recording",
+			"message":        "플랫폼을 통해 연 VM 콘솔 세션의 접속 기록(사용자, 접속 IP, 시작/종료 시각)이 저장됩니다. 입력 내용은 기록되지 않습니다.",
+			"retention_days": int(tC.consoleService.Retention().Hours() / 24),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"terms_url": os.Getenv("TERMS_URL"),
+		"notices":   notices,
+	})
+}
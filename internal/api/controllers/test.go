@@ -1,40 +1,42 @@
+//go:build !release
+
 package controllers
 
 import (
+	"fmt"
 	"net/http"
-	"sync"
-	k8s "vm-controller/internal/services/k8s_service"
-
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/config"
+	"vm-controller/internal/logger"
+	"vm-controller/internal/middleware"
 	"vm-controller/internal/models"
-
-	"os"
+	"vm-controller/internal/policy"
+	auditservice "vm-controller/internal/services/audit_service"
+	k8s "vm-controller/internal/services/k8s_service"
+	vm_service "vm-controller/internal/services/vm_service"
 
 	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
 )
 
+// TestController는 DB 기록 없이 클러스터에 VM 리소스만 만들고 지우는 관리자용 샌드박스 도구입니다.
+// sandbox-tools 기능 플래그가 켜져 있을 때만 응답하며, release 빌드 태그로 빌드하면 포함되지 않습니다. (test_release.go)
 type TestController struct {
+	provisioner  k8s.K8sProvisioner
+	vmService    *vm_service.VmService
+	auditService *auditservice.AuditService
 }
 
-var (
-	testController *TestController
-	onceTest       sync.Once
-)
-
-func GetTestController() *TestController {
-	onceTest.Do(func() {
-		testController = &TestController{}
-	})
-
-	return testController
+func NewTestController(provisioner k8s.K8sProvisioner, vmService *vm_service.VmService, auditService *auditservice.AuditService) *TestController {
+	return &TestController{
+		provisioner:  provisioner,
+		vmService:    vmService,
+		auditService: auditService,
+	}
 }
 
 func (t *TestController) RegisterRoutes(group *gin.RouterGroup) {
-	mode := os.Getenv("GIN_MODE")
-	if mode == "release" {
-		return
-	}
-
-	g := group.Group("/test")
+	g := group.Group("/test", middleware.FeatureGuard(config.FeatureSandboxTools), middleware.AuthGuard(), middleware.Authorize(policy.ResourceSandbox), middleware.RequireCapability("vms", k8s.VMFeaturesAvailable))
 	g.POST("/create-vm", t.TestCreateVM)
 	g.POST("/delete-vm", t.TestDeleteVM)
 }
@@ -44,41 +46,68 @@ type testCreateVMRequest struct {
 	VmName        string `json:"vmName"`
 	Password      string `json:"password"`
 	DnsHost       string `json:"dnsHost"`
-	VmPort        int32  `json:"vmPort"`
+	VmPort        int32  `json:"vmPort"` // 비어 있으면 할당기에서 빈 NodePort를 받음
 }
 
+// recordSandboxAudit는 샌드박스 도구 사용을 감사 로그에 남깁니다.
+func (t *TestController) recordSandboxAudit(c *gin.Context, action, target, detail string) {
+	user_id, _ := c.Get("user_id")
+
+	actorId, err := cast.ToUintE(user_id)
+	if err != nil {
+		return
+	}
+	if err := t.auditService.Record(&actorId, action, target, detail); err != nil {
+		logger.FromContext(c.Request.Context()).Warn("failed to record audit log", "target", target, "error", err)
+	}
+}
+
+// TestCreateVM은 VM 리소스를 클러스터에만 생성합니다. (DB에 기록하지 않으므로 사용 후 delete-vm으로 정리)
+// POST /api/test/create-vm
 func (t *TestController) TestCreateVM(c *gin.Context) {
 	var req testCreateVMRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeInvalidRequest))
 		return
 	}
 
-	service, err := k8s.GetK8sService()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	port := int(req.VmPort)
+	if port == 0 {
+		available, err := t.vmService.GetAvailablePort()
+		if err != nil {
+			middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeUnavailable))
+			return
+		}
+		port = available
+	} else if available, err := t.vmService.IsPortAvailable(port); err != nil || !available {
+		middleware.AbortWithError(c, apperrors.Newf(apperrors.CodePortInUse, "port %d is already in use", port))
 		return
 	}
 
-	vminfo, err := service.CreateUserVM(req.UserNamespace, req.VmName, req.Password, req.DnsHost, "yaml-data/client-vm", 30005)
+	vminfo, err := t.provisioner.CreateUserVM(req.UserNamespace, req.VmName, req.Password, req.DnsHost, "yaml-data/client-vm", k8s.DefaultFlavor, k8s.DefaultImage, int32(port), nil, nil, "")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		t.recordSandboxAudit(c, "sandbox.create-vm.failed", "vm/"+req.UserNamespace+"/"+req.VmName, err.Error())
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeInternal))
 		return
 	}
 
+	t.recordSandboxAudit(c, "sandbox.create-vm", "vm/"+req.UserNamespace+"/"+req.VmName, fmt.Sprintf("port=%d dns_host=%s", port, req.DnsHost))
+
 	c.JSON(http.StatusOK, gin.H{"vmInfo": vminfo})
 }
 
+// TestDeleteVM은 샌드박스로 만든 VM 리소스를 삭제합니다.
+// DB에 기록된 사용자 VM은 상태 기록이 어긋나지 않도록 거부합니다. (관리자 API로 삭제)
+// POST /api/test/delete-vm
 func (t *TestController) TestDeleteVM(c *gin.Context) {
 	var req testCreateVMRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeInvalidRequest))
 		return
 	}
 
-	service, err := k8s.GetK8sService()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if record, _ := t.vmService.WithContext(c.Request.Context()).FetchVmName(req.VmName, false); record != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeConflict, "VM is managed by the API. Use DELETE /api/admin/vms/:name instead"))
 		return
 	}
 
@@ -88,11 +117,14 @@ func (t *TestController) TestDeleteVM(c *gin.Context) {
 		UserID:    0,
 	}
 
-	err = service.DeleteVM(&vm)
+	err := t.provisioner.DeleteVM(&vm)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		t.recordSandboxAudit(c, "sandbox.delete-vm.failed", "vm/"+req.UserNamespace+"/"+req.VmName, err.Error())
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeInternal))
 		return
 	}
 
+	t.recordSandboxAudit(c, "sandbox.delete-vm", "vm/"+req.UserNamespace+"/"+req.VmName, "")
+
 	c.JSON(http.StatusOK, gin.H{"message": "VM deleted successfully"})
 }
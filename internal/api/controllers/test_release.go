@@ -0,0 +1,21 @@
+//go:build release
+
+package controllers
+
+import (
+	auditservice "vm-controller/internal/services/audit_service"
+	k8s_service "vm-controller/internal/services/k8s_service"
+	vm_service "vm-controller/internal/services/vm_service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// release 빌드에는 샌드박스 도구를 포함하지 않습니다. (test.go)
+type TestController struct{}
+
+func NewTestController(provisioner k8s_service.K8sProvisioner, vmService *vm_service.VmService, auditService *auditservice.AuditService) *TestController {
+	return &TestController{}
+}
+
+// sandbox-tools 기능 플래그를 켜도 라우트가 없으므로 404
+func (t *TestController) RegisterRoutes(group *gin.RouterGroup) {}
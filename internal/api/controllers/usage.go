@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	http "net/http"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/middleware"
+	k8s_service "vm-controller/internal/services/k8s_service"
+	quotaservice "vm-controller/internal/services/quota_service"
+	userservice "vm-controller/internal/services/user_service"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+type UsageController struct {
+	k8sService   *k8s_service.K8sService
+	userService  *userservice.UserService
+	quotaService *quotaservice.QuotaService
+}
+
+func NewUsageController(k8sService *k8s_service.K8sService, userService *userservice.UserService, quotaService *quotaservice.QuotaService) *UsageController {
+	return &UsageController{
+		k8sService:   k8sService,
+		userService:  userService,
+		quotaService: quotaService,
+	}
+}
+
+func (uC *UsageController) RegisterRoutes(r *gin.RouterGroup) {
+	usage := r.Group("/usage", middleware.AuthGuard())
+
+	usage.GET("/storage", uC.FetchStorageUsage)
+	usage.GET("/quota", uC.FetchQuota)
+}
+
+// FetchStorageUsage는 사용자의 스토리지 쿼터 현황과 PVC별 실제 사용량을 반환합니다.
+// 쿼터의 80% 이상을 사용 중이면 near_quota와 경고 문구를 함께 반환합니다.
+func (uC *UsageController) FetchStorageUsage(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	user, err := uC.userService.WithContext(c.Request.Context()).FetchUserById(user_id.(string), true)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeUserNotFound, "User not found"))
+		return
+	}
+
+	summary, err := uC.quotaService.StorageSummary(user.ID)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to calculate storage usage"))
+		return
+	}
+
+	// 클러스터 조회 실패 시에도 쿼터 현황은 반환
+	storage, err := uC.k8sService.WithContext(c.Request.Context()).GetNamespaceStorage(user.Namespace)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{"quota": summary, "cluster": nil, "error": "Failed to read cluster storage usage"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"quota": summary, "cluster": storage})
+}
+
+// FetchQuota는 차원별(스토리지, VM 수, DB 수) 쿼터의 hard cap, soft 임계치, 사용량을 반환합니다.
+func (uC *UsageController) FetchQuota(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	user, err := uC.userService.WithContext(c.Request.Context()).FetchUserById(user_id.(string), true)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeUserNotFound, "User not found"))
+		return
+	}
+
+	headrooms, err := uC.quotaService.Headroom(user.ID, nil)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to calculate quota"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"quota": headrooms})
+}
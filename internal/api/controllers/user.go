@@ -3,30 +3,27 @@ package controllers
 import (
 	"net/http"
 	"strings"
-	"sync"
+	"vm-controller/internal/apperrors"
 	"vm-controller/internal/middleware"
+	"vm-controller/internal/services/k8s_service"
+	notificationservice "vm-controller/internal/services/notification_service"
 	userservice "vm-controller/internal/services/user_service"
 
 	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
 )
 
 type UserController struct {
 	userService *userservice.UserService
+	k8sService  *k8s_service.K8sService
 }
 
-var (
-	userController *UserController
-	userOnce       sync.Once
-)
-
-// GetUserController returns the singleton instance of UserController
-func GetUserController() *UserController {
-	userOnce.Do(func() {
-		userController = &UserController{
-			userService: userservice.GetUserService(),
-		}
-	})
-	return userController
+// NewUserController creates a UserController with the given services
+func NewUserController(userService *userservice.UserService, k8sService *k8s_service.K8sService) *UserController {
+	return &UserController{
+		userService: userService,
+		k8sService:  k8sService,
+	}
 }
 
 // RegisterRoutes registers the user-related routes
@@ -39,6 +36,12 @@ func (c *UserController) RegisterRoutes(group *gin.RouterGroup) {
 
 		// 내 정보 조회 (Get My Info) - Auth 미들웨어 필요하다고 가정
 		userGroup.GET("/me", c.GetMe, middleware.AuthGuard())
+
+		// 내 네임스페이스의 Pod Security 레벨 조회
+		userGroup.GET("/me/pod-security", middleware.AuthGuard(), c.GetMyPodSecurity)
+
+		// 알림 언어 변경
+		userGroup.PUT("/me/locale", middleware.AuthGuard(), c.UpdateMyLocale)
 	}
 }
 
@@ -55,7 +58,7 @@ type CreateUserRequest struct {
 func (c *UserController) CreateUser(ctx *gin.Context) {
 	var req CreateUserRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": "잘못된 요청 형식입니다."})
+		middleware.AbortWithError(ctx, apperrors.New(apperrors.CodeInvalidRequest, "잘못된 요청 형식입니다.").WithDetail("reason", err.Error()))
 		return
 	}
 
@@ -70,10 +73,10 @@ func (c *UserController) CreateUser(ctx *gin.Context) {
 	if err != nil {
 		// 중복 에러 등 세분화 가능
 		if strings.Contains(err.Error(), "duplicate") {
-			ctx.JSON(http.StatusConflict, gin.H{"error": "User already exists", "message": "이미 존재하는 학번 또는 ID입니다."})
+			middleware.AbortWithError(ctx, apperrors.New(apperrors.CodeNameTaken, "이미 존재하는 학번 또는 ID입니다."))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": "유저 생성 실패"})
+		middleware.AbortWithError(ctx, apperrors.New(apperrors.CodeInternal, "유저 생성 실패").Wrap(err))
 		return
 	}
 
@@ -91,12 +94,63 @@ func (c *UserController) CreateUser(ctx *gin.Context) {
 func (c *UserController) GetMe(ctx *gin.Context) {
 	user_id, _ := ctx.Get("user_id")
 
-	user, err := c.userService.FetchUserById(user_id.(string), true)
+	user, err := c.userService.WithContext(ctx.Request.Context()).FetchUserById(user_id.(string), true)
 
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found", "message": "유저를 찾을 수 없습니다."})
+		middleware.AbortWithError(ctx, apperrors.New(apperrors.CodeUserNotFound, "유저를 찾을 수 없습니다."))
 		return
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{"user": user})
 }
+
+// GetMyPodSecurity handles fetching the Pod Security Standards level of the current user's namespace
+func (c *UserController) GetMyPodSecurity(ctx *gin.Context) {
+	user_id, _ := ctx.Get("user_id")
+
+	user, err := c.userService.WithContext(ctx.Request.Context()).FetchUserById(user_id.(string), true)
+	if err != nil {
+		middleware.AbortWithError(ctx, apperrors.New(apperrors.CodeUserNotFound, "유저를 찾을 수 없습니다."))
+		return
+	}
+
+	level, err := c.k8sService.WithContext(ctx.Request.Context()).GetPodSecurityLevel(user.Namespace)
+	if err != nil {
+		middleware.AbortWithError(ctx, apperrors.New(apperrors.CodeInternal, "Failed to fetch pod security level"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"namespace": user.Namespace, "level": level})
+}
+
+type UpdateLocaleRequest struct {
+	Locale string `json:"locale"` // ko, en (비우면 기본 언어)
+}
+
+// UpdateMyLocale handles changing the language of the current user's notifications
+func (c *UserController) UpdateMyLocale(ctx *gin.Context) {
+	user_id, _ := ctx.Get("user_id")
+
+	var req UpdateLocaleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(ctx, apperrors.New(apperrors.CodeInvalidRequest, "잘못된 요청 형식입니다."))
+		return
+	}
+	if req.Locale != "" && !notificationservice.IsLocale(req.Locale) {
+		middleware.AbortWithError(ctx, apperrors.New(apperrors.CodeInvalidRequest, "지원하지 않는 언어입니다.").WithDetail("locales", notificationservice.Locales))
+		return
+	}
+
+	userId, err := cast.ToUintE(user_id)
+	if err != nil {
+		middleware.AbortWithError(ctx, apperrors.New(apperrors.CodeInvalidRequest, "Invalid user_id"))
+		return
+	}
+
+	if err := c.userService.WithContext(ctx.Request.Context()).UpdateLocale(userId, req.Locale); err != nil {
+		middleware.AbortWithError(ctx, apperrors.New(apperrors.CodeUserNotFound, "유저를 찾을 수 없습니다."))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"locale": req.Locale})
+}
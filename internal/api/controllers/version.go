@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	http "net/http"
+	"vm-controller/internal/middleware"
+	k8s_service "vm-controller/internal/services/k8s_service"
+	"vm-controller/internal/version"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+type VersionController struct {
+	k8sService *k8s_service.K8sService
+}
+
+func NewVersionController(k8sService *k8s_service.K8sService) *VersionController {
+	return &VersionController{
+		k8sService: k8sService,
+	}
+}
+
+func (vC *VersionController) RegisterRoutes(r *gin.RouterGroup) {
+	r.GET("/version", middleware.AuthGuard(), vC.FetchVersion)
+}
+
+// FetchVersion은 컨트롤러 빌드 정보, 매니페스트 번들 버전, 클러스터 구성 요소 버전을 반환합니다.
+// 문의 시 실행 환경을 정확히 전달할 수 있도록 사용자에게도 제공합니다.
+// GET /api/version
+func (vC *VersionController) FetchVersion(c *gin.Context) {
+	cluster := vC.k8sService.GetClusterVersions(c.Request.Context())
+
+	c.JSON(http.StatusOK, gin.H{
+		"build":                  version.Build(),
+		"manifest_bundle":        version.Bundle(version.ManifestBundleDir),
+		"supported_api_versions": k8s_service.SupportedAPIVersions,
+		"cluster":                cluster,
+	})
+}
@@ -1,65 +1,198 @@
 package controllers
 
 import (
+	"errors"
 	http "net/http"
-	"os"
-	"regexp"
-	sync "sync"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/config"
 	"vm-controller/internal/middleware"
+	"vm-controller/internal/models"
+	approvalservice "vm-controller/internal/services/approval_service"
+	auditservice "vm-controller/internal/services/audit_service"
+	bundleservice "vm-controller/internal/services/bundle_service"
+	consoleservice "vm-controller/internal/services/console_service"
+	flavorchangeservice "vm-controller/internal/services/flavor_change_service"
+	flavorservice "vm-controller/internal/services/flavor_service"
+	imageservice "vm-controller/internal/services/image_service"
 	k8s_service "vm-controller/internal/services/k8s_service"
+	networkservice "vm-controller/internal/services/network_service"
+	preferenceservice "vm-controller/internal/services/preference_service"
+	quotaservice "vm-controller/internal/services/quota_service"
+	reportservice "vm-controller/internal/services/report_service"
+	snapshotservice "vm-controller/internal/services/snapshot_service"
+	sshkeyservice "vm-controller/internal/services/ssh_key_service"
 	userservice "vm-controller/internal/services/user_service"
+	vmeventservice "vm-controller/internal/services/vm_event_service"
+	vmlifecycleservice "vm-controller/internal/services/vm_lifecycle_service"
 	vm_service "vm-controller/internal/services/vm_service"
+	volumeservice "vm-controller/internal/services/volume_service"
 
 	gin "github.com/gin-gonic/gin"
 	cast "github.com/spf13/cast"
+	"gorm.io/gorm"
 )
 
 type VirtualMachineController struct {
 	k8sService  *k8s_service.K8sService
-	userService *userservice.UserService
-	vmService   *vm_service.VmService
+	provisioner k8s_service.K8sProvisioner // VM 생성/시작/정지/삭제 (lifecycleService와 같은 인스턴스)
+
+	approvalService     *approvalservice.ApprovalService
+	auditService        *auditservice.AuditService
+	bundleService       *bundleservice.BundleService
+	consoleService      *consoleservice.ConsoleService
+	flavorChangeService *flavorchangeservice.FlavorChangeService
+	flavorService       *flavorservice.FlavorService
+	imageService        *imageservice.ImageService
+	networkService      *networkservice.NetworkService
+	preferenceService   *preferenceservice.PreferenceService
+	quotaService        *quotaservice.QuotaService
+	reportService       *reportservice.ReportService
+	snapshotService     *snapshotservice.SnapshotService
+	sshKeyService       *sshkeyservice.SSHKeyService
+	userService         *userservice.UserService
+	vmEventService      *vmeventservice.VmEventService
+	vmService           *vm_service.VmService
+	volumeService       *volumeservice.VolumeService
+	lifecycleService    *vmlifecycleservice.VmLifecycleService
 }
 
-var (
-	virtualMachineController *VirtualMachineController
-	onceVM                   sync.Once
-)
-
 func (vmC *VirtualMachineController) RegisterRoutes(r *gin.RouterGroup) {
-	vm := r.Group("/vm", middleware.AuthGuard())
+	// KubeVirt/CDI가 설치되지 않은 클러스터에서는 VM API 전체를 501로 거부 (사용자/배포 API는 계속 제공)
+	vm := r.Group("/vm", middleware.AuthGuard(), middleware.RequireCapability("vms", k8s_service.VMFeaturesAvailable), middleware.Idempotency())
 
 	vm.POST("/create", vmC.CreateVM)
+	vm.POST("/preflight", vmC.PreflightVM)
 	vm.GET("/fetch", vmC.FetchUserVMs)
 	vm.POST("/stop", vmC.StopVM)
 	vm.DELETE("/delete", vmC.DeleteVM)
 	vm.POST("/start", vmC.StartVM)
+	vm.POST("/restart", vmC.RestartVM)
+	vm.POST("/pause", vmC.PauseVM)
+	vm.POST("/unpause", vmC.UnpauseVM)
+
+	vm.POST("/snapshot", vmC.CreateSnapshot)
+	vm.GET("/snapshot", vmC.FetchSnapshots)
+	vm.POST("/snapshot/restore", vmC.RestoreSnapshot)
+	vm.DELETE("/snapshot", vmC.DeleteSnapshot)
+
+	vm.POST("/volume/attach", vmC.AttachVolume)
+	vm.POST("/volume/detach", vmC.DetachVolume)
+	vm.GET("/volume", vmC.FetchVolumes)
+
+	vm.POST("/export", vmC.CreateExport)
+	vm.GET("/export", vmC.FetchExport)
+	vm.GET("/export/download", vmC.DownloadExport)
+
+	vm.POST("/upload", vmC.CreateUploadVM)
+	vm.GET("/upload", vmC.FetchUpload)
+	vm.POST("/upload/complete", vmC.CompleteUpload)
+
+	vm.GET("/networks", vmC.FetchNetworks)
+	vm.GET("/flavors", vmC.FetchFlavors)
+	vm.GET("/images", vmC.FetchImages)
+	vm.GET("/defaults", vmC.FetchVMDefaults)
+	vm.PUT("/defaults", vmC.UpdateVMDefaults)
+	vm.GET("/approvals", vmC.FetchApprovals)
+	vm.GET("/:name", vmC.FetchVM)
+	vm.GET("/:name/manifests", vmC.FetchManifests)
+	vm.GET("/:name/connection", vmC.FetchConnection)
+	vm.POST("/:name/credentials", vmC.RevealCredentials)
+	vm.POST("/:name/extend", vmC.ExtendLease)
+	vm.POST("/:name/flavor/preview", vmC.PreviewFlavorChange)
+	vm.POST("/:name/flavor", vmC.ChangeFlavor)
+	vm.GET("/:name/flavor/changes", vmC.FetchFlavorChanges)
+	vm.DELETE("/:name/flavor/changes/:id", vmC.CancelFlavorChange)
+	vm.GET("/:name/metrics", vmC.FetchMetrics)
+	vm.GET("/:name/events", vmC.FetchVMEvents)
+	vm.POST("/:name/delete-confirmation", vmC.CreateDeleteConfirmation)
+	vm.GET("/:name/console", vmC.OpenConsole)
+
+	// 디스크 이미지 청크는 Idempotency 미들웨어가 본문을 메모리에 올리지 않도록 별도 그룹에 등록 (오프셋 지정 PUT이라 재시도해도 안전)
+	stream := r.Group("/vm", middleware.AuthGuard(), middleware.RequireCapability("vms", k8s_service.VMFeaturesAvailable))
+	stream.PUT("/upload/chunk", vmC.UploadChunk)
 }
 
-func GetVirtualMachineController() *VirtualMachineController {
-	//Sync Once 는 다른 고루틴이 once.Do를 빠져 나오지 못하도록 설계되어 있음.
-	onceVM.Do(func() {
-		k8s_service, err := k8s_service.GetK8sService()
+// VirtualMachineDependencies는 VirtualMachineController가 사용하는 서비스입니다.
+type VirtualMachineDependencies struct {
+	K8sService  *k8s_service.K8sService
+	Provisioner k8s_service.K8sProvisioner
+
+	ApprovalService     *approvalservice.ApprovalService
+	AuditService        *auditservice.AuditService
+	BundleService       *bundleservice.BundleService
+	ConsoleService      *consoleservice.ConsoleService
+	FlavorChangeService *flavorchangeservice.FlavorChangeService
+	FlavorService       *flavorservice.FlavorService
+	ImageService        *imageservice.ImageService
+	NetworkService      *networkservice.NetworkService
+	PreferenceService   *preferenceservice.PreferenceService
+	QuotaService        *quotaservice.QuotaService
+	ReportService       *reportservice.ReportService
+	SnapshotService     *snapshotservice.SnapshotService
+	SSHKeyService       *sshkeyservice.SSHKeyService
+	UserService         *userservice.UserService
+	VmEventService      *vmeventservice.VmEventService
+	VmService           *vm_service.VmService
+	VolumeService       *volumeservice.VolumeService
+	LifecycleService    *vmlifecycleservice.VmLifecycleService
+}
 
-		if err != nil {
-			// Injection 에러가 일어남.
-			panic(err)
-		}
+func NewVirtualMachineController(deps VirtualMachineDependencies) *VirtualMachineController {
+	return &VirtualMachineController{
+		k8sService:  deps.K8sService,
+		provisioner: deps.Provisioner,
+
+		approvalService:     deps.ApprovalService,
+		auditService:        deps.AuditService,
+		bundleService:       deps.BundleService,
+		consoleService:      deps.ConsoleService,
+		flavorChangeService: deps.FlavorChangeService,
+		flavorService:       deps.FlavorService,
+		imageService:        deps.ImageService,
+		networkService:      deps.NetworkService,
+		preferenceService:   deps.PreferenceService,
+		quotaService:        deps.QuotaService,
+		reportService:       deps.ReportService,
+		snapshotService:     deps.SnapshotService,
+		sshKeyService:       deps.SSHKeyService,
+		userService:         deps.UserService,
+		vmEventService:      deps.VmEventService,
+		vmService:           deps.VmService,
+		volumeService:       deps.VolumeService,
+		lifecycleService:    deps.LifecycleService,
+	}
+}
 
-		virtualMachineController = &VirtualMachineController{
-			k8sService: k8s_service,
-		}
-	})
+// respondLifecycleError는 VM 수명 주기 서비스의 에러를 유형에 맞는 상태 코드와 에러 코드로 응답합니다.
+func respondLifecycleError(c *gin.Context, err error, fallback string) {
+	status := http.StatusInternalServerError
+	switch vmlifecycleservice.KindOf(err) {
+	case vmlifecycleservice.KindInvalid:
+		status = http.StatusBadRequest
+	case vmlifecycleservice.KindNotFound:
+		status = http.StatusNotFound
+	case vmlifecycleservice.KindForbidden:
+		status = http.StatusForbidden
+	case vmlifecycleservice.KindConflict:
+		status = http.StatusConflict
+	case vmlifecycleservice.KindUnavailable:
+		status = http.StatusServiceUnavailable
+	case vmlifecycleservice.KindTimeout:
+		status = http.StatusGatewayTimeout
+	case vmlifecycleservice.KindBusy:
+		retryAfter, reason := vmlifecycleservice.RetryAfterOf(err)
+		middleware.AbortServerBusy(c, reason, retryAfter)
+		return
+	}
 
-	// 무조건 할당되어 있습니다.
-	return virtualMachineController
+	middleware.AbortWithError(c, apperrors.New(vmlifecycleservice.CodeOf(err), vmlifecycleservice.MessageOf(err, fallback)).WithStatus(status))
 }
 
-type CreateVMParams struct {
-	VmName        string `json:"vm_name"`
-	VmSSHPassword string `json:"vm_ssh_password"`
-	VmImage       string `json:"vm_image"`
-	VmHostPrefix  string `json:"vm_host_prefix"`
-}
+// 생성된 비밀번호는 다시 조회할 수 없음을 알리는 안내 문구
+const generatedPasswordNotice = "This password is shown only once. Store it securely."
+
+// CreateVMParams는 VM 생성 요청 본문입니다. (필드 설명은 vmlifecycleservice.CreateParams)
+type CreateVMParams = vmlifecycleservice.CreateParams
 
 func (vmC *VirtualMachineController) CreateVM(c *gin.Context) {
 	var req CreateVMParams
@@ -67,72 +200,169 @@ func (vmC *VirtualMachineController) CreateVM(c *gin.Context) {
 	user_id, _ := c.Get("user_id")
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	user, err := vmC.userService.WithContext(c.Request.Context()).FetchUserById(user_id.(string), true)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && user == nil) {
+		// 토큰 발급 이후 삭제된 계정
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeUnauthenticated, "User not found"))
+		return
+	}
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch user"))
+		return
+	}
+
+	if err := vmC.lifecycleService.ApplyDefaults(user.ID, &req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to load vm defaults"))
+		return
+	}
+
+	response, code, ok := vmC.createVM(c, user, req, bundleservice.VMTemplate)
+	if !ok {
 		return
 	}
 
-	user, _ := vmC.userService.FetchUserById(user_id.(string), true)
+	c.JSON(code, response)
+}
 
-	signed_port, err := vmC.vmService.GetAvailablePort()
+// PreflightVM은 VM을 생성하지 않고 생성 요청을 검사하여, 실패 사유와 차원별 쿼터 여유량을 반환합니다.
+// soft 임계치에 도달하는 요청은 허용되지만 quota 항목에 경고가 포함됩니다.
+// POST /api/vm/preflight
+func (vmC *VirtualMachineController) PreflightVM(c *gin.Context) {
+	var req CreateVMParams
 
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get available port"})
+	user_id, _ := c.Get("user_id")
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
 		return
 	}
 
-	// VmHostPrefix가 유효한 도메인 형식(예: prefix.domain.com)인지 검사합니다.
-	// 도메인 네임으로 사용될 것이므로 DNS 규약을 준수해야 합니다.
-	if matched, _ := regexp.MatchString(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)+$`, req.VmHostPrefix); !matched {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "VmHostPrefix must be in a valid domain format (e.g., prefix.domain.com)"})
+	user, err := vmC.userService.WithContext(c.Request.Context()).FetchUserById(user_id.(string), true)
+	if err != nil || user == nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeUserNotFound, "User not found"))
 		return
 	}
 
-	hostname := req.VmHostPrefix + os.Getenv("HOSTNAME")
+	if err := vmC.lifecycleService.ApplyDefaults(user.ID, &req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to load vm defaults"))
+		return
+	}
 
-	vm, err := vmC.k8sService.CreateUserVM(user.Namespace,
-		req.VmName, req.VmSSHPassword, hostname, "yaml-data/client-vm", cast.ToInt32(signed_port))
+	problems := []string{}
+	if _, err := vmC.lifecycleService.ValidateCreateParams(req); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if _, err := vmC.sshKeyService.FetchUserKeysByIds(user.ID, req.SSHKeyIDs); err != nil {
+		problems = append(problems, err.Error())
+	}
+	// UserVM spec으로 표현할 수 없는 항목은 Operator 모드에서 거부됨
+	if config.Get().OperatorMode {
+		if err := vmlifecycleservice.ValidateOperatorParams(req); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
 
+	if existing, err := vmC.vmService.WithContext(c.Request.Context()).FetchVmName(req.VmName, false); err == nil && existing != nil {
+		problems = append(problems, "VM name is already in use")
+	}
+	if err := vmC.provisioner.ProvisionerWithContext(c.Request.Context()).CheckNodePoolCapacity(); errors.Is(err, k8s_service.ErrNoSchedulablePool) {
+		problems = append(problems, err.Error())
+	}
+
+	headrooms, err := vmC.quotaService.Headroom(user.ID, vmC.lifecycleService.QuotaRequest(req.VmFlavor))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create VM"})
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to calculate quota"))
 		return
 	}
 
-	//database 등록 절차를 가져야함.
-	_, err = vmC.vmService.CreateUserVM(vm_service.CreateVmParams{
-		VmName:        req.VmName,
-		VmPassword:    req.VmSSHPassword,
-		VmImage:       req.VmImage,
-		DnsHost:       hostname,
-		Namespace:     user.Namespace,
-		UserID:        user.ID,
-		VmSSHPort:     cast.ToInt32(signed_port),
+	warnings := []string{}
+	for _, h := range headrooms {
+		if h.Exceeded {
+			problems = append(problems, h.Warning)
+		} else if h.SoftHit {
+			warnings = append(warnings, h.Warning)
+		}
+	}
+
+	flavor, _ := k8s_service.GetFlavor(vmC.flavorService, req.VmFlavor)
+
+	c.JSON(http.StatusOK, gin.H{
+		"ok":                len(problems) == 0,
+		"errors":            problems,
+		"warnings":          warnings,
+		"quota":             headrooms,
+		"requires_approval": flavor.RequiresApproval(),
 	})
-	
+}
+
+// createVM은 사용자에게 배정된 매니페스트 번들(stable/canary)의 template으로 VM을 생성하고 응답 본문과 상태 코드를 만듭니다.
+// Operator 모드에서 UserVM으로 접수되었거나 승인 대기 중이면 202를 반환하며, 에러 응답을 작성한 경우에만 false를 반환합니다.
+func (vmC *VirtualMachineController) createVM(c *gin.Context, user *models.User, req CreateVMParams, template string) (gin.H, int, bool) {
+	result, err := vmC.lifecycleService.WithContext(c.Request.Context()).Create(user, req, template, c.GetString("trace_id"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create VM"})
-		return
+		respondLifecycleError(c, err, "Failed to create VM")
+		return nil, 0, false
 	}
 
-	c.JSON(http.StatusOK, gin.H{"vm": vm})
+	var response gin.H
+	switch {
+	case result.Accepted:
+		response = gin.H{"vm": gin.H{"name": result.VM.Name, "namespace": result.VM.Namespace, "dns_host": result.VM.DnsHost}}
+	case result.Approval != nil:
+		response = gin.H{"approval": result.Approval, "status": result.Approval.Status}
+	default:
+		response = gin.H{"vm": newVMResponse(result.VM)}
+		if result.OperationID != 0 {
+			response["operation_id"] = result.OperationID
+		}
+	}
+	if result.Password != "" {
+		response["password"] = result.Password
+		response["password_notice"] = generatedPasswordNotice
+	}
+	if result.Accepted || result.Approval != nil {
+		return response, http.StatusAccepted, true
+	}
+	if result.Warning != "" {
+		response["warning"] = result.Warning
+	}
+
+	return response, http.StatusOK, true
 }
 
+// FetchUserVMs는 사용자의 VM 목록을 반환합니다. 페이지/상태/이름 검색/정렬 조건은 parseVMQuery를 참고하세요.
+// GET /api/vm/fetch?page=1&limit=20&status=Running&q=lab&sort=-created_at
 func (vmC *VirtualMachineController) FetchUserVMs(c *gin.Context) {
 	user_id, ok := c.Get("user_id")
 
 	if !ok {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id"})
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid user_id"))
+		return
+	}
+
+	query, err := parseVMQuery(c)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeInvalidRequest))
 		return
 	}
+	userId := cast.ToUint(user_id)
+	query.UserID = &userId
 
-	vms, err := vmC.vmService.FetchUserVMs(user_id.(string), false)
+	page, err := vmC.vmService.WithContext(c.Request.Context()).QueryVMs(query)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch VMs"})
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch VMs"))
 		return
 	}
 
 	// Password Is Not Sent To Client
-	c.JSON(http.StatusOK, gin.H{"vms": vms})
+	response := vmPageResponse(page)
+	response["vms"] = newVMResponses(page.VMs)
+	c.JSON(http.StatusOK, response)
 }
 
 type StopVMParams struct {
@@ -140,31 +370,31 @@ type StopVMParams struct {
 }
 
 func (vmC *VirtualMachineController) StopVM(c *gin.Context) {
-	user_id, _ := c.Get("user_id")
-
 	var req StopVMParams
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
 		return
 	}
 
-	u64, err := cast.ToUintE(user_id)
-
+	subject, err := middleware.RequestSubject(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id controller 121:30 Switching Error"})
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeUnauthenticated, "Unauthorized"))
 		return
 	}
 
-	vm, _ := vmC.vmService.FetchVmName(req.VmName, false)
-	if vm.UserID != u64 {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+	vm, operationID, err := vmC.lifecycleService.WithContext(c.Request.Context()).Stop(subject, req.VmName, c.GetString("trace_id"))
+	if err != nil {
+		respondLifecycleError(c, err, "Failed to update VM")
 		return
 	}
 
-	go vmC.k8sService.StopVM(vm)
-
-	c.JSON(http.StatusOK, gin.H{"vm": vm})
+	// Operator 모드는 UserVM spec만 변경하므로 작업 ID가 없음
+	response := gin.H{"vm": newVMResponse(vm)}
+	if !config.Get().OperatorMode {
+		response["operation_id"] = operationID
+	}
+	c.JSON(http.StatusOK, response)
 }
 
 type StartVMParams struct {
@@ -172,62 +402,64 @@ type StartVMParams struct {
 }
 
 func (vmC *VirtualMachineController) StartVM(c *gin.Context) {
-	user_id, _ := c.Get("user_id")
-
 	var req StartVMParams
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
 		return
 	}
 
-	u64, err := cast.ToUintE(user_id)
+	subject, err := middleware.RequestSubject(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id controller 155:30 Switching Error"})
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeUnauthenticated, "Unauthorized"))
 		return
 	}
 
-	vm, _ := vmC.vmService.FetchVmName(req.VmName, false)
-	if vm.UserID != u64 {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+	vm, operationID, err := vmC.lifecycleService.WithContext(c.Request.Context()).Start(subject, req.VmName, c.GetString("trace_id"))
+	if err != nil {
+		respondLifecycleError(c, err, "Failed to update VM")
 		return
 	}
 
-	go vmC.k8sService.StartVM(vm)
-
-	c.JSON(http.StatusOK, gin.H{"vm": vm})
+	response := gin.H{"vm": newVMResponse(vm)}
+	if !config.Get().OperatorMode {
+		response["operation_id"] = operationID
+	}
+	c.JSON(http.StatusOK, response)
 }
 
 type DeleteVMParams struct {
-	VmName string `json:"vm_name"`
+	VmName  string `json:"vm_name"`
+	Confirm string `json:"confirm"` // VM 이름 또는 POST /api/vm/:name/delete-confirmation 으로 받은 토큰
+	Force   bool   `json:"force"`   // 실행 중(Running, Paused)인 VM도 삭제
 }
 
+// DeleteVM은 VM 삭제를 요청합니다. 실수로 인한 삭제를 막기 위해 confirm 확인이 필요하며,
+// force 없이는 실행 중인 VM을 삭제하지 않습니다.
+// DELETE /api/vm/delete
 func (vmC *VirtualMachineController) DeleteVM(c *gin.Context) {
-	user_id, _ := c.Get("user_id")
-
 	var req DeleteVMParams
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
 		return
 	}
 
-	u64, err := cast.ToUintE(user_id)
-
-	// 파싱 오류 확인.
+	subject, err := middleware.RequestSubject(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id controller 155:30 Switching Error"})
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeUnauthenticated, "Unauthorized"))
 		return
 	}
 
-	vm, _ := vmC.vmService.FetchVmName(req.VmName, false)
-	// 소유권 확인.
-	if vm.UserID != u64 {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+	vm, operationID, err := vmC.lifecycleService.WithContext(c.Request.Context()).Delete(subject, req.VmName, req.Confirm, req.Force, c.GetString("trace_id"))
+	if err != nil {
+		respondLifecycleError(c, err, "Failed to update VM")
 		return
 	}
 
-	go vmC.k8sService.DeleteVM(vm)
-
-	c.JSON(http.StatusOK, gin.H{"vm": vm})
+	response := gin.H{"vm": newVMResponse(vm)}
+	if !config.Get().OperatorMode {
+		response["operation_id"] = operationID
+	}
+	c.JSON(http.StatusOK, response)
 }
@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	http "net/http"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/middleware"
+
+	gin "github.com/gin-gonic/gin"
+	cast "github.com/spf13/cast"
+)
+
+// FetchApprovals는 사용자가 요청한 VM 생성 승인 목록을 반환합니다.
+// GET /api/vm/approvals
+func (vmC *VirtualMachineController) FetchApprovals(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	u64, err := cast.ToUintE(user_id)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid user_id"))
+		return
+	}
+
+	approvals, err := vmC.approvalService.FetchUserApprovals(u64)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch approvals"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"approvals": approvals})
+}
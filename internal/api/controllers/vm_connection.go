@@ -0,0 +1,33 @@
+package controllers
+
+import (
+	http "net/http"
+
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/middleware"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+// FetchConnection은 VM 접속 정보(ssh 명령, 웹 URL, 콘솔 URL)를 반환합니다.
+// format=ssh_config 이면 ~/.ssh/config 항목을 파일로 내려줍니다.
+func (vmC *VirtualMachineController) FetchConnection(c *gin.Context) {
+	vm, _, ok := vmC.fetchOwnedVM(c, c.Param("name"))
+	if !ok {
+		return
+	}
+
+	connection, err := vmC.k8sService.WithContext(c.Request.Context()).GetVMConnection(vm)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch connection info"))
+		return
+	}
+
+	if c.Query("format") == "ssh_config" {
+		c.Header("Content-Disposition", "attachment; filename=\""+vm.Name+".sshconfig\"")
+		c.String(http.StatusOK, connection.SSHConfig(vm.Name))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"connection": connection, "ssh_config": connection.SSHConfig(vm.Name)})
+}
@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	http "net/http"
+	"time"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/logger"
+	"vm-controller/internal/middleware"
+	"vm-controller/internal/models"
+	k8s_service "vm-controller/internal/services/k8s_service"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+// OpenConsole은 VM 콘솔 WebSocket을 중계합니다. 인증은 기존 JWT 쿠키로 하므로 virtctl 없이 브라우저에서 접속할 수 있습니다.
+// type=serial(기본값) 이면 시리얼 콘솔, type=vnc 이면 VNC 화면을 중계합니다.
+// 콘솔 기록 정책(CONSOLE_RECORDING)이 켜져 있으면 세션 메타데이터(사용자, 접속 IP, 시작/종료 시각)를 기록합니다.
+// GET /api/vm/:name/console?type=serial|vnc
+func (vmC *VirtualMachineController) OpenConsole(c *gin.Context) {
+	var kind string
+	switch c.DefaultQuery("type", "serial") {
+	case "serial":
+		kind = k8s_service.ConsoleSerial
+	case "vnc":
+		kind = k8s_service.ConsoleVNC
+	default:
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "type must be serial or vnc"))
+		return
+	}
+
+	vm, u64, ok := vmC.fetchOwnedVM(c, c.Param("name"))
+	if !ok {
+		return
+	}
+
+	if vm.Status != models.VmStatusRunning {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidState, "VM is not running"))
+		return
+	}
+
+	proxy, err := vmC.k8sService.WithContext(c.Request.Context()).ConsoleProxy(vm, kind)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to open console"))
+		return
+	}
+
+	consoleService := vmC.consoleService
+	session, err := consoleService.StartSession(models.ConsoleSession{
+		UserID:    u64,
+		VmName:    vm.Name,
+		Namespace: vm.Namespace,
+		Type:      kind,
+		ClientIP:  c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
+	// 기록이 필수인 정책에서는 기록 없이 콘솔을 열지 않음
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to record console session"))
+		return
+	}
+
+	// 콘솔은 장시간 연결이므로 서버 타임아웃 해제
+	controller := http.NewResponseController(c.Writer)
+	controller.SetReadDeadline(time.Time{})
+	controller.SetWriteDeadline(time.Time{})
+
+	// 업그레이드된 연결이 끊길 때까지 반환되지 않음
+	proxy.ServeHTTP(c.Writer, c.Request)
+
+	if err := consoleService.EndSession(session); err != nil {
+		logger.FromContext(c.Request.Context()).Warn("failed to close console session", "vm", vm.Name, "error", err)
+	}
+}
@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"errors"
+	http "net/http"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/logger"
+	"vm-controller/internal/middleware"
+	vm_service "vm-controller/internal/services/vm_service"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+// RevealCredentials는 VM의 root 비밀번호를 한 번만 반환하고 감사 로그를 남깁니다.
+// 이미 확인했거나(자동 생성 비밀번호를 생성 응답으로 받은 경우 포함) 다시 요청하면 410을 반환합니다.
+// POST /api/vm/:name/credentials
+func (vmC *VirtualMachineController) RevealCredentials(c *gin.Context) {
+	vm, u64, ok := vmC.fetchOwnedVM(c, c.Param("name"))
+	if !ok {
+		return
+	}
+
+	password, err := vmC.vmService.RevealCredentials(vm.Name)
+	if err != nil {
+		if errors.Is(err, vm_service.ErrCredentialsRevealed) {
+			middleware.AbortWithError(c, apperrors.New(apperrors.CodeCredentialsRevealed, "Credentials have already been revealed"))
+			return
+		}
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to reveal credentials"))
+		return
+	}
+
+	if err := vmC.auditService.Record(&u64, "vm.credentials.reveal", "vm/"+vm.Name, ""); err != nil {
+		logger.FromContext(c.Request.Context()).Warn("failed to record audit log", "vm", vm.Name, "error", err)
+	}
+
+	// 프록시/브라우저 캐시에 비밀번호가 남지 않도록 함
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{
+		"username":        "root",
+		"password":        password,
+		"password_notice": generatedPasswordNotice,
+	})
+}
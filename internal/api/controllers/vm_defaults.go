@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"errors"
+	http "net/http"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/middleware"
+	"vm-controller/internal/models"
+	k8s_service "vm-controller/internal/services/k8s_service"
+	preferenceservice "vm-controller/internal/services/preference_service"
+	sshkeyservice "vm-controller/internal/services/ssh_key_service"
+
+	gin "github.com/gin-gonic/gin"
+	cast "github.com/spf13/cast"
+)
+
+type UpdateVMDefaultsParams struct {
+	Image     string `json:"image"`       // 기본 이미지 이름 (비우면 플랫폼 기본값)
+	Flavor    string `json:"flavor"`      // 기본 요금제 이름 (비우면 플랫폼 기본값)
+	SSHKeyIDs []uint `json:"ssh_key_ids"` // 기본으로 주입할 등록 SSH 키
+	DNSBase   string `json:"dns_base"`    // vm_host_prefix 생략 시 "<vm_name>.<dns_base>"로 사용
+}
+
+func vmDefaultsResponse(pref *models.VmPreference) gin.H {
+	sshKeyIds := pref.SSHKeyIDs
+	if sshKeyIds == nil {
+		sshKeyIds = []uint{}
+	}
+
+	return gin.H{
+		"image":       pref.Image,
+		"flavor":      pref.Flavor,
+		"ssh_key_ids": sshKeyIds,
+		"dns_base":    pref.DNSBase,
+	}
+}
+
+// FetchVMDefaults는 사용자가 저장한 VM 생성 기본값을 반환합니다.
+// GET /api/vm/defaults
+func (vmC *VirtualMachineController) FetchVMDefaults(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	pref, err := vmC.preferenceService.FetchPreference(cast.ToUint(user_id))
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch vm defaults"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"defaults": vmDefaultsResponse(pref)})
+}
+
+// UpdateVMDefaults는 VM 생성 기본값을 저장합니다. 저장한 값은 생성 요청에서 생략한 항목에 적용됩니다.
+// PUT /api/vm/defaults
+func (vmC *VirtualMachineController) UpdateVMDefaults(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	var req UpdateVMDefaultsParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	if req.Image != "" {
+		if _, err := k8s_service.GetImage(vmC.imageService, req.Image); err != nil {
+			middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeInvalidRequest))
+			return
+		}
+	}
+	if req.Flavor != "" {
+		if _, err := k8s_service.GetFlavor(vmC.flavorService, req.Flavor); err != nil {
+			middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeInvalidRequest))
+			return
+		}
+	}
+
+	pref, err := vmC.preferenceService.SavePreference(&models.VmPreference{
+		UserID:    cast.ToUint(user_id),
+		Image:     req.Image,
+		Flavor:    req.Flavor,
+		SSHKeyIDs: req.SSHKeyIDs,
+		DNSBase:   req.DNSBase,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, preferenceservice.ErrInvalidDNSBase), errors.Is(err, sshkeyservice.ErrKeyNotFound):
+			middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeInvalidRequest))
+		default:
+			middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to save vm defaults"))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"defaults": vmDefaultsResponse(pref)})
+}
@@ -0,0 +1,28 @@
+package controllers
+
+import (
+	http "net/http"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/middleware"
+	vmlifecycleservice "vm-controller/internal/services/vm_lifecycle_service"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+// CreateDeleteConfirmation은 DELETE /api/vm/delete의 confirm 값으로 쓸 수 있는 1회용 토큰을 발급합니다.
+// VM 이름을 그대로 보내는 대신, 스크립트에서 삭제 직전에 토큰을 받아 사용하도록 할 때 씁니다.
+// POST /api/vm/:name/delete-confirmation
+func (vmC *VirtualMachineController) CreateDeleteConfirmation(c *gin.Context) {
+	vm, u64, ok := vmC.fetchOwnedVM(c, c.Param("name"))
+	if !ok {
+		return
+	}
+
+	token, expiresAt, err := vmlifecycleservice.IssueDeleteConfirmation(u64, vm.Name)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to issue confirmation token"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"token": token, "expires_at": expiresAt})
+}
@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	http "net/http"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/middleware"
+	k8s_service "vm-controller/internal/services/k8s_service"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+// FetchVM은 VM 상세 정보를 반환합니다. (Accept: application/yaml 이면 YAML)
+// GET /api/vm/:name
+func (vmC *VirtualMachineController) FetchVM(c *gin.Context) {
+	vm, _, ok := vmC.fetchOwnedVM(c, c.Param("name"))
+	if !ok {
+		return
+	}
+
+	// Password Is Not Sent To Client
+	response := newVMResponse(vm)
+	response.Reachability = k8s_service.GetIngressReachability(vm)
+	respondNegotiated(c, http.StatusOK, gin.H{"vm": response})
+}
+
+// FetchManifests는 VM에 적용된 템플릿을 현재 DB 값으로 렌더링한 결과를 반환합니다.
+// Accept: application/yaml 이면 kubectl에 바로 넣을 수 있는 List 형식으로 내려줍니다.
+// GET /api/vm/:name/manifests
+func (vmC *VirtualMachineController) FetchManifests(c *gin.Context) {
+	vm, _, ok := vmC.fetchOwnedVM(c, c.Param("name"))
+	if !ok {
+		return
+	}
+
+	manifests, err := vmC.k8sService.RenderVMManifests(vm)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to render manifests"))
+		return
+	}
+
+	respondNegotiated(c, http.StatusOK, gin.H{
+		"apiVersion": "v1",
+		"kind":       "List",
+		"items":      manifests,
+	})
+}
@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	http "net/http"
+	"time"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/logger"
+	"vm-controller/internal/middleware"
+	"vm-controller/internal/models"
+
+	gin "github.com/gin-gonic/gin"
+	cast "github.com/spf13/cast"
+)
+
+// VMEventResponse는 사용자에게 보여주는 VM 이벤트 한 건입니다.
+type VMEventResponse struct {
+	ID         uint                   `json:"id"`
+	CreatedAt  time.Time              `json:"created_at"`
+	Type       models.EnumVmEventType `json:"type"`
+	Operation  string                 `json:"operation,omitempty"`
+	FromStatus string                 `json:"from_status,omitempty"`
+	ToStatus   string                 `json:"to_status,omitempty"`
+	Actor      string                 `json:"actor"`            // owner / admin / system
+	Detail     string                 `json:"detail,omitempty"` // 실패 사유 (OperationFailed)
+}
+
+// eventActor는 요청한 사용자를 소유자/관리자/시스템(converger, reaper 등)으로 구분합니다.
+func eventActor(event *models.VmEvent, vm *models.VirtualMachine) string {
+	switch {
+	case event.ActorID == nil:
+		return "system"
+	case *event.ActorID == vm.UserID:
+		return "owner"
+	default:
+		return "admin"
+	}
+}
+
+// FetchVMEvents는 VM의 작업 요청/결과와 상태 변화를 최신순으로 반환합니다. (limit 기본 100, 최대 500)
+// GET /api/vm/:name/events?limit=
+func (vmC *VirtualMachineController) FetchVMEvents(c *gin.Context) {
+	vm, _, ok := vmC.fetchOwnedVM(c, c.Param("name"))
+	if !ok {
+		return
+	}
+
+	limit := cast.ToInt(c.DefaultQuery("limit", "100"))
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	events, err := vmC.vmEventService.FetchVmHistory(vm.Name, vm.CreatedAt, limit)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch VM events"))
+		return
+	}
+
+	response := make([]VMEventResponse, 0, len(events))
+	for i := range events {
+		event := &events[i]
+
+		item := VMEventResponse{
+			ID:         event.ID,
+			CreatedAt:  event.CreatedAt,
+			Type:       event.Type,
+			Operation:  event.Operation,
+			FromStatus: event.FromStatus,
+			ToStatus:   event.ToStatus,
+			Actor:      eventActor(event, vm),
+		}
+		if event.Type == models.VmEventOperationFailed {
+			item.Detail = logger.Redact(event.Detail, vm.Password)
+		}
+		response = append(response, item)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"events": response})
+}
@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"fmt"
+	"io"
+	http "net/http"
+	"time"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/logger"
+	"vm-controller/internal/middleware"
+	"vm-controller/internal/models"
+	"vm-controller/internal/policy"
+
+	gin "github.com/gin-gonic/gin"
+	cast "github.com/spf13/cast"
+)
+
+type VMExportParams struct {
+	VmName string `json:"vm_name"`
+}
+
+// fetchOwnedVM은 요청자가 소유한 VM을 조회합니다. 실패 시 응답을 작성하고 false를 반환합니다.
+func (vmC *VirtualMachineController) fetchOwnedVM(c *gin.Context, vmName string) (*models.VirtualMachine, uint, bool) {
+	user_id, _ := c.Get("user_id")
+
+	u64, err := cast.ToUintE(user_id)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid user_id"))
+		return nil, 0, false
+	}
+
+	vm, err := vmC.vmService.WithContext(c.Request.Context()).FetchVmName(vmName, false)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch VM"))
+		return nil, 0, false
+	}
+
+	// 소유권 확인.
+	if vm == nil || !middleware.AuthorizeOwned(c, policy.ResourceVM, vm.UserID) {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeVMNotFound, "VM not found"))
+		return nil, 0, false
+	}
+
+	return vm, u64, true
+}
+
+func exportDownloadURL(c *gin.Context, vmName, format string) string {
+	return apiURL(c, fmt.Sprintf("/vm/export/download?vm_name=%s&format=%s", vmName, format))
+}
+
+// CreateExport는 VM 디스크 내보내기를 요청합니다. 실행 중인 VM은 정지된 뒤에 내보내기가 준비됩니다.
+func (vmC *VirtualMachineController) CreateExport(c *gin.Context) {
+	var req VMExportParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	vm, u64, ok := vmC.fetchOwnedVM(c, req.VmName)
+	if !ok {
+		return
+	}
+
+	status, err := vmC.k8sService.WithContext(c.Request.Context()).CreateVMExport(vm)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to create export"))
+		return
+	}
+	vmC.vmEventService.RecordOperation(vm.Name, "export", u64)
+
+	response := gin.H{"export": status}
+	if vm.Status == models.VmStatusRunning {
+		response["warning"] = "VM이 정지된 후에 내보내기가 준비됩니다."
+	}
+
+	c.JSON(http.StatusAccepted, response)
+}
+
+// FetchExport는 내보내기 상태와, 준비된 경우 형식별 다운로드 URL을 반환합니다.
+func (vmC *VirtualMachineController) FetchExport(c *gin.Context) {
+	vm, _, ok := vmC.fetchOwnedVM(c, c.Query("vm_name"))
+	if !ok {
+		return
+	}
+
+	status, err := vmC.k8sService.WithContext(c.Request.Context()).GetVMExportStatus(vm)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch export"))
+		return
+	}
+	if status == nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeExportNotFound, "Export not found"))
+		return
+	}
+
+	downloads := gin.H{}
+	if status.Phase == "Ready" {
+		for _, format := range status.Formats {
+			downloads[format] = exportDownloadURL(c, vm.Name, format)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"export": status, "downloads": downloads})
+}
+
+// DownloadExport는 export 서버의 디스크 이미지를 인증된 사용자에게 중계합니다.
+// Range 헤더를 그대로 전달하므로 중단된 다운로드를 이어받을 수 있습니다.
+func (vmC *VirtualMachineController) DownloadExport(c *gin.Context) {
+	vm, _, ok := vmC.fetchOwnedVM(c, c.Query("vm_name"))
+	if !ok {
+		return
+	}
+
+	format := c.DefaultQuery("format", "gzip")
+	if format != "gzip" && format != "raw" {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "format must be gzip or raw"))
+		return
+	}
+
+	resp, err := vmC.k8sService.OpenVMExportDownload(c.Request.Context(), vm, format, c.GetHeader("Range"))
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeUpstream))
+		return
+	}
+	defer resp.Body.Close()
+
+	// 수 GB 단위 전송이므로 서버 WriteTimeout을 이 요청에 한해 해제
+	http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	extension := "img"
+	if format == "gzip" {
+		extension = "img.gz"
+	}
+
+	for _, header := range []string{"Content-Length", "Content-Range", "Accept-Ranges", "ETag", "Last-Modified"} {
+		if value := resp.Header.Get(header); value != "" {
+			c.Header(header, value)
+		}
+	}
+	c.Header("Content-Type", "application/octet-stream")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", vm.Name+"."+extension))
+	c.Status(resp.StatusCode)
+
+	if _, err := io.Copy(c.Writer, resp.Body); err != nil {
+		logger.FromContext(c.Request.Context()).Warn("vm export: download interrupted", "vm", vm.Name, "error", err)
+	}
+}
@@ -0,0 +1,21 @@
+package controllers
+
+import (
+	http "net/http"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/middleware"
+	k8s_service "vm-controller/internal/services/k8s_service"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+// FetchFlavors는 VM 생성 시 선택할 수 있는 요금제(자원/가격) 목록을 반환합니다.
+func (vmC *VirtualMachineController) FetchFlavors(c *gin.Context) {
+	flavors, err := k8s_service.ListFlavors(vmC.flavorService)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch flavors"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"flavors": flavors, "default": k8s_service.DefaultFlavor})
+}
@@ -0,0 +1,293 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	http "net/http"
+	"time"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/middleware"
+	"vm-controller/internal/models"
+	flavorchangeservice "vm-controller/internal/services/flavor_change_service"
+	k8s_service "vm-controller/internal/services/k8s_service"
+
+	gin "github.com/gin-gonic/gin"
+	cast "github.com/spf13/cast"
+)
+
+// 요금제 변경 시간대 (분)
+const (
+	defaultFlavorChangeWindow = 60
+	minFlavorChangeWindow     = 15
+	maxFlavorChangeWindow     = 24 * 60
+)
+
+// 요금제 변경을 예약할 수 있는 최대 기간
+const maxFlavorChangeAdvance = 30 * 24 * time.Hour
+
+type FlavorChangeParams struct {
+	Flavor        string     `json:"flavor" binding:"required"` // 변경할 요금제
+	WindowStart   *time.Time `json:"window_start"`              // 변경 시간대 시작 (RFC3339, 비어 있으면 즉시 변경)
+	WindowMinutes int        `json:"window_minutes"`            // 변경 시간대 길이 (기본 60분, 15분 ~ 24시간)
+}
+
+// FlavorChangeResponse는 요금제 변경 요청 한 건입니다.
+type FlavorChangeResponse struct {
+	ID                uint                          `json:"id"`
+	CreatedAt         time.Time                     `json:"created_at"`
+	FromFlavor        string                        `json:"from_flavor"`
+	ToFlavor          string                        `json:"to_flavor"`
+	WindowStart       time.Time                     `json:"window_start"`
+	WindowEnd         time.Time                     `json:"window_end"`
+	Status            models.EnumFlavorChangeStatus `json:"status"`
+	OperationID       *uint                         `json:"operation_id,omitempty"`
+	Error             string                        `json:"error,omitempty"`
+	StartedAt         *time.Time                    `json:"started_at,omitempty"`
+	FinishedAt        *time.Time                    `json:"finished_at,omitempty"`
+	HourlyPriceDelta  int                           `json:"hourly_price_delta"`
+	MonthlyPriceDelta int                           `json:"monthly_price_delta"`
+}
+
+func newFlavorChangeResponse(change *models.VmFlavorChange) FlavorChangeResponse {
+	return FlavorChangeResponse{
+		ID:                change.ID,
+		CreatedAt:         change.CreatedAt,
+		FromFlavor:        change.FromFlavor,
+		ToFlavor:          change.ToFlavor,
+		WindowStart:       change.WindowStart,
+		WindowEnd:         change.WindowEnd,
+		Status:            change.Status,
+		OperationID:       change.OperationID,
+		Error:             change.Error,
+		StartedAt:         change.StartedAt,
+		FinishedAt:        change.FinishedAt,
+		HourlyPriceDelta:  change.HourlyPriceDelta,
+		MonthlyPriceDelta: change.MonthlyPriceDelta,
+	}
+}
+
+// previewFlavorChange는 요청한 요금제를 검사하고 가격/쿼터 변화를 계산합니다.
+// 같은 요금제, 관리자 승인이 필요한 요금제, 삭제 중인 VM은 거부합니다.
+func (vmC *VirtualMachineController) previewFlavorChange(c *gin.Context, vm *models.VirtualMachine, flavorName string) (*k8s_service.FlavorChangePreview, bool) {
+	if vm.DesiredState == models.VmDesiredDeleted {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidState, "VM is being deleted").WithDetail("status", vm.Status))
+		return nil, false
+	}
+
+	to, err := k8s_service.GetFlavor(vmC.flavorService, flavorName)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeInvalidRequest))
+		return nil, false
+	}
+	if to.Name == vm.Flavor {
+		middleware.AbortWithError(c, apperrors.Newf(apperrors.CodeInvalidRequest, "VM already uses flavor %s", to.Name))
+		return nil, false
+	}
+	// 승인 대상 요금제는 생성 시에만 승인 절차가 있으므로 변경으로 우회하지 못하게 막음
+	if to.RequiresApproval() {
+		middleware.AbortWithError(c, apperrors.Newf(apperrors.CodeForbidden, "Flavor %s requires administrator approval and cannot be selected for an existing VM", to.Name))
+		return nil, false
+	}
+
+	preview, err := vmC.k8sService.WithContext(c.Request.Context()).PreviewFlavorChange(vm, to)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeUpstream, "Failed to preview flavor change").Wrap(err))
+		return nil, false
+	}
+
+	return preview, true
+}
+
+// PreviewFlavorChange는 요금제를 바꿨을 때의 가격 변화, 네임스페이스 VM 파드 쿼터 변화, 재시작 여부를 반환합니다.
+// POST /api/vm/:name/flavor/preview {"flavor": "premium"}
+func (vmC *VirtualMachineController) PreviewFlavorChange(c *gin.Context) {
+	var req FlavorChangeParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "flavor is required"))
+		return
+	}
+
+	vm, _, ok := vmC.fetchOwnedVM(c, c.Param("name"))
+	if !ok {
+		return
+	}
+
+	preview, ok := vmC.previewFlavorChange(c, vm, req.Flavor)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"preview": preview, "allowed": preview.QuotaExceeded() == nil})
+}
+
+// ChangeFlavor는 VM 요금제 변경을 요청합니다.
+// window_start가 없으면 바로 실행하고(202 + operation_id), 있으면 해당 시간대에 스케줄러가 실행합니다(201).
+// 실행 중인 VM은 재시작되며, 디스크 크기는 바뀌지 않습니다.
+// POST /api/vm/:name/flavor {"flavor": "premium", "window_start": "2026-03-01T02:00:00+09:00", "window_minutes": 60}
+func (vmC *VirtualMachineController) ChangeFlavor(c *gin.Context) {
+	var req FlavorChangeParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "flavor is required (window_start must be RFC3339)"))
+		return
+	}
+
+	windowMinutes := req.WindowMinutes
+	if windowMinutes == 0 {
+		windowMinutes = defaultFlavorChangeWindow
+	}
+	if windowMinutes < minFlavorChangeWindow || windowMinutes > maxFlavorChangeWindow {
+		middleware.AbortWithError(c, apperrors.Newf(apperrors.CodeInvalidRequest, "window_minutes must be between %d and %d", minFlavorChangeWindow, maxFlavorChangeWindow))
+		return
+	}
+
+	now := time.Now()
+	windowStart := now
+	if req.WindowStart != nil {
+		windowStart = *req.WindowStart
+		if windowStart.Add(time.Duration(windowMinutes) * time.Minute).Before(now) {
+			middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "window has already ended"))
+			return
+		}
+		if windowStart.After(now.Add(maxFlavorChangeAdvance)) {
+			middleware.AbortWithError(c, apperrors.Newf(apperrors.CodeInvalidRequest, "window_start must be within %d days", int(maxFlavorChangeAdvance.Hours()/24)))
+			return
+		}
+	}
+	immediate := !windowStart.After(now)
+
+	vm, userID, ok := vmC.fetchOwnedVM(c, c.Param("name"))
+	if !ok {
+		return
+	}
+
+	// 즉시 변경은 지금 상태에서 실행할 수 있어야 함 (예약은 실행 시점에 스케줄러가 확인)
+	if immediate && vm.Status != models.VmStatusRunning && vm.Status != models.VmStatusStopped {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidState, "Flavor can only be changed while the VM is Running or Stopped").WithDetail("status", vm.Status))
+		return
+	}
+
+	preview, ok := vmC.previewFlavorChange(c, vm, req.Flavor)
+	if !ok {
+		return
+	}
+	if exceeded := preview.QuotaExceeded(); exceeded != nil {
+		middleware.AbortWithError(c, apperrors.Newf(apperrors.CodeQuotaExceeded, "%s quota would be exceeded: %s > %s", exceeded.Resource, exceeded.After, exceeded.Hard).
+			WithDetail("preview", preview))
+		return
+	}
+
+	change := &models.VmFlavorChange{
+		VmName:            vm.Name,
+		UserID:            vm.UserID,
+		FromFlavor:        vm.Flavor,
+		ToFlavor:          preview.To.Name,
+		WindowStart:       windowStart,
+		WindowEnd:         windowStart.Add(time.Duration(windowMinutes) * time.Minute),
+		HourlyPriceDelta:  preview.HourlyPriceDelta,
+		MonthlyPriceDelta: preview.MonthlyPriceDelta,
+	}
+	if err := vmC.flavorChangeService.CreateChange(change); err != nil {
+		if errors.Is(err, flavorchangeservice.ErrChangePending) {
+			middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeConflict))
+			return
+		}
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to schedule flavor change"))
+		return
+	}
+
+	// 예약은 flavor.schedule, 실제 변경은 실행 시점에 flavor.change로 기록 (스케줄러가 실행하면 시스템 요청)
+	if !immediate {
+		vmC.vmEventService.Record(models.VmEvent{
+			VmName:    vm.Name,
+			Type:      models.VmEventOperationRequested,
+			Operation: "flavor.schedule",
+			Detail:    fmt.Sprintf("%s -> %s (window %s ~ %s)", change.FromFlavor, change.ToFlavor, change.WindowStart.Format(time.RFC3339), change.WindowEnd.Format(time.RFC3339)),
+			ActorID:   &userID,
+		})
+		c.JSON(http.StatusCreated, gin.H{"change": newFlavorChangeResponse(change), "preview": preview})
+		return
+	}
+
+	vmC.vmEventService.Record(models.VmEvent{
+		VmName:    vm.Name,
+		Type:      models.VmEventOperationRequested,
+		Operation: "flavor.change",
+		Detail:    change.FromFlavor + " -> " + change.ToFlavor,
+		ActorID:   &userID,
+	})
+
+	operationID, err := vmC.k8sService.WithRequest(c.Request.Context()).StartFlavorChange(vm, change)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeInternal))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"change": newFlavorChangeResponse(change), "preview": preview, "operation_id": operationID})
+}
+
+// FetchFlavorChanges는 VM의 요금제 변경 이력(예약/완료/실패/취소)을 최신순으로 반환합니다.
+// GET /api/vm/:name/flavor/changes
+func (vmC *VirtualMachineController) FetchFlavorChanges(c *gin.Context) {
+	vm, _, ok := vmC.fetchOwnedVM(c, c.Param("name"))
+	if !ok {
+		return
+	}
+
+	changes, err := vmC.flavorChangeService.FetchVmChanges(vm.Name)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch flavor changes"))
+		return
+	}
+
+	response := make([]FlavorChangeResponse, 0, len(changes))
+	for i := range changes {
+		response = append(response, newFlavorChangeResponse(&changes[i]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"changes": response})
+}
+
+// CancelFlavorChange는 아직 실행되지 않은 요금제 변경 예약을 취소합니다.
+// DELETE /api/vm/:name/flavor/changes/:id
+func (vmC *VirtualMachineController) CancelFlavorChange(c *gin.Context) {
+	id, err := cast.ToUintE(c.Param("id"))
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid id"))
+		return
+	}
+
+	vm, userID, ok := vmC.fetchOwnedVM(c, c.Param("name"))
+	if !ok {
+		return
+	}
+
+	changes := vmC.flavorChangeService
+	change, err := changes.FetchChange(vm.Name, id)
+	if err != nil {
+		if errors.Is(err, flavorchangeservice.ErrChangeNotFound) {
+			middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeNotFound))
+			return
+		}
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch flavor change"))
+		return
+	}
+
+	if err := changes.Cancel(change.ID); err != nil {
+		if errors.Is(err, flavorchangeservice.ErrChangeNotScheduled) {
+			middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeInvalidState).WithDetail("status", change.Status))
+			return
+		}
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to cancel flavor change"))
+		return
+	}
+
+	vmC.vmEventService.Record(models.VmEvent{
+		VmName:    vm.Name,
+		Type:      models.VmEventOperationRequested,
+		Operation: "flavor.cancel",
+		Detail:    change.FromFlavor + " -> " + change.ToFlavor,
+		ActorID:   &userID,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Flavor change cancelled"})
+}
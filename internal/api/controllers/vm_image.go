@@ -0,0 +1,22 @@
+package controllers
+
+import (
+	http "net/http"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/middleware"
+	k8s_service "vm-controller/internal/services/k8s_service"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+// FetchImages는 VM 생성 시 선택할 수 있는 이미지와 기본 SSH 사용자/인증 방식을 반환합니다.
+// GET /api/vm/images
+func (vmC *VirtualMachineController) FetchImages(c *gin.Context) {
+	images, err := k8s_service.ListImages(vmC.imageService)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch images"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"images": images, "default": k8s_service.DefaultImage})
+}
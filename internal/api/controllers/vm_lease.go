@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"errors"
+	http "net/http"
+	"time"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/middleware"
+	vm_service "vm-controller/internal/services/vm_service"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+type ExtendLeaseParams struct {
+	Days int `json:"days" binding:"required"` // 연장할 일수 (연장 후 만료 시각은 지금부터 사용 기간 이내)
+}
+
+// ExtendLease는 VM 사용 기간을 연장합니다. 만료되어 정지된 VM도 삭제되기 전이면 연장할 수 있으며,
+// 연장 후에는 /api/vm/start로 다시 시작합니다.
+// POST /api/vm/:name/extend {"days": 30}
+func (vmC *VirtualMachineController) ExtendLease(c *gin.Context) {
+	user_id, _ := c.Get("user_id")
+
+	var req ExtendLeaseParams
+	if err := c.ShouldBindJSON(&req); err != nil || req.Days <= 0 {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "days must be a positive number"))
+		return
+	}
+
+	vm, _, ok := vmC.fetchOwnedVM(c, c.Param("name"))
+	if !ok {
+		return
+	}
+
+	user, err := vmC.userService.WithContext(c.Request.Context()).FetchUserById(user_id.(string), true)
+	if err != nil || user == nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeUserNotFound, "User not found"))
+		return
+	}
+
+	expiresAt, err := vmC.vmService.ExtendLease(vm, user, req.Days)
+	if err != nil {
+		switch {
+		case errors.Is(err, vm_service.ErrNoLease), errors.Is(err, vm_service.ErrLeaseDisabled):
+			middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeInvalidRequest))
+		case errors.Is(err, vm_service.ErrLeaseTooLong):
+			middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeInvalidRequest).WithDetail("max_days", vmC.vmService.LeaseDays(user)))
+		default:
+			middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to extend lease"))
+		}
+		return
+	}
+
+	vmC.vmEventService.RecordOperation(vm.Name, "lease.extend", user.ID)
+
+	c.JSON(http.StatusOK, gin.H{"vm": newVMResponse(vm), "expires_at": expiresAt.Format(time.RFC3339)})
+}
@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"errors"
+	http "net/http"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/middleware"
+	"vm-controller/internal/models"
+	"vm-controller/internal/services/k8s_service"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+// FetchMetrics는 실행 중인 VM의 CPU/메모리/디스크 사용량을 반환합니다. (리소스 게이지용)
+// 디스크 사용량은 kubelet 통계를 1분간 캐시하므로 CPU/메모리보다 늦게 갱신됩니다.
+// GET /api/vm/:name/metrics
+func (vmC *VirtualMachineController) FetchMetrics(c *gin.Context) {
+	vm, _, ok := vmC.fetchOwnedVM(c, c.Param("name"))
+	if !ok {
+		return
+	}
+
+	// 일시 정지된 VM도 virt-launcher 파드는 살아 있으므로 메모리/디스크 사용량을 보여줌
+	if vm.Status != models.VmStatusRunning && vm.Status != models.VmStatusPaused {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidState, "현재 상태("+string(vm.Status)+")에서는 요청을 처리할 수 없습니다."))
+		return
+	}
+
+	metrics, err := vmC.k8sService.WithContext(c.Request.Context()).GetVMMetrics(vm)
+	if err != nil {
+		switch {
+		case errors.Is(err, k8s_service.ErrVMNotRunning):
+			middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeConflict))
+		case errors.Is(err, k8s_service.ErrMetricsUnavailable):
+			middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeUnavailable))
+		default:
+			middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch VM metrics"))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"metrics": metrics})
+}
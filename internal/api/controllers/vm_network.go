@@ -0,0 +1,24 @@
+package controllers
+
+import (
+	http "net/http"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/middleware"
+	k8s_service "vm-controller/internal/services/k8s_service"
+	vmlifecycleservice "vm-controller/internal/services/vm_lifecycle_service"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+type CreateVMNetworkParams = vmlifecycleservice.NetworkParams
+
+// FetchNetworks는 VM 생성 시 선택할 수 있는 보조 네트워크 목록을 반환합니다.
+func (vmC *VirtualMachineController) FetchNetworks(c *gin.Context) {
+	networks, err := vmC.networkService.FetchNetworks(true)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch networks"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"networks": networks, "max_networks": k8s_service.MaxSecondaryNetworks})
+}
@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	http "net/http"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/middleware"
+	"vm-controller/internal/models"
+	"vm-controller/internal/vmstate"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+type PauseVMParams struct {
+	VmName string `json:"vm_name"`
+}
+
+// PauseVM은 실행 중인 VM을 일시 정지합니다. 메모리 상태는 유지되며 목표 상태(Running)는 바뀌지 않습니다.
+// POST /api/vm/pause
+func (vmC *VirtualMachineController) PauseVM(c *gin.Context) {
+	var req PauseVMParams
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	vm, u64, ok := vmC.fetchOwnedVM(c, req.VmName)
+	if !ok {
+		return
+	}
+
+	// 현재 상태에서 허용되지 않는 요청은 거부 (예: 정지된 VM 일시 정지)
+	if !vmstate.CanTransition(vm.Status, models.VmStatusPausing) {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidState, "현재 상태("+string(vm.Status)+")에서는 요청을 처리할 수 없습니다."))
+		return
+	}
+
+	vmC.vmEventService.RecordOperation(vm.Name, "pause", u64)
+	operationID := vmC.k8sService.WithRequest(c.Request.Context()).PauseVMAsync(vm, c.GetString("trace_id"))
+
+	vm.Status = models.VmStatusPausing
+	c.JSON(http.StatusOK, gin.H{"vm": newVMResponse(vm), "operation_id": operationID})
+}
+
+// UnpauseVM은 일시 정지된 VM을 다시 실행합니다.
+// POST /api/vm/unpause
+func (vmC *VirtualMachineController) UnpauseVM(c *gin.Context) {
+	var req PauseVMParams
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	vm, u64, ok := vmC.fetchOwnedVM(c, req.VmName)
+	if !ok {
+		return
+	}
+
+	if vm.Status != models.VmStatusPaused {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidState, "현재 상태("+string(vm.Status)+")에서는 요청을 처리할 수 없습니다."))
+		return
+	}
+
+	// 채굴 의심으로 자동 일시 정지된 VM은 관리자 검토가 끝날 때까지 해제할 수 없음
+	throttled, err := vmC.reportService.IsVMThrottled(vm.ID)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to check VM review status").Wrap(err))
+		return
+	}
+	if throttled {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeUnderReview, "관리자 검토 중인 VM은 일시 정지를 해제할 수 없습니다."))
+		return
+	}
+
+	vmC.vmEventService.RecordOperation(vm.Name, "unpause", u64)
+	operationID := vmC.k8sService.WithRequest(c.Request.Context()).UnpauseVMAsync(vm, c.GetString("trace_id"))
+
+	c.JSON(http.StatusOK, gin.H{"vm": newVMResponse(vm), "operation_id": operationID})
+}
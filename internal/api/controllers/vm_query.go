@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"vm-controller/internal/models"
+	vm_service "vm-controller/internal/services/vm_service"
+
+	gin "github.com/gin-gonic/gin"
+	cast "github.com/spf13/cast"
+)
+
+// parseVMQuery는 VM 목록 쿼리 파라미터를 읽습니다. (사용자/관리자 목록 공통)
+// ?page=1&limit=20&status=Running,Stopped&q=lab&sort=-created_at
+// page/limit을 생략하면 전체 목록을 반환합니다.
+func parseVMQuery(c *gin.Context) (vm_service.VmQuery, error) {
+	var query vm_service.VmQuery
+
+	if raw := c.Query("page"); raw != "" {
+		page, err := cast.ToIntE(raw)
+		if err != nil || page < 1 {
+			return query, fmt.Errorf("page must be 1 or greater")
+		}
+		query.Page = page
+	}
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := cast.ToIntE(raw)
+		if err != nil || limit < 1 {
+			return query, fmt.Errorf("limit must be between 1 and %d", vm_service.MaxVmPageLimit)
+		}
+		query.Limit = limit
+	} else if query.Page > 0 {
+		query.Limit = 20
+	}
+
+	for _, status := range strings.Split(c.Query("status"), ",") {
+		if status = strings.TrimSpace(status); status != "" {
+			query.Statuses = append(query.Statuses, models.EnumVmStatus(status))
+		}
+	}
+	query.Search = c.Query("q")
+	query.Sort = c.Query("sort")
+
+	return query, query.Validate()
+}
+
+// vmPageResponse는 목록 응답의 페이지 정보입니다.
+func vmPageResponse(page *vm_service.VmPage) gin.H {
+	response := gin.H{"total": page.Total}
+	if page.Limit > 0 {
+		response["page"] = page.Page
+		response["limit"] = page.Limit
+		response["has_more"] = int64(page.Page*page.Limit) < page.Total
+	}
+	return response
+}
@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"time"
+	"vm-controller/internal/models"
+	k8s_service "vm-controller/internal/services/k8s_service"
+)
+
+// VMResponse는 API 응답에 포함하는 VM 정보입니다.
+// 모델을 그대로 직렬화하지 않고 필드를 명시하여, 비밀번호 같은 민감한 값이 새 필드와 함께 실수로 노출되지 않도록 합니다.
+type VMResponse struct {
+	ID                  uint                      `json:"id"`
+	Name                string                    `json:"name"`
+	Namespace           string                    `json:"namespace"`
+	Status              models.EnumVmStatus       `json:"status"`
+	DesiredState        models.EnumVmDesiredState `json:"desired_state"`
+	Image               string                    `json:"image"`
+	SSHUser             string                    `json:"ssh_user"` // 접속할 SSH 사용자 (이미지의 기본 사용자)
+	Flavor              string                    `json:"flavor"`
+	DiskGi              int                       `json:"disk_gi"`
+	NodePort            int32                     `json:"node_port"`
+	DnsHost             string                    `json:"dns_host"`
+	MacAddress          string                    `json:"mac_address"`
+	Networks            []models.VmNetwork        `json:"networks"`
+	SSHKeys             []string                  `json:"ssh_keys"`
+	CloudInit           string                    `json:"cloud_init,omitempty"`
+	BundleChannel       string                    `json:"bundle_channel"`
+	BundleVersion       string                    `json:"bundle_version"`
+	CredentialsRevealed bool                      `json:"credentials_revealed"` // 비밀번호를 이미 확인함 (다시 확인 불가)
+	ExpiresAt           *time.Time                `json:"expires_at"`           // 사용 기간 만료 시각 (null이면 만료 없음)
+	CreatedAt           time.Time                 `json:"created_at"`
+	UpdatedAt           time.Time                 `json:"updated_at"`
+
+	// 웹 Ingress 도달성 (상세 조회에서만, INGRESS_CHECK_INTERVAL 설정 시)
+	Reachability *k8s_service.IngressReachability `json:"reachability,omitempty"`
+}
+
+func newVMResponse(vm *models.VirtualMachine) VMResponse {
+	return VMResponse{
+		ID:                  vm.ID,
+		Name:                vm.Name,
+		Namespace:           vm.Namespace,
+		Status:              vm.Status,
+		DesiredState:        vm.DesiredState,
+		Image:               vm.Image,
+		SSHUser:             k8s_service.VMSSHUser(vm),
+		Flavor:              vm.Flavor,
+		DiskGi:              vm.DiskGi,
+		NodePort:            vm.NodePort,
+		DnsHost:             vm.DnsHost,
+		MacAddress:          vm.MacAddress,
+		Networks:            vm.Networks,
+		SSHKeys:             vm.SSHKeys,
+		CloudInit:           vm.CloudInit,
+		BundleChannel:       vm.BundleChannel,
+		BundleVersion:       vm.BundleVersion,
+		CredentialsRevealed: vm.CredentialsRevealedAt != nil,
+		ExpiresAt:           vm.ExpiresAt,
+		CreatedAt:           vm.CreatedAt,
+		UpdatedAt:           vm.UpdatedAt,
+	}
+}
+
+func newVMResponses(vms []models.VirtualMachine) []VMResponse {
+	responses := make([]VMResponse, 0, len(vms))
+	for i := range vms {
+		responses = append(responses, newVMResponse(&vms[i]))
+	}
+	return responses
+}
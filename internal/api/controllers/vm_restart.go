@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	http "net/http"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/middleware"
+	"vm-controller/internal/models"
+	vmlifecycleservice "vm-controller/internal/services/vm_lifecycle_service"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+type RestartVMParams struct {
+	VmName string `json:"vm_name"`
+}
+
+// RestartVM은 VM을 재부팅하고 Running으로 돌아올 때까지(최대 VM_RESTART_TIMEOUT) 기다립니다.
+// 제한 시간을 넘기면 504를 반환하며, 재부팅은 계속 진행되고 상태는 converger가 반영합니다.
+// POST /api/vm/restart
+func (vmC *VirtualMachineController) RestartVM(c *gin.Context) {
+	var req RestartVMParams
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	subject, err := middleware.RequestSubject(c)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeVMNotFound, "VM not found"))
+		return
+	}
+
+	vm, err := vmC.lifecycleService.WithContext(c.Request.Context()).Restart(subject, req.VmName)
+	if err != nil {
+		if vmlifecycleservice.KindOf(err) == vmlifecycleservice.KindTimeout {
+			middleware.AbortWithError(c, apperrors.New(apperrors.CodeTimeout, vmlifecycleservice.MessageOf(err, "")).WithDetail("status", models.VmStatusRestarting))
+			return
+		}
+		respondLifecycleError(c, err, "Failed to restart VM")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"vm": newVMResponse(vm)})
+}
@@ -0,0 +1,186 @@
+package controllers
+
+import (
+	"errors"
+	http "net/http"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/logger"
+	"vm-controller/internal/middleware"
+	"vm-controller/internal/models"
+	snapshotservice "vm-controller/internal/services/snapshot_service"
+	"vm-controller/internal/vmstate"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+type CreateSnapshotParams struct {
+	VmName      string `json:"vm_name"`
+	Description string `json:"description"`
+}
+
+type SnapshotParams struct {
+	VmName       string `json:"vm_name"`
+	SnapshotName string `json:"snapshot_name"`
+}
+
+// CreateSnapshot은 VM 디스크 스냅샷 생성을 요청합니다. 완료 여부는 목록 조회로 확인합니다.
+// POST /api/vm/snapshot
+func (vmC *VirtualMachineController) CreateSnapshot(c *gin.Context) {
+	var req CreateSnapshotParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	vm, u64, ok := vmC.fetchOwnedVM(c, req.VmName)
+	if !ok {
+		return
+	}
+
+	switch vm.Status {
+	case models.VmStatusRunning, models.VmStatusPaused, models.VmStatusStopped:
+	default:
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidState, "현재 상태("+string(vm.Status)+")에서는 요청을 처리할 수 없습니다."))
+		return
+	}
+
+	snapshotService := vmC.snapshotService
+	snapshot := &models.Snapshot{
+		UserID:      u64,
+		VmName:      vm.Name,
+		Namespace:   vm.Namespace,
+		Name:        snapshotService.SnapshotName(vm.Name),
+		Description: req.Description,
+		Status:      models.SnapshotStatusInProgress,
+	}
+
+	if err := snapshotService.CreateSnapshot(snapshot); err != nil {
+		if errors.Is(err, snapshotservice.ErrSnapshotLimit) {
+			middleware.AbortWithError(c, apperrors.Newf(apperrors.CodeLimitExceeded, "VM당 스냅샷은 최대 %d개까지 보관할 수 있습니다. 기존 스냅샷을 삭제해 주세요.", snapshotService.Limit()))
+			return
+		}
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to create snapshot"))
+		return
+	}
+
+	if err := vmC.k8sService.WithContext(c.Request.Context()).CreateVMSnapshot(vm, snapshot); err != nil {
+		logger.FromContext(c.Request.Context()).Error("failed to create snapshot", "vm", vm.Name, "error", err)
+		if errDelete := snapshotService.DeleteSnapshot(snapshot.ID); errDelete != nil {
+			logger.FromContext(c.Request.Context()).Error("failed to remove snapshot record", "snapshot", snapshot.Name, "error", errDelete)
+		}
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to create snapshot"))
+		return
+	}
+	vmC.vmEventService.RecordOperation(vm.Name, "snapshot", u64)
+
+	c.JSON(http.StatusAccepted, gin.H{"snapshot": snapshot})
+}
+
+// FetchSnapshots는 VM의 스냅샷 목록을 반환합니다. 생성 중인 스냅샷은 클러스터 상태를 반영합니다.
+// GET /api/vm/snapshot?vm_name=
+func (vmC *VirtualMachineController) FetchSnapshots(c *gin.Context) {
+	vm, _, ok := vmC.fetchOwnedVM(c, c.Query("vm_name"))
+	if !ok {
+		return
+	}
+
+	snapshotService := vmC.snapshotService
+	snapshots, err := snapshotService.FetchVmSnapshots(vm.Name)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch snapshots"))
+		return
+	}
+
+	for i := range snapshots {
+		if err := vmC.k8sService.WithContext(c.Request.Context()).SyncVMSnapshotStatus(&snapshots[i]); err != nil {
+			logger.FromContext(c.Request.Context()).Warn("failed to sync snapshot", "snapshot", snapshots[i].Name, "error", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"snapshots": snapshots, "limit": snapshotService.Limit()})
+}
+
+// fetchOwnedSnapshot은 요청자가 소유한 VM과 그 스냅샷을 조회합니다. 실패 시 응답을 작성하고 false를 반환합니다.
+func (vmC *VirtualMachineController) fetchOwnedSnapshot(c *gin.Context, req SnapshotParams) (*models.VirtualMachine, *models.Snapshot, uint, bool) {
+	vm, u64, ok := vmC.fetchOwnedVM(c, req.VmName)
+	if !ok {
+		return nil, nil, 0, false
+	}
+
+	snapshot, err := vmC.snapshotService.FetchSnapshot(vm.Name, req.SnapshotName)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch snapshot"))
+		return nil, nil, 0, false
+	}
+	if snapshot == nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeSnapshotNotFound, "Snapshot not found"))
+		return nil, nil, 0, false
+	}
+
+	return vm, snapshot, u64, true
+}
+
+// RestoreSnapshot은 VM 디스크를 스냅샷 시점으로 되돌립니다. VM은 먼저 정지되어 있어야 하며,
+// 복원이 끝나면 Stopped 상태가 되므로 사용자가 다시 시작합니다.
+// POST /api/vm/snapshot/restore
+func (vmC *VirtualMachineController) RestoreSnapshot(c *gin.Context) {
+	var req SnapshotParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	vm, snapshot, u64, ok := vmC.fetchOwnedSnapshot(c, req)
+	if !ok {
+		return
+	}
+
+	// 복원 중에는 KubeVirt가 VM을 시작할 수 없으므로 정지(목표 상태 Stopped)된 VM만 허용
+	if vm.DesiredState != models.VmDesiredStopped || !vmstate.CanTransition(vm.Status, models.VmStatusRestoring) {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidState, "VM을 정지한 뒤에 복원할 수 있습니다. (현재 상태: "+string(vm.Status)+")"))
+		return
+	}
+
+	if err := vmC.k8sService.WithContext(c.Request.Context()).SyncVMSnapshotStatus(snapshot); err != nil {
+		logger.FromContext(c.Request.Context()).Warn("failed to sync snapshot", "snapshot", snapshot.Name, "error", err)
+	}
+	if snapshot.Status != models.SnapshotStatusReady {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidState, "스냅샷이 아직 준비되지 않았습니다. (상태: "+string(snapshot.Status)+")"))
+		return
+	}
+
+	vmC.vmEventService.RecordOperation(vm.Name, "restore", u64)
+	operationID := vmC.k8sService.WithRequest(c.Request.Context()).RestoreVMSnapshotAsync(vm, snapshot, c.GetString("trace_id"))
+
+	vm.Status = models.VmStatusRestoring
+	c.JSON(http.StatusAccepted, gin.H{"vm": newVMResponse(vm), "snapshot": snapshot, "operation_id": operationID})
+}
+
+// DeleteSnapshot은 스냅샷과 보관 중인 디스크 데이터를 삭제합니다.
+// DELETE /api/vm/snapshot
+func (vmC *VirtualMachineController) DeleteSnapshot(c *gin.Context) {
+	var req SnapshotParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	vm, snapshot, _, ok := vmC.fetchOwnedSnapshot(c, req)
+	if !ok {
+		return
+	}
+
+	// 복원 중인 스냅샷은 삭제하지 않음
+	if vm.Status == models.VmStatusRestoring {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidState, "복원이 진행 중인 VM의 스냅샷은 삭제할 수 없습니다."))
+		return
+	}
+
+	if err := vmC.k8sService.WithContext(c.Request.Context()).DeleteVMSnapshot(snapshot); err != nil {
+		logger.FromContext(c.Request.Context()).Error("failed to delete snapshot", "snapshot", snapshot.Name, "error", err)
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to delete snapshot"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Snapshot deleted"})
+}
@@ -0,0 +1,181 @@
+package controllers
+
+import (
+	"fmt"
+	http "net/http"
+	"os"
+	"time"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/config"
+	"vm-controller/internal/middleware"
+	bundleservice "vm-controller/internal/services/bundle_service"
+	k8s_service "vm-controller/internal/services/k8s_service"
+	quotaservice "vm-controller/internal/services/quota_service"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+// 한 번에 받을 수 있는 청크 최대 크기
+const maxUploadChunkBytes = 64 << 20
+
+// maxUploadImageBytes는 업로드 가능한 이미지의 최대 (가상) 디스크 크기입니다: VM 루트 디스크 크기와 동일
+func maxUploadImageBytes(diskGi int) int64 {
+	if diskGi <= 0 {
+		diskGi = quotaservice.VmDiskSizeGi
+	}
+	return int64(diskGi) << 30
+}
+
+// CreateUploadVM은 업로드 방식 디스크를 가진 VM을 생성합니다.
+// 디스크 이미지를 받을 때까지 VM은 부팅되지 않으며, 이후 /vm/upload/chunk 로 이미지를 전송합니다.
+func (vmC *VirtualMachineController) CreateUploadVM(c *gin.Context) {
+	var req CreateVMParams
+
+	user_id, _ := c.Get("user_id")
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	if config.Get().OperatorMode {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Disk upload is not supported in operator mode"))
+		return
+	}
+
+	user, err := vmC.userService.WithContext(c.Request.Context()).FetchUserById(user_id.(string), true)
+	if err != nil || user == nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeUserNotFound, "User not found"))
+		return
+	}
+
+	req.VmImage = k8s_service.UploadImage
+	response, code, ok := vmC.createVM(c, user, req, bundleservice.UploadVMTemplate)
+	if !ok {
+		return
+	}
+	// 승인 대기 중이면 업로드 주소 없이 응답 (승인 후 생성된 VM에 업로드)
+	if code != http.StatusOK {
+		c.JSON(code, response)
+		return
+	}
+
+	flavor, _ := k8s_service.GetFlavor(vmC.flavorService, req.VmFlavor)
+	response["upload"] = gin.H{
+		"chunk_url": apiURL(c, "/vm/upload/chunk?vm_name="+req.VmName),
+		"max_bytes": maxUploadImageBytes(flavor.DiskGi),
+		"max_chunk": maxUploadChunkBytes,
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// UploadChunk는 Content-Range(bytes start-end/total) 헤더와 함께 이미지 청크를 받습니다.
+// start가 서버에 저장된 크기와 다르면 409와 함께 현재 offset을 반환하므로 중단된 업로드를 이어서 보낼 수 있습니다.
+func (vmC *VirtualMachineController) UploadChunk(c *gin.Context) {
+	vm, _, ok := vmC.fetchOwnedVM(c, c.Query("vm_name"))
+	if !ok {
+		return
+	}
+
+	if vm.Image != k8s_service.UploadImage {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "VM does not accept disk uploads"))
+		return
+	}
+
+	var start, end, total int64
+	if _, err := fmt.Sscanf(c.GetHeader("Content-Range"), "bytes %d-%d/%d", &start, &end, &total); err != nil || start > end || end >= total {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Content-Range header must be in the form 'bytes start-end/total'"))
+		return
+	}
+	maxBytes := maxUploadImageBytes(vm.DiskGi)
+	if total > maxBytes {
+		middleware.AbortWithError(c, apperrors.Newf(apperrors.CodePayloadTooLarge, "image exceeds the maximum size of %d bytes", maxBytes))
+		return
+	}
+	if end-start+1 > maxUploadChunkBytes {
+		middleware.AbortWithError(c, apperrors.Newf(apperrors.CodePayloadTooLarge, "chunk exceeds the maximum size of %d bytes", maxUploadChunkBytes))
+		return
+	}
+
+	// 느린 회선에서도 청크를 끝까지 받을 수 있도록 이 요청에 한해 서버 타임아웃 해제
+	controller := http.NewResponseController(c.Writer)
+	controller.SetReadDeadline(time.Time{})
+	controller.SetWriteDeadline(time.Time{})
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, end-start+1)
+	path := k8s_service.UploadStagingPath(vm.Namespace, vm.Name)
+
+	received, err := k8s_service.AppendUploadChunk(path, start, body, maxBytes)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeConflict).WithDetail("offset", received))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"offset": received, "total": total, "complete": received == total})
+}
+
+// FetchUpload는 지금까지 받은 크기와 디스크 상태를 반환합니다. (이어받기 시작 위치 확인용)
+func (vmC *VirtualMachineController) FetchUpload(c *gin.Context) {
+	vm, _, ok := vmC.fetchOwnedVM(c, c.Query("vm_name"))
+	if !ok {
+		return
+	}
+
+	var received int64
+	if info, err := os.Stat(k8s_service.UploadStagingPath(vm.Namespace, vm.Name)); err == nil {
+		received = info.Size()
+	}
+
+	phase, err := vmC.k8sService.WithContext(c.Request.Context()).GetDiskPhase(vm)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch disk status"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"offset": received, "max_bytes": maxUploadImageBytes(vm.DiskGi), "disk_phase": phase})
+}
+
+type CompleteUploadParams struct {
+	VmName string `json:"vm_name"`
+}
+
+// CompleteUpload는 받은 이미지의 형식/크기를 검사한 뒤 CDI로 전송합니다. 전송이 끝나면 VM이 부팅됩니다.
+func (vmC *VirtualMachineController) CompleteUpload(c *gin.Context) {
+	var req CompleteUploadParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	vm, u64, ok := vmC.fetchOwnedVM(c, req.VmName)
+	if !ok {
+		return
+	}
+
+	if vm.Image != k8s_service.UploadImage {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "VM does not accept disk uploads"))
+		return
+	}
+
+	path := k8s_service.UploadStagingPath(vm.Namespace, vm.Name)
+	if _, err := os.Stat(path); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "No uploaded image"))
+		return
+	}
+
+	// 검사에 실패한 이미지는 삭제하여 처음부터 다시 업로드하도록 함
+	format, err := k8s_service.InspectDiskImage(path, maxUploadImageBytes(vm.DiskGi))
+	if err == nil {
+		err = k8s_service.ScanDiskImage(path)
+	}
+	if err != nil {
+		os.Remove(path)
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeUnprocessable))
+		return
+	}
+
+	vmC.vmEventService.RecordOperation(vm.Name, "upload", u64)
+	operationID := vmC.k8sService.WithRequest(c.Request.Context()).UploadDiskImageAsync(vm, path, c.GetString("trace_id"))
+
+	c.JSON(http.StatusAccepted, gin.H{"vm": newVMResponse(vm), "format": format, "operation_id": operationID})
+}
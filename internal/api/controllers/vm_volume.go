@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"errors"
+	http "net/http"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/middleware"
+	"vm-controller/internal/models"
+	quotaservice "vm-controller/internal/services/quota_service"
+	volumeservice "vm-controller/internal/services/volume_service"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+type AttachVolumeParams struct {
+	VmName string `json:"vm_name"`
+	SizeGi int    `json:"size_gi"` // 추가 디스크 크기 (GiB)
+}
+
+type DetachVolumeParams struct {
+	VmName     string `json:"vm_name"`
+	VolumeName string `json:"volume_name"`
+}
+
+// AttachVolume은 빈 데이터 디스크를 만들어 실행 중인 VM에 핫플러그합니다. 완료 여부는 목록 조회로 확인합니다.
+// 게스트에는 SCSI 디스크로 나타나므로 사용자가 파티션/포맷 후 마운트합니다.
+// POST /api/vm/volume/attach
+func (vmC *VirtualMachineController) AttachVolume(c *gin.Context) {
+	var req AttachVolumeParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	volumeService := vmC.volumeService
+	if req.SizeGi <= 0 || req.SizeGi > volumeService.MaxSizeGi() {
+		middleware.AbortWithError(c, apperrors.Newf(apperrors.CodeInvalidRequest, "size_gi must be between 1 and %d", volumeService.MaxSizeGi()))
+		return
+	}
+
+	vm, u64, ok := vmC.fetchOwnedVM(c, req.VmName)
+	if !ok {
+		return
+	}
+
+	// 핫플러그는 실행 중인 VM 인스턴스에만 가능
+	if vm.Status != models.VmStatusRunning {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidState, "현재 상태("+string(vm.Status)+")에서는 요청을 처리할 수 없습니다."))
+		return
+	}
+
+	// 쿼터 확인 (추가 디스크도 스토리지 쿼터에 포함)
+	headrooms, err := vmC.quotaService.Check(u64, map[quotaservice.Dimension]int{quotaservice.DimensionStorage: req.SizeGi})
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.From(err, apperrors.CodeForbidden))
+		return
+	}
+
+	volume := &models.VolumeAttachment{
+		UserID:    u64,
+		VmName:    vm.Name,
+		Namespace: vm.Namespace,
+		Name:      volumeService.VolumeName(vm.Name),
+		SizeGi:    req.SizeGi,
+		Status:    models.VolumeStatusAttaching,
+	}
+
+	if err := volumeService.CreateVolume(volume); err != nil {
+		if errors.Is(err, volumeservice.ErrVolumeLimit) {
+			middleware.AbortWithError(c, apperrors.Newf(apperrors.CodeLimitExceeded, "VM당 추가 디스크는 최대 %d개까지 연결할 수 있습니다.", volumeService.Limit()))
+			return
+		}
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to attach volume"))
+		return
+	}
+
+	vmC.vmEventService.RecordOperation(vm.Name, "volume.attach", u64)
+	vmC.quotaService.NotifySoftLimits(u64, headrooms)
+	operationID := vmC.k8sService.WithRequest(c.Request.Context()).AttachVMVolumeAsync(vm, volume, c.GetString("trace_id"))
+
+	c.JSON(http.StatusAccepted, gin.H{"volume": volume, "operation_id": operationID})
+}
+
+// DetachVolume은 추가 디스크를 VM에서 분리하고 삭제합니다. 디스크의 데이터도 함께 삭제됩니다.
+// POST /api/vm/volume/detach
+func (vmC *VirtualMachineController) DetachVolume(c *gin.Context) {
+	var req DetachVolumeParams
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+		return
+	}
+
+	vm, u64, ok := vmC.fetchOwnedVM(c, req.VmName)
+	if !ok {
+		return
+	}
+
+	volume, err := vmC.volumeService.FetchVolume(vm.Name, req.VolumeName)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch volume"))
+		return
+	}
+	if volume == nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeVolumeNotFound, "Volume not found"))
+		return
+	}
+
+	// 연결/분리가 진행 중인 디스크는 끝난 뒤에 요청
+	switch volume.Status {
+	case models.VolumeStatusAttached, models.VolumeStatusFailed:
+	default:
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInvalidState, "현재 상태("+string(volume.Status)+")에서는 요청을 처리할 수 없습니다."))
+		return
+	}
+
+	vmC.vmEventService.RecordOperation(vm.Name, "volume.detach", u64)
+	operationID := vmC.k8sService.WithRequest(c.Request.Context()).DetachVMVolumeAsync(vm, volume, c.GetString("trace_id"))
+
+	volume.Status = models.VolumeStatusDetaching
+	c.JSON(http.StatusAccepted, gin.H{"volume": volume, "operation_id": operationID})
+}
+
+// FetchVolumes는 VM의 추가 디스크 목록을 반환합니다.
+// GET /api/vm/volume?vm_name=
+func (vmC *VirtualMachineController) FetchVolumes(c *gin.Context) {
+	vm, _, ok := vmC.fetchOwnedVM(c, c.Query("vm_name"))
+	if !ok {
+		return
+	}
+
+	volumeService := vmC.volumeService
+	volumes, err := volumeService.FetchVmVolumes(vm.Name)
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to fetch volumes"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"volumes": volumes, "limit": volumeService.Limit(), "max_size_gi": volumeService.MaxSizeGi()})
+}
@@ -0,0 +1,256 @@
+// Package grpcapi는 내부 자동화 도구용 gRPC API(proto/vmcontroller/v1)를 제공합니다.
+// REST 컨트롤러와 같은 서비스 계층(vmlifecycleservice 등)을 사용하므로 검증/권한/상태 전이 규칙이 같습니다.
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"vm-controller/internal/api/routes"
+	"vm-controller/internal/config"
+	"vm-controller/internal/logger"
+	"vm-controller/internal/middleware"
+	"vm-controller/internal/policy"
+	"vm-controller/internal/services/k8s_service"
+	vmlifecycleservice "vm-controller/internal/services/vm_lifecycle_service"
+	pb "vm-controller/proto/vmcontroller/v1"
+
+	"github.com/google/uuid"
+	cast "github.com/spf13/cast"
+	errdetails "google.golang.org/genproto/googleapis/rpc/errdetails"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	credentials "google.golang.org/grpc/credentials"
+	metadata "google.golang.org/grpc/metadata"
+	status "google.golang.org/grpc/status"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+)
+
+type subjectKey struct{}
+
+type traceKey struct{}
+
+// NewServer는 인증 인터셉터와 VMService/UserService가 등록된 gRPC 서버를 만듭니다.
+// REST와 같은 요청 제한과 핸들러 제한 시간을 인터셉터로 적용합니다. (작업 큐 과부하는 VmLifecycleService가 거부)
+func NewServer(container *routes.Container, opts ...grpc.ServerOption) *grpc.Server {
+	cfg := config.Get()
+	opts = append(opts, grpc.ChainUnaryInterceptor(
+		authInterceptor,
+		rateLimitInterceptor(rateLimitPolicy(cfg, container)),
+		capabilityInterceptor,
+		deadlineInterceptor(cfg),
+	))
+	srv := grpc.NewServer(opts...)
+
+	pb.RegisterVMServiceServer(srv, &vmServer{
+		lifecycleService: container.LifecycleService,
+		vmService:        container.VmService,
+		userService:      container.UserService,
+	})
+	pb.RegisterUserServiceServer(srv, &userServer{userService: container.UserService})
+
+	return srv
+}
+
+// Run은 GRPC_PORT에서 gRPC API를 제공합니다. (비어 있으면 비활성화)
+// 메인 서버와 같은 TLS 인증서(TLS_CERT_FILE/TLS_KEY_FILE)가 설정되어 있으면 TLS로 제공합니다.
+func Run(cfg *config.Config, container *routes.Container) {
+	if cfg.GRPCPort == "" {
+		return
+	}
+
+	var opts []grpc.ServerOption
+	if cfg.TLSCertFile != "" && cfg.TLSKeyFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(cfg.TLSCertFile, cfg.TLSKeyFile)
+		if err != nil {
+			log.Printf("gRPC server not started: %v", err)
+			return
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%s", cfg.GRPCPort))
+	if err != nil {
+		log.Printf("gRPC server not started: %v", err)
+		return
+	}
+
+	log.Printf("Starting gRPC server on port %s", cfg.GRPCPort)
+	if err := NewServer(container, opts...).Serve(listener); err != nil {
+		log.Printf("gRPC server stopped: %v", err)
+	}
+}
+
+// authInterceptor는 metadata "authorization: Bearer <token>"을 REST 로그인 토큰과 같은 규칙으로 검증하고,
+// DB의 현재 권한으로 만든 Subject를 컨텍스트에 저장합니다. traceparent가 있으면 trace를 이어 씁니다.
+func authInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "로그인 토큰이 없습니다.")
+	}
+	if !strings.HasPrefix(values[0], "Bearer ") {
+		return nil, status.Error(codes.Unauthenticated, "유효하지 않은 토큰 형식입니다.")
+	}
+
+	userID, err := middleware.ParseToken(strings.TrimPrefix(values[0], "Bearer "))
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	u64, err := cast.ToUintE(userID)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "사용자를 찾을 수 없습니다.")
+	}
+	subject, err := middleware.UserSubject(u64)
+	if errors.Is(err, middleware.ErrUserSuspended) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "사용자를 찾을 수 없습니다.")
+	}
+
+	traceID := ""
+	if traceparent := md.Get("traceparent"); len(traceparent) > 0 {
+		traceID = logger.ParseTraceID(traceparent[0])
+	}
+	if traceID == "" {
+		traceID = logger.NewTraceID()
+	}
+
+	// REST와 같이 x-request-id를 이어 쓰거나 새로 만들고 응답 헤더로 돌려줌
+	requestID := ""
+	if values := md.Get("x-request-id"); len(values) > 0 {
+		requestID = values[0]
+	}
+	if !middleware.ValidRequestID(requestID) {
+		requestID = uuid.NewString()
+	}
+	grpc.SetHeader(ctx, metadata.Pairs("x-request-id", requestID))
+
+	ctx = context.WithValue(ctx, subjectKey{}, subject)
+	ctx = context.WithValue(ctx, traceKey{}, traceID)
+	ctx = logger.WithRequest(ctx, requestID, traceID)
+	return handler(ctx, req)
+}
+
+// capabilityInterceptor는 KubeVirt/CDI가 설치되지 않은 클러스터에서 VMService 호출을 Unimplemented로 거부합니다. (REST의 501과 같음)
+func capabilityInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if strings.HasPrefix(info.FullMethod, "/"+pb.VMService_ServiceDesc.ServiceName+"/") {
+		if available, reason := k8s_service.VMFeaturesAvailable(); !available {
+			return nil, status.Error(codes.Unimplemented, reason)
+		}
+	}
+	return handler(ctx, req)
+}
+
+func subjectFrom(ctx context.Context) policy.Subject {
+	subject, _ := ctx.Value(subjectKey{}).(policy.Subject)
+	return subject
+}
+
+func traceIDFrom(ctx context.Context) string {
+	traceID, _ := ctx.Value(traceKey{}).(string)
+	return traceID
+}
+
+// rateLimitPolicy는 REST와 같은 규칙 이름을 써서 한 사용자의 REST/gRPC 요청이 같은 버킷을 쓰도록 합니다.
+func rateLimitPolicy(cfg *config.Config, container *routes.Container) middleware.RateLimitPolicy {
+	policy := middleware.RateLimitPolicy{
+		Default: middleware.RateLimitRule{Name: "api", Limit: cfg.RateLimitAPI},
+		Routes: map[string]middleware.RateLimitRule{
+			pb.VMService_CreateVM_FullMethodName: {Name: "vm.create", Limit: cfg.RateLimitVMCreate},
+		},
+	}
+	if container.BlocklistService != nil {
+		policy.Take = container.BlocklistService.TakeToken
+	}
+	return policy
+}
+
+// rateLimitInterceptor는 인증된 사용자별로 요청 수를 제한하고, 초과하면 ResourceExhausted와 재시도 대기 시간(RetryInfo)을 반환합니다.
+func rateLimitInterceptor(policy middleware.RateLimitPolicy) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if policy.Take == nil {
+			return handler(ctx, req)
+		}
+
+		subject := "user:" + strconv.FormatUint(uint64(subjectFrom(ctx).UserID), 10)
+		rule, retryAfter, allowed := policy.Allow(ctx, subject, info.FullMethod)
+		if !allowed {
+			return nil, retryableError(codes.ResourceExhausted, "Too many requests ("+rule.Name+"), please retry later", retryAfter)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// methodTimeout은 REST 라우트와 같은 핸들러 제한 시간입니다. (조회는 RouteReadTimeout, 생성/재시작은 RouteCreateTimeout)
+func methodTimeout(cfg *config.Config, method string) time.Duration {
+	switch method {
+	case pb.VMService_ListVMs_FullMethodName, pb.VMService_GetVM_FullMethodName, pb.UserService_GetMe_FullMethodName:
+		return cfg.RouteReadTimeout
+	case pb.VMService_CreateVM_FullMethodName, pb.VMService_RestartVM_FullMethodName:
+		return cfg.RouteCreateTimeout
+	}
+	return cfg.RouteWriteTimeout
+}
+
+// deadlineInterceptor는 호출에 핸들러 제한 시간을 적용합니다. 클라이언트가 더 짧은 deadline을 보냈으면 그것을 따릅니다.
+// 제한 시간을 넘기면 DeadlineExceeded를 반환하며, 이미 시작된 백그라운드 작업은 계속 진행됩니다.
+func deadlineInterceptor(cfg *config.Config) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		timeout := methodTimeout(cfg, info.FullMethod)
+		if timeout <= 0 {
+			return handler(ctx, req)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		resp, err := handler(ctx, req)
+		if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			logger.FromContext(ctx).Warn("grpc call timed out", "method", info.FullMethod, "timeout", timeout.String())
+			return nil, status.Error(codes.DeadlineExceeded, "request timed out")
+		}
+		return resp, err
+	}
+}
+
+// retryableError는 재시도 대기 시간을 RetryInfo 상세로 담은 gRPC 에러입니다. (REST의 Retry-After)
+func retryableError(code codes.Code, message string, retryAfter time.Duration) error {
+	st := status.New(code, message)
+	if detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// lifecycleError는 서비스 계층의 에러 유형을 gRPC 상태 코드로 바꿉니다.
+func lifecycleError(err error, fallback string) error {
+	code := codes.Internal
+	switch vmlifecycleservice.KindOf(err) {
+	case vmlifecycleservice.KindInvalid:
+		code = codes.InvalidArgument
+	case vmlifecycleservice.KindNotFound:
+		code = codes.NotFound
+	case vmlifecycleservice.KindForbidden:
+		code = codes.PermissionDenied
+	case vmlifecycleservice.KindConflict:
+		code = codes.FailedPrecondition
+	case vmlifecycleservice.KindUnavailable:
+		code = codes.Unavailable
+	case vmlifecycleservice.KindTimeout:
+		code = codes.DeadlineExceeded
+	case vmlifecycleservice.KindBusy:
+		retryAfter, _ := vmlifecycleservice.RetryAfterOf(err)
+		return retryableError(codes.ResourceExhausted, vmlifecycleservice.MessageOf(err, fallback), retryAfter)
+	}
+
+	return status.Error(code, vmlifecycleservice.MessageOf(err, fallback))
+}
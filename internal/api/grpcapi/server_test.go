@@ -0,0 +1,141 @@
+package grpcapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"vm-controller/internal/api/routes"
+	"vm-controller/internal/config"
+	"vm-controller/internal/policy"
+	vmlifecycleservice "vm-controller/internal/services/vm_lifecycle_service"
+	pb "vm-controller/proto/vmcontroller/v1"
+
+	errdetails "google.golang.org/genproto/googleapis/rpc/errdetails"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+func withSubject(userID uint) context.Context {
+	return context.WithValue(context.Background(), subjectKey{}, policy.Subject{UserID: userID})
+}
+
+func retryDelay(t *testing.T, err error) time.Duration {
+	t.Helper()
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			return info.GetRetryDelay().AsDuration()
+		}
+	}
+	t.Fatalf("error %v has no RetryInfo", err)
+	return 0
+}
+
+// REST와 같은 규칙 이름의 버킷을 쓰고, CreateVM은 기본 버킷을 통과한 뒤 vm.create 버킷도 써야 함
+func TestRateLimitInterceptor(t *testing.T) {
+	remaining := map[string]int{}
+	var keys []string
+	policy := rateLimitPolicy(&config.Config{
+		RateLimitAPI:      config.RateLimit{Requests: 10, Per: time.Minute},
+		RateLimitVMCreate: config.RateLimit{Requests: 1, Per: 10 * time.Minute},
+	}, &routes.Container{})
+	policy.Take = func(_ context.Context, key string, limit int, per time.Duration) (time.Duration, bool) {
+		keys = append(keys, key)
+		left, ok := remaining[key]
+		if !ok {
+			left = limit
+		}
+		if left <= 0 {
+			return per, false
+		}
+		remaining[key] = left - 1
+		return 0, true
+	}
+	interceptor := rateLimitInterceptor(policy)
+
+	calls := 0
+	handler := func(ctx context.Context, req any) (any, error) {
+		calls++
+		return nil, nil
+	}
+	create := &grpc.UnaryServerInfo{FullMethod: pb.VMService_CreateVM_FullMethodName}
+
+	if _, err := interceptor(withSubject(7), nil, create, handler); err != nil {
+		t.Fatalf("first create: %v", err)
+	}
+	_, err := interceptor(withSubject(7), nil, create, handler)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("second create: code = %v, want ResourceExhausted", status.Code(err))
+	}
+	if delay := retryDelay(t, err); delay != 10*time.Minute {
+		t.Errorf("retry delay = %v, want 10m", delay)
+	}
+	if calls != 1 {
+		t.Errorf("handler called %d times, want 1", calls)
+	}
+
+	// 다른 메서드는 기본 버킷만 사용
+	get := &grpc.UnaryServerInfo{FullMethod: pb.VMService_GetVM_FullMethodName}
+	if _, err := interceptor(withSubject(7), nil, get, handler); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	want := []string{"api:user:7", "vm.create:user:7", "api:user:7", "vm.create:user:7", "api:user:7"}
+	if len(keys) != len(want) {
+		t.Fatalf("bucket keys = %v, want %v", keys, want)
+	}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Errorf("bucket key %d = %q, want %q", i, keys[i], want[i])
+		}
+	}
+}
+
+// 저장소가 없으면(테스트용 컨테이너 등) 제한하지 않음
+func TestRateLimitInterceptorWithoutStore(t *testing.T) {
+	interceptor := rateLimitInterceptor(rateLimitPolicy(&config.Config{RateLimitAPI: config.RateLimit{Requests: 1, Per: time.Minute}}, &routes.Container{}))
+	called := false
+	_, err := interceptor(withSubject(1), nil, &grpc.UnaryServerInfo{FullMethod: pb.VMService_ListVMs_FullMethodName},
+		func(ctx context.Context, req any) (any, error) { called = true; return nil, nil })
+	if err != nil || !called {
+		t.Fatalf("err = %v, called = %v; want the handler to run", err, called)
+	}
+}
+
+func TestDeadlineInterceptor(t *testing.T) {
+	cfg := &config.Config{RouteReadTimeout: time.Hour, RouteWriteTimeout: 20 * time.Millisecond, RouteCreateTimeout: time.Hour}
+	interceptor := deadlineInterceptor(cfg)
+
+	blocking := func(ctx context.Context, req any) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: pb.VMService_StopVM_FullMethodName}, blocking)
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("code = %v, want DeadlineExceeded", status.Code(err))
+	}
+
+	// 조회는 RouteReadTimeout을 사용
+	_, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: pb.VMService_GetVM_FullMethodName},
+		func(ctx context.Context, req any) (any, error) {
+			deadline, ok := ctx.Deadline()
+			if !ok || time.Until(deadline) < 30*time.Minute {
+				t.Errorf("GetVM deadline = %v (set %v), want about 1h", time.Until(deadline), ok)
+			}
+			return nil, nil
+		})
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+}
+
+func TestLifecycleBusyError(t *testing.T) {
+	err := lifecycleError(&vmlifecycleservice.Error{Kind: vmlifecycleservice.KindBusy, Message: "Server is busy, please retry later", RetryAfter: 30 * time.Second}, "fallback")
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("code = %v, want ResourceExhausted", status.Code(err))
+	}
+	if delay := retryDelay(t, err); delay != 30*time.Second {
+		t.Errorf("retry delay = %v, want 30s", delay)
+	}
+}
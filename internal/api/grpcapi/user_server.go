@@ -0,0 +1,36 @@
+package grpcapi
+
+import (
+	"context"
+	"strconv"
+
+	userservice "vm-controller/internal/services/user_service"
+	pb "vm-controller/proto/vmcontroller/v1"
+
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+type userServer struct {
+	pb.UnimplementedUserServiceServer
+
+	userService *userservice.UserService
+}
+
+// GetMe는 요청자 계정 정보를 반환합니다. (비밀번호 해시 제외)
+func (s *userServer) GetMe(ctx context.Context, _ *pb.GetMeRequest) (*pb.User, error) {
+	user, err := s.userService.WithContext(ctx).FetchUserById(strconv.FormatUint(uint64(subjectFrom(ctx).UserID), 10), true)
+	if err != nil || user == nil {
+		return nil, status.Error(codes.NotFound, "User not found")
+	}
+
+	return &pb.User{
+		Id:        uint64(user.ID),
+		Username:  user.Username,
+		StudentId: user.UserStudentId,
+		Email:     user.Email,
+		Namespace: user.Namespace,
+		Role:      user.Role,
+		CreatedAt: timestamp(&user.CreatedAt),
+	}, nil
+}
@@ -0,0 +1,191 @@
+package grpcapi
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"vm-controller/internal/models"
+	"vm-controller/internal/policy"
+	bundleservice "vm-controller/internal/services/bundle_service"
+	k8s_service "vm-controller/internal/services/k8s_service"
+	userservice "vm-controller/internal/services/user_service"
+	vmlifecycleservice "vm-controller/internal/services/vm_lifecycle_service"
+	vm_service "vm-controller/internal/services/vm_service"
+	pb "vm-controller/proto/vmcontroller/v1"
+
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type vmServer struct {
+	pb.UnimplementedVMServiceServer
+
+	lifecycleService *vmlifecycleservice.VmLifecycleService
+	vmService        *vm_service.VmService
+	userService      *userservice.UserService
+}
+
+// ListVMs는 요청자의 VM 목록을 반환합니다. 조회 조건은 GET /api/v1/vm/fetch와 같습니다.
+func (s *vmServer) ListVMs(ctx context.Context, req *pb.ListVMsRequest) (*pb.ListVMsResponse, error) {
+	userID := subjectFrom(ctx).UserID
+	query := vm_service.VmQuery{
+		UserID: &userID,
+		Search: req.GetQuery(),
+		Sort:   req.GetSort(),
+		Page:   int(req.GetPage()),
+		Limit:  int(req.GetLimit()),
+	}
+	if query.Page > 0 && query.Limit == 0 {
+		query.Limit = 20
+	}
+	for _, vmStatus := range req.GetStatuses() {
+		query.Statuses = append(query.Statuses, models.EnumVmStatus(vmStatus))
+	}
+	if err := query.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	page, err := s.vmService.WithContext(ctx).QueryVMs(query)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Failed to fetch VMs")
+	}
+
+	response := &pb.ListVMsResponse{Total: page.Total}
+	if page.Limit > 0 {
+		response.Page = int32(page.Page)
+		response.Limit = int32(page.Limit)
+		response.HasMore = int64(page.Page*page.Limit) < page.Total
+	}
+	for i := range page.VMs {
+		response.Vms = append(response.Vms, vmMessage(&page.VMs[i]))
+	}
+	return response, nil
+}
+
+func (s *vmServer) GetVM(ctx context.Context, req *pb.GetVMRequest) (*pb.VM, error) {
+	vm, err := s.lifecycleService.WithContext(ctx).FetchOwned(subjectFrom(ctx), policy.ActionRead, req.GetName())
+	if err != nil {
+		return nil, lifecycleError(err, "Failed to fetch VM")
+	}
+	return vmMessage(vm), nil
+}
+
+// CreateVM은 POST /api/v1/vm/create와 같이 생략한 항목을 사용자 기본값으로 채워 VM을 생성합니다.
+func (s *vmServer) CreateVM(ctx context.Context, req *pb.CreateVMRequest) (*pb.CreateVMResponse, error) {
+	subject := subjectFrom(ctx)
+	user, err := s.userService.WithContext(ctx).FetchUserById(strconv.FormatUint(uint64(subject.UserID), 10), true)
+	if err != nil || user == nil {
+		return nil, status.Error(codes.NotFound, "User not found")
+	}
+
+	params := vmlifecycleservice.CreateParams{
+		VmName:           req.GetVmName(),
+		VmSSHPassword:    req.GetVmSshPassword(),
+		VmImage:          req.GetVmImage(),
+		VmFlavor:         req.GetVmFlavor(),
+		VmHostPrefix:     req.GetVmHostPrefix(),
+		GeneratePassword: req.GetGeneratePassword(),
+		CloudInit:        req.GetCloudInit(),
+	}
+	for _, network := range req.GetNetworks() {
+		params.Networks = append(params.Networks, vmlifecycleservice.NetworkParams{Network: network.GetNetwork(), Address: network.GetAddress()})
+	}
+	// proto3 반복 필드는 생략과 빈 목록을 구분할 수 없으므로 비어 있으면 기본 SSH 키를 사용
+	for _, id := range req.GetSshKeyIds() {
+		params.SSHKeyIDs = append(params.SSHKeyIDs, uint(id))
+	}
+
+	lifecycle := s.lifecycleService.WithContext(ctx)
+	if err := lifecycle.ApplyDefaults(user.ID, &params); err != nil {
+		return nil, status.Error(codes.Internal, "Failed to load vm defaults")
+	}
+
+	result, err := lifecycle.Create(user, params, bundleservice.VMTemplate, traceIDFrom(ctx))
+	if err != nil {
+		return nil, lifecycleError(err, "Failed to create VM")
+	}
+
+	response := &pb.CreateVMResponse{
+		OperationId: uint64(result.OperationID),
+		Password:    result.Password,
+		Warning:     result.Warning,
+		Accepted:    result.Accepted,
+	}
+	if result.VM != nil {
+		response.Vm = vmMessage(result.VM)
+	}
+	if result.Approval != nil {
+		response.ApprovalId = uint64(result.Approval.ID)
+	}
+	return response, nil
+}
+
+func (s *vmServer) StartVM(ctx context.Context, req *pb.VMActionRequest) (*pb.VMActionResponse, error) {
+	vm, operationID, err := s.lifecycleService.WithContext(ctx).Start(subjectFrom(ctx), req.GetVmName(), traceIDFrom(ctx))
+	if err != nil {
+		return nil, lifecycleError(err, "Failed to update VM")
+	}
+	return &pb.VMActionResponse{Vm: vmMessage(vm), OperationId: uint64(operationID)}, nil
+}
+
+func (s *vmServer) StopVM(ctx context.Context, req *pb.VMActionRequest) (*pb.VMActionResponse, error) {
+	vm, operationID, err := s.lifecycleService.WithContext(ctx).Stop(subjectFrom(ctx), req.GetVmName(), traceIDFrom(ctx))
+	if err != nil {
+		return nil, lifecycleError(err, "Failed to update VM")
+	}
+	return &pb.VMActionResponse{Vm: vmMessage(vm), OperationId: uint64(operationID)}, nil
+}
+
+func (s *vmServer) DeleteVM(ctx context.Context, req *pb.DeleteVMRequest) (*pb.VMActionResponse, error) {
+	vm, operationID, err := s.lifecycleService.WithContext(ctx).Delete(subjectFrom(ctx), req.GetVmName(), req.GetConfirm(), req.GetForce(), traceIDFrom(ctx))
+	if err != nil {
+		return nil, lifecycleError(err, "Failed to update VM")
+	}
+	return &pb.VMActionResponse{Vm: vmMessage(vm), OperationId: uint64(operationID)}, nil
+}
+
+func (s *vmServer) RestartVM(ctx context.Context, req *pb.VMActionRequest) (*pb.VMActionResponse, error) {
+	vm, err := s.lifecycleService.WithContext(ctx).Restart(subjectFrom(ctx), req.GetVmName())
+	if err != nil {
+		return nil, lifecycleError(err, "Failed to restart VM")
+	}
+	return &pb.VMActionResponse{Vm: vmMessage(vm)}, nil
+}
+
+// vmMessage는 REST 응답(VMResponse)과 같이 비밀번호를 제외한 VM 정보를 만듭니다.
+func vmMessage(vm *models.VirtualMachine) *pb.VM {
+	message := &pb.VM{
+		Id:                  uint64(vm.ID),
+		Name:                vm.Name,
+		Namespace:           vm.Namespace,
+		Status:              string(vm.Status),
+		DesiredState:        string(vm.DesiredState),
+		Image:               vm.Image,
+		SshUser:             k8s_service.VMSSHUser(vm),
+		Flavor:              vm.Flavor,
+		DiskGi:              int32(vm.DiskGi),
+		NodePort:            vm.NodePort,
+		DnsHost:             vm.DnsHost,
+		MacAddress:          vm.MacAddress,
+		SshKeys:             vm.SSHKeys,
+		BundleChannel:       vm.BundleChannel,
+		BundleVersion:       vm.BundleVersion,
+		CredentialsRevealed: vm.CredentialsRevealedAt != nil,
+		ExpiresAt:           timestamp(vm.ExpiresAt),
+		CreatedAt:           timestamp(&vm.CreatedAt),
+		UpdatedAt:           timestamp(&vm.UpdatedAt),
+	}
+	for _, network := range vm.Networks {
+		message.Networks = append(message.Networks, &pb.VMNetwork{Network: network.Network, MacAddress: network.MacAddress, Address: network.Address})
+	}
+	return message
+}
+
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
@@ -0,0 +1,251 @@
+package routes
+
+import (
+	controllers "vm-controller/internal/api/controllers"
+	approvalservice "vm-controller/internal/services/approval_service"
+	auditservice "vm-controller/internal/services/audit_service"
+	blocklistservice "vm-controller/internal/services/blocklist_service"
+	bundleservice "vm-controller/internal/services/bundle_service"
+	consoleservice "vm-controller/internal/services/console_service"
+	databaseservice "vm-controller/internal/services/database_service"
+	deploymentservice "vm-controller/internal/services/deployment_service"
+	flavorchangeservice "vm-controller/internal/services/flavor_change_service"
+	flavorservice "vm-controller/internal/services/flavor_service"
+	imageservice "vm-controller/internal/services/image_service"
+	"vm-controller/internal/services/k8s_service"
+	networkservice "vm-controller/internal/services/network_service"
+	nodepoolservice "vm-controller/internal/services/nodepool_service"
+	notificationservice "vm-controller/internal/services/notification_service"
+	operationservice "vm-controller/internal/services/operation_service"
+	passwordservice "vm-controller/internal/services/password_service"
+	preferenceservice "vm-controller/internal/services/preference_service"
+	quotaservice "vm-controller/internal/services/quota_service"
+	reportservice "vm-controller/internal/services/report_service"
+	resourceservice "vm-controller/internal/services/resource_service"
+	snapshotservice "vm-controller/internal/services/snapshot_service"
+	sshkeyservice "vm-controller/internal/services/ssh_key_service"
+	statsservice "vm-controller/internal/services/stats_service"
+	userservice "vm-controller/internal/services/user_service"
+	vmeventservice "vm-controller/internal/services/vm_event_service"
+	vmlifecycleservice "vm-controller/internal/services/vm_lifecycle_service"
+	vm_service "vm-controller/internal/services/vm_service"
+	volumeservice "vm-controller/internal/services/volume_service"
+)
+
+// Services는 API 서버가 사용하는 DB 서비스 묶음입니다.
+// 서버 시작 시 NewServices로 한 번 만들고, K8sService, 컨트롤러, 백그라운드 루프에 생성자로 주입합니다.
+type Services struct {
+	ApprovalService     *approvalservice.ApprovalService
+	AuditService        *auditservice.AuditService
+	BlocklistService    *blocklistservice.BlocklistService
+	BundleService       *bundleservice.BundleService
+	ConsoleService      *consoleservice.ConsoleService
+	DatabaseService     *databaseservice.DatabaseService
+	DeploymentService   *deploymentservice.DeploymentService
+	FlavorChangeService *flavorchangeservice.FlavorChangeService
+	FlavorService       *flavorservice.FlavorService
+	ImageService        *imageservice.ImageService
+	NetworkService      *networkservice.NetworkService
+	NodePoolService     *nodepoolservice.NodePoolService
+	NotificationService *notificationservice.NotificationService
+	OperationService    *operationservice.OperationService
+	PasswordService     *passwordservice.PasswordService
+	PreferenceService   *preferenceservice.PreferenceService
+	QuotaService        *quotaservice.QuotaService
+	ReportService       *reportservice.ReportService
+	ResourceService     *resourceservice.ResourceService
+	SnapshotService     *snapshotservice.SnapshotService
+	SSHKeyService       *sshkeyservice.SSHKeyService
+	StatsService        *statsservice.StatsService
+	UserService         *userservice.UserService
+	VmEventService      *vmeventservice.VmEventService
+	VmService           *vm_service.VmService
+	VolumeService       *volumeservice.VolumeService
+}
+
+// NewServices는 서비스를 의존 관계 순서대로 만듭니다. 생성자가 환경 변수를 읽으므로 .env 로드(config.Load) 이후에 호출합니다.
+func NewServices() Services {
+	s := Services{
+		AuditService:        auditservice.NewAuditService(),
+		BlocklistService:    blocklistservice.NewBlocklistService(blocklistservice.NewStoreFromEnv()),
+		BundleService:       bundleservice.NewBundleService(),
+		ConsoleService:      consoleservice.NewConsoleService(),
+		DeploymentService:   deploymentservice.NewDeploymentService(),
+		FlavorChangeService: flavorchangeservice.NewFlavorChangeService(),
+		FlavorService:       flavorservice.NewFlavorService(),
+		ImageService:        imageservice.NewImageService(),
+		NetworkService:      networkservice.NewNetworkService(),
+		NodePoolService:     nodepoolservice.NewNodePoolService(),
+		NotificationService: notificationservice.NewNotificationService(),
+		OperationService:    operationservice.NewOperationService(),
+		PasswordService:     passwordservice.NewPasswordService(),
+		SnapshotService:     snapshotservice.NewSnapshotService(),
+		SSHKeyService:       sshkeyservice.NewSSHKeyService(),
+		StatsService:        statsservice.NewStatsService(),
+		UserService:         userservice.NewUserService(),
+		VmEventService:      vmeventservice.NewVmEventService(),
+		VmService:           vm_service.NewVmService(),
+		VolumeService:       volumeservice.NewVolumeService(),
+	}
+	s.ApprovalService = approvalservice.NewApprovalService(s.NotificationService)
+	s.QuotaService = quotaservice.NewQuotaService(s.NotificationService)
+	s.ReportService = reportservice.NewReportService(s.NotificationService)
+	s.PreferenceService = preferenceservice.NewPreferenceService(s.SSHKeyService)
+	s.DatabaseService = databaseservice.NewDatabaseService(s.QuotaService)
+	s.ResourceService = resourceservice.NewResourceService(s.VmService, s.DeploymentService, s.DatabaseService)
+	return s
+}
+
+// K8sDependencies는 K8sService에 주입할 서비스입니다. (k8s_service.NewK8sService)
+func (s Services) K8sDependencies() k8s_service.Dependencies {
+	return k8s_service.Dependencies{
+		AuditService:        s.AuditService,
+		BundleService:       s.BundleService,
+		DatabaseService:     s.DatabaseService,
+		DeploymentService:   s.DeploymentService,
+		FlavorChangeService: s.FlavorChangeService,
+		FlavorService:       s.FlavorService,
+		ImageService:        s.ImageService,
+		NodePoolService:     s.NodePoolService,
+		NotificationService: s.NotificationService,
+		OperationService:    s.OperationService,
+		ReportService:       s.ReportService,
+		SnapshotService:     s.SnapshotService,
+		UserService:         s.UserService,
+		VmEventService:      s.VmEventService,
+		VmService:           s.VmService,
+		VolumeService:       s.VolumeService,
+	}
+}
+
+// Container는 라우터가 사용하는 서비스 묶음입니다.
+// 컨트롤러는 전역 인스턴스 대신 Container의 서비스를 생성자로 주입받으므로,
+// 테스트에서는 필드를 바꾼 Container로 다른 설정의 라우터를 만들 수 있습니다.
+type Container struct {
+	Services
+
+	K8sService *k8s_service.K8sService
+	// VM 생성/시작/정지/삭제에 쓰는 클러스터 의존 부분 (기본값 K8sService, 테스트에서는 k8sfake.Provisioner)
+	Provisioner k8s_service.K8sProvisioner
+
+	// REST 컨트롤러와 gRPC 서버가 함께 쓰는 VM 생성/수명 주기 처리 (위 서비스로 구성)
+	LifecycleService *vmlifecycleservice.VmLifecycleService
+}
+
+// NewContainer는 NewServices로 만든 서비스와 그 서비스를 주입한 K8sService로 Container를 만듭니다.
+func NewContainer(services Services, k8sService *k8s_service.K8sService) *Container {
+	c := &Container{
+		Services:    services,
+		K8sService:  k8sService,
+		Provisioner: k8sService,
+	}
+	c.LifecycleService = vmlifecycleservice.NewVmLifecycleService(c.Provisioner, vmlifecycleservice.Dependencies{
+		ApprovalService:   c.ApprovalService,
+		BundleService:     c.BundleService,
+		FlavorService:     c.FlavorService,
+		ImageService:      c.ImageService,
+		NetworkService:    c.NetworkService,
+		OperationService:  c.OperationService,
+		PasswordService:   c.PasswordService,
+		PreferenceService: c.PreferenceService,
+		QuotaService:      c.QuotaService,
+		SSHKeyService:     c.SSHKeyService,
+		UserService:       c.UserService,
+		VmEventService:    c.VmEventService,
+		VmService:         c.VmService,
+	})
+
+	return c
+}
+
+// controllerSet은 Container로 만든 API 컨트롤러입니다.
+type controllerSet struct {
+	health         *controllers.HealthController
+	auth           *controllers.AuthController
+	virtualMachine *controllers.VirtualMachineController
+	operation      *controllers.OperationController
+	user           *controllers.UserController
+	deployment     *controllers.DeploymentController
+	notification   *controllers.NotificationController
+	sshKey         *controllers.SSHKeyController
+	database       *controllers.DatabaseController
+	resource       *controllers.ResourceController
+	usage          *controllers.UsageController
+	terms          *controllers.TermsController
+	report         *controllers.ReportController
+	admin          *controllers.AdminController
+	version        *controllers.VersionController
+	stats          *controllers.StatsController
+	test           *controllers.TestController
+	interceptor    *controllers.Interceptor
+}
+
+func (c *Container) controllers() *controllerSet {
+	virtualMachine := controllers.NewVirtualMachineController(controllers.VirtualMachineDependencies{
+		K8sService:  c.K8sService,
+		Provisioner: c.Provisioner,
+
+		ApprovalService:     c.ApprovalService,
+		AuditService:        c.AuditService,
+		BundleService:       c.BundleService,
+		ConsoleService:      c.ConsoleService,
+		FlavorChangeService: c.FlavorChangeService,
+		FlavorService:       c.FlavorService,
+		ImageService:        c.ImageService,
+		NetworkService:      c.NetworkService,
+		PreferenceService:   c.PreferenceService,
+		QuotaService:        c.QuotaService,
+		ReportService:       c.ReportService,
+		SnapshotService:     c.SnapshotService,
+		SSHKeyService:       c.SSHKeyService,
+		UserService:         c.UserService,
+		VmEventService:      c.VmEventService,
+		VmService:           c.VmService,
+		VolumeService:       c.VolumeService,
+		LifecycleService:    c.LifecycleService,
+	})
+
+	return &controllerSet{
+		health:         controllers.NewHealthController(c.K8sService),
+		auth:           controllers.NewAuthController(c.UserService),
+		virtualMachine: virtualMachine,
+		operation:      controllers.NewOperationController(c.OperationService),
+		user:           controllers.NewUserController(c.UserService, c.K8sService),
+		deployment:     controllers.NewDeploymentController(c.K8sService, c.UserService, c.DeploymentService),
+		notification:   controllers.NewNotificationController(c.NotificationService),
+		sshKey:         controllers.NewSSHKeyController(c.SSHKeyService),
+		database:       controllers.NewDatabaseController(c.K8sService, c.UserService, c.DatabaseService, c.DeploymentService),
+		resource:       controllers.NewResourceController(c.ResourceService),
+		usage:          controllers.NewUsageController(c.K8sService, c.UserService, c.QuotaService),
+		terms:          controllers.NewTermsController(c.ConsoleService),
+		report:         controllers.NewReportController(c.ReportService, c.AuditService),
+		admin: controllers.NewAdminController(controllers.AdminDependencies{
+			K8sService:  c.K8sService,
+			Provisioner: c.Provisioner,
+
+			ApprovalService:     c.ApprovalService,
+			AuditService:        c.AuditService,
+			BlocklistService:    c.BlocklistService,
+			BundleService:       c.BundleService,
+			ConsoleService:      c.ConsoleService,
+			DeploymentService:   c.DeploymentService,
+			FlavorService:       c.FlavorService,
+			ImageService:        c.ImageService,
+			NetworkService:      c.NetworkService,
+			NodePoolService:     c.NodePoolService,
+			NotificationService: c.NotificationService,
+			OperationService:    c.OperationService,
+			QuotaService:        c.QuotaService,
+			ReportService:       c.ReportService,
+			StatsService:        c.StatsService,
+			UserService:         c.UserService,
+			VmEventService:      c.VmEventService,
+			VmService:           c.VmService,
+			VolumeService:       c.VolumeService,
+		}, virtualMachine),
+		version:     controllers.NewVersionController(c.K8sService),
+		stats:       controllers.NewStatsController(c.StatsService),
+		test:        controllers.NewTestController(c.Provisioner, c.VmService, c.AuditService),
+		interceptor: controllers.NewInterceptor(c.K8sService, c.BlocklistService, c.AuditService, c.DeploymentService),
+	}
+}
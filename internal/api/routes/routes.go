@@ -1,29 +1,222 @@
 package routes
 
 import (
-	"os"
+	"log/slog"
+	"strings"
+	"time"
 	controllers "vm-controller/internal/api/controllers"
+	"vm-controller/internal/config"
+	"vm-controller/internal/metrics"
+	"vm-controller/internal/middleware"
+	blocklistservice "vm-controller/internal/services/blocklist_service"
+	"vm-controller/internal/services/k8s_service"
 
 	gin "github.com/gin-gonic/gin"
 )
 
-func SetupRouter() *gin.Engine {
-	r := gin.Default()
+// applyGinMode는 설정의 Gin 모드를 라우터 생성 전에 적용합니다. (GIN_MODE 환경 변수를 직접 읽지 않음)
+func applyGinMode() {
+	gin.SetMode(config.Get().GinMode)
+}
+
+// legacyAPIPrefix는 버전 도입 이전 경로입니다. (API_LEGACY_ROUTES=false 로 제거)
+const legacyAPIPrefix = "/api"
+
+// versionedRoutes는 "METHOD /api/..." 로 적은 라우트 설정을 버전 경로에도 똑같이 적용합니다.
+func versionedRoutes[V any](routes map[string]V) map[string]V {
+	result := make(map[string]V, len(routes)*2)
+	for route, value := range routes {
+		result[route] = value
+		method, path, _ := strings.Cut(route, " ")
+		result[method+" "+controllers.APIPrefix+strings.TrimPrefix(path, legacyAPIPrefix)] = value
+	}
+	return result
+}
+
+// SetupRouter는 Container의 서비스를 주입한 컨트롤러로 API 라우터를 만듭니다.
+func SetupRouter(c *Container) *gin.Engine {
+	applyGinMode()
+
+	// gin 기본 로거 대신 테넌트 라벨/요청 ID를 포함한 구조화 접근 로그 사용
+	r := gin.New()
+	applyTrustedProxies(r, config.Get())
+	r.Use(middleware.Recovery())
+	r.Use(middleware.RequestID())
+	// 다른 출처의 프론트엔드 허용 (CORS_ALLOWED_ORIGINS, preflight는 인증/요청 제한 전에 응답)
+	r.Use(middleware.CORS(corsPolicy(config.Get())))
+	r.Use(middleware.AccessLog())
+	r.Use(middleware.ErrorHandler())
+
+	// 등록되지 않은 경로도 공통 에러 형식으로 응답
+	r.NoRoute(middleware.NoRoute())
+
+	// HTTPS 요청에 HSTS 헤더 부여 (HSTS_MAX_AGE > 0 인 경우)
+	if maxAge := config.Get().HSTSMaxAge; maxAge > 0 {
+		r.Use(middleware.HSTS(maxAge))
+	}
+
+	// 핸들러 제한 시간 (K8s 호출이 멈춰도 연결을 무한히 점유하지 않도록)
+	r.Use(middleware.Timeout(routeTimeouts(config.Get())))
+
+	// 작업 큐 과부하 시 비싼 요청은 큐에 쌓지 않고 429로 거부
+	r.Use(middleware.Backpressure(backpressurePolicy()))
+
+	ctrls := c.controllers()
+
+	// 사용자/IP별 요청 제한 (스크립트를 이용한 남용 방지)
+	rateLimit := middleware.RateLimit(rateLimitPolicy(config.Get(), c.BlocklistService))
 
 	// Health Check
-	controllers.GetHealthController().RegisterRoutes(r.Group("/"))
+	ctrls.health.RegisterRoutes(r.Group("/"))
+
+	// Prometheus Metrics
+	r.GET("/metrics", metrics.Handler())
 
-	// API Group
-	api := r.Group("/api")
-	controllers.GetAuthController().RegisterRoutes(api)
-	controllers.GetVirtualMachineController().RegisterRoutes(api)
-	controllers.GetUserController().RegisterRoutes(api)
+	// 버전 API (/api/v1)
+	registerAPI(ctrls, r.Group(controllers.APIPrefix, rateLimit))
 
-	if os.Getenv("GIN_MODE") == "debug" {
-		controllers.GetTestController().RegisterRoutes(api)
+	// 버전 없는 기존 경로 (/api) - 같은 핸들러를 폐기 예정 헤더와 함께 제공
+	if config.Get().LegacyAPIRoutes {
+		registerAPI(ctrls, r.Group(legacyAPIPrefix, middleware.Deprecated(legacyAPIPrefix, controllers.APIPrefix, config.Get().LegacyAPISunset), rateLimit))
 	}
 
-	controllers.GetInterceptor().RegisterRoutes(api)
+	return r
+}
+
+// registerAPI는 API 컨트롤러의 라우트를 group 아래에 등록합니다. (/api/v1, /api 에서 같이 사용)
+func registerAPI(ctrls *controllerSet, api *gin.RouterGroup) {
+	ctrls.auth.RegisterRoutes(api)
+	ctrls.virtualMachine.RegisterRoutes(api)
+	ctrls.operation.RegisterRoutes(api)
+	ctrls.user.RegisterRoutes(api)
+	ctrls.deployment.RegisterRoutes(api)
+	ctrls.notification.RegisterRoutes(api)
+	ctrls.sshKey.RegisterRoutes(api)
+	ctrls.database.RegisterRoutes(api)
+	ctrls.resource.RegisterRoutes(api)
+	ctrls.usage.RegisterRoutes(api)
+	ctrls.terms.RegisterRoutes(api)
+	ctrls.report.RegisterRoutes(api)
+	ctrls.admin.RegisterRoutes(api)
+	ctrls.version.RegisterRoutes(api)
+	ctrls.stats.RegisterRoutes(api)
+
+	// 관리자용 샌드박스 도구 (sandbox-tools 기능 플래그, release 빌드 태그에서는 제외)
+	ctrls.test.RegisterRoutes(api)
+
+	ctrls.interceptor.RegisterRoutes(api)
+}
+
+// SetupAdminRouter는 내부 관리자 리스너용 라우터입니다. (pprof, 런타임 진단, 메트릭)
+// 외부에 노출되지 않는 포트에서만 제공해야 합니다.
+func SetupAdminRouter() *gin.Engine {
+	applyGinMode()
+
+	r := gin.New()
+	applyTrustedProxies(r, config.Get())
+	r.Use(gin.Recovery())
+
+	r.GET("/metrics", metrics.Handler())
+	controllers.NewDebugController().RegisterRoutes(r.Group("/"))
 
 	return r
 }
+
+// applyTrustedProxies는 c.ClientIP()가 전달 헤더를 믿을 프록시를 설정합니다.
+// gin은 기본으로 모든 프록시를 믿으므로, 설정하지 않으면 클라이언트가 X-Forwarded-For 를 바꿔 가며
+// IP별 요청 제한과 인터셉터 차단을 피할 수 있습니다. 목록이 비어 있거나 잘못되면 연결 주소만 사용합니다.
+func applyTrustedProxies(r *gin.Engine, cfg *config.Config) {
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		slog.Error("invalid TRUSTED_PROXIES, ignoring forwarded client IP headers", "error", err)
+		_ = r.SetTrustedProxies(nil)
+	}
+}
+
+// streamingRoutes는 핸들러 제한 시간을 적용하지 않는 라우트입니다. (웹소켓 콘솔, 대용량 업로드/다운로드, 행 단위 리포트)
+// Timeout 미들웨어는 응답 전체를 버퍼에 모았다가 보내므로, 응답을 스트리밍하거나 연결을 hijack하는 라우트를 새로 만들면
+// 반드시 여기에 추가해야 합니다. (routes_test.go가 목록의 라우트가 등록되어 있고 제한 시간에서 제외되는지 확인)
+var streamingRoutes = []string{
+	"GET /api/vm/:name/console",
+	"GET /api/vm/export/download",
+	"GET /api/admin/vms/export",
+	"PUT /api/vm/upload/chunk",
+}
+
+// routeTimeouts는 라우트별 핸들러 제한 시간입니다.
+// 생성 요청은 K8s 리소스 적용까지 기다리므로 길게, 스트리밍 라우트(streamingRoutes)는 제한하지 않습니다.
+func routeTimeouts(cfg *config.Config) middleware.TimeoutPolicy {
+	routes := map[string]time.Duration{
+		"POST /api/vm/create":          cfg.RouteCreateTimeout,
+		"POST /api/vm/upload":          cfg.RouteCreateTimeout,
+		"POST /api/vm/export":          cfg.RouteCreateTimeout,
+		"POST /api/deployment/create":  cfg.RouteCreateTimeout,
+		"POST /api/database/create":    cfg.RouteCreateTimeout,
+		"POST /api/vm/upload/complete": cfg.RouteCreateTimeout,
+		"POST /api/vm/restart":         cfg.RouteCreateTimeout,
+		"GET /api/admin/permissions":   cfg.RouteCreateTimeout, // 권한마다 SelfSubjectAccessReview 호출
+
+		"POST /api/admin/approvals/:id/approve": cfg.RouteCreateTimeout, // 승인된 요청으로 VM 생성
+	}
+	for _, route := range streamingRoutes {
+		routes[route] = 0
+	}
+
+	return middleware.TimeoutPolicy{
+		Read:   cfg.RouteReadTimeout,
+		Write:  cfg.RouteWriteTimeout,
+		Routes: versionedRoutes(routes),
+	}
+}
+
+// corsPolicy는 설정의 CORS 허용 목록입니다.
+func corsPolicy(cfg *config.Config) middleware.CORSPolicy {
+	return middleware.CORSPolicy{
+		AllowedOrigins:   cfg.CORSAllowedOrigins,
+		AllowedMethods:   cfg.CORSAllowedMethods,
+		AllowCredentials: cfg.CORSAllowCredentials,
+		MaxAge:           cfg.CORSMaxAge,
+	}
+}
+
+// rateLimitPolicy는 API 요청 제한입니다. 로그인과 VM 생성은 기본 한도와 별도로 더 엄격한 한도를 적용합니다.
+// 버킷은 blocklist 저장소에 두므로 REDIS_URL 설정 시 API 서버 여러 대가 같은 한도를 공유합니다.
+func rateLimitPolicy(cfg *config.Config, blocklist *blocklistservice.BlocklistService) middleware.RateLimitPolicy {
+	return middleware.RateLimitPolicy{
+		Default: middleware.RateLimitRule{Name: "api", Limit: cfg.RateLimitAPI},
+		Routes: versionedRoutes(map[string]middleware.RateLimitRule{
+			"POST /api/auth/login": {Name: "login", Limit: cfg.RateLimitLogin},
+			"POST /api/vm/create":  {Name: "vm.create", Limit: cfg.RateLimitVMCreate},
+		}),
+		// 인터셉터는 웹 배포 방문자 트래픽마다 호출되며 INTERCEPT_RATE_LIMIT로 따로 제한
+		Exempt: versionedRoutes(map[string]bool{
+			"GET /api/intercept": true,
+		}),
+		Take: blocklist.TakeToken,
+	}
+}
+
+// backpressurePolicy는 작업 큐 과부하 시 거부할 라우트입니다.
+// 백그라운드 작업이나 클러스터 리소스를 새로 만드는 요청만 포함합니다. (정지/삭제는 부하를 줄이므로 제외)
+// VM 생성/시작/재시작은 gRPC에도 같이 적용되도록 VmLifecycleService가 확인합니다.
+func backpressurePolicy() middleware.BackpressurePolicy {
+	return middleware.BackpressurePolicy{
+		Routes: versionedRoutes(map[string]bool{
+			"POST /api/vm/upload":           true,
+			"POST /api/vm/upload/complete":  true,
+			"POST /api/vm/export":           true,
+			"POST /api/vm/snapshot":         true,
+			"POST /api/vm/snapshot/restore": true,
+			"POST /api/vm/volume/attach":    true,
+			"POST /api/vm/:name/flavor":     true,
+			"POST /api/deployment/create":   true,
+			"POST /api/database/create":     true,
+
+			"POST /api/admin/approvals/:id/approve": true, // 승인된 요청으로 VM 생성
+			"POST /api/admin/vm/migrate":            true,
+		}),
+		Check: func() (bool, string, time.Duration) {
+			status := k8s_service.GetBackpressure()
+			return status.Overloaded, status.Reason, status.RetryAfter
+		},
+	}
+}
@@ -0,0 +1,140 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	controllers "vm-controller/internal/api/controllers"
+	"vm-controller/internal/config"
+	"vm-controller/internal/middleware"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+// 스트리밍 라우트는 Timeout 미들웨어가 응답을 버퍼에 모으지 않도록 제한 시간에서 제외되어야 함
+func TestStreamingRoutesExemptFromTimeout(t *testing.T) {
+	policy := routeTimeouts(&config.Config{
+		RouteReadTimeout:   10 * time.Second,
+		RouteWriteTimeout:  30 * time.Second,
+		RouteCreateTimeout: 2 * time.Minute,
+	})
+
+	for _, route := range streamingRoutes {
+		for _, versioned := range versionedVariants(route) {
+			timeout, ok := policy.Routes[versioned]
+			if !ok || timeout != 0 {
+				t.Errorf("%s must be exempt from the handler timeout (got %v, listed %v)", versioned, timeout, ok)
+			}
+		}
+	}
+}
+
+// 라우트 경로가 바뀌면 제외 목록이 조용히 무효가 되므로 목록의 라우트가 실제로 등록되어 있는지 확인
+func TestStreamingRoutesRegistered(t *testing.T) {
+	r := SetupRouter(&Container{})
+
+	registered := map[string]bool{}
+	for _, route := range r.Routes() {
+		registered[route.Method+" "+route.Path] = true
+	}
+
+	for _, route := range streamingRoutes {
+		for _, versioned := range versionedVariants(route) {
+			if !registered[versioned] {
+				t.Errorf("streaming route %s is not registered", versioned)
+			}
+		}
+	}
+}
+
+func versionedVariants(route string) []string {
+	method, path, _ := strings.Cut(route, " ")
+	variants := []string{method + " " + controllers.APIPrefix + strings.TrimPrefix(path, legacyAPIPrefix)}
+	if config.Get().LegacyAPIRoutes {
+		variants = append(variants, route)
+	}
+	return variants
+}
+
+// 요청 제한 버킷은 신뢰하는 프록시가 전달한 주소로만 바뀌어야 함 (클라이언트가 보낸 X-Forwarded-For 로는 바뀌지 않음)
+func TestRateLimitBucketIgnoresSpoofedForwardedFor(t *testing.T) {
+	tests := []struct {
+		name       string
+		trusted    []string
+		remoteAddr string
+		forwarded  string
+		wantKey    string
+	}{
+		{
+			name:       "no trusted proxies",
+			remoteAddr: "203.0.113.5:40000",
+			forwarded:  "198.51.100.7",
+			wantKey:    "api:ip:203.0.113.5",
+		},
+		{
+			name:       "untrusted peer",
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "203.0.113.5:40000",
+			forwarded:  "198.51.100.7",
+			wantKey:    "api:ip:203.0.113.5",
+		},
+		{
+			name:       "trusted ingress",
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.1.2.3:40000",
+			forwarded:  "198.51.100.7",
+			wantKey:    "api:ip:198.51.100.7",
+		},
+		{
+			name:       "invalid proxy list falls back to peer address",
+			trusted:    []string{"not-an-ip"},
+			remoteAddr: "203.0.113.5:40000",
+			forwarded:  "198.51.100.7",
+			wantKey:    "api:ip:203.0.113.5",
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var keys []string
+			r := gin.New()
+			applyTrustedProxies(r, &config.Config{TrustedProxies: tt.trusted})
+			r.Use(middleware.RateLimit(middleware.RateLimitPolicy{
+				Default: middleware.RateLimitRule{Name: "api", Limit: config.RateLimit{Requests: 10, Per: time.Minute}},
+				Take: func(_ context.Context, key string, _ int, _ time.Duration) (time.Duration, bool) {
+					keys = append(keys, key)
+					return 0, true
+				},
+			}))
+			r.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			for _, forwarded := range []string{"", tt.forwarded, "192.0.2.99"} {
+				req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+				req.RemoteAddr = tt.remoteAddr
+				if forwarded != "" {
+					req.Header.Set("X-Forwarded-For", forwarded)
+				}
+				r.ServeHTTP(httptest.NewRecorder(), req)
+			}
+
+			// 첫 요청(헤더 없음)은 항상 연결 주소
+			want := []string{"api:ip:" + strings.Split(tt.remoteAddr, ":")[0], tt.wantKey}
+			if len(keys) != 3 {
+				t.Fatalf("got %d bucket lookups, want 3", len(keys))
+			}
+			for i, key := range want {
+				if keys[i] != key {
+					t.Errorf("request %d bucket = %q, want %q", i, keys[i], key)
+				}
+			}
+			if tt.wantKey != "api:ip:198.51.100.7" && keys[2] != keys[0] {
+				t.Errorf("spoofed X-Forwarded-For changed the bucket to %q", keys[2])
+			}
+		})
+	}
+}
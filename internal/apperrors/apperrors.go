@@ -0,0 +1,216 @@
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Code는 클라이언트가 분기할 때 쓰는 안정적인 에러 코드입니다. 메시지 문구는 바뀔 수 있지만 코드는 바꾸지 않습니다.
+type Code string
+
+// 상태 코드마다 쓰는 일반 코드
+const (
+	CodeInvalidRequest  Code = "INVALID_REQUEST"
+	CodeUnauthenticated Code = "UNAUTHENTICATED"
+	CodeForbidden       Code = "FORBIDDEN"
+	CodeNotFound        Code = "NOT_FOUND"
+	CodeConflict        Code = "CONFLICT"
+	CodeGone            Code = "GONE"
+	CodePayloadTooLarge Code = "PAYLOAD_TOO_LARGE"
+	CodeUnprocessable   Code = "UNPROCESSABLE"
+	CodeRateLimited     Code = "RATE_LIMITED"
+	CodeInternal        Code = "INTERNAL"
+	CodeNotImplemented  Code = "NOT_IMPLEMENTED"
+	CodeUpstream        Code = "UPSTREAM_ERROR"
+	CodeUnavailable     Code = "UNAVAILABLE"
+	CodeTimeout         Code = "TIMEOUT"
+)
+
+// 리소스/상황별 코드
+const (
+	CodeVMNotFound         Code = "VM_NOT_FOUND"
+	CodeUserNotFound       Code = "USER_NOT_FOUND"
+	CodeDeploymentNotFound Code = "DEPLOYMENT_NOT_FOUND"
+	CodeDatabaseNotFound   Code = "DATABASE_NOT_FOUND"
+	CodeOperationNotFound  Code = "OPERATION_NOT_FOUND"
+	CodeSnapshotNotFound   Code = "SNAPSHOT_NOT_FOUND"
+	CodeVolumeNotFound     Code = "VOLUME_NOT_FOUND"
+	CodeSSHKeyNotFound     Code = "SSH_KEY_NOT_FOUND"
+	CodeExportNotFound     Code = "EXPORT_NOT_FOUND"
+
+	CodeInvalidState        Code = "INVALID_STATE"        // 현재 상태에서 허용되지 않는 요청
+	CodeNameTaken           Code = "NAME_TAKEN"           // VM/계정 이름 중복
+	CodeQuotaExceeded       Code = "QUOTA_EXCEEDED"       // 사용자 쿼터 hard cap 초과
+	CodeLimitExceeded       Code = "LIMIT_EXCEEDED"       // VM당 스냅샷/디스크 개수 제한
+	CodePortExhausted       Code = "PORT_EXHAUSTED"       // 할당할 NodePort 없음
+	CodePortInUse           Code = "PORT_IN_USE"          // 지정한 NodePort를 이미 사용 중
+	CodeNoSchedulablePool   Code = "NO_SCHEDULABLE_POOL"  // 모든 노드 풀이 유지보수 중
+	CodeUserSuspended       Code = "USER_SUSPENDED"       // 정지된 계정
+	CodeReadOnlyAccount     Code = "READ_ONLY_ACCOUNT"    // 읽기 전용 계정의 변경 요청
+	CodeCredentialsRevealed Code = "CREDENTIALS_REVEALED" // 비밀번호를 이미 확인함
+	CodeServerBusy          Code = "SERVER_BUSY"          // 작업 큐 과부하
+	CodeUnderReview         Code = "UNDER_REVIEW"         // 악용 탐지로 관리자 검토 중
+	CodeIdempotencyMismatch Code = "IDEMPOTENCY_KEY_MISMATCH"
+	CodeIdempotencyPending  Code = "IDEMPOTENCY_KEY_IN_PROGRESS"
+)
+
+// statusByCode는 코드의 기본 HTTP 상태입니다. (없는 코드는 500)
+var statusByCode = map[Code]int{
+	CodeInvalidRequest:  http.StatusBadRequest,
+	CodeUnauthenticated: http.StatusUnauthorized,
+	CodeForbidden:       http.StatusForbidden,
+	CodeNotFound:        http.StatusNotFound,
+	CodeConflict:        http.StatusConflict,
+	CodeGone:            http.StatusGone,
+	CodePayloadTooLarge: http.StatusRequestEntityTooLarge,
+	CodeUnprocessable:   http.StatusUnprocessableEntity,
+	CodeRateLimited:     http.StatusTooManyRequests,
+	CodeInternal:        http.StatusInternalServerError,
+	CodeNotImplemented:  http.StatusNotImplemented,
+	CodeUpstream:        http.StatusBadGateway,
+	CodeUnavailable:     http.StatusServiceUnavailable,
+	CodeTimeout:         http.StatusGatewayTimeout,
+
+	CodeVMNotFound:         http.StatusNotFound,
+	CodeUserNotFound:       http.StatusNotFound,
+	CodeDeploymentNotFound: http.StatusNotFound,
+	CodeDatabaseNotFound:   http.StatusNotFound,
+	CodeOperationNotFound:  http.StatusNotFound,
+	CodeSnapshotNotFound:   http.StatusNotFound,
+	CodeVolumeNotFound:     http.StatusNotFound,
+	CodeSSHKeyNotFound:     http.StatusNotFound,
+	CodeExportNotFound:     http.StatusNotFound,
+
+	CodeInvalidState:        http.StatusConflict,
+	CodeNameTaken:           http.StatusConflict,
+	CodeQuotaExceeded:       http.StatusForbidden,
+	CodeLimitExceeded:       http.StatusConflict,
+	CodePortExhausted:       http.StatusServiceUnavailable,
+	CodePortInUse:           http.StatusConflict,
+	CodeNoSchedulablePool:   http.StatusServiceUnavailable,
+	CodeUserSuspended:       http.StatusForbidden,
+	CodeReadOnlyAccount:     http.StatusForbidden,
+	CodeCredentialsRevealed: http.StatusGone,
+	CodeServerBusy:          http.StatusTooManyRequests,
+	CodeUnderReview:         http.StatusConflict,
+	CodeIdempotencyMismatch: http.StatusUnprocessableEntity,
+	CodeIdempotencyPending:  http.StatusConflict,
+}
+
+// Status는 코드의 기본 HTTP 상태를 반환합니다.
+func (code Code) Status() int {
+	if status, ok := statusByCode[code]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// Error는 API 응답으로 그대로 내보낼 수 있는 에러입니다.
+// Message와 Details는 클라이언트에 보여지며, 원인(Err)은 로그용으로만 사용합니다.
+type Error struct {
+	Code    Code
+	Message string
+	Details map[string]interface{}
+	Err     error
+
+	status int // 0이면 Code의 기본 상태
+}
+
+// New는 code와 message로 에러를 만듭니다.
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Newf는 형식 문자열로 메시지를 만듭니다.
+func Newf(code Code, format string, args ...interface{}) *Error {
+	return New(code, fmt.Sprintf(format, args...))
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Status는 응답할 HTTP 상태입니다.
+func (e *Error) Status() int {
+	if e.status != 0 {
+		return e.status
+	}
+	return e.Code.Status()
+}
+
+// WithStatus는 코드의 기본값과 다른 상태로 응답하는 사본을 반환합니다. (기존 API의 상태 코드를 유지할 때 사용)
+func (e *Error) WithStatus(status int) *Error {
+	clone := e.clone()
+	clone.status = status
+	return clone
+}
+
+// WithDetail은 details에 key를 추가한 사본을 반환합니다.
+func (e *Error) WithDetail(key string, value interface{}) *Error {
+	clone := e.clone()
+	clone.Details = make(map[string]interface{}, len(e.Details)+1)
+	for k, v := range e.Details {
+		clone.Details[k] = v
+	}
+	clone.Details[key] = value
+	return clone
+}
+
+// Wrap은 원인 에러를 기록한 사본을 반환합니다. 원인은 응답에 포함되지 않습니다.
+func (e *Error) Wrap(err error) *Error {
+	clone := e.clone()
+	clone.Err = err
+	return clone
+}
+
+func (e *Error) clone() *Error {
+	clone := *e
+	return &clone
+}
+
+// From은 err를 응답용 에러로 바꿉니다.
+// err가 Error를 감싸고 있으면 그 코드와 상태를 쓰고, 아니면 fallback 코드를 씁니다. 메시지는 err의 문구 그대로입니다.
+func From(err error, fallback Code) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		if appErr == err {
+			return appErr
+		}
+		converted := appErr.clone()
+		converted.Message = err.Error()
+		converted.Err = err
+		return converted
+	}
+	return &Error{Code: fallback, Message: err.Error(), Err: err}
+}
+
+// CodeOf는 err가 감싼 Error의 코드를 반환합니다. 없으면 빈 문자열입니다.
+func CodeOf(err error) Code {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr.Code
+	}
+	return ""
+}
+
+// Response는 모든 API 에러 응답의 본문입니다.
+type Response struct {
+	Code    Code                   `json:"code"`
+	Message string                 `json:"message"`
+	Details map[string]interface{} `json:"details"`
+}
+
+// Response는 응답 본문을 만듭니다. details가 없으면 빈 객체로 내보내 클라이언트가 항상 같은 형태를 받도록 합니다.
+func (e *Error) Response() Response {
+	details := e.Details
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	return Response{Code: e.Code, Message: e.Message, Details: details}
+}
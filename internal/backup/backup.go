@@ -0,0 +1,258 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"vm-controller/internal/db"
+	"vm-controller/internal/metrics"
+)
+
+const (
+	dumpPrefix = "vm-controller-"
+	dumpSuffix = ".dump"
+
+	// 보관할 백업 개수 기본값 (BACKUP_RETENTION으로 변경)
+	defaultRetention = 14
+)
+
+var (
+	// ErrNoBackup은 복원할 백업이 저장소에 없을 때 반환됩니다.
+	ErrNoBackup = errors.New("no backup found in storage")
+	// ErrBackupRunning은 다른 인스턴스가 이미 백업 중일 때 반환됩니다.
+	ErrBackupRunning = errors.New("another instance is already running a backup")
+)
+
+var (
+	backupRuns        = metrics.NewCounterVec("platform_backup_runs_total", "Scheduled and manual backups of the controller database by result.", "result")
+	lastBackupSuccess atomic.Int64
+)
+
+func init() {
+	metrics.NewGaugeFunc("platform_backup_last_success_timestamp_seconds", "Unix time of the last successful controller database backup (0 if none since start).", func() float64 {
+		return float64(lastBackupSuccess.Load())
+	})
+}
+
+// 여러 레플리카가 같은 주기에 백업하지 않도록 잡는 advisory lock 키
+var backupLockKey = func() int64 {
+	h := fnv.New64a()
+	h.Write([]byte("vm-controller/backup"))
+	return int64(h.Sum64() >> 1)
+}()
+
+// Retention은 보관할 백업 개수입니다. (BACKUP_RETENTION, 기본 14)
+func Retention() int {
+	if n, err := strconv.Atoi(os.Getenv("BACKUP_RETENTION")); err == nil && n > 0 {
+		return n
+	}
+	return defaultRetention
+}
+
+// Run은 pg_dump(custom 포맷)로 컨트롤러 DB를 덤프하여 저장소에 올리고, 보관 개수를 넘는 오래된 백업을 지웁니다.
+func Run(ctx context.Context, store Store) (Object, error) {
+	conn, err := db.GetDB().DB()
+	if err != nil {
+		return Object{}, err
+	}
+	// 세션 advisory lock은 같은 커넥션에서 풀어야 하므로 커넥션을 고정
+	lockConn, err := conn.Conn(ctx)
+	if err != nil {
+		return Object{}, err
+	}
+	defer lockConn.Close()
+
+	var locked bool
+	if err := lockConn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", backupLockKey).Scan(&locked); err != nil {
+		return Object{}, fmt.Errorf("failed to acquire backup lock: %v", err)
+	}
+	if !locked {
+		return Object{}, ErrBackupRunning
+	}
+	defer lockConn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", backupLockKey)
+
+	object, err := dumpAndUpload(ctx, store)
+	if err != nil {
+		backupRuns.Inc("failure")
+		return Object{}, err
+	}
+	backupRuns.Inc("success")
+	lastBackupSuccess.Store(time.Now().Unix())
+
+	if err := prune(ctx, store, Retention()); err != nil {
+		slog.Warn("backup retention cleanup failed", "error", err)
+	}
+	return object, nil
+}
+
+func dumpAndUpload(ctx context.Context, store Store) (Object, error) {
+	dsn, err := db.ConnString()
+	if err != nil {
+		return Object{}, err
+	}
+
+	file, err := os.CreateTemp("", "vm-controller-backup-*"+dumpSuffix)
+	if err != nil {
+		return Object{}, err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	// 권한/소유자는 복원 대상 DB 계정이 다를 수 있으므로 제외
+	cmd := exec.CommandContext(ctx, pgTool("pg_dump"), "--format=custom", "--no-owner", "--no-privileges", "--dbname="+dsn)
+	var stderr bytes.Buffer
+	cmd.Stdout = file
+	cmd.Stderr = &stderr
+	started := time.Now()
+	if err := cmd.Run(); err != nil {
+		return Object{}, fmt.Errorf("pg_dump failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return Object{}, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return Object{}, err
+	}
+
+	key := dumpPrefix + started.UTC().Format("20060102T150405Z") + dumpSuffix
+	if err := store.Put(ctx, key, file); err != nil {
+		return Object{}, fmt.Errorf("failed to upload backup %s: %v", key, err)
+	}
+
+	slog.Info("controller database backup uploaded", "key", key, "bytes", size, "duration", time.Since(started).String())
+	return Object{Key: key, Size: size, LastModified: started}, nil
+}
+
+// prune은 최신 keep개를 제외한 백업을 삭제합니다.
+func prune(ctx context.Context, store Store, keep int) error {
+	objects, err := store.List(ctx)
+	if err != nil {
+		return err
+	}
+	for len(objects) > keep {
+		if err := store.Delete(ctx, objects[0].Key); err != nil {
+			return fmt.Errorf("failed to delete backup %s: %v", objects[0].Key, err)
+		}
+		slog.Info("old controller database backup deleted", "key", objects[0].Key)
+		objects = objects[1:]
+	}
+	return nil
+}
+
+// Restore는 백업을 내려받아 pg_restore로 컨트롤러 DB를 덮어씁니다. key가 "latest"면 가장 최근 백업을 사용합니다.
+// 기존 테이블을 지우고 다시 만들므로 API 서버를 모두 멈춘 뒤 실행해야 합니다.
+func Restore(ctx context.Context, store Store, key string) (Object, error) {
+	objects, err := store.List(ctx)
+	if err != nil {
+		return Object{}, err
+	}
+
+	var target *Object
+	for i := range objects {
+		if key == "latest" || objects[i].Key == key {
+			target = &objects[i]
+		}
+	}
+	if target == nil {
+		if key == "latest" {
+			return Object{}, ErrNoBackup
+		}
+		return Object{}, fmt.Errorf("backup %q not found", key)
+	}
+
+	dsn, err := db.ConnString()
+	if err != nil {
+		return Object{}, err
+	}
+
+	body, err := store.Get(ctx, target.Key)
+	if err != nil {
+		return Object{}, err
+	}
+	defer body.Close()
+
+	file, err := os.CreateTemp("", "vm-controller-restore-*"+dumpSuffix)
+	if err != nil {
+		return Object{}, err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+	if _, err := io.Copy(file, body); err != nil {
+		return Object{}, fmt.Errorf("failed to download backup %s: %v", target.Key, err)
+	}
+
+	// 한 트랜잭션으로 복원하여 실패하면 기존 데이터가 그대로 남음
+	cmd := exec.CommandContext(ctx, pgTool("pg_restore"), "--clean", "--if-exists", "--no-owner", "--no-privileges",
+		"--single-transaction", "--exit-on-error", "--dbname="+dsn, file.Name())
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return Object{}, fmt.Errorf("pg_restore failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return *target, nil
+}
+
+// pgTool은 PG_BIN_DIR이 있으면 그 디렉터리의 클라이언트 도구를, 없으면 PATH의 도구를 사용합니다.
+// (서버와 메이저 버전이 같거나 높은 pg_dump가 필요함)
+func pgTool(name string) string {
+	if dir := os.Getenv("PG_BIN_DIR"); dir != "" {
+		return strings.TrimRight(dir, "/") + "/" + name
+	}
+	return name
+}
+
+// Interval은 예약 백업 주기입니다. (BACKUP_INTERVAL, 설정되지 않았거나 1분 미만/잘못된 값이면 0)
+func Interval() time.Duration {
+	interval, err := time.ParseDuration(os.Getenv("BACKUP_INTERVAL"))
+	if err != nil || interval < time.Minute {
+		return 0
+	}
+	return interval
+}
+
+// StartScheduler는 BACKUP_INTERVAL(예: 6h) 주기로 백업을 실행합니다.
+// 주기나 저장소가 설정되지 않았으면 아무것도 하지 않습니다.
+func StartScheduler() {
+	raw := os.Getenv("BACKUP_INTERVAL")
+	if raw == "" {
+		return
+	}
+	interval := Interval()
+	if interval == 0 {
+		slog.Error("invalid BACKUP_INTERVAL, scheduled backups disabled", "value", raw)
+		return
+	}
+	store, err := NewStoreFromEnv()
+	if err != nil {
+		slog.Error("scheduled backups disabled", "error", err)
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			if _, err := Run(ctx, store); err != nil && !errors.Is(err, ErrBackupRunning) {
+				slog.Error("scheduled backup failed", "error", err)
+			}
+			cancel()
+		}
+	}()
+	slog.Info("scheduled controller database backups enabled", "interval", interval.String(), "retention", Retention())
+}
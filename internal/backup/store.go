@@ -0,0 +1,124 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrNotConfigured는 백업 저장소(BACKUP_S3_BUCKET 또는 BACKUP_DIR)가 설정되지 않았을 때 반환됩니다.
+var ErrNotConfigured = errors.New("backup storage is not configured (set BACKUP_S3_BUCKET or BACKUP_DIR)")
+
+// Object는 저장소에 올라간 백업 파일 하나입니다.
+type Object struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// Store는 백업 파일을 보관하는 저장소입니다. 키는 저장소 안의 상대 경로입니다.
+type Store interface {
+	Put(ctx context.Context, key string, body io.ReadSeeker) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	List(ctx context.Context) ([]Object, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// NewStoreFromEnv는 환경 변수로 저장소를 만듭니다.
+// BACKUP_S3_BUCKET이 있으면 S3 호환 오브젝트 스토리지, 없으면 BACKUP_DIR 로컬 디렉터리를 사용합니다.
+func NewStoreFromEnv() (Store, error) {
+	if bucket := os.Getenv("BACKUP_S3_BUCKET"); bucket != "" {
+		return newS3Store(bucket)
+	}
+	if dir := os.Getenv("BACKUP_DIR"); dir != "" {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return nil, fmt.Errorf("failed to create backup dir: %v", err)
+		}
+		return &dirStore{dir: dir}, nil
+	}
+	return nil, ErrNotConfigured
+}
+
+// dirStore는 로컬(또는 마운트된 볼륨) 디렉터리 저장소입니다.
+type dirStore struct {
+	dir string
+}
+
+func (s *dirStore) path(key string) (string, error) {
+	if key == "" || strings.Contains(key, "/") || strings.Contains(key, "..") {
+		return "", fmt.Errorf("invalid backup key %q", key)
+	}
+	return filepath.Join(s.dir, key), nil
+}
+
+func (s *dirStore) Put(_ context.Context, key string, body io.ReadSeeker) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	// 쓰는 도중의 파일이 목록에 보이지 않도록 임시 이름으로 쓰고 옮김
+	tmp := path + ".partial"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, body); err != nil {
+		file.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (s *dirStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+func (s *dirStore) List(_ context.Context) ([]Object, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := []Object{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), dumpSuffix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		objects = append(objects, Object{Key: entry.Name(), Size: info.Size(), LastModified: info.ModTime()})
+	}
+	sortObjects(objects)
+	return objects, nil
+}
+
+func (s *dirStore) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// sortObjects는 백업을 오래된 순으로 정렬합니다. (키에 UTC 시각이 들어 있으므로 키 순서와 같음)
+func sortObjects(objects []Object) {
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+}
@@ -0,0 +1,127 @@
+package coalesce
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"vm-controller/internal/metrics"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// 조회 결과 캐시 기본 유지 시간 (READ_COALESCE_TTL로 변경, 0이면 동시 요청 합치기만 수행)
+const defaultTTL = 2 * time.Second
+
+// 만료 항목을 정리하기 시작하는 캐시 크기
+const sweepThreshold = 1024
+
+var readsTotal = metrics.NewCounterVec("coalesced_reads_total", "Read lookups by coalescing group and result (hit: served from cache, shared: joined an in-flight lookup, miss: executed).", "group", "result")
+
+// TTL은 조회 결과를 캐시하는 시간입니다. (READ_COALESCE_TTL, 기본 2s)
+func TTL() time.Duration {
+	raw := os.Getenv("READ_COALESCE_TTL")
+	if raw == "" {
+		return defaultTTL
+	}
+	ttl, err := time.ParseDuration(raw)
+	if err != nil || ttl < 0 {
+		log.Printf("Invalid READ_COALESCE_TTL: %q, using default %s", raw, defaultTTL)
+		return defaultTTL
+	}
+	return ttl
+}
+
+type entry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+// Group은 같은 키의 동시 조회를 한 번의 실행으로 합치고(singleflight), 결과를 TTL 동안 캐시합니다.
+// 대시보드처럼 여러 클라이언트가 같은 상태를 동시에 폴링할 때 DB/K8s 조회를 줄이기 위한 것으로,
+// 반환 값은 여러 호출자가 공유하므로 호출자는 수정하지 말아야 합니다. 에러는 캐시하지 않습니다.
+type Group[V any] struct {
+	name   string
+	ttl    time.Duration // 0이면 캐시하지 않음 (동시 요청 합치기만 수행)
+	flight singleflight.Group
+
+	mu         sync.Mutex
+	entries    map[string]entry[V]
+	generation uint64 // Purge마다 증가 (Purge 이전에 시작된 조회 결과는 캐시하지 않음)
+}
+
+// New는 메트릭 라벨로 name을 사용하고 READ_COALESCE_TTL 동안 결과를 캐시하는 Group을 만듭니다.
+// 환경 변수를 만들 때 읽으므로 .env 로드 이후 서비스 생성자에서 만듭니다.
+func New[V any](name string) *Group[V] {
+	return NewWithTTL[V](name, TTL())
+}
+
+// NewWithTTL은 READ_COALESCE_TTL 대신 ttl 동안 결과를 캐시하는 Group을 만듭니다. (자주 바뀌지 않는 집계 등)
+func NewWithTTL[V any](name string, ttl time.Duration) *Group[V] {
+	return &Group[V]{name: name, ttl: ttl, entries: map[string]entry[V]{}}
+}
+
+// Do는 key의 캐시된 값을 반환하거나, 같은 key로 실행 중인 조회에 합류하거나, fn을 실행합니다.
+// fn은 먼저 들어온 요청의 취소에 다른 요청이 영향을 받지 않도록 요청 컨텍스트와 분리하여 실행해야 합니다.
+func (g *Group[V]) Do(key string, fn func() (V, error)) (V, error) {
+	now := time.Now()
+
+	g.mu.Lock()
+	if cached, ok := g.entries[key]; ok && now.Before(cached.expires) {
+		g.mu.Unlock()
+		readsTotal.Inc(g.name, "hit")
+		return cached.value, nil
+	}
+	generation := g.generation
+	g.mu.Unlock()
+
+	// Purge 이후의 요청이 이전 세대의 조회에 합류하지 않도록 세대를 키에 포함
+	value, err, shared := g.flight.Do(strconv.FormatUint(generation, 10)+"/"+key, func() (interface{}, error) {
+		value, err := fn()
+		if err == nil {
+			g.store(key, value, generation)
+		}
+		return value, err
+	})
+	if shared {
+		readsTotal.Inc(g.name, "shared")
+	} else {
+		readsTotal.Inc(g.name, "miss")
+	}
+
+	result, _ := value.(V)
+	return result, err
+}
+
+func (g *Group[V]) store(key string, value V, generation uint64) {
+	if g.ttl <= 0 {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if generation != g.generation {
+		return
+	}
+	now := time.Now()
+	if len(g.entries) >= sweepThreshold {
+		for k, cached := range g.entries {
+			if !now.Before(cached.expires) {
+				delete(g.entries, k)
+			}
+		}
+	}
+	g.entries[key] = entry[V]{value: value, expires: now.Add(g.ttl)}
+}
+
+// Purge는 캐시를 비웁니다. 데이터가 바뀐 직후 이전 값이 보이지 않도록 쓰기 경로에서 호출합니다.
+func (g *Group[V]) Purge() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.generation++
+	clear(g.entries)
+}
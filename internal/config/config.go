@@ -2,15 +2,20 @@ package config
 
 import (
 	"log"
+	"net/http"
 	"os"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/spf13/cast"
 )
 
 // Config 구조체는 애플리케이션 설정을 저장합니다.
 type Config struct {
 	Port     string // 서버가 실행될 포트
-	GinMode  string // Gin 모드 (debug/release)
+	GinMode  string // Gin 모드 (debug/release/test, 라우터 생성 전에 gin.SetMode로 적용)
 	HostName string // 호스트 이름
 
 	DB_Name     string // 데이터베이스 이름
@@ -18,6 +23,83 @@ type Config struct {
 	DB_Password string // 데이터베이스 비밀번호
 	DB_Host     string // 데이터베이스 호스트
 	DB_Port     string // 데이터베이스 포트
+
+	LogLevel   string // 애플리케이션 로그 레벨 (debug/info/warn/error)
+	LogFormat  string // 로그 출력 형식 (json/text)
+	DBLogLevel string // GORM SQL 로그 레벨 (silent/error/warn/info)
+
+	TLSCertFile      string        // HTTPS 인증서 경로
+	TLSKeyFile       string        // HTTPS 개인키 경로
+	AutocertDomains  []string      // Let's Encrypt 자동 발급 도메인 목록
+	AutocertCacheDir string        // 자동 발급 인증서 캐시 디렉터리
+	HTTPRedirectPort string        // HTTPS 사용 시 HTTP -> HTTPS 리다이렉트(ACME 챌린지 포함) 포트
+	HSTSMaxAge       int           // Strict-Transport-Security max-age (초, 0이면 비활성화)
+	SecureCookies    bool          // 인증 쿠키에 Secure 속성 부여 여부
+	CookieSameSite   http.SameSite // 인증 쿠키 SameSite 속성 (프론트엔드를 다른 사이트에서 제공하면 None)
+
+	CORSAllowedOrigins   []string      // 교차 출처 요청을 허용할 Origin (비어 있으면 CORS 헤더를 보내지 않음, "https://*.example.com" 형식 허용)
+	CORSAllowedMethods   []string      // 허용할 메서드
+	CORSAllowCredentials bool          // 쿠키(인증) 포함 요청 허용 여부 (기본 false)
+	CORSMaxAge           time.Duration // preflight 응답 캐시 시간
+
+	ReadTimeout       time.Duration // 요청 전체(바디 포함) 읽기 제한 시간
+	ReadHeaderTimeout time.Duration // 요청 헤더 읽기 제한 시간 (slow-loris 방지)
+	WriteTimeout      time.Duration // 응답 쓰기 제한 시간
+	IdleTimeout       time.Duration // keep-alive 유휴 연결 유지 시간
+	MaxHeaderBytes    int           // 요청 헤더 최대 크기
+	H2C               bool          // TLS 없이 HTTP/2(h2c) 허용 여부
+
+	RouteReadTimeout   time.Duration // 조회(GET) 핸들러 제한 시간
+	RouteWriteTimeout  time.Duration // 변경(POST/PUT/DELETE) 핸들러 제한 시간
+	RouteCreateTimeout time.Duration // 리소스 생성 요청 핸들러 제한 시간
+
+	RateLimitAPI      RateLimit // /api 요청 제한 (로그인 사용자별, 비로그인은 IP별)
+	RateLimitLogin    RateLimit // 로그인 시도 제한 (IP별, 무차별 대입 방지)
+	RateLimitVMCreate RateLimit // VM 생성 요청 제한 (사용자별)
+
+	TrustedProxies []string // X-Forwarded-For/X-Real-IP 를 믿을 프록시 IP/CIDR (비어 있으면 연결 주소를 클라이언트 IP로 사용)
+
+	AdminPort string // 내부 관리자 리스너 포트 (pprof 등, 비어 있으면 비활성화)
+	GRPCPort  string // 내부 자동화용 gRPC API 포트 (비어 있으면 비활성화)
+
+	LegacyAPIRoutes bool      // 버전 없는 /api 경로 제공 여부 (deprecated, /api/v1과 같은 핸들러)
+	LegacyAPISunset time.Time // /api 경로 제거 예정일 (Sunset 헤더, 0이면 헤더 생략)
+
+	OperatorMode bool // UserVM CRD 기반 operator 모드 사용 여부
+	// 인스턴스 중 UserVM을 reconcile 할 리더를 정하는 Lease의 네임스페이스
+	OperatorLeaseNamespace string
+
+	Features map[string]bool // 기능 플래그 초기값 (FEATURE_FLAGS, FeatureEnabled로 조회)
+
+	DeletePolicies map[string]DeletePolicy // 리소스 종류(Kind, 소문자)별 삭제 동작 (DeletePolicyFor로 조회)
+}
+
+// RateLimit은 Per 동안 Requests개까지 허용하는 요청 제한입니다. (토큰 버킷, Requests가 0이면 제한 없음)
+type RateLimit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// TLSEnabled 함수는 서버가 직접 HTTPS를 제공하는지 여부를 반환합니다.
+func (c *Config) TLSEnabled() bool {
+	return len(c.AutocertDomains) > 0 || (c.TLSCertFile != "" && c.TLSKeyFile != "")
+}
+
+var (
+	current *Config
+	mu      sync.Mutex
+)
+
+// Get 함수는 마지막으로 Load된 설정을 반환합니다. 아직 Load되지 않았다면 Load를 수행합니다.
+func Get() *Config {
+	mu.Lock()
+	loaded := current
+	mu.Unlock()
+
+	if loaded != nil {
+		return loaded
+	}
+	return Load()
 }
 
 // Load 함수는 환경 변수에서 설정을 읽어 Config 구조체를 반환합니다.
@@ -32,9 +114,14 @@ func Load() *Config {
 		port = "8080" // 기본값 8080
 	}
 
-	ginMode := os.Getenv("GIN_MODE")
-	if ginMode == "" {
+	ginMode := strings.ToLower(strings.TrimSpace(os.Getenv("GIN_MODE")))
+	switch ginMode {
+	case "debug", "release", "test":
+	case "":
 		ginMode = "release" // 기본값 release
+	default:
+		log.Printf("Invalid GIN_MODE %q, using release", ginMode)
+		ginMode = "release"
 	}
 
 	hostName := os.Getenv("HOST_NAME")
@@ -67,7 +154,90 @@ func Load() *Config {
 		dbPort = "5432" // 기본값 5432
 	}
 
-	return &Config{
+	// 로그 설정: release 모드에서는 SQL 전체 로그를 남기지 않도록 warn이 기본값
+	logLevel := strings.ToLower(os.Getenv("LOG_LEVEL"))
+	if logLevel == "" {
+		logLevel = "info" // 기본값 info
+	}
+
+	logFormat := strings.ToLower(os.Getenv("LOG_FORMAT"))
+	if logFormat == "" {
+		logFormat = "json" // 기본값 json
+		if ginMode == "debug" {
+			logFormat = "text"
+		}
+	}
+
+	dbLogLevel := strings.ToLower(os.Getenv("DB_LOG_LEVEL"))
+	if dbLogLevel == "" {
+		dbLogLevel = "warn" // 기본값 warn (느린 쿼리와 에러만)
+		if ginMode == "debug" {
+			dbLogLevel = "info"
+		}
+	}
+
+	// TLS 설정: 인그레스 없이 직접 HTTPS를 제공하는 경우에 사용
+	var autocertDomains []string
+	for _, domain := range strings.Split(os.Getenv("TLS_AUTOCERT_DOMAINS"), ",") {
+		if domain = strings.TrimSpace(domain); domain != "" {
+			autocertDomains = append(autocertDomains, domain)
+		}
+	}
+
+	autocertCacheDir := os.Getenv("TLS_AUTOCERT_CACHE_DIR")
+	if autocertCacheDir == "" {
+		autocertCacheDir = "certs" // 기본값 ./certs
+	}
+
+	httpRedirectPort := os.Getenv("HTTP_REDIRECT_PORT")
+	if httpRedirectPort == "" {
+		httpRedirectPort = "80" // 기본값 80 (ACME HTTP-01 챌린지)
+	}
+
+	tlsEnabled := len(autocertDomains) > 0 || (os.Getenv("TLS_CERT_FILE") != "" && os.Getenv("TLS_KEY_FILE") != "")
+
+	// HSTS는 HTTPS를 직접 제공할 때만 기본 활성화 (1년)
+	hstsMaxAge := 0
+	if tlsEnabled {
+		hstsMaxAge = 31536000
+	}
+	if value := os.Getenv("HSTS_MAX_AGE"); value != "" {
+		hstsMaxAge = cast.ToInt(value)
+	}
+
+	// Secure 쿠키는 기본 활성화, 로컬 HTTP 개발 환경에서만 false로 설정
+	secureCookies := true
+	if value := os.Getenv("SECURE_COOKIES"); value != "" {
+		secureCookies = cast.ToBool(value)
+	}
+
+	// 쿠키 포함 요청은 명시적으로 켠 경우에만 허용 (하위 도메인 패턴은 CORS 미들웨어가 무시)
+	// Access-Control-Allow-Origin: * 와 함께 쓸 수 없으므로, 모든 출처 허용 시에는 자격 증명을 끔
+	corsOrigins := listEnv("CORS_ALLOWED_ORIGINS")
+	corsCredentials := cast.ToBool(envOrDefault("CORS_ALLOW_CREDENTIALS", "false"))
+	for _, origin := range corsOrigins {
+		if origin == "*" && corsCredentials {
+			log.Println("CORS_ALLOWED_ORIGINS=* cannot be used with credentials, disabling CORS_ALLOW_CREDENTIALS")
+			corsCredentials = false
+		}
+	}
+
+	corsMethods := listEnv("CORS_ALLOWED_METHODS")
+	if len(corsMethods) == 0 {
+		corsMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	}
+	for i := range corsMethods {
+		corsMethods[i] = strings.ToUpper(corsMethods[i])
+	}
+
+	cookieSameSite := sameSiteEnv("COOKIE_SAME_SITE")
+	if cookieSameSite == http.SameSiteNoneMode && !secureCookies {
+		// 브라우저는 Secure 없는 SameSite=None 쿠키를 거부함
+		log.Println("COOKIE_SAME_SITE=none requires SECURE_COOKIES=true, using the browser default")
+		cookieSameSite = http.SameSiteDefaultMode
+	}
+
+	cfg := &Config{
 		Port:        port,
 		GinMode:     ginMode,
 		HostName:    hostName,
@@ -76,5 +246,149 @@ func Load() *Config {
 		DB_Password: dbPassword,
 		DB_Host:     dbHost,
 		DB_Port:     dbPort,
+		LogLevel:    logLevel,
+		LogFormat:   logFormat,
+		DBLogLevel:  dbLogLevel,
+
+		TLSCertFile:      os.Getenv("TLS_CERT_FILE"),
+		TLSKeyFile:       os.Getenv("TLS_KEY_FILE"),
+		AutocertDomains:  autocertDomains,
+		AutocertCacheDir: autocertCacheDir,
+		HTTPRedirectPort: httpRedirectPort,
+		HSTSMaxAge:       hstsMaxAge,
+		SecureCookies:    secureCookies,
+		CookieSameSite:   cookieSameSite,
+
+		CORSAllowedOrigins:   corsOrigins,
+		CORSAllowedMethods:   corsMethods,
+		CORSAllowCredentials: corsCredentials,
+		CORSMaxAge:           durationEnv("CORS_MAX_AGE", 10*time.Minute),
+
+		// HTTP 서버 타임아웃 설정 (Go 기본값은 무제한)
+		ReadTimeout:       durationEnv("HTTP_READ_TIMEOUT", 30*time.Second),
+		ReadHeaderTimeout: durationEnv("HTTP_READ_HEADER_TIMEOUT", 10*time.Second),
+		WriteTimeout:      durationEnv("HTTP_WRITE_TIMEOUT", 60*time.Second),
+		IdleTimeout:       durationEnv("HTTP_IDLE_TIMEOUT", 120*time.Second),
+		MaxHeaderBytes:    cast.ToInt(envOrDefault("HTTP_MAX_HEADER_BYTES", "1048576")),
+		H2C:               cast.ToBool(envOrDefault("HTTP_H2C", "false")),
+
+		// 핸들러별 제한 시간 (생성 요청은 WriteTimeout 보다 짧게 유지)
+		RouteReadTimeout:   durationEnv("ROUTE_READ_TIMEOUT", 10*time.Second),
+		RouteWriteTimeout:  durationEnv("ROUTE_WRITE_TIMEOUT", 30*time.Second),
+		RouteCreateTimeout: durationEnv("ROUTE_CREATE_TIMEOUT", 55*time.Second),
+
+		// 요청 제한 ("요청 수/기간", 0이면 제한 없음)
+		RateLimitAPI:      rateLimitEnv("RATE_LIMIT_API", RateLimit{Requests: 300, Per: time.Minute}),
+		RateLimitLogin:    rateLimitEnv("RATE_LIMIT_LOGIN", RateLimit{Requests: 10, Per: time.Minute}),
+		RateLimitVMCreate: rateLimitEnv("RATE_LIMIT_VM_CREATE", RateLimit{Requests: 10, Per: 10 * time.Minute}),
+
+		// 클라이언트 IP를 알려 주는 프록시 (Ingress 컨트롤러 등)
+		TrustedProxies: listEnv("TRUSTED_PROXIES"),
+
+		AdminPort: os.Getenv("ADMIN_PORT"),
+		GRPCPort:  os.Getenv("GRPC_PORT"),
+
+		LegacyAPIRoutes: cast.ToBool(envOrDefault("API_LEGACY_ROUTES", "true")),
+		LegacyAPISunset: dateEnv("API_LEGACY_SUNSET"),
+
+		OperatorMode:           cast.ToBool(envOrDefault("OPERATOR_MODE", "false")),
+		OperatorLeaseNamespace: envOrDefault("OPERATOR_LEASE_NAMESPACE", envOrDefault("POD_NAMESPACE", "default")),
+
+		Features: parseFeatureFlags(os.Getenv("FEATURE_FLAGS")),
+
+		DeletePolicies: parseDeletePolicies(os.Getenv("DELETE_POLICIES")),
+	}
+
+	mu.Lock()
+	current = cfg
+	mu.Unlock()
+
+	return cfg
+}
+
+// envOrDefault 함수는 환경 변수가 비어 있으면 기본값을 반환합니다.
+func envOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// durationEnv 함수는 "30s", "2m" 형식의 환경 변수를 읽습니다. 잘못된 값이면 기본값을 사용합니다.
+func durationEnv(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		log.Printf("Invalid %s: %q, using default %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return duration
+}
+
+// rateLimitEnv 함수는 "60/1m" 형식(요청 수/기간)의 환경 변수를 읽습니다. "0"이면 제한 없음, 잘못된 값이면 기본값을 사용합니다.
+func rateLimitEnv(key string, defaultValue RateLimit) RateLimit {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	if value == "0" {
+		return RateLimit{}
+	}
+
+	requests, per, _ := strings.Cut(value, "/")
+	limit := RateLimit{Requests: cast.ToInt(requests)}
+	duration, err := time.ParseDuration(per)
+	if err != nil || duration <= 0 || limit.Requests <= 0 {
+		log.Printf("Invalid %s: %q, expected <requests>/<duration> (e.g. 60/1m)", key, value)
+		return defaultValue
+	}
+	limit.Per = duration
+	return limit
+}
+
+// listEnv 함수는 쉼표로 구분된 환경 변수를 읽습니다. (빈 항목 제외)
+func listEnv(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// sameSiteEnv 함수는 lax/strict/none 값을 쿠키 SameSite 속성으로 읽습니다. 비어 있거나 잘못된 값이면 브라우저 기본값을 사용합니다.
+func sameSiteEnv(key string) http.SameSite {
+	switch value := strings.ToLower(strings.TrimSpace(os.Getenv(key))); value {
+	case "":
+		return http.SameSiteDefaultMode
+	case "lax":
+		return http.SameSiteLaxMode
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		log.Printf("Invalid %s: %q, expected lax, strict or none", key, value)
+		return http.SameSiteDefaultMode
+	}
+}
+
+// dateEnv 함수는 "2006-01-02" 형식의 날짜 환경 변수를 읽습니다. (UTC 자정, 비어 있거나 잘못된 값이면 0)
+func dateEnv(key string) time.Time {
+	value := os.Getenv(key)
+	if value == "" {
+		return time.Time{}
+	}
+
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		log.Printf("Invalid %s: %q, expected YYYY-MM-DD", key, value)
+		return time.Time{}
 	}
+	return date
 }
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+const (
+	PropagationBackground = "Background" // 소유 리소스를 K8s GC가 나중에 삭제 (기본값)
+	PropagationForeground = "Foreground" // 소유 리소스가 모두 삭제된 뒤 삭제 완료
+	PropagationOrphan     = "Orphan"     // 소유 리소스는 남겨 둠
+)
+
+// DeletePolicy는 리소스를 삭제할 때 K8s에 전달하는 옵션입니다.
+type DeletePolicy struct {
+	Propagation        string `json:"propagation"`                    // Background / Foreground / Orphan
+	GracePeriodSeconds *int64 `json:"grace_period_seconds,omitempty"` // nil이면 리소스 기본값 (Pod는 30초)
+	Force              bool   `json:"force,omitempty"`                // 유예 없이 즉시 삭제 (grace period 0)
+}
+
+// DefaultDeletePolicy는 설정이 없는 리소스 종류에 적용되는 삭제 동작입니다.
+var DefaultDeletePolicy = DeletePolicy{Propagation: PropagationBackground}
+
+// Validate는 전파 정책과 유예 시간이 올바른지 확인합니다.
+func (p DeletePolicy) Validate() error {
+	switch p.Propagation {
+	case PropagationBackground, PropagationForeground, PropagationOrphan:
+	default:
+		return fmt.Errorf("propagation must be one of Background, Foreground, Orphan")
+	}
+	if p.GracePeriodSeconds != nil && *p.GracePeriodSeconds < 0 {
+		return fmt.Errorf("grace_period_seconds must not be negative")
+	}
+	if p.Force && p.GracePeriodSeconds != nil && *p.GracePeriodSeconds != 0 {
+		return fmt.Errorf("force cannot be used with a non-zero grace_period_seconds")
+	}
+	return nil
+}
+
+// DeletePolicyFor는 리소스 종류(Kind)에 설정된 삭제 동작을 반환합니다. 설정이 없으면 DefaultDeletePolicy입니다.
+func (c *Config) DeletePolicyFor(kind string) DeletePolicy {
+	if policy, ok := c.DeletePolicies[strings.ToLower(kind)]; ok {
+		return policy
+	}
+	return DefaultDeletePolicy
+}
+
+// parseDeletePolicies는 DELETE_POLICIES 환경 변수를 읽습니다.
+// 형식: "Kind=Propagation[/graceSeconds|/force], ..." (예: "VirtualMachine=Foreground/60, Pod=Background/force")
+// 잘못된 항목은 로그를 남기고 무시합니다.
+func parseDeletePolicies(value string) map[string]DeletePolicy {
+	policies := map[string]DeletePolicy{}
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		kind, spec, found := strings.Cut(entry, "=")
+		kind = strings.TrimSpace(kind)
+		if !found || kind == "" {
+			log.Printf("Invalid DELETE_POLICIES entry: %q", entry)
+			continue
+		}
+
+		propagation, option, _ := strings.Cut(strings.TrimSpace(spec), "/")
+		policy := DeletePolicy{Propagation: propagation}
+		switch {
+		case option == "":
+		case option == "force":
+			policy.Force = true
+		default:
+			seconds, err := strconv.ParseInt(option, 10, 64)
+			if err != nil {
+				log.Printf("Invalid DELETE_POLICIES entry: %q", entry)
+				continue
+			}
+			policy.GracePeriodSeconds = &seconds
+		}
+
+		if err := policy.Validate(); err != nil {
+			log.Printf("Invalid DELETE_POLICIES entry %q: %v", entry, err)
+			continue
+		}
+		policies[strings.ToLower(kind)] = policy
+	}
+
+	return policies
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cast"
+)
+
+// 기능 플래그 이름
+const (
+	FeatureSandboxTools = "sandbox-tools" // 관리자용 샌드박스 도구 (/api/test, release 빌드 태그에서는 제외)
+)
+
+// 알려진 기능 플래그와 설명 (FEATURE_FLAGS, PUT /api/admin/features/:name)
+var knownFeatures = map[string]string{
+	FeatureSandboxTools: "Admin sandbox tools (POST /api/test/create-vm, /api/test/delete-vm)",
+}
+
+// FeatureFlag는 기능 플래그의 현재 상태입니다.
+type FeatureFlag struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+	Overridden  bool   `json:"overridden"` // 실행 중 관리자가 변경함 (재시작하면 FEATURE_FLAGS 값으로 돌아감)
+}
+
+var (
+	featureMu        sync.RWMutex
+	featureOverrides = map[string]bool{} // 실행 중 변경된 값 (SetFeature)
+)
+
+// parseFeatureFlags는 FEATURE_FLAGS("name", "name=true", "name=false"를 쉼표로 구분)를 읽습니다.
+func parseFeatureFlags(raw string) map[string]bool {
+	features := map[string]bool{}
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if _, ok := knownFeatures[name]; !ok {
+			log.Printf("Unknown feature flag %q in FEATURE_FLAGS is ignored", name)
+			continue
+		}
+
+		enabled := true
+		if hasValue {
+			enabled = cast.ToBool(strings.TrimSpace(value))
+		}
+		features[name] = enabled
+	}
+	return features
+}
+
+// FeatureEnabled는 기능 플래그가 켜져 있는지 반환합니다. 실행 중 변경된 값이 있으면 그 값을 사용합니다.
+func FeatureEnabled(name string) bool {
+	featureMu.RLock()
+	enabled, overridden := featureOverrides[name]
+	featureMu.RUnlock()
+
+	if overridden {
+		return enabled
+	}
+	return Get().Features[name]
+}
+
+// SetFeature는 재시작 없이 기능 플래그를 바꿉니다. (라우트는 항상 등록되어 있고 FeatureGuard가 요청마다 확인)
+func SetFeature(name string, enabled bool) error {
+	if _, ok := knownFeatures[name]; !ok {
+		return fmt.Errorf("unknown feature flag: %s", name)
+	}
+
+	featureMu.Lock()
+	defer featureMu.Unlock()
+
+	featureOverrides[name] = enabled
+	return nil
+}
+
+// Features는 알려진 모든 기능 플래그의 상태를 이름순으로 반환합니다.
+func Features() []FeatureFlag {
+	featureMu.RLock()
+	defer featureMu.RUnlock()
+
+	flags := make([]FeatureFlag, 0, len(knownFeatures))
+	for name, description := range knownFeatures {
+		enabled, overridden := featureOverrides[name]
+		if !overridden {
+			enabled = Get().Features[name]
+		}
+		flags = append(flags, FeatureFlag{Name: name, Description: description, Enabled: enabled, Overridden: overridden})
+	}
+
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Name < flags[j].Name })
+	return flags
+}
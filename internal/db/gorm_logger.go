@@ -0,0 +1,127 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// 이 시간보다 오래 걸린 쿼리는 warn 레벨로 기록
+const slowQueryThreshold = 200 * time.Millisecond
+
+const redacted = "[REDACTED]"
+
+// slogGormLogger는 GORM 로그를 구조화 로거(slog)로 전달하고, 민감한 파라미터 값을 가립니다.
+type slogGormLogger struct {
+	level logger.LogLevel
+}
+
+// newGormLogger는 설정 문자열(silent/error/warn/info)에 맞는 GORM 로거를 생성합니다.
+func newGormLogger(level string) logger.Interface {
+	return &slogGormLogger{level: parseGormLevel(level)}
+}
+
+func parseGormLevel(level string) logger.LogLevel {
+	switch strings.ToLower(level) {
+	case "silent":
+		return logger.Silent
+	case "error":
+		return logger.Error
+	case "info":
+		return logger.Info
+	default:
+		return logger.Warn
+	}
+}
+
+func (l *slogGormLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return &slogGormLogger{level: level}
+}
+
+func (l *slogGormLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Info {
+		slog.InfoContext(ctx, fmt.Sprintf(msg, args...), "component", "gorm")
+	}
+}
+
+func (l *slogGormLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Warn {
+		slog.WarnContext(ctx, fmt.Sprintf(msg, args...), "component", "gorm")
+	}
+}
+
+func (l *slogGormLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Error {
+		slog.ErrorContext(ctx, fmt.Sprintf(msg, args...), "component", "gorm")
+	}
+}
+
+func (l *slogGormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= logger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+
+	switch {
+	case err != nil && l.level >= logger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		sql, rows := fc()
+		slog.ErrorContext(ctx, "sql error", "component", "gorm", "error", err, "elapsed_ms", elapsed.Milliseconds(), "rows", rows, "sql", sql)
+	case elapsed > slowQueryThreshold && l.level >= logger.Warn:
+		sql, rows := fc()
+		slog.WarnContext(ctx, "slow sql", "component", "gorm", "elapsed_ms", elapsed.Milliseconds(), "rows", rows, "sql", sql)
+	case l.level >= logger.Info:
+		sql, rows := fc()
+		slog.InfoContext(ctx, "sql", "component", "gorm", "elapsed_ms", elapsed.Milliseconds(), "rows", rows, "sql", sql)
+	}
+}
+
+var (
+	// INSERT INTO "users" ("a","b") VALUES ($1,$2),($3,$4)
+	insertColumnsRegex = regexp.MustCompile(`(?is)^\s*INSERT\s+INTO\s+\S+\s*\(([^)]*)\)\s*VALUES`)
+	// "password" = $3, password_hash=$4 ...
+	assignmentRegex = regexp.MustCompile(`"?([A-Za-z0-9_]+)"?\s*(?:=|<>|!=|LIKE)\s*\$(\d+)`)
+)
+
+// isSensitiveColumn은 로그에 값이 남으면 안 되는 컬럼인지 확인합니다.
+func isSensitiveColumn(column string) bool {
+	column = strings.ToLower(strings.Trim(strings.TrimSpace(column), `"`))
+	return strings.Contains(column, "password") || strings.Contains(column, "secret") || strings.Contains(column, "token")
+}
+
+// ParamsFilter는 GORM이 SQL에 값을 채워 넣기 전에 호출되며, 민감한 컬럼의 값을 [REDACTED]로 바꿉니다.
+// (gorm.ParamsFilter 인터페이스 구현)
+func (l *slogGormLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	filtered := make([]interface{}, len(params))
+	copy(filtered, params)
+
+	// INSERT: 컬럼 순서대로 파라미터가 반복되므로 인덱스로 매핑
+	if match := insertColumnsRegex.FindStringSubmatch(sql); match != nil {
+		columns := strings.Split(match[1], ",")
+		for i := range filtered {
+			if len(columns) > 0 && isSensitiveColumn(columns[i%len(columns)]) {
+				filtered[i] = redacted
+			}
+		}
+	}
+
+	// UPDATE SET / WHERE: "column" = $n 형태
+	for _, match := range assignmentRegex.FindAllStringSubmatch(sql, -1) {
+		if !isSensitiveColumn(match[1]) {
+			continue
+		}
+		if idx, err := strconv.Atoi(match[2]); err == nil && idx >= 1 && idx <= len(filtered) {
+			filtered[idx-1] = redacted
+		}
+	}
+
+	return sql, filtered
+}
@@ -0,0 +1,158 @@
+package db
+
+import (
+	"database/sql"
+	"log"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"vm-controller/internal/metrics"
+)
+
+// PoolStats는 /readyz 및 메트릭으로 노출되는 커넥션 풀 상태입니다.
+type PoolStats struct {
+	MaxOpen         int     `json:"max_open"`
+	Open            int     `json:"open"`
+	InUse           int     `json:"in_use"`
+	Idle            int     `json:"idle"`
+	WaitCount       int64   `json:"wait_count"`
+	WaitDurationSec float64 `json:"wait_duration_seconds"`
+	Recycles        int64   `json:"recycles"`
+}
+
+var poolRecycles atomic.Int64
+
+func init() {
+	// 스크랩 시점에 현재 풀 상태를 읽어서 노출
+	metrics.NewGaugeFunc("db_pool_open_connections", "Number of established connections (in use + idle).", func() float64 { return float64(currentSQLStats().OpenConnections) })
+	metrics.NewGaugeFunc("db_pool_in_use_connections", "Number of connections currently in use.", func() float64 { return float64(currentSQLStats().InUse) })
+	metrics.NewGaugeFunc("db_pool_idle_connections", "Number of idle connections.", func() float64 { return float64(currentSQLStats().Idle) })
+	metrics.NewGaugeFunc("db_pool_wait_count_total", "Total number of connections waited for.", func() float64 { return float64(currentSQLStats().WaitCount) })
+	metrics.NewGaugeFunc("db_pool_wait_duration_seconds_total", "Total time blocked waiting for a new connection.", func() float64 { return currentSQLStats().WaitDuration.Seconds() })
+	metrics.NewGaugeFunc("db_pool_recycles_total", "Number of times the watchdog recycled the connection pool.", func() float64 { return float64(poolRecycles.Load()) })
+}
+
+// currentSQLStats는 현재 풀의 sql.DBStats를 반환합니다. (미초기화 시 0 값)
+func currentSQLStats() sql.DBStats {
+	dbMu.RLock()
+	current := DB
+	dbMu.RUnlock()
+
+	if current == nil {
+		return sql.DBStats{}
+	}
+
+	sqlDB, err := current.DB()
+	if err != nil {
+		return sql.DBStats{}
+	}
+
+	return sqlDB.Stats()
+}
+
+// GetPoolStats는 현재 커넥션 풀 상태를 반환합니다.
+func GetPoolStats() PoolStats {
+	stats := currentSQLStats()
+
+	return PoolStats{
+		MaxOpen:         stats.MaxOpenConnections,
+		Open:            stats.OpenConnections,
+		InUse:           stats.InUse,
+		Idle:            stats.Idle,
+		WaitCount:       stats.WaitCount,
+		WaitDurationSec: stats.WaitDuration.Seconds(),
+		Recycles:        poolRecycles.Load(),
+	}
+}
+
+// Ping은 readiness 체크용으로 DB 연결을 확인합니다. (GetDB와 달리 재연결/패닉 없음)
+func Ping() error {
+	dbMu.RLock()
+	current := DB
+	dbMu.RUnlock()
+
+	if current == nil {
+		return sql.ErrConnDone
+	}
+
+	sqlDB, err := current.DB()
+	if err != nil {
+		return err
+	}
+
+	return sqlDB.Ping()
+}
+
+// recyclePool은 새 커넥션 풀을 만들어 교체하고, 기존 풀은 진행 중인 쿼리가 끝나도록 잠시 후 닫습니다.
+func recyclePool() error {
+	conn, err := openDB()
+	if err != nil {
+		return err
+	}
+
+	dbMu.Lock()
+	old := DB
+	DB = conn
+	dbMu.Unlock()
+
+	poolRecycles.Add(1)
+
+	if old != nil {
+		go func() {
+			time.Sleep(30 * time.Second)
+			if sqlDB, err := old.DB(); err == nil {
+				sqlDB.Close()
+			}
+		}()
+	}
+
+	return nil
+}
+
+// poolWaitThreshold는 watchdog이 풀을 교체하는 기준 대기 시간입니다. (DB_POOL_WAIT_THRESHOLD, 기본 2s)
+func poolWaitThreshold() time.Duration {
+	threshold, err := time.ParseDuration(os.Getenv("DB_POOL_WAIT_THRESHOLD"))
+	if err != nil || threshold <= 0 {
+		threshold = 2 * time.Second
+	}
+	return threshold
+}
+
+// StartPoolWatchdog는 interval마다 커넥션 대기 시간을 관찰하여,
+// 구간 동안의 평균 대기 시간이 임계값을 넘으면 풀을 선제적으로 교체합니다.
+// 기존에는 풀이 고갈되어도 요청이 조용히 느려지기만 했습니다.
+func StartPoolWatchdog(interval time.Duration) {
+	threshold := poolWaitThreshold()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		prev := currentSQLStats()
+		for range ticker.C {
+			cur := currentSQLStats()
+
+			waits := cur.WaitCount - prev.WaitCount
+			waited := cur.WaitDuration - prev.WaitDuration
+			prev = cur
+
+			// 풀이 교체되면 누적값이 초기화되므로 음수 구간은 무시
+			if waits <= 0 || waited <= 0 {
+				continue
+			}
+
+			avgWait := waited / time.Duration(waits)
+			if avgWait < threshold {
+				continue
+			}
+
+			log.Printf("DB pool watchdog: average wait %v exceeds %v (in_use=%d, idle=%d). Recycling pool... (커넥션 풀 교체)", avgWait, threshold, cur.InUse, cur.Idle)
+			if err := recyclePool(); err != nil {
+				log.Printf("DB pool watchdog: failed to recycle pool: %v", err)
+				continue
+			}
+			prev = currentSQLStats()
+		}
+	}()
+}
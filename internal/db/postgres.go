@@ -4,36 +4,166 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
+	"sync"
 	"time"
 
+	"vm-controller/internal/config"
 	"vm-controller/internal/models"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
 var (
-	DB *gorm.DB
+	DB   *gorm.DB
+	dbMu sync.RWMutex // 커넥션 풀 교체(recycle) 시 DB 포인터 보호
 )
 
 // InitDB는 환경 변수를 사용하여 데이터베이스 연결을 초기화합니다.
 // InitDB initializes the database connection using environment variables.
 func InitDB() error {
+	conn, err := openDB()
+	if err != nil {
+		return err
+	}
+
+	dbMu.Lock()
+	DB = conn
+	dbMu.Unlock()
+
+	// 3. Auto Migration (자동 마이그레이션)
+	// 정의된 모델(struct)을 기반으로 테이블을 자동으로 생성하거나 스키마를 업데이트합니다.
+	// Auto Migration: Automatically migrate schema based on defined models
+	log.Println("Running AutoMigrate... (테이블 자동 생성 중)")
+	err = Migrate(conn)
+	if err != nil {
+		return fmt.Errorf("failed to migrate database schema: %w", err)
+	}
+	log.Println("Database migration completed (마이그레이션 완료)")
+
+	return nil
+}
+
+// Migrate는 모든 모델의 테이블을 생성하거나 스키마를 갱신합니다. (테스트용 DB에서도 사용)
+func Migrate(conn *gorm.DB) error {
+	if err := failLegacyOperations(conn); err != nil {
+		return err
+	}
+
+	err := conn.AutoMigrate(
+		&models.User{},
+		&models.VirtualMachine{},
+		&models.Deployment{},
+		&models.JobRun{},
+		&models.Notification{},
+		&models.ManagedDatabase{},
+		&models.VmEvent{},
+		&models.Operation{},
+		&models.IdempotencyKey{},
+		&models.AuditLog{},
+		&models.ConsoleSession{},
+		&models.Network{},
+		&models.Snapshot{},
+		&models.BundleAttempt{},
+		&models.SSHKey{},
+		&models.VolumeAttachment{},
+		&models.VmApproval{},
+		&models.Flavor{},
+		&models.Image{},
+		&models.VmPreference{},
+		&models.NodePoolMaintenance{},
+		&models.AbuseReport{},
+		&models.VmFlavorChange{},
+		&models.PublicStatSetting{},
+		&models.NotificationTemplate{},
+	)
+	if err != nil {
+		return err
+	}
+
+	// 대상별로 끝나지 않은 작업은 하나만 (여러 서버 인스턴스가 같은 VM에 동시에 작업을 넣지 않도록)
+	return conn.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_operations_active_target ON operations (target) WHERE status IN ('Queued', 'Running')").Error
+}
+
+// failLegacyOperations는 작업 큐 이전 버전이 남긴 Queued/Running 작업을 실패로 표시합니다.
+// 인자(payload)가 없어 이어서 실행할 수 없고, 같은 대상에 여러 개가 남아 있으면 고유 인덱스를 만들 수 없기 때문입니다.
+func failLegacyOperations(conn *gorm.DB) error {
+	migrator := conn.Migrator()
+	if !migrator.HasTable(&models.Operation{}) || migrator.HasColumn(&models.Operation{}, "Payload") {
+		return nil
+	}
+
+	return conn.Model(&models.Operation{}).
+		Where("status IN ?", models.OperationActiveStatuses).
+		Updates(map[string]any{
+			"status":      models.OperationFailed,
+			"error":       "interrupted by server restart",
+			"finished_at": time.Now(),
+		}).Error
+}
+
+// openDB는 DSN을 구성하여 새 커넥션 풀을 생성합니다. (마이그레이션은 수행하지 않음)
+func openDB() (*gorm.DB, error) {
+	dsn, preferSimpleProtocol, err := connectionDSN()
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. GORM을 사용하여 PostgreSQL 드라이버로 연결
+	// Connect to PostgreSQL driver using GORM
+	// SQL 로그 레벨은 설정(DB_LOG_LEVEL)을 따르며, 구조화 로거로 출력됨
+	dialector := postgres.New(postgres.Config{
+		DSN:                  dsn,
+		PreferSimpleProtocol: preferSimpleProtocol,
+	})
+	conn, err := gorm.Open(dialector, &gorm.Config{
+		Logger: newGormLogger(config.Get().DBLogLevel),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database (DB 연결 실패): %w", err)
+	}
+
+	// 2. Connection Pool(커넥션 풀) 설정
+	// Configure Connection Pool
+	sqlDB, err := conn.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get generic database object: %w", err)
+	}
+
+	// SetMaxIdleConns: 유휴 상태로 유지할 최대 커넥션 수
+	// SetMaxIdleConns: Maximum number of idle connections
+	sqlDB.SetMaxIdleConns(10)
+	// SetMaxOpenConns: 데이터베이스에 오픈할 수 있는 최대 커넥션 수
+	// SetMaxOpenConns: Maximum number of open connections
+	sqlDB.SetMaxOpenConns(100)
+	// SetConnMaxLifetime: 커넥션이 재사용될 수 있는 최대 시간
+	// SetConnMaxLifetime: Maximum amount of time a connection may be reused
+	sqlDB.SetConnMaxLifetime(time.Hour)
+
+	log.Println("Successfully connected to PostgreSQL database (PostgreSQL 연결 성공)")
+
+	return conn, nil
+}
+
+// connectionDSN은 환경 변수(DATABASE_URL, DB_*, SUPABASE_*)로 연결 문자열을 구성합니다.
+// 두 번째 반환값은 simple protocol 사용 여부입니다. (Supabase transaction 모드)
+func connectionDSN() (string, bool, error) {
 	// 환경 변수 확인
 	databaseURL := os.Getenv("DATABASE_URL")
 	dbHost := os.Getenv("DB_HOST")
 	supabaseProjectID := os.Getenv("SUPABASE_PROJECT_ID")
 
 	if dbHost == "" && supabaseProjectID == "" {
-		return fmt.Errorf("no database configuration found (DB 설정이 없습니다)")
+		return "", false, fmt.Errorf("no database configuration found (DB 설정이 없습니다)")
 	}
 
 	if dbHost != "" && supabaseProjectID != "" {
-		return fmt.Errorf("어떤 DataBase를 사용해야하는지 알 수 없습니다. 1개의 데이터베이스만 환경변수에 등록하세요.")
+		return "", false, fmt.Errorf("어떤 DataBase를 사용해야하는지 알 수 없습니다. 1개의 데이터베이스만 환경변수에 등록하세요.")
 	}
 
 	var dsn string
+	preferSimpleProtocol := false // transaction 풀링 모드에서는 prepared statement를 사용할 수 없음
 
 	// 1순위: DATABASE_URL (직접 연결 문자열 사용)
 	// Priority 1: DATABASE_URL (Use direct connection string)
@@ -43,81 +173,139 @@ func InitDB() error {
 	} else {
 		// 2순위: DB_HOST와 SUPABASE_PROJECT_ID 중복 체크
 		if dbHost != "" && supabaseProjectID != "" {
-			return fmt.Errorf("어떤 DataBase를 사용해야하는지 알 수 없습니다. 1개의 데이터베이스만 환경변수에 등록하세요.")
+			return "", false, fmt.Errorf("어떤 DataBase를 사용해야하는지 알 수 없습니다. 1개의 데이터베이스만 환경변수에 등록하세요.")
 		}
 
 		if supabaseProjectID != "" {
-			// Supabase Direct Connection (IPv6 connection likely)
 			log.Println("Initializing Supabase connection... (Supabase 연결 초기화 중)")
-			dbPassword := os.Getenv("SUPABASE_PASSWORD")
-
-			targetHost := "aws-1-ap-south-1.pooler.supabase.com"
-			userName := fmt.Sprintf("postgres.%s", supabaseProjectID)
-
-			dsn = fmt.Sprintf("host=%s user=%s password=%s dbname=postgres port=5432 sslmode=disable TimeZone=Asia/Seoul",
-				targetHost,
-				userName,
-				dbPassword,
-			)
+			supabaseConn, simple, err := supabaseDSN(supabaseProjectID)
+			if err != nil {
+				return "", false, err
+			}
+			dsn = supabaseConn
+			preferSimpleProtocol = simple
 		} else if dbHost != "" {
 			// 일반 PostgreSQL 연결 설정
 			log.Println("Initializing Standard PostgreSQL connection... (일반 PostgreSQL 연결 초기화 중)")
-			dsn = fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Asia/Seoul",
+			tlsParams, err := sslParams("disable")
+			if err != nil {
+				return "", false, err
+			}
+			dsn = fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s %s TimeZone=Asia/Seoul",
 				dbHost,
 				os.Getenv("DB_USER"),
 				os.Getenv("DB_PASSWORD"),
 				os.Getenv("DB_NAME"),
 				os.Getenv("DB_PORT"),
+				tlsParams,
 			)
 		} else {
-			return fmt.Errorf("no database configuration found (DB 설정이 없습니다)")
+			return "", false, fmt.Errorf("no database configuration found (DB 설정이 없습니다)")
 		}
 	}
 
-	var err error
-	// 1. GORM을 사용하여 PostgreSQL 드라이버로 연결
-	// Connect to PostgreSQL driver using GORM
-	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
-	})
+	return dsn, preferSimpleProtocol, nil
+}
+
+// ConnString은 pg_dump/pg_restore 등 libpq 도구에 넘길 연결 문자열을 반환합니다.
+// GORM 전용 파라미터(TimeZone)는 libpq가 인식하지 못하므로 제외합니다.
+func ConnString() (string, error) {
+	dsn, _, err := connectionDSN()
 	if err != nil {
-		return fmt.Errorf("failed to connect to database (DB 연결 실패): %w", err)
+		return "", err
 	}
+	return strings.TrimSuffix(dsn, " TimeZone=Asia/Seoul"), nil
+}
 
-	// 2. Connection Pool(커넥션 풀) 설정
-	// Configure Connection Pool
-	sqlDB, err := DB.DB()
-	if err != nil {
-		return fmt.Errorf("failed to get generic database object: %w", err)
+// supabaseDSN은 SUPABASE_* 환경 변수로 Supabase 연결 문자열을 구성합니다.
+// 두 번째 반환값은 simple protocol 사용 여부입니다. (transaction 모드)
+func supabaseDSN(projectID string) (string, bool, error) {
+	host := os.Getenv("SUPABASE_HOST")
+	poolMode := strings.ToLower(os.Getenv("SUPABASE_POOL_MODE"))
+	if poolMode == "" {
+		poolMode = "direct"
+		if host != "" {
+			poolMode = "session"
+		}
 	}
 
-	// SetMaxIdleConns: 유휴 상태로 유지할 최대 커넥션 수
-	// SetMaxIdleConns: Maximum number of idle connections
-	sqlDB.SetMaxIdleConns(10)
-	// SetMaxOpenConns: 데이터베이스에 오픈할 수 있는 최대 커넥션 수
-	// SetMaxOpenConns: Maximum number of open connections
-	sqlDB.SetMaxOpenConns(100)
-	// SetConnMaxLifetime: 커넥션이 재사용될 수 있는 최대 시간
-	// SetConnMaxLifetime: Maximum amount of time a connection may be reused
-	sqlDB.SetConnMaxLifetime(time.Hour)
+	var port, userName string
+	switch poolMode {
+	case "direct":
+		// 직접 연결: 프로젝트 전용 호스트, 사용자명은 postgres
+		if host == "" {
+			host = fmt.Sprintf("db.%s.supabase.co", projectID)
+		}
+		port = "5432"
+		userName = "postgres"
+	case "session", "transaction":
+		// 풀러 연결: 리전별 호스트가 필요하며, 사용자명에 프로젝트 ID를 붙임
+		if host == "" {
+			return "", false, fmt.Errorf("SUPABASE_HOST is required for %s pool mode (풀러 호스트를 지정하세요)", poolMode)
+		}
+		port = "5432"
+		if poolMode == "transaction" {
+			port = "6543"
+		}
+		userName = fmt.Sprintf("postgres.%s", projectID)
+	default:
+		return "", false, fmt.Errorf("invalid SUPABASE_POOL_MODE: %s (direct, session, transaction 중 하나)", poolMode)
+	}
 
-	log.Println("Successfully connected to PostgreSQL database (PostgreSQL 연결 성공)")
+	if customPort := os.Getenv("SUPABASE_PORT"); customPort != "" {
+		port = customPort
+	}
 
-	// 3. Auto Migration (자동 마이그레이션)
-	// 정의된 모델(struct)을 기반으로 테이블을 자동으로 생성하거나 스키마를 업데이트합니다.
-	// Auto Migration: Automatically migrate schema based on defined models
-	log.Println("Running AutoMigrate... (테이블 자동 생성 중)")
-	err = DB.AutoMigrate(
-		&models.User{},
-		&models.VirtualMachine{},
-		&models.Deployment{},
-	)
+	tlsParams, err := sslParams("require")
 	if err != nil {
-		return fmt.Errorf("failed to migrate database schema: %w", err)
+		return "", false, err
 	}
-	log.Println("Database migration completed (마이그레이션 완료)")
 
-	return nil
+	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=postgres port=%s %s TimeZone=Asia/Seoul",
+		host,
+		userName,
+		os.Getenv("SUPABASE_PASSWORD"),
+		port,
+		tlsParams,
+	)
+	return dsn, poolMode == "transaction", nil
+}
+
+// sslParams는 DB_SSL* 환경 변수로 DSN의 TLS 관련 파라미터를 구성합니다.
+func sslParams(defaultMode string) (string, error) {
+	mode := strings.ToLower(os.Getenv("DB_SSLMODE"))
+	if mode == "" {
+		mode = defaultMode
+	}
+
+	switch mode {
+	case "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
+	default:
+		return "", fmt.Errorf("invalid DB_SSLMODE: %s", mode)
+	}
+
+	rootCert := os.Getenv("DB_SSLROOTCERT")
+	if (mode == "verify-ca" || mode == "verify-full") && rootCert == "" {
+		return "", fmt.Errorf("DB_SSLROOTCERT is required for sslmode=%s (인증서 검증에 CA 인증서가 필요합니다)", mode)
+	}
+
+	params := []string{"sslmode=" + mode}
+	for _, kv := range [][2]string{
+		{"sslrootcert", rootCert},
+		{"sslcert", os.Getenv("DB_SSLCERT")},
+		{"sslkey", os.Getenv("DB_SSLKEY")},
+	} {
+		key, value := kv[0], kv[1]
+		if value == "" {
+			continue
+		}
+		if _, err := os.Stat(value); err != nil {
+			return "", fmt.Errorf("%s file not accessible: %v", key, err)
+		}
+		params = append(params, key+"="+value)
+	}
+
+	return strings.Join(params, " "), nil
 }
 
 // GetDB는 데이터베이스 인스턴스를 반환합니다.
@@ -129,10 +317,14 @@ func InitDB() error {
 func GetDB() *gorm.DB {
 	// 연결 확인 로직
 	// Check connection status
-	if DB != nil {
-		sqlDB, err := DB.DB()
+	dbMu.RLock()
+	current := DB
+	dbMu.RUnlock()
+
+	if current != nil {
+		sqlDB, err := current.DB()
 		if err == nil && sqlDB.Ping() == nil {
-			return DB
+			return current
 		}
 	}
 
@@ -144,5 +336,15 @@ func GetDB() *gorm.DB {
 		panic(err)
 	}
 
+	dbMu.RLock()
+	defer dbMu.RUnlock()
+	return DB
+}
+
+// Current는 현재 커넥션 풀을 반환합니다. GetDB와 달리 재연결을 시도하지 않으며, 미초기화 시 nil입니다.
+// (메트릭 스크랩처럼 DB 장애 시 실패해도 되는 조회용)
+func Current() *gorm.DB {
+	dbMu.RLock()
+	defer dbMu.RUnlock()
 	return DB
 }
@@ -0,0 +1,142 @@
+package db
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm/schema"
+)
+
+// 암호화된 값의 접두사. 접두사가 없는 값은 암호화 도입 전에 저장된 평문으로 취급합니다.
+const encryptedPrefix = "enc:v1:"
+
+var (
+	secretKey     []byte
+	secretKeyOnce sync.Once
+)
+
+func init() {
+	// `gorm:"serializer:encrypted"` 태그가 붙은 문자열 필드는 AES-GCM으로 암호화되어 저장됩니다.
+	schema.RegisterSerializer("encrypted", EncryptedSerializer{})
+}
+
+// loadSecretKey는 SECRET_ENCRYPTION_KEY(base64, 32바이트)를 읽습니다.
+func loadSecretKey() []byte {
+	secretKeyOnce.Do(func() {
+		raw := strings.TrimSpace(os.Getenv("SECRET_ENCRYPTION_KEY"))
+		if raw == "" {
+			return
+		}
+
+		key, err := base64.StdEncoding.DecodeString(raw)
+		if err != nil || len(key) != 32 {
+			log.Println("SECRET_ENCRYPTION_KEY must be base64 encoded 32 bytes, secrets are stored unencrypted (암호화 키 형식 오류)")
+			return
+		}
+		secretKey = key
+	})
+
+	return secretKey
+}
+
+// SecretEncryptionEnabled는 암호화 키가 설정되어 비밀 값이 암호화되어 저장되는지 반환합니다.
+func SecretEncryptionEnabled() bool {
+	return loadSecretKey() != nil
+}
+
+func newSecretCipher() (cipher.AEAD, error) {
+	key := loadSecretKey()
+	if key == nil {
+		return nil, fmt.Errorf("SECRET_ENCRYPTION_KEY is not configured")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptSecret은 키가 설정된 경우 평문을 암호화합니다. 키가 없으면 평문을 그대로 반환합니다.
+func encryptSecret(plain string) (string, error) {
+	if plain == "" || !SecretEncryptionEnabled() {
+		return plain, nil
+	}
+
+	gcm, err := newSecretCipher()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(plain), nil)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decryptSecret은 암호화된 값을 복호화합니다. 접두사가 없는 값(기존 평문)은 그대로 반환합니다.
+func decryptSecret(stored string) (string, error) {
+	if !strings.HasPrefix(stored, encryptedPrefix) {
+		return stored, nil
+	}
+
+	gcm, err := newSecretCipher()
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(stored, encryptedPrefix))
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return "", fmt.Errorf("malformed encrypted value")
+	}
+
+	plain, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %v", err)
+	}
+	return string(plain), nil
+}
+
+// EncryptedSerializer는 문자열 필드를 저장 시 암호화하고 조회 시 복호화하는 GORM serializer입니다.
+type EncryptedSerializer struct{}
+
+func (EncryptedSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var stored string
+	switch v := dbValue.(type) {
+	case nil:
+	case []byte:
+		stored = string(v)
+	case string:
+		stored = v
+	default:
+		return fmt.Errorf("unsupported encrypted value type %T", dbValue)
+	}
+
+	plain, err := decryptSecret(stored)
+	if err != nil {
+		return err
+	}
+
+	field.ReflectValueOf(ctx, dst).SetString(plain)
+	return nil
+}
+
+func (EncryptedSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	plain, ok := fieldValue.(string)
+	if !ok {
+		return nil, fmt.Errorf("encrypted serializer supports string fields only, got %T", fieldValue)
+	}
+	return encryptSecret(plain)
+}
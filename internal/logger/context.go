@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+type requestKey struct{}
+
+// requestIDs는 요청 하나를 가리키는 상관관계 ID입니다.
+type requestIDs struct {
+	requestID string
+	traceID   string
+}
+
+// WithRequest는 요청 ID와 trace id를 ctx에 담습니다.
+// HTTP/gRPC 진입점에서 한 번 설정하면 서비스 계층과 백그라운드 작업이 FromContext로 같은 ID를 로그에 남깁니다.
+func WithRequest(ctx context.Context, requestID, traceID string) context.Context {
+	return context.WithValue(ctx, requestKey{}, requestIDs{requestID: requestID, traceID: traceID})
+}
+
+// RequestIDFrom은 ctx의 요청 ID를 반환합니다. 요청 없이 시작된 작업(converger 등)은 빈 문자열입니다.
+func RequestIDFrom(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	ids, _ := ctx.Value(requestKey{}).(requestIDs)
+	return ids.requestID
+}
+
+// TraceIDFrom은 ctx의 trace id를 반환합니다.
+func TraceIDFrom(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	ids, _ := ctx.Value(requestKey{}).(requestIDs)
+	return ids.traceID
+}
+
+// FromContext는 ctx의 request_id, trace_id를 속성으로 붙인 로거를 반환합니다. (없으면 기본 로거)
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx == nil {
+		return slog.Default()
+	}
+	ids, ok := ctx.Value(requestKey{}).(requestIDs)
+	if !ok {
+		return slog.Default()
+	}
+
+	attrs := []any{"request_id", ids.requestID}
+	if ids.traceID != "" {
+		attrs = append(attrs, "trace_id", ids.traceID)
+	}
+	return slog.Default().With(attrs...)
+}
@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+
+	"vm-controller/internal/config"
+)
+
+// Init 함수는 설정에 따라 구조화 로거(slog)를 초기화하고 기본 로거로 등록합니다.
+// slog.SetDefault 이후에는 표준 log 패키지 출력도 같은 핸들러로 전달됩니다.
+func Init(cfg *config.Config) {
+	opts := &slog.HandlerOptions{Level: ParseLevel(cfg.LogLevel)}
+
+	var handler slog.Handler
+	if cfg.LogFormat == "text" {
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	} else {
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	}
+
+	slog.SetDefault(slog.New(handler))
+	log.SetFlags(0) // 시간 정보는 slog가 기록
+}
+
+// ParseLevel 함수는 문자열 로그 레벨을 slog.Level로 변환합니다. (알 수 없는 값은 info)
+func ParseLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
@@ -0,0 +1,38 @@
+package logger
+
+import "strings"
+
+const redacted = "[REDACTED]"
+
+// Redact는 text에 포함된 비밀 값(비밀번호 등)을 [REDACTED]로 바꿉니다. 빈 값은 무시합니다.
+func Redact(text string, secrets ...string) string {
+	for _, secret := range secrets {
+		if secret != "" {
+			text = strings.ReplaceAll(text, secret, redacted)
+		}
+	}
+	return text
+}
+
+// redactedError는 메시지에서 비밀 값을 가린 에러입니다. errors.Is/As를 위해 원래 에러를 감쌉니다.
+type redactedError struct {
+	err     error
+	message string
+}
+
+func (e *redactedError) Error() string { return e.message }
+func (e *redactedError) Unwrap() error { return e.err }
+
+// RedactError는 에러 메시지에 비밀 값이 포함되어 있으면 가린 에러를 반환합니다.
+// K8s API 에러는 거부된 필드 값을 메시지에 담을 수 있으므로, 로그/이벤트/작업 기록으로 전달하기 전에 사용합니다.
+func RedactError(err error, secrets ...string) error {
+	if err == nil {
+		return nil
+	}
+
+	message := err.Error()
+	if cleaned := Redact(message, secrets...); cleaned != message {
+		return &redactedError{err: err, message: cleaned}
+	}
+	return err
+}
@@ -0,0 +1,38 @@
+package logger
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+)
+
+// ParseTraceID는 W3C traceparent 헤더(version-traceid-spanid-flags)에서 trace id를 꺼냅니다.
+// 형식이 잘못되었거나 모두 0인 id는 빈 문자열을 반환합니다.
+func ParseTraceID(traceparent string) string {
+	parts := strings.Split(traceparent, "-")
+	if len(parts) != 4 || len(parts[1]) != 32 || parts[1] == strings.Repeat("0", 32) {
+		return ""
+	}
+	for _, r := range parts[1] {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return ""
+		}
+	}
+	return parts[1]
+}
+
+// NewTraceID는 OpenTelemetry 형식(16바이트 hex)의 새 trace id를 만듭니다.
+func NewTraceID() string {
+	return randomHex(16)
+}
+
+// NewSpanID는 OpenTelemetry 형식(8바이트 hex)의 새 span id를 만듭니다.
+func NewSpanID() string {
+	return randomHex(8)
+}
+
+func randomHex(size int) string {
+	b := make([]byte, size)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,167 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Prometheus text exposition format(0.0.4)을 직접 출력하는 경량 메트릭 레지스트리입니다.
+// 외부 의존성 없이 Counter / Gauge / GaugeFunc 만 지원합니다.
+
+type metricType string
+
+const (
+	typeCounter metricType = "counter"
+	typeGauge   metricType = "gauge"
+)
+
+type family struct {
+	name       string
+	help       string
+	typ        metricType
+	labelNames []string
+
+	mu     sync.Mutex
+	values map[string]float64 // 라벨 값 조합(키) -> 값
+	labels map[string][]string
+
+	fn func() float64 // GaugeFunc 용 (스크랩 시점에 계산)
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]*family{}
+)
+
+func register(f *family) *family {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if existing, ok := registry[f.name]; ok {
+		return existing
+	}
+	registry[f.name] = f
+	return f
+}
+
+// CounterVec은 라벨별로 단조 증가하는 카운터입니다.
+type CounterVec struct{ f *family }
+
+// GaugeVec은 라벨별로 임의의 값을 설정할 수 있는 게이지입니다.
+type GaugeVec struct{ f *family }
+
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{f: register(&family{name: name, help: help, typ: typeCounter, labelNames: labelNames, values: map[string]float64{}, labels: map[string][]string{}})}
+}
+
+func NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	return &GaugeVec{f: register(&family{name: name, help: help, typ: typeGauge, labelNames: labelNames, values: map[string]float64{}, labels: map[string][]string{}})}
+}
+
+// NewGaugeFunc는 스크랩 시점에 fn을 호출하여 값을 계산하는 게이지를 등록합니다.
+func NewGaugeFunc(name, help string, fn func() float64) {
+	register(&family{name: name, help: help, typ: typeGauge, fn: fn})
+}
+
+func (f *family) add(delta float64, labelValues []string) {
+	if len(labelValues) != len(f.labelNames) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", f.name, len(f.labelNames), len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+	f.mu.Lock()
+	f.values[key] += delta
+	f.labels[key] = labelValues
+	f.mu.Unlock()
+}
+
+func (f *family) set(value float64, labelValues []string) {
+	if len(labelValues) != len(f.labelNames) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", f.name, len(f.labelNames), len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+	f.mu.Lock()
+	f.values[key] = value
+	f.labels[key] = labelValues
+	f.mu.Unlock()
+}
+
+func (c *CounterVec) Inc(labelValues ...string) { c.f.add(1, labelValues) }
+
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		panic("metrics: counter cannot decrease")
+	}
+	c.f.add(delta, labelValues)
+}
+
+func (g *GaugeVec) Set(value float64, labelValues ...string) { g.f.set(value, labelValues) }
+
+func (g *GaugeVec) Add(delta float64, labelValues ...string) { g.f.add(delta, labelValues) }
+
+func escapeLabel(v string) string {
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, "\n", `\n`)
+	return strings.ReplaceAll(v, `"`, `\"`)
+}
+
+func (f *family) write(sb *strings.Builder) {
+	fmt.Fprintf(sb, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.typ)
+
+	if f.fn != nil {
+		fmt.Fprintf(sb, "%s %g\n", f.name, f.fn())
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	keys := make([]string, 0, len(f.values))
+	for k := range f.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if len(f.labelNames) == 0 {
+			fmt.Fprintf(sb, "%s %g\n", f.name, f.values[k])
+			continue
+		}
+
+		pairs := make([]string, len(f.labelNames))
+		for i, name := range f.labelNames {
+			pairs[i] = fmt.Sprintf(`%s="%s"`, name, escapeLabel(f.labels[k][i]))
+		}
+		fmt.Fprintf(sb, "%s{%s} %g\n", f.name, strings.Join(pairs, ","), f.values[k])
+	}
+}
+
+// Handler는 등록된 모든 메트릭을 Prometheus 텍스트 형식으로 출력합니다.
+func Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		registryMu.Lock()
+		names := make([]string, 0, len(registry))
+		for name := range registry {
+			names = append(names, name)
+		}
+		families := make([]*family, 0, len(names))
+		sort.Strings(names)
+		for _, name := range names {
+			families = append(families, registry[name])
+		}
+		registryMu.Unlock()
+
+		var sb strings.Builder
+		for _, f := range families {
+			f.write(&sb)
+		}
+
+		c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(sb.String()))
+	}
+}
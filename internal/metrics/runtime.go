@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// RuntimeStats는 진단용 Go 런타임 상태입니다.
+type RuntimeStats struct {
+	Goroutines     int     `json:"goroutines"`
+	HeapAllocBytes uint64  `json:"heap_alloc_bytes"`
+	HeapInuseBytes uint64  `json:"heap_inuse_bytes"`
+	HeapObjects    uint64  `json:"heap_objects"`
+	SysBytes       uint64  `json:"sys_bytes"`
+	NumGC          uint32  `json:"num_gc"`
+	LastGCPauseSec float64 `json:"last_gc_pause_seconds"`
+}
+
+var (
+	memStatsMu     sync.Mutex
+	memStatsCache  runtime.MemStats
+	memStatsReadAt time.Time
+)
+
+// readMemStats는 ReadMemStats(stop-the-world)가 스크랩마다 여러 번 호출되지 않도록 1초간 캐시합니다.
+func readMemStats() runtime.MemStats {
+	memStatsMu.Lock()
+	defer memStatsMu.Unlock()
+
+	if time.Since(memStatsReadAt) > time.Second {
+		runtime.ReadMemStats(&memStatsCache)
+		memStatsReadAt = time.Now()
+	}
+	return memStatsCache
+}
+
+// GetRuntimeStats는 현재 고루틴 수와 힙 사용량을 반환합니다.
+func GetRuntimeStats() RuntimeStats {
+	m := readMemStats()
+	return RuntimeStats{
+		Goroutines:     runtime.NumGoroutine(),
+		HeapAllocBytes: m.HeapAlloc,
+		HeapInuseBytes: m.HeapInuse,
+		HeapObjects:    m.HeapObjects,
+		SysBytes:       m.Sys,
+		NumGC:          m.NumGC,
+		LastGCPauseSec: time.Duration(m.PauseNs[(m.NumGC+255)%256]).Seconds(),
+	}
+}
+
+func init() {
+	NewGaugeFunc("go_goroutines", "Number of goroutines that currently exist.", func() float64 { return float64(runtime.NumGoroutine()) })
+	NewGaugeFunc("go_memstats_heap_alloc_bytes", "Number of heap bytes allocated and still in use.", func() float64 { return float64(readMemStats().HeapAlloc) })
+	NewGaugeFunc("go_memstats_heap_objects", "Number of allocated objects.", func() float64 { return float64(readMemStats().HeapObjects) })
+	NewGaugeFunc("go_memstats_sys_bytes", "Number of bytes obtained from system.", func() float64 { return float64(readMemStats().Sys) })
+	NewGaugeFunc("go_gc_count_total", "Number of completed GC cycles.", func() float64 { return float64(readMemStats().NumGC) })
+}
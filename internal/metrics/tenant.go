@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// 테넌트 라벨 값
+const (
+	TenantNone     = "none"     // 인증되지 않은 요청 / 시스템 작업
+	TenantOverflow = "overflow" // 카디널리티 상한을 넘은 테넌트
+)
+
+// 라벨로 허용하는 테넌트 수 기본값 (METRICS_TENANT_LIMIT)
+const defaultTenantLimit = 500
+
+var (
+	tenantMu      sync.Mutex
+	tenantAllowed = map[string]struct{}{}
+	tenantLimit   = -1
+)
+
+// TenantLabel은 사용자 ID를 메트릭/로그에 쓸 테넌트 라벨로 바꿉니다.
+// 학번 등 개인 식별 정보가 노출되지 않도록 TENANT_LABEL_SALT로 HMAC 해시한 앞 12자리만 사용합니다.
+// 라벨에 해당하는 사용자는 관리자 API(GET /api/admin/tenants/:tenant)로 역조회합니다.
+//
+// 카디널리티 제어: 처음 관측된 METRICS_TENANT_LIMIT개의 테넌트만 개별 라벨을 갖고,
+// 그 이후의 테넌트는 모두 "overflow"로 묶입니다. (프로세스 재시작 시 초기화)
+func TenantLabel(userId string) string {
+	if userId == "" || userId == "0" {
+		return TenantNone
+	}
+	if os.Getenv("TENANT_LABEL_MODE") == "off" {
+		return TenantNone
+	}
+
+	label := TenantHash(userId)
+
+	tenantMu.Lock()
+	defer tenantMu.Unlock()
+
+	if tenantLimit < 0 {
+		tenantLimit = defaultTenantLimit
+		if limit, err := strconv.Atoi(os.Getenv("METRICS_TENANT_LIMIT")); err == nil && limit >= 0 {
+			tenantLimit = limit
+		}
+	}
+
+	if _, ok := tenantAllowed[label]; ok {
+		return label
+	}
+	if len(tenantAllowed) >= tenantLimit {
+		tenantOverflowTotal.Inc()
+		return TenantOverflow
+	}
+
+	tenantAllowed[label] = struct{}{}
+	return label
+}
+
+// TenantHash는 카디널리티 제한을 적용하지 않은 테넌트 해시입니다. (관리자가 라벨로 사용자를 찾을 때 사용)
+func TenantHash(userId string) string {
+	mac := hmac.New(sha256.New, []byte(os.Getenv("TENANT_LABEL_SALT")))
+	mac.Write([]byte(userId))
+	return "t-" + hex.EncodeToString(mac.Sum(nil))[:12]
+}
+
+// TenantLabelFor는 숫자 사용자 ID용 TenantLabel입니다.
+func TenantLabelFor(userId uint) string {
+	return TenantLabel(strconv.FormatUint(uint64(userId), 10))
+}
+
+var tenantOverflowTotal = NewCounterVec("metrics_tenant_overflow_total", "Observations folded into the overflow tenant label because the tenant limit was reached.")
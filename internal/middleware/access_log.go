@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"log/slog"
+	"strconv"
+	"time"
+
+	"vm-controller/internal/metrics"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+// 라우트별 메트릭에는 테넌트 라벨을 붙이지 않고, 테넌트별 메트릭에는 라우트 라벨을 붙이지 않습니다.
+// (테넌트 x 라우트 x 상태 조합으로 시계열이 폭증하지 않도록)
+var (
+	httpRequestsTotal         = metrics.NewCounterVec("http_requests_total", "HTTP requests by route and status.", "method", "route", "status")
+	httpRequestSecondsTotal   = metrics.NewCounterVec("http_request_duration_seconds_total", "Total time spent handling HTTP requests by route.", "method", "route")
+	httpTenantRequestsTotal   = metrics.NewCounterVec("http_tenant_requests_total", "HTTP requests by tenant and status class.", "tenant", "status_class")
+	httpTenantRequestsSeconds = metrics.NewCounterVec("http_tenant_request_duration_seconds_total", "Total time spent handling HTTP requests by tenant.", "tenant")
+)
+
+// AccessLog는 요청마다 구조화 로그 한 줄과 HTTP 메트릭을 기록합니다.
+// 로그에는 request_id, 해시된 테넌트 라벨, trace_id(RequestID에서 설정)가 포함되며
+// 쿼리 문자열과 사용자 ID 같은 개인 식별 정보는 기록하지 않습니다.
+func AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		elapsed := time.Since(start)
+		status := c.Writer.Status()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched" // 404 경로를 그대로 라벨로 쓰지 않음
+		}
+
+		tenant := c.GetString("tenant")
+		if tenant == "" {
+			tenant = metrics.TenantNone
+		}
+
+		httpRequestsTotal.Inc(c.Request.Method, route, strconv.Itoa(status))
+		httpRequestSecondsTotal.Add(elapsed.Seconds(), c.Request.Method, route)
+		httpTenantRequestsTotal.Inc(tenant, strconv.Itoa(status/100)+"xx")
+		httpTenantRequestsSeconds.Add(elapsed.Seconds(), tenant)
+
+		attrs := []any{
+			"component", "http",
+			"method", c.Request.Method,
+			"route", route,
+			"status", status,
+			"elapsed_ms", elapsed.Milliseconds(),
+			"request_id", c.GetString("request_id"),
+			"tenant", tenant,
+		}
+		if traceID := c.GetString("trace_id"); traceID != "" {
+			attrs = append(attrs, "trace_id", traceID)
+		}
+
+		switch {
+		case status >= 500:
+			slog.Error("request", attrs...)
+		case status >= 400:
+			slog.Warn("request", attrs...)
+		default:
+			slog.Info("request", attrs...)
+		}
+	}
+}
@@ -1,16 +1,18 @@
 package middleware
 
 import (
+	"errors"
 	strings "strings"
 
 	gin "github.com/gin-gonic/gin"
 
-	http "net/http"
-
 	fmt "fmt"
 	"time"
 
 	jwt "github.com/golang-jwt/jwt/v5"
+
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/metrics"
 )
 
 func AuthGuard() gin.HandlerFunc {
@@ -19,76 +21,92 @@ func AuthGuard() gin.HandlerFunc {
 		tokenString, err := c.Cookie("authorization")
 
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "로그인 토큰이 없습니다."})
-			c.Abort() // 중요: 이후의 핸들러 함수 호출을 중단함 (Guard의 핵심)
+			AbortWithError(c, apperrors.New(apperrors.CodeUnauthenticated, "로그인 토큰이 없습니다.")) // 중요: 이후의 핸들러 함수 호출을 중단함 (Guard의 핵심)
 			return
 		}
 
 		// 2. "Bearer " 접두사 제거 및 토큰 검증 로직
 		// (예: jwt.Parse 등을 활용한 실제 검증)
 		if !strings.HasPrefix(tokenString, "Bearer ") {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "유효하지 않은 토큰 형식입니다."})
-			c.Abort()
+			AbortWithError(c, apperrors.New(apperrors.CodeUnauthenticated, "유효하지 않은 토큰 형식입니다."))
 			return
 		}
 
 		tokenString = tokenString[7:] // "Bearer " 접두사 제거
 		// 3. 검증 통과 시 사용자 정보를 Context에 저장 (Next 핸들러에서 사용 가능)
-
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return []byte("secret"), nil
-		})
-
+		userID, err := ParseToken(tokenString)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "유효하지 않은 토큰입니다."})
-			c.Abort()
+			AbortWithError(c, apperrors.From(err, apperrors.CodeUnauthenticated))
 			return
 		}
 
-		// 4. 토큰 클레임에서 사용자 식별 정보(user_id) 추출
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "토큰 클레임을 읽을 수 없습니다."})
-			c.Abort()
-			return
-		}
+		c.Set("user_id", userID)
 
-		exp, ok := claims["exp"]
-		if !ok {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "토큰에 만료 시간이 누락되었습니다."})
-			c.Abort()
+		// 정지된 계정은 토큰이 유효해도 거부 (조회한 권한은 RequestSubject에서 재사용)
+		if _, err := RequestSubject(c); err != nil {
+			if errors.Is(err, ErrUserSuspended) {
+				AbortWithError(c, ErrUserSuspended)
+			} else {
+				AbortWithError(c, apperrors.New(apperrors.CodeUnauthenticated, "사용자를 찾을 수 없습니다."))
+			}
 			return
 		}
 
-		if time.Now().Unix() > int64(exp.(float64)) {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "토큰이 만료되었습니다."})
-			c.Abort()
-			return
-		}
+		// 메트릭/로그에는 사용자 ID 대신 해시된 테넌트 라벨만 기록
+		c.Set("tenant", metrics.TenantLabel(userID))
+		c.Next()
+	}
+}
 
-		userIDRaw, ok := claims["user_id"]
+var (
+	ErrInvalidToken       = errors.New("유효하지 않은 토큰입니다.")
+	ErrInvalidClaims      = errors.New("토큰 클레임을 읽을 수 없습니다.")
+	ErrMissingExpiration  = errors.New("토큰에 만료 시간이 누락되었습니다.")
+	ErrTokenExpired       = errors.New("토큰이 만료되었습니다.")
+	ErrMissingTokenUserID = errors.New("토큰에 사용자 정보가 누락되었습니다.")
+)
 
-		if !ok {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "토큰에 사용자 정보가 누락되었습니다."})
-			c.Abort()
-			return
+// ParseToken은 로그인 토큰("Bearer " 접두사 제외)을 검증하고 사용자 ID를 반환합니다.
+// 쿠키를 쓰지 않는 gRPC 서버도 같은 규칙으로 토큰을 검증하도록 AuthGuard와 분리되어 있습니다.
+func ParseToken(tokenString string) (string, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
+		return []byte("secret"), nil
+	})
 
-		var userID string
+	if err != nil {
+		return "", ErrInvalidToken
+	}
 
-		switch v := userIDRaw.(type) {
-		case string:
-			userID = v
-		case float64:
-			userID = fmt.Sprintf("%.0f", v)
-		default:
-			userID = fmt.Sprintf("%v", v)
-		}
+	// 토큰 클레임에서 사용자 식별 정보(user_id) 추출
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return "", ErrInvalidClaims
+	}
 
-		c.Set("user_id", userID)
-		c.Next()
+	exp, ok := claims["exp"]
+	if !ok {
+		return "", ErrMissingExpiration
+	}
+
+	if time.Now().Unix() > int64(exp.(float64)) {
+		return "", ErrTokenExpired
+	}
+
+	userIDRaw, ok := claims["user_id"]
+
+	if !ok {
+		return "", ErrMissingTokenUserID
+	}
+
+	switch v := userIDRaw.(type) {
+	case string:
+		return v, nil
+	case float64:
+		return fmt.Sprintf("%.0f", v), nil
+	default:
+		return fmt.Sprintf("%v", v), nil
 	}
 }
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/metrics"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+var backpressureRejectionsTotal = metrics.NewCounterVec("http_backpressure_rejections_total", "Requests rejected with 429 because the background job queue is overloaded.", "route", "reason")
+
+// BackpressurePolicy는 작업 큐가 과부하일 때 거부할 라우트와 과부하 판단 함수입니다.
+// Routes에는 "METHOD /full/path" 로 백그라운드 작업을 만드는 비싼 요청만 지정합니다.
+type BackpressurePolicy struct {
+	Routes map[string]bool
+	// Check는 과부하 여부, 사유, 재시도까지 기다릴 시간을 반환합니다.
+	Check func() (overloaded bool, reason string, retryAfter time.Duration)
+}
+
+// Backpressure는 과부하 상태에서 지정된 라우트의 요청을 큐에 쌓지 않고 429 + Retry-After로 거부합니다.
+// 조회 요청과 정지/삭제처럼 부하를 줄이는 요청은 계속 처리됩니다.
+func Backpressure(policy BackpressurePolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.Request.Method + " " + c.FullPath()
+		if !policy.Routes[route] {
+			c.Next()
+			return
+		}
+
+		overloaded, reason, retryAfter := policy.Check()
+		if !overloaded {
+			c.Next()
+			return
+		}
+
+		AbortServerBusy(c, reason, retryAfter)
+	}
+}
+
+// AbortServerBusy는 작업 큐 과부하로 요청을 429 + Retry-After로 거부합니다.
+// 서비스 계층이 과부하로 거부한 요청(vmlifecycleservice.KindBusy)도 같은 응답과 메트릭을 쓰도록 공개합니다.
+func AbortServerBusy(c *gin.Context, reason string, retryAfter time.Duration) {
+	backpressureRejectionsTotal.Inc(c.Request.Method+" "+c.FullPath(), reason)
+
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	AbortWithError(c, apperrors.New(apperrors.CodeServerBusy, "Server is busy, please retry later").
+		WithDetail("reason", reason).
+		WithDetail("retry_after", seconds))
+}
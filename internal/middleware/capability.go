@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"vm-controller/internal/apperrors"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+// RequireCapability는 클러스터에 필요한 구성 요소가 없으면 501로 요청을 거부합니다.
+// FeatureGuard(404)와 달리 라우트는 존재하지만 이 클러스터에서 제공할 수 없다는 것을 사유와 함께 알려 줍니다.
+// check는 사용 가능 여부와, 불가능할 때 응답에 포함할 사유를 반환합니다.
+func RequireCapability(capability string, check func() (available bool, reason string)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		available, reason := check()
+		if !available {
+			AbortWithError(c, apperrors.New(apperrors.CodeNotImplemented, reason).
+				WithDetail("capability", capability))
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+// 브라우저 요청에서 허용할 헤더 (쿠키 인증 외에 API가 읽는 요청 헤더)
+var corsAllowedHeaders = []string{"Content-Type", "Authorization", idempotencyKeyHeader, RequestIDHeader, "Range", "Content-Range"}
+
+// 브라우저 스크립트가 읽을 수 있는 응답 헤더 (요청 ID, 재시도 대기, 폐기 예정 안내)
+var corsExposedHeaders = []string{RequestIDHeader, "Retry-After", "Deprecation", "Sunset", "Link", "Content-Disposition"}
+
+// CORSPolicy는 다른 출처에서 제공되는 프론트엔드의 API 요청 허용 설정입니다.
+type CORSPolicy struct {
+	AllowedOrigins   []string // "https://app.example.com", "https://*.example.com" 또는 "*"
+	AllowedMethods   []string
+	AllowCredentials bool // 인증 쿠키를 포함한 요청 허용 ("*"와 하위 도메인 패턴은 무시됨)
+	MaxAge           time.Duration
+}
+
+// wildcardOrigin은 "*" 또는 "https://*.example.com" 처럼 여러 출처를 허용하는 패턴인지 확인합니다.
+func wildcardOrigin(allowed string) bool {
+	return allowed == "*" || strings.Contains(allowed, "://*.")
+}
+
+// withoutCredentialWildcards는 쿠키 포함 요청을 허용할 때 와일드카드 패턴을 허용 목록에서 뺀 정책입니다.
+// 하위 도메인 전체를 허용하면 그중 하나(사용자 페이지, 오래된 서비스 등)만 탈취돼도 그 페이지가 사용자의 인증 쿠키로
+// API를 호출할 수 있으므로, 자격 증명을 허용할 때는 출처를 하나씩 나열해야 합니다.
+func (p CORSPolicy) withoutCredentialWildcards() CORSPolicy {
+	if !p.AllowCredentials {
+		return p
+	}
+
+	origins := make([]string, 0, len(p.AllowedOrigins))
+	for _, allowed := range p.AllowedOrigins {
+		if wildcardOrigin(allowed) {
+			slog.Warn("CORS: ignoring wildcard origin because credentials are allowed; list each trusted origin explicitly",
+				"origin", allowed)
+			continue
+		}
+		origins = append(origins, allowed)
+	}
+	p.AllowedOrigins = origins
+	return p
+}
+
+// allows는 origin이 허용 목록에 있는지 확인합니다.
+// "https://*.example.com" 은 example.com의 하위 도메인만 허용하며 example.com 자체는 포함하지 않습니다.
+func (p CORSPolicy) allows(origin string) bool {
+	for _, allowed := range p.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+		scheme, domain, ok := strings.Cut(strings.ToLower(allowed), "://*.")
+		if ok && strings.HasPrefix(strings.ToLower(origin), scheme+"://") && strings.HasSuffix(strings.ToLower(origin), "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// CORS는 허용된 Origin의 요청에 CORS 응답 헤더를 붙이고 preflight(OPTIONS) 요청에 바로 응답합니다.
+// 허용 목록이 비어 있으면 아무 헤더도 보내지 않으므로 같은 출처에서만 API를 쓸 수 있습니다. (기존 동작)
+// 허용되지 않은 Origin은 헤더 없이 그대로 처리되어 브라우저가 응답을 차단합니다.
+// AllowCredentials가 켜져 있으면 와일드카드 출처는 시작 시 경고를 남기고 무시합니다.
+func CORS(policy CORSPolicy) gin.HandlerFunc {
+	configured := len(policy.AllowedOrigins) > 0
+	policy = policy.withoutCredentialWildcards()
+	methods := []string{}
+	for _, method := range policy.AllowedMethods {
+		if method != http.MethodOptions {
+			methods = append(methods, method)
+		}
+	}
+	allowMethods := strings.Join(append(methods, http.MethodOptions), ", ")
+	allowHeaders := strings.Join(corsAllowedHeaders, ", ")
+	exposeHeaders := strings.Join(corsExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(policy.MaxAge.Seconds()))
+	wildcard := !policy.AllowCredentials && len(policy.AllowedOrigins) == 1 && policy.AllowedOrigins[0] == "*"
+
+	return func(c *gin.Context) {
+		if !configured {
+			c.Next()
+			return
+		}
+
+		// 응답이 Origin에 따라 달라지므로 캐시가 출처별로 구분하도록 함
+		c.Writer.Header().Add("Vary", "Origin")
+
+		origin := c.GetHeader("Origin")
+		if origin == "" || !policy.allows(origin) {
+			c.Next()
+			return
+		}
+
+		if wildcard {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			c.Header("Access-Control-Allow-Origin", origin)
+		}
+		if policy.AllowCredentials {
+			c.Header("Access-Control-Allow-Credentials", "true")
+		}
+
+		// preflight: 실제 요청 전에 브라우저가 보내는 OPTIONS 요청은 라우트까지 가지 않고 바로 응답
+		if c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != "" {
+			c.Writer.Header().Add("Vary", "Access-Control-Request-Method")
+			c.Writer.Header().Add("Vary", "Access-Control-Request-Headers")
+			c.Header("Access-Control-Allow-Methods", allowMethods)
+			c.Header("Access-Control-Allow-Headers", allowHeaders)
+			if policy.MaxAge > 0 {
+				c.Header("Access-Control-Max-Age", maxAge)
+			}
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		c.Header("Access-Control-Expose-Headers", exposeHeaders)
+		c.Next()
+	}
+}
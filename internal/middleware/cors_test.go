@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+func corsRequest(policy CORSPolicy, origin string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(CORS(policy))
+	r.GET("/me", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Origin", origin)
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORSOrigins(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      CORSPolicy
+		origin      string
+		wantAllowed bool
+	}{
+		{
+			name:        "exact origin with credentials",
+			policy:      CORSPolicy{AllowedOrigins: []string{"https://console.example.com"}, AllowCredentials: true},
+			origin:      "https://console.example.com",
+			wantAllowed: true,
+		},
+		{
+			name:        "wildcard subdomain without credentials",
+			policy:      CORSPolicy{AllowedOrigins: []string{"https://*.example.com"}},
+			origin:      "https://console.example.com",
+			wantAllowed: true,
+		},
+		{
+			name:        "wildcard does not cover the apex domain",
+			policy:      CORSPolicy{AllowedOrigins: []string{"https://*.example.com"}},
+			origin:      "https://example.com",
+			wantAllowed: false,
+		},
+		{
+			// 하위 도메인 하나가 탈취되면 사용자의 쿠키로 API를 호출할 수 있으므로 무시
+			name:        "wildcard subdomain ignored with credentials",
+			policy:      CORSPolicy{AllowedOrigins: []string{"https://*.example.com"}, AllowCredentials: true},
+			origin:      "https://user-page.example.com",
+			wantAllowed: false,
+		},
+		{
+			name:        "explicit origins still allowed next to an ignored wildcard",
+			policy:      CORSPolicy{AllowedOrigins: []string{"https://*.example.com", "https://console.example.com"}, AllowCredentials: true},
+			origin:      "https://console.example.com",
+			wantAllowed: true,
+		},
+		{
+			name:        "scheme must match",
+			policy:      CORSPolicy{AllowedOrigins: []string{"https://*.example.com"}},
+			origin:      "http://console.example.com",
+			wantAllowed: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := corsRequest(tt.policy, tt.origin)
+			got := w.Header().Get("Access-Control-Allow-Origin")
+			if tt.wantAllowed && got != tt.origin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.origin)
+			}
+			if !tt.wantAllowed && got != "" {
+				t.Errorf("Access-Control-Allow-Origin = %q, want none", got)
+			}
+			if !tt.wantAllowed && w.Header().Get("Access-Control-Allow-Credentials") != "" {
+				t.Errorf("credentials allowed for a rejected origin")
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"vm-controller/internal/metrics"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+var deprecatedRequestsTotal = metrics.NewCounterVec("http_deprecated_requests_total", "Requests served through deprecated unversioned API routes.", "route")
+
+// Deprecated는 버전 없는 경로(legacyPrefix)로 들어온 요청에 폐기 예정 헤더를 붙입니다.
+// Link 헤더로 같은 요청의 후속 경로(successorPrefix)를 알려 주고, sunset이 있으면 제거 예정일을 함께 보냅니다.
+// 응답 본문과 동작은 바꾸지 않으므로 기존 클라이언트는 그대로 동작합니다.
+func Deprecated(legacyPrefix, successorPrefix string, sunset time.Time) gin.HandlerFunc {
+	sunsetValue := ""
+	if !sunset.IsZero() {
+		sunsetValue = sunset.UTC().Format(http.TimeFormat)
+	}
+
+	return func(c *gin.Context) {
+		successor := successorPrefix + strings.TrimPrefix(c.Request.URL.Path, legacyPrefix)
+
+		c.Header("Deprecation", "true")
+		c.Header("Link", "<"+successor+`>; rel="successor-version"`)
+		if sunsetValue != "" {
+			c.Header("Sunset", sunsetValue)
+		}
+		deprecatedRequestsTotal.Inc(c.Request.Method + " " + c.FullPath())
+
+		c.Next()
+	}
+}
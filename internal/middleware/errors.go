@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/logger"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+// AbortWithError는 err를 {code, message, details} 형태로 응답하고 이후 핸들러를 중단합니다.
+// apperrors.Error가 아닌 에러는 내부 구현이 노출되지 않도록 메시지 없이 INTERNAL로 응답하고 원인은 로그로 남깁니다.
+// details에는 요청 ID(request_id)를 담아 사용자가 전달한 에러 응답으로 서버 로그를 찾을 수 있게 합니다.
+func AbortWithError(c *gin.Context, err error) {
+	var appErr *apperrors.Error
+	if errors.As(err, &appErr) {
+		appErr = apperrors.From(err, apperrors.CodeInternal)
+		if appErr.Err != nil && appErr.Status() >= http.StatusInternalServerError {
+			logger.FromContext(c.Request.Context()).Error("request failed", "component", "http", "method", c.Request.Method, "path", c.Request.URL.Path, "code", appErr.Code, "error", appErr.Error())
+		}
+	} else {
+		logger.FromContext(c.Request.Context()).Error("request failed", "component", "http", "method", c.Request.Method, "path", c.Request.URL.Path, "error", err.Error())
+		appErr = apperrors.New(apperrors.CodeInternal, "Internal server error").Wrap(err)
+	}
+
+	if requestID := c.GetString("request_id"); requestID != "" {
+		appErr = appErr.WithDetail("request_id", requestID)
+	}
+	c.AbortWithStatusJSON(appErr.Status(), appErr.Response())
+}
+
+// ErrorHandler는 핸들러가 응답을 쓰지 않고 c.Error로만 남긴 에러를 공통 형식으로 응답합니다.
+// 대부분의 핸들러는 AbortWithError로 직접 응답하며, 이 미들웨어는 누락된 경우를 위한 안전망입니다.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if c.Writer.Written() || len(c.Errors) == 0 {
+			return
+		}
+		AbortWithError(c, c.Errors.Last().Err)
+	}
+}
+
+// Recovery는 핸들러 패닉을 복구하고 공통 형식의 500 응답을 보냅니다.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
+		AbortWithError(c, fmt.Errorf("panic: %v", recovered))
+	})
+}
+
+// NoRoute는 등록되지 않은 경로에 대한 응답입니다. (gin 기본 응답은 텍스트 본문)
+func NoRoute() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		AbortWithError(c, apperrors.New(apperrors.CodeNotFound, "Not found"))
+	}
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/config"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+// FeatureGuard는 기능 플래그가 꺼져 있으면 라우트가 없는 것처럼 404를 반환합니다.
+// 라우트는 항상 등록해 두고 요청마다 플래그를 확인하므로, 플래그를 바꿔도 라우터를 다시 만들 필요가 없습니다.
+func FeatureGuard(feature string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !config.FeatureEnabled(feature) {
+			AbortWithError(c, apperrors.New(apperrors.CodeNotFound, "Not found"))
+			return
+		}
+		c.Next()
+	}
+}
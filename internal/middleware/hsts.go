@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"fmt"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+// HSTS는 HTTPS로 들어온 요청에 Strict-Transport-Security 헤더를 추가합니다.
+// 서버가 직접 TLS를 종료한 경우와 프록시가 X-Forwarded-Proto: https를 넘긴 경우 모두 해당됩니다.
+func HSTS(maxAge int) gin.HandlerFunc {
+	value := fmt.Sprintf("max-age=%d; includeSubDomains", maxAge)
+
+	return func(c *gin.Context) {
+		if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+			c.Header("Strict-Transport-Security", value)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,244 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	http "net/http"
+	"os"
+	"time"
+
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/db"
+	"vm-controller/internal/logger"
+	"vm-controller/internal/models"
+
+	gin "github.com/gin-gonic/gin"
+	cast "github.com/spf13/cast"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// 저장된 응답을 돌려줄 때 붙이는 헤더
+const idempotentReplayedHeader = "Idempotent-Replayed"
+
+// Idempotency-Key 최대 길이
+const maxIdempotencyKeyLength = 255
+
+// Idempotency-Key가 붙은 요청 본문 최대 크기. 본문 전체를 해시하므로 디스크 이미지 같은 대용량 요청은 받지 않음
+const maxIdempotentBodyBytes = 1 << 20 // 1 MiB
+
+// 키 기본 보존 기간 (IDEMPOTENCY_KEY_TTL). 이후에는 같은 키도 새 요청으로 처리
+const defaultIdempotencyKeyTTL = 24 * time.Hour
+
+// IdempotencyKeyTTL은 Idempotency-Key와 응답을 보관하는 기간을 반환합니다.
+func IdempotencyKeyTTL() time.Duration {
+	if ttl, err := time.ParseDuration(os.Getenv("IDEMPOTENCY_KEY_TTL")); err == nil && ttl > 0 {
+		return ttl
+	}
+	return defaultIdempotencyKeyTTL
+}
+
+// Idempotency는 AuthGuard 이후에 사용되며, Idempotency-Key 헤더가 붙은 변경 요청을 한 번만 처리합니다.
+//
+//   - 처음 보는 키: 요청을 처리하고 응답을 저장합니다. (5xx 응답은 저장하지 않아 같은 키로 재시도 가능)
+//   - 처리가 끝난 키: 핸들러를 실행하지 않고 저장된 응답을 Idempotent-Replayed: true 와 함께 돌려줍니다.
+//   - 처리 중인 키: 409를 반환합니다.
+//   - 다른 요청(메서드/경로/본문)에 재사용한 키: 422를 반환합니다.
+//   - 본문이 1 MiB를 넘는 요청: 본문을 메모리에 올리지 않고 413을 반환합니다. (업로드 청크처럼 큰 요청은 이 미들웨어 밖에 등록)
+//
+// 헤더가 없는 요청과 조회(GET/HEAD/OPTIONS) 요청은 그대로 처리합니다.
+func Idempotency() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotencyKeyHeader)
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			AbortWithError(c, apperrors.Newf(apperrors.CodeInvalidRequest, "Idempotency-Key must be at most %d characters", maxIdempotencyKeyLength))
+			return
+		}
+
+		userID, err := cast.ToUintE(c.GetString("user_id"))
+		if err != nil {
+			AbortWithError(c, apperrors.New(apperrors.CodeUnauthenticated, "로그인이 필요합니다."))
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxIdempotentBodyBytes))
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			AbortWithError(c, apperrors.Newf(apperrors.CodePayloadTooLarge, "Requests with an Idempotency-Key must have a body of at most %d bytes", maxIdempotentBodyBytes))
+			return
+		}
+		if err != nil {
+			AbortWithError(c, apperrors.New(apperrors.CodeInvalidRequest, "Invalid request"))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := sha256.New()
+		hash.Write([]byte(c.Request.Method + " " + c.Request.URL.RequestURI() + "\n"))
+		hash.Write(body)
+
+		record := models.IdempotencyKey{
+			UserID:      userID,
+			Key:         key,
+			Method:      c.Request.Method,
+			Path:        c.Request.URL.Path,
+			RequestHash: hex.EncodeToString(hash.Sum(nil)),
+		}
+
+		existing, err := claimIdempotencyKey(&record)
+		if err != nil {
+			logger.FromContext(c.Request.Context()).Error("idempotency: failed to claim key", "user_id", userID, "error", err)
+			AbortWithError(c, apperrors.New(apperrors.CodeInternal, "Failed to process Idempotency-Key"))
+			return
+		}
+
+		if existing != nil {
+			switch {
+			case existing.RequestHash != record.RequestHash:
+				AbortWithError(c, apperrors.New(apperrors.CodeIdempotencyMismatch, "Idempotency-Key was already used for a different request"))
+			case existing.CompletedAt == nil:
+				AbortWithError(c, apperrors.New(apperrors.CodeIdempotencyPending, "A request with this Idempotency-Key is still being processed"))
+			default:
+				c.Header(idempotentReplayedHeader, "true")
+				c.Data(existing.StatusCode, existing.ContentType, []byte(existing.ResponseBody))
+				c.Abort()
+			}
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		defer func() {
+			c.Writer = recorder.ResponseWriter
+			// 핸들러 패닉 시 키가 처리 중으로 남지 않도록 해제 (Recovery가 500 응답)
+			if r := recover(); r != nil {
+				releaseIdempotencyKey(c.Request.Context(), record.ID)
+				panic(r)
+			}
+		}()
+
+		c.Next()
+
+		completeIdempotencyKey(c.Request.Context(), &record, recorder)
+	}
+}
+
+// claimIdempotencyKey는 키를 처리 중으로 등록합니다. 이미 (보존 기간 안에) 등록된 키면 그 기록을 반환합니다.
+func claimIdempotencyKey(record *models.IdempotencyKey) (*models.IdempotencyKey, error) {
+	conn := db.GetDB()
+
+	for attempt := 0; attempt < 2; attempt++ {
+		result := conn.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected > 0 {
+			return nil, nil
+		}
+
+		var existing models.IdempotencyKey
+		err := conn.Where("user_id = ? AND key = ?", record.UserID, record.Key).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue // 그 사이 삭제됨 (5xx 응답) - 다시 등록
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if time.Since(existing.CreatedAt) < IdempotencyKeyTTL() {
+			return &existing, nil
+		}
+
+		// 보존 기간이 지난 키는 새 요청으로 처리
+		if err := conn.Delete(&models.IdempotencyKey{}, existing.ID).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, fmt.Errorf("idempotency key %q is contended", record.Key)
+}
+
+// completeIdempotencyKey는 응답을 저장합니다. 서버 오류(5xx)면 키를 지워 클라이언트가 재시도할 수 있게 합니다.
+func completeIdempotencyKey(ctx context.Context, record *models.IdempotencyKey, recorder *responseRecorder) {
+	conn := db.GetDB()
+
+	status := recorder.Status()
+	if status >= http.StatusInternalServerError {
+		releaseIdempotencyKey(ctx, record.ID)
+		return
+	}
+
+	now := time.Now()
+	err := conn.Model(&models.IdempotencyKey{ID: record.ID}).
+		Select("status_code", "content_type", "response_body", "completed_at").
+		Updates(&models.IdempotencyKey{
+			StatusCode:   status,
+			ContentType:  recorder.Header().Get("Content-Type"),
+			ResponseBody: recorder.body.String(),
+			CompletedAt:  &now,
+		}).Error
+	if err != nil {
+		logger.FromContext(ctx).Error("idempotency: failed to store response", "key_id", record.ID, "error", err)
+	}
+}
+
+func releaseIdempotencyKey(ctx context.Context, id uint) {
+	if err := db.GetDB().Delete(&models.IdempotencyKey{}, id).Error; err != nil {
+		logger.FromContext(ctx).Error("idempotency: failed to release key", "key_id", id, "error", err)
+	}
+}
+
+// PurgeExpiredIdempotencyKeys는 보존 기간이 지난 키를 삭제합니다.
+func PurgeExpiredIdempotencyKeys() (int64, error) {
+	result := db.GetDB().Where("created_at < ?", time.Now().Add(-IdempotencyKeyTTL())).Delete(&models.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
+
+// StartIdempotencyKeyCleanup은 주기적으로 보존 기간이 지난 키를 삭제합니다.
+func StartIdempotencyKeyCleanup(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if purged, err := PurgeExpiredIdempotencyKeys(); err != nil {
+				slog.Warn("idempotency: failed to purge expired keys", "error", err)
+			} else if purged > 0 {
+				slog.Info("idempotency: purged expired keys", "count", purged)
+			}
+		}
+	}()
+}
+
+// responseRecorder는 응답을 그대로 전송하면서 본문을 복사해 둡니다.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
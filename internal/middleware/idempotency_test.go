@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+// Idempotency-Key가 붙은 큰 본문은 메모리에 올려 해시하기 전에 413으로 거부
+func TestIdempotencyRejectsOversizedBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handled := false
+	r := gin.New()
+	r.PUT("/upload", func(c *gin.Context) { c.Set("user_id", "1") }, Idempotency(), func(c *gin.Context) {
+		handled = true
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/upload", bytes.NewReader(make([]byte, maxIdempotentBodyBytes+1)))
+	req.Header.Set(idempotencyKeyHeader, "retry-1")
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status = %d, want 413 (%s)", w.Code, w.Body.String())
+	}
+	if handled {
+		t.Fatal("handler must not run for an oversized body")
+	}
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"errors"
+	http "net/http"
+
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/db"
+	"vm-controller/internal/models"
+	"vm-controller/internal/policy"
+
+	gin "github.com/gin-gonic/gin"
+	cast "github.com/spf13/cast"
+)
+
+var errNoSubject = errors.New("no authenticated user")
+
+// ErrUserSuspended는 관리자가 정지한 계정의 요청입니다.
+var ErrUserSuspended = apperrors.New(apperrors.CodeUserSuspended, "정지된 계정입니다.")
+
+// requestAction은 HTTP 메서드로 동작을 결정합니다. (조회 메서드만 read)
+func requestAction(c *gin.Context) policy.Action {
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return policy.ActionRead
+	}
+	return policy.ActionWrite
+}
+
+// RequestSubject는 AuthGuard가 저장한 사용자와 DB의 현재 권한으로 Subject를 만듭니다.
+// 권한은 토큰이 아닌 DB에서 조회하므로 권한 회수가 즉시 반영되며, 한 요청에서는 한 번만 조회합니다.
+func RequestSubject(c *gin.Context) (policy.Subject, error) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		return policy.Subject{}, errNoSubject
+	}
+	u64, err := cast.ToUintE(userID)
+	if err != nil {
+		return policy.Subject{}, err
+	}
+
+	if role, ok := c.Get("role"); ok {
+		return policy.Subject{UserID: u64, Role: role.(string)}, nil
+	}
+
+	subject, err := UserSubject(u64)
+	if err != nil {
+		return policy.Subject{}, err
+	}
+	c.Set("role", subject.Role)
+
+	return subject, nil
+}
+
+// UserSubject는 사용자의 DB상 현재 권한으로 Subject를 만듭니다. (gin 요청이 아닌 gRPC 호출 등에서 사용)
+// 정지된 계정이면 ErrUserSuspended를 반환합니다.
+func UserSubject(userID uint) (policy.Subject, error) {
+	var user models.User
+	if err := db.GetDB().Select("id", "role", "suspended_at").Where("id = ?", userID).First(&user).Error; err != nil {
+		return policy.Subject{}, err
+	}
+	if user.SuspendedAt != nil {
+		return policy.Subject{}, ErrUserSuspended
+	}
+
+	return policy.Subject{UserID: userID, Role: user.Role}, nil
+}
+
+// Authorize는 AuthGuard 이후에 사용되며, 소유자가 없는 플랫폼 리소스(관리자 API 등)에 대한 요청을 정책 엔진으로 검사합니다.
+// 조회 메서드는 read, 그 외는 write 동작으로 판단합니다.
+func Authorize(resourceType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		subject, err := RequestSubject(c)
+		if errors.Is(err, errNoSubject) {
+			AbortWithError(c, apperrors.New(apperrors.CodeUnauthenticated, "로그인이 필요합니다."))
+			return
+		}
+		if err != nil {
+			AbortWithError(c, apperrors.New(apperrors.CodeUnauthenticated, "사용자를 찾을 수 없습니다."))
+			return
+		}
+
+		resource := policy.Resource{Type: resourceType}
+		action := requestAction(c)
+		if err := policy.Authorize(subject, action, resource); err != nil {
+			// 조회는 허용되는 권한(auditor)의 변경 요청
+			if action == policy.ActionWrite && policy.Authorize(subject, policy.ActionRead, resource) == nil {
+				AbortWithError(c, apperrors.New(apperrors.CodeReadOnlyAccount, "읽기 전용 계정은 변경 요청을 할 수 없습니다."))
+			} else {
+				AbortWithError(c, apperrors.New(apperrors.CodeForbidden, "권한이 없습니다."))
+			}
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// AuthorizeOwned는 핸들러가 조회한 소유 리소스(VM, 배포 등)에 요청자가 이 요청의 동작을 할 수 있는지 정책 엔진으로 판단합니다.
+// 응답은 작성하지 않으므로 거부 시 호출자가 기존처럼 404 등으로 응답합니다. (다른 사용자의 리소스는 존재 여부도 알리지 않음)
+func AuthorizeOwned(c *gin.Context, resourceType string, ownerID uint) bool {
+	subject, err := RequestSubject(c)
+	if err != nil {
+		return false
+	}
+
+	return policy.Authorize(subject, requestAction(c), policy.Resource{Type: resourceType, OwnerID: &ownerID}) == nil
+}
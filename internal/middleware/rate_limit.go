@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/config"
+	"vm-controller/internal/metrics"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+var rateLimitRejectionsTotal = metrics.NewCounterVec("http_rate_limit_rejections_total", "Requests rejected with 429 because the client exceeded its rate limit.", "rule")
+
+// RateLimitRule은 이름 붙은 요청 제한입니다. 같은 이름의 규칙은 버킷을 공유합니다. (/api와 /api/v1 경로가 같은 한도를 쓰도록)
+type RateLimitRule struct {
+	Name  string
+	Limit config.RateLimit
+}
+
+// RateLimitPolicy는 API 요청 제한 설정입니다.
+// 모든 요청은 Default 버킷을 쓰고, Routes("METHOD /full/path")에 지정된 라우트는 Default 버킷을 통과한 뒤 해당 버킷도 씁니다.
+type RateLimitPolicy struct {
+	Default RateLimitRule
+	Routes  map[string]RateLimitRule
+	Exempt  map[string]bool // 제한하지 않는 라우트 (자체 제한이 있는 인터셉터 등)
+	// Take는 key의 버킷에서 토큰 하나를 꺼냅니다. 허용되지 않으면 다시 시도할 수 있을 때까지의 시간을 반환합니다.
+	Take func(ctx context.Context, key string, limit int, per time.Duration) (retryAfter time.Duration, allowed bool)
+}
+
+// Allow는 subject(요청자 키)의 route 요청이 한도 안인지 확인하고 토큰을 꺼냅니다.
+// 거부되면 거부한 규칙과 다시 시도할 수 있을 때까지의 시간을 반환합니다. gRPC 인터셉터도 같은 버킷을 쓰도록 공개합니다.
+func (policy RateLimitPolicy) Allow(ctx context.Context, subject, route string) (rejected RateLimitRule, retryAfter time.Duration, allowed bool) {
+	if policy.Exempt[route] {
+		return RateLimitRule{}, 0, true
+	}
+
+	// 기본 버킷을 먼저 확인 (기본 한도로 거부되는 요청이 더 엄격한 로그인/VM 생성 한도를 소모하지 않도록)
+	rules := []RateLimitRule{policy.Default}
+	if rule, ok := policy.Routes[route]; ok {
+		rules = append(rules, rule)
+	}
+
+	for _, rule := range rules {
+		if rule.Limit.Requests <= 0 {
+			continue
+		}
+		retryAfter, allowed := policy.Take(ctx, rule.Name+":"+subject, rule.Limit.Requests, rule.Limit.Per)
+		if !allowed {
+			rateLimitRejectionsTotal.Inc(rule.Name)
+			return rule, retryAfter, false
+		}
+	}
+	return RateLimitRule{}, 0, true
+}
+
+// RateLimit은 로그인 사용자는 사용자별로, 비로그인 요청은 IP별로 요청 수를 제한하고 초과하면 429 + Retry-After로 거부합니다.
+// 인증은 라우트 그룹에서 처리되므로 여기서는 토큰이 유효한지만 확인하여 버킷을 고릅니다. (거부는 AuthGuard가 담당)
+func RateLimit(policy RateLimitPolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.Request.Method + " " + c.FullPath()
+		// 제외 라우트는 요청자 키를 만들지 않음 (방문자 트래픽마다 토큰을 검사하지 않도록)
+		if policy.Exempt[route] {
+			c.Next()
+			return
+		}
+
+		rule, retryAfter, allowed := policy.Allow(c.Request.Context(), rateLimitSubject(c), route)
+		if !allowed {
+			seconds := int(math.Ceil(retryAfter.Seconds()))
+			c.Header("Retry-After", strconv.Itoa(seconds))
+			AbortWithError(c, apperrors.New(apperrors.CodeRateLimited, "Too many requests, please retry later").
+				WithDetail("rule", rule.Name).
+				WithDetail("retry_after", seconds))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// rateLimitSubject는 요청자를 구분하는 키입니다. 유효한 로그인 토큰이 있으면 사용자 ID, 없으면 클라이언트 IP를 씁니다.
+func rateLimitSubject(c *gin.Context) string {
+	if tokenString, err := c.Cookie("authorization"); err == nil && strings.HasPrefix(tokenString, "Bearer ") {
+		if userID, err := ParseToken(tokenString[7:]); err == nil {
+			return "user:" + userID
+		}
+	}
+	return "ip:" + c.ClientIP()
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"vm-controller/internal/config"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+// countingBuckets는 키별로 남은 토큰 수만 세는 Take 구현입니다. (충전 없음)
+type countingBuckets struct {
+	remaining map[string]int
+}
+
+func (b *countingBuckets) take(ctx context.Context, key string, limit int, per time.Duration) (time.Duration, bool) {
+	left, ok := b.remaining[key]
+	if !ok {
+		left = limit
+	}
+	if left <= 0 {
+		return per, false
+	}
+	b.remaining[key] = left - 1
+	return 0, true
+}
+
+func newRateLimitRouter(buckets *countingBuckets, apiLimit, loginLimit int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(RateLimit(RateLimitPolicy{
+		Default: RateLimitRule{Name: "api", Limit: config.RateLimit{Requests: apiLimit, Per: time.Minute}},
+		Routes: map[string]RateLimitRule{
+			"POST /login": {Name: "login", Limit: config.RateLimit{Requests: loginLimit, Per: time.Minute}},
+		},
+		Take: buckets.take,
+	}))
+	r.POST("/login", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/other", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func serve(r *gin.Engine, method, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// 기본 한도로 거부된 요청은 라우트별(로그인) 토큰을 소모하지 않아야 함
+func TestRateLimitDefaultRejectionKeepsRouteTokens(t *testing.T) {
+	buckets := &countingBuckets{remaining: map[string]int{}}
+	r := newRateLimitRouter(buckets, 2, 5)
+
+	// 다른 라우트로 기본 버킷을 모두 사용
+	for i := 0; i < 2; i++ {
+		if w := serve(r, http.MethodGet, "/other"); w.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want 200", i, w.Code)
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		w := serve(r, http.MethodPost, "/login")
+		if w.Code != http.StatusTooManyRequests {
+			t.Fatalf("login %d: status = %d, want 429", i, w.Code)
+		}
+		if w.Header().Get("Retry-After") == "" {
+			t.Errorf("login %d: missing Retry-After", i)
+		}
+	}
+
+	if left, ok := buckets.remaining["login:ip:192.0.2.1"]; ok && left != 5 {
+		t.Errorf("login tokens left = %d, want 5 (rejected requests must not spend them)", left)
+	}
+}
+
+// 라우트별 한도가 더 엄격하면 기본 한도가 남아 있어도 거부
+func TestRateLimitRouteRule(t *testing.T) {
+	buckets := &countingBuckets{remaining: map[string]int{}}
+	r := newRateLimitRouter(buckets, 10, 1)
+
+	if w := serve(r, http.MethodPost, "/login"); w.Code != http.StatusOK {
+		t.Fatalf("first login: status = %d, want 200", w.Code)
+	}
+	if w := serve(r, http.MethodPost, "/login"); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("second login: status = %d, want 429", w.Code)
+	}
+	if w := serve(r, http.MethodGet, "/other"); w.Code != http.StatusOK {
+		t.Fatalf("other route: status = %d, want 200", w.Code)
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"strings"
+
+	"vm-controller/internal/logger"
+
+	gin "github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID는 요청마다 ID를 부여합니다. 프록시가 넘긴 X-Request-ID가 있으면 그대로 사용합니다.
+// ID는 응답 헤더와 컨텍스트("request_id")에 저장되어 에러 응답과 로그를 연결하는 데 사용됩니다.
+// 요청 컨텍스트(logger.WithRequest)에도 담기므로 서비스 계층과 백그라운드 작업 로그에도 같은 ID가 남습니다.
+//
+// trace id도 함께 정합니다. 프록시/클라이언트가 보낸 W3C traceparent가 있으면 그 trace를 이어 쓰고,
+// 없으면 새로 만들어 컨텍스트("trace_id")에 저장합니다. 백그라운드 작업은 이 값을 링크로 기록합니다.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !ValidRequestID(id) {
+			id = uuid.NewString()
+		}
+
+		c.Set("request_id", id)
+
+		traceID := logger.ParseTraceID(c.GetHeader("traceparent"))
+		if traceID == "" {
+			traceID = logger.NewTraceID()
+		}
+		c.Set("trace_id", traceID)
+		c.Request = c.Request.WithContext(logger.WithRequest(c.Request.Context(), id, traceID))
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+// ValidRequestID는 프록시나 클라이언트가 넘긴 ID를 그대로 써도 되는지 확인합니다. (로그 줄 위조를 막기 위해 영숫자와 -_.:만 허용)
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_.:", r)) {
+			return false
+		}
+	}
+	return true
+}
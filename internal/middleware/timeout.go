@@ -0,0 +1,217 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/logger"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+// TimeoutPolicy는 라우트별 핸들러 제한 시간입니다.
+// Routes에 "METHOD /full/path" 로 지정하며, 0은 제한 없음(스트리밍 라우트)을 뜻합니다.
+// 제한 시간이 있는 라우트는 응답 전체를 버퍼에 모으므로, 응답을 스트리밍하거나 연결을 hijack하는 라우트는 반드시 0으로 지정해야 합니다.
+// 지정되지 않은 라우트는 GET이면 Read, 그 외에는 Write를 사용합니다.
+type TimeoutPolicy struct {
+	Read   time.Duration
+	Write  time.Duration
+	Routes map[string]time.Duration
+}
+
+func (p TimeoutPolicy) timeoutFor(method, fullPath string) time.Duration {
+	if timeout, ok := p.Routes[method+" "+fullPath]; ok {
+		return timeout
+	}
+	if method == http.MethodGet || method == http.MethodHead {
+		return p.Read
+	}
+	return p.Write
+}
+
+// Timeout은 핸들러가 제한 시간 안에 끝나지 않으면 요청 컨텍스트를 취소하고 504와 요청 ID를 반환합니다.
+//
+// 핸들러의 응답은 버퍼에 모았다가 제한 시간 안에 끝난 경우에만 전송합니다.
+// 제한 시간이 지나면 504를 즉시 보내고 연결을 닫도록 알리지만, gin 컨텍스트는 재사용되므로
+// 미들웨어는 핸들러가 실제로 반환될 때까지 기다립니다. (핸들러는 취소된 컨텍스트를 보고 중단해야 합니다)
+func Timeout(policy TimeoutPolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout := policy.timeoutFor(c.Request.Method, c.FullPath())
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		original := c.Writer
+		buffered := &timeoutWriter{ResponseWriter: original, header: http.Header{}, request: c.Request, route: c.Request.Method + " " + c.FullPath()}
+		c.Writer = buffered
+
+		done := make(chan struct{})
+		var panicked interface{}
+
+		go func() {
+			defer close(done)
+			defer func() { panicked = recover() }()
+			c.Next()
+		}()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			buffered.expire(c, original, timeout)
+			<-done
+		}
+
+		c.Writer = original
+		if panicked != nil {
+			panic(panicked)
+		}
+		buffered.flushTo(original)
+	}
+}
+
+// timeoutWriter는 핸들러의 응답을 버퍼에 모으는 gin.ResponseWriter 입니다.
+type timeoutWriter struct {
+	gin.ResponseWriter // 원본 (직접 쓰지 않음)
+
+	request *http.Request
+	route   string
+
+	mu       sync.Mutex
+	warned   bool // 스트리밍 시도 경고를 이미 남겼는지
+	header   http.Header
+	body     bytes.Buffer
+	status   int
+	timedOut bool
+}
+
+func (w *timeoutWriter) Header() http.Header { return w.header }
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.status == 0 {
+		w.status = code
+	}
+}
+
+func (w *timeoutWriter) WriteHeaderNow() {}
+
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(data)
+}
+
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *timeoutWriter) Status() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+func (w *timeoutWriter) Size() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.status == 0 {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *timeoutWriter) Written() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.status != 0
+}
+
+// 버퍼링 중에는 flush / hijack 할 수 없음 (스트리밍 라우트는 TimeoutPolicy에서 제외)
+// 제외하지 않은 라우트가 스트리밍을 시도하면 응답이 끝날 때까지 전송되지 않으므로 경고를 남깁니다.
+func (w *timeoutWriter) Flush() {
+	w.warnStreaming("flush")
+}
+
+func (w *timeoutWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.warnStreaming("hijack")
+	return nil, nil, fmt.Errorf("hijack is not supported on routes with a handler timeout")
+}
+
+func (w *timeoutWriter) warnStreaming(op string) {
+	w.mu.Lock()
+	warned := w.warned
+	w.warned = true
+	w.mu.Unlock()
+
+	if !warned {
+		logger.FromContext(w.request.Context()).Warn("streaming response is buffered by the handler timeout; exempt the route in the timeout policy",
+			"component", "http", "route", w.route, "op", op)
+	}
+}
+
+func (w *timeoutWriter) Pusher() http.Pusher { return nil }
+
+// expire는 이후 핸들러의 쓰기를 막고 504를 바로 전송합니다.
+func (w *timeoutWriter) expire(c *gin.Context, original gin.ResponseWriter, timeout time.Duration) {
+	w.mu.Lock()
+	w.timedOut = true
+	w.mu.Unlock()
+
+	requestId, _ := c.Get("request_id")
+	logger.FromContext(c.Request.Context()).Warn("request timed out", "method", c.Request.Method, "path", c.Request.URL.Path, "timeout", timeout.String())
+
+	body, _ := json.Marshal(apperrors.New(apperrors.CodeTimeout, "Request timed out").WithDetail("request_id", fmt.Sprint(requestId)).Response())
+	original.Header().Set("Content-Type", "application/json; charset=utf-8")
+	original.Header().Set("Connection", "close")
+	original.WriteHeader(http.StatusGatewayTimeout)
+	original.Write(body)
+	original.Flush()
+}
+
+// flushTo는 제한 시간 안에 끝난 핸들러의 응답을 원본 writer로 전송합니다.
+func (w *timeoutWriter) flushTo(original gin.ResponseWriter) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timedOut {
+		return
+	}
+
+	for key, values := range w.header {
+		original.Header()[key] = values
+	}
+	if w.status != 0 {
+		original.WriteHeader(w.status)
+	}
+	if w.body.Len() > 0 {
+		original.Write(w.body.Bytes())
+	}
+}
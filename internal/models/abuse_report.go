@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type EnumReportStatus string
+
+const (
+	ReportStatusOpen      EnumReportStatus = "Open"      // 관리자 확인 대기
+	ReportStatusResolved  EnumReportStatus = "Resolved"  // 조치 완료 (VM 정지, 사용자 정지 등)
+	ReportStatusDismissed EnumReportStatus = "Dismissed" // 문제 없음으로 종료
+)
+
+// 신고 유형
+const (
+	ReportCategorySpam     = "spam"
+	ReportCategoryMining   = "crypto_mining"
+	ReportCategoryPhishing = "phishing"
+	ReportCategoryOther    = "other"
+)
+
+// ReportCategories는 신고할 수 있는 유형 목록입니다.
+var ReportCategories = []string{ReportCategorySpam, ReportCategoryMining, ReportCategoryPhishing, ReportCategoryOther}
+
+// 신고 출처 (사용자 신고 / 자동 탐지)
+const (
+	ReportSourceUser     = "user"
+	ReportSourceDetector = "detector"
+)
+
+// 신고 대상 종류
+const (
+	ReportTargetVM         = "vm"
+	ReportTargetDeployment = "deployment"
+)
+
+// AbuseReport 구조체는 사용자가 신고한 VM/웹 배포를 저장합니다.
+// 신고자가 입력한 호스트 이름 등으로 대상을 찾아 소유자와 함께 기록하므로, 대상이 삭제되어도 신고 내역은 남습니다.
+type AbuseReport struct {
+	gorm.Model
+	ReporterID  uint             `gorm:"column:reporter_id;not null;index"` // 신고한 사용자 ID
+	Category    string           `gorm:"column:category;not null"`          // 신고 유형
+	TargetType  string           `gorm:"column:target_type;not null"`       // vm / deployment
+	TargetName  string           `gorm:"column:target_name;not null;index"` // VM 이름 또는 배포 도메인
+	TargetID    uint             `gorm:"column:target_id;not null"`         // VM 또는 배포 ID
+	OwnerID     uint             `gorm:"column:owner_id;not null;index"`    // 대상 소유자 ID
+	Hostname    string           `gorm:"column:hostname"`                   // 신고자가 입력한 호스트 이름
+	Description string           `gorm:"column:description;type:text"`      // 신고 내용
+	Status      EnumReportStatus `gorm:"column:status;not null;index"`      // 처리 상태
+	ReviewerID  *uint            `gorm:"column:reviewer_id"`                // 처리한 관리자 ID
+	Action      string           `gorm:"column:action"`                     // 관리자 조치 (stop / suspend_user / dismiss)
+	Note        string           `gorm:"column:note"`                       // 처리 메모
+
+	// 자동 탐지 신고 (ReporterID 0)
+	Source      string          `gorm:"column:source;not null;default:user;index"` // user / detector
+	Evidence    *MiningEvidence `gorm:"column:evidence;serializer:json"`           // 탐지 근거 (최근 관측값으로 갱신)
+	ThrottledAt *time.Time      `gorm:"column:throttled_at"`                       // 검토 전 자동 격리 시각 (기각하면 해제)
+}
+
+// MiningEvidence는 채굴 의심 VM을 탐지한 근거입니다.
+type MiningEvidence struct {
+	// 지속적인 CPU 포화
+	AvgUsageCores  float64 `json:"avg_usage_cores"`
+	LimitCores     float64 `json:"limit_cores"`
+	SaturatedRatio float64 `json:"saturated_ratio"` // 관측 구간 중 CPU limit에 도달한 비율
+	Samples        int     `json:"samples"`
+
+	// 채굴 풀 연결과 비슷한 네트워크 패턴 (항상 열려 있는 저대역 연결)
+	SteadyNetwork     bool    `json:"steady_network"`
+	ActiveRatio       float64 `json:"active_ratio"`         // 송수신이 모두 있었던 구간 비율
+	AvgBytesPerMinute float64 `json:"avg_bytes_per_minute"` // 송수신 합계
+	TxVariation       float64 `json:"tx_variation"`         // 구간별 송신량의 변동 계수 (낮을수록 일정)
+
+	// 차단 목록의 채굴 풀 도메인 조회
+	PoolLookups []PoolLookup `json:"pool_lookups,omitempty"`
+
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// PoolLookup은 VM이 조회한 채굴 풀 도메인입니다.
+type PoolLookup struct {
+	Domain   string    `json:"domain"`
+	Count    int       `json:"count"`
+	LastSeen time.Time `json:"last_seen"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "gorm.io/gorm"
+
+// AuditLog 구조체는 관리자 작업 등 추적이 필요한 변경 이력을 저장합니다.
+type AuditLog struct {
+	gorm.Model
+	ActorID *uint  `gorm:"column:actor_id;index"`  // 작업을 수행한 사용자 ID (시스템 작업은 NULL)
+	Action  string `gorm:"column:action;not null"` // 작업 종류 (예: namespace.pod-security.update)
+	Target  string `gorm:"column:target;index"`    // 대상 (예: namespace/id-1-abcd1234)
+	Detail  string `gorm:"column:detail"`          // 변경 내용 (예: baseline -> restricted)
+}
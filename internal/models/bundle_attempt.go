@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+type EnumBundleAttemptResult string
+
+const (
+	BundleAttemptPending   EnumBundleAttemptResult = "Pending"   // 매니페스트 적용 완료, 프로비저닝 중
+	BundleAttemptSucceeded EnumBundleAttemptResult = "Succeeded" // VM이 Running에 도달
+	BundleAttemptFailed    EnumBundleAttemptResult = "Failed"    // 매니페스트 적용 또는 프로비저닝 실패
+)
+
+// BundleAttempt 구조체는 VM 생성 시 사용한 매니페스트 번들과 그 결과를 기록합니다.
+// stable / canary 번들의 실패율 비교에 사용됩니다.
+type BundleAttempt struct {
+	ID        uint                    `gorm:"primaryKey"`
+	CreatedAt time.Time               `gorm:"column:created_at;index"`
+	UpdatedAt time.Time               `gorm:"column:updated_at"`
+	VmName    string                  `gorm:"column:vm_name;not null;index"` // 생성한 VM 이름
+	UserID    uint                    `gorm:"column:user_id;not null"`       // 요청한 사용자 ID
+	Channel   string                  `gorm:"column:channel;not null"`       // stable / canary
+	Version   string                  `gorm:"column:version"`                // 번들 VERSION
+	Digest    string                  `gorm:"column:digest"`                 // 번들 내용 해시
+	Result    EnumBundleAttemptResult `gorm:"column:result;not null"`        // 결과
+	Detail    string                  `gorm:"column:detail"`                 // 실패 사유
+}
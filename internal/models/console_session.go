@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ConsoleSession 구조체는 플랫폼을 통해 열린 VM 콘솔 세션의 메타데이터를 저장합니다.
+// 입력 내용(키 입력)은 기록하지 않으며, 콘솔 기록 정책이 켜져 있을 때만 생성됩니다.
+type ConsoleSession struct {
+	gorm.Model
+	UserID    uint       `gorm:"column:user_id;not null;index"` // 세션을 연 사용자 ID
+	VmName    string     `gorm:"column:vm_name;not null;index"` // 대상 VM 이름
+	Namespace string     `gorm:"column:namespace"`              // 대상 VM 네임스페이스
+	Type      string     `gorm:"column:type;default:console"`   // 콘솔 종류 (console: 시리얼, vnc)
+	ClientIP  string     `gorm:"column:client_ip"`              // 접속 IP
+	UserAgent string     `gorm:"column:user_agent"`             // 접속 클라이언트
+	StartedAt time.Time  `gorm:"column:started_at;not null"`    // 세션 시작 시각
+	EndedAt   *time.Time `gorm:"column:ended_at"`               // 세션 종료 시각 (진행 중이면 NULL)
+}
@@ -1,13 +1,46 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	BuildStrategyDockerfile = "dockerfile"
+	BuildStrategyBuildpacks = "buildpacks"
+)
+
+const (
+	DeploymentStatusPending  = "Pending"
+	DeploymentStatusBuilding = "Building"
+	DeploymentStatusDeployed = "Deployed"
+	DeploymentStatusFailed   = "Failed"
+	DeploymentStatusPaused   = "Paused"   // 사용자가 수동으로 0으로 스케일
+	DeploymentStatusSleeping = "Sleeping" // 유휴 상태로 자동 0 스케일, 요청 시 깨어남
+)
+
+const (
+	DeploymentTypeWeb     = "web"     // 상시 실행되는 웹 서비스
+	DeploymentTypeCronJob = "cronjob" // 스케줄에 따라 실행되는 작업
+)
 
 // Deployment 구조체는 GitHub 기반의 웹 배포 정보를 추적합니다.
 type Deployment struct {
 	gorm.Model
-	UserID  uint   `gorm:"not null"`          // 소유한 사용자의 ID
-	User    User   `gorm:"foreignKey:UserID"` // 소유한 사용자 객체
-	RepoURL string `gorm:"not null"`          // GitHub 리포지토리 URL
-	Domain  string `gorm:"not null"`          // 연결된 도메인 (예: project.hy3on.site)
-	Status  string // 배포 상태 (예: "Building", "Deployed", "Failed")
+	UserID          uint       `gorm:"not null"`                                  // 소유한 사용자의 ID
+	User            User       `gorm:"foreignKey:UserID"`                         // 소유한 사용자 객체
+	Type            string     `gorm:"column:type;not null;default:web"`          // 배포 유형 (web / cronjob)
+	Schedule        string     `gorm:"column:schedule"`                           // cronjob 유형의 cron 스케줄 (예: "*/30 * * * *")
+	RepoURL         string     `gorm:"not null"`                                  // GitHub 리포지토리 URL
+	Domain          string     `gorm:"not null"`                                  // 연결된 도메인 (예: project.hy3on.site)
+	Branch          string     `gorm:"column:branch;not null;default:main"`       // 빌드할 브랜치
+	ContextDir      string     `gorm:"column:context_dir;not null;default:."`     // 빌드 컨텍스트 디렉토리 (모노레포 하위 폴더)
+	DockerfilePath  string     `gorm:"column:dockerfile_path;default:Dockerfile"` // 컨텍스트 기준 Dockerfile 경로
+	BuildStrategy   string     `gorm:"column:build_strategy"`                     // 빌드 방식 (dockerfile / buildpacks)
+	Language        string     `gorm:"column:language"`                           // buildpacks 사용 시 감지된 언어 (node, python, go)
+	DatabaseID      *uint      `gorm:"column:database_id"`                        // 연결된 관리형 데이터베이스 ID (선택)
+	SleepAfterHours int        `gorm:"column:sleep_after_hours;default:0"`        // N시간 동안 트래픽이 없으면 자동 0 스케일 (0이면 비활성)
+	LastActivityAt  *time.Time `gorm:"column:last_activity_at"`                   // 마지막 트래픽 관측 시각
+	Status          string     // 배포 상태 (예: "Building", "Deployed", "Failed")
 }
@@ -0,0 +1,18 @@
+package models
+
+import "gorm.io/gorm"
+
+// Flavor 구조체는 VM 요금제(인스턴스 크기)를 저장합니다. 관리자가 추가/수정하며 VM 생성 시 이름으로 선택합니다.
+// 생성된 VM의 디스크 크기는 VM에 따로 기록되므로, 요금제를 수정해도 기존 VM의 디스크/쿼터는 바뀌지 않습니다.
+type Flavor struct {
+	gorm.Model
+	Name         string `gorm:"column:name;not null;uniqueIndex"` // 요금제 이름 (예: standard)
+	Cores        int    `gorm:"column:cores;not null"`            // 게스트에 보이는 vCPU 수
+	CPURequest   string `gorm:"column:cpu_request;not null"`      // 스케줄링 시 보장되는 CPU (예: 500m)
+	CPULimit     string `gorm:"column:cpu_limit;not null"`        // 사용 가능한 최대 CPU
+	Memory       string `gorm:"column:memory;not null"`           // 메모리 (예: 4Gi, request = limit)
+	DiskGi       int    `gorm:"column:disk_gi;not null"`          // 루트 디스크 크기 (GiB, 스토리지 쿼터에 포함)
+	DedicatedCPU bool   `gorm:"column:dedicated_cpu"`             // 전용 CPU 배치
+	HourlyPrice  int    `gorm:"column:hourly_price"`              // 시간당 가격 (원)
+	MonthlyPrice int    `gorm:"column:monthly_price"`             // 월 가격 (원)
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// IdempotencyKey 구조체는 Idempotency-Key 헤더를 붙인 변경 요청과 그 응답입니다.
+// 같은 키로 다시 보낸 요청은 핸들러를 실행하지 않고 저장된 응답을 돌려줍니다. (middleware.Idempotency)
+type IdempotencyKey struct {
+	ID           uint       `gorm:"primaryKey"`
+	CreatedAt    time.Time  `gorm:"column:created_at;index"`
+	UserID       uint       `gorm:"column:user_id;not null;uniqueIndex:idx_idempotency_user_key"` // 요청한 사용자
+	Key          string     `gorm:"column:key;not null;uniqueIndex:idx_idempotency_user_key"`     // Idempotency-Key 헤더 값
+	Method       string     `gorm:"column:method;not null"`
+	Path         string     `gorm:"column:path;not null"`
+	RequestHash  string     `gorm:"column:request_hash;not null"`                        // 메서드/경로/본문의 SHA-256 (다른 요청에 키 재사용 감지)
+	StatusCode   int        `gorm:"column:status_code"`                                  // 응답 코드 (0이면 처리 중)
+	ContentType  string     `gorm:"column:content_type"`                                 // 응답 Content-Type
+	ResponseBody string     `gorm:"column:response_body;type:text;serializer:encrypted"` // 응답 본문 (생성된 비밀번호가 포함될 수 있어 암호화 저장)
+	CompletedAt  *time.Time `gorm:"column:completed_at"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "gorm.io/gorm"
+
+// Image 구조체는 VM 이미지 카탈로그 항목입니다. 관리자가 추가/수정하며 VM 생성 시 이름(vm_image)으로 선택합니다.
+// 이미지마다 기본 사용자가 다르므로(ubuntu, debian, fedora) cloud-init 사용자와 접속 정보에 쓰입니다.
+type Image struct {
+	gorm.Model
+	Name            string   `gorm:"column:name;not null;uniqueIndex"`    // 이미지 이름 (예: ubuntu-22.04)
+	DisplayName     string   `gorm:"column:display_name"`                 // 화면에 보여줄 이름
+	SourcePVC       string   `gorm:"column:source_pvc;not null"`          // 루트 디스크로 복제할 원본 PVC
+	SourceNamespace string   `gorm:"column:source_namespace;not null"`    // 원본 PVC의 네임스페이스
+	SSHUser         string   `gorm:"column:ssh_user;not null"`            // 기본 SSH 사용자
+	AuthMethods     []string `gorm:"column:auth_methods;serializer:json"` // 허용하는 SSH 인증 방식 (password, publickey)
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type EnumJobRunStatus string
+
+const (
+	JobRunStatusRunning   EnumJobRunStatus = "Running"
+	JobRunStatusSucceeded EnumJobRunStatus = "Succeeded"
+	JobRunStatusFailed    EnumJobRunStatus = "Failed"
+)
+
+// JobRun 구조체는 스케줄 작업(CronJob) 배포의 실행 이력을 추적합니다.
+type JobRun struct {
+	gorm.Model
+	DeploymentID uint             `gorm:"column:deployment_id;not null;index"`  // 실행된 배포의 ID
+	JobName      string           `gorm:"column:job_name;not null;uniqueIndex"` // K8s Job 이름
+	Status       EnumJobRunStatus `gorm:"column:status"`                        // 실행 상태
+	StartedAt    *time.Time       `gorm:"column:started_at"`                    // 실행 시작 시각
+	FinishedAt   *time.Time       `gorm:"column:finished_at"`                   // 실행 종료 시각
+	Logs         string           `gorm:"column:logs"`                          // 마지막 로그 (tail)
+}
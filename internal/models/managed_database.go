@@ -0,0 +1,26 @@
+package models
+
+import "gorm.io/gorm"
+
+const (
+	DatabaseEnginePostgres = "postgres"
+	DatabaseEngineMySQL    = "mysql"
+)
+
+// ManagedDatabase 구조체는 사용자 네임스페이스에 프로비저닝된 관리형 데이터베이스 정보를 추적합니다.
+type ManagedDatabase struct {
+	gorm.Model
+	UserID       uint   `gorm:"not null"`                         // 소유한 사용자의 ID
+	User         User   `gorm:"foreignKey:UserID"`                // 소유한 사용자 객체
+	Name         string `gorm:"column:name;not null;uniqueIndex"` // 데이터베이스 인스턴스 이름 (K8s 리소스 이름)
+	Engine       string `gorm:"column:engine;not null"`           // 엔진 (postgres / mysql)
+	Namespace    string `gorm:"column:namespace;not null"`        // K8s 네임스페이스
+	Host         string `gorm:"column:host;not null"`             // 클러스터 내부 접속 주소
+	Port         int32  `gorm:"column:port;not null"`             // 접속 포트
+	StorageGi    int    `gorm:"column:storage_gi;not null"`       // 할당된 스토리지 (GiB, 쿼터에 포함)
+	DatabaseName string `gorm:"column:database_name;not null"`    // 기본 생성 데이터베이스 이름
+	Username     string `gorm:"column:username;not null"`         // 접속 사용자
+	Password     string `gorm:"column:password;not null"`         // 접속 비밀번호 (VM과 동일하게 평문 저장, 운영시 암호화 필요)
+	Status       string `gorm:"column:status"`                    // 상태 (예: "Provisioning", "Ready", "Failed")
+	IsDeleted    bool   `gorm:"column:is_deleted"`                // 삭제 여부
+}
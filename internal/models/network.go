@@ -0,0 +1,22 @@
+package models
+
+import "gorm.io/gorm"
+
+// Network 구조체는 관리자가 등록한, VM 보조 NIC로 연결할 수 있는 네트워크(Multus NetworkAttachmentDefinition) 카탈로그 항목입니다.
+type Network struct {
+	gorm.Model
+	Name          string `gorm:"column:name;not null;uniqueIndex"` // 사용자에게 보이는 이름 (예: lab-vlan10)
+	Description   string `gorm:"column:description"`               // 설명
+	NadNamespace  string `gorm:"column:nad_namespace;not null"`    // NetworkAttachmentDefinition 네임스페이스
+	NadName       string `gorm:"column:nad_name;not null"`         // NetworkAttachmentDefinition 이름
+	AllowStaticIP bool   `gorm:"column:allow_static_ip"`           // 사용자가 고정 IP를 지정할 수 있는지 (아니면 DHCP)
+	Enabled       bool   `gorm:"column:enabled"`                   // 신규 VM에서 선택 가능 여부
+}
+
+// VmNetwork는 VM에 연결된 보조 NIC 한 개입니다. (VirtualMachine.Networks 에 JSON으로 저장)
+type VmNetwork struct {
+	Network    string `json:"network"`           // Network.Name
+	NadRef     string `json:"nad_ref"`           // "namespace/name"
+	MacAddress string `json:"mac_address"`       // 재생성 시에도 유지되는 MAC 주소
+	Address    string `json:"address,omitempty"` // 고정 IP (CIDR), 비어 있으면 DHCP
+}
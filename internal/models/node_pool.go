@@ -0,0 +1,12 @@
+package models
+
+import "gorm.io/gorm"
+
+// NodePoolMaintenance 구조체는 관리자가 신규 VM 배치를 막은 노드 풀입니다. (소프트 유지보수)
+// 풀에서 실행 중인 VM은 그대로 두고, 이후 생성/재생성되는 VM만 다른 풀로 스케줄링됩니다.
+type NodePoolMaintenance struct {
+	gorm.Model
+	Pool    string `gorm:"column:pool;not null;uniqueIndex"` // 노드 풀 이름 (NODE_POOL_LABEL 라벨 값)
+	Reason  string `gorm:"column:reason"`                    // 유지보수 사유 (예: 커널 업데이트 예정)
+	ActorID *uint  `gorm:"column:actor_id"`                  // 유지보수를 설정한 관리자
+}
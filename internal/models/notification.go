@@ -0,0 +1,12 @@
+package models
+
+import "gorm.io/gorm"
+
+// Notification 구조체는 사용자에게 전달되는 인앱 알림을 저장합니다.
+type Notification struct {
+	gorm.Model
+	UserID  uint   `gorm:"column:user_id;not null;index"` // 알림을 받을 사용자의 ID
+	Title   string `gorm:"column:title;not null"`         // 알림 제목
+	Message string `gorm:"column:message"`                // 알림 본문
+	IsRead  bool   `gorm:"column:is_read"`                // 읽음 여부
+}
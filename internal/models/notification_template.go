@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// NotificationTemplate 구조체는 관리자가 수정한 알림 문구입니다. (알림 종류 + 언어별 하나)
+// 레코드가 없으면 코드에 정의된 기본 문구(notificationservice.Templates)를 사용합니다.
+type NotificationTemplate struct {
+	Key       string    `gorm:"column:template_key;primaryKey"` // 알림 종류 (예: vm.expire.stop)
+	Locale    string    `gorm:"column:locale;primaryKey"`       // 언어 (ko, en)
+	Title     string    `gorm:"column:title;not null"`          // 제목 템플릿 (text/template)
+	Message   string    `gorm:"column:message;not null"`        // 본문 템플릿 (text/template)
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+	UpdatedBy *uint     `gorm:"column:updated_by"` // 수정한 관리자 ID
+}
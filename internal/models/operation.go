@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+type EnumOperationStatus string
+
+const (
+	OperationQueued    EnumOperationStatus = "Queued"    // 작업 슬롯 대기 중 (재시도 대기 포함, NextRunAt 이후 실행)
+	OperationRunning   EnumOperationStatus = "Running"   // 워커가 점유하여 실행 중
+	OperationSucceeded EnumOperationStatus = "Succeeded" // 완료
+	OperationFailed    EnumOperationStatus = "Failed"    // 모든 시도 실패 또는 이어서 실행할 수 없음
+	OperationSkipped   EnumOperationStatus = "Skipped"   // 같은 대상의 다른 작업이 실행 중이라 건너뜀
+	OperationRejected  EnumOperationStatus = "Rejected"  // 상태 머신이 거부 (다른 요청이 먼저 상태를 바꿈)
+)
+
+// OperationActiveStatuses는 대상을 점유하는(아직 끝나지 않은) 작업 상태입니다.
+// 대상별로 하나만 존재할 수 있습니다. (db.Migrate의 idx_operations_active_target)
+var OperationActiveStatuses = []EnumOperationStatus{OperationQueued, OperationRunning}
+
+// Operation 구조체는 백그라운드 작업(RunAsync) 하나의 진행 상태와 결과입니다.
+// API는 작업을 요청한 뒤 ID를 돌려주고, 클라이언트는 GET /api/operations/:id 로 결과를 확인합니다.
+// 작업 큐이기도 하여, 워커는 Queued 작업을 점유(Running, LeaseUntil)해서 실행하고 서버가 재시작되면 남은 작업을 이어서 실행합니다.
+type Operation struct {
+	ID         uint                `gorm:"primaryKey"`
+	CreatedAt  time.Time           `gorm:"column:created_at;index"`
+	UpdatedAt  time.Time           `gorm:"column:updated_at"`
+	Name       string              `gorm:"column:name;not null"`         // 작업 이름 (예: vm.stop)
+	Target     string              `gorm:"column:target;not null;index"` // 대상 리소스 (예: vm/my-vps)
+	UserID     *uint               `gorm:"column:user_id;index"`         // 대상 리소스 소유자 (시스템 리소스는 NULL)
+	Status     EnumOperationStatus `gorm:"column:status;not null;index"` // 진행 상태
+	Attempts   int                 `gorm:"column:attempts;not null"`     // 실행한 시도 횟수
+	Error      string              `gorm:"column:error"`                 // 마지막 실패 사유
+	TraceID    string              `gorm:"column:trace_id"`              // 작업을 요청한 HTTP 요청의 trace id
+	RequestID  string              `gorm:"column:request_id;index"`      // 작업을 요청한 HTTP 요청의 ID (X-Request-ID)
+	StartedAt  *time.Time          `gorm:"column:started_at"`            // 첫 시도 시작 시각
+	FinishedAt *time.Time          `gorm:"column:finished_at"`           // 종료 시각
+	Payload    string              `gorm:"column:payload;type:text"`     // 작업을 다시 만들 때 필요한 인자 (JSON, 비밀번호 등은 저장하지 않음)
+	NextRunAt  *time.Time          `gorm:"column:next_run_at;index"`     // 재시도 대기 중이면 다음 시도 시각
+	Worker     string              `gorm:"column:worker"`                // 점유한 워커 (호스트/번호)
+	LeaseUntil *time.Time          `gorm:"column:lease_until"`           // 점유 만료 시각 (워커가 갱신하지 못하면 다른 워커가 이어서 실행)
+}
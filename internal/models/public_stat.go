@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// PublicStatSetting 구조체는 공개 통계(GET /api/public/stats)에 포함할 지표를 관리자가 지정한 값입니다.
+// 레코드가 없는 지표는 PUBLIC_STATS 환경 변수의 기본값을 따릅니다.
+type PublicStatSetting struct {
+	Metric    string    `gorm:"column:metric;primaryKey"` // 지표 이름 (예: vms_running)
+	Public    bool      `gorm:"column:public;not null"`   // 공개 여부
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+	UpdatedBy *uint     `gorm:"column:updated_by"` // 변경한 관리자 ID
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type EnumSnapshotStatus string
+
+const (
+	SnapshotStatusInProgress EnumSnapshotStatus = "InProgress" // VirtualMachineSnapshot 생성 중
+	SnapshotStatusReady      EnumSnapshotStatus = "Ready"      // 복원에 사용 가능
+	SnapshotStatusFailed     EnumSnapshotStatus = "Failed"     // 생성 실패 (삭제 후 다시 생성)
+)
+
+// Snapshot 구조체는 VM 디스크 스냅샷(KubeVirt VirtualMachineSnapshot)의 메타데이터를 저장합니다.
+// 실제 디스크 데이터는 클러스터의 VolumeSnapshot으로 보관됩니다.
+type Snapshot struct {
+	gorm.Model
+	UserID         uint               `gorm:"column:user_id;not null;index"`    // 소유한 사용자 ID
+	VmName         string             `gorm:"column:vm_name;not null;index"`    // 대상 VM 이름
+	Namespace      string             `gorm:"column:namespace;not null"`        // VM 네임스페이스 (스냅샷 CR도 같은 네임스페이스)
+	Name           string             `gorm:"column:name;not null;uniqueIndex"` // VirtualMachineSnapshot 이름
+	Description    string             `gorm:"column:description"`               // 사용자 메모
+	Status         EnumSnapshotStatus `gorm:"column:status"`                    // 스냅샷 상태
+	Message        string             `gorm:"column:message"`                   // 실패 사유
+	ReadyAt        *time.Time         `gorm:"column:ready_at"`                  // 복원 가능해진 시각
+	LastRestoredAt *time.Time         `gorm:"column:last_restored_at"`          // 마지막으로 복원한 시각
+}
@@ -0,0 +1,13 @@
+package models
+
+import "gorm.io/gorm"
+
+// SSHKey 구조체는 사용자가 등록한 SSH 공개 키를 저장합니다.
+// VM 생성 시 선택한 키가 cloud-init userdata(ssh_authorized_keys)로 주입됩니다.
+type SSHKey struct {
+	gorm.Model
+	UserID      uint   `gorm:"column:user_id;not null;uniqueIndex:idx_ssh_keys_user_fingerprint"`     // 키를 등록한 사용자 ID
+	Name        string `gorm:"column:name;not null"`                                                  // 키 이름 (예: laptop)
+	PublicKey   string `gorm:"column:public_key;not null"`                                            // authorized_keys 형식의 공개 키
+	Fingerprint string `gorm:"column:fingerprint;not null;uniqueIndex:idx_ssh_keys_user_fingerprint"` // SHA256 지문
+}
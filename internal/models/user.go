@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
@@ -14,9 +16,32 @@ type User struct {
 	VMs           []VirtualMachine // 사용자가 소유한 VM 목록
 	Deployments   []Deployment     // 사용자가 배포한 웹 서비스 목록
 	Namespace     string           `gorm:"column:namespace;not null"` // K8s 네임스페이스 무조건 있음...
-	Email 		string	`gorm:"column:email;not null"`
+	Email         string           `gorm:"column:email;not null"`
+	Role          string           `gorm:"column:role;not null;default:user"` // 권한 (user / admin / auditor)
+
+	// VM 사용 기간(일). nil이면 기본 정책(VM_LEASE_DAYS), 0이면 만료 없음
+	VmLeaseDays *int `gorm:"column:vm_lease_days"`
+
+	// 네임스페이스 초기화(yaml-data/client-init) 적용 완료 여부. true면 이후 생성에서 초기화 적용을 건너뜀
+	NamespaceInitialized bool `gorm:"column:namespace_initialized;not null;default:false"`
+
+	// 관리자가 계정을 정지한 시각 (nil이면 정상). 정지된 계정은 로그인과 API 호출이 거부됨
+	SuspendedAt     *time.Time `gorm:"column:suspended_at"`
+	SuspendedReason string     `gorm:"column:suspended_reason"`
+
+	// 알림 언어 (ko, en). 비어 있으면 NOTIFICATION_DEFAULT_LOCALE
+	Locale string `gorm:"column:locale"`
 }
 
+const (
+	RoleUser    = "user"
+	RoleAdmin   = "admin"
+	RoleAuditor = "auditor" // 관리자 화면 읽기 전용 (조교/보안 검토)
+)
+
+// Roles는 부여할 수 있는 권한 목록입니다.
+var Roles = []string{RoleUser, RoleAdmin, RoleAuditor}
+
 // HashPassword 함수는 평문 비밀번호를 bcrypt 알고리즘을 사용하여 해시화합니다.
 func HashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
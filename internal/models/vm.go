@@ -1,6 +1,10 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type EnumVmStatus string
 
@@ -8,21 +12,59 @@ const (
 	VmStatusProvisioning EnumVmStatus = "Provisioning"
 	VmStatusFailed       EnumVmStatus = "Failed"
 	VmStatusRunning      EnumVmStatus = "Running"
+	VmStatusRestarting   EnumVmStatus = "Restarting"
+	VmStatusPausing      EnumVmStatus = "Pausing"
+	VmStatusPaused       EnumVmStatus = "Paused" // vCPU만 멈춘 상태 (메모리 유지)
 	VmStatusStopping     EnumVmStatus = "Stopping"
 	VmStatusStopped      EnumVmStatus = "Stopped"
+	VmStatusRestoring    EnumVmStatus = "Restoring" // 스냅샷으로 디스크 복원 중 (정지 상태에서만)
 	VmStatusDeleted      EnumVmStatus = "Deleted"
 )
 
+// EnumVmDesiredState는 API 요청으로 설정되는 VM의 목표 상태입니다.
+// converger가 클러스터의 실제 상태를 이 값으로 수렴시킵니다.
+type EnumVmDesiredState string
+
+const (
+	VmDesiredRunning EnumVmDesiredState = "Running"
+	VmDesiredStopped EnumVmDesiredState = "Stopped"
+	VmDesiredDeleted EnumVmDesiredState = "Deleted"
+)
+
 // VirtualMachine 구조체는 사용자를 위해 프로비저닝된 VM 정보를 추적합니다.
 type VirtualMachine struct {
 	gorm.Model
-	UserID    uint         `gorm:"not null"`                         // 소유한 사용자의 ID
-	User      User         `gorm:"foreignKey:UserID"`                // 소유한 사용자 객체
-	Name      string       `gorm:"column:name;not null;uniqueIndex"` // VM 이름 (예: my-cloud-vps)
-	Namespace string       `gorm:"column:namespace;not null"`        // K8s 네임스페이스
-	NodePort  int32        `gorm:"column:node_port;not null"`        // SSH 접근을 위한 NodePort 번호
-	Password  string       `gorm:"column:password;not null"`         // Root 계정 비밀번호 (요구사항에 따라 평문 저장, 운영시 암호화 필요)
-	Status    EnumVmStatus `gorm:"column:status"`                    // VM 상태 (예: "Provisioned", "Failed")
-	Image     string       `gorm:"column:image"`                     // VM 이미지
-	IsDeleted bool         `gorm:"column:is_deleted"`                // VM 삭제 여부
+	UserID    uint         `gorm:"not null"`                                      // 소유한 사용자의 ID
+	User      User         `gorm:"foreignKey:UserID"`                             // 소유한 사용자 객체
+	Name      string       `gorm:"column:name;not null;uniqueIndex"`              // VM 이름 (예: my-cloud-vps)
+	Namespace string       `gorm:"column:namespace;not null"`                     // K8s 네임스페이스
+	NodePort  int32        `gorm:"column:node_port;not null"`                     // SSH 접근을 위한 NodePort 번호
+	Password  string       `gorm:"column:password;not null;serializer:encrypted"` // Root 계정 비밀번호 (SECRET_ENCRYPTION_KEY 설정 시 암호화 저장)
+	Status    EnumVmStatus `gorm:"column:status"`                                 // VM 상태 (예: "Provisioned", "Failed")
+	Image     string       `gorm:"column:image"`                                  // VM 이미지
+	Flavor    string       `gorm:"column:flavor;default:standard"`                // 요금제 (CPU/메모리 할당, k8s_service.Flavor)
+	DiskGi    int          `gorm:"column:disk_gi;default:20"`                     // 생성 시 요금제의 루트 디스크 크기 (GiB)
+	IsDeleted bool         `gorm:"column:is_deleted"`                             // VM 삭제 여부
+
+	// 재생성 시에도 게스트 네트워크 설정이 유지되도록 보존하는 식별 정보
+	DnsHost    string      `gorm:"column:dns_host"`                 // Ingress 호스트 (예: prefix.domain.com)
+	MacAddress string      `gorm:"column:mac_address"`              // 기본 인터페이스 MAC 주소
+	Networks   []VmNetwork `gorm:"column:networks;serializer:json"` // 보조 NIC (Multus 네트워크)
+	SSHKeys    []string    `gorm:"column:ssh_keys;serializer:json"` // 주입한 SSH 공개 키 (등록 키를 삭제해도 유지)
+	CloudInit  string      `gorm:"column:cloud_init;type:text"`     // 사용자 cloud-config (검증 후 userdata에 병합)
+
+	DesiredState EnumVmDesiredState `gorm:"column:desired_state;default:Running"` // 목표 상태 (Running/Stopped/Deleted)
+
+	// 생성에 사용한 매니페스트 번들 (재생성 시에도 같은 번들 사용)
+	BundleChannel string `gorm:"column:bundle_channel;default:stable"` // stable / canary
+	BundleVersion string `gorm:"column:bundle_version"`                // 번들 VERSION
+
+	// 사용 기간 만료 시각 (nil이면 만료 없음). 만료되면 정지되고, 유예 기간이 지나면 삭제됨
+	ExpiresAt *time.Time `gorm:"column:expires_at;index"`
+
+	// 비밀번호는 한 번만 확인할 수 있음 (자동 생성 비밀번호를 생성 응답으로 받았거나 POST /api/vm/:name/credentials 호출 시 기록)
+	CredentialsRevealedAt *time.Time `gorm:"column:credentials_revealed_at"`
+
+	// 생성 시 이미지의 기본 SSH 사용자 (비어 있으면 root, 이미지 카탈로그 이전에 만든 VM)
+	SSHUser string `gorm:"column:ssh_user"`
 }
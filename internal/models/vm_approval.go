@@ -0,0 +1,31 @@
+package models
+
+import "gorm.io/gorm"
+
+type EnumApprovalStatus string
+
+const (
+	ApprovalStatusPending  EnumApprovalStatus = "PendingApproval" // 관리자 승인 대기
+	ApprovalStatusApproved EnumApprovalStatus = "Approved"        // 승인 후 VM 생성 완료
+	ApprovalStatusRejected EnumApprovalStatus = "Rejected"        // 관리자가 거절
+	ApprovalStatusFailed   EnumApprovalStatus = "Failed"          // 승인했으나 VM 생성 실패
+)
+
+// VmApproval 구조체는 관리자 승인이 필요한 VM 생성 요청을 저장합니다.
+// 승인 시 저장된 요청 그대로 VM을 생성합니다. 요청 본문도 암호화하여 저장하지만,
+// 비밀번호는 본문(Params)에 남기지 않고 별도 컬럼에 두어 본문이 복호화되어 전달되어도 노출되지 않도록 합니다.
+type VmApproval struct {
+	gorm.Model
+	UserID     uint               `gorm:"column:user_id;not null;index"`                                  // 요청한 사용자 ID
+	User       User               `gorm:"foreignKey:UserID"`                                              // 요청한 사용자
+	VmName     string             `gorm:"column:vm_name;not null;index"`                                  // 생성할 VM 이름
+	VmFlavor   string             `gorm:"column:vm_flavor;not null"`                                      // 요청한 요금제
+	Template   string             `gorm:"column:template;not null"`                                       // 매니페스트 템플릿 (client-vm / client-vm-upload)
+	Params     string             `gorm:"column:params;type:text;not null;serializer:encrypted" json:"-"` // 생성 요청 본문 (JSON)
+	Status     EnumApprovalStatus `gorm:"column:status;not null;index"`                                   // 승인 상태
+	ReviewerID *uint              `gorm:"column:reviewer_id"`                                             // 승인/거절한 관리자 ID
+	Reason     string             `gorm:"column:reason"`                                                  // 거절 사유 또는 생성 실패 사유
+
+	Password          string `gorm:"column:password;serializer:encrypted" json:"-"` // VM 비밀번호 (Params에서 분리)
+	PasswordGenerated bool   `gorm:"column:password_generated" json:"-"`            // 자동 생성 비밀번호를 요청 응답으로 이미 전달함
+}
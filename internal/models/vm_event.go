@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+type EnumVmEventType string
+
+const (
+	VmEventOperationRequested EnumVmEventType = "OperationRequested" // API로 작업 요청됨
+	VmEventPatchSent          EnumVmEventType = "PatchSent"          // 클러스터에 변경(패치/생성/삭제) 전송
+	VmEventStatusObserved     EnumVmEventType = "StatusObserved"     // 클러스터에서 관측된 상태
+	VmEventStatusTransition   EnumVmEventType = "StatusTransition"   // DB 상태 전이
+	VmEventOperationFailed    EnumVmEventType = "OperationFailed"    // 작업 최종 실패
+	VmEventOperationSucceeded EnumVmEventType = "OperationSucceeded" // 백그라운드 작업 완료
+)
+
+// VmEvent 구조체는 VM 수명주기의 append-only 이벤트 스트림입니다.
+// 수정/삭제하지 않으므로 gorm.Model 대신 ID와 CreatedAt만 가집니다.
+type VmEvent struct {
+	ID         uint            `gorm:"primaryKey"`
+	CreatedAt  time.Time       `gorm:"column:created_at;index"`
+	VmName     string          `gorm:"column:vm_name;not null;index"` // 대상 VM 이름
+	Type       EnumVmEventType `gorm:"column:type;not null"`          // 이벤트 종류
+	Operation  string          `gorm:"column:operation"`              // 관련 작업 (create/start/stop/delete ...)
+	FromStatus string          `gorm:"column:from_status"`            // 전이 이전 상태 (StatusTransition)
+	ToStatus   string          `gorm:"column:to_status"`              // 전이 이후 상태 또는 관측된 상태
+	Detail     string          `gorm:"column:detail"`                 // 부가 정보 (패치 내용, 에러 메시지 등)
+	ActorID    *uint           `gorm:"column:actor_id"`               // 요청한 사용자 ID (시스템 이벤트는 NULL)
+}
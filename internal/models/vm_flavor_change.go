@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type EnumFlavorChangeStatus string
+
+const (
+	FlavorChangeScheduled EnumFlavorChangeStatus = "Scheduled" // 변경 시간대 대기
+	FlavorChangeRunning   EnumFlavorChangeStatus = "Running"   // 스펙 변경/재시작 중
+	FlavorChangeCompleted EnumFlavorChangeStatus = "Completed" // 변경 완료
+	FlavorChangeFailed    EnumFlavorChangeStatus = "Failed"    // 변경 실패 (Error에 사유)
+	FlavorChangeCancelled EnumFlavorChangeStatus = "Cancelled" // 사용자가 취소
+	FlavorChangeMissed    EnumFlavorChangeStatus = "Missed"    // 시간대 안에 실행하지 못함 (VM 작업 중, 서버 중단 등)
+)
+
+// VmFlavorChange 구조체는 VM 요금제 변경 요청입니다.
+// 사용자가 고른 시간대(WindowStart ~ WindowEnd) 안에 스케줄러가 실행하며, 실행 중인 VM은 재시작됩니다.
+// 요청 시점의 가격 차이를 함께 저장하여 요금제 가격이 나중에 바뀌어도 당시 미리보기 내용을 확인할 수 있습니다.
+type VmFlavorChange struct {
+	gorm.Model
+	VmName      string                 `gorm:"column:vm_name;not null;index"` // 대상 VM 이름
+	UserID      uint                   `gorm:"column:user_id;not null;index"` // 요청한 사용자 ID
+	FromFlavor  string                 `gorm:"column:from_flavor;not null"`   // 변경 전 요금제
+	ToFlavor    string                 `gorm:"column:to_flavor;not null"`     // 변경할 요금제
+	WindowStart time.Time              `gorm:"column:window_start;not null"`  // 실행 가능 시작 시각
+	WindowEnd   time.Time              `gorm:"column:window_end;not null"`    // 실행 가능 종료 시각 (지나면 Missed)
+	Status      EnumFlavorChangeStatus `gorm:"column:status;not null;index"`  // 진행 상태
+	OperationID *uint                  `gorm:"column:operation_id"`           // 실행한 백그라운드 작업 ID
+	Error       string                 `gorm:"column:error"`                  // 실패/누락 사유
+	StartedAt   *time.Time             `gorm:"column:started_at"`             // 실행 시작 시각
+	FinishedAt  *time.Time             `gorm:"column:finished_at"`            // 완료/실패 시각
+
+	HourlyPriceDelta  int `gorm:"column:hourly_price_delta"`  // 시간당 가격 변화 (원)
+	MonthlyPriceDelta int `gorm:"column:monthly_price_delta"` // 월 가격 변화 (원)
+}
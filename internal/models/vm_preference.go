@@ -0,0 +1,14 @@
+package models
+
+import "gorm.io/gorm"
+
+// VmPreference 구조체는 사용자별 VM 생성 기본값입니다.
+// VM 생성 요청에서 생략한 항목(이미지, 요금제, SSH 키, 호스트)에 자동으로 적용됩니다.
+type VmPreference struct {
+	gorm.Model
+	UserID    uint   `gorm:"column:user_id;not null;uniqueIndex"` // 기본값을 저장한 사용자 ID
+	Image     string `gorm:"column:image"`                        // 기본 이미지 이름 (비어 있으면 플랫폼 기본값)
+	Flavor    string `gorm:"column:flavor"`                       // 기본 요금제 이름 (비어 있으면 플랫폼 기본값)
+	SSHKeyIDs []uint `gorm:"column:ssh_key_ids;serializer:json"`  // 기본으로 주입할 등록 SSH 키 ID
+	DNSBase   string `gorm:"column:dns_base"`                     // 호스트 접두사 기본값 ("<vm 이름>.<dns base>")
+}
@@ -0,0 +1,25 @@
+package models
+
+import "gorm.io/gorm"
+
+type EnumVolumeStatus string
+
+const (
+	VolumeStatusAttaching EnumVolumeStatus = "Attaching" // DataVolume 생성 및 핫플러그 진행 중
+	VolumeStatusAttached  EnumVolumeStatus = "Attached"  // VM 게스트에서 사용 가능
+	VolumeStatusDetaching EnumVolumeStatus = "Detaching" // 핫플러그 해제 및 디스크 삭제 진행 중
+	VolumeStatusFailed    EnumVolumeStatus = "Failed"    // 연결/해제 실패 (detach로 정리)
+)
+
+// VolumeAttachment 구조체는 VM에 핫플러그한 추가 데이터 디스크(DataVolume)를 저장합니다.
+// 루트 디스크와 달리 VM 템플릿에 포함되지 않으므로 VM 삭제 시 별도로 정리합니다.
+type VolumeAttachment struct {
+	gorm.Model
+	UserID    uint             `gorm:"column:user_id;not null;index"`    // 소유한 사용자 ID
+	VmName    string           `gorm:"column:vm_name;not null;index"`    // 연결된 VM 이름
+	Namespace string           `gorm:"column:namespace;not null"`        // VM 네임스페이스 (DataVolume도 같은 네임스페이스)
+	Name      string           `gorm:"column:name;not null;uniqueIndex"` // DataVolume / VM volume 이름
+	SizeGi    int              `gorm:"column:size_gi;not null"`          // 디스크 크기 (GiB, 스토리지 쿼터에 포함)
+	Status    EnumVolumeStatus `gorm:"column:status"`                    // 연결 상태
+	Message   string           `gorm:"column:message"`                   // 실패 사유
+}
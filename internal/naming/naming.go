@@ -0,0 +1,38 @@
+package naming
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// MaxResourceNameLength는 파생 리소스 이름의 최대 길이입니다.
+// Service 이름이나 VM 스펙의 볼륨 이름은 DNS label이라 63자가 상한이므로, 253자를 허용하는 리소스도 같은 기준을 씁니다.
+const MaxResourceNameLength = 63
+
+// 이름을 줄였을 때 충돌을 피하기 위해 붙이는 해시 길이
+const hashLength = 8
+
+// ResourceName은 prefix + base + suffix를 만들되, 63자를 넘으면 base를 잘라 전체 이름의 해시를 붙입니다.
+// 같은 입력에는 항상 같은 이름을 반환하므로 생성과 삭제가 같은 리소스를 가리킵니다.
+func ResourceName(prefix, base, suffix string) string {
+	name := prefix + base + suffix
+	if len(name) <= MaxResourceNameLength {
+		return name
+	}
+
+	sum := sha256.Sum256([]byte(name))
+	hash := hex.EncodeToString(sum[:])[:hashLength]
+
+	keep := MaxResourceNameLength - len(prefix) - len(suffix) - len(hash) - 1
+	if keep < 1 {
+		keep = 1
+	}
+	if keep > len(base) {
+		keep = len(base)
+	}
+	// DNS-1123 이름은 '-'로 끝날 수 없으므로 잘린 지점의 '-'는 제거
+	trimmed := strings.TrimRight(base[:keep], "-")
+
+	return prefix + trimmed + "-" + hash + suffix
+}
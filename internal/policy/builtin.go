@@ -0,0 +1,47 @@
+package policy
+
+import (
+	"fmt"
+	"vm-controller/internal/models"
+)
+
+// 플랫폼 리소스별 허용 권한 (동작 -> 권한)
+var platformRules = map[string]map[Action][]string{
+	ResourceAdmin: {
+		ActionRead:  {models.RoleAdmin, models.RoleAuditor}, // auditor는 관리자 화면 읽기 전용
+		ActionWrite: {models.RoleAdmin},
+	},
+	ResourceDebug: {
+		ActionRead:  {models.RoleAdmin},
+		ActionWrite: {models.RoleAdmin},
+	},
+	ResourceSandbox: {
+		ActionRead:  {models.RoleAdmin},
+		ActionWrite: {models.RoleAdmin},
+	},
+}
+
+// BuiltinEngine은 기본 규칙입니다.
+//   - 소유 리소스: 소유자만 모든 동작 가능 (관리자도 사용자 API로는 다른 사용자의 리소스를 다루지 않고 관리자 API를 사용)
+//   - 플랫폼 리소스: platformRules의 권한만 허용
+type BuiltinEngine struct{}
+
+func (BuiltinEngine) Authorize(subject Subject, action Action, resource Resource) error {
+	if resource.OwnerID != nil {
+		if *resource.OwnerID == subject.UserID {
+			return nil
+		}
+		return fmt.Errorf("%w: %s is owned by another user", ErrDenied, resource.Type)
+	}
+
+	rules, ok := platformRules[resource.Type]
+	if !ok {
+		return fmt.Errorf("%w: no rule for %s", ErrDenied, resource.Type)
+	}
+	for _, role := range rules[action] {
+		if subject.Role == role {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: role %q cannot %s %s", ErrDenied, subject.Role, action, resource.Type)
+}
@@ -0,0 +1,92 @@
+// Package policy는 리소스/동작 단위의 권한 판단을 한곳에서 담당합니다.
+// 컨트롤러는 UserID나 권한을 직접 비교하지 않고 middleware.Authorize / middleware.AuthorizeOwned를 통해 엔진에 묻습니다.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Action은 리소스에 대한 동작입니다. (HTTP 메서드에서 결정)
+type Action string
+
+const (
+	ActionRead  Action = "read"
+	ActionWrite Action = "write"
+)
+
+// 리소스 종류
+const (
+	// 사용자 소유 리소스 (소유자가 있음)
+	ResourceVM         = "vm" // 스냅샷, 추가 볼륨 등 VM 하위 리소스 포함
+	ResourceDeployment = "deployment"
+	ResourceDatabase   = "database"
+	ResourceOperation  = "operation"
+
+	// 플랫폼 리소스 (소유자가 없음)
+	ResourceAdmin   = "admin"   // 관리자 API
+	ResourceDebug   = "debug"   // 런타임 진단
+	ResourceSandbox = "sandbox" // 샌드박스 도구
+)
+
+// Subject는 요청한 사용자입니다.
+type Subject struct {
+	UserID uint
+	Role   string
+}
+
+// Resource는 판단 대상 리소스입니다. OwnerID가 nil이면 플랫폼 리소스입니다.
+type Resource struct {
+	Type    string
+	OwnerID *uint
+}
+
+// ErrDenied는 정책이 요청을 거부했음을 나타냅니다.
+var ErrDenied = errors.New("permission denied")
+
+// Engine은 권한 판단 엔진입니다. 허용하면 nil, 거부하면 ErrDenied를 감싼 에러를 반환합니다.
+// RegisterEngine으로 등록하고 AUTHZ_ENGINE으로 선택합니다. (기본값 builtin)
+type Engine interface {
+	Authorize(subject Subject, action Action, resource Resource) error
+}
+
+var (
+	engines   = map[string]Engine{"builtin": BuiltinEngine{}}
+	enginesMu sync.RWMutex
+)
+
+// RegisterEngine은 이름으로 권한 판단 엔진을 등록합니다.
+func RegisterEngine(name string, engine Engine) {
+	enginesMu.Lock()
+	defer enginesMu.Unlock()
+
+	engines[name] = engine
+}
+
+func engine() (Engine, error) {
+	name := os.Getenv("AUTHZ_ENGINE")
+	if name == "" {
+		name = "builtin"
+	}
+
+	enginesMu.RLock()
+	defer enginesMu.RUnlock()
+
+	engine, ok := engines[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown authorization engine: %s", name)
+	}
+	return engine, nil
+}
+
+// Authorize는 설정된 엔진으로 subject의 action을 판단합니다.
+// 엔진을 찾을 수 없으면 거부합니다. (fail closed)
+func Authorize(subject Subject, action Action, resource Resource) error {
+	engine, err := engine()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrDenied, err)
+	}
+	return engine.Authorize(subject, action, resource)
+}
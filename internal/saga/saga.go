@@ -0,0 +1,89 @@
+// Package saga는 여러 리소스(DB 레코드, 네임스페이스, 매니페스트)에 걸친 작업의 보상 단계를 관리합니다.
+// 각 단계가 성공할 때마다 되돌리는 방법(undo)을 등록하고, 작업이 끝나기 전에 실패하면 등록의 역순으로 실행합니다.
+//
+//	sg := saga.New("vm.create", "vm", name)
+//	defer sg.Rollback() // Complete 이후에는 아무것도 하지 않음
+//	...
+//	sg.Compensate("discard vm record", func() error { ... })
+//	...
+//	sg.Complete()
+package saga
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+	"vm-controller/internal/metrics"
+)
+
+var compensations = metrics.NewCounterVec("saga_compensations_total", "Compensation steps run after a failed multi-resource operation.", "saga", "result")
+
+type step struct {
+	name string
+	undo func() error
+}
+
+// Saga는 하나의 작업에 등록된 보상 단계입니다. 여러 goroutine에서 사용해도 안전합니다.
+type Saga struct {
+	name  string
+	attrs []any // 보상 로그에 함께 남길 속성 (key, value 순서)
+
+	mu        sync.Mutex
+	steps     []step
+	completed bool
+}
+
+// New는 name 작업의 Saga를 만듭니다. attrs는 slog 형식의 key/value 목록입니다.
+func New(name string, attrs ...any) *Saga {
+	return &Saga{name: name, attrs: attrs}
+}
+
+// Compensate는 방금 성공한 단계를 되돌리는 방법을 등록합니다.
+func (s *Saga) Compensate(name string, undo func() error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.steps = append(s.steps, step{name: name, undo: undo})
+}
+
+// Complete는 작업이 끝났음을 표시합니다. 이후의 Rollback은 보상 단계를 실행하지 않습니다.
+func (s *Saga) Complete() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.completed = true
+	s.steps = nil
+}
+
+// Rollback은 작업이 끝나지 않았으면 등록된 보상 단계를 역순으로 모두 실행하고 실패한 단계의 에러를 반환합니다.
+// 한 단계가 실패해도 나머지 단계는 계속 실행하며, 같은 단계를 두 번 실행하지 않습니다.
+func (s *Saga) Rollback() []error {
+	s.mu.Lock()
+	if s.completed || len(s.steps) == 0 {
+		s.mu.Unlock()
+		return nil
+	}
+	steps := s.steps
+	s.steps = nil
+	s.mu.Unlock()
+
+	slog.Warn("saga rolling back", append([]any{"saga", s.name, "steps", len(steps)}, s.attrs...)...)
+
+	var errs []error
+	for i := len(steps) - 1; i >= 0; i-- {
+		started := time.Now()
+		err := steps[i].undo()
+
+		attrs := append([]any{"saga", s.name, "step", steps[i].name, "elapsed_ms", time.Since(started).Milliseconds()}, s.attrs...)
+		if err != nil {
+			errs = append(errs, err)
+			compensations.Inc(s.name, "failed")
+			slog.Error("saga compensation failed", append(attrs, "error", err.Error())...)
+			continue
+		}
+		compensations.Inc(s.name, "succeeded")
+		slog.Info("saga compensation", attrs...)
+	}
+
+	return errs
+}
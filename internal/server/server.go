@@ -0,0 +1,119 @@
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+
+	"vm-controller/internal/config"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// Run 함수는 설정에 따라 HTTP 또는 HTTPS로 handler를 제공합니다.
+//   - TLS_CERT_FILE/TLS_KEY_FILE: 지정된 인증서로 HTTPS 제공
+//   - TLS_AUTOCERT_DOMAINS: Let's Encrypt 인증서를 자동 발급/갱신하여 HTTPS 제공
+//   - 둘 다 없으면 HTTP (인그레스에서 TLS 종료)
+func Run(cfg *config.Config, handler http.Handler) error {
+	addr := fmt.Sprintf(":%s", cfg.Port)
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
+
+	if !cfg.TLSEnabled() {
+		// 인그레스가 HTTP/2로 업스트림에 연결하는 경우를 위한 h2c
+		if cfg.H2C {
+			srv.Protocols = new(http.Protocols)
+			srv.Protocols.SetHTTP1(true)
+			srv.Protocols.SetUnencryptedHTTP2(true)
+		}
+
+		log.Printf("Starting HTTP server on port %s (h2c: %v)", cfg.Port, cfg.H2C)
+		return srv.ListenAndServe()
+	}
+
+	srv.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if len(cfg.AutocertDomains) > 0 {
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.AutocertDomains...),
+			Cache:      autocert.DirCache(cfg.AutocertCacheDir),
+		}
+		srv.TLSConfig.GetCertificate = manager.GetCertificate
+		srv.TLSConfig.NextProtos = append([]string{"h2", "http/1.1"}, srv.TLSConfig.NextProtos...)
+
+		// ACME HTTP-01 챌린지 응답 + 나머지 요청은 HTTPS로 리다이렉트
+		go serveRedirect(cfg, manager.HTTPHandler(nil))
+
+		log.Printf("Starting HTTPS server on port %s (autocert: %v)", cfg.Port, cfg.AutocertDomains)
+		return srv.ListenAndServeTLS("", "")
+	}
+
+	go serveRedirect(cfg, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, httpsURL(cfg, r), http.StatusMovedPermanently)
+	}))
+
+	log.Printf("Starting HTTPS server on port %s", cfg.Port)
+	return srv.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+}
+
+// RunAdmin 함수는 ADMIN_PORT에서 내부 관리자 리스너를 제공합니다. (ADMIN_PORT가 비어 있으면 아무것도 하지 않음)
+// pprof 프로파일 수집은 오래 걸릴 수 있으므로 WriteTimeout을 두지 않습니다.
+func RunAdmin(cfg *config.Config, handler http.Handler) {
+	if cfg.AdminPort == "" {
+		return
+	}
+
+	srv := &http.Server{
+		Addr:              fmt.Sprintf(":%s", cfg.AdminPort),
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
+
+	log.Printf("Starting admin server on port %s", cfg.AdminPort)
+	if err := srv.ListenAndServe(); err != nil {
+		log.Printf("Admin server stopped: %v", err)
+	}
+}
+
+// serveRedirect는 HTTP_REDIRECT_PORT에서 평문 HTTP 요청을 처리합니다. (실패해도 HTTPS 서버는 유지)
+func serveRedirect(cfg *config.Config, handler http.Handler) {
+	if cfg.HTTPRedirectPort == "" || cfg.HTTPRedirectPort == cfg.Port {
+		return
+	}
+
+	redirect := &http.Server{
+		Addr:              fmt.Sprintf(":%s", cfg.HTTPRedirectPort),
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+
+	log.Printf("Starting HTTP redirect server on port %s", cfg.HTTPRedirectPort)
+	if err := redirect.ListenAndServe(); err != nil {
+		log.Printf("HTTP redirect server stopped: %v", err)
+	}
+}
+
+// httpsURL은 요청 URL을 HTTPS 포트 기준으로 변환합니다.
+func httpsURL(cfg *config.Config, r *http.Request) string {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if cfg.Port != "443" {
+		host = net.JoinHostPort(host, cfg.Port)
+	}
+	return "https://" + host + r.URL.RequestURI()
+}
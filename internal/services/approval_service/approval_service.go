@@ -0,0 +1,119 @@
+package approvalservice
+
+import (
+	"errors"
+	"vm-controller/internal/db"
+	"vm-controller/internal/models"
+	notificationservice "vm-controller/internal/services/notification_service"
+
+	"gorm.io/gorm"
+)
+
+type ApprovalService struct {
+	notificationService *notificationservice.NotificationService
+}
+
+func NewApprovalService(notificationService *notificationservice.NotificationService) *ApprovalService {
+	return &ApprovalService{notificationService: notificationService}
+}
+
+var (
+	ErrApprovalExists     = errors.New("an approval request for this VM is already pending")
+	ErrApprovalNotFound   = errors.New("approval request not found")
+	ErrApprovalNotPending = errors.New("approval request has already been reviewed")
+)
+
+// CreateApproval은 승인 대기 요청을 저장합니다. 같은 사용자가 같은 이름으로 대기 중인 요청이 있으면 ErrApprovalExists를 반환합니다.
+func (s *ApprovalService) CreateApproval(approval *models.VmApproval) error {
+	db := db.GetDB()
+
+	var count int64
+	if err := db.Model(&models.VmApproval{}).
+		Where("user_id = ? AND vm_name = ? AND status = ?", approval.UserID, approval.VmName, models.ApprovalStatusPending).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrApprovalExists
+	}
+
+	approval.Status = models.ApprovalStatusPending
+	return db.Create(approval).Error
+}
+
+// FetchApproval은 승인 요청을 요청자 정보와 함께 조회합니다. (비밀번호 해시 제외)
+func (s *ApprovalService) FetchApproval(id uint) (*models.VmApproval, error) {
+	db := db.GetDB()
+
+	var approval models.VmApproval
+	if err := db.Preload("User").First(&approval, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrApprovalNotFound
+		}
+		return nil, err
+	}
+	approval.User.PasswordHash = ""
+
+	return &approval, nil
+}
+
+// FetchApprovals는 승인 요청 목록을 최신순으로 조회합니다. status가 비어 있으면 모든 상태를 반환합니다.
+func (s *ApprovalService) FetchApprovals(status string) ([]models.VmApproval, error) {
+	db := db.GetDB()
+
+	var approvals []models.VmApproval
+
+	query := db.Preload("User")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Order("created_at desc").Find(&approvals).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range approvals {
+		approvals[i].User.PasswordHash = ""
+	}
+
+	return approvals, nil
+}
+
+// FetchUserApprovals는 사용자가 요청한 승인 목록을 최신순으로 조회합니다.
+func (s *ApprovalService) FetchUserApprovals(userId uint) ([]models.VmApproval, error) {
+	db := db.GetDB()
+
+	var approvals []models.VmApproval
+
+	if err := db.Where("user_id = ?", userId).Order("created_at desc").Find(&approvals).Error; err != nil {
+		return nil, err
+	}
+
+	return approvals, nil
+}
+
+// Review는 대기 중인 요청을 승인/거절 상태로 바꿉니다.
+// 대기 상태일 때만 변경하므로 두 관리자가 동시에 처리해도 한 번만 성공하고, 나머지는 ErrApprovalNotPending을 받습니다.
+func (s *ApprovalService) Review(id uint, status models.EnumApprovalStatus, reviewerId uint, reason string) error {
+	db := db.GetDB()
+
+	result := db.Model(&models.VmApproval{}).
+		Where("id = ? AND status = ?", id, models.ApprovalStatusPending).
+		Updates(map[string]interface{}{"status": status, "reviewer_id": reviewerId, "reason": reason})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrApprovalNotPending
+	}
+
+	return nil
+}
+
+// MarkFailed는 승인된 요청의 VM 생성이 실패했을 때 사유를 기록합니다.
+func (s *ApprovalService) MarkFailed(id uint, reason string) error {
+	db := db.GetDB()
+
+	return db.Model(&models.VmApproval{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"status": models.ApprovalStatusFailed, "reason": reason}).Error
+}
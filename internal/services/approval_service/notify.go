@@ -0,0 +1,121 @@
+package approvalservice
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+	"vm-controller/internal/db"
+	"vm-controller/internal/models"
+	notificationservice "vm-controller/internal/services/notification_service"
+)
+
+var webhookClient = &http.Client{Timeout: 5 * time.Second}
+
+// ApprovalEvent는 승인 웹훅으로 전송되는 본문입니다.
+// 메일/메신저 전달은 웹훅을 받는 쪽(메일 게이트웨이, 슬랙 워크플로 등)에서 처리합니다.
+type ApprovalEvent struct {
+	Event     string `json:"event"` // vm.approval.requested
+	ID        uint   `json:"id"`
+	VmName    string `json:"vm_name"`
+	VmFlavor  string `json:"vm_flavor"`
+	Requester string `json:"requester"`
+	StudentID string `json:"student_id"`
+	Email     string `json:"email"`
+	CreatedAt string `json:"created_at"`
+	Title     string `json:"title"`   // 알림 제목 (approval.requested 템플릿, 기본 언어)
+	Message   string `json:"message"` // 알림 본문
+}
+
+// NotifyReviewers는 새 승인 요청을 관리자에게 알립니다.
+// 모든 관리자에게 인앱 알림을 만들고, APPROVAL_WEBHOOK_URL이 설정되어 있으면 웹훅을 백그라운드로 전송합니다.
+// 알림 실패로 요청 접수가 실패하지 않도록 에러는 로그만 남깁니다.
+func (s *ApprovalService) NotifyReviewers(approval *models.VmApproval, requester *models.User) {
+	db := db.GetDB()
+
+	var admins []models.User
+	if err := db.Where("role = ?", models.RoleAdmin).Find(&admins).Error; err != nil {
+		slog.Warn("approval: failed to fetch admins", "approval_id", approval.ID, "error", err)
+	}
+
+	notificationService := s.notificationService
+	data := notificationservice.Data{"requester": requester.Username, "student_id": requester.UserStudentId, "flavor": approval.VmFlavor, "vm": approval.VmName}
+	for _, admin := range admins {
+		if err := notificationService.NotifyTemplate(admin.ID, "approval.requested", data); err != nil {
+			slog.Warn("approval: failed to notify admin", "admin_id", admin.ID, "approval_id", approval.ID, "error", err)
+		}
+	}
+
+	url := os.Getenv("APPROVAL_WEBHOOK_URL")
+	if url == "" {
+		return
+	}
+
+	// 웹훅을 받아 메일/메신저로 전달하는 쪽도 같은 문구를 쓰도록 기본 언어로 렌더링한 문구를 함께 전송
+	title, message, err := notificationService.Render("approval.requested", "", data)
+	if err != nil {
+		slog.Warn("approval: failed to render webhook message", "approval_id", approval.ID, "error", err)
+	}
+
+	event := ApprovalEvent{
+		Event:     "vm.approval.requested",
+		ID:        approval.ID,
+		VmName:    approval.VmName,
+		VmFlavor:  approval.VmFlavor,
+		Requester: requester.Username,
+		StudentID: requester.UserStudentId,
+		Email:     requester.Email,
+		CreatedAt: approval.CreatedAt.Format(time.RFC3339),
+		Title:     title,
+		Message:   message,
+	}
+	go func() {
+		if err := postWebhook(url, event); err != nil {
+			slog.Warn("approval: failed to send webhook", "approval_id", approval.ID, "error", err)
+		}
+	}()
+}
+
+// NotifyRequester는 승인/거절/생성 실패 결과를 요청한 사용자에게 인앱 알림으로 전달합니다.
+func (s *ApprovalService) NotifyRequester(approval *models.VmApproval, key string, data notificationservice.Data) {
+	if err := s.notificationService.NotifyTemplate(approval.UserID, key, data); err != nil {
+		slog.Warn("approval: failed to notify requester", "user_id", approval.UserID, "approval_id", approval.ID, "error", err)
+	}
+}
+
+// postWebhook은 이벤트를 JSON으로 전송합니다.
+// APPROVAL_WEBHOOK_SECRET이 설정되어 있으면 본문의 HMAC-SHA256 서명을 X-Approval-Signature 헤더에 담습니다.
+func postWebhook(url string, event ApprovalEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret := os.Getenv("APPROVAL_WEBHOOK_SECRET"); secret != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		req.Header.Set("X-Approval-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
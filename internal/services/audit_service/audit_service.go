@@ -0,0 +1,58 @@
+package auditservice
+
+import (
+	"vm-controller/internal/db"
+	"vm-controller/internal/models"
+)
+
+type AuditService struct {
+}
+
+func NewAuditService() *AuditService {
+	return &AuditService{}
+}
+
+// Record는 감사 로그를 추가합니다.
+func (s *AuditService) Record(actorId *uint, action, target, detail string) error {
+	db := db.GetDB()
+
+	log := models.AuditLog{
+		ActorID: actorId,
+		Action:  action,
+		Target:  target,
+		Detail:  detail,
+	}
+
+	return db.Create(&log).Error
+}
+
+// FetchAuditLogs는 대상(target)의 감사 로그를 최신순으로 반환합니다. target이 비어 있으면 전체를 반환합니다.
+func (s *AuditService) FetchAuditLogs(target string, limit int) ([]models.AuditLog, error) {
+	db := db.GetDB()
+
+	var logs []models.AuditLog
+
+	query := db.Order("id desc").Limit(limit)
+	if target != "" {
+		query = query.Where("target = ?", target)
+	}
+
+	if err := query.Find(&logs).Error; err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+// FetchAuditLogsByAction은 action 접두사(예: "security.")가 일치하는 감사 로그를 최신순으로 반환합니다.
+func (s *AuditService) FetchAuditLogsByAction(prefix string, limit int) ([]models.AuditLog, error) {
+	db := db.GetDB()
+
+	var logs []models.AuditLog
+
+	if err := db.Where("action LIKE ?", prefix+"%").Order("id desc").Limit(limit).Find(&logs).Error; err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
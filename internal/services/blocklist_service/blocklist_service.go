@@ -0,0 +1,186 @@
+package blocklistservice
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// 기본값 (환경 변수로 변경)
+const (
+	defaultRateWindow        = time.Minute      // INTERCEPT_RATE_WINDOW
+	defaultBanThreshold      = 5                // INTERCEPT_BAN_THRESHOLD: 윈도 안에서 차단된 요청 수
+	defaultOffenseWindow     = 10 * time.Minute // INTERCEPT_OFFENSE_WINDOW
+	defaultBanDuration       = 15 * time.Minute // INTERCEPT_BAN_DURATION
+	defaultOffenderRetention = 24 * time.Hour   // INTERCEPT_OFFENDER_RETENTION
+	defaultCacheTTL          = 5 * time.Second  // BLOCKLIST_CACHE_TTL
+	defaultKeyPrefix         = "vm-controller:intercept:"
+)
+
+// 만료 항목을 정리하기 시작하는 맵 크기
+const sweepThreshold = 4096
+
+const (
+	ReasonRateLimit = "Rate Limit Exceeded"
+	ReasonRepeated  = "Repeated Violations"
+)
+
+// BlocklistService는 인터셉터의 IP 단위 요청 제한과 일시 차단, API 서버의 요청 제한(토큰 버킷)을 관리합니다.
+// 상태는 Store(기본 메모리, REDIS_URL 설정 시 Redis)에 저장되므로 Traefik 뒤의 인터셉터 여러 대가 같은 차단 목록을 봅니다.
+type BlocklistService struct {
+	store Store
+
+	rateLimit     int64 // 윈도당 IP별 최대 요청 수 (0이면 제한 없음)
+	rateWindow    time.Duration
+	banThreshold  int64 // 차단까지 허용하는 보안 검사 위반 수 (0이면 자동 차단 안 함)
+	offenseWindow time.Duration
+	banDuration   time.Duration
+}
+
+func NewBlocklistService(store Store) *BlocklistService {
+	return &BlocklistService{
+		store: store,
+
+		rateLimit:     int64(intEnv("INTERCEPT_RATE_LIMIT", 0)),
+		rateWindow:    durationEnv("INTERCEPT_RATE_WINDOW", defaultRateWindow),
+		banThreshold:  int64(intEnv("INTERCEPT_BAN_THRESHOLD", defaultBanThreshold)),
+		offenseWindow: durationEnv("INTERCEPT_OFFENSE_WINDOW", defaultOffenseWindow),
+		banDuration:   durationEnv("INTERCEPT_BAN_DURATION", defaultBanDuration),
+	}
+}
+
+// NewStoreFromEnv는 REDIS_URL이 있으면 Redis 저장소, 없으면 메모리 저장소를 만듭니다.
+// REDIS_URL이 잘못되었으면 로그를 남기고 메모리 저장소를 사용합니다. (인터셉터는 계속 동작)
+func NewStoreFromEnv() Store {
+	retention := durationEnv("INTERCEPT_OFFENDER_RETENTION", defaultOffenderRetention)
+
+	rawURL := os.Getenv("REDIS_URL")
+	if rawURL == "" {
+		return newMemoryStore(retention)
+	}
+	client, err := newRedisClient(rawURL)
+	if err != nil {
+		log.Printf("Blocklist: %v, using local state", err)
+		return newMemoryStore(retention)
+	}
+
+	prefix := os.Getenv("REDIS_KEY_PREFIX")
+	if prefix == "" {
+		prefix = defaultKeyPrefix
+	}
+	return newRedisStore(client, prefix, retention, durationEnv("BLOCKLIST_CACHE_TTL", defaultCacheTTL))
+}
+
+// Check는 ip가 차단 중이면 차단 정보를 반환합니다.
+func (s *BlocklistService) Check(ctx context.Context, ip string) *Ban {
+	ban, err := s.store.Banned(ctx, ip)
+	if err != nil {
+		return nil
+	}
+	return ban
+}
+
+// CountRequest는 ip의 요청 수를 세고, INTERCEPT_RATE_LIMIT를 넘으면 INTERCEPT_BAN_DURATION 동안 차단합니다.
+// 이 요청으로 새로 차단되었으면 created가 true입니다.
+func (s *BlocklistService) CountRequest(ctx context.Context, ip string) (ban *Ban, created bool) {
+	if s.rateLimit <= 0 {
+		return nil, false
+	}
+
+	count, err := s.store.Incr(ctx, "rate:"+ip, s.rateWindow)
+	if err != nil || count <= s.rateLimit {
+		return nil, false
+	}
+
+	ban = &Ban{IP: ip, Reason: ReasonRateLimit, Until: time.Now().Add(s.banDuration)}
+	// 동시에 들어온 초과 요청은 같은 차단을 다시 저장하지 않음
+	if count == s.rateLimit+1 {
+		s.store.Ban(ctx, *ban)
+		return ban, true
+	}
+	return ban, false
+}
+
+// TakeToken은 API 요청 제한용 토큰 버킷에서 토큰 하나를 꺼냅니다. (limit개를 per 동안 균등하게 충전, 최대 limit개)
+// 허용되지 않으면 다시 시도할 수 있을 때까지의 시간을 반환하며, 저장소 에러 시에는 요청을 막지 않습니다.
+func (s *BlocklistService) TakeToken(ctx context.Context, key string, limit int, per time.Duration) (retryAfter time.Duration, allowed bool) {
+	if limit <= 0 || per <= 0 {
+		return 0, true
+	}
+
+	wait, err := s.store.TakeToken(ctx, "api:"+key, float64(limit)/per.Seconds(), limit)
+	if err != nil || wait <= 0 {
+		return 0, true
+	}
+	return wait, false
+}
+
+// RecordOffense는 보안 검사에 걸린 요청을 최근 위반 IP로 기록하고,
+// INTERCEPT_OFFENSE_WINDOW 안에 INTERCEPT_BAN_THRESHOLD번 걸리면 ip를 차단합니다.
+func (s *BlocklistService) RecordOffense(ctx context.Context, ip, reason string) (ban *Ban, created bool) {
+	s.store.RecordOffender(ctx, ip, reason, time.Now())
+
+	if s.banThreshold <= 0 {
+		return nil, false
+	}
+	count, err := s.store.Incr(ctx, "offense:"+ip, s.offenseWindow)
+	if err != nil || count != s.banThreshold {
+		return nil, false
+	}
+
+	ban = &Ban{IP: ip, Reason: ReasonRepeated + ": " + reason, Until: time.Now().Add(s.banDuration)}
+	s.store.Ban(ctx, *ban)
+	return ban, true
+}
+
+// Ban은 관리자가 ip를 duration 동안 차단합니다.
+func (s *BlocklistService) Ban(ctx context.Context, ip, reason string, duration time.Duration) (*Ban, error) {
+	if duration <= 0 {
+		duration = s.banDuration
+	}
+	ban := &Ban{IP: ip, Reason: reason, Until: time.Now().Add(duration)}
+	if err := s.store.Ban(ctx, *ban); err != nil {
+		return nil, err
+	}
+	return ban, nil
+}
+
+func (s *BlocklistService) Unban(ctx context.Context, ip string) error {
+	return s.store.Unban(ctx, ip)
+}
+
+func (s *BlocklistService) Bans(ctx context.Context) ([]Ban, error) {
+	return s.store.Bans(ctx)
+}
+
+func (s *BlocklistService) RecentOffenders(ctx context.Context, limit int) ([]Offender, error) {
+	return s.store.RecentOffenders(ctx, limit)
+}
+
+func intEnv(key string, defaultValue int) int {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return defaultValue
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 {
+		log.Printf("Invalid %s: %q, using default %d", key, raw, defaultValue)
+		return defaultValue
+	}
+	return value
+}
+
+func durationEnv(key string, defaultValue time.Duration) time.Duration {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return defaultValue
+	}
+	value, err := time.ParseDuration(raw)
+	if err != nil || value <= 0 {
+		log.Printf("Invalid %s: %q, using default %s", key, raw, defaultValue)
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,298 @@
+package blocklistservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"vm-controller/internal/metrics"
+
+	redis "github.com/redis/go-redis/v9"
+	redislogging "github.com/redis/go-redis/v9/logging"
+)
+
+func init() {
+	// 연결 실패는 fallback이 간격을 두고 기록하므로 go-redis 자체 로그(명령마다 출력)는 끔
+	redislogging.Disable()
+}
+
+// Redis 장애 로그 최소 간격 (요청마다 같은 에러를 남기지 않도록)
+const redisErrorLogInterval = 30 * time.Second
+
+// Redis 연결/명령 제한 시간 (Redis 장애 시 로컬 저장소로 넘어가기까지 기다리는 최대 시간)
+const redisTimeout = 2 * time.Second
+
+var storeErrorsTotal = metrics.NewCounterVec("blocklist_store_errors_total", "Interceptor state operations that failed against Redis and fell back to the local store.", "operation")
+
+// 스크립트는 EVALSHA로 실행하고, 서버에 캐시되어 있지 않으면 EVAL로 다시 보냅니다. (redis.Script)
+
+// 고정 윈도 카운터: 첫 증가 시에만 만료 시간을 설정
+var incrScript = redis.NewScript(`local n = redis.call('INCR', KEYS[1])
+if n == 1 then redis.call('PEXPIRE', KEYS[1], ARGV[1]) end
+return n`)
+
+// 토큰 버킷: 마지막 갱신 이후 충전된 토큰을 더하고 하나를 꺼냄. 꺼내지 못하면 기다릴 시간(ms)을 반환
+// 버킷이 가득 차는 시간이 지나면 키가 만료되어 새 버킷과 같아짐
+var tokenBucketScript = redis.NewScript(`local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or burst
+local ts = tonumber(state[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
+local wait = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+else
+  wait = math.ceil((1 - tokens) / rate)
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate))
+return wait`)
+
+// 차단 기록 + 목록 인덱스 (만료된 인덱스 항목 정리)
+var banScript = redis.NewScript(`redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+redis.call('ZADD', KEYS[2], ARGV[3], ARGV[4])
+redis.call('ZREMRANGEBYSCORE', KEYS[2], '-inf', ARGV[5])
+return 1`)
+
+// 위반 기록: 마지막 위반 후 보존 기간이 지나면 누적 횟수와 함께 사라짐
+var offenderScript = redis.NewScript(`redis.call('HSET', KEYS[1], 'reason', ARGV[1], 'last_seen', ARGV[2])
+redis.call('HINCRBY', KEYS[1], 'count', 1)
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+redis.call('ZADD', KEYS[2], ARGV[2], ARGV[4])
+redis.call('ZREMRANGEBYSCORE', KEYS[2], '-inf', tonumber(ARGV[2]) - tonumber(ARGV[3]))
+return 1`)
+
+type cachedBan struct {
+	ban       *Ban
+	expiresAt time.Time
+}
+
+// redisStore는 인터셉터 여러 대가 공유하는 Redis 저장소입니다. 재시작해도 차단 상태가 유지됩니다.
+// 차단 여부는 cacheTTL 동안 로컬에 캐시하여 요청마다 Redis를 조회하지 않으며,
+// Redis에 접근할 수 없으면 로컬 메모리 저장소로 계속 동작합니다. (이 인스턴스가 기록한 차단은 로컬에도 유지)
+type redisStore struct {
+	client    redis.UniversalClient
+	prefix    string
+	retention time.Duration
+	cacheTTL  time.Duration
+	local     *memoryStore
+
+	mu           sync.Mutex
+	cache        map[string]cachedBan
+	lastErrorLog time.Time
+}
+
+// newRedisClient는 redis://[user:password@]host:port/db 또는 rediss://(TLS) 주소로 클라이언트를 만듭니다.
+// 장애 시 로컬 저장소로 넘어가므로 명령을 재시도하지 않습니다.
+func newRedisClient(rawURL string) (*redis.Client, error) {
+	opts, err := redis.ParseURL(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid REDIS_URL: %v", err)
+	}
+	opts.DialTimeout = redisTimeout
+	opts.ReadTimeout = redisTimeout
+	opts.WriteTimeout = redisTimeout
+	opts.MaxRetries = -1
+	opts.DialerRetries = 1
+	return redis.NewClient(opts), nil
+}
+
+func newRedisStore(client redis.UniversalClient, prefix string, retention, cacheTTL time.Duration) *redisStore {
+	return &redisStore{
+		client:    client,
+		prefix:    prefix,
+		retention: retention,
+		cacheTTL:  cacheTTL,
+		local:     newMemoryStore(retention),
+		cache:     map[string]cachedBan{},
+	}
+}
+
+func (s *redisStore) key(parts ...string) string {
+	key := s.prefix
+	for _, part := range parts {
+		key += part
+	}
+	return key
+}
+
+// fallback은 Redis 에러를 기록하고 로컬 저장소로 대신 처리하도록 알립니다.
+func (s *redisStore) fallback(operation string, err error) {
+	storeErrorsTotal.Inc(operation)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if time.Since(s.lastErrorLog) >= redisErrorLogInterval {
+		s.lastErrorLog = time.Now()
+		log.Printf("Blocklist: redis %s failed, using local state: %v", operation, err)
+	}
+}
+
+func (s *redisStore) Incr(ctx context.Context, key string, window time.Duration) (int64, error) {
+	count, err := incrScript.Run(ctx, s.client, []string{s.key("counter:", key)}, window.Milliseconds()).Int64()
+	if err == nil {
+		return count, nil
+	}
+	s.fallback("incr", err)
+	return s.local.Incr(ctx, key, window)
+}
+
+// TakeToken은 인스턴스 시각을 기준으로 충전량을 계산하므로 인스턴스 간 시계가 맞아야 합니다. (NTP)
+func (s *redisStore) TakeToken(ctx context.Context, key string, rate float64, burst int) (time.Duration, error) {
+	// 스크립트에는 ms당 충전량을 전달
+	wait, err := tokenBucketScript.Run(ctx, s.client, []string{s.key("bucket:", key)},
+		strconv.FormatFloat(rate/1000, 'g', -1, 64), burst, time.Now().UnixMilli()).Int64()
+	if err == nil {
+		return time.Duration(wait) * time.Millisecond, nil
+	}
+	s.fallback("take", err)
+	return s.local.TakeToken(ctx, key, rate, burst)
+}
+
+func (s *redisStore) Ban(ctx context.Context, ban Ban) error {
+	s.local.Ban(ctx, ban)
+	s.cacheBan(ban.IP, &ban)
+
+	ttl := time.Until(ban.Until)
+	if ttl <= 0 {
+		return nil
+	}
+	value, err := json.Marshal(ban)
+	if err != nil {
+		return err
+	}
+	if err := banScript.Run(ctx, s.client, []string{s.key("ban:", ban.IP), s.key("bans")},
+		string(value), ttl.Milliseconds(), ban.Until.UnixMilli(), ban.IP, time.Now().UnixMilli()).Err(); err != nil {
+		s.fallback("ban", err)
+	}
+	return nil
+}
+
+func (s *redisStore) Banned(ctx context.Context, ip string) (*Ban, error) {
+	s.mu.Lock()
+	cached, ok := s.cache[ip]
+	s.mu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		if cached.ban != nil && time.Now().Before(cached.ban.Until) {
+			return cached.ban, nil
+		}
+		return nil, nil
+	}
+
+	value, err := s.client.Get(ctx, s.key("ban:", ip)).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		s.fallback("get", err)
+		return s.local.Banned(ctx, ip)
+	}
+
+	var ban *Ban
+	if err == nil {
+		ban = &Ban{}
+		if err := json.Unmarshal([]byte(value), ban); err != nil || !time.Now().Before(ban.Until) {
+			ban = nil
+		}
+	}
+	s.cacheBan(ip, ban)
+	return ban, nil
+}
+
+func (s *redisStore) cacheBan(ip string, ban *Ban) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.cache[ip] = cachedBan{ban: ban, expiresAt: now.Add(s.cacheTTL)}
+	if len(s.cache) > sweepThreshold {
+		for k, c := range s.cache {
+			if !now.Before(c.expiresAt) {
+				delete(s.cache, k)
+			}
+		}
+	}
+}
+
+// Unban은 다른 인스턴스에는 로컬 캐시가 만료된 뒤(cacheTTL 이내) 반영됩니다.
+func (s *redisStore) Unban(ctx context.Context, ip string) error {
+	s.local.Unban(ctx, ip)
+	s.mu.Lock()
+	delete(s.cache, ip)
+	s.mu.Unlock()
+
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, s.key("ban:", ip))
+		pipe.ZRem(ctx, s.key("bans"), ip)
+		return nil
+	})
+	return err
+}
+
+func (s *redisStore) Bans(ctx context.Context) ([]Ban, error) {
+	ips, err := s.client.ZRevRangeByScore(ctx, s.key("bans"), &redis.ZRangeBy{
+		Max: "+inf",
+		Min: strconv.FormatInt(time.Now().UnixMilli(), 10),
+	}).Result()
+	if err != nil {
+		s.fallback("bans", err)
+		return s.local.Bans(ctx)
+	}
+
+	bans := []Ban{}
+	for _, ip := range ips {
+		// 해제된 차단은 인덱스에만 남아 있을 수 있으므로 차단 기록으로 확인
+		ban, err := s.Banned(ctx, ip)
+		if err != nil {
+			return nil, err
+		}
+		if ban != nil {
+			bans = append(bans, *ban)
+		}
+	}
+	return bans, nil
+}
+
+func (s *redisStore) RecordOffender(ctx context.Context, ip, reason string, at time.Time) error {
+	if err := offenderScript.Run(ctx, s.client, []string{s.key("offender:", ip), s.key("offenders")},
+		reason, at.UnixMilli(), s.retention.Milliseconds(), ip).Err(); err != nil {
+		s.fallback("offender", err)
+		return s.local.RecordOffender(ctx, ip, reason, at)
+	}
+	return nil
+}
+
+func (s *redisStore) RecentOffenders(ctx context.Context, limit int) ([]Offender, error) {
+	since := time.Now().Add(-s.retention).UnixMilli()
+	ips, err := s.client.ZRevRangeByScore(ctx, s.key("offenders"), &redis.ZRangeBy{
+		Max:   "+inf",
+		Min:   strconv.FormatInt(since, 10),
+		Count: int64(limit), // 0이면 제한 없음
+	}).Result()
+	if err != nil {
+		s.fallback("offenders", err)
+		return s.local.RecentOffenders(ctx, limit)
+	}
+
+	offenders := []Offender{}
+	for _, ip := range ips {
+		var fields struct {
+			Reason   string `redis:"reason"`
+			Count    int64  `redis:"count"`
+			LastSeen int64  `redis:"last_seen"`
+		}
+		if err := s.client.HMGet(ctx, s.key("offender:", ip), "reason", "count", "last_seen").Scan(&fields); err != nil {
+			return nil, err
+		}
+		// 보존 기간이 지나 기록은 만료되고 인덱스에만 남은 항목
+		if fields.LastSeen == 0 {
+			continue
+		}
+		offenders = append(offenders, Offender{IP: ip, LastReason: fields.Reason, Count: fields.Count, LastSeen: time.UnixMilli(fields.LastSeen)})
+	}
+	return offenders, nil
+}
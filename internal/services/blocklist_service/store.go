@@ -0,0 +1,213 @@
+package blocklistservice
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Ban은 인터셉터가 일시적으로 차단한 IP입니다.
+type Ban struct {
+	IP     string    `json:"ip"`
+	Reason string    `json:"reason"`
+	Until  time.Time `json:"until"`
+}
+
+// Offender는 최근 보안 검사에 걸린 IP와 누적 횟수입니다. (보존 기간 동안 유지)
+type Offender struct {
+	IP         string    `json:"ip"`
+	LastReason string    `json:"last_reason"`
+	Count      int64     `json:"count"`
+	LastSeen   time.Time `json:"last_seen"`
+}
+
+// Store는 인터셉터의 동적 상태(요청 카운터, 일시 차단, 최근 위반 IP) 저장소입니다.
+// 단일 인스턴스는 메모리 저장소로 충분하고, 인터셉터 여러 대가 같은 상태를 봐야 하면 Redis 저장소를 사용합니다.
+type Store interface {
+	// Incr는 key의 고정 윈도 카운터를 1 올리고 현재 값을 반환합니다. 첫 증가 시점부터 window가 지나면 초기화됩니다.
+	Incr(ctx context.Context, key string, window time.Duration) (int64, error)
+	// TakeToken은 key의 토큰 버킷(초당 rate개 충전, 최대 burst개)에서 토큰 하나를 꺼냅니다.
+	// 토큰이 없으면 꺼내지 않고 다음 토큰까지 기다릴 시간을 반환합니다. (0이면 허용)
+	TakeToken(ctx context.Context, key string, rate float64, burst int) (time.Duration, error)
+
+	Ban(ctx context.Context, ban Ban) error
+	Banned(ctx context.Context, ip string) (*Ban, error) // 차단 중이 아니면 nil
+	Unban(ctx context.Context, ip string) error
+	Bans(ctx context.Context) ([]Ban, error)
+
+	RecordOffender(ctx context.Context, ip, reason string, at time.Time) error
+	RecentOffenders(ctx context.Context, limit int) ([]Offender, error) // 최근에 본 순서
+}
+
+type memoryCounter struct {
+	count     int64
+	expiresAt time.Time
+}
+
+type memoryBucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+// memoryStore는 프로세스 메모리 저장소입니다. 재시작하면 상태가 사라지며 인스턴스 간에 공유되지 않습니다.
+type memoryStore struct {
+	mu        sync.Mutex
+	retention time.Duration // 위반 IP 보존 기간
+	counters  map[string]memoryCounter
+	buckets   map[string]memoryBucket
+	bans      map[string]Ban
+	offenders map[string]Offender
+}
+
+func newMemoryStore(retention time.Duration) *memoryStore {
+	return &memoryStore{
+		retention: retention,
+		counters:  map[string]memoryCounter{},
+		buckets:   map[string]memoryBucket{},
+		bans:      map[string]Ban{},
+		offenders: map[string]Offender{},
+	}
+}
+
+func (s *memoryStore) Incr(_ context.Context, key string, window time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	counter, ok := s.counters[key]
+	if !ok || !now.Before(counter.expiresAt) {
+		counter = memoryCounter{expiresAt: now.Add(window)}
+	}
+	counter.count++
+	s.counters[key] = counter
+
+	if len(s.counters) > sweepThreshold {
+		for k, c := range s.counters {
+			if !now.Before(c.expiresAt) {
+				delete(s.counters, k)
+			}
+		}
+	}
+
+	return counter.count, nil
+}
+
+func (s *memoryStore) TakeToken(_ context.Context, key string, rate float64, burst int) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = memoryBucket{tokens: float64(burst), updatedAt: now}
+	}
+	bucket.tokens = math.Min(float64(burst), bucket.tokens+now.Sub(bucket.updatedAt).Seconds()*rate)
+	bucket.updatedAt = now
+
+	var wait time.Duration
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+	} else {
+		wait = time.Duration((1 - bucket.tokens) / rate * float64(time.Second))
+	}
+	s.buckets[key] = bucket
+
+	// 가득 찬 버킷은 새로 만든 것과 같으므로 정리
+	if len(s.buckets) > sweepThreshold {
+		for k, b := range s.buckets {
+			if b.tokens+now.Sub(b.updatedAt).Seconds()*rate >= float64(burst) {
+				delete(s.buckets, k)
+			}
+		}
+	}
+
+	return wait, nil
+}
+
+func (s *memoryStore) Ban(_ context.Context, ban Ban) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.bans[ban.IP] = ban
+	return nil
+}
+
+func (s *memoryStore) Banned(_ context.Context, ip string) (*Ban, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ban, ok := s.bans[ip]
+	if !ok {
+		return nil, nil
+	}
+	if !time.Now().Before(ban.Until) {
+		delete(s.bans, ip)
+		return nil, nil
+	}
+	return &ban, nil
+}
+
+func (s *memoryStore) Unban(_ context.Context, ip string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.bans, ip)
+	return nil
+}
+
+func (s *memoryStore) Bans(_ context.Context) ([]Ban, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	bans := []Ban{}
+	for ip, ban := range s.bans {
+		if !now.Before(ban.Until) {
+			delete(s.bans, ip)
+			continue
+		}
+		bans = append(bans, ban)
+	}
+	sort.Slice(bans, func(i, j int) bool { return bans[i].Until.After(bans[j].Until) })
+	return bans, nil
+}
+
+func (s *memoryStore) RecordOffender(_ context.Context, ip, reason string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	offender := s.offenders[ip]
+	if at.Sub(offender.LastSeen) > s.retention {
+		offender = Offender{}
+	}
+	s.offenders[ip] = Offender{IP: ip, LastReason: reason, Count: offender.Count + 1, LastSeen: at}
+
+	if len(s.offenders) > sweepThreshold {
+		for k, o := range s.offenders {
+			if at.Sub(o.LastSeen) > s.retention {
+				delete(s.offenders, k)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *memoryStore) RecentOffenders(_ context.Context, limit int) ([]Offender, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	offenders := []Offender{}
+	for _, offender := range s.offenders {
+		if now.Sub(offender.LastSeen) <= s.retention {
+			offenders = append(offenders, offender)
+		}
+	}
+	sort.Slice(offenders, func(i, j int) bool { return offenders[i].LastSeen.After(offenders[j].LastSeen) })
+	if limit > 0 && len(offenders) > limit {
+		offenders = offenders[:limit]
+	}
+	return offenders, nil
+}
@@ -0,0 +1,207 @@
+package bundleservice
+
+import (
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"vm-controller/internal/db"
+	"vm-controller/internal/models"
+	"vm-controller/internal/version"
+	"vm-controller/internal/vmstate"
+
+	"github.com/spf13/cast"
+)
+
+type BundleService struct {
+}
+
+func NewBundleService() *BundleService {
+	return &BundleService{}
+}
+
+// SubscribeTransitions는 프로비저닝 결과(Provisioning -> Running / Failed)를 생성 시도 기록에 반영하도록 상태 머신에 등록합니다.
+// 서버 시작 시 한 번만 호출합니다.
+func (s *BundleService) SubscribeTransitions() {
+	vmstate.OnTransition(func(t vmstate.Transition) {
+		if t.From != models.VmStatusProvisioning {
+			return
+		}
+		switch t.To {
+		case models.VmStatusRunning:
+			s.resolveAttempt(t.VmName, models.BundleAttemptSucceeded, "")
+		case models.VmStatusFailed:
+			s.resolveAttempt(t.VmName, models.BundleAttemptFailed, "provisioning failed")
+		}
+	})
+}
+
+// 번들 채널
+const (
+	ChannelStable = "stable"
+	ChannelCanary = "canary"
+)
+
+// 번들 안의 VM 템플릿 디렉터리
+const (
+	VMTemplate       = "client-vm"
+	UploadVMTemplate = "client-vm-upload" // DataVolume source가 upload 인 것만 client-vm 과 다름
+)
+
+// Assignment는 새 VM 생성에 사용할 번들입니다.
+type Assignment struct {
+	Channel string `json:"channel"`
+	Root    string `json:"root"`
+	version.BundleInfo
+}
+
+// CanaryRoot는 canary 번들 디렉터리(MANIFEST_CANARY_DIR)를 반환합니다. 설정되지 않았거나 없으면 빈 값입니다.
+// 번들 구조는 yaml-data와 같아야 합니다. (client-vm, client-vm-upload, VERSION)
+func (s *BundleService) CanaryRoot() string {
+	dir := os.Getenv("MANIFEST_CANARY_DIR")
+	if dir == "" {
+		return ""
+	}
+
+	dir = filepath.Clean(dir)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return ""
+	}
+	return dir
+}
+
+// CanaryPercent는 canary 번들로 생성할 사용자 비율(0~100, MANIFEST_CANARY_PERCENT)입니다.
+func (s *BundleService) CanaryPercent() int {
+	percent := cast.ToInt(os.Getenv("MANIFEST_CANARY_PERCENT"))
+	if percent < 0 {
+		return 0
+	}
+	if percent > 100 {
+		return 100
+	}
+	return percent
+}
+
+// CanaryUsers는 비율과 관계없이 항상 canary 번들을 사용하는 학번 목록(MANIFEST_CANARY_USERS)입니다.
+func (s *BundleService) CanaryUsers() map[string]bool {
+	users := map[string]bool{}
+	for _, studentId := range strings.Split(os.Getenv("MANIFEST_CANARY_USERS"), ",") {
+		if studentId = strings.TrimSpace(studentId); studentId != "" {
+			users[studentId] = true
+		}
+	}
+	return users
+}
+
+// Assign은 사용자의 새 VM에 사용할 번들을 정합니다.
+// 비율 배정은 사용자 ID 해시로 결정되므로 같은 사용자는 항상 같은 번들을 받습니다.
+func (s *BundleService) Assign(user *models.User) Assignment {
+	stable := Assignment{Channel: ChannelStable, Root: version.ManifestBundleDir, BundleInfo: version.Bundle(version.ManifestBundleDir)}
+
+	root := s.CanaryRoot()
+	if root == "" {
+		return stable
+	}
+
+	canary := Assignment{Channel: ChannelCanary, Root: root, BundleInfo: version.Bundle(root)}
+	if s.CanaryUsers()[user.UserStudentId] {
+		return canary
+	}
+
+	hash := fnv.New32a()
+	hash.Write([]byte(fmt.Sprintf("%d", user.ID)))
+	if int(hash.Sum32()%100) < s.CanaryPercent() {
+		return canary
+	}
+
+	return stable
+}
+
+// TemplateDir은 채널 번들의 템플릿 디렉터리를 반환합니다.
+// canary 번들이 제거된 뒤 canary로 생성된 VM을 재생성하면 stable 번들을 사용합니다.
+func (s *BundleService) TemplateDir(channel, template string) string {
+	if channel == ChannelCanary {
+		if root := s.CanaryRoot(); root != "" {
+			return filepath.Join(root, template)
+		}
+	}
+	return filepath.Join(version.ManifestBundleDir, template)
+}
+
+// RecordAttempt는 VM 생성 시도를 기록합니다. 매니페스트 적용에 실패했으면 err를 전달합니다.
+// 기록 실패가 VM 생성을 막지 않도록 에러는 로그만 남깁니다.
+func (s *BundleService) RecordAttempt(vmName string, userId uint, assignment Assignment, err error) {
+	db := db.GetDB()
+
+	attempt := models.BundleAttempt{
+		VmName:  vmName,
+		UserID:  userId,
+		Channel: assignment.Channel,
+		Version: assignment.Version,
+		Digest:  assignment.Digest,
+		Result:  models.BundleAttemptPending,
+	}
+	if err != nil {
+		attempt.Result = models.BundleAttemptFailed
+		attempt.Detail = err.Error()
+	}
+
+	if err := db.Create(&attempt).Error; err != nil {
+		slog.Warn("bundle: failed to record attempt", "vm", vmName, "error", err)
+	}
+}
+
+// resolveAttempt는 VM의 진행 중인 생성 시도에 결과를 기록합니다.
+func (s *BundleService) resolveAttempt(vmName string, result models.EnumBundleAttemptResult, detail string) {
+	db := db.GetDB()
+
+	err := db.Model(&models.BundleAttempt{}).
+		Where("vm_name = ? AND result = ?", vmName, models.BundleAttemptPending).
+		Updates(map[string]interface{}{"result": result, "detail": detail}).Error
+	if err != nil {
+		slog.Warn("bundle: failed to resolve attempt", "vm", vmName, "error", err)
+	}
+}
+
+// BundleReport는 한 번들 버전의 생성 결과 집계입니다.
+type BundleReport struct {
+	Channel     string  `json:"channel"`
+	Version     string  `json:"version"`
+	Digest      string  `json:"digest"`
+	Total       int     `json:"total"`
+	Succeeded   int     `json:"succeeded"`
+	Failed      int     `json:"failed"`
+	Pending     int     `json:"pending"`
+	FailureRate float64 `json:"failure_rate"` // failed / (succeeded + failed), 완료된 시도가 없으면 0
+}
+
+// Report는 since 이후 생성 시도를 채널/번들 버전별로 집계합니다.
+func (s *BundleService) Report(since time.Time) ([]BundleReport, error) {
+	db := db.GetDB()
+
+	var reports []BundleReport
+	err := db.Model(&models.BundleAttempt{}).
+		Select(`channel, version, digest, count(*) AS total,
+			count(*) FILTER (WHERE result = ?) AS succeeded,
+			count(*) FILTER (WHERE result = ?) AS failed,
+			count(*) FILTER (WHERE result = ?) AS pending`,
+			models.BundleAttemptSucceeded, models.BundleAttemptFailed, models.BundleAttemptPending).
+		Where("created_at >= ?", since).
+		Group("channel, version, digest").
+		Order("channel, version").
+		Scan(&reports).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range reports {
+		if completed := reports[i].Succeeded + reports[i].Failed; completed > 0 {
+			reports[i].FailureRate = float64(reports[i].Failed) / float64(completed)
+		}
+	}
+
+	return reports, nil
+}
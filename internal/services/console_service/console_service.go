@@ -0,0 +1,102 @@
+package consoleservice
+
+import (
+	"log/slog"
+	"os"
+	"time"
+	"vm-controller/internal/db"
+	"vm-controller/internal/models"
+)
+
+// 기본 콘솔 세션 기록 보존 기간 (CONSOLE_RECORDING_RETENTION 으로 변경 가능)
+const defaultRetention = 90 * 24 * time.Hour
+
+type ConsoleService struct {
+}
+
+func NewConsoleService() *ConsoleService {
+	return &ConsoleService{}
+}
+
+// RecordingEnabled는 콘솔 세션 기록 정책(CONSOLE_RECORDING)이 켜져 있는지 반환합니다.
+// 일부 학내 IT 정책에서 요구하는 경우에만 켭니다.
+func (s *ConsoleService) RecordingEnabled() bool {
+	return os.Getenv("CONSOLE_RECORDING") == "true"
+}
+
+// Retention은 콘솔 세션 기록 보존 기간을 반환합니다.
+func (s *ConsoleService) Retention() time.Duration {
+	if retention, err := time.ParseDuration(os.Getenv("CONSOLE_RECORDING_RETENTION")); err == nil && retention > 0 {
+		return retention
+	}
+	return defaultRetention
+}
+
+// StartSession은 콘솔 세션 시작을 기록합니다. 기록 정책이 꺼져 있으면 nil을 반환합니다.
+func (s *ConsoleService) StartSession(session models.ConsoleSession) (*models.ConsoleSession, error) {
+	if !s.RecordingEnabled() {
+		return nil, nil
+	}
+
+	db := db.GetDB()
+
+	session.StartedAt = time.Now()
+	if err := db.Create(&session).Error; err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// EndSession은 콘솔 세션 종료 시각을 기록합니다.
+func (s *ConsoleService) EndSession(session *models.ConsoleSession) error {
+	if session == nil {
+		return nil
+	}
+
+	db := db.GetDB()
+
+	return db.Model(session).Update("ended_at", time.Now()).Error
+}
+
+// FetchSessions는 콘솔 세션 기록을 최신순으로 반환합니다. vmName이 비어 있으면 전체를 반환합니다.
+func (s *ConsoleService) FetchSessions(vmName string, limit int) ([]models.ConsoleSession, error) {
+	db := db.GetDB()
+
+	var sessions []models.ConsoleSession
+
+	query := db.Order("id desc").Limit(limit)
+	if vmName != "" {
+		query = query.Where("vm_name = ?", vmName)
+	}
+
+	if err := query.Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// PurgeExpired는 보존 기간이 지난 기록을 영구 삭제합니다.
+func (s *ConsoleService) PurgeExpired() (int64, error) {
+	db := db.GetDB()
+
+	result := db.Unscoped().Where("started_at < ?", time.Now().Add(-s.Retention())).Delete(&models.ConsoleSession{})
+	return result.RowsAffected, result.Error
+}
+
+// StartRetentionCleanup은 주기적으로 보존 기간이 지난 기록을 삭제합니다.
+func (s *ConsoleService) StartRetentionCleanup(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if purged, err := s.PurgeExpired(); err != nil {
+				slog.Warn("console sessions: failed to purge expired records", "error", err)
+			} else if purged > 0 {
+				slog.Info("console sessions: purged expired records", "count", purged)
+			}
+		}
+	}()
+}
@@ -0,0 +1,186 @@
+package databaseservice
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"vm-controller/internal/db"
+	"vm-controller/internal/models"
+	quotaservice "vm-controller/internal/services/quota_service"
+
+	"gorm.io/gorm"
+)
+
+type DatabaseService struct {
+	quotaService *quotaservice.QuotaService
+}
+
+func NewDatabaseService(quotaService *quotaservice.QuotaService) *DatabaseService {
+	return &DatabaseService{quotaService: quotaService}
+}
+
+// 관리형 데이터베이스 스토리지 허용 범위 (GiB)
+const (
+	minStorageGi = 1
+	maxStorageGi = 20
+)
+
+var nameRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// enginePorts는 엔진별 기본 접속 포트입니다.
+var enginePorts = map[string]int32{
+	models.DatabaseEnginePostgres: 5432,
+	models.DatabaseEngineMySQL:    3306,
+}
+
+// generatePassword는 YAML/URL에 안전한 영숫자 비밀번호를 생성합니다.
+func generatePassword(length int) (string, error) {
+	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+	result := make([]byte, length)
+	for i := range result {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+		if err != nil {
+			return "", err
+		}
+		result[i] = charset[n.Int64()]
+	}
+
+	return string(result), nil
+}
+
+func (s *DatabaseService) CreateDatabase(params CreateDatabaseParams) (*models.ManagedDatabase, error) {
+	if len(params.Name) > 40 || !nameRegex.MatchString(params.Name) {
+		return nil, fmt.Errorf("invalid database name: %s (must be DNS-1123 compliant, max 40 characters)", params.Name)
+	}
+
+	port, ok := enginePorts[params.Engine]
+	if !ok {
+		return nil, fmt.Errorf("invalid engine: %s (allowed: postgres, mysql)", params.Engine)
+	}
+
+	if params.StorageGi < minStorageGi || params.StorageGi > maxStorageGi {
+		return nil, fmt.Errorf("invalid storage: %dGi (must be between %d and %d)", params.StorageGi, minStorageGi, maxStorageGi)
+	}
+
+	// 쿼터 확인 (스토리지는 VM 디스크 + 관리형 DB 합산, hard cap 초과 시 거부)
+	headrooms, err := s.quotaService.Check(params.UserID, map[quotaservice.Dimension]int{
+		quotaservice.DimensionStorage:   params.StorageGi,
+		quotaservice.DimensionDatabases: 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := generatePassword(20)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate password: %v", err)
+	}
+
+	db := db.GetDB()
+
+	database := models.ManagedDatabase{
+		UserID:       params.UserID,
+		Name:         params.Name,
+		Engine:       params.Engine,
+		Namespace:    params.Namespace,
+		Host:         fmt.Sprintf("%s.%s.svc.cluster.local", params.Name, params.Namespace),
+		Port:         port,
+		StorageGi:    params.StorageGi,
+		DatabaseName: "app",
+		Username:     "app",
+		Password:     password,
+		Status:       "Provisioning",
+	}
+
+	if err := db.Create(&database).Error; err != nil {
+		return nil, err
+	}
+	s.quotaService.NotifySoftLimits(params.UserID, headrooms)
+
+	return &database, nil
+}
+
+func (s *DatabaseService) FetchUserDatabases(userId string, containPassword bool) ([]models.ManagedDatabase, error) {
+	db := db.GetDB()
+
+	var databases []models.ManagedDatabase
+
+	if err := db.Where("user_id = ? AND is_deleted = false", userId).Find(&databases).Error; err != nil {
+		return nil, err
+	}
+
+	if !containPassword {
+		for i := range databases {
+			databases[i].Password = ""
+		}
+	}
+
+	return databases, nil
+}
+
+func (s *DatabaseService) FetchDatabaseById(id uint, containPassword bool) (*models.ManagedDatabase, error) {
+	db := db.GetDB()
+
+	var database models.ManagedDatabase
+
+	if err := db.Where("id = ? AND is_deleted = false", id).First(&database).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	if !containPassword {
+		database.Password = ""
+	}
+
+	return &database, nil
+}
+
+func (s *DatabaseService) UpdateDatabaseStatus(id uint, status string) error {
+	db := db.GetDB()
+
+	if err := db.Model(&models.ManagedDatabase{}).Where("id = ?", id).Update("status", status).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *DatabaseService) DeleteDatabase(id uint) error {
+	db := db.GetDB()
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.ManagedDatabase{}).Where("id = ?", id).Update("is_deleted", true).Error; err != nil {
+			return err
+		}
+
+		// 연결된 배포에서 참조 해제
+		return tx.Model(&models.Deployment{}).Where("database_id = ?", id).Update("database_id", nil).Error
+	})
+}
+
+// AttachToDeployment는 배포에 관리형 데이터베이스를 연결합니다.
+func (s *DatabaseService) AttachToDeployment(databaseId, deploymentId uint) error {
+	db := db.GetDB()
+
+	if err := db.Model(&models.Deployment{}).Where("id = ?", deploymentId).Update("database_id", databaseId).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ConnectionURL은 배포에 주입할 접속 URL을 생성합니다.
+func ConnectionURL(database *models.ManagedDatabase) string {
+	scheme := "postgres"
+	if database.Engine == models.DatabaseEngineMySQL {
+		scheme = "mysql"
+	}
+
+	return fmt.Sprintf("%s://%s:%s@%s:%d/%s", scheme, database.Username, database.Password, database.Host, database.Port, database.DatabaseName)
+}
@@ -0,0 +1,9 @@
+package databaseservice
+
+type CreateDatabaseParams struct {
+	UserID    uint
+	Namespace string
+	Name      string
+	Engine    string
+	StorageGi int
+}
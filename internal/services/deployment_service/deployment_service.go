@@ -0,0 +1,331 @@
+package deploymentservice
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"vm-controller/internal/db"
+	"vm-controller/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type DeploymentService struct {
+}
+
+func NewDeploymentService() *DeploymentService {
+	return &DeploymentService{}
+}
+
+var (
+	repoURLRegex = regexp.MustCompile(`^https://github\.com/[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+?(\.git)?$`)
+	branchRegex  = regexp.MustCompile(`^[A-Za-z0-9._/-]+$`)
+	pathRegex    = regexp.MustCompile(`^[A-Za-z0-9._/-]+$`)
+	domainRegex  = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)+$`)
+	cronRegex    = regexp.MustCompile(`^[0-9A-Za-z*/,?-]+$`)
+)
+
+// cron 매크로 (K8s CronJob이 지원하는 형식)
+var cronMacros = map[string]bool{
+	"@yearly": true, "@annually": true, "@monthly": true,
+	"@weekly": true, "@daily": true, "@midnight": true, "@hourly": true,
+}
+
+// ValidateSchedule은 cron 표현식이 5개 필드로 구성되었는지, 허용 문자만 포함하는지 확인합니다.
+func ValidateSchedule(schedule string) error {
+	if cronMacros[schedule] {
+		return nil
+	}
+
+	fields := strings.Fields(schedule)
+	if len(fields) != 5 || strings.Join(fields, " ") != schedule {
+		return fmt.Errorf("invalid schedule: %s (must be a 5-field cron expression)", schedule)
+	}
+
+	for _, field := range fields {
+		if !cronRegex.MatchString(field) {
+			return fmt.Errorf("invalid schedule: %s (contains invalid characters)", schedule)
+		}
+	}
+
+	return nil
+}
+
+// validateRelativePath는 빌드 컨텍스트 내부 경로가 안전한지 확인합니다.
+// 절대 경로, 상위 디렉토리 참조(..), 허용되지 않은 문자를 차단합니다.
+func validateRelativePath(field, p string) (string, error) {
+	if !pathRegex.MatchString(p) {
+		return "", fmt.Errorf("invalid %s: %s (contains invalid characters)", field, p)
+	}
+
+	cleaned := path.Clean(p)
+	if strings.HasPrefix(cleaned, "/") || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("invalid %s: %s (must be relative to the repository root)", field, p)
+	}
+
+	return cleaned, nil
+}
+
+// validateBranch는 git ref 명명 규칙 중 인젝션에 영향을 주는 항목을 검사합니다.
+func validateBranch(branch string) error {
+	if !branchRegex.MatchString(branch) ||
+		strings.HasPrefix(branch, "-") ||
+		strings.HasPrefix(branch, "/") ||
+		strings.HasSuffix(branch, "/") ||
+		strings.HasSuffix(branch, ".lock") ||
+		strings.Contains(branch, "..") ||
+		strings.Contains(branch, "//") {
+		return fmt.Errorf("invalid branch name: %s", branch)
+	}
+
+	return nil
+}
+
+// normalizeParams는 기본값을 채우고 모든 빌드 관련 입력값을 검증합니다.
+func (s *DeploymentService) normalizeParams(params *CreateDeploymentParams) error {
+	if !repoURLRegex.MatchString(params.RepoURL) {
+		return fmt.Errorf("invalid repo url: %s (must be https://github.com/{owner}/{repo})", params.RepoURL)
+	}
+
+	if !domainRegex.MatchString(params.Domain) {
+		return fmt.Errorf("invalid domain format: %s", params.Domain)
+	}
+
+	switch params.Type {
+	case "":
+		params.Type = models.DeploymentTypeWeb
+	case models.DeploymentTypeWeb, models.DeploymentTypeCronJob:
+	default:
+		return fmt.Errorf("invalid deployment type: %s (allowed: web, cronjob)", params.Type)
+	}
+
+	if params.Type == models.DeploymentTypeCronJob {
+		if err := ValidateSchedule(params.Schedule); err != nil {
+			return err
+		}
+	} else {
+		params.Schedule = ""
+	}
+
+	if params.Branch == "" {
+		params.Branch = "main"
+	}
+	if err := validateBranch(params.Branch); err != nil {
+		return err
+	}
+
+	if params.ContextDir == "" {
+		params.ContextDir = "."
+	}
+	contextDir, err := validateRelativePath("context_dir", params.ContextDir)
+	if err != nil {
+		return err
+	}
+	params.ContextDir = contextDir
+
+	if params.DockerfilePath == "" {
+		params.DockerfilePath = "Dockerfile"
+	}
+	dockerfilePath, err := validateRelativePath("dockerfile_path", params.DockerfilePath)
+	if err != nil {
+		return err
+	}
+	if dockerfilePath == "." {
+		return fmt.Errorf("invalid dockerfile_path: must point to a file")
+	}
+	params.DockerfilePath = dockerfilePath
+
+	return nil
+}
+
+func (s *DeploymentService) CreateDeployment(params CreateDeploymentParams) (*models.Deployment, error) {
+	if err := s.normalizeParams(&params); err != nil {
+		return nil, err
+	}
+
+	// Dockerfile 유무에 따라 빌드 방식 자동 선택
+	strategy, language, err := detectBuildStrategy(params)
+	if err != nil {
+		return nil, err
+	}
+
+	db := db.GetDB()
+
+	deployment := models.Deployment{
+		UserID:         params.UserID,
+		Type:           params.Type,
+		Schedule:       params.Schedule,
+		RepoURL:        params.RepoURL,
+		Domain:         params.Domain,
+		Branch:         params.Branch,
+		ContextDir:     params.ContextDir,
+		DockerfilePath: params.DockerfilePath,
+		BuildStrategy:  strategy,
+		Language:       language,
+		Status:         models.DeploymentStatusPending,
+	}
+
+	if err := db.Create(&deployment).Error; err != nil {
+		return nil, err
+	}
+
+	return &deployment, nil
+}
+
+func (s *DeploymentService) FetchUserDeployments(userId string) ([]models.Deployment, error) {
+	db := db.GetDB()
+
+	var deployments []models.Deployment
+
+	if err := db.Where("user_id = ?", userId).Find(&deployments).Error; err != nil {
+		return nil, err
+	}
+
+	return deployments, nil
+}
+
+func (s *DeploymentService) UpdateDeploymentStatus(id uint, status string) error {
+	db := db.GetDB()
+
+	if err := db.Model(&models.Deployment{}).Where("id = ?", id).Update("status", status).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *DeploymentService) FetchDeploymentById(id uint) (*models.Deployment, error) {
+	db := db.GetDB()
+
+	var deployment models.Deployment
+
+	if err := db.Where("id = ?", id).First(&deployment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &deployment, nil
+}
+
+func (s *DeploymentService) FetchJobRuns(deploymentId uint) ([]models.JobRun, error) {
+	db := db.GetDB()
+
+	var runs []models.JobRun
+
+	if err := db.Where("deployment_id = ?", deploymentId).Order("started_at desc").Find(&runs).Error; err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+// UpsertJobRun은 관측된 Job 실행 정보를 저장합니다.
+// 반환값 newlyFailed는 이번 갱신으로 처음 Failed 상태가 된 경우 true이며, 실패 알림 발송에 사용됩니다.
+func (s *DeploymentService) UpsertJobRun(run models.JobRun) (bool, error) {
+	db := db.GetDB()
+
+	var existing models.JobRun
+	err := db.Where("job_name = ?", run.JobName).First(&existing).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if err := db.Create(&run).Error; err != nil {
+			return false, err
+		}
+		return run.Status == models.JobRunStatusFailed, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	newlyFailed := existing.Status != models.JobRunStatusFailed && run.Status == models.JobRunStatusFailed
+
+	updates := map[string]interface{}{
+		"status":      run.Status,
+		"started_at":  run.StartedAt,
+		"finished_at": run.FinishedAt,
+	}
+	if run.Logs != "" {
+		updates["logs"] = run.Logs
+	}
+
+	if err := db.Model(&existing).Updates(updates).Error; err != nil {
+		return false, err
+	}
+
+	return newlyFailed, nil
+}
+
+func (s *DeploymentService) FetchDeploymentByDomain(domain string) (*models.Deployment, error) {
+	db := db.GetDB()
+
+	var deployment models.Deployment
+
+	if err := db.Where("domain = ?", domain).First(&deployment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &deployment, nil
+}
+
+func (s *DeploymentService) UpdateSleepPolicy(id uint, sleepAfterHours int) error {
+	if sleepAfterHours < 0 || sleepAfterHours > 24*30 {
+		return fmt.Errorf("invalid sleep_after_hours: %d (must be between 0 and 720)", sleepAfterHours)
+	}
+
+	db := db.GetDB()
+
+	if err := db.Model(&models.Deployment{}).Where("id = ?", id).Update("sleep_after_hours", sleepAfterHours).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// 같은 도메인의 활동 기록은 이 간격마다 한 번만 DB에 반영 (요청마다 UPDATE 방지)
+const activityFlushInterval = time.Minute
+
+var lastActivityFlush sync.Map // domain -> time.Time
+
+// RecordActivity는 도메인으로 들어온 트래픽을 배포의 마지막 활동 시각으로 기록합니다.
+func (s *DeploymentService) RecordActivity(domain string) {
+	now := time.Now()
+
+	if last, ok := lastActivityFlush.Load(domain); ok && now.Sub(last.(time.Time)) < activityFlushInterval {
+		return
+	}
+	lastActivityFlush.Store(domain, now)
+
+	db := db.GetDB()
+	if err := db.Model(&models.Deployment{}).Where("domain = ?", domain).Update("last_activity_at", now).Error; err != nil {
+		slog.Warn("deployment: failed to record activity", "domain", domain, "error", err)
+	}
+}
+
+// FetchIdleDeployments는 sleep-on-idle이 활성화된 웹 배포 중 유휴 시간이 초과된 배포를 반환합니다.
+func (s *DeploymentService) FetchIdleDeployments() ([]models.Deployment, error) {
+	db := db.GetDB()
+
+	var deployments []models.Deployment
+
+	if err := db.Where("type = ? AND sleep_after_hours > 0 AND status NOT IN ?", models.DeploymentTypeWeb,
+		[]string{models.DeploymentStatusPaused, models.DeploymentStatusSleeping, models.DeploymentStatusFailed}).
+		Where("COALESCE(last_activity_at, created_at) < NOW() - sleep_after_hours * INTERVAL '1 hour'").
+		Find(&deployments).Error; err != nil {
+		return nil, err
+	}
+
+	return deployments, nil
+}
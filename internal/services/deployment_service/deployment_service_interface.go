@@ -0,0 +1,12 @@
+package deploymentservice
+
+type CreateDeploymentParams struct {
+	UserID         uint
+	Type           string
+	Schedule       string
+	RepoURL        string
+	Domain         string
+	Branch         string
+	ContextDir     string
+	DockerfilePath string
+}
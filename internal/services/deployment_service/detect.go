@@ -0,0 +1,69 @@
+package deploymentservice
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+	"vm-controller/internal/models"
+)
+
+// buildpacks가 지원하는 언어별 감지 파일 목록 (우선순위 순)
+var languageMarkers = []struct {
+	Language string
+	Files    []string
+}{
+	{Language: "node", Files: []string{"package.json"}},
+	{Language: "python", Files: []string{"requirements.txt", "pyproject.toml", "Pipfile"}},
+	{Language: "go", Files: []string{"go.mod"}},
+}
+
+var detectClient = &http.Client{Timeout: 5 * time.Second}
+
+// rawFileExists는 raw.githubusercontent.com에 HEAD 요청을 보내 파일 존재 여부를 확인합니다.
+func rawFileExists(repoURL, branch, filePath string) (bool, error) {
+	repo := strings.TrimSuffix(strings.TrimPrefix(repoURL, "https://github.com/"), ".git")
+	url := fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/%s", repo, branch, filePath)
+
+	resp, err := detectClient.Head(url)
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect repository: %v", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected status %d while inspecting %s", resp.StatusCode, filePath)
+	}
+}
+
+// detectBuildStrategy는 Dockerfile이 있으면 dockerfile 빌드를, 없으면 buildpacks 빌드를 선택합니다.
+// buildpacks의 경우 컨텍스트 디렉토리의 마커 파일로 언어를 감지하며, 지원하지 않는 프로젝트는 에러를 반환합니다.
+func detectBuildStrategy(params CreateDeploymentParams) (string, string, error) {
+	hasDockerfile, err := rawFileExists(params.RepoURL, params.Branch, path.Join(params.ContextDir, params.DockerfilePath))
+	if err != nil {
+		return "", "", err
+	}
+	if hasDockerfile {
+		return models.BuildStrategyDockerfile, "", nil
+	}
+
+	for _, marker := range languageMarkers {
+		for _, file := range marker.Files {
+			found, err := rawFileExists(params.RepoURL, params.Branch, path.Join(params.ContextDir, file))
+			if err != nil {
+				return "", "", err
+			}
+			if found {
+				return models.BuildStrategyBuildpacks, marker.Language, nil
+			}
+		}
+	}
+
+	return "", "", fmt.Errorf("no Dockerfile found and project type could not be detected (supported: node, python, go)")
+}
@@ -0,0 +1,145 @@
+package flavorchangeservice
+
+import (
+	"errors"
+	"time"
+	"vm-controller/internal/db"
+	"vm-controller/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type FlavorChangeService struct {
+}
+
+func NewFlavorChangeService() *FlavorChangeService {
+	return &FlavorChangeService{}
+}
+
+var (
+	ErrChangePending      = errors.New("a flavor change for this VM is already scheduled or running")
+	ErrChangeNotFound     = errors.New("flavor change not found")
+	ErrChangeNotScheduled = errors.New("only scheduled flavor changes can be cancelled")
+)
+
+// 대기/실행 중인 변경 상태 (VM당 하나만 허용)
+var activeStatuses = []models.EnumFlavorChangeStatus{models.FlavorChangeScheduled, models.FlavorChangeRunning}
+
+// CreateChange는 요금제 변경 요청을 Scheduled 상태로 저장합니다.
+// 같은 VM에 대기/실행 중인 변경이 있으면 ErrChangePending을 반환합니다.
+func (s *FlavorChangeService) CreateChange(change *models.VmFlavorChange) error {
+	db := db.GetDB()
+
+	var count int64
+	if err := db.Model(&models.VmFlavorChange{}).
+		Where("vm_name = ? AND status IN ?", change.VmName, activeStatuses).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrChangePending
+	}
+
+	change.Status = models.FlavorChangeScheduled
+	return db.Create(change).Error
+}
+
+// FetchChange는 VM의 변경 요청 하나를 조회합니다.
+func (s *FlavorChangeService) FetchChange(vmName string, id uint) (*models.VmFlavorChange, error) {
+	db := db.GetDB()
+
+	var change models.VmFlavorChange
+	if err := db.Where("vm_name = ?", vmName).First(&change, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrChangeNotFound
+		}
+		return nil, err
+	}
+
+	return &change, nil
+}
+
+// FetchVmChanges는 VM의 요금제 변경 이력을 최신순으로 조회합니다.
+func (s *FlavorChangeService) FetchVmChanges(vmName string) ([]models.VmFlavorChange, error) {
+	db := db.GetDB()
+
+	var changes []models.VmFlavorChange
+	if err := db.Where("vm_name = ?", vmName).Order("created_at desc").Find(&changes).Error; err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// FetchDueChanges는 시간대가 시작된 Scheduled 변경을 오래된 순으로 조회합니다.
+func (s *FlavorChangeService) FetchDueChanges(now time.Time) ([]models.VmFlavorChange, error) {
+	db := db.GetDB()
+
+	var changes []models.VmFlavorChange
+	if err := db.Where("status = ? AND window_start <= ?", models.FlavorChangeScheduled, now).
+		Order("window_start").Find(&changes).Error; err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// FetchStaleRunning은 startedBefore 이전에 시작되어 아직 Running인 변경을 조회합니다. (서버 재시작으로 중단된 작업)
+func (s *FlavorChangeService) FetchStaleRunning(startedBefore time.Time) ([]models.VmFlavorChange, error) {
+	db := db.GetDB()
+
+	var changes []models.VmFlavorChange
+	if err := db.Where("status = ? AND started_at < ?", models.FlavorChangeRunning, startedBefore).
+		Find(&changes).Error; err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// Claim은 Scheduled 변경을 Running으로 바꿉니다.
+// 조건부 업데이트이므로 스케줄러 여러 대가 같은 변경을 실행하지 않으며, 이미 처리된 변경이면 false를 반환합니다.
+func (s *FlavorChangeService) Claim(id uint, now time.Time) (bool, error) {
+	db := db.GetDB()
+
+	result := db.Model(&models.VmFlavorChange{}).
+		Where("id = ? AND status = ?", id, models.FlavorChangeScheduled).
+		Updates(map[string]interface{}{"status": models.FlavorChangeRunning, "started_at": now})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// SetOperation은 변경을 실행하는 백그라운드 작업 ID를 기록합니다.
+func (s *FlavorChangeService) SetOperation(id, operationID uint) error {
+	db := db.GetDB()
+
+	return db.Model(&models.VmFlavorChange{}).Where("id = ?", id).Update("operation_id", operationID).Error
+}
+
+// Finish는 대기/실행 중인 변경을 최종 상태(Completed/Failed/Missed)로 바꿉니다.
+func (s *FlavorChangeService) Finish(id uint, status models.EnumFlavorChangeStatus, reason string) error {
+	db := db.GetDB()
+
+	return db.Model(&models.VmFlavorChange{}).
+		Where("id = ? AND status IN ?", id, activeStatuses).
+		Updates(map[string]interface{}{"status": status, "error": reason, "finished_at": time.Now()}).Error
+}
+
+// Cancel은 Scheduled 변경을 취소합니다. 이미 실행되었거나 끝난 변경이면 ErrChangeNotScheduled를 반환합니다.
+func (s *FlavorChangeService) Cancel(id uint) error {
+	db := db.GetDB()
+
+	result := db.Model(&models.VmFlavorChange{}).
+		Where("id = ? AND status = ?", id, models.FlavorChangeScheduled).
+		Updates(map[string]interface{}{"status": models.FlavorChangeCancelled, "finished_at": time.Now()})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrChangeNotScheduled
+	}
+
+	return nil
+}
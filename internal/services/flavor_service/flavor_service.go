@@ -0,0 +1,136 @@
+package flavorservice
+
+import (
+	"errors"
+	"vm-controller/internal/db"
+	"vm-controller/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type FlavorService struct {
+}
+
+func NewFlavorService() *FlavorService {
+	return &FlavorService{}
+}
+
+var (
+	ErrFlavorNotFound = errors.New("flavor not found")
+	ErrFlavorExists   = errors.New("flavor already exists")
+	ErrFlavorInUse    = errors.New("flavor is used by existing VMs")
+)
+
+// defaultFlavors는 요금제 테이블이 비어 있을 때 등록되는 기본 요금제입니다. (이전 고정 VM 크기와 동일)
+var defaultFlavors = []models.Flavor{
+	{Name: "standard", Cores: 2, CPURequest: "500m", CPULimit: "2", Memory: "4Gi", DiskGi: 20},
+	{Name: "premium", Cores: 2, CPURequest: "2", CPULimit: "2", Memory: "4Gi", DiskGi: 20, DedicatedCPU: true},
+}
+
+// SeedDefaults는 요금제가 하나도 없으면 기본 요금제를 등록합니다. (서버 시작 시 호출)
+func (s *FlavorService) SeedDefaults() error {
+	db := db.GetDB()
+
+	var count int64
+	if err := db.Model(&models.Flavor{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	flavors := append([]models.Flavor(nil), defaultFlavors...)
+	return db.Create(&flavors).Error
+}
+
+// FetchFlavors는 요금제 목록을 등록 순서로 반환합니다.
+func (s *FlavorService) FetchFlavors() ([]models.Flavor, error) {
+	db := db.GetDB()
+
+	var flavors []models.Flavor
+
+	if err := db.Order("id").Find(&flavors).Error; err != nil {
+		return nil, err
+	}
+
+	return flavors, nil
+}
+
+// FetchFlavor는 이름으로 요금제를 조회합니다.
+func (s *FlavorService) FetchFlavor(name string) (*models.Flavor, error) {
+	db := db.GetDB()
+
+	var flavor models.Flavor
+	if err := db.Where("name = ?", name).First(&flavor).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFlavorNotFound
+		}
+		return nil, err
+	}
+
+	return &flavor, nil
+}
+
+// CreateFlavor는 요금제를 등록합니다. 같은 이름이 있으면 ErrFlavorExists를 반환합니다.
+func (s *FlavorService) CreateFlavor(flavor *models.Flavor) error {
+	db := db.GetDB()
+
+	var count int64
+	if err := db.Model(&models.Flavor{}).Where("name = ?", flavor.Name).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrFlavorExists
+	}
+
+	return db.Create(flavor).Error
+}
+
+// UpdateFlavor는 요금제의 자원/가격을 변경합니다. (이름은 VM이 참조하므로 변경 불가)
+// 실행 중인 VM에는 재생성 전까지 반영되지 않습니다.
+func (s *FlavorService) UpdateFlavor(name string, flavor *models.Flavor) error {
+	db := db.GetDB()
+
+	result := db.Model(&models.Flavor{}).Where("name = ?", name).Updates(map[string]interface{}{
+		"cores":         flavor.Cores,
+		"cpu_request":   flavor.CPURequest,
+		"cpu_limit":     flavor.CPULimit,
+		"memory":        flavor.Memory,
+		"disk_gi":       flavor.DiskGi,
+		"dedicated_cpu": flavor.DedicatedCPU,
+		"hourly_price":  flavor.HourlyPrice,
+		"monthly_price": flavor.MonthlyPrice,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrFlavorNotFound
+	}
+
+	return nil
+}
+
+// DeleteFlavor는 요금제를 삭제합니다. 삭제되지 않은 VM이 사용 중이면 재생성할 수 없게 되므로 ErrFlavorInUse를 반환합니다.
+// 같은 이름으로 다시 등록할 수 있도록 레코드를 완전히 삭제합니다.
+func (s *FlavorService) DeleteFlavor(name string) error {
+	db := db.GetDB()
+
+	var count int64
+	if err := db.Model(&models.VirtualMachine{}).Where("flavor = ? AND is_deleted = false", name).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrFlavorInUse
+	}
+
+	result := db.Unscoped().Where("name = ?", name).Delete(&models.Flavor{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrFlavorNotFound
+	}
+
+	return nil
+}
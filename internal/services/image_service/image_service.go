@@ -0,0 +1,142 @@
+package imageservice
+
+import (
+	"errors"
+	"vm-controller/internal/db"
+	"vm-controller/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ImageService struct {
+}
+
+func NewImageService() *ImageService {
+	return &ImageService{}
+}
+
+var (
+	ErrImageNotFound = errors.New("image not found")
+	ErrImageExists   = errors.New("image already exists")
+	ErrImageInUse    = errors.New("image is used by existing VMs")
+)
+
+// defaultImages는 이미지 카탈로그가 비어 있을 때 등록되는 기본 이미지입니다. (이전 고정 원본 디스크와 동일)
+var defaultImages = []models.Image{
+	{
+		Name:            "ubuntu-22.04",
+		DisplayName:     "Ubuntu 22.04 LTS",
+		SourcePVC:       "ubuntu-2204-gold-source",
+		SourceNamespace: "cloud-admin",
+		SSHUser:         "ubuntu",
+		AuthMethods:     []string{"password", "publickey"},
+	},
+}
+
+// SeedDefaults는 이미지가 하나도 없으면 기본 이미지를 등록합니다. (서버 시작 시 호출)
+func (s *ImageService) SeedDefaults() error {
+	db := db.GetDB()
+
+	var count int64
+	if err := db.Model(&models.Image{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	images := append([]models.Image(nil), defaultImages...)
+	return db.Create(&images).Error
+}
+
+// FetchImages는 이미지 목록을 등록 순서로 반환합니다.
+func (s *ImageService) FetchImages() ([]models.Image, error) {
+	db := db.GetDB()
+
+	var images []models.Image
+
+	if err := db.Order("id").Find(&images).Error; err != nil {
+		return nil, err
+	}
+
+	return images, nil
+}
+
+// FetchImage는 이름으로 이미지를 조회합니다.
+func (s *ImageService) FetchImage(name string) (*models.Image, error) {
+	db := db.GetDB()
+
+	var image models.Image
+	if err := db.Where("name = ?", name).First(&image).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrImageNotFound
+		}
+		return nil, err
+	}
+
+	return &image, nil
+}
+
+// CreateImage는 이미지를 등록합니다. 같은 이름이 있으면 ErrImageExists를 반환합니다.
+func (s *ImageService) CreateImage(image *models.Image) error {
+	db := db.GetDB()
+
+	var count int64
+	if err := db.Model(&models.Image{}).Where("name = ?", image.Name).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrImageExists
+	}
+
+	return db.Create(image).Error
+}
+
+// UpdateImage는 이미지의 원본 디스크/사용자 정보를 변경합니다. (이름은 VM이 참조하므로 변경 불가)
+// 이미 생성된 VM의 SSH 사용자는 생성 당시 값을 유지합니다.
+func (s *ImageService) UpdateImage(name string, image *models.Image) error {
+	db := db.GetDB()
+
+	// auth_methods는 JSON serializer를 거쳐야 하므로 map 대신 구조체로 갱신 (Select로 빈 값도 반영)
+	result := db.Model(&models.Image{}).Where("name = ?", name).
+		Select("display_name", "source_pvc", "source_namespace", "ssh_user", "auth_methods").
+		Updates(&models.Image{
+			DisplayName:     image.DisplayName,
+			SourcePVC:       image.SourcePVC,
+			SourceNamespace: image.SourceNamespace,
+			SSHUser:         image.SSHUser,
+			AuthMethods:     image.AuthMethods,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrImageNotFound
+	}
+
+	return nil
+}
+
+// DeleteImage는 이미지를 삭제합니다. 삭제되지 않은 VM이 사용 중이면 재생성할 수 없게 되므로 ErrImageInUse를 반환합니다.
+// 같은 이름으로 다시 등록할 수 있도록 레코드를 완전히 삭제합니다.
+func (s *ImageService) DeleteImage(name string) error {
+	db := db.GetDB()
+
+	var count int64
+	if err := db.Model(&models.VirtualMachine{}).Where("image = ? AND is_deleted = false", name).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrImageInUse
+	}
+
+	result := db.Unscoped().Where("name = ?", name).Delete(&models.Image{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrImageNotFound
+	}
+
+	return nil
+}
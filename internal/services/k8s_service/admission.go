@@ -0,0 +1,164 @@
+package k8s_service
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var (
+	gvrAdmissionPolicy        = schema.GroupVersionResource{Group: "admissionregistration.k8s.io", Version: "v1", Resource: "validatingadmissionpolicies"}
+	gvrAdmissionPolicyBinding = schema.GroupVersionResource{Group: "admissionregistration.k8s.io", Version: "v1", Resource: "validatingadmissionpolicybindings"}
+)
+
+// 테넌트 네임스페이스 식별 라벨 (client-init 네임스페이스 템플릿과 동일)
+const (
+	tenantLabel           = "cloud.vm-controller.io/tenant"
+	tenantNamespacePrefix = "id-"
+)
+
+// admissionPolicyCatalog는 플랫폼이 관리하는 정책 목록입니다. (yaml-data/admission/<key>)
+var admissionPolicyCatalog = []struct {
+	Key         string
+	Description string
+}{
+	{Key: "deny-loadbalancer", Description: "Deny LoadBalancer services in tenant namespaces"},
+	{Key: "deny-hostpath", Description: "Deny hostPath volumes in tenant namespaces"},
+	{Key: "deny-privileged", Description: "Deny privileged containers and host namespaces in tenant namespaces"},
+	{Key: "deny-vm-priority-class", Description: "Reserve the VM pod priority class (separate VM quota) for virt-launcher pods"},
+}
+
+// AdmissionPolicy는 관리자 API에 노출되는 정책 상태입니다.
+type AdmissionPolicy struct {
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Installed   bool   `json:"installed"` // ValidatingAdmissionPolicy 존재 여부
+	Enabled     bool   `json:"enabled"`   // Binding 존재 여부 (Binding이 있어야 정책이 적용됨)
+}
+
+func admissionPolicyName(key string) string { return "tenant-" + key }
+
+func admissionBindingName(key string) string { return "tenant-" + key + "-binding" }
+
+func findAdmissionPolicy(key string) bool {
+	for _, policy := range admissionPolicyCatalog {
+		if policy.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// EnsureAdmissionPolicies는 서버 시작 시 정책을 설치합니다.
+// 처음 설치되는 정책만 Binding까지 생성하므로, 관리자가 비활성화한 정책은 재시작 후에도 비활성 상태로 유지됩니다.
+func (s *K8sService) EnsureAdmissionPolicies() error {
+	if err := s.labelTenantNamespaces(); err != nil {
+		s.log().Warn("admission: failed to label existing tenant namespaces", "error", err)
+	}
+
+	for _, policy := range admissionPolicyCatalog {
+		dir := filepath.Join("yaml-data", "admission", policy.Key)
+
+		created, err := s.applyManifests(filepath.Join(dir, "policy"), nil, "", true)
+		if err != nil {
+			return fmt.Errorf("failed to install admission policy %s: %v", policy.Key, err)
+		}
+
+		if len(created) > 0 {
+			if _, err := s.applyManifests(filepath.Join(dir, "binding"), nil, "", true); err != nil {
+				return fmt.Errorf("failed to bind admission policy %s: %v", policy.Key, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// labelTenantNamespaces는 라벨 도입 이전에 생성된 사용자 네임스페이스에 테넌트 라벨을 붙입니다.
+func (s *K8sService) labelTenantNamespaces() error {
+	ctx := s.baseContext()
+
+	namespaces, err := s.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"labels": map[string]string{tenantLabel: "true"}},
+	})
+
+	for _, namespace := range namespaces.Items {
+		if !strings.HasPrefix(namespace.Name, tenantNamespacePrefix) || namespace.Labels[tenantLabel] == "true" {
+			continue
+		}
+		if _, err := s.clientset.CoreV1().Namespaces().Patch(ctx, namespace.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			return fmt.Errorf("failed to label namespace %s: %v", namespace.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// ListAdmissionPolicies는 관리 대상 정책의 설치/활성화 상태를 반환합니다.
+func (s *K8sService) ListAdmissionPolicies() ([]AdmissionPolicy, error) {
+	ctx := s.baseContext()
+	policies := make([]AdmissionPolicy, 0, len(admissionPolicyCatalog))
+
+	for _, entry := range admissionPolicyCatalog {
+		policy := AdmissionPolicy{
+			Key:         entry.Key,
+			Name:        admissionPolicyName(entry.Key),
+			Description: entry.Description,
+		}
+
+		_, err := s.dynamicClient.Resource(gvrAdmissionPolicy).Get(ctx, policy.Name, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get admission policy %s: %v", policy.Name, err)
+		}
+		policy.Installed = err == nil
+
+		_, err = s.dynamicClient.Resource(gvrAdmissionPolicyBinding).Get(ctx, admissionBindingName(entry.Key), metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get admission policy binding %s: %v", policy.Name, err)
+		}
+		policy.Enabled = err == nil
+
+		policies = append(policies, policy)
+	}
+
+	return policies, nil
+}
+
+// SetAdmissionPolicyEnabled는 정책 Binding을 생성/삭제하여 정책을 활성화/비활성화합니다.
+func (s *K8sService) SetAdmissionPolicyEnabled(key string, enabled bool) error {
+	if !findAdmissionPolicy(key) {
+		return fmt.Errorf("unknown admission policy: %s", key)
+	}
+
+	dir := filepath.Join("yaml-data", "admission", key)
+
+	if enabled {
+		// 정책이 없으면 먼저 설치
+		if _, err := s.applyManifests(filepath.Join(dir, "policy"), nil, "", true); err != nil {
+			return fmt.Errorf("failed to install admission policy %s: %v", key, err)
+		}
+		if _, err := s.applyManifests(filepath.Join(dir, "binding"), nil, "", true); err != nil {
+			return fmt.Errorf("failed to bind admission policy %s: %v", key, err)
+		}
+		return nil
+	}
+
+	return s.deleteResource(CreatedResource{
+		Group:   gvrAdmissionPolicyBinding.Group,
+		Version: gvrAdmissionPolicyBinding.Version,
+		Kind:    "ValidatingAdmissionPolicyBinding",
+		Name:    admissionBindingName(key),
+	})
+}
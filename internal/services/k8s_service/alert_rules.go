@@ -0,0 +1,227 @@
+package k8s_service
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"vm-controller/internal/backup"
+	vmservice "vm-controller/internal/services/vm_service"
+)
+
+// 알림 임계값 기본값 (ALERT_*로 변경)
+const (
+	defaultAlertCreateFailureRatio = 0.2 // ALERT_CREATE_FAILURE_RATIO: 30분간 생성 실패 비율
+	defaultAlertQueueWaitSeconds   = 30  // ALERT_QUEUE_WAIT_SECONDS: 작업 슬롯 평균 대기 시간
+	defaultAlertPortPoolRatio      = 0.9 // ALERT_PORT_POOL_RATIO: NodePort 사용 비율
+)
+
+// 생성 실패 비율 알림에 필요한 최소 생성 건수 (적은 표본에서 한두 건 실패로 울리지 않도록)
+const alertCreateMinSamples = 5
+
+// 대기열 포화 알림 기준 (ASYNC_QUEUE_MAX_DEPTH 대비)
+const alertQueueDepthRatio = 0.8
+
+// AlertRule은 Prometheus 알림 규칙 하나입니다. (rules 파일과 PrometheusRule spec에서 같은 형식)
+type AlertRule struct {
+	Alert       string            `json:"alert"`
+	Expr        string            `json:"expr"`
+	For         string            `json:"for,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// AlertRuleGroup은 함께 평가되는 알림 규칙 묶음입니다.
+type AlertRuleGroup struct {
+	Name  string      `json:"name"`
+	Rules []AlertRule `json:"rules"`
+}
+
+// AlertRuleFile은 Prometheus rule_files에 그대로 넣을 수 있는 규칙 파일입니다.
+type AlertRuleFile struct {
+	Groups []AlertRuleGroup `json:"groups"`
+}
+
+// PrometheusRule은 prometheus-operator의 PrometheusRule 리소스입니다.
+type PrometheusRule struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name      string            `json:"name"`
+		Namespace string            `json:"namespace,omitempty"`
+		Labels    map[string]string `json:"labels,omitempty"`
+	} `json:"metadata"`
+	Spec AlertRuleFile `json:"spec"`
+}
+
+// AlertRuleOptions는 생성할 규칙의 대상과 리소스 메타데이터입니다.
+type AlertRuleOptions struct {
+	Job       string            // 비어 있지 않으면 모든 메트릭 선택자에 job 라벨 조건 추가 (여러 서비스가 같은 Prometheus를 쓸 때)
+	Namespace string            // PrometheusRule 네임스페이스
+	Labels    map[string]string // PrometheusRule 라벨 (operator의 ruleSelector에 맞출 때)
+}
+
+// AlertRuleGroups는 현재 설정(대기열 크기, 포트 범위, 에러율 임계값, 백업 주기)을 반영한 플랫폼 SLO 알림 규칙을 만듭니다.
+// 설정에서 꺼진 기능(ASYNC_QUEUE_MAX_DEPTH=0, 백업 미설정 등)의 규칙은 포함하지 않습니다.
+func AlertRuleGroups(opts AlertRuleOptions) []AlertRuleGroup {
+	sel := func(metric string, matchers ...string) string {
+		if opts.Job != "" {
+			matchers = append(matchers, "job="+strconv.Quote(opts.Job))
+		}
+		if len(matchers) == 0 {
+			return metric
+		}
+		return metric + "{" + strings.Join(matchers, ",") + "}"
+	}
+
+	failureRatio := floatEnv("ALERT_CREATE_FAILURE_RATIO", defaultAlertCreateFailureRatio)
+	queueWait := floatEnv("ALERT_QUEUE_WAIT_SECONDS", defaultAlertQueueWaitSeconds)
+	portRatio := floatEnv("ALERT_PORT_POOL_RATIO", defaultAlertPortPoolRatio)
+
+	provisioned := fmt.Sprintf("sum(increase(%s[30m]))", sel("vm_status_transitions_total", `from="Provisioning"`))
+	provisioning := []AlertRule{{
+		Alert: "VMCreationFailureRateHigh",
+		Expr: fmt.Sprintf("sum(increase(%s[30m])) / %s > %s and %s >= %d",
+			sel("vm_status_transitions_total", `from="Provisioning"`, `to="Failed"`), provisioned, formatFloat(failureRatio), provisioned, alertCreateMinSamples),
+		For:    "10m",
+		Labels: map[string]string{"severity": "warning"},
+		Annotations: map[string]string{
+			"summary":     "VM creation failure rate is high",
+			"description": fmt.Sprintf("{{ $value | humanizePercentage }} of VMs that left Provisioning in the last 30m ended in Failed (threshold %s).", formatPercent(failureRatio)),
+		},
+	}}
+
+	queue := []AlertRule{{
+		Alert: "AsyncQueueLatencyHigh",
+		Expr: fmt.Sprintf("sum(rate(%s[10m])) / sum(rate(%s[10m])) > %s",
+			sel("async_queue_wait_seconds_total"), sel("async_queue_started_total"), formatFloat(queueWait)),
+		For:    "10m",
+		Labels: map[string]string{"severity": "warning"},
+		Annotations: map[string]string{
+			"summary":     "Background jobs are waiting for a worker slot",
+			"description": fmt.Sprintf("Jobs waited {{ $value | humanizeDuration }} on average for one of the %d worker slots (ASYNC_WORKERS) over the last 10m.", asyncWorkerCount()),
+		},
+	}}
+	if maxDepth := intEnv("ASYNC_QUEUE_MAX_DEPTH", defaultAsyncQueueMaxDepth); maxDepth > 0 {
+		queue = append(queue,
+			AlertRule{
+				Alert:  "AsyncQueueNearCapacity",
+				Expr:   fmt.Sprintf("%s >= %d", sel("async_queue_depth"), int(math.Ceil(float64(maxDepth)*alertQueueDepthRatio))),
+				For:    "5m",
+				Labels: map[string]string{"severity": "warning"},
+				Annotations: map[string]string{
+					"summary":     "Background job queue is close to its limit",
+					"description": fmt.Sprintf("{{ $labels.instance }} has {{ $value }} queued or running jobs; new requests are rejected at %d (ASYNC_QUEUE_MAX_DEPTH).", maxDepth),
+				},
+			},
+			AlertRule{
+				Alert:  "AsyncBackpressureActive",
+				Expr:   sel("async_backpressure_active") + " == 1",
+				For:    "5m",
+				Labels: map[string]string{"severity": "critical"},
+				Annotations: map[string]string{
+					"summary":     "API is rejecting new operations with 429",
+					"description": "{{ $labels.instance }} has been shedding load for 5m because of queue depth or cluster error rate.",
+				},
+			})
+	}
+	if threshold := clusterErrorRateThreshold(); threshold > 0 {
+		queue = append(queue, AlertRule{
+			Alert:  "ClusterErrorRateHigh",
+			Expr:   fmt.Sprintf("%s >= %s", sel("async_cluster_error_rate"), formatFloat(threshold)),
+			For:    "5m",
+			Labels: map[string]string{"severity": "warning"},
+			Annotations: map[string]string{
+				"summary":     "Cluster operations are failing",
+				"description": fmt.Sprintf("{{ $value | humanizePercentage }} of background operation attempts failed in the last minute (CLUSTER_ERROR_RATE_THRESHOLD %s).", formatPercent(threshold)),
+			},
+		})
+	}
+
+	ports := []AlertRule{
+		{
+			Alert:  "VMPortPoolNearlyExhausted",
+			Expr:   fmt.Sprintf("max(%s) / max(%s) >= %s", sel("vm_port_pool_used"), sel("vm_port_pool_size"), formatFloat(portRatio)),
+			For:    "5m",
+			Labels: map[string]string{"severity": "warning"},
+			Annotations: map[string]string{
+				"summary":     "VM NodePort pool is running out",
+				"description": fmt.Sprintf("{{ $value | humanizePercentage }} of the %d NodePorts (%d-%d) are assigned.", vmservice.PortPoolSize, vmservice.PortRangeStart, vmservice.PortRangeEnd),
+			},
+		},
+		{
+			Alert:  "VMPortPoolExhausted",
+			Expr:   fmt.Sprintf("max(%s) >= max(%s)", sel("vm_port_pool_used"), sel("vm_port_pool_size")),
+			For:    "1m",
+			Labels: map[string]string{"severity": "critical"},
+			Annotations: map[string]string{
+				"summary":     "VM NodePort pool is exhausted",
+				"description": fmt.Sprintf("All %d NodePorts are assigned; new VMs cannot be created.", vmservice.PortPoolSize),
+			},
+		},
+	}
+
+	groups := []AlertRuleGroup{
+		{Name: "vm-controller.provisioning", Rules: provisioning},
+		{Name: "vm-controller.queue", Rules: queue},
+		{Name: "vm-controller.ports", Rules: ports},
+	}
+
+	if interval := backup.Interval(); interval > 0 {
+		groups = append(groups, AlertRuleGroup{Name: "vm-controller.backup", Rules: []AlertRule{{
+			Alert:  "ControllerBackupFailing",
+			Expr:   fmt.Sprintf("sum(increase(%s[%s])) > 0", sel("platform_backup_runs_total", `result="failure"`), promDuration(2*interval)),
+			Labels: map[string]string{"severity": "warning"},
+			Annotations: map[string]string{
+				"summary":     "Controller database backup failed",
+				"description": fmt.Sprintf("A scheduled backup (every %s) failed in the last two intervals.", interval),
+			},
+		}}})
+	}
+
+	return groups
+}
+
+// PrometheusRuleFor는 AlertRuleGroups를 prometheus-operator PrometheusRule 리소스로 감쌉니다.
+func PrometheusRuleFor(opts AlertRuleOptions) PrometheusRule {
+	rule := PrometheusRule{APIVersion: "monitoring.coreos.com/v1", Kind: "PrometheusRule"}
+	rule.Metadata.Name = "vm-controller-alerts"
+	rule.Metadata.Namespace = opts.Namespace
+	rule.Metadata.Labels = map[string]string{managedByLabel: managedByValue}
+	for k, v := range opts.Labels {
+		rule.Metadata.Labels[k] = v
+	}
+	rule.Spec.Groups = AlertRuleGroups(opts)
+	return rule
+}
+
+// asyncWorkerCount는 작업 슬롯 수입니다. (ASYNC_WORKERS, 0 이하면 기본값)
+func asyncWorkerCount() int {
+	if workers := intEnv("ASYNC_WORKERS", defaultAsyncWorkers); workers > 0 {
+		return workers
+	}
+	return defaultAsyncWorkers
+}
+
+func floatEnv(key string, defaultValue float64) float64 {
+	if value, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func formatPercent(ratio float64) string {
+	return formatFloat(ratio*100) + "%"
+}
+
+// promDuration은 PromQL 범위 선택자 형식(예: 43200s)으로 변환합니다. (Go의 "12h0m0s"는 PromQL에서 쓸 수 없음)
+func promDuration(d time.Duration) string {
+	return fmt.Sprintf("%ds", int64(d.Seconds()))
+}
@@ -0,0 +1,383 @@
+package k8s_service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"time"
+	"vm-controller/internal/config"
+	"vm-controller/internal/logger"
+	"vm-controller/internal/metrics"
+	"vm-controller/internal/models"
+	operationservice "vm-controller/internal/services/operation_service"
+)
+
+var (
+	asyncOperationsTotal = metrics.NewCounterVec("async_operations_total", "Background operations by final result.", "operation", "result", "tenant")
+	asyncPanicsTotal     = metrics.NewCounterVec("async_operation_panics_total", "Panics recovered from background operations.", "operation")
+
+	// 작업 슬롯 대기 시간 (평균 대기 = wait_seconds_total / started_total 증가율)
+	asyncQueueWaitSeconds = metrics.NewCounterVec("async_queue_wait_seconds_total", "Total time background operations waited for a worker slot.", "operation")
+	asyncQueueStarted     = metrics.NewCounterVec("async_queue_started_total", "Background operations that obtained a worker slot.", "operation")
+)
+
+// 재시도 간격 (시도마다 2배씩 증가)
+const asyncRetryBaseDelay = 5 * time.Second
+
+// 인스턴스마다 동시에 실행하는 백그라운드 작업 기본 수 (ASYNC_WORKERS)
+const defaultAsyncWorkers = 16
+
+// AsyncOperation은 API 요청과 분리되어 백그라운드에서 실행되는 작업입니다.
+// 작업은 operations 테이블에 이름(Name)과 인자(payload)로 기록되고, 워커가 asyncOperationBuilders로 다시 만들어 실행합니다.
+type AsyncOperation struct {
+	Name       string          // 작업 이름 (예: vm.stop, asyncOperationBuilders에 등록된 이름)
+	Target     string          // 대상 리소스 (예: vm/my-vps)
+	Tenant     string          // 메트릭 테넌트 라벨 (metrics.TenantLabel, 비어 있으면 none)
+	Run        func() error    // 실제 작업
+	Compensate func(err error) // 모든 시도가 실패했을 때 실행되는 보상 작업 (상태 Failed 처리 등)
+	OnSuccess  func()          // 작업이 성공했을 때 실행 (결과 기록 등)
+	MaxRetries int             // 실패(패닉 포함) 시 재시도 횟수
+	Owner      uint            // 대상 리소스 소유자 (GET /api/operations/:id 조회 권한, 0이면 관리자만)
+
+	// 작업을 요청한 HTTP 요청의 trace id (컨텍스트 "trace_id")
+	// 작업은 자기 root span으로 기록되고 이 trace를 링크로 남깁니다. converger처럼 요청 없이 시작된 작업은 비워 둡니다.
+	TraceID string
+	// 작업을 요청한 HTTP 요청의 ID (X-Request-ID). 비어 있으면 WithRequest로 묶인 컨텍스트에서 가져옵니다.
+	RequestID string
+
+	payload asyncPayload // 워커가 작업을 다시 만들 때 쓰는 인자
+}
+
+// RunAsync는 op를 작업 큐(operations 테이블)에 Queued로 넣고, 진행 상태를 조회할 작업 ID를 반환합니다.
+// 작업은 어느 인스턴스의 워커(StartAsyncWorkers)든 점유하여 실행하며, 서버가 재시작되어도 이어서 실행됩니다.
+// 패닉은 복구되어 에러로 취급되며, 재시도 후에도 실패하면 Compensate를 호출합니다.
+// 작업을 기록하지 못하면 실행하지 않고 Compensate를 호출한 뒤 0을 반환합니다.
+//
+// 같은 Target에 대한 작업이 이미 대기 중이거나 실행 중이면 새 작업은 건너뜁니다.
+// (목표 상태는 DB에 저장되어 있으므로 converger가 이후에 수렴시킵니다)
+// 건너뛴 작업은 요청(TraceID)으로 시작된 경우에만 기록하여, converger의 반복 시도가 기록을 채우지 않도록 합니다.
+func (s *K8sService) RunAsync(op AsyncOperation) uint {
+	if op.Tenant == "" {
+		op.Tenant = metrics.TenantNone
+	}
+	if op.RequestID == "" {
+		op.RequestID = logger.RequestIDFrom(s.baseContext())
+	}
+	if op.TraceID == "" {
+		op.TraceID = logger.TraceIDFrom(s.baseContext())
+	}
+
+	operation, err := newOperationRecord(op, models.OperationQueued)
+	if err == nil {
+		err = s.operationService.CreateActiveOperation(operation)
+	}
+
+	if errors.Is(err, operationservice.ErrOperationInFlight) {
+		slog.Info("async operation skipped: another operation is in flight", asyncAttrs(op)...)
+		asyncOperationsTotal.Inc(op.Name, "skipped", op.Tenant)
+		if op.TraceID == "" {
+			return 0
+		}
+		id := s.recordOperation(op, models.OperationSkipped)
+		s.finishOperation(id, models.OperationSkipped, "another operation is in flight")
+		return id
+	}
+	if err != nil {
+		slog.Error("async: failed to queue operation", append(asyncAttrs(op), "error", err.Error())...)
+		asyncOperationsTotal.Inc(op.Name, "failed", op.Tenant)
+		compensate(op, fmt.Errorf("failed to queue operation: %w", err))
+		return 0
+	}
+
+	asyncDepth.Add(1)
+	s.wakeAsyncWorkers()
+	return operation.ID
+}
+
+// newOperationRecord는 op의 작업 기록을 만듭니다. 워커가 다시 만들 수 없는 작업(등록되지 않은 이름)이면 에러를 반환합니다.
+func newOperationRecord(op AsyncOperation, status models.EnumOperationStatus) (*models.Operation, error) {
+	if _, ok := asyncOperationBuilders[op.Name]; !ok {
+		return nil, fmt.Errorf("operation %s is not registered", op.Name)
+	}
+	payload, err := json.Marshal(op.payload)
+	if err != nil {
+		return nil, err
+	}
+
+	operation := &models.Operation{
+		Name:      op.Name,
+		Target:    op.Target,
+		Status:    status,
+		TraceID:   op.TraceID,
+		RequestID: op.RequestID,
+		Payload:   string(payload),
+	}
+	if op.Owner != 0 {
+		owner := op.Owner
+		operation.UserID = &owner
+	}
+	return operation, nil
+}
+
+// recordOperation은 건너뛴 작업을 기록하고 ID를 반환합니다. 기록 실패가 요청을 막지 않도록 에러는 로그만 남깁니다.
+func (s *K8sService) recordOperation(op AsyncOperation, status models.EnumOperationStatus) uint {
+	operation, err := newOperationRecord(op, status)
+	if err == nil {
+		err = s.operationService.CreateOperation(operation)
+	}
+	if err != nil {
+		slog.Error("async: failed to record operation", append(asyncAttrs(op), "error", err.Error())...)
+		return 0
+	}
+	return operation.ID
+}
+
+func (s *K8sService) finishOperation(id uint, status models.EnumOperationStatus, reason string) {
+	if id == 0 {
+		return
+	}
+	if err := s.operationService.FinishOperation(id, status, reason); err != nil {
+		slog.Error("async: failed to finish operation", "operation_id", id, "error", err)
+	}
+}
+
+// compensate는 op의 보상 작업을 실행합니다. (패닉은 로그만 남김)
+func compensate(op AsyncOperation, err error) {
+	if op.Compensate == nil {
+		return
+	}
+	compensateErr := runRecovered(op.Name+".compensate", op.Target, func() error {
+		op.Compensate(err)
+		return nil
+	})
+	if compensateErr != nil {
+		slog.Error("async operation compensation failed", append(asyncAttrs(op), "error", compensateErr.Error())...)
+	}
+}
+
+// asyncSpan은 백그라운드 작업 하나의 root span입니다.
+// 시작/종료 로그에 OpenTelemetry 로그 필드(trace_id, span_id)와 요청 trace 링크(link_trace_id)를 남겨
+// 로그 수집기에서 API 요청과 그 요청이 만든 작업을 연결할 수 있게 합니다.
+type asyncSpan struct {
+	op      AsyncOperation
+	traceID string
+	spanID  string
+	start   time.Time
+}
+
+func startAsyncSpan(op AsyncOperation) asyncSpan {
+	span := asyncSpan{op: op, traceID: logger.NewTraceID(), spanID: logger.NewSpanID(), start: time.Now()}
+	slog.Info("async operation started", span.attrs()...)
+	return span
+}
+
+func (span asyncSpan) attrs() []any {
+	attrs := append(asyncAttrs(span.op), "trace_id", span.traceID, "span_id", span.spanID)
+	if span.op.TraceID != "" {
+		attrs = append(attrs, "link_trace_id", span.op.TraceID)
+	}
+	return attrs
+}
+
+// asyncAttrs는 작업 로그에 공통으로 남기는 속성입니다. 요청으로 시작된 작업은 request_id로 API 접근 로그와 연결됩니다.
+func asyncAttrs(op AsyncOperation) []any {
+	attrs := []any{
+		"component", "async",
+		"operation", op.Name,
+		"target", op.Target,
+		"tenant", op.Tenant,
+	}
+	if op.RequestID != "" {
+		attrs = append(attrs, "request_id", op.RequestID)
+	}
+	return attrs
+}
+
+func (span asyncSpan) end(result string, err error) {
+	attrs := append(span.attrs(), "result", result, "elapsed_ms", time.Since(span.start).Milliseconds())
+	if result != "success" && err != nil {
+		slog.Warn("async operation finished", append(attrs, "error", err.Error())...)
+		return
+	}
+	slog.Info("async operation finished", attrs...)
+}
+
+// runRecovered는 fn 실행 중 발생한 패닉을 스택과 함께 기록하고 에러로 변환합니다.
+func runRecovered(name, target string, fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			asyncPanicsTotal.Inc(name)
+			slog.Error("async: panic", "operation", name, "target", target, "panic", fmt.Sprint(r), "stack", string(debug.Stack()))
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return fn()
+}
+
+// StopVMAsync는 VM 정지를 백그라운드로 실행합니다. (정지 패치는 멱등이므로 재시도)
+func (s *K8sService) StopVMAsync(vm *models.VirtualMachine, traceID string) uint {
+	return s.queueAsync(s.stopVMOperation(vm), traceID)
+}
+
+func (s *K8sService) stopVMOperation(vm *models.VirtualMachine) AsyncOperation {
+	return AsyncOperation{
+		Name:       "vm.stop",
+		Target:     "vm/" + vm.Name,
+		Tenant:     metrics.TenantLabelFor(vm.UserID),
+		Owner:      vm.UserID,
+		Run:        func() error { return s.StopVM(vm) },
+		Compensate: s.markVMFailed(vm, "stop"),
+		OnSuccess:  s.markVMSucceeded(vm, "stop"),
+		MaxRetries: 2,
+		payload:    asyncPayload{VM: vm.Name},
+	}
+}
+
+// StartVMAsync는 VM 시작을 백그라운드로 실행합니다.
+func (s *K8sService) StartVMAsync(vm *models.VirtualMachine, traceID string) uint {
+	return s.queueAsync(s.startVMOperation(vm), traceID)
+}
+
+func (s *K8sService) startVMOperation(vm *models.VirtualMachine) AsyncOperation {
+	return AsyncOperation{
+		Name:       "vm.start",
+		Target:     "vm/" + vm.Name,
+		Tenant:     metrics.TenantLabelFor(vm.UserID),
+		Owner:      vm.UserID,
+		Run:        func() error { return s.StartVM(vm) },
+		Compensate: s.markVMFailed(vm, "start"),
+		OnSuccess:  s.markVMSucceeded(vm, "start"),
+		MaxRetries: 2,
+		payload:    asyncPayload{VM: vm.Name},
+	}
+}
+
+// DeleteVMAsync는 VM 삭제를 백그라운드로 실행합니다. (이미 삭제된 리소스는 건너뛰므로 재시도 가능)
+func (s *K8sService) DeleteVMAsync(vm *models.VirtualMachine, traceID string) uint {
+	return s.queueAsync(s.deleteVMOperation(vm, nil), traceID)
+}
+
+// DeleteVMWithPolicyAsync는 관리자가 지정한 삭제 옵션으로 VM 삭제를 백그라운드로 실행합니다.
+func (s *K8sService) DeleteVMWithPolicyAsync(vm *models.VirtualMachine, policy config.DeletePolicy, traceID string) uint {
+	return s.queueAsync(s.deleteVMOperation(vm, &policy), traceID)
+}
+
+// deleteVMOperation은 VM 삭제 작업입니다. policy가 nil이면 설정된 삭제 정책을 따릅니다.
+func (s *K8sService) deleteVMOperation(vm *models.VirtualMachine, policy *config.DeletePolicy) AsyncOperation {
+	run := func() error { return s.DeleteVM(vm) }
+	if policy != nil {
+		run = func() error { return s.DeleteVMWithPolicy(vm, policy) }
+	}
+
+	return AsyncOperation{
+		Name:       "vm.delete",
+		Target:     "vm/" + vm.Name,
+		Tenant:     metrics.TenantLabelFor(vm.UserID),
+		Owner:      vm.UserID,
+		Run:        run,
+		Compensate: s.markVMFailed(vm, "delete"),
+		OnSuccess:  s.markVMSucceeded(vm, "delete"),
+		MaxRetries: 3,
+		payload:    asyncPayload{VM: vm.Name, Policy: policy},
+	}
+}
+
+// queueAsync는 작업을 요청한 HTTP 요청의 trace id를 붙여 작업을 큐에 넣습니다.
+func (s *K8sService) queueAsync(op AsyncOperation, traceID string) uint {
+	op.TraceID = traceID
+	return s.RunAsync(op)
+}
+
+// BuildDeploymentAsync는 배포 빌드를 백그라운드로 실행합니다.
+// 빌드 Job 생성은 멱등하지 않으므로 재시도하지 않습니다.
+func (s *K8sService) BuildDeploymentAsync(deployment *models.Deployment, namespace, traceID string) uint {
+	return s.queueAsync(s.buildDeploymentOperation(deployment, namespace), traceID)
+}
+
+func (s *K8sService) buildDeploymentOperation(deployment *models.Deployment, namespace string) AsyncOperation {
+	return AsyncOperation{
+		Name:   "deployment.build",
+		Target: fmt.Sprintf("deployment/%d", deployment.ID),
+		Tenant: metrics.TenantLabelFor(deployment.UserID),
+		Owner:  deployment.UserID,
+		Run:    func() error { return s.BuildDeployment(deployment, namespace) },
+		Compensate: func(err error) {
+			if errStatus := s.deploymentService.UpdateDeploymentStatus(deployment.ID, models.DeploymentStatusFailed); errStatus != nil {
+				s.log().Error("async: failed to mark deployment as Failed", "deployment_id", deployment.ID, "error", errStatus)
+			}
+		},
+		payload: asyncPayload{DeploymentID: deployment.ID, Namespace: namespace},
+	}
+}
+
+// CreateManagedDatabaseAsync는 관리형 DB 생성을 백그라운드로 실행합니다. (실패 시 내부에서 롤백)
+func (s *K8sService) CreateManagedDatabaseAsync(database *models.ManagedDatabase, traceID string) uint {
+	return s.queueAsync(s.createDatabaseOperation(database), traceID)
+}
+
+func (s *K8sService) createDatabaseOperation(database *models.ManagedDatabase) AsyncOperation {
+	return AsyncOperation{
+		Name:       "database.create",
+		Target:     "database/" + database.Name,
+		Tenant:     metrics.TenantLabelFor(database.UserID),
+		Owner:      database.UserID,
+		Run:        func() error { return s.CreateManagedDatabase(database) },
+		Compensate: s.markDatabaseFailed(database),
+		payload:    asyncPayload{DatabaseID: database.ID},
+	}
+}
+
+// DeleteManagedDatabaseAsync는 관리형 DB 삭제를 백그라운드로 실행합니다.
+func (s *K8sService) DeleteManagedDatabaseAsync(database *models.ManagedDatabase, traceID string) uint {
+	return s.queueAsync(s.deleteDatabaseOperation(database), traceID)
+}
+
+func (s *K8sService) deleteDatabaseOperation(database *models.ManagedDatabase) AsyncOperation {
+	return AsyncOperation{
+		Name:       "database.delete",
+		Target:     "database/" + database.Name,
+		Tenant:     metrics.TenantLabelFor(database.UserID),
+		Owner:      database.UserID,
+		Run:        func() error { return s.DeleteManagedDatabase(database) },
+		Compensate: s.markDatabaseFailed(database),
+		MaxRetries: 3,
+		payload:    asyncPayload{DatabaseID: database.ID},
+	}
+}
+
+// markVMSucceeded는 VM 작업 완료를 이벤트 스트림에 기록합니다.
+func (s *K8sService) markVMSucceeded(vm *models.VirtualMachine, operation string) func() {
+	return func() {
+		s.vmEventService.Record(models.VmEvent{
+			VmName:    vm.Name,
+			Type:      models.VmEventOperationSucceeded,
+			Operation: operation,
+		})
+	}
+}
+
+func (s *K8sService) markVMFailed(vm *models.VirtualMachine, operation string) func(err error) {
+	return func(err error) {
+		s.vmEventService.Record(models.VmEvent{
+			VmName:    vm.Name,
+			Type:      models.VmEventOperationFailed,
+			Operation: operation,
+			Detail:    err.Error(),
+		})
+
+		if errStatus := s.vmService.MarkVmFailed(vm.Name); errStatus != nil {
+			slog.Error("async: failed to mark VM as Failed", "vm", vm.Name, "error", errStatus)
+		}
+	}
+}
+
+func (s *K8sService) markDatabaseFailed(database *models.ManagedDatabase) func(err error) {
+	return func(err error) {
+		if errStatus := s.databaseService.UpdateDatabaseStatus(database.ID, "Failed"); errStatus != nil {
+			slog.Error("async: failed to mark database as Failed", "database_id", database.ID, "error", errStatus)
+		}
+	}
+}
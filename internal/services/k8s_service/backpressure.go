@@ -0,0 +1,147 @@
+package k8s_service
+
+import (
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+	"vm-controller/internal/metrics"
+)
+
+// 백그라운드 작업 큐 역압(backpressure) 기본값
+const (
+	defaultAsyncQueueMaxDepth    = 50               // ASYNC_QUEUE_MAX_DEPTH
+	defaultClusterErrorRate      = 0.5              // CLUSTER_ERROR_RATE_THRESHOLD
+	defaultClusterErrorMinSample = 10               // CLUSTER_ERROR_RATE_MIN_SAMPLES
+	defaultBackpressureRetry     = 30 * time.Second // BACKPRESSURE_RETRY_AFTER
+
+	// 에러율은 최근 1분(10초 버킷 6개)의 작업 시도 결과로 계산
+	errorRateBucket  = 10 * time.Second
+	errorRateBuckets = 6
+)
+
+// 역압 사유
+const (
+	BackpressureQueueDepth = "queue_depth"
+	BackpressureErrorRate  = "error_rate"
+)
+
+// 대기 중이거나 실행 중인 백그라운드 작업 수 (모든 인스턴스 기준, 워커가 operations 테이블에서 주기적으로 갱신)
+var asyncDepth atomic.Int64
+
+func init() {
+	metrics.NewGaugeFunc("async_queue_depth", "Background operations currently queued or running.", func() float64 { return float64(asyncDepth.Load()) })
+	metrics.NewGaugeFunc("async_cluster_error_rate", "Ratio of failed background operation attempts over the last minute.", func() float64 {
+		rate, _ := clusterErrors.rate(time.Now())
+		return rate
+	})
+	metrics.NewGaugeFunc("async_backpressure_active", "1 while new expensive operations are rejected with 429.", func() float64 {
+		if GetBackpressure().Overloaded {
+			return 1
+		}
+		return 0
+	})
+}
+
+// BackpressureStatus는 /readyz와 429 응답에 노출되는 작업 큐 상태입니다.
+type BackpressureStatus struct {
+	Overloaded bool          `json:"overloaded"`
+	Reason     string        `json:"reason,omitempty"` // queue_depth / error_rate
+	Depth      int64         `json:"depth"`
+	MaxDepth   int64         `json:"max_depth"`
+	ErrorRate  float64       `json:"error_rate"`
+	Samples    int           `json:"samples"`
+	RetryAfter time.Duration `json:"-"`
+}
+
+// GetBackpressure는 새 작업을 받아도 되는지 판단합니다.
+// 실행 중인 작업 수가 ASYNC_QUEUE_MAX_DEPTH 이상이거나, 최근 1분간 작업 시도의 실패 비율이
+// CLUSTER_ERROR_RATE_THRESHOLD 이상이면(표본이 CLUSTER_ERROR_RATE_MIN_SAMPLES 이상일 때) 과부하로 봅니다.
+// (각 값이 0이면 해당 조건은 사용하지 않음)
+func GetBackpressure() BackpressureStatus {
+	status := BackpressureStatus{
+		Depth:      asyncDepth.Load(),
+		MaxDepth:   int64(intEnv("ASYNC_QUEUE_MAX_DEPTH", defaultAsyncQueueMaxDepth)),
+		RetryAfter: defaultBackpressureRetry,
+	}
+	if retryAfter, err := time.ParseDuration(os.Getenv("BACKPRESSURE_RETRY_AFTER")); err == nil && retryAfter > 0 {
+		status.RetryAfter = retryAfter
+	}
+	status.ErrorRate, status.Samples = clusterErrors.rate(time.Now())
+
+	threshold := clusterErrorRateThreshold()
+
+	switch {
+	case status.MaxDepth > 0 && status.Depth >= status.MaxDepth:
+		status.Overloaded = true
+		status.Reason = BackpressureQueueDepth
+	case threshold > 0 && status.Samples >= intEnv("CLUSTER_ERROR_RATE_MIN_SAMPLES", defaultClusterErrorMinSample) && status.ErrorRate >= threshold:
+		status.Overloaded = true
+		status.Reason = BackpressureErrorRate
+	}
+
+	return status
+}
+
+// clusterErrorRateThreshold는 과부하로 보는 작업 실패 비율입니다. (CLUSTER_ERROR_RATE_THRESHOLD, 0이면 사용 안 함)
+func clusterErrorRateThreshold() float64 {
+	if value, err := strconv.ParseFloat(os.Getenv("CLUSTER_ERROR_RATE_THRESHOLD"), 64); err == nil && value >= 0 {
+		return value
+	}
+	return defaultClusterErrorRate
+}
+
+func intEnv(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value >= 0 {
+		return value
+	}
+	return defaultValue
+}
+
+// errorWindow는 작업 시도 결과를 10초 단위 버킷에 모아 최근 1분의 실패 비율을 계산합니다.
+type errorWindow struct {
+	mu      sync.Mutex
+	buckets [errorRateBuckets]struct {
+		start  int64 // 버킷 시작 시각 (unix, errorRateBucket 단위)
+		total  int
+		failed int
+	}
+}
+
+var clusterErrors errorWindow
+
+func (w *errorWindow) record(now time.Time, failed bool) {
+	slot := now.Unix() / int64(errorRateBucket/time.Second)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	bucket := &w.buckets[slot%errorRateBuckets]
+	if bucket.start != slot {
+		bucket.start, bucket.total, bucket.failed = slot, 0, 0
+	}
+	bucket.total++
+	if failed {
+		bucket.failed++
+	}
+}
+
+func (w *errorWindow) rate(now time.Time) (float64, int) {
+	slot := now.Unix() / int64(errorRateBucket/time.Second)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	total, failed := 0, 0
+	for _, bucket := range w.buckets {
+		if slot-bucket.start < errorRateBuckets {
+			total += bucket.total
+			failed += bucket.failed
+		}
+	}
+	if total == 0 {
+		return 0, 0
+	}
+	return float64(failed) / float64(total), total
+}
@@ -0,0 +1,202 @@
+package k8s_service
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/serializer/yaml"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// bootstrapBundle은 서버 시작 시 설치하는 플랫폼 공용 리소스입니다. (네임스페이스, PriorityClass, 인터셉터 Middleware, 인증서 발급자)
+// 사용자 리소스 템플릿(yaml-data)과 달리 바이너리에 포함되어 배포 디렉터리와 무관하게 같은 내용이 적용됩니다.
+//
+//go:embed bootstrap/*.yaml
+var bootstrapBundle embed.FS
+
+// server-side apply 필드 소유자 이름
+const bootstrapFieldManager = "vm-controller-bootstrap"
+
+const defaultACMEServer = "https://acme-v02.api.letsencrypt.org/directory"
+
+var bootstrapPlaceholder = regexp.MustCompile(`{{[A-Z_]+}}`)
+
+// 부트스트랩 결과
+const (
+	BootstrapCreated   = "created"
+	BootstrapUpdated   = "updated"
+	BootstrapUnchanged = "unchanged"
+	BootstrapSkipped   = "skipped" // 설정 값이 없거나 CRD가 설치되지 않음
+	BootstrapFailed    = "failed"
+)
+
+// BootstrapResult는 부트스트랩 리소스 하나의 적용 결과입니다.
+type BootstrapResult struct {
+	File      string `json:"file"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Action    string `json:"action"`
+	Detail    string `json:"detail,omitempty"`
+}
+
+var (
+	lastBootstrapMu sync.RWMutex
+	lastBootstrap   []BootstrapResult
+)
+
+// LastBootstrap은 마지막 부트스트랩 결과를 반환합니다. (서버 시작 후 실행 전이면 nil)
+func LastBootstrap() []BootstrapResult {
+	lastBootstrapMu.RLock()
+	defer lastBootstrapMu.RUnlock()
+	return lastBootstrap
+}
+
+// bootstrapReplacements는 부트스트랩 템플릿의 치환 값입니다. 값이 빈 항목을 참조하는 리소스는 건너뜁니다.
+func bootstrapReplacements() map[string]string {
+	acmeServer := os.Getenv("BOOTSTRAP_ACME_SERVER")
+	if acmeServer == "" {
+		acmeServer = defaultACMEServer
+	}
+	return map[string]string{
+		"{{INTERCEPT_URL}}": os.Getenv("BOOTSTRAP_INTERCEPT_URL"),
+		"{{ACME_EMAIL}}":    os.Getenv("BOOTSTRAP_ACME_EMAIL"),
+		"{{ACME_SERVER}}":   acmeServer,
+	}
+}
+
+// Bootstrap은 내장 번들의 플랫폼 공용 리소스를 server-side apply로 적용하고 리소스별 결과를 반환합니다.
+// 여러 번 실행해도 같은 상태가 되며, 번들에 적힌 필드만 소유하므로 관리자가 추가한 라벨 등은 유지됩니다.
+// 하나가 실패해도 나머지는 계속 적용하며, 실패가 있으면 결과와 함께 에러를 반환합니다.
+func (s *K8sService) Bootstrap() ([]BootstrapResult, error) {
+	files, err := bootstrapBundle.ReadDir("bootstrap")
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name() < files[j].Name() })
+
+	replacements := bootstrapReplacements()
+	decoder := yaml.NewDecodingSerializer(unstructured.UnstructuredJSONScheme)
+	results := []BootstrapResult{}
+	failed := 0
+
+	for _, file := range files {
+		content, err := bootstrapBundle.ReadFile(path.Join("bootstrap", file.Name()))
+		if err != nil {
+			return results, err
+		}
+		docs, err := splitYAMLDocuments(string(content))
+		if err != nil {
+			return results, fmt.Errorf("failed to split bootstrap %s: %v", file.Name(), err)
+		}
+
+		for _, doc := range docs {
+			text, missing := renderBootstrapDoc(string(doc), replacements)
+
+			obj := &unstructured.Unstructured{}
+			if _, _, err := decoder.Decode([]byte(text), nil, obj); err != nil {
+				return results, fmt.Errorf("failed to decode bootstrap %s: %v", file.Name(), err)
+			}
+			result := BootstrapResult{File: file.Name(), Kind: obj.GetKind(), Namespace: obj.GetNamespace(), Name: obj.GetName()}
+
+			if len(missing) > 0 {
+				result.Action = BootstrapSkipped
+				result.Detail = "not configured: " + strings.Join(missing, ", ")
+			} else {
+				result.Action, result.Detail = s.applyBootstrapObject(obj)
+			}
+			if result.Action == BootstrapFailed {
+				failed++
+			}
+			results = append(results, result)
+		}
+	}
+
+	lastBootstrapMu.Lock()
+	lastBootstrap = results
+	lastBootstrapMu.Unlock()
+
+	if failed > 0 {
+		return results, fmt.Errorf("%d bootstrap resource(s) failed to apply", failed)
+	}
+	return results, nil
+}
+
+// renderBootstrapDoc은 치환 값을 적용하고, 값이 비어 있는 설정 이름(BOOTSTRAP_*)을 반환합니다.
+func renderBootstrapDoc(text string, replacements map[string]string) (string, []string) {
+	var missing []string
+	for _, placeholder := range bootstrapPlaceholder.FindAllString(text, -1) {
+		if replacements[placeholder] == "" {
+			missing = append(missing, "BOOTSTRAP_"+strings.Trim(placeholder, "{}"))
+		}
+	}
+	for k, v := range replacements {
+		text = strings.ReplaceAll(text, k, v)
+	}
+	return text, missing
+}
+
+// applyBootstrapObject는 객체 하나를 server-side apply로 적용하고 결과(action, detail)를 반환합니다.
+// 적용 전후 resourceVersion으로 생성/변경/유지를 구분합니다.
+func (s *K8sService) applyBootstrapObject(obj *unstructured.Unstructured) (string, string) {
+	ctx := s.baseContext()
+	setOwnershipLabels(obj, "")
+	gvk := obj.GroupVersionKind()
+
+	mapping, err := s.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return BootstrapSkipped, fmt.Sprintf("%s is not installed in the cluster", gvk.GroupVersion().String())
+		}
+		return BootstrapFailed, err.Error()
+	}
+
+	var dri dynamic.ResourceInterface
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		dri = s.dynamicClient.Resource(mapping.Resource).Namespace(obj.GetNamespace())
+	} else {
+		dri = s.dynamicClient.Resource(mapping.Resource)
+	}
+
+	previousVersion := ""
+	existing, err := dri.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	switch {
+	case err == nil:
+		previousVersion = existing.GetResourceVersion()
+	case !apierrors.IsNotFound(err):
+		return BootstrapFailed, err.Error()
+	}
+
+	data, err := json.Marshal(obj.Object)
+	if err != nil {
+		return BootstrapFailed, err.Error()
+	}
+	force := true
+	applied, err := dri.Patch(ctx, obj.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{
+		FieldManager: bootstrapFieldManager,
+		Force:        &force,
+	})
+	if err != nil {
+		return BootstrapFailed, err.Error()
+	}
+
+	switch {
+	case previousVersion == "":
+		return BootstrapCreated, ""
+	case applied.GetResourceVersion() != previousVersion:
+		return BootstrapUpdated, ""
+	}
+	return BootstrapUnchanged, ""
+}
@@ -0,0 +1,155 @@
+package k8s_service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"vm-controller/internal/models"
+)
+
+// BuildParams는 배포 이미지 빌드 Job 생성을 위한 파라미터입니다.
+type BuildParams struct {
+	UserNamespace  string
+	DeploymentName string
+	RepoURL        string
+	Branch         string
+	ContextDir     string
+	DockerfilePath string
+	Strategy       string
+}
+
+// registryHost는 빌드된 이미지를 푸시할 레지스트리 주소를 반환합니다.
+func registryHost() string {
+	host := os.Getenv("REGISTRY_HOST")
+	if host == "" {
+		host = "registry.cloud-admin.svc:5000" // 클러스터 내부 레지스트리 기본값
+	}
+	return host
+}
+
+// DeploymentImage는 배포에 대응하는 컨테이너 이미지 이름을 반환합니다.
+func DeploymentImage(namespace, deploymentName string) string {
+	return fmt.Sprintf("%s/%s/%s:latest", registryHost(), namespace, deploymentName)
+}
+
+// DeploymentResourceName은 배포 ID로부터 K8s 리소스 이름을 생성합니다.
+func DeploymentResourceName(deployment *models.Deployment) string {
+	return fmt.Sprintf("deploy-%d", deployment.ID)
+}
+
+// checkBuildInjection은 빌드 Job 템플릿에 치환될 값들을 검증합니다.
+// 서비스 계층에서 이미 검증하지만, 템플릿 치환 직전 한 번 더 확인합니다.
+func (s *K8sService) checkBuildInjection(params BuildParams) error {
+	if params.UserNamespace == "" || params.DeploymentName == "" || params.RepoURL == "" || params.Branch == "" {
+		return fmt.Errorf("invalid parameters: empty values not allowed (필수 파라미터 누락)")
+	}
+
+	dns1123Regex := regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	if !dns1123Regex.MatchString(params.UserNamespace) || !dns1123Regex.MatchString(params.DeploymentName) {
+		return fmt.Errorf("invalid namespace or deployment name (must be DNS-1123 compliant)")
+	}
+
+	safeRegex := regexp.MustCompile(`^[A-Za-z0-9._/:-]+$`)
+	for _, v := range []string{params.RepoURL, params.Branch, params.ContextDir, params.DockerfilePath} {
+		if v != "" && !safeRegex.MatchString(v) {
+			return fmt.Errorf("invalid build parameter: %s (contains invalid characters)", v)
+		}
+	}
+
+	return nil
+}
+
+// CreateBuildJob은 빌드 방식(dockerfile/buildpacks)에 맞는 빌드 Job을 생성합니다.
+// dockerfile: kaniko가 git 컨텍스트에서 직접 빌드
+// buildpacks: 소스 클론 후 CNB lifecycle이 언어를 감지하여 빌드
+func (s *K8sService) CreateBuildJob(params BuildParams) ([]CreatedResource, error) {
+	if err := s.checkBuildInjection(params); err != nil {
+		return nil, err
+	}
+
+	var manifestDir string
+	switch params.Strategy {
+	case models.BuildStrategyDockerfile:
+		manifestDir = filepath.Join("yaml-data", "client-build", "dockerfile")
+	case models.BuildStrategyBuildpacks:
+		manifestDir = filepath.Join("yaml-data", "client-build", "buildpacks")
+	default:
+		return nil, fmt.Errorf("unknown build strategy: %s", params.Strategy)
+	}
+
+	repoURL := strings.TrimSuffix(params.RepoURL, ".git") + ".git"
+	replacements := map[string]string{
+		"{{NAMESPACE}}":       params.UserNamespace,
+		"{{DEPLOYMENT_NAME}}": params.DeploymentName,
+		"{{REPO_URL}}":        repoURL,
+		"{{GIT_CONTEXT}}":     "git://" + strings.TrimPrefix(repoURL, "https://") + "#refs/heads/" + params.Branch,
+		"{{BRANCH}}":          params.Branch,
+		"{{CONTEXT_DIR}}":     params.ContextDir,
+		"{{DOCKERFILE_PATH}}": params.DockerfilePath,
+		"{{REGISTRY_HOST}}":   registryHost(),
+		"{{IMAGE}}":           DeploymentImage(params.UserNamespace, params.DeploymentName),
+	}
+
+	return s.applyManifests(manifestDir, replacements, params.UserNamespace, false)
+}
+
+// CreateWebDeployment는 웹 서비스 유형 배포의 Deployment/Service/Ingress를 생성합니다.
+func (s *K8sService) CreateWebDeployment(deployment *models.Deployment, namespace string) ([]CreatedResource, error) {
+	name := DeploymentResourceName(deployment)
+	replacements := map[string]string{
+		"{{NAMESPACE}}":       namespace,
+		"{{DEPLOYMENT_NAME}}": name,
+		"{{DOMAIN}}":          deployment.Domain,
+		"{{IMAGE}}":           DeploymentImage(namespace, name),
+	}
+
+	return s.applyManifests(filepath.Join("yaml-data", "client-deployment"), replacements, namespace, false)
+}
+
+// BuildDeployment는 배포의 빌드 Job과 실행 리소스를 생성하고 DB 상태를 갱신합니다.
+func (s *K8sService) BuildDeployment(deployment *models.Deployment, namespace string) error {
+	if err := s.applyDeployment(deployment, namespace); err != nil {
+		s.log().Error("build: failed to apply deployment", "deployment_id", deployment.ID, "error", err)
+		if errStatus := s.deploymentService.UpdateDeploymentStatus(deployment.ID, models.DeploymentStatusFailed); errStatus != nil {
+			return fmt.Errorf("failed to update deployment status to Failed: %v", errStatus)
+		}
+		return err
+	}
+
+	return s.deploymentService.UpdateDeploymentStatus(deployment.ID, models.DeploymentStatusBuilding)
+}
+
+// applyDeployment는 네임스페이스 초기화, 빌드 Job, 실행 리소스 생성을 순서대로 수행합니다.
+func (s *K8sService) applyDeployment(deployment *models.Deployment, namespace string) error {
+	if err := s.ensureUserNamespace(namespace); err != nil {
+		return err
+	}
+
+	_, err := s.CreateBuildJob(BuildParams{
+		UserNamespace:  namespace,
+		DeploymentName: DeploymentResourceName(deployment),
+		RepoURL:        deployment.RepoURL,
+		Branch:         deployment.Branch,
+		ContextDir:     deployment.ContextDir,
+		DockerfilePath: deployment.DockerfilePath,
+		Strategy:       deployment.BuildStrategy,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create build job: %v", err)
+	}
+
+	// 빌드된 이미지를 사용하는 실행 리소스를 함께 생성
+	// 스케줄 작업 유형은 CronJob, 웹 서비스 유형은 Deployment/Service/Ingress
+	if deployment.Type == models.DeploymentTypeCronJob {
+		_, err = s.CreateCronJob(deployment, namespace)
+	} else {
+		_, err = s.CreateWebDeployment(deployment, namespace)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create runtime resources: %v", err)
+	}
+
+	return nil
+}
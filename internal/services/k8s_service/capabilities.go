@@ -0,0 +1,103 @@
+package k8s_service
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// 클러스터 구성 요소 이름 (VM 기능에 필요)
+const (
+	CapabilityKubeVirt = "kubevirt"
+	CapabilityCDI      = "cdi"
+)
+
+// capabilityAPIs는 구성 요소가 설치되었는지 판단하는 API 그룹/버전입니다.
+var capabilityAPIs = []struct {
+	name         string
+	display      string
+	groupVersion string
+}{
+	{CapabilityKubeVirt, "KubeVirt", "kubevirt.io/v1"},
+	{CapabilityCDI, "CDI", "cdi.kubevirt.io/v1beta1"},
+}
+
+// Capabilities는 서버 시작 시 감지한 클러스터 구성 요소 설치 여부입니다.
+// VM 기능에는 KubeVirt(VM)와 CDI(VM 디스크 DataVolume)가 모두 필요하며, 사용자 관리와 배포는 기본 K8s API만 사용합니다.
+type Capabilities struct {
+	KubeVirt   bool      `json:"kubevirt"`
+	CDI        bool      `json:"cdi"`
+	VMs        bool      `json:"vms"`             // VM 기능 사용 가능 여부
+	Missing    []string  `json:"missing"`         // 설치되지 않은 구성 요소
+	Error      string    `json:"error,omitempty"` // 감지 실패 (이 경우 설치된 것으로 간주)
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// 마지막 감지 결과 (감지 전에는 nil)
+var capabilities atomic.Pointer[Capabilities]
+
+// DetectCapabilities는 API discovery로 KubeVirt/CDI 설치 여부를 감지해 저장합니다.
+// 감지 자체가 실패하면 VM 기능을 끄지 않고 설치된 것으로 간주합니다. (일시적인 API 서버 오류로 기능이 꺼지지 않도록)
+// 구성 요소를 나중에 설치하면 서버를 재시작해야 VM 기능과 VM 관련 백그라운드 루프가 켜집니다.
+func (s *K8sService) DetectCapabilities() Capabilities {
+	caps := Capabilities{KubeVirt: true, CDI: true, Missing: []string{}, DetectedAt: time.Now()}
+
+	groups, err := s.clientset.Discovery().ServerGroups()
+	if err != nil {
+		caps.Error = err.Error()
+	} else {
+		served := map[string]bool{}
+		for _, group := range groups.Groups {
+			for _, version := range group.Versions {
+				served[version.GroupVersion] = true
+			}
+		}
+		caps.KubeVirt = served["kubevirt.io/v1"]
+		caps.CDI = served["cdi.kubevirt.io/v1beta1"]
+	}
+
+	for _, api := range capabilityAPIs {
+		if !caps.installed(api.name) {
+			caps.Missing = append(caps.Missing, api.name)
+		}
+	}
+	caps.VMs = len(caps.Missing) == 0
+
+	capabilities.Store(&caps)
+	return caps
+}
+
+func (c Capabilities) installed(name string) bool {
+	switch name {
+	case CapabilityKubeVirt:
+		return c.KubeVirt
+	case CapabilityCDI:
+		return c.CDI
+	}
+	return false
+}
+
+// GetCapabilities는 마지막으로 감지한 결과를 반환합니다. 감지 전에는 모든 구성 요소가 설치된 것으로 간주합니다.
+func GetCapabilities() Capabilities {
+	if caps := capabilities.Load(); caps != nil {
+		return *caps
+	}
+	return Capabilities{KubeVirt: true, CDI: true, VMs: true, Missing: []string{}}
+}
+
+// VMFeaturesAvailable은 VM 기능을 제공할 수 있는지와, 제공할 수 없으면 그 사유를 반환합니다.
+func VMFeaturesAvailable() (bool, string) {
+	caps := GetCapabilities()
+	if caps.VMs {
+		return true, ""
+	}
+
+	missing := []string{}
+	for _, api := range capabilityAPIs {
+		if !caps.installed(api.name) {
+			missing = append(missing, fmt.Sprintf("%s (%s)", api.display, api.groupVersion))
+		}
+	}
+	return false, fmt.Sprintf("VM features are disabled because %s is not installed in the cluster", strings.Join(missing, " and "))
+}
@@ -0,0 +1,228 @@
+package k8s_service
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	yaml "sigs.k8s.io/yaml"
+)
+
+// 사용자 cloud-init 문서의 최대 크기
+const maxCloudInitBytes = 16 * 1024
+
+const cloudConfigHeader = "#cloud-config"
+
+// 사용자가 지정할 수 있는 cloud-init 모듈
+// 계정/비밀번호/SSH/호스트 이름은 플랫폼이 관리하므로 허용하지 않습니다. (접속 불가 VM 방지)
+var allowedCloudInitKeys = map[string]bool{
+	"packages":        true,
+	"package_update":  true,
+	"package_upgrade": true,
+	"runcmd":          true,
+	"write_files":     true,
+	"timezone":        true,
+	"locale":          true,
+}
+
+// write_files 항목에 허용하는 필드
+var allowedWriteFileKeys = map[string]bool{
+	"path":        true,
+	"content":     true,
+	"encoding":    true,
+	"owner":       true,
+	"permissions": true,
+	"append":      true,
+	"defer":       true,
+}
+
+// ParseCloudInit은 사용자가 입력한 cloud-config 문서를 검사하고 허용된 모듈만 담은 맵으로 반환합니다.
+// 빈 문서는 nil을 반환합니다. 허용되지 않은 모듈이나 잘못된 형식은 무시하지 않고 에러로 반환합니다.
+func ParseCloudInit(raw string) (map[string]interface{}, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	if len(raw) > maxCloudInitBytes {
+		return nil, fmt.Errorf("cloud_init must be at most %d bytes", maxCloudInitBytes)
+	}
+
+	trimmed := strings.TrimSpace(raw)
+	if strings.HasPrefix(trimmed, "#!") {
+		return nil, fmt.Errorf("cloud_init must be a #cloud-config document (use runcmd for scripts)")
+	}
+
+	var config map[string]interface{}
+	if err := yaml.Unmarshal([]byte(raw), &config); err != nil {
+		return nil, fmt.Errorf("cloud_init is not a valid yaml mapping: %v", err)
+	}
+	if len(config) == 0 {
+		return nil, nil
+	}
+
+	var rejected []string
+	for key := range config {
+		if !allowedCloudInitKeys[key] {
+			rejected = append(rejected, key)
+		}
+	}
+	if len(rejected) > 0 {
+		sort.Strings(rejected)
+		return nil, fmt.Errorf("cloud_init modules not allowed: %s", strings.Join(rejected, ", "))
+	}
+
+	if err := checkStringList(config, "packages"); err != nil {
+		return nil, err
+	}
+	if err := checkCommands(config["runcmd"]); err != nil {
+		return nil, err
+	}
+	if err := checkWriteFiles(config["write_files"]); err != nil {
+		return nil, err
+	}
+	for _, key := range []string{"package_update", "package_upgrade"} {
+		if value, ok := config[key]; ok {
+			if _, isBool := value.(bool); !isBool {
+				return nil, fmt.Errorf("cloud_init %s must be a boolean", key)
+			}
+		}
+	}
+	for _, key := range []string{"timezone", "locale"} {
+		if value, ok := config[key]; ok {
+			if text, isString := value.(string); !isString || strings.ContainsAny(text, " \t\r\n") {
+				return nil, fmt.Errorf("cloud_init %s must be a single word", key)
+			}
+		}
+	}
+
+	return config, nil
+}
+
+func checkStringList(config map[string]interface{}, key string) error {
+	value, ok := config[key]
+	if !ok {
+		return nil
+	}
+
+	items, isList := value.([]interface{})
+	if !isList {
+		return fmt.Errorf("cloud_init %s must be a list", key)
+	}
+	for _, item := range items {
+		if _, isString := item.(string); !isString {
+			return fmt.Errorf("cloud_init %s must be a list of strings", key)
+		}
+	}
+	return nil
+}
+
+// checkCommands는 runcmd 항목이 문자열(셸 명령) 또는 문자열 목록(argv)인지 확인합니다.
+func checkCommands(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	commands, isList := value.([]interface{})
+	if !isList {
+		return fmt.Errorf("cloud_init runcmd must be a list")
+	}
+	for _, command := range commands {
+		switch command := command.(type) {
+		case string:
+		case []interface{}:
+			for _, arg := range command {
+				if _, isString := arg.(string); !isString {
+					return fmt.Errorf("cloud_init runcmd arguments must be strings")
+				}
+			}
+		default:
+			return fmt.Errorf("cloud_init runcmd entries must be a string or a list of strings")
+		}
+	}
+	return nil
+}
+
+// checkWriteFiles는 write_files 항목이 절대 경로를 가진 파일 정의인지 확인합니다.
+func checkWriteFiles(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	files, isList := value.([]interface{})
+	if !isList {
+		return fmt.Errorf("cloud_init write_files must be a list")
+	}
+	for _, file := range files {
+		entry, isMap := file.(map[string]interface{})
+		if !isMap {
+			return fmt.Errorf("cloud_init write_files entries must be mappings")
+		}
+		for key := range entry {
+			if !allowedWriteFileKeys[key] {
+				return fmt.Errorf("cloud_init write_files field not allowed: %s", key)
+			}
+		}
+
+		filePath, _ := entry["path"].(string)
+		if !path.IsAbs(filePath) || path.Clean(filePath) != filePath {
+			return fmt.Errorf("cloud_init write_files path must be a clean absolute path: %q", filePath)
+		}
+		// 플랫폼이 설정하는 SSH 설정은 덮어쓰지 않음
+		if strings.HasPrefix(filePath, "/etc/ssh/") || strings.HasPrefix(filePath, "/root/.ssh/") {
+			return fmt.Errorf("cloud_init write_files cannot modify ssh configuration: %s", filePath)
+		}
+	}
+	return nil
+}
+
+// mergeCloudInit은 렌더링된 cloud-init Secret의 userdata에 사용자 cloud-config를 병합합니다.
+// 목록 모듈(packages, runcmd, write_files)은 플랫폼 항목 뒤에 이어 붙이고, 값 모듈은 플랫폼 값이 없을 때만 설정합니다.
+// 사용자 문서가 없으면 템플릿을 그대로 둡니다.
+func mergeCloudInit(objs []*unstructured.Unstructured, raw string) error {
+	userConfig, err := ParseCloudInit(raw)
+	if err != nil || userConfig == nil {
+		return err
+	}
+
+	for _, obj := range objs {
+		if obj.GetKind() != "Secret" {
+			continue
+		}
+		userdata, found, _ := unstructured.NestedString(obj.Object, "stringData", "userdata")
+		if !found {
+			continue
+		}
+
+		var config map[string]interface{}
+		if err := yaml.Unmarshal([]byte(userdata), &config); err != nil {
+			return fmt.Errorf("failed to parse template userdata in %s: %v", obj.GetName(), err)
+		}
+		if config == nil {
+			config = map[string]interface{}{}
+		}
+
+		for key, value := range userConfig {
+			switch value := value.(type) {
+			case []interface{}:
+				existing, _ := config[key].([]interface{})
+				config[key] = append(existing, value...)
+			default:
+				if _, exists := config[key]; !exists {
+					config[key] = value
+				}
+			}
+		}
+
+		merged, err := yaml.Marshal(config)
+		if err != nil {
+			return fmt.Errorf("failed to build userdata for %s: %v", obj.GetName(), err)
+		}
+		if err := unstructured.SetNestedField(obj.Object, cloudConfigHeader+"\n"+string(merged), "stringData", "userdata"); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	return fmt.Errorf("template has no cloud-init userdata secret")
+}
@@ -0,0 +1,109 @@
+package k8s_service
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var gvrCDI = schema.GroupVersionResource{Group: "cdi.kubevirt.io", Version: "v1beta1", Resource: "cdis"}
+
+// SupportedAPIVersions는 컨트롤러가 사용하는 K8s API 그룹/버전입니다.
+var SupportedAPIVersions = []string{
+	"kubevirt.io/v1",
+	"subresources.kubevirt.io/v1",
+	"cdi.kubevirt.io/v1beta1",
+	"upload.cdi.kubevirt.io/v1beta1",
+	"export.kubevirt.io/v1beta1",
+	"snapshot.kubevirt.io/v1beta1",
+	"admissionregistration.k8s.io/v1",
+}
+
+// 클러스터 버전 정보는 자주 바뀌지 않으므로 잠시 캐시
+const clusterVersionTTL = 5 * time.Minute
+
+// APIVersionSupport는 컨트롤러가 사용하는 API 그룹/버전을 클러스터가 제공하는지 여부입니다.
+type APIVersionSupport struct {
+	GroupVersion string `json:"group_version"`
+	Served       bool   `json:"served"`
+}
+
+// ClusterVersions는 클러스터에서 감지한 구성 요소 버전입니다. (감지하지 못하면 빈 값과 에러 메시지)
+type ClusterVersions struct {
+	Kubernetes  string              `json:"kubernetes"`
+	KubeVirt    string              `json:"kubevirt"`
+	CDI         string              `json:"cdi"`
+	APIVersions []APIVersionSupport `json:"api_versions"`
+	Errors      map[string]string   `json:"errors,omitempty"`
+	DetectedAt  time.Time           `json:"detected_at"`
+}
+
+var (
+	clusterVersionMu    sync.Mutex
+	clusterVersionCache *ClusterVersions
+)
+
+// GetClusterVersions는 Kubernetes / KubeVirt / CDI 버전과 API 지원 여부를 반환합니다.
+// 일부 구성 요소를 감지하지 못해도 나머지 결과는 반환합니다.
+func (s *K8sService) GetClusterVersions(ctx context.Context) ClusterVersions {
+	clusterVersionMu.Lock()
+	defer clusterVersionMu.Unlock()
+
+	if clusterVersionCache != nil && time.Since(clusterVersionCache.DetectedAt) < clusterVersionTTL {
+		return *clusterVersionCache
+	}
+
+	versions := ClusterVersions{Errors: map[string]string{}, DetectedAt: time.Now()}
+
+	if info, err := s.clientset.Discovery().ServerVersion(); err != nil {
+		versions.Errors["kubernetes"] = err.Error()
+	} else {
+		versions.Kubernetes = info.GitVersion
+	}
+
+	// KubeVirt는 subresources API로 자신의 버전을 제공
+	raw, err := s.clientset.Discovery().RESTClient().Get().AbsPath("/apis/subresources.kubevirt.io/v1/version").DoRaw(ctx)
+	if err == nil {
+		var info struct {
+			GitVersion string `json:"gitVersion"`
+		}
+		err = json.Unmarshal(raw, &info)
+		versions.KubeVirt = info.GitVersion
+	}
+	if err != nil {
+		versions.Errors["kubevirt"] = err.Error()
+	}
+
+	// CDI는 클러스터 범위 CDI 리소스의 status.observedVersion
+	if list, err := s.dynamicClient.Resource(gvrCDI).List(ctx, metav1.ListOptions{}); err != nil {
+		versions.Errors["cdi"] = err.Error()
+	} else if len(list.Items) > 0 {
+		versions.CDI, _, _ = unstructured.NestedString(list.Items[0].Object, "status", "observedVersion")
+	}
+
+	served := map[string]bool{}
+	if groups, err := s.clientset.Discovery().ServerGroups(); err != nil {
+		versions.Errors["api_versions"] = err.Error()
+	} else {
+		for _, group := range groups.Groups {
+			for _, version := range group.Versions {
+				served[version.GroupVersion] = true
+			}
+		}
+	}
+	for _, groupVersion := range SupportedAPIVersions {
+		versions.APIVersions = append(versions.APIVersions, APIVersionSupport{GroupVersion: groupVersion, Served: served[groupVersion]})
+	}
+
+	// 실패한 감지는 캐시하지 않음 (다음 요청에서 다시 시도)
+	if len(versions.Errors) == 0 {
+		clusterVersionCache = &versions
+	}
+
+	return versions
+}
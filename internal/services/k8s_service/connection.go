@@ -0,0 +1,67 @@
+package k8s_service
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"vm-controller/internal/models"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VMConnection은 사용자가 바로 복사해서 쓸 수 있는 VM 접속 정보입니다.
+type VMConnection struct {
+	SSHHost    string `json:"ssh_host"`
+	SSHPort    int32  `json:"ssh_port"`
+	SSHUser    string `json:"ssh_user"` // 이미지의 기본 사용자
+	SSHCommand string `json:"ssh_command"`
+	WebURL     string `json:"web_url,omitempty"`     // Ingress가 있는 경우
+	ConsoleURL string `json:"console_url,omitempty"` // CONSOLE_URL 이 설정된 경우
+}
+
+// GetVMConnection은 클러스터에 실제로 노출된 Service(NodePort)와 Ingress(DNS)를 기준으로 접속 정보를 구성합니다.
+func (s *K8sService) GetVMConnection(vm *models.VirtualMachine) (*VMConnection, error) {
+	ctx := s.baseContext()
+
+	// SSH는 노드의 공인 주소로 접속 (SSH_HOST, 없으면 서비스 도메인)
+	host := os.Getenv("SSH_HOST")
+	if host == "" {
+		host = os.Getenv("HOSTNAME")
+	}
+
+	connection := &VMConnection{SSHHost: host, SSHPort: vm.NodePort, SSHUser: VMSSHUser(vm)}
+
+	service, err := s.clientset.CoreV1().Services(vm.Namespace).Get(ctx, VMNames(vm.Name).SSHService, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get ssh service: %v", err)
+	}
+	if err == nil && len(service.Spec.Ports) > 0 && service.Spec.Ports[0].NodePort != 0 {
+		connection.SSHPort = service.Spec.Ports[0].NodePort
+	}
+	connection.SSHCommand = fmt.Sprintf("ssh %s@%s -p %d", connection.SSHUser, connection.SSHHost, connection.SSHPort)
+
+	ingress, err := s.clientset.NetworkingV1().Ingresses(vm.Namespace).Get(ctx, VMNames(vm.Name).Ingress, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get ingress: %v", err)
+	}
+	if err == nil && len(ingress.Spec.Rules) > 0 && ingress.Spec.Rules[0].Host != "" {
+		scheme := "http"
+		if len(ingress.Spec.TLS) > 0 {
+			scheme = "https"
+		}
+		connection.WebURL = scheme + "://" + ingress.Spec.Rules[0].Host
+	}
+
+	// 예: https://console.example.com/{{NAMESPACE}}/{{VM_NAME}}
+	if consoleURL := os.Getenv("CONSOLE_URL"); consoleURL != "" {
+		connection.ConsoleURL = strings.NewReplacer("{{NAMESPACE}}", vm.Namespace, "{{VM_NAME}}", vm.Name).Replace(consoleURL)
+	}
+
+	return connection, nil
+}
+
+// SSHConfig는 ~/.ssh/config 에 붙여 넣을 수 있는 Host 항목을 만듭니다.
+func (c *VMConnection) SSHConfig(vmName string) string {
+	return fmt.Sprintf("Host %s\n    HostName %s\n    Port %d\n    User %s\n", vmName, c.SSHHost, c.SSHPort, c.SSHUser)
+}
@@ -0,0 +1,68 @@
+package k8s_service
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"vm-controller/internal/models"
+
+	"k8s.io/client-go/rest"
+)
+
+// 콘솔 종류 (KubeVirt VMI 서브리소스 이름)
+const (
+	ConsoleSerial = "console"
+	ConsoleVNC    = "vnc"
+)
+
+// ConsoleProxy는 VM 콘솔(KubeVirt console / vnc subresource)로 WebSocket 연결을 중계하는 핸들러를 반환합니다.
+// 클라이언트는 plain.kubevirt.io 서브프로토콜로 연결하며, vnc는 RFB 스트림을 그대로 전달하므로 noVNC 등으로 접속합니다.
+func (s *K8sService) ConsoleProxy(vm *models.VirtualMachine, kind string) (http.Handler, error) {
+	if kind != ConsoleSerial && kind != ConsoleVNC {
+		return nil, fmt.Errorf("unknown console type: %s", kind)
+	}
+
+	target, err := url.Parse(s.restConfig.Host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid api server address: %v", err)
+	}
+
+	// WebSocket 업그레이드는 HTTP/1.1 에서만 가능하므로 h2 협상을 끔
+	tlsConfig, err := rest.TLSConfigFor(s.restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build tls config: %v", err)
+	}
+	if tlsConfig != nil {
+		tlsConfig.NextProtos = []string{"http/1.1"}
+	}
+
+	transport, err := rest.HTTPWrappersForConfig(s.restConfig, &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: tlsConfig,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build transport: %v", err)
+	}
+
+	path := fmt.Sprintf("/apis/subresources.kubevirt.io/v1/namespaces/%s/virtualmachineinstances/%s/%s", vm.Namespace, vm.Name, kind)
+
+	return &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(target)
+			r.Out.URL.Path = path
+			r.Out.URL.RawPath = ""
+			r.Out.URL.RawQuery = ""
+			r.Out.Host = target.Host
+
+			// 사용자 인증 정보는 API 서버로 전달하지 않음 (서비스 계정으로 접근)
+			r.Out.Header.Del("Cookie")
+			r.Out.Header.Del("Authorization")
+		},
+		Transport: transport,
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			s.log().Warn("console proxy failed", "namespace", vm.Namespace, "vm", vm.Name, "error", err)
+			w.WriteHeader(http.StatusBadGateway)
+		},
+	}, nil
+}
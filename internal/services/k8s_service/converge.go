@@ -0,0 +1,146 @@
+package k8s_service
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+	"vm-controller/internal/models"
+	"vm-controller/internal/vmstate"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// observedVM은 클러스터에서 관측한 VirtualMachine 상태입니다.
+type observedVM struct {
+	Running         bool   // spec.running (또는 runStrategy)
+	PrintableStatus string // status.printableStatus (Running, Stopped, Starting ...)
+	Managed         bool   // 이 컨트롤러가 만든 리소스 (managed-by 라벨)
+}
+
+// listObservedVMs는 클러스터 전체의 VirtualMachine을 "namespace/name" 키로 반환합니다.
+func (s *K8sService) listObservedVMs() (map[string]observedVM, error) {
+	list, err := s.dynamicClient.Resource(gvrVM).List(s.baseContext(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list virtual machines: %v", err)
+	}
+
+	result := make(map[string]observedVM, len(list.Items))
+	for _, item := range list.Items {
+		running, found, _ := unstructured.NestedBool(item.Object, "spec", "running")
+		if !found {
+			runStrategy, _, _ := unstructured.NestedString(item.Object, "spec", "runStrategy")
+			running = runStrategy != "" && runStrategy != "Halted"
+		}
+		printableStatus, _, _ := unstructured.NestedString(item.Object, "status", "printableStatus")
+
+		result[item.GetNamespace()+"/"+item.GetName()] = observedVM{
+			Running:         running,
+			PrintableStatus: printableStatus,
+			Managed:         item.GetLabels()[managedByLabel] == managedByValue,
+		}
+	}
+
+	return result, nil
+}
+
+// StartVMConverger는 주기적으로 모든 VM의 목표 상태(desired_state)와 클러스터 상태를 비교하여 수렴시킵니다.
+// 컨트롤러가 재시작되어 진행 중이던 작업이 사라져도 다음 주기에 다시 시도됩니다.
+func (s *K8sService) StartVMConverger(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			runRecovered("vm.converger", "*", func() error {
+				s.convergeVMs()
+				return nil
+			})
+		}
+	}()
+}
+
+func (s *K8sService) convergeVMs() {
+	vms, err := s.vmService.FetchUnconvergedVMs()
+	if err != nil {
+		s.log().Error("vm converger: failed to fetch VMs", "error", err)
+		return
+	}
+
+	observed, err := s.listObservedVMs()
+	if err != nil {
+		s.log().Error("vm converger: failed to list cluster VMs", "error", err)
+		return
+	}
+
+	for i := range vms {
+		vm := &vms[i]
+
+		// 실행 중인 작업이 있으면 이번 주기는 건너뜀
+		if s.operationInFlight("vm/" + vm.Name) {
+			continue
+		}
+
+		cluster, exists := observed[vm.Namespace+"/"+vm.Name]
+		s.convergeVM(vm, cluster, exists)
+	}
+}
+
+// convergeVM은 한 VM의 목표 상태와 관측 상태의 차이에 따라 작업을 실행하거나 DB 상태를 맞춥니다.
+func (s *K8sService) convergeVM(vm *models.VirtualMachine, cluster observedVM, exists bool) {
+	desired := vm.DesiredState
+	if desired == "" {
+		desired = models.VmDesiredRunning
+	}
+
+	switch desired {
+	case models.VmDesiredDeleted:
+		// 리소스 삭제가 끝나지 않았으면 삭제를 다시 시도
+		s.DeleteVMAsync(vm, "")
+
+	case models.VmDesiredRunning:
+		if !exists {
+			// 생성 직후 목록에 아직 없을 수 있으므로 Provisioning은 기다림
+			if vm.Status != models.VmStatusProvisioning {
+				s.syncVMStatus(vm, models.VmStatusFailed)
+			}
+			return
+		}
+		if !cluster.Running {
+			s.StartVMAsync(vm, "")
+			return
+		}
+		switch cluster.PrintableStatus {
+		case "Running":
+			// 생성/재시작 후 Running이 DB에 반영되지 않은 경우 (Provisioning 등)
+			s.syncVMStatus(vm, models.VmStatusRunning)
+		case "Paused":
+			// 일시 정지는 목표 상태를 바꾸지 않으므로 관측된 상태만 반영
+			s.syncVMStatus(vm, models.VmStatusPaused)
+		}
+
+	case models.VmDesiredStopped:
+		if !exists {
+			s.syncVMStatus(vm, models.VmStatusFailed)
+			return
+		}
+		if cluster.Running {
+			s.StopVMAsync(vm, "")
+			return
+		}
+		if cluster.PrintableStatus == "Stopped" {
+			s.syncVMStatus(vm, models.VmStatusStopped)
+		}
+	}
+}
+
+// syncVMStatus는 관측된 상태를 DB에 반영합니다. 상태 머신이 허용하지 않는 전이는 무시합니다.
+func (s *K8sService) syncVMStatus(vm *models.VirtualMachine, status models.EnumVmStatus) {
+	if vm.Status == status || !vmstate.CanTransition(vm.Status, status) {
+		return
+	}
+
+	if err := s.vmService.UpdateVmStatus(vm.Name, status); err != nil {
+		slog.Error("vm converger: failed to update status", "vm", vm.Name, "status", status, "error", err)
+	}
+}
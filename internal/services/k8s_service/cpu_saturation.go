@@ -0,0 +1,190 @@
+package k8s_service
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cast"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CPU 사용량이 limit의 이 비율 이상이면 포화(saturated)로 간주
+const cpuSaturationThreshold = 0.9
+
+// 관측 구간 중 이 비율 이상 포화 상태였으면 "지속적으로 포화"로 보고
+const cpuSaturationPersistentRatio = 0.75
+
+// 기본 관측 구간 샘플 수 (CPU_SATURATION_WINDOW, 1분 간격이면 1시간)
+const defaultCPUSaturationWindow = 60
+
+// CPUSaturation은 CPU limit에 지속적으로 도달하는 VM입니다. (요금제 업그레이드 상담용)
+type CPUSaturation struct {
+	Namespace      string  `json:"namespace"`
+	VmName         string  `json:"vm_name"`
+	LimitCores     float64 `json:"limit_cores"`
+	AvgUsageCores  float64 `json:"avg_usage_cores"`
+	SaturatedRatio float64 `json:"saturated_ratio"` // 관측 구간 중 포화 상태였던 비율
+	Samples        int     `json:"samples"`
+}
+
+type cpuSamples struct {
+	limitCores float64
+	usage      []float64       // 최근 window 개의 사용량 (cores)
+	network    []networkSample // 최근 window 개의 파드 네트워크 누적 송수신량 (채굴 탐지용)
+}
+
+// networkSample은 샘플 시점의 파드 네트워크 누적 송수신 바이트입니다.
+type networkSample struct {
+	at      time.Time
+	rxBytes uint64
+	txBytes uint64
+}
+
+var (
+	cpuSamplesMu sync.Mutex
+	cpuSamplesOf = map[string]*cpuSamples{} // "namespace/vm" 별 최근 사용량
+)
+
+func cpuSaturationWindow() int {
+	if window := cast.ToInt(os.Getenv("CPU_SATURATION_WINDOW")); window > 0 {
+		return window
+	}
+	return defaultCPUSaturationWindow
+}
+
+// StartCPUSaturationSampler는 주기적으로 virt-launcher 파드의 CPU 사용량을 limit과 비교하여 기록합니다.
+func (s *K8sService) StartCPUSaturationSampler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			runRecovered("cpu.sampler", "*", func() error {
+				return s.sampleVMCPU()
+			})
+		}
+	}()
+}
+
+func (s *K8sService) sampleVMCPU() error {
+	pods, err := s.clientset.CoreV1().Pods(metav1.NamespaceAll).List(s.baseContext(), metav1.ListOptions{
+		LabelSelector: "kubevirt.io=virt-launcher",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list virt-launcher pods: %v", err)
+	}
+
+	// 파드 이름 → VM, CPU limit (컨테이너 limit 합계)
+	type launcher struct {
+		key        string
+		limitCores float64
+	}
+	launchers := map[string]launcher{}
+	for _, pod := range pods.Items {
+		vmName := pod.Labels["vm.kubevirt.io/name"]
+		if vmName == "" {
+			continue
+		}
+
+		var limit float64
+		for _, container := range pod.Spec.Containers {
+			if quantity, ok := container.Resources.Limits["cpu"]; ok {
+				limit += quantity.AsApproximateFloat64()
+			}
+		}
+		if limit == 0 {
+			continue
+		}
+
+		launchers[pod.Namespace+"/"+pod.Name] = launcher{key: pod.Namespace + "/" + vmName, limitCores: limit}
+	}
+
+	window := cpuSaturationWindow()
+	seen := map[string]bool{}
+	now := time.Now()
+
+	cpuSamplesMu.Lock()
+	defer cpuSamplesMu.Unlock()
+
+	for _, summary := range s.readNodeSummaries() {
+		for _, pod := range summary.Pods {
+			launcher, ok := launchers[pod.PodRef.Namespace+"/"+pod.PodRef.Name]
+			if !ok || pod.CPU == nil || pod.CPU.UsageNanoCores == nil {
+				continue
+			}
+
+			samples, ok := cpuSamplesOf[launcher.key]
+			if !ok {
+				samples = &cpuSamples{}
+				cpuSamplesOf[launcher.key] = samples
+			}
+			samples.limitCores = launcher.limitCores
+			samples.usage = append(samples.usage, float64(*pod.CPU.UsageNanoCores)/1e9)
+			if len(samples.usage) > window {
+				samples.usage = samples.usage[len(samples.usage)-window:]
+			}
+			if pod.Network != nil && pod.Network.RxBytes != nil && pod.Network.TxBytes != nil {
+				samples.network = append(samples.network, networkSample{at: now, rxBytes: *pod.Network.RxBytes, txBytes: *pod.Network.TxBytes})
+				if len(samples.network) > window {
+					samples.network = samples.network[len(samples.network)-window:]
+				}
+			}
+			seen[launcher.key] = true
+		}
+	}
+
+	// 정지/삭제된 VM의 기록은 제거
+	for key := range cpuSamplesOf {
+		if !seen[key] {
+			delete(cpuSamplesOf, key)
+		}
+	}
+
+	return nil
+}
+
+// ListCPUSaturatedVMs는 관측 구간의 절반 이상 샘플이 있고, 그중 대부분에서 CPU limit에 도달한 VM을 반환합니다.
+func (s *K8sService) ListCPUSaturatedVMs() []CPUSaturation {
+	window := cpuSaturationWindow()
+
+	cpuSamplesMu.Lock()
+	defer cpuSamplesMu.Unlock()
+
+	result := []CPUSaturation{}
+	for key, samples := range cpuSamplesOf {
+		if len(samples.usage) < window/2 {
+			continue
+		}
+
+		var total float64
+		saturated := 0
+		for _, usage := range samples.usage {
+			total += usage
+			if usage >= samples.limitCores*cpuSaturationThreshold {
+				saturated++
+			}
+		}
+
+		ratio := float64(saturated) / float64(len(samples.usage))
+		if ratio < cpuSaturationPersistentRatio {
+			continue
+		}
+
+		namespace, vmName, _ := strings.Cut(key, "/")
+		result = append(result, CPUSaturation{
+			Namespace:      namespace,
+			VmName:         vmName,
+			LimitCores:     samples.limitCores,
+			AvgUsageCores:  total / float64(len(samples.usage)),
+			SaturatedRatio: ratio,
+			Samples:        len(samples.usage),
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].SaturatedRatio > result[j].SaturatedRatio })
+	return result
+}
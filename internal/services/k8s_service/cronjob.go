@@ -0,0 +1,137 @@
+package k8s_service
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+	"vm-controller/internal/models"
+	deploymentservice "vm-controller/internal/services/deployment_service"
+	notificationservice "vm-controller/internal/services/notification_service"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// 마지막 실행 로그로 저장할 최대 줄 수
+const jobRunLogTailLines int64 = 200
+
+// CreateCronJob은 스케줄 작업 배포를 위한 CronJob을 생성합니다.
+func (s *K8sService) CreateCronJob(deployment *models.Deployment, namespace string) ([]CreatedResource, error) {
+	if err := deploymentservice.ValidateSchedule(deployment.Schedule); err != nil {
+		return nil, err
+	}
+
+	name := DeploymentResourceName(deployment)
+	replacements := map[string]string{
+		"{{NAMESPACE}}":       namespace,
+		"{{DEPLOYMENT_NAME}}": name,
+		"{{SCHEDULE}}":        deployment.Schedule,
+		"{{IMAGE}}":           DeploymentImage(namespace, name),
+	}
+
+	return s.applyManifests(filepath.Join("yaml-data", "client-cronjob"), replacements, namespace, false)
+}
+
+// SyncJobRuns는 CronJob이 생성한 Job 목록을 조회하여 실행 이력을 DB에 반영합니다.
+// 가장 최근 실행의 로그를 함께 저장하며, 새로 실패한 실행이 있으면 사용자에게 알림을 보냅니다.
+func (s *K8sService) SyncJobRuns(deployment *models.Deployment, namespace string) ([]models.JobRun, error) {
+	ctx := s.baseContext()
+	gvrJob := schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+	name := DeploymentResourceName(deployment)
+
+	jobs, err := s.dynamicClient.Resource(gvrJob).Namespace(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "deployment-run=" + name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %v", err)
+	}
+
+	// 최신 실행이 먼저 오도록 정렬
+	items := jobs.Items
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].GetCreationTimestamp().After(items[j].GetCreationTimestamp().Time)
+	})
+
+	for i, job := range items {
+		run := jobRunFromObject(deployment.ID, &job)
+
+		// 가장 최근 실행의 로그만 저장 (DB 용량 절약)
+		if i == 0 {
+			if logs, err := s.fetchJobLogs(ctx, namespace, job.GetName()); err == nil {
+				run.Logs = logs
+			} else {
+				s.log().Warn("cronjob: failed to fetch job logs", "namespace", namespace, "job", job.GetName(), "error", err)
+			}
+		}
+
+		newlyFailed, err := s.deploymentService.UpsertJobRun(run)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save job run: %v", err)
+		}
+
+		if newlyFailed {
+			data := notificationservice.Data{"repo": deployment.RepoURL, "job": job.GetName()}
+			if err := s.notificationService.NotifyTemplate(deployment.UserID, "deployment.job_failed", data); err != nil {
+				s.log().Warn("cronjob: failed to notify user", "user_id", deployment.UserID, "error", err)
+			}
+		}
+	}
+
+	return s.deploymentService.FetchJobRuns(deployment.ID)
+}
+
+// jobRunFromObject는 Job 리소스의 status를 JobRun 모델로 변환합니다.
+func jobRunFromObject(deploymentId uint, job *unstructured.Unstructured) models.JobRun {
+	run := models.JobRun{
+		DeploymentID: deploymentId,
+		JobName:      job.GetName(),
+		Status:       models.JobRunStatusRunning,
+	}
+
+	if succeeded, _, _ := unstructured.NestedInt64(job.Object, "status", "succeeded"); succeeded > 0 {
+		run.Status = models.JobRunStatusSucceeded
+	}
+	if failed, _, _ := unstructured.NestedInt64(job.Object, "status", "failed"); failed > 0 {
+		run.Status = models.JobRunStatusFailed
+	}
+
+	if startTime, found, _ := unstructured.NestedString(job.Object, "status", "startTime"); found {
+		if t, err := time.Parse(time.RFC3339, startTime); err == nil {
+			run.StartedAt = &t
+		}
+	}
+	if completionTime, found, _ := unstructured.NestedString(job.Object, "status", "completionTime"); found {
+		if t, err := time.Parse(time.RFC3339, completionTime); err == nil {
+			run.FinishedAt = &t
+		}
+	}
+
+	return run
+}
+
+// fetchJobLogs는 Job이 생성한 Pod의 로그 마지막 부분을 반환합니다.
+func (s *K8sService) fetchJobLogs(ctx context.Context, namespace, jobName string) (string, error) {
+	pods, err := s.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "job-name=" + jobName,
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(pods.Items) == 0 {
+		return "", fmt.Errorf("no pods found for job %s", jobName)
+	}
+
+	tail := jobRunLogTailLines
+	raw, err := s.clientset.CoreV1().Pods(namespace).GetLogs(pods.Items[0].Name, &corev1.PodLogOptions{
+		TailLines: &tail,
+	}).DoRaw(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return string(raw), nil
+}
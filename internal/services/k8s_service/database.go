@@ -0,0 +1,144 @@
+package k8s_service
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+	"vm-controller/internal/logger"
+	"vm-controller/internal/models"
+	"vm-controller/internal/saga"
+	databaseservice "vm-controller/internal/services/database_service"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// CreateManagedDatabase는 관리형 데이터베이스의 Secret/Service/StatefulSet을 생성합니다.
+// 실패 시 생성된 리소스를 롤백하고 DB 상태를 Failed로 갱신합니다.
+func (s *K8sService) CreateManagedDatabase(database *models.ManagedDatabase) error {
+	created, err := s.applyManagedDatabase(database)
+
+	if err != nil {
+		s.log().Error("database: failed to create managed database, rolling back", "database", database.Name, "error", err)
+		sg := saga.New("database.create", "namespace", database.Namespace, "database", database.Name, "request_id", logger.RequestIDFrom(s.baseContext()))
+		s.compensateResources(sg, created)
+		sg.Rollback()
+
+		if errStatus := s.databaseService.UpdateDatabaseStatus(database.ID, "Failed"); errStatus != nil {
+			return fmt.Errorf("failed to update database status to Failed: %v", errStatus)
+		}
+		return err
+	}
+
+	return s.databaseService.UpdateDatabaseStatus(database.ID, "Ready")
+}
+
+func (s *K8sService) applyManagedDatabase(database *models.ManagedDatabase) ([]CreatedResource, error) {
+	if err := s.ensureUserNamespace(database.Namespace); err != nil {
+		return nil, err
+	}
+
+	var manifestDir string
+	switch database.Engine {
+	case models.DatabaseEnginePostgres:
+		manifestDir = filepath.Join("yaml-data", "client-database", "postgres")
+	case models.DatabaseEngineMySQL:
+		manifestDir = filepath.Join("yaml-data", "client-database", "mysql")
+	default:
+		return nil, fmt.Errorf("unknown database engine: %s", database.Engine)
+	}
+
+	replacements := map[string]string{
+		"{{NAMESPACE}}":   database.Namespace,
+		"{{DB_NAME}}":     database.Name,
+		"{{DB_USER}}":     database.Username,
+		"{{DB_PASSWORD}}": database.Password,
+		"{{DB_DATABASE}}": database.DatabaseName,
+		"{{STORAGE}}":     fmt.Sprintf("%d", database.StorageGi),
+	}
+
+	return s.applyManifests(manifestDir, replacements, database.Namespace, false)
+}
+
+// DeleteManagedDatabase는 관리형 데이터베이스의 리소스와 데이터 볼륨(PVC)을 삭제합니다.
+func (s *K8sService) DeleteManagedDatabase(database *models.ManagedDatabase) error {
+	if err := s.databaseService.DeleteDatabase(database.ID); err != nil {
+		return err
+	}
+
+	resources := []CreatedResource{
+		{Group: "apps", Version: "v1", Kind: "StatefulSet", Name: database.Name, Namespace: database.Namespace},
+		{Version: "v1", Kind: "Service", Name: database.Name, Namespace: database.Namespace},
+		{Version: "v1", Kind: "Secret", Name: database.Name + "-credentials", Namespace: database.Namespace},
+		// volumeClaimTemplates로 생성된 PVC는 StatefulSet 삭제 시 자동으로 지워지지 않음
+		{Version: "v1", Kind: "PersistentVolumeClaim", Name: "data-" + database.Name + "-0", Namespace: database.Namespace},
+	}
+
+	for _, res := range resources {
+		if err := s.deleteResource(res); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// InjectDatabaseSecret은 배포의 Pod에 envFrom으로 주입되는 접속 정보 Secret을 생성(또는 갱신)합니다.
+// 웹 서비스 유형이면 새 접속 정보가 반영되도록 Deployment를 재시작합니다.
+func (s *K8sService) InjectDatabaseSecret(deployment *models.Deployment, database *models.ManagedDatabase, namespace string) error {
+	ctx := s.baseContext()
+	name := DeploymentResourceName(deployment)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name + "-db",
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "vm-controller",
+				"deployment":                   name,
+				"managed-database":             database.Name,
+			},
+		},
+		StringData: map[string]string{
+			"DATABASE_URL": databaseservice.ConnectionURL(database),
+			"DB_HOST":      database.Host,
+			"DB_PORT":      fmt.Sprintf("%d", database.Port),
+			"DB_USER":      database.Username,
+			"DB_PASSWORD":  database.Password,
+			"DB_NAME":      database.DatabaseName,
+		},
+	}
+
+	secrets := s.clientset.CoreV1().Secrets(namespace)
+	_, err := secrets.Create(ctx, secret, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write database secret: %v", err)
+	}
+
+	if deployment.Type == models.DeploymentTypeCronJob {
+		// CronJob은 다음 실행부터 새 Secret을 사용
+		return nil
+	}
+
+	return s.restartDeployment(ctx, namespace, name)
+}
+
+// restartDeployment는 kubectl rollout restart와 동일하게 Pod 템플릿 annotation을 갱신합니다.
+func (s *K8sService) restartDeployment(ctx context.Context, namespace, name string) error {
+	gvrDeployment := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	patch := []byte(fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{"kubectl.kubernetes.io/restartedAt":"%s"}}}}}`, time.Now().Format(time.RFC3339)))
+
+	_, err := s.dynamicClient.Resource(gvrDeployment).Namespace(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to restart deployment: %v", err)
+	}
+
+	return nil
+}
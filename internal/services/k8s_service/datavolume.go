@@ -0,0 +1,160 @@
+package k8s_service
+
+import (
+	"fmt"
+	"os"
+	"time"
+	"vm-controller/internal/models"
+	notificationservice "vm-controller/internal/services/notification_service"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var gvrDataVolume = schema.GroupVersionResource{Group: "cdi.kubevirt.io", Version: "v1beta1", Resource: "datavolumes"}
+
+// 기본 DataVolume import/clone 제한 시간 (DATAVOLUME_IMPORT_TIMEOUT 으로 변경 가능)
+const defaultDataVolumeTimeout = 30 * time.Minute
+
+func dataVolumeTimeout() time.Duration {
+	if timeout, err := time.ParseDuration(os.Getenv("DATAVOLUME_IMPORT_TIMEOUT")); err == nil && timeout > 0 {
+		return timeout
+	}
+	return defaultDataVolumeTimeout
+}
+
+// 업로드 방식 디스크는 사용자가 이미지를 전송하는 시간까지 포함하므로 별도의 제한 시간을 사용 (DATAVOLUME_UPLOAD_TIMEOUT)
+const defaultDataVolumeUploadTimeout = 6 * time.Hour
+
+func dataVolumeUploadTimeout() time.Duration {
+	if timeout, err := time.ParseDuration(os.Getenv("DATAVOLUME_UPLOAD_TIMEOUT")); err == nil && timeout > 0 {
+		return timeout
+	}
+	return defaultDataVolumeUploadTimeout
+}
+
+// stuckDataVolume은 제한 시간 내에 준비되지 않은 VM 디스크입니다.
+type stuckDataVolume struct {
+	Namespace string
+	VmName    string
+	Phase     string
+	Message   string
+}
+
+// StartDataVolumeWatchdog는 주기적으로 멈추거나 실패한 VM 디스크 import를 찾아 정리합니다.
+func (s *K8sService) StartDataVolumeWatchdog(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			runRecovered("datavolume.watchdog", "*", func() error {
+				s.cleanupStuckDataVolumes()
+				return nil
+			})
+		}
+	}()
+}
+
+// findStuckDataVolumes는 Failed 상태이거나 제한 시간이 지나도 Succeeded가 아닌 VM 디스크를 찾습니다.
+func (s *K8sService) findStuckDataVolumes() ([]stuckDataVolume, error) {
+	list, err := s.dynamicClient.Resource(gvrDataVolume).List(s.baseContext(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list data volumes: %v", err)
+	}
+
+	timeout := dataVolumeTimeout()
+	uploadTimeout := dataVolumeUploadTimeout()
+	var stuck []stuckDataVolume
+
+	for _, item := range list.Items {
+		vmName, isVMDisk := vmNameFromDisk(item.GetName(), item.GetLabels())
+		if !isVMDisk {
+			continue
+		}
+
+		phase, _, _ := unstructured.NestedString(item.Object, "status", "phase")
+		if phase == "Succeeded" {
+			continue
+		}
+
+		limit := timeout
+		if _, isUpload, _ := unstructured.NestedMap(item.Object, "spec", "source", "upload"); isUpload {
+			limit = uploadTimeout
+		}
+
+		age := time.Since(item.GetCreationTimestamp().Time)
+		if phase != "Failed" && age < limit {
+			continue
+		}
+
+		stuck = append(stuck, stuckDataVolume{
+			Namespace: item.GetNamespace(),
+			VmName:    vmName,
+			Phase:     phase,
+			Message:   dataVolumeMessage(&item, phase, age),
+		})
+	}
+
+	return stuck, nil
+}
+
+// dataVolumeMessage는 CDI condition에서 사용자에게 보여줄 실패 원인을 추출합니다. (잘못된 이미지 URL, 용량 부족 등)
+func dataVolumeMessage(item *unstructured.Unstructured, phase string, age time.Duration) string {
+	conditions, _, _ := unstructured.NestedSlice(item.Object, "status", "conditions")
+	for _, raw := range conditions {
+		condition, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if message, _ := condition["message"].(string); message != "" && condition["status"] != "True" {
+			return fmt.Sprintf("%s: %s", condition["reason"], message)
+		}
+	}
+
+	if phase == "" {
+		phase = "Pending"
+	}
+	return fmt.Sprintf("disk is still %s after %s", phase, age.Round(time.Minute))
+}
+
+func (s *K8sService) cleanupStuckDataVolumes() {
+	stuck, err := s.findStuckDataVolumes()
+	if err != nil {
+		s.log().Error("datavolume watchdog: failed to list DataVolumes", "error", err)
+		return
+	}
+
+	for _, dv := range stuck {
+		vm, err := s.vmService.FetchVmName(dv.VmName, false)
+		if err != nil || vm == nil || vm.Namespace != dv.Namespace {
+			// 플랫폼이 관리하지 않는 DataVolume은 건드리지 않음
+			continue
+		}
+
+		s.log().Warn("datavolume watchdog: VM disk is stuck", "vm", vm.Name, "phase", dv.Phase, "message", dv.Message)
+
+		// 실패 원인을 이벤트와 알림으로 남긴 뒤, 쿼터가 계속 점유되지 않도록 VM과 부분 리소스를 정리
+		s.vmEventService.Record(models.VmEvent{
+			VmName:    vm.Name,
+			Type:      models.VmEventOperationFailed,
+			Operation: "import",
+			ToStatus:  dv.Phase,
+			Detail:    dv.Message,
+		})
+		if err := s.vmService.MarkVmFailed(vm.Name); err != nil {
+			s.log().Error("datavolume watchdog: failed to mark VM as Failed", "vm", vm.Name, "error", err)
+		}
+		if err := s.notificationService.NotifyTemplate(vm.UserID, "vm.import_failed",
+			notificationservice.Data{"vm": vm.Name, "reason": dv.Message}); err != nil {
+			s.log().Warn("datavolume watchdog: failed to notify user", "user_id", vm.UserID, "error", err)
+		}
+
+		if err := s.vmService.SetDesiredState(vm.Name, models.VmDesiredDeleted); err != nil {
+			s.log().Error("datavolume watchdog: failed to set desired state", "vm", vm.Name, "error", err)
+			continue
+		}
+		s.DeleteVMAsync(vm, "")
+	}
+}
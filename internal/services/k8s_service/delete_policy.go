@@ -0,0 +1,27 @@
+package k8s_service
+
+import (
+	"vm-controller/internal/config"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// deleteOptions는 kind 리소스를 삭제할 때 사용할 옵션을 만듭니다.
+// override가 있으면(관리자 요청별 지정) DELETE_POLICIES 설정 대신 사용합니다.
+func deleteOptions(kind string, override *config.DeletePolicy) metav1.DeleteOptions {
+	policy := config.Get().DeletePolicyFor(kind)
+	if override != nil {
+		policy = *override
+	}
+
+	propagation := metav1.DeletionPropagation(policy.Propagation)
+	options := metav1.DeleteOptions{
+		PropagationPolicy:  &propagation,
+		GracePeriodSeconds: policy.GracePeriodSeconds,
+	}
+	if policy.Force {
+		zero := int64(0)
+		options.GracePeriodSeconds = &zero
+	}
+	return options
+}
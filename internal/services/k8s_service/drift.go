@@ -0,0 +1,216 @@
+package k8s_service
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"vm-controller/internal/models"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DriftKind는 DB와 클러스터가 어긋난 종류입니다.
+type DriftKind string
+
+const (
+	DriftMissingInstance DriftKind = "missing_instance" // DB는 Running인데 VMI가 없음
+	DriftOrphanInstance  DriftKind = "orphan_instance"  // VMI가 있는데 DB 레코드가 없음
+	DriftPortMismatch    DriftKind = "port_mismatch"    // SSH Service의 NodePort가 DB와 다름 (Service가 없는 경우 포함)
+)
+
+var DriftKinds = []DriftKind{DriftMissingInstance, DriftOrphanInstance, DriftPortMismatch}
+
+// 자동 수정 방법 (없으면 관리자가 직접 확인)
+const (
+	DriftFixStart    = "start"    // VirtualMachine은 있으나 정지됨: 시작
+	DriftFixRecreate = "recreate" // 사라진 리소스를 DB 정보로 재생성
+	DriftFixDelete   = "delete"   // 이 컨트롤러가 만든 리소스: 삭제
+	DriftFixPatch    = "patch"    // Service NodePort를 DB 값으로 변경
+)
+
+// Drift는 DB와 클러스터 사이의 불일치 한 건입니다.
+type Drift struct {
+	Kind        DriftKind `json:"kind"`
+	Namespace   string    `json:"namespace"`
+	Name        string    `json:"name"`
+	DBStatus    string    `json:"db_status,omitempty"`
+	DBPort      int32     `json:"db_port,omitempty"`
+	ClusterPort int32     `json:"cluster_port,omitempty"`
+	Detail      string    `json:"detail"`
+	Fix         string    `json:"fix,omitempty"` // 자동 수정 방법 (비어 있으면 자동 수정 불가)
+
+	vm *models.VirtualMachine // 수정에 사용할 DB 레코드 (orphan_instance는 nil)
+}
+
+// DriftFixResult는 불일치 한 건의 자동 수정 결과입니다.
+type DriftFixResult struct {
+	Drift
+	OperationID uint   `json:"operation_id,omitempty"` // 백그라운드 작업으로 수정한 경우
+	Error       string `json:"error,omitempty"`
+}
+
+// DetectDrift는 삭제되지 않은 DB VM과 클러스터의 VirtualMachine/VMI/SSH Service를 비교하여 불일치 목록을 반환합니다.
+// 작업이 실행 중인 VM은 상태가 곧 바뀌므로 제외합니다.
+func (s *K8sService) DetectDrift() ([]Drift, error) {
+	vms, err := s.vmService.FetchAllVMs(true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch VMs: %v", err)
+	}
+
+	observed, err := s.listObservedVMs()
+	if err != nil {
+		return nil, err
+	}
+	instances, err := s.ListVMIs()
+	if err != nil {
+		return nil, err
+	}
+	services, err := s.clientset.CoreV1().Services("").List(s.baseContext(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %v", err)
+	}
+	sshPorts := map[string]int32{}
+	for _, service := range services.Items {
+		if len(service.Spec.Ports) > 0 {
+			sshPorts[service.Namespace+"/"+service.Name] = service.Spec.Ports[0].NodePort
+		}
+	}
+
+	drifts := []Drift{}
+	known := make(map[string]bool, len(vms))
+	for i := range vms {
+		vm := &vms[i]
+		key := vm.Namespace + "/" + vm.Name
+		known[key] = true
+
+		if s.operationInFlight("vm/" + vm.Name) {
+			continue
+		}
+
+		if vm.Status == models.VmStatusRunning {
+			if _, ok := instances[key]; !ok {
+				drift := Drift{Kind: DriftMissingInstance, Namespace: vm.Namespace, Name: vm.Name, DBStatus: string(vm.Status), vm: vm}
+				if cluster, exists := observed[key]; exists {
+					drift.Detail = fmt.Sprintf("virtual machine exists but has no instance (printable status %q)", cluster.PrintableStatus)
+					drift.Fix = DriftFixStart
+				} else {
+					drift.Detail = "virtual machine resource not found in cluster"
+					drift.Fix = DriftFixRecreate
+				}
+				drifts = append(drifts, drift)
+			}
+		}
+
+		// Provisioning 중에는 Service가 아직 없을 수 있음
+		if vm.Status == models.VmStatusProvisioning {
+			continue
+		}
+		port, ok := sshPorts[vm.Namespace+"/"+VMNames(vm.Name).SSHService]
+		if ok && port == vm.NodePort {
+			continue
+		}
+		drift := Drift{Kind: DriftPortMismatch, Namespace: vm.Namespace, Name: vm.Name, DBStatus: string(vm.Status), DBPort: vm.NodePort, ClusterPort: port, vm: vm}
+		if ok {
+			drift.Detail = fmt.Sprintf("ssh service node port %d differs from stored port %d", port, vm.NodePort)
+			drift.Fix = DriftFixPatch
+		} else {
+			drift.Detail = "ssh service not found in cluster"
+			drift.Fix = DriftFixRecreate
+		}
+		drifts = append(drifts, drift)
+	}
+
+	for key, instance := range instances {
+		if known[key] {
+			continue
+		}
+		if s.operationInFlight("vm/" + instance.Name) {
+			continue
+		}
+
+		drift := Drift{Kind: DriftOrphanInstance, Namespace: instance.Namespace, Name: instance.Name}
+		// 이 컨트롤러가 만들지 않은 VM은 다른 용도일 수 있으므로 자동으로 지우지 않음
+		if observed[key].Managed {
+			drift.Detail = fmt.Sprintf("managed instance (phase %s) has no vm record", instance.Phase)
+			drift.Fix = DriftFixDelete
+		} else {
+			drift.Detail = fmt.Sprintf("unmanaged instance (phase %s) has no vm record", instance.Phase)
+		}
+		drifts = append(drifts, drift)
+	}
+
+	sort.Slice(drifts, func(i, j int) bool {
+		if drifts[i].Kind != drifts[j].Kind {
+			return drifts[i].Kind < drifts[j].Kind
+		}
+		if drifts[i].Namespace != drifts[j].Namespace {
+			return drifts[i].Namespace < drifts[j].Namespace
+		}
+		return drifts[i].Name < drifts[j].Name
+	})
+
+	return drifts, nil
+}
+
+// FixDrift는 불일치 한 건을 Fix에 적힌 방법으로 수정합니다.
+// 시작/재생성은 백그라운드 작업으로 실행하고 작업 ID를 반환하며, 나머지는 바로 적용합니다. (작업 ID 0)
+func (s *K8sService) FixDrift(drift Drift, traceID string) (uint, error) {
+	switch drift.Fix {
+	case DriftFixStart:
+		return s.StartVMAsync(drift.vm, traceID), nil
+
+	case DriftFixRecreate:
+		return s.RecreateVMAsync(drift.vm, traceID), nil
+
+	case DriftFixDelete:
+		// DB 레코드가 없으므로 리소스 이름으로만 삭제
+		return 0, s.detached().DeleteVM(&models.VirtualMachine{Name: drift.Name, Namespace: drift.Namespace})
+
+	case DriftFixPatch:
+		return 0, s.patchSSHNodePort(drift.vm)
+	}
+
+	return 0, fmt.Errorf("%s drift of %s/%s cannot be fixed automatically", drift.Kind, drift.Namespace, drift.Name)
+}
+
+// patchSSHNodePort는 SSH Service의 NodePort를 DB에 저장된 포트로 되돌립니다.
+// 다른 Service가 그 포트를 쓰고 있으면 API 서버가 거부합니다.
+func (s *K8sService) patchSSHNodePort(vm *models.VirtualMachine) error {
+	ctx := s.baseContext()
+	services := s.clientset.CoreV1().Services(vm.Namespace)
+
+	service, err := services.Get(ctx, VMNames(vm.Name).SSHService, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get ssh service: %v", err)
+	}
+	if len(service.Spec.Ports) == 0 {
+		return fmt.Errorf("ssh service %s/%s has no ports", service.Namespace, service.Name)
+	}
+
+	service.Spec.Ports[0].NodePort = vm.NodePort
+	if _, err := services.Update(ctx, service, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update ssh service: %v", err)
+	}
+	s.recordVMPatch(vm.Name, "drift", fmt.Sprintf("restored ssh node port %d", vm.NodePort))
+
+	return nil
+}
+
+// ParseDriftKinds는 쉼표로 구분한 불일치 종류를 검사합니다. 비어 있으면 nil(모든 종류)을 반환합니다.
+func ParseDriftKinds(raw string) (map[DriftKind]bool, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	kinds := map[DriftKind]bool{}
+	for _, part := range strings.Split(raw, ",") {
+		kind := DriftKind(strings.TrimSpace(part))
+		if !slices.Contains(DriftKinds, kind) {
+			return nil, fmt.Errorf("unknown drift kind %q", kind)
+		}
+		kinds[kind] = true
+	}
+
+	return kinds, nil
+}
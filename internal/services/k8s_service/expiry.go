@@ -0,0 +1,96 @@
+package k8s_service
+
+import (
+	"time"
+	"vm-controller/internal/config"
+	"vm-controller/internal/models"
+	notificationservice "vm-controller/internal/services/notification_service"
+)
+
+// StartVMReaper는 주기적으로 사용 기간(expires_at)이 지난 VM을 정지하고,
+// 유예 기간(VM_EXPIRY_DELETE_AFTER_DAYS)이 더 지나면 삭제합니다. (학기 종료 후 학생 VM 회수)
+// 목표 상태만 바꾸고 작업을 시작하므로, 작업이 중단되어도 converger가 이어서 수렴시킵니다.
+func (s *K8sService) StartVMReaper(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			runRecovered("vm.reaper", "*", func() error {
+				s.reapExpiredVMs()
+				return nil
+			})
+		}
+	}()
+}
+
+func (s *K8sService) reapExpiredVMs() {
+	vmService := s.vmService
+	now := time.Now()
+
+	vms, err := vmService.FetchExpiredVMs(now)
+	if err != nil {
+		s.log().Error("vm reaper: failed to fetch expired VMs", "error", err)
+		return
+	}
+
+	deleteAfter := vmService.ExpiryDeleteAfter()
+	for i := range vms {
+		vm := &vms[i]
+
+		// 실행 중인 작업이 있으면 이번 주기는 건너뜀
+		if s.operationInFlight("vm/" + vm.Name) {
+			continue
+		}
+
+		// 이미 삭제 중인 VM은 converger가 처리
+		if vm.DesiredState == models.VmDesiredDeleted {
+			continue
+		}
+
+		deleteAt := vm.ExpiresAt.Add(deleteAfter)
+		switch {
+		case !now.Before(deleteAt):
+			s.reapVM(vm, models.VmDesiredDeleted, "expire.delete", notificationservice.Data{"vm": vm.Name})
+		case vm.DesiredState != models.VmDesiredStopped:
+			s.reapVM(vm, models.VmDesiredStopped, "expire.stop", notificationservice.Data{"vm": vm.Name, "delete_at": deleteAt.Format("2006-01-02 15:04")})
+		}
+	}
+}
+
+// reapVM은 만료된 VM의 목표 상태를 바꾸고 작업을 시작한 뒤 소유자에게 알립니다. (알림 문구는 "vm."+operation 템플릿)
+func (s *K8sService) reapVM(vm *models.VirtualMachine, desired models.EnumVmDesiredState, operation string, data notificationservice.Data) {
+	s.vmEventService.Record(models.VmEvent{
+		VmName:    vm.Name,
+		Type:      models.VmEventOperationRequested,
+		Operation: operation,
+		Detail:    "expired at " + vm.ExpiresAt.Format(time.RFC3339),
+	})
+
+	// 목표 상태를 먼저 저장 (다음 주기에 같은 VM을 다시 처리하지 않도록)
+	if err := s.vmService.SetDesiredState(vm.Name, desired); err != nil {
+		s.log().Error("vm reaper: failed to reap VM", "operation", operation, "vm", vm.Name, "error", err)
+		return
+	}
+	vm.DesiredState = desired
+
+	switch {
+	// Operator 모드: UserVM spec만 변경 (reconcile 컨트롤러가 목표 상태를 반영)
+	case config.Get().OperatorMode && desired == models.VmDesiredDeleted:
+		if err := s.DeleteUserVM(vm.Namespace, vm.Name); err != nil {
+			s.log().Error("vm reaper: failed to delete UserVM", "vm", vm.Name, "error", err)
+		}
+	case config.Get().OperatorMode:
+		if err := s.SetUserVMRunning(vm.Namespace, vm.Name, false); err != nil {
+			s.log().Error("vm reaper: failed to stop UserVM", "vm", vm.Name, "error", err)
+		}
+	case desired == models.VmDesiredDeleted:
+		s.DeleteVMAsync(vm, "")
+	default:
+		s.StopVMAsync(vm, "")
+	}
+
+	if err := s.notificationService.NotifyTemplate(vm.UserID, "vm."+operation, data); err != nil {
+		s.log().Warn("vm reaper: failed to notify owner", "vm", vm.Name, "error", err)
+	}
+}
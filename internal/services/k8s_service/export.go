@@ -0,0 +1,204 @@
+package k8s_service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+	"vm-controller/internal/models"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var gvrVMExport = schema.GroupVersionResource{Group: "export.kubevirt.io", Version: "v1beta1", Resource: "virtualmachineexports"}
+
+// 기본 export 유지 시간 (VM_EXPORT_TTL 로 변경 가능). TTL이 지나면 KubeVirt가 export를 삭제합니다.
+const defaultVMExportTTL = 24 * time.Hour
+
+const exportTokenHeader = "x-kubevirt-export-token"
+
+func vmExportTTL() time.Duration {
+	if ttl, err := time.ParseDuration(os.Getenv("VM_EXPORT_TTL")); err == nil && ttl > 0 {
+		return ttl
+	}
+	return defaultVMExportTTL
+}
+
+// VMExportStatus는 사용자에게 보여줄 디스크 export 상태입니다.
+type VMExportStatus struct {
+	Phase     string    `json:"phase"`      // Pending, Ready, Terminated
+	Formats   []string  `json:"formats"`    // 다운로드 가능한 형식 (gzip, raw)
+	ExpiresAt time.Time `json:"expires_at"` // 자동 만료 시각
+	Message   string    `json:"message,omitempty"`
+}
+
+// CreateVMExport는 VM 디스크를 내려받기 위한 VirtualMachineExport와 접근 토큰 Secret을 생성합니다.
+// VM이 실행 중이면 KubeVirt는 VM이 정지될 때까지 export를 Pending으로 유지합니다.
+func (s *K8sService) CreateVMExport(vm *models.VirtualMachine) (*VMExportStatus, error) {
+	ctx := s.baseContext()
+	names := VMNames(vm.Name)
+	ttl := vmExportTTL()
+
+	export := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "export.kubevirt.io/v1beta1",
+		"kind":       "VirtualMachineExport",
+		"metadata": map[string]interface{}{
+			"name":      names.Export,
+			"namespace": vm.Namespace,
+		},
+		"spec": map[string]interface{}{
+			"source": map[string]interface{}{
+				"apiGroup": "kubevirt.io",
+				"kind":     "VirtualMachine",
+				"name":     vm.Name,
+			},
+			"tokenSecretRef": names.ExportToken,
+			"ttlDuration":    ttl.String(),
+		},
+	}}
+
+	created, err := s.dynamicClient.Resource(gvrVMExport).Namespace(vm.Namespace).Create(ctx, export, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		return s.GetVMExportStatus(vm)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create virtual machine export: %v", err)
+	}
+
+	// 토큰 Secret은 export가 TTL로 삭제될 때 함께 정리되도록 export를 owner로 지정
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return nil, fmt.Errorf("failed to generate export token: %v", err)
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      names.ExportToken,
+			Namespace: vm.Namespace,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "export.kubevirt.io/v1beta1",
+				Kind:       "VirtualMachineExport",
+				Name:       created.GetName(),
+				UID:        created.GetUID(),
+			}},
+		},
+		StringData: map[string]string{"token": hex.EncodeToString(tokenBytes)},
+	}
+	if _, err := s.clientset.CoreV1().Secrets(vm.Namespace).Create(ctx, secret, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return nil, fmt.Errorf("failed to create export token secret: %v", err)
+	}
+
+	return &VMExportStatus{Phase: "Pending", Formats: []string{}, ExpiresAt: created.GetCreationTimestamp().Add(ttl)}, nil
+}
+
+// GetVMExportStatus는 VM export의 준비 상태를 반환합니다. (export가 없으면 nil)
+func (s *K8sService) GetVMExportStatus(vm *models.VirtualMachine) (*VMExportStatus, error) {
+	obj, err := s.dynamicClient.Resource(gvrVMExport).Namespace(vm.Namespace).Get(s.baseContext(), VMNames(vm.Name).Export, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get virtual machine export: %v", err)
+	}
+
+	phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+	status := &VMExportStatus{Phase: phase, Formats: []string{}, ExpiresAt: obj.GetCreationTimestamp().Add(vmExportTTL())}
+
+	if ttl, found, _ := unstructured.NestedString(obj.Object, "spec", "ttlDuration"); found {
+		if duration, err := time.ParseDuration(ttl); err == nil {
+			status.ExpiresAt = obj.GetCreationTimestamp().Add(duration)
+		}
+	}
+
+	volumes, _, _ := unstructured.NestedSlice(obj.Object, "status", "links", "internal", "volumes")
+	for _, raw := range volumes {
+		volume, _ := raw.(map[string]interface{})
+		formats, _ := volume["formats"].([]interface{})
+		for _, rawFormat := range formats {
+			format, _ := rawFormat.(map[string]interface{})
+			if name, ok := format["format"].(string); ok && (name == "gzip" || name == "raw") {
+				status.Formats = append(status.Formats, name)
+			}
+		}
+	}
+
+	if phase != "Ready" {
+		conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+		for _, raw := range conditions {
+			condition, _ := raw.(map[string]interface{})
+			if message, _ := condition["message"].(string); message != "" {
+				status.Message = message
+			}
+		}
+	}
+
+	return status, nil
+}
+
+// OpenVMExportDownload는 export 서버에 다운로드 요청을 보내고 응답을 그대로 반환합니다.
+// rangeHeader를 전달하여 이어받기(206 Partial Content)를 지원합니다. 호출자가 Body를 닫아야 합니다.
+func (s *K8sService) OpenVMExportDownload(ctx context.Context, vm *models.VirtualMachine, format, rangeHeader string) (*http.Response, error) {
+	obj, err := s.dynamicClient.Resource(gvrVMExport).Namespace(vm.Namespace).Get(ctx, VMNames(vm.Name).Export, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get virtual machine export: %v", err)
+	}
+
+	if phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase"); phase != "Ready" {
+		return nil, fmt.Errorf("export is not ready (phase: %s)", phase)
+	}
+
+	// 클러스터 내부 링크 사용 (API 서버가 클러스터 안에서 동작)
+	cert, _, _ := unstructured.NestedString(obj.Object, "status", "links", "internal", "cert")
+	url := ""
+	volumes, _, _ := unstructured.NestedSlice(obj.Object, "status", "links", "internal", "volumes")
+	for _, raw := range volumes {
+		volume, _ := raw.(map[string]interface{})
+		formats, _ := volume["formats"].([]interface{})
+		for _, rawFormat := range formats {
+			entry, _ := rawFormat.(map[string]interface{})
+			if entry["format"] == format {
+				url, _ = entry["url"].(string)
+			}
+		}
+	}
+	if url == "" {
+		return nil, fmt.Errorf("format %s is not available for this export", format)
+	}
+
+	secret, err := s.clientset.CoreV1().Secrets(vm.Namespace).Get(ctx, VMNames(vm.Name).ExportToken, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export token: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM([]byte(cert))
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}}}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(exportTokenHeader, string(secret.Data["token"]))
+	if rangeHeader != "" {
+		req.Header.Set("Range", rangeHeader)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download from export server: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		resp.Body.Close()
+		return nil, fmt.Errorf("export server returned %s", resp.Status)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,162 @@
+package k8s_service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"vm-controller/internal/models"
+	flavorservice "vm-controller/internal/services/flavor_service"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Flavor는 VM 요금제별 CPU/메모리/디스크 할당과 가격입니다. (models.Flavor, 관리자가 /api/admin/flavors로 관리)
+// CPU limit으로 한 VM이 노드의 CPU를 독점하여 같은 노드의 다른 VM이 느려지는 것(noisy neighbor)을 막습니다.
+type Flavor struct {
+	Name         string `json:"name"`
+	Cores        int    `json:"cores"`         // 게스트에 보이는 vCPU 수
+	CPURequest   string `json:"cpu_request"`   // 스케줄링 시 보장되는 CPU
+	CPULimit     string `json:"cpu_limit"`     // 사용 가능한 최대 CPU
+	Memory       string `json:"memory"`        // 메모리 (request = limit)
+	DiskGi       int    `json:"disk_gi"`       // 루트 디스크 크기 (GiB)
+	DedicatedCPU bool   `json:"dedicated_cpu"` // 전용 CPU 배치 (ENABLE_DEDICATED_CPU=true 일 때만 적용)
+	HourlyPrice  int    `json:"hourly_price"`  // 시간당 가격 (원)
+	MonthlyPrice int    `json:"monthly_price"` // 월 가격 (원)
+}
+
+// 기본 요금제
+const DefaultFlavor = "standard"
+
+// 요금제 이름은 VM 레코드와 API에서 그대로 쓰이므로 DNS 레이블 형식으로 제한
+var flavorNamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,30}[a-z0-9])?$`)
+
+// GetFlavor는 이름에 해당하는 요금제를 반환합니다. 빈 이름은 기본 요금제로 취급합니다.
+func GetFlavor(flavorService *flavorservice.FlavorService, name string) (Flavor, error) {
+	if name == "" {
+		name = DefaultFlavor
+	}
+
+	flavor, err := flavorService.FetchFlavor(name)
+	if errors.Is(err, flavorservice.ErrFlavorNotFound) {
+		return Flavor{}, fmt.Errorf("unknown flavor: %s", name)
+	}
+	if err != nil {
+		return Flavor{}, fmt.Errorf("failed to fetch flavor %s: %v", name, err)
+	}
+	return FlavorFromModel(flavor), nil
+}
+
+// ListFlavors는 선택 가능한 요금제 목록을 반환합니다.
+func ListFlavors(flavorService *flavorservice.FlavorService) ([]Flavor, error) {
+	stored, err := flavorService.FetchFlavors()
+	if err != nil {
+		return nil, err
+	}
+
+	flavors := make([]Flavor, 0, len(stored))
+	for i := range stored {
+		flavors = append(flavors, FlavorFromModel(&stored[i]))
+	}
+	return flavors, nil
+}
+
+// FlavorFromModel은 저장된 요금제를 템플릿/응답에 쓰는 Flavor로 변환합니다.
+func FlavorFromModel(flavor *models.Flavor) Flavor {
+	return Flavor{
+		Name:         flavor.Name,
+		Cores:        flavor.Cores,
+		CPURequest:   flavor.CPURequest,
+		CPULimit:     flavor.CPULimit,
+		Memory:       flavor.Memory,
+		DiskGi:       flavor.DiskGi,
+		DedicatedCPU: flavor.DedicatedCPU,
+		HourlyPrice:  flavor.HourlyPrice,
+		MonthlyPrice: flavor.MonthlyPrice,
+	}
+}
+
+// Model은 저장할 요금제 레코드를 만듭니다.
+func (f Flavor) Model() *models.Flavor {
+	return &models.Flavor{
+		Name:         f.Name,
+		Cores:        f.Cores,
+		CPURequest:   f.CPURequest,
+		CPULimit:     f.CPULimit,
+		Memory:       f.Memory,
+		DiskGi:       f.DiskGi,
+		DedicatedCPU: f.DedicatedCPU,
+		HourlyPrice:  f.HourlyPrice,
+		MonthlyPrice: f.MonthlyPrice,
+	}
+}
+
+// Validate는 관리자가 입력한 요금제 값을 검사합니다.
+// 값이 VM 템플릿에 그대로 치환되므로 K8s 수량 형식만 허용합니다.
+func (f Flavor) Validate() error {
+	if !flavorNamePattern.MatchString(f.Name) {
+		return fmt.Errorf("name must be lowercase alphanumeric or '-' (max 32 characters)")
+	}
+	if f.Cores <= 0 {
+		return fmt.Errorf("cores must be positive")
+	}
+	if f.DiskGi <= 0 {
+		return fmt.Errorf("disk_gi must be positive")
+	}
+	if f.HourlyPrice < 0 || f.MonthlyPrice < 0 {
+		return fmt.Errorf("prices must not be negative")
+	}
+
+	request, err := resource.ParseQuantity(f.CPURequest)
+	if err != nil {
+		return fmt.Errorf("cpu_request must be a cpu quantity (e.g. 500m, 2)")
+	}
+	limit, err := resource.ParseQuantity(f.CPULimit)
+	if err != nil {
+		return fmt.Errorf("cpu_limit must be a cpu quantity (e.g. 500m, 2)")
+	}
+	if request.Cmp(limit) > 0 {
+		return fmt.Errorf("cpu_request must not exceed cpu_limit")
+	}
+	if _, err := resource.ParseQuantity(f.Memory); err != nil {
+		return fmt.Errorf("memory must be a memory quantity (e.g. 4Gi)")
+	}
+	// 전용 CPU는 request = limit = 정수 코어일 때만 배치 가능
+	if f.DedicatedCPU && (request.Cmp(limit) != 0 || request.MilliValue()%1000 != 0) {
+		return fmt.Errorf("dedicated_cpu requires cpu_request and cpu_limit to be the same whole number of cores")
+	}
+	return nil
+}
+
+// RequiresApproval은 요금제로 VM을 만들기 전에 관리자 승인이 필요한지 확인합니다.
+// CPU request가 VM_APPROVAL_CPU_THRESHOLD(예: 1)를 초과하면 승인 대상이며, 비어 있거나 형식이 잘못되면 승인 없이 생성합니다.
+func (f Flavor) RequiresApproval() bool {
+	threshold, err := resource.ParseQuantity(os.Getenv("VM_APPROVAL_CPU_THRESHOLD"))
+	if err != nil {
+		return false
+	}
+
+	request, err := resource.ParseQuantity(f.CPURequest)
+	if err != nil {
+		return false
+	}
+	return request.Cmp(threshold) > 0
+}
+
+// dedicatedCPUPlacement는 전용 CPU 배치를 사용할지 결정합니다.
+// 노드에 CPU Manager(static 정책)가 켜져 있어야 하므로 클러스터 설정에 따라 선택적으로 활성화합니다.
+func (f Flavor) dedicatedCPUPlacement() bool {
+	return f.DedicatedCPU && os.Getenv("ENABLE_DEDICATED_CPU") == "true"
+}
+
+// flavorReplacements는 VM 템플릿의 CPU/메모리/디스크 항목에 치환할 값을 만듭니다.
+func flavorReplacements(flavor Flavor) map[string]string {
+	return map[string]string{
+		"{{CPU_CORES}}":     fmt.Sprintf("%d", flavor.Cores),
+		"{{CPU_REQUEST}}":   flavor.CPURequest,
+		"{{CPU_LIMIT}}":     flavor.CPULimit,
+		"{{MEMORY}}":        flavor.Memory,
+		"{{DEDICATED_CPU}}": fmt.Sprintf("%t", flavor.dedicatedCPUPlacement()),
+		"{{DISK_SIZE}}":     fmt.Sprintf("%dGi", flavor.DiskGi),
+	}
+}
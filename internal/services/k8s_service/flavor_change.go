@@ -0,0 +1,367 @@
+package k8s_service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/metrics"
+	"vm-controller/internal/models"
+	notificationservice "vm-controller/internal/services/notification_service"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Running으로 남은 변경을 중단된 것으로 보는 시간 (재시작 대기 시간보다 충분히 길게)
+const flavorChangeStaleAfter = 1 * time.Hour
+
+// QuotaImpact는 요금제 변경이 네임스페이스 VM 파드 쿼터(tenant-vm-pods)에 미치는 영향입니다.
+type QuotaImpact struct {
+	Resource string `json:"resource"` // requests.cpu / requests.memory
+	Hard     string `json:"hard"`
+	Used     string `json:"used"`
+	After    string `json:"after"`    // 변경 후 (정지된 VM은 다시 시작했을 때) 예상 사용량
+	Exceeded bool   `json:"exceeded"` // 변경 후 hard 초과 (VM 파드가 생성되지 않음)
+}
+
+// FlavorChangePreview는 요금제 변경 전에 보여주는 가격/쿼터 변화입니다.
+type FlavorChangePreview struct {
+	From              Flavor        `json:"from"`
+	To                Flavor        `json:"to"`
+	HourlyPriceDelta  int           `json:"hourly_price_delta"`
+	MonthlyPriceDelta int           `json:"monthly_price_delta"`
+	DiskGi            int           `json:"disk_gi"`          // 루트 디스크는 바뀌지 않음
+	RestartRequired   bool          `json:"restart_required"` // 실행 중인 VM은 재시작하여 반영
+	Quota             []QuotaImpact `json:"quota"`            // VM 파드 쿼터가 없으면 빈 목록
+}
+
+// QuotaExceeded는 변경 후 VM 파드 쿼터를 넘는 자원이 있는지 확인합니다.
+func (p *FlavorChangePreview) QuotaExceeded() *QuotaImpact {
+	for i := range p.Quota {
+		if p.Quota[i].Exceeded {
+			return &p.Quota[i]
+		}
+	}
+	return nil
+}
+
+// PreviewFlavorChange는 vm의 요금제를 to로 바꿨을 때의 가격/쿼터 변화를 계산합니다.
+// 현재 요금제가 삭제되어 찾을 수 없으면 가격과 자원을 0으로 보고 계산합니다. (쿼터는 보수적으로 계산됨)
+func (s *K8sService) PreviewFlavorChange(vm *models.VirtualMachine, to Flavor) (*FlavorChangePreview, error) {
+	from, err := GetFlavor(s.flavorService, vm.Flavor)
+	if err != nil {
+		from = Flavor{Name: vm.Flavor}
+	}
+
+	running := vm.Status == models.VmStatusRunning
+	quota, err := s.vmPodQuotaImpact(vm.Namespace, from, to, running)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FlavorChangePreview{
+		From:              from,
+		To:                to,
+		HourlyPriceDelta:  to.HourlyPrice - from.HourlyPrice,
+		MonthlyPriceDelta: to.MonthlyPrice - from.MonthlyPrice,
+		DiskGi:            vm.DiskGi,
+		RestartRequired:   running,
+		Quota:             quota,
+	}, nil
+}
+
+// vmPodQuotaImpact는 네임스페이스 VM 파드 쿼터의 CPU/메모리 requests 변화를 계산합니다.
+// 실행 중인 VM은 현재 요청량이 이미 사용량에 포함되어 있으므로 차이만 더하고, 정지된 VM은 새 요청량 전체를 더합니다.
+// 줄어드는 변경은 이미 쿼터를 넘은 상태여도 초과로 보지 않습니다.
+func (s *K8sService) vmPodQuotaImpact(namespace string, from, to Flavor, running bool) ([]QuotaImpact, error) {
+	quota, err := s.clientset.CoreV1().ResourceQuotas(namespace).Get(s.baseContext(), vmPodQuotaName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return []QuotaImpact{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get VM pod quota: %w", err)
+	}
+
+	requests := map[corev1.ResourceName][2]string{
+		corev1.ResourceRequestsCPU:    {from.CPURequest, to.CPURequest},
+		corev1.ResourceRequestsMemory: {from.Memory, to.Memory},
+	}
+
+	impacts := []QuotaImpact{}
+	for _, name := range []corev1.ResourceName{corev1.ResourceRequestsCPU, corev1.ResourceRequestsMemory} {
+		hard, ok := quota.Status.Hard[name]
+		if !ok {
+			continue
+		}
+		used := quota.Status.Used[name]
+
+		current := parseQuantityOrZero(requests[name][0])
+		next := parseQuantityOrZero(requests[name][1])
+
+		after := used.DeepCopy()
+		if running {
+			after.Sub(current)
+		}
+		after.Add(next)
+
+		impacts = append(impacts, QuotaImpact{
+			Resource: string(name),
+			Hard:     hard.String(),
+			Used:     used.String(),
+			After:    after.String(),
+			Exceeded: next.Cmp(current) > 0 && after.Cmp(hard) > 0,
+		})
+	}
+
+	return impacts, nil
+}
+
+func parseQuantityOrZero(value string) resource.Quantity {
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return resource.Quantity{}
+	}
+	return quantity
+}
+
+// flavorSpecPatch는 VirtualMachine 템플릿의 CPU/메모리 항목을 요금제 값으로 바꾸는 merge patch입니다.
+// (client-vm 템플릿의 {{CPU_CORES}}, {{CPU_REQUEST}} 등이 치환되는 위치와 같음)
+func flavorSpecPatch(flavor Flavor) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"domain": map[string]interface{}{
+						"cpu": map[string]interface{}{
+							"cores":                 flavor.Cores,
+							"dedicatedCpuPlacement": flavor.dedicatedCPUPlacement(),
+						},
+						"resources": map[string]interface{}{
+							"requests": map[string]string{"cpu": flavor.CPURequest, "memory": flavor.Memory},
+							"limits":   map[string]string{"cpu": flavor.CPULimit, "memory": flavor.Memory},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+// ChangeVMFlavor는 VM의 CPU/메모리를 요금제 to로 바꿉니다.
+// 1. 쿼터를 다시 확인 (예약 후 다른 VM이 쿼터를 사용했을 수 있음)
+// 2. VirtualMachine 템플릿 패치 (KubeVirt는 다음 인스턴스부터 반영)
+// 3. DB 요금제 변경 (재생성/재조정도 새 요금제로 렌더링)
+// 4. 실행 중이면 재시작하여 새 인스턴스에 반영
+func (s *K8sService) ChangeVMFlavor(vm *models.VirtualMachine, to Flavor) error {
+	preview, err := s.PreviewFlavorChange(vm, to)
+	if err != nil {
+		return err
+	}
+	if exceeded := preview.QuotaExceeded(); exceeded != nil {
+		return fmt.Errorf("%s quota would be exceeded: %s > %s", exceeded.Resource, exceeded.After, exceeded.Hard)
+	}
+
+	patch, err := flavorSpecPatch(to)
+	if err != nil {
+		return err
+	}
+	if _, err := s.dynamicClient.Resource(gvrVM).Namespace(vm.Namespace).Patch(
+		s.baseContext(), vm.Name, types.MergePatchType, patch, metav1.PatchOptions{},
+	); err != nil {
+		return fmt.Errorf("failed to patch VM flavor: %w", err)
+	}
+	s.recordVMPatch(vm.Name, "flavor.change", string(patch))
+
+	if err := s.vmService.UpdateVmFlavor(vm.Name, to.Name); err != nil {
+		return fmt.Errorf("failed to update VM flavor: %w", err)
+	}
+	vm.Flavor = to.Name
+
+	if !preview.RestartRequired {
+		return nil
+	}
+	return s.restartVM(vm, RestartTimeout())
+}
+
+// ChangeVMFlavorAsync는 Running으로 표시된(Claim된) 요금제 변경을 백그라운드로 실행하고 작업 ID를 기록합니다.
+// 재시작은 반복하면 안 되므로 재시도하지 않으며, 결과는 변경 요청과 VM 이벤트에 기록하고 소유자에게 알립니다.
+func (s *K8sService) ChangeVMFlavorAsync(vm *models.VirtualMachine, change *models.VmFlavorChange, to Flavor) uint {
+	operationID := s.RunAsync(s.flavorChangeOperation(vm, change, to))
+
+	if operationID != 0 {
+		change.OperationID = &operationID
+		if err := s.flavorChangeService.SetOperation(change.ID, operationID); err != nil {
+			s.log().Warn("flavor change: failed to record operation", "change_id", change.ID, "error", err)
+		}
+	}
+	return operationID
+}
+
+func (s *K8sService) flavorChangeOperation(vm *models.VirtualMachine, change *models.VmFlavorChange, to Flavor) AsyncOperation {
+	changes := s.flavorChangeService
+
+	return AsyncOperation{
+		Name:   "vm.flavor",
+		Target: "vm/" + vm.Name,
+		Tenant: metrics.TenantLabelFor(vm.UserID),
+		Owner:  vm.UserID,
+		Run:    func() error { return s.ChangeVMFlavor(vm, to) },
+		Compensate: func(err error) {
+			s.vmEventService.Record(models.VmEvent{
+				VmName:    vm.Name,
+				Type:      models.VmEventOperationFailed,
+				Operation: "flavor.change",
+				Detail:    err.Error(),
+			})
+			if errFinish := changes.Finish(change.ID, models.FlavorChangeFailed, err.Error()); errFinish != nil {
+				s.log().Error("flavor change: failed to mark change as Failed", "change_id", change.ID, "error", errFinish)
+			}
+			s.notifyFlavorChange(change, "vm.flavor.failed", notificationservice.Data{"vm": change.VmName, "to": change.ToFlavor, "reason": err.Error()})
+		},
+		OnSuccess: func() {
+			s.vmEventService.Record(models.VmEvent{
+				VmName:    vm.Name,
+				Type:      models.VmEventOperationSucceeded,
+				Operation: "flavor.change",
+				Detail:    change.FromFlavor + " -> " + change.ToFlavor,
+			})
+			if errFinish := changes.Finish(change.ID, models.FlavorChangeCompleted, ""); errFinish != nil {
+				s.log().Error("flavor change: failed to mark change as Completed", "change_id", change.ID, "error", errFinish)
+			}
+			s.notifyFlavorChange(change, "vm.flavor.completed", notificationservice.Data{"vm": change.VmName, "from": change.FromFlavor, "to": change.ToFlavor})
+		},
+		payload: asyncPayload{VM: vm.Name, FlavorChangeID: change.ID},
+	}
+}
+
+func (s *K8sService) notifyFlavorChange(change *models.VmFlavorChange, key string, data notificationservice.Data) {
+	if err := s.notificationService.NotifyTemplate(change.UserID, key, data); err != nil {
+		slog.Warn("flavor change: failed to notify user", "user_id", change.UserID, "change_id", change.ID, "error", err)
+	}
+}
+
+// StartFlavorChangeScheduler는 주기적으로 시간대가 시작된 요금제 변경을 실행합니다.
+// VM에 다른 작업이 실행 중이거나 변경할 수 없는 상태이면 다음 주기에 다시 시도하고, 시간대가 지나면 Missed로 표시합니다.
+func (s *K8sService) StartFlavorChangeScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			runRecovered("vm.flavor.scheduler", "*", func() error {
+				s.runDueFlavorChanges()
+				return nil
+			})
+		}
+	}()
+}
+
+func (s *K8sService) runDueFlavorChanges() {
+	changes := s.flavorChangeService
+	now := time.Now()
+
+	// 서버 재시작 등으로 결과가 기록되지 않은 변경 정리
+	if stale, err := changes.FetchStaleRunning(now.Add(-flavorChangeStaleAfter)); err == nil {
+		for i := range stale {
+			if s.operationInFlight("vm/" + stale[i].VmName) {
+				continue
+			}
+			if err := changes.Finish(stale[i].ID, models.FlavorChangeFailed, "interrupted"); err != nil {
+				s.log().Error("flavor change: failed to mark change as Failed", "change_id", stale[i].ID, "error", err)
+			}
+		}
+	}
+
+	due, err := changes.FetchDueChanges(now)
+	if err != nil {
+		s.log().Error("flavor change: failed to fetch due changes", "error", err)
+		return
+	}
+
+	for i := range due {
+		change := &due[i]
+
+		if now.After(change.WindowEnd) {
+			s.missFlavorChange(change, "window ended before the change could run")
+			continue
+		}
+
+		vm, err := s.vmService.FetchVmName(change.VmName, false)
+		if err != nil {
+			s.log().Error("flavor change: failed to fetch VM", "vm", change.VmName, "error", err)
+			continue
+		}
+		if vm == nil {
+			if err := changes.Finish(change.ID, models.FlavorChangeFailed, "vm not found"); err != nil {
+				s.log().Error("flavor change: failed to mark change as Failed", "change_id", change.ID, "error", err)
+			}
+			continue
+		}
+
+		// 다른 작업 중이거나 전이 중인 VM은 다음 주기에 다시 시도
+		if s.operationInFlight("vm/" + vm.Name) {
+			continue
+		}
+		if vm.Status != models.VmStatusRunning && vm.Status != models.VmStatusStopped {
+			continue
+		}
+
+		s.vmEventService.Record(models.VmEvent{
+			VmName:    vm.Name,
+			Type:      models.VmEventOperationRequested,
+			Operation: "flavor.change",
+			Detail:    change.FromFlavor + " -> " + change.ToFlavor,
+		})
+		if _, err := s.StartFlavorChange(vm, change); err != nil {
+			s.log().Error("flavor change: failed to start change", "change_id", change.ID, "error", err)
+		}
+	}
+}
+
+// StartFlavorChange는 Scheduled 변경을 Running으로 표시하고 실행합니다.
+// 다른 곳에서 이미 실행/취소된 변경이면 0을 반환합니다. 변경할 요금제가 삭제되었으면 Failed로 표시합니다.
+func (s *K8sService) StartFlavorChange(vm *models.VirtualMachine, change *models.VmFlavorChange) (uint, error) {
+	changes := s.flavorChangeService
+
+	to, err := GetFlavor(s.flavorService, change.ToFlavor)
+	if err != nil {
+		if errFinish := changes.Finish(change.ID, models.FlavorChangeFailed, err.Error()); errFinish != nil {
+			return 0, errFinish
+		}
+		return 0, apperrors.From(err, apperrors.CodeInvalidRequest)
+	}
+
+	claimed, err := changes.Claim(change.ID, time.Now())
+	if err != nil || !claimed {
+		return 0, err
+	}
+	change.Status = models.FlavorChangeRunning
+
+	return s.ChangeVMFlavorAsync(vm, change, to), nil
+}
+
+// missFlavorChange는 시간대 안에 실행하지 못한 변경을 Missed로 표시하고 소유자에게 알립니다.
+func (s *K8sService) missFlavorChange(change *models.VmFlavorChange, reason string) {
+	if err := s.flavorChangeService.Finish(change.ID, models.FlavorChangeMissed, reason); err != nil {
+		s.log().Error("flavor change: failed to mark change as Missed", "change_id", change.ID, "error", err)
+		return
+	}
+
+	s.vmEventService.Record(models.VmEvent{
+		VmName:    change.VmName,
+		Type:      models.VmEventOperationFailed,
+		Operation: "flavor.change",
+		Detail:    reason,
+	})
+	s.notifyFlavorChange(change, "vm.flavor.missed", notificationservice.Data{
+		"vm":           change.VmName,
+		"to":           change.ToFlavor,
+		"window_start": change.WindowStart.Format("2006-01-02 15:04"),
+		"window_end":   change.WindowEnd.Format("2006-01-02 15:04"),
+	})
+}
@@ -0,0 +1,161 @@
+package k8s_service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"vm-controller/internal/models"
+	imageservice "vm-controller/internal/services/image_service"
+)
+
+// Image는 VM 이미지 카탈로그 항목입니다. (models.Image, 관리자가 /api/admin/images로 관리)
+type Image struct {
+	Name            string   `json:"name"`
+	DisplayName     string   `json:"display_name"`
+	SourcePVC       string   `json:"source_pvc"`       // 루트 디스크로 복제할 원본 PVC
+	SourceNamespace string   `json:"source_namespace"` // 원본 PVC의 네임스페이스
+	SSHUser         string   `json:"ssh_user"`         // 기본 SSH 사용자 (cloud-init 사용자, 접속 정보)
+	AuthMethods     []string `json:"auth_methods"`     // password, publickey
+}
+
+// 기본 이미지 (vm_image를 비워 두면 사용)
+const DefaultImage = "ubuntu-22.04"
+
+// 사용자가 디스크 이미지를 직접 업로드하는 VM의 이미지 이름 (카탈로그에 없음)
+const UploadImage = "upload"
+
+// SSH 인증 방식
+const (
+	AuthMethodPassword  = "password"
+	AuthMethodPublicKey = "publickey"
+)
+
+// 업로드 VM은 게스트 사용자를 알 수 없으므로 root로 접속 (client-vm-upload 템플릿)
+var uploadImage = Image{
+	Name:        UploadImage,
+	SSHUser:     "root",
+	AuthMethods: []string{AuthMethodPassword, AuthMethodPublicKey},
+}
+
+// 이미지 카탈로그 이전에 만든 VM은 root 계정만 설정되어 있음
+const legacySSHUser = "root"
+
+var (
+	imageNamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9.-]{0,30}[a-z0-9])?$`)
+	dnsLabelPattern  = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+	sshUserPattern   = regexp.MustCompile(`^[a-z_][a-z0-9_-]{0,31}$`)
+)
+
+// GetImage는 이름에 해당하는 이미지를 반환합니다. 빈 이름은 기본 이미지로 취급합니다.
+func GetImage(imageService *imageservice.ImageService, name string) (Image, error) {
+	if name == "" {
+		name = DefaultImage
+	}
+	if name == UploadImage {
+		return uploadImage, nil
+	}
+
+	image, err := imageService.FetchImage(name)
+	if errors.Is(err, imageservice.ErrImageNotFound) {
+		return Image{}, fmt.Errorf("unknown image: %s", name)
+	}
+	if err != nil {
+		return Image{}, fmt.Errorf("failed to fetch image %s: %v", name, err)
+	}
+	return ImageFromModel(image), nil
+}
+
+// ListImages는 선택 가능한 이미지 목록을 반환합니다.
+func ListImages(imageService *imageservice.ImageService) ([]Image, error) {
+	stored, err := imageService.FetchImages()
+	if err != nil {
+		return nil, err
+	}
+
+	images := make([]Image, 0, len(stored))
+	for i := range stored {
+		images = append(images, ImageFromModel(&stored[i]))
+	}
+	return images, nil
+}
+
+// ImageFromModel은 저장된 이미지를 템플릿/응답에 쓰는 Image로 변환합니다.
+func ImageFromModel(image *models.Image) Image {
+	return Image{
+		Name:            image.Name,
+		DisplayName:     image.DisplayName,
+		SourcePVC:       image.SourcePVC,
+		SourceNamespace: image.SourceNamespace,
+		SSHUser:         image.SSHUser,
+		AuthMethods:     image.AuthMethods,
+	}
+}
+
+// Model은 저장할 이미지 레코드를 만듭니다.
+func (i Image) Model() *models.Image {
+	return &models.Image{
+		Name:            i.Name,
+		DisplayName:     i.DisplayName,
+		SourcePVC:       i.SourcePVC,
+		SourceNamespace: i.SourceNamespace,
+		SSHUser:         i.SSHUser,
+		AuthMethods:     i.AuthMethods,
+	}
+}
+
+// Validate는 관리자가 입력한 이미지 값을 검사합니다.
+// 값이 VM 템플릿(YAML, cloud-init)에 그대로 치환되므로 이름 형식만 허용합니다.
+func (i Image) Validate() error {
+	if !imageNamePattern.MatchString(i.Name) || i.Name == UploadImage {
+		return fmt.Errorf("name must be lowercase alphanumeric, '-' or '.' (max 32 characters)")
+	}
+	if !dnsLabelPattern.MatchString(i.SourcePVC) || !dnsLabelPattern.MatchString(i.SourceNamespace) {
+		return fmt.Errorf("source_pvc and source_namespace must be valid kubernetes names")
+	}
+	if !sshUserPattern.MatchString(i.SSHUser) {
+		return fmt.Errorf("ssh_user must be a valid linux user name")
+	}
+	if len(i.AuthMethods) == 0 {
+		return fmt.Errorf("auth_methods must not be empty")
+	}
+	for _, method := range i.AuthMethods {
+		if method != AuthMethodPassword && method != AuthMethodPublicKey {
+			return fmt.Errorf("auth_methods must be password or publickey")
+		}
+	}
+	return nil
+}
+
+// VMSSHUser는 VM에 접속할 SSH 사용자를 반환합니다. (생성 시 이미지의 기본 사용자, 카탈로그 이전 VM은 root)
+func VMSSHUser(vm *models.VirtualMachine) string {
+	if vm.SSHUser == "" {
+		return legacySSHUser
+	}
+	return vm.SSHUser
+}
+
+// AllowsPassword는 SSH 비밀번호 인증을 허용하는지 확인합니다.
+func (i Image) AllowsPassword() bool {
+	for _, method := range i.AuthMethods {
+		if method == AuthMethodPassword {
+			return true
+		}
+	}
+	return false
+}
+
+// imageReplacements는 VM 템플릿의 원본 디스크와 cloud-init 사용자 항목에 치환할 값을 만듭니다.
+func imageReplacements(image Image) map[string]string {
+	sshdPasswordAuth := "no"
+	if image.AllowsPassword() {
+		sshdPasswordAuth = "yes"
+	}
+
+	return map[string]string{
+		"{{IMAGE_SOURCE_PVC}}":       image.SourcePVC,
+		"{{IMAGE_SOURCE_NAMESPACE}}": image.SourceNamespace,
+		"{{SSH_USER}}":               image.SSHUser,
+		"{{SSH_PASSWORD_AUTH}}":      fmt.Sprintf("%t", image.AllowsPassword()),
+		"{{SSHD_PASSWORD_AUTH}}":     sshdPasswordAuth,
+	}
+}
@@ -0,0 +1,253 @@
+package k8s_service
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+	"vm-controller/internal/models"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// 한 번의 검사(DNS, TLS, HTTP 각각)에 허용하는 시간
+const ingressProbeTimeout = 5 * time.Second
+
+// 동시에 검사하는 VM 수
+const ingressProbeConcurrency = 8
+
+// 도달성 판정 결과 (사용자가 플랫폼 문제와 자신의 앱 문제를 구분할 수 있도록)
+const (
+	ReachabilityOK          = "ok"
+	ReachabilityPlatform    = "platform"    // DNS/TLS/Ingress 문제 (서비스는 응답함)
+	ReachabilityApplication = "application" // VM의 웹 서버가 응답하지 않거나 5xx
+)
+
+// IngressReachability는 클러스터 내부에서 VM의 웹 Service/Ingress 호스트를 검사한 결과입니다.
+type IngressReachability struct {
+	Host          string     `json:"host"`
+	DNSResolved   bool       `json:"dns_resolved"`
+	DNSError      string     `json:"dns_error,omitempty"`
+	TLSEnabled    bool       `json:"tls_enabled"`
+	TLSValid      bool       `json:"tls_valid"`
+	TLSError      string     `json:"tls_error,omitempty"`
+	TLSExpiresAt  *time.Time `json:"tls_expires_at,omitempty"`
+	HTTPStatus    int        `json:"http_status,omitempty"` // Ingress 호스트로 요청한 응답 코드
+	HTTPError     string     `json:"http_error,omitempty"`
+	ServiceStatus int        `json:"service_status,omitempty"` // Ingress를 거치지 않고 Service로 직접 요청한 응답 코드
+	ServiceError  string     `json:"service_error,omitempty"`
+	Verdict       string     `json:"verdict"` // ok, platform, application
+	CheckedAt     time.Time  `json:"checked_at"`
+}
+
+var (
+	ingressChecksMu sync.Mutex
+	ingressChecks   = map[string]IngressReachability{} // "namespace/vm" 별 최근 검사 결과
+)
+
+// IngressCheckInterval은 Ingress 도달성 검사 주기를 반환합니다. (INGRESS_CHECK_INTERVAL, 비어 있으면 0 = 사용 안 함)
+func IngressCheckInterval() time.Duration {
+	if interval, err := time.ParseDuration(os.Getenv("INGRESS_CHECK_INTERVAL")); err == nil && interval > 0 {
+		return interval
+	}
+	return 0
+}
+
+// GetIngressReachability는 VM의 최근 도달성 검사 결과를 반환합니다. 검사기가 꺼져 있거나 아직 검사하지 않았으면 nil
+func GetIngressReachability(vm *models.VirtualMachine) *IngressReachability {
+	ingressChecksMu.Lock()
+	defer ingressChecksMu.Unlock()
+
+	result, ok := ingressChecks[vm.Namespace+"/"+vm.Name]
+	if !ok {
+		return nil
+	}
+	return &result
+}
+
+// StartIngressChecker는 주기적으로 실행 중인 VM의 Ingress 호스트를 클러스터 내부에서 검사합니다.
+func (s *K8sService) StartIngressChecker(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			runRecovered("ingress.checker", "*", func() error {
+				return s.checkIngresses()
+			})
+		}
+	}()
+}
+
+func (s *K8sService) checkIngresses() error {
+	vms, err := s.vmService.FetchAllVMs(false)
+	if err != nil {
+		return fmt.Errorf("failed to fetch VMs: %v", err)
+	}
+
+	var (
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, ingressProbeConcurrency)
+		results sync.Map
+	)
+	for i := range vms {
+		vm := &vms[i]
+		if vm.Status != models.VmStatusRunning || vm.DnsHost == "" {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result, err := s.probeIngress(vm)
+			if err != nil {
+				s.log().Warn("ingress checker: failed to probe", "vm", vm.Name, "error", err)
+				return
+			}
+			if result != nil {
+				results.Store(vm.Namespace+"/"+vm.Name, *result)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 실행 중이 아니거나 Ingress가 없는 VM의 결과는 제거
+	checks := map[string]IngressReachability{}
+	results.Range(func(key, value any) bool {
+		checks[key.(string)] = value.(IngressReachability)
+		return true
+	})
+
+	ingressChecksMu.Lock()
+	ingressChecks = checks
+	ingressChecksMu.Unlock()
+
+	return nil
+}
+
+// probeIngress는 VM Ingress의 호스트와 백엔드 Service를 검사합니다. Ingress가 없으면 nil
+func (s *K8sService) probeIngress(vm *models.VirtualMachine) (*IngressReachability, error) {
+	ctx, cancel := context.WithTimeout(s.baseContext(), ingressProbeTimeout)
+	defer cancel()
+
+	ingress, err := s.clientset.NetworkingV1().Ingresses(vm.Namespace).Get(ctx, VMNames(vm.Name).Ingress, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ingress: %v", err)
+	}
+	if len(ingress.Spec.Rules) == 0 || ingress.Spec.Rules[0].Host == "" {
+		return nil, nil
+	}
+
+	rule := ingress.Spec.Rules[0]
+	result := &IngressReachability{
+		Host:       rule.Host,
+		TLSEnabled: len(ingress.Spec.TLS) > 0,
+		CheckedAt:  time.Now(),
+	}
+
+	// 1. DNS
+	if _, err := net.DefaultResolver.LookupHost(ctx, rule.Host); err != nil {
+		result.DNSError = err.Error()
+	} else {
+		result.DNSResolved = true
+	}
+
+	// 2. TLS (인증서 검증 포함)
+	if result.DNSResolved && result.TLSEnabled {
+		probeTLS(rule.Host, result)
+	}
+
+	// 3. Ingress 호스트로 HTTP 요청 (인증서 문제는 TLS 결과로 따로 보고하므로 검증 생략)
+	if result.DNSResolved {
+		scheme := "http"
+		if result.TLSEnabled {
+			scheme = "https"
+		}
+		result.HTTPStatus, err = probeHTTP(scheme + "://" + rule.Host + "/")
+		if err != nil {
+			result.HTTPError = err.Error()
+		}
+	}
+
+	// 4. Ingress를 거치지 않고 백엔드 Service로 직접 요청
+	if rule.HTTP != nil && len(rule.HTTP.Paths) > 0 && rule.HTTP.Paths[0].Backend.Service != nil {
+		backend := rule.HTTP.Paths[0].Backend.Service
+		port := backend.Port.Number
+		if port == 0 {
+			port = 80
+		}
+		host := backend.Name + "." + vm.Namespace + ".svc"
+		result.ServiceStatus, err = probeHTTP("http://" + net.JoinHostPort(host, strconv.Itoa(int(port))) + "/")
+		if err != nil {
+			result.ServiceError = err.Error()
+		}
+	}
+
+	result.Verdict = reachabilityVerdict(result)
+	return result, nil
+}
+
+func probeTLS(host string, result *IngressReachability) {
+	dialer := &net.Dialer{Timeout: ingressProbeTimeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", net.JoinHostPort(host, "443"), &tls.Config{ServerName: host})
+	if err != nil {
+		result.TLSError = err.Error()
+		return
+	}
+	defer conn.Close()
+
+	result.TLSValid = true
+	if certs := conn.ConnectionState().PeerCertificates; len(certs) > 0 {
+		expiresAt := certs[0].NotAfter
+		result.TLSExpiresAt = &expiresAt
+	}
+}
+
+var probeClient = &http.Client{
+	Timeout: ingressProbeTimeout,
+	Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+		DisableKeepAlives: true,
+	},
+	// 리다이렉트는 따라가지 않고 응답 코드 그대로 보고
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+func probeHTTP(url string) (int, error) {
+	resp, err := probeClient.Get(url)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode, nil
+}
+
+// reachabilityVerdict는 검사 결과로 문제의 원인을 판정합니다.
+// Service가 정상 응답하는데 Ingress 경로만 실패하면 플랫폼 문제, Service부터 실패하면 VM 안의 앱 문제로 봅니다.
+func reachabilityVerdict(result *IngressReachability) string {
+	if !result.DNSResolved || (result.TLSEnabled && !result.TLSValid) {
+		return ReachabilityPlatform
+	}
+	if result.ServiceError != "" || result.ServiceStatus >= 500 {
+		return ReachabilityApplication
+	}
+	if result.HTTPError != "" || result.HTTPStatus >= 500 || result.HTTPStatus == http.StatusNotFound && result.ServiceStatus != http.StatusNotFound {
+		return ReachabilityPlatform
+	}
+	return ReachabilityOK
+}
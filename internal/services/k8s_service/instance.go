@@ -0,0 +1,54 @@
+package k8s_service
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	gvrVM  = schema.GroupVersionResource{Group: "kubevirt.io", Version: "v1", Resource: "virtualmachines"}
+	gvrVMI = schema.GroupVersionResource{Group: "kubevirt.io", Version: "v1", Resource: "virtualmachineinstances"}
+)
+
+// VMIInfo는 실행 중인 VirtualMachineInstance에서 관측한 정보입니다.
+type VMIInfo struct {
+	Namespace string
+	Name      string
+	Phase     string    // Running, Scheduling 등
+	NodeName  string    // VM이 실행 중인 노드
+	StartedAt time.Time // VMI 생성 시각 (uptime 계산용)
+}
+
+// ListVMIs는 클러스터 전체의 VMI를 "namespace/name" 키로 반환합니다.
+// 동시 조회는 한 번만 실행되며 반환된 map은 호출자끼리 공유되므로 수정하면 안 됩니다.
+func (s *K8sService) ListVMIs() (map[string]VMIInfo, error) {
+	detached := s.detached()
+	return s.vmiListReads.Do("all", detached.listVMIs)
+}
+
+func (s *K8sService) listVMIs() (map[string]VMIInfo, error) {
+	list, err := s.dynamicClient.Resource(gvrVMI).List(s.baseContext(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list virtual machine instances: %v", err)
+	}
+
+	result := make(map[string]VMIInfo, len(list.Items))
+	for _, item := range list.Items {
+		phase, _, _ := unstructured.NestedString(item.Object, "status", "phase")
+		nodeName, _, _ := unstructured.NestedString(item.Object, "status", "nodeName")
+
+		result[item.GetNamespace()+"/"+item.GetName()] = VMIInfo{
+			Namespace: item.GetNamespace(),
+			Name:      item.GetName(),
+			Phase:     phase,
+			NodeName:  nodeName,
+			StartedAt: item.GetCreationTimestamp().Time,
+		}
+	}
+
+	return result, nil
+}
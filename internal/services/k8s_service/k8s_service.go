@@ -1,27 +1,54 @@
 package k8s_service
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"net"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
-	"sync"
 	"time"
+	"vm-controller/internal/coalesce"
+	"vm-controller/internal/config"
+	"vm-controller/internal/logger"
 	"vm-controller/internal/models"
+	"vm-controller/internal/saga"
+	auditservice "vm-controller/internal/services/audit_service"
+	bundleservice "vm-controller/internal/services/bundle_service"
+	databaseservice "vm-controller/internal/services/database_service"
+	deploymentservice "vm-controller/internal/services/deployment_service"
+	flavorchangeservice "vm-controller/internal/services/flavor_change_service"
+	flavorservice "vm-controller/internal/services/flavor_service"
+	imageservice "vm-controller/internal/services/image_service"
+	nodepoolservice "vm-controller/internal/services/nodepool_service"
+	notificationservice "vm-controller/internal/services/notification_service"
+	operationservice "vm-controller/internal/services/operation_service"
+	reportservice "vm-controller/internal/services/report_service"
+	snapshotservice "vm-controller/internal/services/snapshot_service"
+	userservice "vm-controller/internal/services/user_service"
+	vmeventservice "vm-controller/internal/services/vm_event_service"
 	vmservice "vm-controller/internal/services/vm_service"
+	volumeservice "vm-controller/internal/services/volume_service"
+	"vm-controller/internal/vmstate"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer/yaml"
 	"k8s.io/apimachinery/pkg/types"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
@@ -33,100 +60,216 @@ import (
 // 순환의존성 방지
 type K8sService struct {
 	dynamicClient dynamic.Interface
+	clientset     kubernetes.Interface // 로그 조회 등 subresource 접근용
 	mapper        meta.RESTMapper
+	restConfig    *rest.Config   // subresource 프록시(콘솔 등)용
+	metricsClient rest.Interface // metrics.k8s.io (metrics-server) 조회용
+
+	auditService        *auditservice.AuditService
+	bundleService       *bundleservice.BundleService
+	databaseService     *databaseservice.DatabaseService
+	deploymentService   *deploymentservice.DeploymentService
+	flavorChangeService *flavorchangeservice.FlavorChangeService
+	flavorService       *flavorservice.FlavorService
+	imageService        *imageservice.ImageService
+	nodePoolService     *nodepoolservice.NodePoolService
+	notificationService *notificationservice.NotificationService
+	operationService    *operationservice.OperationService
+	reportService       *reportservice.ReportService
+	snapshotService     *snapshotservice.SnapshotService
+	userService         *userservice.UserService
+	vmEventService      *vmeventservice.VmEventService
+	vmService           *vmservice.VmService
+	volumeService       *volumeservice.VolumeService
+
+	// 클러스터 전체 VMI 목록 조회를 합치고 짧게 캐시 (관리자 화면들이 같은 목록을 동시에 조회)
+	vmiListReads *coalesce.Group[map[string]VMIInfo]
+	// 같은 VM의 동시 사용량 조회를 합치고 짧게 캐시 (metrics-server 값은 수십 초 단위로 갱신됨)
+	vmMetricsReads *coalesce.Group[*VMMetrics]
+
+	asyncWake chan struct{} // RunAsync가 작업을 넣었을 때 대기 중인 워커를 깨움 (StartAsyncWorkers)
+
+	ctx context.Context // WithContext로 묶은 요청 컨텍스트 (기본 인스턴스는 nil)
 }
 
-var (
-	instance *K8sService
-	once     sync.Once
-)
-
 // params for deployment
 type DeploymentParams struct {
 	UserNamespace string
 	NodePort      int32
 }
 
-// 싱글톤 인스턴스 반환 함수
-func GetK8sService() (*K8sService, error) {
-	var err error
-	once.Do(func() {
-		var config *rest.Config
-
-		// 1. Custom Secret Mounting Logic (User Request)
-		// 사용자가 지정한 로직: /mnt/secrets의 토큰과 CA 인증서를 사용하여 Config 생성
-		tokenPath := "/mnt/secrets/token"
-		caPath := "/mnt/secrets/ca.crt"
-
-		if _, errStat := os.Stat(tokenPath); errStat == nil {
-			// API 서버 주소 설정 (기본값: 10.43.0.1)
-			host := os.Getenv("KUBERNETES_SERVICE_HOST")
-			if host == "" {
-				host = "10.43.0.1"
-			}
-			port := os.Getenv("KUBERNETES_SERVICE_PORT")
-			if port == "" {
-				port = "443"
-			}
+// Dependencies는 K8sService가 클러스터 상태를 기록하고 작업을 이어서 실행할 때 쓰는 DB 서비스입니다.
+type Dependencies struct {
+	AuditService        *auditservice.AuditService
+	BundleService       *bundleservice.BundleService
+	DatabaseService     *databaseservice.DatabaseService
+	DeploymentService   *deploymentservice.DeploymentService
+	FlavorChangeService *flavorchangeservice.FlavorChangeService
+	FlavorService       *flavorservice.FlavorService
+	ImageService        *imageservice.ImageService
+	NodePoolService     *nodepoolservice.NodePoolService
+	NotificationService *notificationservice.NotificationService
+	OperationService    *operationservice.OperationService
+	ReportService       *reportservice.ReportService
+	SnapshotService     *snapshotservice.SnapshotService
+	UserService         *userservice.UserService
+	VmEventService      *vmeventservice.VmEventService
+	VmService           *vmservice.VmService
+	VolumeService       *volumeservice.VolumeService
+}
 
-			token, errRead := os.ReadFile(tokenPath)
-			if errRead == nil {
-				config = &rest.Config{
-					Host: "https://" + net.JoinHostPort(host, port),
-					TLSClientConfig: rest.TLSClientConfig{
-						CAFile: caPath,
-					},
-					BearerToken: string(token),
-				}
-				fmt.Printf("Using Custom K8s Config from %s with Host %s\n", tokenPath, config.Host)
-			} else {
-				fmt.Printf("Failed to read token from %s: %v\n", tokenPath, errRead)
-			}
+// NewK8sService는 클러스터 접속 설정을 읽어 새 K8sService를 만듭니다.
+// 서버 시작 시 한 번 만들어 컨트롤러와 백그라운드 루프에 주입합니다.
+func NewK8sService(deps Dependencies) (*K8sService, error) {
+	var err error
+	var config *rest.Config
+
+	// 1. Custom Secret Mounting Logic (User Request)
+	// 사용자가 지정한 로직: /mnt/secrets의 토큰과 CA 인증서를 사용하여 Config 생성
+	tokenPath := "/mnt/secrets/token"
+	caPath := "/mnt/secrets/ca.crt"
+
+	if _, errStat := os.Stat(tokenPath); errStat == nil {
+		// API 서버 주소 설정 (기본값: 10.43.0.1)
+		host := os.Getenv("KUBERNETES_SERVICE_HOST")
+		if host == "" {
+			host = "10.43.0.1"
+		}
+		port := os.Getenv("KUBERNETES_SERVICE_PORT")
+		if port == "" {
+			port = "443"
 		}
 
-		// 2. 만약 Custom Config가 생성되지 않았다면 기존 로직 시도 (InCluster -> fallback)
-		if config == nil {
-			config, err = rest.InClusterConfig()
-			if err != nil {
-				// fallback to standard kubeconfig loading (KUBECONFIG env or ~/.kube/config)
-				loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
-				configOverrides := &clientcmd.ConfigOverrides{}
-				kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
-				config, err = kubeConfig.ClientConfig()
+		token, errRead := os.ReadFile(tokenPath)
+		if errRead == nil {
+			config = &rest.Config{
+				Host: "https://" + net.JoinHostPort(host, port),
+				TLSClientConfig: rest.TLSClientConfig{
+					CAFile: caPath,
+				},
+				BearerToken: string(token),
 			}
+			slog.Info("using custom k8s config", "token_path", tokenPath, "host", config.Host)
+		} else {
+			slog.Warn("failed to read k8s token", "token_path", tokenPath, "error", errRead)
 		}
+	}
 
+	// 2. 만약 Custom Config가 생성되지 않았다면 기존 로직 시도 (InCluster -> fallback)
+	if config == nil {
+		config, err = rest.InClusterConfig()
 		if err != nil {
-			// 마지막으로 fallback 메시지만 남김
-			err = fmt.Errorf("failed to get k8s config: %v", err)
-			return
+			// fallback to standard kubeconfig loading (KUBECONFIG env or ~/.kube/config)
+			loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+			configOverrides := &clientcmd.ConfigOverrides{}
+			kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
+			config, err = kubeConfig.ClientConfig()
 		}
+	}
 
-		// 2. Dynamic Client 생성
-		dynClient, errDyn := dynamic.NewForConfig(config)
-		if errDyn != nil {
-			err = fmt.Errorf("failed to create dynamic client: %v", errDyn)
-			return
-		}
+	if err != nil {
+		// 마지막으로 fallback 메시지만 남김
+		return nil, fmt.Errorf("failed to get k8s config: %v", err)
+	}
 
-		// 3. Discovery Client & Mapper 생성 (GVR 매핑용)
-		dc, errDisc := discovery.NewDiscoveryClientForConfig(config)
-		if errDisc != nil {
-			err = fmt.Errorf("failed to create discovery client: %v", errDisc)
-			return
-		}
-		mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(dc))
+	// 2. Dynamic Client 생성 (일시적인 API 서버 오류는 retry.go에서 재시도)
+	dynClient, errDyn := dynamic.NewForConfig(config)
+	if errDyn != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %v", errDyn)
+	}
 
-		instance = &K8sService{
-			dynamicClient: dynClient,
-			mapper:        mapper,
-		}
-	})
+	// Typed Clientset 생성 (Pod 로그 등 dynamic client로 접근할 수 없는 subresource용)
+	clientset, errCs := kubernetes.NewForConfig(config)
+	if errCs != nil {
+		return nil, fmt.Errorf("failed to create clientset: %v", errCs)
+	}
 
-	if err != nil {
-		return nil, err
+	// 3. Discovery Client & Mapper 생성 (GVR 매핑용)
+	dc, errDisc := discovery.NewDiscoveryClientForConfig(config)
+	if errDisc != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %v", errDisc)
 	}
-	return instance, nil
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(dc))
+
+	// 4. Metrics Client 생성 (metrics.k8s.io/v1beta1, 파드 CPU/메모리 사용량)
+	metricsClient, errMetrics := newMetricsClient(config)
+	if errMetrics != nil {
+		return nil, fmt.Errorf("failed to create metrics client: %v", errMetrics)
+	}
+
+	s := newK8sService(deps)
+	s.dynamicClient = newRetryingDynamicClient(dynClient)
+	s.clientset = clientset
+	s.mapper = mapper
+	s.restConfig = config
+	s.metricsClient = metricsClient
+	return s, nil
+}
+
+// newK8sService는 클러스터 클라이언트 없이 DB 서비스만 연결한 K8sService를 만듭니다. (클라이언트는 NewK8sService가 채움)
+func newK8sService(deps Dependencies) *K8sService {
+	s := &K8sService{
+		auditService:        deps.AuditService,
+		bundleService:       deps.BundleService,
+		databaseService:     deps.DatabaseService,
+		deploymentService:   deps.DeploymentService,
+		flavorChangeService: deps.FlavorChangeService,
+		flavorService:       deps.FlavorService,
+		imageService:        deps.ImageService,
+		nodePoolService:     deps.NodePoolService,
+		notificationService: deps.NotificationService,
+		operationService:    deps.OperationService,
+		reportService:       deps.ReportService,
+		snapshotService:     deps.SnapshotService,
+		userService:         deps.UserService,
+		vmEventService:      deps.VmEventService,
+		vmService:           deps.VmService,
+		volumeService:       deps.VolumeService,
+
+		vmiListReads:   coalesce.New[map[string]VMIInfo]("vmi_list"),
+		vmMetricsReads: coalesce.New[*VMMetrics]("vm_metrics"),
+
+		asyncWake: make(chan struct{}, asyncWorkerCount()),
+	}
+	// VM이 시작/정지되면 VMI 목록이 바뀌므로 캐시를 비움
+	vmstate.OnTransition(func(vmstate.Transition) { s.vmiListReads.Purge() })
+	return s
+}
+
+// WithContext는 API 호출과 상태 대기가 ctx를 따르는 K8sService를 반환합니다.
+// 클라이언트가 연결을 끊거나 라우트 제한 시간이 지나면 진행 중인 호출과 폴링이 중단됩니다.
+// 응답 이후에도 계속되어야 하는 비동기 작업(*Async)에는 주입된 인스턴스를 그대로 사용합니다.
+func (s *K8sService) WithContext(ctx context.Context) *K8sService {
+	bound := *s
+	bound.ctx = ctx
+	return &bound
+}
+
+// WithRequest는 ctx의 요청 ID와 trace id만 물려받고 요청의 취소/제한 시간은 따르지 않는 K8sService를 반환합니다.
+// API 요청에서 백그라운드 작업(RunAsync)을 시작할 때 사용하여, 작업 로그와 기록을 요청과 연결합니다.
+func (s *K8sService) WithRequest(ctx context.Context) *K8sService {
+	return s.WithContext(context.WithoutCancel(ctx))
+}
+
+// detached는 요청이 취소되어도 끝까지 실행되어야 하는 정리 작업(롤백 등)용으로 취소를 따르지 않는 K8sService를 반환합니다.
+func (s *K8sService) detached() *K8sService {
+	if s.ctx == nil {
+		return s
+	}
+	return s.WithContext(context.WithoutCancel(s.ctx))
+}
+
+// baseContext는 WithContext로 묶인 컨텍스트를, 없으면 s.baseContext()를 반환합니다.
+func (s *K8sService) baseContext() context.Context {
+	if s.ctx == nil {
+		return context.Background()
+	}
+	return s.ctx
+}
+
+// log는 요청 ID와 trace id가 붙은 로거입니다. (요청 없이 시작된 백그라운드 작업은 기본 로거)
+func (s *K8sService) log() *slog.Logger {
+	return logger.FromContext(s.baseContext())
 }
 
 func (s *K8sService) CheckConnectivity() (string, error) {
@@ -136,7 +279,7 @@ func (s *K8sService) CheckConnectivity() (string, error) {
 	if err != nil {
 		return "unhealthy", err
 	}
-	_, err = s.dynamicClient.Resource(gvr).List(context.Background(), metav1.ListOptions{Limit: 1})
+	_, err = s.dynamicClient.Resource(gvr).List(s.baseContext(), metav1.ListOptions{Limit: 1})
 	if err != nil {
 		return "unhealthy", err
 	}
@@ -167,8 +310,11 @@ func (s *K8sService) checkInjection(userNamespace, vmName, password, dnsHost, ma
 	if !dns1123Regex.MatchString(userNamespace) {
 		return fmt.Errorf("invalid namespace format: %s (must be DNS-1123 compliant)", userNamespace)
 	}
-	if !dns1123Regex.MatchString(vmName) {
-		return fmt.Errorf("invalid vmName format: %s (must be DNS-1123 compliant)", vmName)
+	if err := ValidateVMName(vmName); err != nil {
+		return err
+	}
+	if err := ValidateDNSHost(dnsHost); err != nil {
+		return err
 	}
 
 	// 4. Password 체크 (보안 및 인젝션 방지)
@@ -219,19 +365,46 @@ type VMInfo struct {
 	Namespace        string
 	Name             string
 	Port             int32
-	Password         string
+	Password         string `json:"-"` // 응답으로 직렬화하지 않음
 	DNSHost          string
+	MacAddress       string
+	Flavor           Flavor
+	Image            Image              // 원본 디스크와 기본 SSH 사용자
+	Networks         []models.VmNetwork // 보조 NIC
+	SSHKeys          []string           // cloud-init ssh_authorized_keys
+	CloudInit        string             // 사용자 cloud-config (템플릿 userdata에 병합)
 	CreatedResources []CreatedResource
 }
 
 // CreateUserVM creates resources defined in yaml-data/client-vm
-func (s *K8sService) CreateUserVM(userNamespace, vmName, password, dnsHost, manifestDir string, vmPort int32) (*VMInfo, error) {
+// 반환하는 에러 메시지에서는 비밀번호를 가립니다. (API 서버 에러가 거부된 값을 그대로 담을 수 있음)
+func (s *K8sService) CreateUserVM(userNamespace, vmName, password, dnsHost, manifestDir, flavorName, imageName string, vmPort int32, networks []models.VmNetwork, sshKeys []string, cloudInit string) (*VMInfo, error) {
+	vmInfo, err := s.createUserVM(userNamespace, vmName, password, dnsHost, manifestDir, flavorName, imageName, vmPort, networks, sshKeys, cloudInit)
+	return vmInfo, logger.RedactError(err, password)
+}
+
+func (s *K8sService) createUserVM(userNamespace, vmName, password, dnsHost, manifestDir, flavorName, imageName string, vmPort int32, networks []models.VmNetwork, sshKeys []string, cloudInit string) (*VMInfo, error) {
 	// manifestDir := "yaml-data/client-vm" // 실행 위치 기준
 
 	// Yaml에 그대로 넣지만, Injection검사를 시행.
 	if err := s.checkInjection(userNamespace, vmName, password, dnsHost, manifestDir, vmPort); err != nil {
 		return nil, err
 	}
+	if err := checkSSHKeys(sshKeys); err != nil {
+		return nil, err
+	}
+	if _, err := ParseCloudInit(cloudInit); err != nil {
+		return nil, err
+	}
+
+	flavor, err := GetFlavor(s.flavorService, flavorName)
+	if err != nil {
+		return nil, err
+	}
+	image, err := GetImage(s.imageService, imageName)
+	if err != nil {
+		return nil, err
+	}
 
 	vmInfo := &VMInfo{
 		Namespace: userNamespace,
@@ -239,27 +412,19 @@ func (s *K8sService) CreateUserVM(userNamespace, vmName, password, dnsHost, mani
 		Port:      vmPort,
 		Password:  password,
 		DNSHost:   dnsHost,
+
+		MacAddress: GenerateMACAddress(),
+		Flavor:     flavor,
+		Image:      image,
+		Networks:   networks,
+		SSHKeys:    sshKeys,
+		CloudInit:  cloudInit,
 	}
 
-	// 롤백을 위한 성공 여부 플래그
-	var success bool
-	// 이 함수에서 생성한 모든 리소스를 추적 (init + vm)
-	var allCreatedResources []CreatedResource
-
-	// defer를 사용하여 작업 실패 시 롤백(삭제) 수행
-	defer func() {
-		if !success {
-			fmt.Println("CreateUserVM failed. Rolling back created resources...")
-			// 생성의 역순으로 삭제
-			for i := len(allCreatedResources) - 1; i >= 0; i-- {
-				res := allCreatedResources[i]
-				fmt.Printf("Rolling back resource: %s %s/%s\n", res.Kind, res.Namespace, res.Name)
-				if errRaw := s.deleteResource(res); errRaw != nil {
-					fmt.Printf("Failed to delete resource %s %s/%s during rollback: %v\n", res.Kind, res.Namespace, res.Name, errRaw)
-				}
-			}
-		}
-	}()
+	// 이 함수에서 생성한 모든 리소스(init + vm)의 삭제를 보상 단계로 등록하여, 실패 시 생성의 역순으로 삭제
+	// (요청이 취소되어 실패한 경우에도 끝까지 정리)
+	sg := saga.New("vm.create", "namespace", userNamespace, "vm", vmName, "request_id", logger.RequestIDFrom(s.baseContext()))
+	defer sg.Rollback()
 
 	// 1. Client Init Resources (yaml-data/client-init) - 이미 존재하면 무시(Skip)
 	// manifestDir가 "yaml-data/client-vm"이라면 상위 폴더의 client-init을 찾음
@@ -271,34 +436,30 @@ func (s *K8sService) CreateUserVM(userNamespace, vmName, password, dnsHost, mani
 		initDir = "yaml-data/client-init"
 	}
 
-	initReplacements := map[string]string{
-		"{{NAMESPACE}}": userNamespace,
-	}
-
-	initCreated, err := s.applyManifests(initDir, initReplacements, userNamespace, true)
+	// 같은 사용자의 동시 생성은 초기화를 직렬화하며, 초기화가 기록된 네임스페이스는 적용하지 않음
+	initCreated, err := s.initUserNamespace(initDir, userNamespace)
+	s.compensateResources(sg, initCreated)
 	if err != nil {
 		// init 과정 실패 시에도 롤백 발동 (여기까지 생성된 것 삭제)
-		return nil, fmt.Errorf("failed to apply client-init manifests: %v", err)
+		return nil, err
 	}
-	allCreatedResources = append(allCreatedResources, initCreated...)
 
 	// 2. Client VM Resources (yaml-data/client-vm)
-	vmReplacements := map[string]string{
-		"{{NAMESPACE}}": userNamespace,
-		"{{NODEPORT}}":  fmt.Sprintf("%d", vmPort),
-		"{{VM_NAME}}":   vmName,
-		"{{DNS_HOST}}":  dnsHost,
-		"{{PASSWORD}}":  password,
+	s.log().Debug("applying manifests", "dir", manifestDir)
+	vmObjs, err := s.renderVMManifests(manifestDir, vmInfo, s.usesRunStrategy())
+	if err != nil {
+		return nil, fmt.Errorf("failed to render client-vm manifests: %v", err)
 	}
 
-	vmCreated, err := s.applyManifests(manifestDir, vmReplacements, userNamespace, false)
+	vmCreated, err := s.applyObjects(vmObjs, false)
+	s.compensateResources(sg, vmCreated)
 	if err != nil {
-		return nil, fmt.Errorf("failed to apply client-vm manifests: %v", err)
+		return nil, fmt.Errorf("failed to apply client-vm manifests: %w", err)
 	}
-	allCreatedResources = append(allCreatedResources, vmCreated...)
+	s.recordVMPatch(vmName, "create", fmt.Sprintf("applied %d resources from %s", len(vmCreated), manifestDir))
 
 	// 성공적으로 완료되었음을 표시 (롤백 방지)
-	success = true
+	sg.Complete()
 	// 최종 VMInfo에는 VM 관련 리소스만 넣을지, Init 포함할지 결정.
 	// 사용자의 요청 "적용하는데 성공한 obj 들을 배열에 담아둿다가..."는 롤백 로직을 위한 것이었음.
 	// 리턴값은 VM 관련 리소스 정보로 채움.
@@ -307,16 +468,64 @@ func (s *K8sService) CreateUserVM(userNamespace, vmName, password, dnsHost, mani
 	return vmInfo, nil
 }
 
-// applyManifests iterates over yamls in a directory, applies replacements, and creates resources.
-// ignoreExists: if true, "already exists" error is ignored and resource is NOT returned as created.
-func (s *K8sService) applyManifests(dir string, replacements map[string]string, defaultNamespace string, ignoreExists bool) ([]CreatedResource, error) {
-	fmt.Println("Applying manifests from directory:", dir)
+// RollbackUserVM은 CreateUserVM이 만든 VM 리소스를 삭제합니다.
+// 리소스 생성 이후 단계(DB 갱신 등)가 실패했을 때 호출하며, 네임스페이스 초기화 리소스는 남겨둡니다.
+func (s *K8sService) RollbackUserVM(vmInfo *VMInfo) error {
+	sg := saga.New("vm.rollback", "namespace", vmInfo.Namespace, "vm", vmInfo.Name, "request_id", logger.RequestIDFrom(s.baseContext()))
+	s.compensateResources(sg, vmInfo.CreatedResources)
+	return errors.Join(sg.Rollback()...)
+}
+
+// compensateResources는 생성한 리소스마다 삭제를 보상 단계로 등록합니다. 요청이 취소되어도 삭제는 끝까지 실행됩니다.
+func (s *K8sService) compensateResources(sg *saga.Saga, resources []CreatedResource) {
+	for _, res := range resources {
+		sg.Compensate(fmt.Sprintf("delete %s %s/%s", res.Kind, res.Namespace, res.Name), func() error {
+			return s.detached().deleteResource(res)
+		})
+	}
+}
+
+// splitYAMLDocuments는 여러 문서로 된 YAML을 문서 단위 JSON으로 나눕니다.
+// BOM, CRLF 줄바꿈, 파일 맨 앞의 구분자, "--- # 주석" 형태의 구분자를 처리하며 빈 문서나 주석만 있는 문서는 건너뜁니다.
+func splitYAMLDocuments(text string) ([][]byte, error) {
+	text = strings.TrimPrefix(text, "\ufeff")
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(strings.NewReader(text)))
+
+	var docs [][]byte
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// 공백(탭 포함)만 있는 문서는 YAML 파서가 거부하므로 변환 전에 건너뜀
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		doc, err = utilyaml.ToJSON(doc)
+		if err != nil {
+			return nil, err
+		}
+		if trimmed := bytes.TrimSpace(doc); len(trimmed) == 0 || string(trimmed) == "null" {
+			continue
+		}
+
+		docs = append(docs, doc)
+	}
+}
+
+// renderManifests는 dir의 템플릿에 값을 치환하고 객체로 디코딩합니다. (클러스터에는 적용하지 않음)
+func renderManifests(dir string, replacements map[string]string, defaultNamespace string) ([]*unstructured.Unstructured, error) {
 	files, err := os.ReadDir(dir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read directory %s: %v", dir, err)
 	}
 
-	var created []CreatedResource
+	var objs []*unstructured.Unstructured
 	decUnstructured := yaml.NewDecodingSerializer(unstructured.UnstructuredJSONScheme)
 
 	for _, file := range files {
@@ -327,7 +536,7 @@ func (s *K8sService) applyManifests(dir string, replacements map[string]string,
 		path := filepath.Join(dir, file.Name())
 		content, err := os.ReadFile(path)
 		if err != nil {
-			return created, fmt.Errorf("failed to read file %s: %v", file.Name(), err)
+			return nil, fmt.Errorf("failed to read file %s: %v", file.Name(), err)
 		}
 
 		text := string(content)
@@ -335,16 +544,14 @@ func (s *K8sService) applyManifests(dir string, replacements map[string]string,
 			text = strings.ReplaceAll(text, k, v)
 		}
 
-		docs := strings.Split(text, "\n---\n")
+		docs, err := splitYAMLDocuments(text)
+		if err != nil {
+			return nil, fmt.Errorf("failed to split yaml in %s: %v", file.Name(), err)
+		}
 		for _, doc := range docs {
-			if strings.TrimSpace(doc) == "" {
-				continue
-			}
-
 			obj := &unstructured.Unstructured{}
-			_, gvk, err := decUnstructured.Decode([]byte(doc), nil, obj)
-			if err != nil {
-				return created, fmt.Errorf("failed to decode yaml in %s: %v", file.Name(), err)
+			if _, _, err := decUnstructured.Decode(doc, nil, obj); err != nil {
+				return nil, fmt.Errorf("failed to decode yaml in %s: %v", file.Name(), err)
 			}
 
 			// Namespace 설정 (없는 경우 defaultNamespace 주입)
@@ -352,56 +559,100 @@ func (s *K8sService) applyManifests(dir string, replacements map[string]string,
 				obj.SetNamespace(defaultNamespace)
 			}
 
-			// GVR 매핑
-			mapping, err := s.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
-			if err != nil {
-				return created, fmt.Errorf("failed to find mapping for %s: %v", gvk.String(), err)
-			}
+			objs = append(objs, obj)
+		}
+	}
+	return objs, nil
+}
+
+// applyManifests iterates over yamls in a directory, applies replacements, and creates resources.
+// ignoreExists: if true, "already exists" error is ignored and resource is NOT returned as created.
+// Otherwise an existing resource is adopted (returned as created) only if it carries the same ownership labels.
+func (s *K8sService) applyManifests(dir string, replacements map[string]string, defaultNamespace string, ignoreExists bool) ([]CreatedResource, error) {
+	s.log().Debug("applying manifests", "dir", dir)
+	objs, err := renderManifests(dir, replacements, defaultNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.applyObjects(objs, ignoreExists)
+}
 
-			var dri dynamic.ResourceInterface
-			if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
-				dri = s.dynamicClient.Resource(mapping.Resource).Namespace(obj.GetNamespace())
-			} else {
-				dri = s.dynamicClient.Resource(mapping.Resource)
+// applyObjects는 렌더링된 객체를 순서대로 생성합니다. ignoreExists 동작은 applyManifests와 같습니다.
+func (s *K8sService) applyObjects(objs []*unstructured.Unstructured, ignoreExists bool) ([]CreatedResource, error) {
+	var created []CreatedResource
+
+	for _, obj := range objs {
+		gvk := obj.GroupVersionKind()
+		setOwnershipLabels(obj, "")
+
+		// GVR 매핑
+		mapping, err := s.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return created, fmt.Errorf("failed to find mapping for %s: %v", gvk.String(), err)
+		}
+
+		var dri dynamic.ResourceInterface
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			dri = s.dynamicClient.Resource(mapping.Resource).Namespace(obj.GetNamespace())
+		} else {
+			dri = s.dynamicClient.Resource(mapping.Resource)
+		}
+
+		// Create Resource
+		createdObj, err := dri.Create(s.baseContext(), obj, metav1.CreateOptions{})
+		if apierrors.IsAlreadyExists(err) {
+			if ignoreExists {
+				// 이미 존재하면 무시하고 넘어감 (롤백 대상 아님)
+				s.log().Debug("resource already exists, skipping", "kind", gvk.Kind, "namespace", obj.GetNamespace(), "name", obj.GetName())
+				continue
 			}
 
-			// Create Resource
-			createdObj, err := dri.Create(context.Background(), obj, metav1.CreateOptions{})
-			if err != nil {
-				if strings.Contains(err.Error(), "already exists") {
-					if ignoreExists {
-						// 이미 존재하면 무시하고 넘어감 (롤백 대상 아님)
-						fmt.Printf("Resource %s %s/%s already exists, skipping.\n", gvk.Kind, obj.GetNamespace(), obj.GetName())
-						continue
-					} else {
-						// VM 생성 시 중복은 에러로 처리하거나, 여기서도 로그만 찍고 넘어갈 수 있음.
-						// 기존 로직은 로그 찍고 넘어가는 것이었음. ("already exists, skipping")
-						// 하지만 "원자성"을 위해 새로 생성하려던 것이 이미 있으면 실패로 보는게 맞을 수도 있고,
-						// 재시도 관점에서는 성공으로 볼 수도 있음.
-						// 여기서는 기존 로직(로그 찍고 스킵)을 유지하되, Created 목록에는 넣지 않음 -> 롤백 안함.
-						fmt.Printf("Resource %s %s/%s already exists, skipping (not tracking for rollback).\n", gvk.Kind, obj.GetNamespace(), obj.GetName())
-						continue
-					}
-				}
-				return created, fmt.Errorf("failed to create resource %s: %v", gvk.Kind, err)
+			// 이전 시도에서 만들어진 같은 소유자의 리소스는 이번 생성 결과로 인수(adopt)하여 실패 시 함께 롤백
+			// 소유자가 다르면 남의 리소스를 덮어쓰거나 롤백으로 삭제하지 않도록 실패 처리
+			existing, errGet := dri.Get(s.baseContext(), obj.GetName(), metav1.GetOptions{})
+			if errGet != nil {
+				return created, fmt.Errorf("failed to get existing resource %s %s/%s: %v", gvk.Kind, obj.GetNamespace(), obj.GetName(), errGet)
+			}
+			if err := checkOwnership(existing, obj); err != nil {
+				return created, err
 			}
 
-			fmt.Printf("Successfully created %s: %s\n", gvk.Kind, createdObj.GetName())
-			created = append(created, CreatedResource{
-				Group:     gvk.Group,
-				Version:   gvk.Version,
-				Kind:      gvk.Kind,
-				Name:      createdObj.GetName(),
-				Namespace: createdObj.GetNamespace(),
-				UID:       createdObj.GetUID(),
-			})
+			s.log().Debug("resource already exists with matching ownership, adopting", "kind", gvk.Kind, "namespace", obj.GetNamespace(), "name", obj.GetName())
+			createdObj = existing
+			err = nil
 		}
+		if err != nil {
+			return created, fmt.Errorf("failed to create resource %s: %v", gvk.Kind, err)
+		}
+
+		s.log().Debug("resource created", "kind", gvk.Kind, "namespace", createdObj.GetNamespace(), "name", createdObj.GetName())
+		created = append(created, CreatedResource{
+			Group:     gvk.Group,
+			Version:   gvk.Version,
+			Kind:      gvk.Kind,
+			Name:      createdObj.GetName(),
+			Namespace: createdObj.GetNamespace(),
+			UID:       createdObj.GetUID(),
+		})
 	}
 	return created, nil
 }
 
+// ensureUserNamespace는 사용자 네임스페이스와 기본 정책(client-init)이 존재하도록 보장합니다.
+// 이미 존재하는 리소스는 건너뛰므로 여러 번 호출해도 안전합니다.
+func (s *K8sService) ensureUserNamespace(userNamespace string) error {
+	_, err := s.initUserNamespace(filepath.Join("yaml-data", "client-init"), userNamespace)
+	return err
+}
+
 // deleteResource deletes a specific resource
 func (s *K8sService) deleteResource(res CreatedResource) error {
+	return s.deleteResourceWithPolicy(nil, res)
+}
+
+// deleteResourceWithPolicy는 policy가 있으면 리소스 종류별 설정 대신 그 삭제 옵션으로 삭제합니다.
+func (s *K8sService) deleteResourceWithPolicy(policy *config.DeletePolicy, res CreatedResource) error {
 	gvk := schema.GroupVersionKind{
 		Group:   res.Group,
 		Version: res.Version,
@@ -419,24 +670,35 @@ func (s *K8sService) deleteResource(res CreatedResource) error {
 		dri = s.dynamicClient.Resource(mapping.Resource)
 	}
 
-	// 백그라운드 삭제 (즉시 반환하지 않고 K8s가 알아서 GC하도록)
-	deletePolicy := metav1.DeletePropagationBackground
-	return dri.Delete(context.Background(), res.Name, metav1.DeleteOptions{
-		PropagationPolicy: &deletePolicy,
-	})
+	// 전파 정책/유예 시간은 리소스 종류별 설정을 따름 (기본: 백그라운드 삭제, K8s가 소유 리소스를 GC)
+	err = dri.Delete(s.baseContext(), res.Name, deleteOptions(res.Kind, policy))
+
+	// 이미 삭제된 리소스는 성공으로 간주 (삭제 재시도 시 멱등성 보장)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
 }
 
 func (s *K8sService) DeleteVM(vm *models.VirtualMachine) error {
-	err := vmservice.GetVmService().DeleteVm(vm.Name)
+	return s.DeleteVMWithPolicy(vm, nil)
+}
+
+// DeleteVMWithPolicy는 VM 리소스를 policy의 삭제 옵션으로 삭제합니다. (nil이면 리소스 종류별 설정)
+// 관리자가 종료되지 않는 VM을 강제 삭제할 때 사용하며, 추가 디스크와 스냅샷은 항상 설정을 따릅니다.
+func (s *K8sService) DeleteVMWithPolicy(vm *models.VirtualMachine, policy *config.DeletePolicy) error {
+	err := s.vmService.DeleteVm(vm.Name)
 	if err != nil {
 		return err
 	}
 
+	names := VMNames(vm.Name)
+
 	// VM 리소스 삭제
-	err = s.deleteResource(CreatedResource{
+	err = s.deleteResourceWithPolicy(policy, CreatedResource{
 		Version:   "v1",
 		Kind:      "Service",
-		Name:      "vps-access-" + vm.Name,
+		Name:      names.SSHService,
 		Namespace: vm.Namespace,
 	})
 
@@ -444,11 +706,11 @@ func (s *K8sService) DeleteVM(vm *models.VirtualMachine) error {
 		return err
 	}
 
-	err = s.deleteResource(CreatedResource{
+	err = s.deleteResourceWithPolicy(policy, CreatedResource{
 		Group:     "networking.k8s.io",
 		Version:   "v1",
 		Kind:      "Ingress",
-		Name:      "vm-ingress-" + vm.Name,
+		Name:      names.Ingress,
 		Namespace: vm.Namespace,
 	})
 
@@ -456,11 +718,11 @@ func (s *K8sService) DeleteVM(vm *models.VirtualMachine) error {
 		return err
 	}
 
-	err = s.deleteResource(CreatedResource{
+	err = s.deleteResourceWithPolicy(policy, CreatedResource{
 		Group:     "kubevirt.io",
 		Version:   "v1",
 		Kind:      "VirtualMachine",
-		Name:      vm.Name,
+		Name:      names.VirtualMachine,
 		Namespace: vm.Namespace,
 	})
 
@@ -468,10 +730,10 @@ func (s *K8sService) DeleteVM(vm *models.VirtualMachine) error {
 		return err
 	}
 
-	err = s.deleteResource(CreatedResource{
+	err = s.deleteResourceWithPolicy(policy, CreatedResource{
 		Version:   "v1",
 		Kind:      "Secret",
-		Name:      vm.Name + "-cloud-init-userdata",
+		Name:      names.CloudInitSecret,
 		Namespace: vm.Namespace,
 	})
 
@@ -479,11 +741,11 @@ func (s *K8sService) DeleteVM(vm *models.VirtualMachine) error {
 		return err
 	}
 
-	err = s.deleteResource(CreatedResource{
+	err = s.deleteResourceWithPolicy(policy, CreatedResource{
 		Group:     "cdi.kubevirt.io",
 		Version:   "v1beta1",
 		Kind:      "DataVolume",
-		Name:      vm.Name + "-disk",
+		Name:      names.Disk,
 		Namespace: vm.Namespace,
 	})
 
@@ -491,10 +753,10 @@ func (s *K8sService) DeleteVM(vm *models.VirtualMachine) error {
 		return err
 	}
 
-	err = s.deleteResource(CreatedResource{
+	err = s.deleteResourceWithPolicy(policy, CreatedResource{
 		Version:   "v1",
 		Kind:      "Service",
-		Name:      "vps-web-" + vm.Name,
+		Name:      names.WebService,
 		Namespace: vm.Namespace,
 	})
 
@@ -502,24 +764,48 @@ func (s *K8sService) DeleteVM(vm *models.VirtualMachine) error {
 		return err
 	}
 
-	return nil
+	// 추가 디스크와 스냅샷은 VM과 owner 관계가 없으므로 직접 삭제
+	if err := s.deleteVMVolumes(vm); err != nil {
+		return err
+	}
+	if err := s.deleteVMSnapshots(vm); err != nil {
+		return err
+	}
+
+	s.recordVMPatch(vm.Name, "delete", "deleted VM resources")
+
+	// 모든 리소스 삭제 완료: Deleted
+	return s.vmService.MarkVmDeleted(vm.Name)
 }
 
+// waitForVMStatus가 제한 시간 안에 원하는 상태를 관측하지 못함
+var errVMStatusTimeout = errors.New("timeout waiting for VM status")
+
 // waitForVMStatus는 VM의 상태가 원하는 상태(desiredStatus)가 될 때까지 5초 간격으로 폴링합니다.
 // 최대 1분간 대기하며, 시간 내에 상태가 변경되지 않으면 타임아웃 에러를 반환합니다.
 func (s *K8sService) waitForVMStatus(namespace, name, desiredStatus string) error {
-	ctx := context.Background()
+	return s.waitForVMStatusTimeout(namespace, name, desiredStatus, 1*time.Minute)
+}
+
+// waitForVMStatusTimeout은 제한 시간을 지정하여 VM 상태를 기다립니다. 시간 초과 시 errVMStatusTimeout을 감싸 반환합니다.
+func (s *K8sService) waitForVMStatusTimeout(namespace, name, desiredStatus string, wait time.Duration) error {
+	ctx := s.baseContext()
 	gvrVM := schema.GroupVersionResource{Group: "kubevirt.io", Version: "v1", Resource: "virtualmachines"}
 
-	// 1분 타임아웃 설정
-	timeout := time.After(1 * time.Minute)
+	timeout := time.After(wait)
 	ticker := time.NewTicker(5 * time.Second)
 	defer ticker.Stop()
 
+	// 관측된 상태가 바뀔 때만 이벤트로 기록
+	lastObserved := ""
+
 	for {
 		select {
 		case <-timeout:
-			return fmt.Errorf("timeout waiting for VM status to become %s", desiredStatus)
+			return fmt.Errorf("%w to become %s", errVMStatusTimeout, desiredStatus)
+		case <-ctx.Done():
+			// 요청이 취소되면 더 기다리지 않음 (WithContext)
+			return fmt.Errorf("stopped waiting for VM to become %s: %w", desiredStatus, ctx.Err())
 		case <-ticker.C:
 			// VM 리소스 조회
 			vmObj, err := s.dynamicClient.Resource(gvrVM).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
@@ -535,6 +821,15 @@ func (s *K8sService) waitForVMStatus(namespace, name, desiredStatus string) erro
 				continue
 			}
 
+			if status != lastObserved {
+				lastObserved = status
+				s.vmEventService.Record(models.VmEvent{
+					VmName:   name,
+					Type:     models.VmEventStatusObserved,
+					ToStatus: status,
+				})
+			}
+
 			if strings.EqualFold(status, desiredStatus) {
 				return nil
 			}
@@ -546,52 +841,72 @@ func (s *K8sService) waitForVMStatus(namespace, name, desiredStatus string) erro
 // 1. DB의 VM 상태를 'Stopping'으로 업데이트합니다.
 // 2. K8s 상의 VirtualMachine 리소스만 삭제합니다.
 // 3. 삭제가 완료되면 DB의 VM 상태를 'Stopped'로 업데이트합니다.
+// graceful 모드에서는 게스트 ACPI 종료를 VM_STOP_TIMEOUT 동안 기다린 뒤 강제 종료합니다.
 func (s *K8sService) StopVM(vm *models.VirtualMachine) error {
-	ctx := context.Background()
+	ctx := s.baseContext()
 
 	// 1. 상태 업데이트: Stopping
-	if err := vmservice.GetVmService().UpdateVmStatus(vm.Name, models.VmStatusStopping); err != nil {
-		return fmt.Errorf("failed to update VM status to Stopping: %v", err)
+	if err := s.vmService.UpdateVmStatus(vm.Name, models.VmStatusStopping); err != nil {
+		return fmt.Errorf("failed to update VM status to Stopping: %w", err)
 	}
 
-	// 2. Spec Patch: running = false
+	// 2. Spec Patch: running = false (KubeVirt 버전에 따라 runStrategy: Halted)
 	gvrVM := schema.GroupVersionResource{Group: "kubevirt.io", Version: "v1", Resource: "virtualmachines"}
-	data := []byte(`{"spec":{"running":false}}`)
+	data := s.runStatePatch(false)
 	_, err := s.dynamicClient.Resource(gvrVM).Namespace(vm.Namespace).Patch(
 		ctx, vm.Name, types.MergePatchType, data, metav1.PatchOptions{})
 
 	if err != nil {
 		return fmt.Errorf("failed to patch VM running state: %v", err)
 	}
+	mode := StopMode()
+	s.recordVMPatch(vm.Name, "stop."+mode, string(data))
 
 	// 3. Watch: Stopped 상태 대기
-	// 5초 간격으로 최대 1분동안 확인
-	if err := s.waitForVMStatus(vm.Namespace, vm.Name, "Stopped"); err != nil {
+	// force 모드는 바로 인스턴스를 종료하고 최대 1분, graceful 모드는 게스트 종료를 VM_STOP_TIMEOUT 동안 확인
+	wait := StopTimeout()
+	if mode == StopModeForce {
+		if err := s.forceStopVM(vm, "stop mode force"); err != nil {
+			return err
+		}
+		wait = 1 * time.Minute
+	}
+
+	err = s.waitForVMStatusTimeout(vm.Namespace, vm.Name, "Stopped", wait)
+	if err != nil && mode == StopModeGraceful && errors.Is(err, errVMStatusTimeout) {
+		// 게스트가 제한 시간 안에 종료되지 않음 → 강제 종료
+		if err := s.forceStopVM(vm, "graceful shutdown timed out after "+wait.String()); err != nil {
+			return err
+		}
+		err = s.waitForVMStatus(vm.Namespace, vm.Name, "Stopped")
+	}
+	if err != nil {
 		return fmt.Errorf("failed to wait for VM to stop: %v", err)
 	}
 
 	// 4. 상태 업데이트: Stopped
-	if err := vmservice.GetVmService().UpdateVmStatus(vm.Name, models.VmStatusStopped); err != nil {
-		return fmt.Errorf("failed to update VM status to Stopped: %v", err)
+	if err := s.vmService.UpdateVmStatus(vm.Name, models.VmStatusStopped); err != nil {
+		return fmt.Errorf("failed to update VM status to Stopped: %w", err)
 	}
 
 	return nil
 }
 
 // StartVM은 VM을 시작(재시작)합니다.
-// 1. VM Spec을 Patch하여 running=true로 설정합니다.
+// 1. VM Spec을 Patch하여 running=true(또는 runStrategy)로 설정합니다.
 // 2. Watch를 통해 VM이 Running 상태가 될 때까지 대기합니다.
 // 3. 성공 시 DB의 VM 상태를 Running으로 업데이트합니다.
 func (s *K8sService) StartVM(vm *models.VirtualMachine) error {
-	ctx := context.Background()
+	ctx := s.baseContext()
 
-	// 1. Spec Patch: running = true
+	// 1. Spec Patch: running = true (KubeVirt 버전에 따라 runStrategy: Always)
 	gvrVM := schema.GroupVersionResource{Group: "kubevirt.io", Version: "v1", Resource: "virtualmachines"}
-	patchData := []byte(`{"spec": {"running": true}}`)
+	patchData := s.runStatePatch(true)
 	_, err := s.dynamicClient.Resource(gvrVM).Namespace(vm.Namespace).Patch(ctx, vm.Name, types.MergePatchType, patchData, metav1.PatchOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to patch VM running state: %v", err)
 	}
+	s.recordVMPatch(vm.Name, "start", string(patchData))
 
 	// 2. Watch: Running 상태 대기
 	// 5초 간격으로 최대 1분동안 확인
@@ -600,9 +915,19 @@ func (s *K8sService) StartVM(vm *models.VirtualMachine) error {
 	}
 
 	// 3. DB Status Update: Running
-	if err := vmservice.GetVmService().UpdateVmStatus(vm.Name, models.VmStatusRunning); err != nil {
-		return fmt.Errorf("failed to update VM status to Running: %v", err)
+	if err := s.vmService.UpdateVmStatus(vm.Name, models.VmStatusRunning); err != nil {
+		return fmt.Errorf("failed to update VM status to Running: %w", err)
 	}
 
 	return nil
 }
+
+// recordVMPatch는 클러스터에 변경을 전송했음을 VM 이벤트 스트림에 기록합니다.
+func (s *K8sService) recordVMPatch(vmName, operation, detail string) {
+	s.vmEventService.Record(models.VmEvent{
+		VmName:    vmName,
+		Type:      models.VmEventPatchSent,
+		Operation: operation,
+		Detail:    detail,
+	})
+}
@@ -0,0 +1,135 @@
+package k8s_service
+
+import (
+	"encoding/json"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestSplitYAMLDocuments(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string // 문서별 metadata.name
+	}{
+		{
+			name:  "single document",
+			input: "kind: Service\nmetadata:\n  name: a\n",
+			want:  []string{"a"},
+		},
+		{
+			name:  "crlf line endings",
+			input: "kind: Service\r\nmetadata:\r\n  name: a\r\n---\r\nkind: Service\r\nmetadata:\r\n  name: b\r\n",
+			want:  []string{"a", "b"},
+		},
+		{
+			name:  "leading separator",
+			input: "---\nkind: Service\nmetadata:\n  name: a\n",
+			want:  []string{"a"},
+		},
+		{
+			name:  "separator with comment",
+			input: "kind: Service\nmetadata:\n  name: a\n--- # second\nkind: Service\nmetadata:\n  name: b\n",
+			want:  []string{"a", "b"},
+		},
+		{
+			name:  "empty and whitespace-only documents",
+			input: "---\n---\n   \n\t\n---\nkind: Service\nmetadata:\n  name: a\n---\n\n---\n",
+			want:  []string{"a"},
+		},
+		{
+			name:  "comment-only document",
+			input: "# header\n---\nkind: Service\nmetadata:\n  name: a\n",
+			want:  []string{"a"},
+		},
+		{
+			name:  "byte order mark",
+			input: "\ufeffkind: Service\nmetadata:\n  name: a\n---\nkind: Service\nmetadata:\n  name: b\n",
+			want:  []string{"a", "b"},
+		},
+		{
+			name:  "trailing separator",
+			input: "kind: Service\nmetadata:\n  name: a\n---\n",
+			want:  []string{"a"},
+		},
+		{
+			name:  "separator inside block scalar",
+			input: "kind: ConfigMap\nmetadata:\n  name: a\ndata:\n  script: |\n    echo ---\n    echo done\n",
+			want:  []string{"a"},
+		},
+		{
+			name:  "empty input",
+			input: "",
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs, err := splitYAMLDocuments(tt.input)
+			if err != nil {
+				t.Fatalf("splitYAMLDocuments() error = %v", err)
+			}
+			if len(docs) != len(tt.want) {
+				t.Fatalf("got %d documents, want %d: %q", len(docs), len(tt.want), docs)
+			}
+
+			for i, doc := range docs {
+				var obj struct {
+					Metadata struct {
+						Name string `json:"name"`
+					} `json:"metadata"`
+				}
+				if err := json.Unmarshal(doc, &obj); err != nil {
+					t.Fatalf("document %d is not JSON: %v (%s)", i, err, doc)
+				}
+				if obj.Metadata.Name != tt.want[i] {
+					t.Errorf("document %d name = %q, want %q", i, obj.Metadata.Name, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSplitYAMLDocumentsInvalid(t *testing.T) {
+	if _, err := splitYAMLDocuments("kind: Service\nmetadata: [unclosed\n"); err == nil {
+		t.Fatal("expected an error for malformed YAML")
+	}
+}
+
+func TestUserVMSpecFrom(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":        "lab",
+			"namespace":   "user-ns",
+			"annotations": map[string]interface{}{userVMApprovalAnnotation: "42"},
+		},
+		"spec": map[string]interface{}{
+			"image":             "ubuntu-22.04",
+			"flavor":            "premium",
+			"hostPrefix":        "lab",
+			"passwordSecretRef": map[string]interface{}{"name": "lab-password"},
+		},
+	}}
+
+	spec := userVMSpecFrom(obj)
+	if spec.Namespace != "user-ns" || spec.Name != "lab" || spec.Image != "ubuntu-22.04" || spec.Flavor != "premium" || spec.HostPrefix != "lab" {
+		t.Errorf("spec = %+v", spec)
+	}
+	// running과 secret key를 생략하면 기본값
+	if !spec.Running || spec.PasswordSecret != "lab-password" || spec.PasswordSecretKey != userVMPasswordKey {
+		t.Errorf("running = %v, secret = %s/%s; want true, lab-password/%s", spec.Running, spec.PasswordSecret, spec.PasswordSecretKey, userVMPasswordKey)
+	}
+	if spec.ApprovalID != 42 {
+		t.Errorf("approval id = %d, want 42", spec.ApprovalID)
+	}
+
+	// 잘못된 승인 어노테이션은 무시하고, 명시한 running: false는 그대로 사용
+	obj.SetAnnotations(map[string]string{userVMApprovalAnnotation: "not-a-number"})
+	unstructured.SetNestedField(obj.Object, false, "spec", "running")
+	spec = userVMSpecFrom(obj)
+	if spec.ApprovalID != 0 || spec.Running {
+		t.Errorf("approval id = %d, running = %v; want 0, false", spec.ApprovalID, spec.Running)
+	}
+}
@@ -0,0 +1,309 @@
+// Package k8sfake는 클러스터 없이 동작하는 메모리 기반 K8sProvisioner 구현입니다.
+package k8sfake
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+	"vm-controller/internal/config"
+	"vm-controller/internal/models"
+	"vm-controller/internal/services/k8s_service"
+)
+
+// 실패를 주입할 수 있는 작업 이름 (Provisioner.Fail 키)
+const (
+	OpCreate   = "create"
+	OpRollback = "rollback"
+	OpCapacity = "capacity"
+	OpStart    = "start"
+	OpStop     = "stop"
+	OpRestart  = "restart"
+	OpDelete   = "delete"
+	OpUserVM   = "uservm" // ApplyUserVM / SetUserVMRunning / DeleteUserVM
+)
+
+// VM은 Provisioner가 기억하는 VM 리소스 상태입니다.
+type VM struct {
+	Info    *k8s_service.VMInfo
+	Running bool
+}
+
+// UserVM은 Provisioner가 기억하는 UserVM 리소스와 status입니다.
+type UserVM struct {
+	Spec     k8s_service.UserVMSpec
+	Password string
+
+	Phase    string
+	NodePort int32
+	Message  string
+}
+
+// Provisioner는 VM 리소스를 메모리에만 기록하는 K8sProvisioner입니다.
+// Fail에 작업별 에러를 넣으면 해당 작업이 실패하며, 생성 실패 시에는 만들었던 리소스를 RolledBack에 기록하고 되돌립니다.
+// 백그라운드 작업(*Async)은 호출 즉시 실행하고, 결과와 관계없이 새 작업 ID를 반환합니다.
+type Provisioner struct {
+	mu sync.Mutex
+
+	VMs        map[string]*VM     // "namespace/name" -> VM
+	UserVMs    map[string]*UserVM // "namespace/name" -> UserVM
+	Fail       map[string]error
+	Calls      []string                      // "create namespace/name" 형식의 호출 기록
+	RolledBack []k8s_service.CreatedResource // 생성 실패 또는 RollbackUserVM으로 되돌린 리소스
+
+	operations uint
+}
+
+var _ k8s_service.K8sProvisioner = (*Provisioner)(nil)
+
+func NewProvisioner() *Provisioner {
+	return &Provisioner{
+		VMs:     map[string]*VM{},
+		UserVMs: map[string]*UserVM{},
+		Fail:    map[string]error{},
+	}
+}
+
+// ProvisionerWithContext는 컨텍스트와 관계없이 같은 Provisioner를 반환합니다.
+func (p *Provisioner) ProvisionerWithContext(ctx context.Context) k8s_service.K8sProvisioner {
+	return p
+}
+
+func (p *Provisioner) ProvisionerWithRequest(ctx context.Context) k8s_service.K8sProvisioner {
+	return p
+}
+
+func vmKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// record는 호출을 기록하고 주입된 실패를 반환합니다. (mu를 잡은 상태에서 호출)
+func (p *Provisioner) record(op, namespace, name string) error {
+	p.Calls = append(p.Calls, op+" "+vmKey(namespace, name))
+	return p.Fail[op]
+}
+
+func (p *Provisioner) CreateUserVM(userNamespace, vmName, password, dnsHost, manifestDir, flavorName, imageName string, vmPort int32, networks []models.VmNetwork, sshKeys []string, cloudInit string) (*k8s_service.VMInfo, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := vmKey(userNamespace, vmName)
+	if _, exists := p.VMs[key]; exists {
+		p.Calls = append(p.Calls, OpCreate+" "+key)
+		return nil, fmt.Errorf("virtualmachines %q already exists", vmName)
+	}
+
+	names := k8s_service.VMNames(vmName)
+	created := []k8s_service.CreatedResource{
+		{Group: "kubevirt.io", Version: "v1", Kind: "VirtualMachine", Name: names.VirtualMachine, Namespace: userNamespace},
+		{Version: "v1", Kind: "Service", Name: names.SSHService, Namespace: userNamespace},
+	}
+
+	if err := p.record(OpCreate, userNamespace, vmName); err != nil {
+		p.rollback(created)
+		return nil, err
+	}
+
+	info := &k8s_service.VMInfo{
+		Namespace: userNamespace,
+		Name:      vmName,
+		Port:      vmPort,
+		Password:  password,
+		DNSHost:   dnsHost,
+
+		MacAddress: k8s_service.GenerateMACAddress(),
+		Flavor:     k8s_service.Flavor{Name: flavorName},
+		Image:      k8s_service.Image{Name: imageName},
+		Networks:   networks,
+		SSHKeys:    sshKeys,
+		CloudInit:  cloudInit,
+
+		CreatedResources: created,
+	}
+	p.VMs[key] = &VM{Info: info, Running: true}
+
+	return info, nil
+}
+
+func (p *Provisioner) StartVM(vm *models.VirtualMachine) error {
+	return p.setRunning(OpStart, vm, true)
+}
+
+func (p *Provisioner) StopVM(vm *models.VirtualMachine) error {
+	return p.setRunning(OpStop, vm, false)
+}
+
+func (p *Provisioner) setRunning(op string, vm *models.VirtualMachine, running bool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.record(op, vm.Namespace, vm.Name); err != nil {
+		return err
+	}
+
+	state, ok := p.VMs[vmKey(vm.Namespace, vm.Name)]
+	if !ok {
+		return fmt.Errorf("virtualmachines %q not found", vm.Name)
+	}
+	state.Running = running
+
+	return nil
+}
+
+func (p *Provisioner) DeleteVM(vm *models.VirtualMachine) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.record(OpDelete, vm.Namespace, vm.Name); err != nil {
+		return err
+	}
+
+	// 실제 구현처럼 이미 없는 리소스는 무시
+	delete(p.VMs, vmKey(vm.Namespace, vm.Name))
+	return nil
+}
+
+// rollback은 실제 구현처럼 생성한 역순으로 리소스를 되돌립니다. (mu를 잡은 상태에서 호출)
+func (p *Provisioner) rollback(created []k8s_service.CreatedResource) {
+	for i := len(created) - 1; i >= 0; i-- {
+		p.RolledBack = append(p.RolledBack, created[i])
+	}
+}
+
+func (p *Provisioner) RollbackUserVM(vmInfo *k8s_service.VMInfo) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.record(OpRollback, vmInfo.Namespace, vmInfo.Name); err != nil {
+		return err
+	}
+	p.rollback(vmInfo.CreatedResources)
+	delete(p.VMs, vmKey(vmInfo.Namespace, vmInfo.Name))
+	return nil
+}
+
+func (p *Provisioner) CheckNodePoolCapacity() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.Fail[OpCapacity]
+}
+
+func (p *Provisioner) RestartVM(vm *models.VirtualMachine, timeout time.Duration) error {
+	return p.setRunning(OpRestart, vm, true)
+}
+
+// nextOperation은 백그라운드 작업 ID를 발급합니다.
+func (p *Provisioner) nextOperation() uint {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.operations++
+	return p.operations
+}
+
+func (p *Provisioner) StartVMAsync(vm *models.VirtualMachine, traceID string) uint {
+	p.StartVM(vm)
+	return p.nextOperation()
+}
+
+func (p *Provisioner) StopVMAsync(vm *models.VirtualMachine, traceID string) uint {
+	p.StopVM(vm)
+	return p.nextOperation()
+}
+
+func (p *Provisioner) DeleteVMAsync(vm *models.VirtualMachine, traceID string) uint {
+	p.DeleteVM(vm)
+	return p.nextOperation()
+}
+
+func (p *Provisioner) DeleteVMWithPolicyAsync(vm *models.VirtualMachine, policy config.DeletePolicy, traceID string) uint {
+	return p.DeleteVMAsync(vm, traceID)
+}
+
+// StartOperator는 감시 루프 없이 성공합니다. 테스트는 reconcile 함수를 직접 호출합니다.
+func (p *Provisioner) StartOperator(resync time.Duration, reconcile func(namespace, name string) error) error {
+	return nil
+}
+
+func (p *Provisioner) FetchUserVM(namespace, name string) (*k8s_service.UserVMSpec, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	uvm, ok := p.UserVMs[vmKey(namespace, name)]
+	if !ok {
+		return nil, nil
+	}
+	spec := uvm.Spec
+	return &spec, nil
+}
+
+func (p *Provisioner) ReadUserVMPassword(spec *k8s_service.UserVMSpec) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	uvm, ok := p.UserVMs[vmKey(spec.Namespace, spec.Name)]
+	if !ok || uvm.Password == "" {
+		return "", fmt.Errorf("password secret %s not found", spec.PasswordSecret)
+	}
+	return uvm.Password, nil
+}
+
+func (p *Provisioner) UpdateUserVMStatus(namespace, name, phase string, nodePort int32, message string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if uvm, ok := p.UserVMs[vmKey(namespace, name)]; ok {
+		uvm.Phase, uvm.NodePort, uvm.Message = phase, nodePort, message
+	}
+}
+
+// ApplyUserVM은 실제 구현처럼 승인된 요청이면 기존 UserVM에 승인 ID만 표시하고, 그 밖에 같은 이름이 있으면 ErrUserVMExists를 반환합니다.
+func (p *Provisioner) ApplyUserVM(spec k8s_service.UserVMSpec, password string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.record(OpUserVM, spec.Namespace, spec.Name); err != nil {
+		return err
+	}
+
+	key := vmKey(spec.Namespace, spec.Name)
+	if existing, ok := p.UserVMs[key]; ok {
+		if spec.ApprovalID == 0 {
+			return k8s_service.ErrUserVMExists
+		}
+		existing.Spec.ApprovalID = spec.ApprovalID
+		return nil
+	}
+	if spec.CreatedAt.IsZero() {
+		spec.CreatedAt = time.Now()
+	}
+	p.UserVMs[key] = &UserVM{Spec: spec, Password: password, Phase: k8s_service.UserVMPhasePending}
+	return nil
+}
+
+func (p *Provisioner) SetUserVMRunning(namespace, name string, running bool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.record(OpUserVM, namespace, name); err != nil {
+		return err
+	}
+	uvm, ok := p.UserVMs[vmKey(namespace, name)]
+	if !ok {
+		return fmt.Errorf("uservms %q not found", name)
+	}
+	uvm.Spec.Running = running
+	return nil
+}
+
+func (p *Provisioner) DeleteUserVM(namespace, name string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.record(OpUserVM, namespace, name); err != nil {
+		return err
+	}
+	delete(p.UserVMs, vmKey(namespace, name))
+	return nil
+}
@@ -0,0 +1,166 @@
+package k8s_service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"vm-controller/internal/models"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+)
+
+// metrics-server가 없거나 파드가 아직 수집되지 않은 경우
+var ErrMetricsUnavailable = errors.New("metrics are not available yet")
+
+// UsageCPU는 VM의 CPU 사용량입니다. (코어 단위)
+type UsageCPU struct {
+	UsageCores float64 `json:"usage_cores"`
+	LimitCores float64 `json:"limit_cores"`
+	Ratio      float64 `json:"ratio"` // limit이 없으면 0
+}
+
+// UsageBytes는 VM의 메모리/디스크 사용량입니다.
+type UsageBytes struct {
+	UsedBytes  int64   `json:"used_bytes"` // 디스크 통계가 없으면 -1
+	LimitBytes int64   `json:"limit_bytes"`
+	Ratio      float64 `json:"ratio"` // limit 또는 사용량을 모르면 0
+}
+
+// VMMetrics는 실행 중인 VM의 현재 리소스 사용량입니다. (프론트엔드 게이지용)
+type VMMetrics struct {
+	VmName        string     `json:"vm_name"`
+	Timestamp     time.Time  `json:"timestamp"`      // metrics-server 수집 시각
+	WindowSeconds float64    `json:"window_seconds"` // CPU 사용량 평균 구간
+	CPU           UsageCPU   `json:"cpu"`
+	Memory        UsageBytes `json:"memory"`
+	Disk          UsageBytes `json:"disk"`
+}
+
+// metrics.k8s.io/v1beta1 PodMetrics 중 필요한 부분 (k8s.io/metrics 의존성 없이 디코딩)
+type podMetrics struct {
+	Timestamp  metav1.Time     `json:"timestamp"`
+	Window     metav1.Duration `json:"window"`
+	Containers []struct {
+		Name  string              `json:"name"`
+		Usage corev1.ResourceList `json:"usage"`
+	} `json:"containers"`
+}
+
+func newMetricsClient(config *rest.Config) (rest.Interface, error) {
+	metricsConfig := rest.CopyConfig(config)
+	metricsConfig.GroupVersion = &schema.GroupVersion{Group: "metrics.k8s.io", Version: "v1beta1"}
+	metricsConfig.APIPath = "/apis"
+	metricsConfig.NegotiatedSerializer = scheme.Codecs.WithoutConversion()
+	return rest.RESTClientFor(metricsConfig)
+}
+
+// GetVMMetrics는 VM의 virt-launcher 파드 CPU/메모리 사용량(metrics-server)과
+// 디스크 PVC 사용량(kubelet 통계)을 limit과 함께 반환합니다.
+// 같은 VM의 동시 조회는 한 번만 실행되며 반환 값은 호출자끼리 공유됩니다.
+func (s *K8sService) GetVMMetrics(vm *models.VirtualMachine) (*VMMetrics, error) {
+	detached := s.detached()
+	return s.vmMetricsReads.Do(vm.Namespace+"/"+vm.Name, func() (*VMMetrics, error) {
+		return detached.getVMMetrics(vm)
+	})
+}
+
+func (s *K8sService) getVMMetrics(vm *models.VirtualMachine) (*VMMetrics, error) {
+	ctx := s.baseContext()
+
+	pods, err := s.clientset.CoreV1().Pods(vm.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "kubevirt.io=virt-launcher,vm.kubevirt.io/name=" + vm.Name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list virt-launcher pods: %v", err)
+	}
+
+	var launcher *corev1.Pod
+	for i := range pods.Items {
+		if pods.Items[i].Status.Phase == corev1.PodRunning {
+			launcher = &pods.Items[i]
+			break
+		}
+	}
+	if launcher == nil {
+		return nil, ErrVMNotRunning
+	}
+
+	raw, err := s.metricsClient.Get().
+		Namespace(vm.Namespace).
+		Resource("pods").
+		Name(launcher.Name).
+		DoRaw(ctx)
+	if err != nil {
+		if apierrors.IsNotFound(err) || apierrors.IsServiceUnavailable(err) {
+			return nil, ErrMetricsUnavailable
+		}
+		return nil, fmt.Errorf("failed to read pod metrics: %v", err)
+	}
+
+	var usage podMetrics
+	if err := json.Unmarshal(raw, &usage); err != nil {
+		return nil, fmt.Errorf("failed to decode pod metrics: %v", err)
+	}
+
+	metrics := &VMMetrics{
+		VmName:        vm.Name,
+		Timestamp:     usage.Timestamp.Time,
+		WindowSeconds: usage.Window.Duration.Seconds(),
+		Disk:          UsageBytes{UsedBytes: -1},
+	}
+
+	for _, container := range usage.Containers {
+		if quantity, ok := container.Usage[corev1.ResourceCPU]; ok {
+			metrics.CPU.UsageCores += quantity.AsApproximateFloat64()
+		}
+		if quantity, ok := container.Usage[corev1.ResourceMemory]; ok {
+			metrics.Memory.UsedBytes += quantity.Value()
+		}
+	}
+
+	// limit은 CPU 포화 샘플러와 같이 컨테이너 limit 합계 기준
+	for _, container := range launcher.Spec.Containers {
+		if quantity, ok := container.Resources.Limits[corev1.ResourceCPU]; ok {
+			metrics.CPU.LimitCores += quantity.AsApproximateFloat64()
+		}
+		if quantity, ok := container.Resources.Limits[corev1.ResourceMemory]; ok {
+			metrics.Memory.LimitBytes += quantity.Value()
+		}
+	}
+
+	if metrics.CPU.LimitCores > 0 {
+		metrics.CPU.Ratio = metrics.CPU.UsageCores / metrics.CPU.LimitCores
+	}
+	if metrics.Memory.LimitBytes > 0 {
+		metrics.Memory.Ratio = float64(metrics.Memory.UsedBytes) / float64(metrics.Memory.LimitBytes)
+	}
+
+	// 디스크는 DataVolume이 만든 PVC({VM_NAME}-disk)의 kubelet 볼륨 통계
+	pvcName := VMNames(vm.Name).Disk
+	pvc, err := s.clientset.CoreV1().PersistentVolumeClaims(vm.Namespace).Get(ctx, pvcName, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get disk pvc: %v", err)
+	}
+	if err == nil {
+		quantity, ok := pvc.Status.Capacity[corev1.ResourceStorage]
+		if !ok {
+			quantity = pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		}
+		metrics.Disk.LimitBytes = quantity.Value()
+
+		if used, ok := s.volumeUsage()[vm.Namespace+"/"+pvcName]; ok {
+			metrics.Disk.UsedBytes = used
+			if metrics.Disk.LimitBytes > 0 {
+				metrics.Disk.Ratio = float64(used) / float64(metrics.Disk.LimitBytes)
+			}
+		}
+	}
+
+	return metrics, nil
+}
@@ -0,0 +1,166 @@
+package k8s_service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+	"vm-controller/internal/models"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var gvrVMIMigration = schema.GroupVersionResource{Group: "kubevirt.io", Version: "v1", Resource: "virtualmachineinstancemigrations"}
+
+var (
+	ErrVMNotRunning        = errors.New("VM instance is not running")
+	ErrNotMigratable       = errors.New("VM cannot be live migrated")
+	ErrMigrationInProgress = errors.New("a migration for this VM is already in progress")
+)
+
+// MigrationInfo는 VirtualMachineInstanceMigration의 진행 상황입니다.
+// 노드/시각 정보는 VMI의 status.migrationState가 이 마이그레이션을 가리킬 때만 채워집니다.
+type MigrationInfo struct {
+	Name       string     `json:"name"`
+	VmName     string     `json:"vm_name"`
+	Phase      string     `json:"phase"` // Pending, Scheduling, Scheduled, PreparingTarget, TargetReady, Running, Succeeded, Failed
+	SourceNode string     `json:"source_node,omitempty"`
+	TargetNode string     `json:"target_node,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	EndedAt    *time.Time `json:"ended_at,omitempty"`
+	Message    string     `json:"message,omitempty"` // 실패 사유
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Finished는 마이그레이션이 끝났는지(성공/실패) 확인합니다.
+func (m MigrationInfo) Finished() bool {
+	return m.Phase == "Succeeded" || m.Phase == "Failed"
+}
+
+// MigrateVM은 실행 중인 VM을 다른 노드로 라이브 마이그레이션하는 VirtualMachineInstanceMigration을 생성합니다.
+// 노드 드레인 전에 사용하며, 진행 상황은 ListVMMigrations로 확인합니다.
+// 공유(RWX) 스토리지가 아닌 디스크 등으로 VMI의 LiveMigratable 조건이 False이면 생성하지 않고 사유를 반환합니다.
+func (s *K8sService) MigrateVM(vm *models.VirtualMachine) (*MigrationInfo, error) {
+	ctx := s.baseContext()
+
+	vmi, err := s.dynamicClient.Resource(gvrVMI).Namespace(vm.Namespace).Get(ctx, vm.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, ErrVMNotRunning
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get VM instance: %v", err)
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(vmi.Object, "status", "conditions")
+	for _, raw := range conditions {
+		condition, ok := raw.(map[string]interface{})
+		if ok && condition["type"] == "LiveMigratable" && condition["status"] == "False" {
+			return nil, fmt.Errorf("%w: %v", ErrNotMigratable, condition["message"])
+		}
+	}
+
+	migrations, err := s.ListVMMigrations(vm)
+	if err != nil {
+		return nil, err
+	}
+	for _, migration := range migrations {
+		if !migration.Finished() {
+			return nil, ErrMigrationInProgress
+		}
+	}
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "kubevirt.io/v1",
+		"kind":       "VirtualMachineInstanceMigration",
+		"metadata": map[string]interface{}{
+			"generateName": vm.Name + "-migration-",
+			"namespace":    vm.Namespace,
+		},
+		"spec": map[string]interface{}{
+			"vmiName": vm.Name,
+		},
+	}}
+
+	created, err := s.dynamicClient.Resource(gvrVMIMigration).Namespace(vm.Namespace).Create(ctx, obj, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create virtual machine instance migration: %v", err)
+	}
+	s.recordVMPatch(vm.Name, "migrate", "created VirtualMachineInstanceMigration "+created.GetName())
+
+	info := migrationInfo(created, vmi)
+	return &info, nil
+}
+
+// ListVMMigrations는 VM의 마이그레이션 목록을 최신순으로 반환합니다.
+func (s *K8sService) ListVMMigrations(vm *models.VirtualMachine) ([]MigrationInfo, error) {
+	ctx := s.baseContext()
+
+	list, err := s.dynamicClient.Resource(gvrVMIMigration).Namespace(vm.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list virtual machine instance migrations: %v", err)
+	}
+
+	// 진행 중인 마이그레이션의 노드/시각 정보는 VMI에 기록됨 (VM이 정지되어 VMI가 없으면 생략)
+	vmi, err := s.dynamicClient.Resource(gvrVMI).Namespace(vm.Namespace).Get(ctx, vm.Name, metav1.GetOptions{})
+	if err != nil {
+		vmi = nil
+	}
+
+	migrations := []MigrationInfo{}
+	for i := range list.Items {
+		if vmiName, _, _ := unstructured.NestedString(list.Items[i].Object, "spec", "vmiName"); vmiName != vm.Name {
+			continue
+		}
+		migrations = append(migrations, migrationInfo(&list.Items[i], vmi))
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].CreatedAt.After(migrations[j].CreatedAt)
+	})
+
+	return migrations, nil
+}
+
+// migrationInfo는 마이그레이션 리소스와 VMI의 migrationState를 합쳐 진행 상황을 만듭니다.
+func migrationInfo(migration *unstructured.Unstructured, vmi *unstructured.Unstructured) MigrationInfo {
+	vmiName, _, _ := unstructured.NestedString(migration.Object, "spec", "vmiName")
+	phase, _, _ := unstructured.NestedString(migration.Object, "status", "phase")
+	if phase == "" {
+		phase = "Pending"
+	}
+
+	info := MigrationInfo{
+		Name:      migration.GetName(),
+		VmName:    vmiName,
+		Phase:     phase,
+		CreatedAt: migration.GetCreationTimestamp().Time,
+	}
+
+	if vmi == nil {
+		return info
+	}
+	state, found, _ := unstructured.NestedMap(vmi.Object, "status", "migrationState")
+	if !found || state["migrationUid"] != string(migration.GetUID()) {
+		return info
+	}
+
+	info.SourceNode, _ = state["sourceNode"].(string)
+	info.TargetNode, _ = state["targetNode"].(string)
+	info.Message, _ = state["failureReason"].(string)
+	info.StartedAt = parseMigrationTime(state["startTimestamp"])
+	info.EndedAt = parseMigrationTime(state["endTimestamp"])
+
+	return info
+}
+
+func parseMigrationTime(value interface{}) *time.Time {
+	text, _ := value.(string)
+	parsed, err := time.Parse(time.RFC3339, text)
+	if err != nil {
+		return nil
+	}
+	return &parsed
+}
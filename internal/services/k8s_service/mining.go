@@ -0,0 +1,397 @@
+package k8s_service
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"vm-controller/internal/models"
+	notificationservice "vm-controller/internal/services/notification_service"
+
+	"github.com/spf13/cast"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// 기본 탐지 주기 (MINING_DETECT_INTERVAL, 0이면 사용 안 함)
+const defaultMiningDetectInterval = 5 * time.Minute
+
+// 네트워크 패턴 판정 기준: 채굴기는 풀과 연결을 계속 열어 두고 작은 share를 일정하게 주고받음
+const (
+	miningActiveRatio       = 0.9     // 송수신이 모두 있었던 구간 비율 (이상)
+	miningMaxBytesPerMinute = 1 << 20 // 분당 송수신 합계 (미만, 대용량 트래픽은 채굴이 아님)
+	miningMaxTxVariation    = 1.0     // 구간별 송신량의 변동 계수 (미만)
+)
+
+// CoreDNS 로그를 한 번에 읽는 최대 크기 (파드당)
+const miningDNSLogLimitBytes = 8 << 20
+
+// defaultMiningPoolDomains는 잘 알려진 채굴 풀 도메인입니다. (하위 도메인 포함, MINING_POOL_DOMAINS로 추가)
+var defaultMiningPoolDomains = []string{
+	"minexmr.com", "supportxmr.com", "nanopool.org", "2miners.com", "f2pool.com", "ethermine.org",
+	"hashvault.pro", "moneroocean.stream", "c3pool.com", "herominers.com", "minergate.com", "nicehash.com",
+	"unmineable.com", "xmrpool.eu", "viabtc.com", "antpool.com", "poolin.com", "kryptex.network",
+}
+
+var (
+	miningMu          sync.Mutex
+	miningLookups     = map[string]map[string]*models.PoolLookup{} // "namespace/vm" 별 채굴 풀 조회 기록 (관측 구간 동안 유지)
+	miningLastDNSScan time.Time
+)
+
+// MiningDetectInterval은 채굴 탐지 주기를 반환합니다. (MINING_DETECT_INTERVAL, 0이면 사용 안 함)
+func MiningDetectInterval() time.Duration {
+	raw := os.Getenv("MINING_DETECT_INTERVAL")
+	if raw == "" {
+		return defaultMiningDetectInterval
+	}
+	interval, err := time.ParseDuration(raw)
+	if err != nil || interval < 0 {
+		return defaultMiningDetectInterval
+	}
+	return interval
+}
+
+// miningAutoThrottle은 탐지한 VM을 검토 전까지 일시 정지할지 여부입니다. (MINING_AUTO_THROTTLE, 기본 false)
+func miningAutoThrottle() bool {
+	return cast.ToBool(os.Getenv("MINING_AUTO_THROTTLE"))
+}
+
+func miningPoolDomains() []string {
+	domains := append([]string{}, defaultMiningPoolDomains...)
+	for _, domain := range strings.Split(os.Getenv("MINING_POOL_DOMAINS"), ",") {
+		if domain = strings.ToLower(strings.Trim(strings.TrimSpace(domain), ".")); domain != "" {
+			domains = append(domains, domain)
+		}
+	}
+	return domains
+}
+
+// StartMiningDetector는 주기적으로 CPU 포화와 네트워크/DNS 패턴을 함께 보고 채굴 의심 VM을 관리자 신고 대기열에 올립니다.
+// CPU 샘플은 StartCPUSaturationSampler가 수집한 값을 사용합니다.
+func (s *K8sService) StartMiningDetector(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			runRecovered("mining.detector", "*", func() error {
+				return s.detectMining()
+			})
+		}
+	}()
+}
+
+func (s *K8sService) detectMining() error {
+	// DNS 로그를 읽지 못해도 네트워크 패턴만으로 탐지는 계속
+	if err := s.scanPoolLookups(); err != nil {
+		s.log().Warn("mining detector: failed to scan DNS logs", "error", err)
+	}
+
+	saturated := s.ListCPUSaturatedVMs()
+	if len(saturated) == 0 {
+		return nil
+	}
+
+	vms, err := s.vmService.FetchAllVMs(false)
+	if err != nil {
+		return fmt.Errorf("failed to fetch VMs: %v", err)
+	}
+	vmByKey := map[string]*models.VirtualMachine{}
+	for i := range vms {
+		vmByKey[vms[i].Namespace+"/"+vms[i].Name] = &vms[i]
+	}
+
+	now := time.Now()
+	for _, saturation := range saturated {
+		key := saturation.Namespace + "/" + saturation.VmName
+		vm, ok := vmByKey[key]
+		if !ok || vm.Status != models.VmStatusRunning {
+			continue
+		}
+
+		evidence := &models.MiningEvidence{
+			AvgUsageCores:  saturation.AvgUsageCores,
+			LimitCores:     saturation.LimitCores,
+			SaturatedRatio: saturation.SaturatedRatio,
+			Samples:        saturation.Samples,
+			PoolLookups:    poolLookupsOf(key),
+			DetectedAt:     now,
+		}
+		fillNetworkEvidence(key, evidence)
+
+		// CPU 포화만으로는 신고하지 않음 (빌드, 학습 등 정상 작업과 구분)
+		if !evidence.SteadyNetwork && len(evidence.PoolLookups) == 0 {
+			continue
+		}
+
+		s.flagMining(vm, evidence)
+	}
+
+	return nil
+}
+
+// flagMining은 탐지 신고를 등록하고, 새 신고이면 관리자에게 알린 뒤 설정에 따라 VM을 일시 정지합니다.
+func (s *K8sService) flagMining(vm *models.VirtualMachine, evidence *models.MiningEvidence) {
+	reportService := s.reportService
+
+	report, created, err := reportService.FlagMining(vm, evidence)
+	if err != nil {
+		s.log().Error("mining detector: failed to flag VM", "vm", vm.Name, "error", err)
+		return
+	}
+	if report == nil || !created {
+		return
+	}
+
+	s.log().Warn("mining detector: flagged VM",
+		"vm", vm.Name, "report_id", report.ID, "cpu_saturated_ratio", evidence.SaturatedRatio, "pool_lookups", len(evidence.PoolLookups))
+	reportService.NotifyAdmins(report)
+
+	if !miningAutoThrottle() {
+		return
+	}
+
+	s.vmEventService.Record(models.VmEvent{
+		VmName:    vm.Name,
+		Type:      models.VmEventOperationRequested,
+		Operation: "mining.throttle",
+		Detail:    fmt.Sprintf("report #%d", report.ID),
+	})
+
+	// 격리를 먼저 기록 (사용자가 일시 정지 직후 해제하지 못하도록)
+	throttledAt := time.Now()
+	if err := reportService.MarkThrottled(report.ID, &throttledAt); err != nil {
+		s.log().Error("mining detector: failed to mark report throttled", "report_id", report.ID, "error", err)
+		return
+	}
+	s.PauseVMAsync(vm, "")
+
+	if err := s.notificationService.NotifyTemplate(vm.UserID, "vm.mining_throttled", notificationservice.Data{"vm": vm.Name}); err != nil {
+		s.log().Warn("mining detector: failed to notify owner", "vm", vm.Name, "error", err)
+	}
+}
+
+// fillNetworkEvidence는 관측 구간의 파드 네트워크 누적값으로 "항상 열려 있는 저대역 연결" 패턴인지 판정합니다.
+func fillNetworkEvidence(key string, evidence *models.MiningEvidence) {
+	cpuSamplesMu.Lock()
+	var network []networkSample
+	if samples, ok := cpuSamplesOf[key]; ok {
+		network = append(network, samples.network...)
+	}
+	cpuSamplesMu.Unlock()
+
+	if len(network) < 2 {
+		return
+	}
+
+	var (
+		active     int
+		totalBytes float64
+		txRates    []float64
+	)
+	for i := 1; i < len(network); i++ {
+		prev, cur := network[i-1], network[i]
+		minutes := cur.at.Sub(prev.at).Minutes()
+		// 파드 재시작으로 누적값이 초기화된 구간은 제외
+		if minutes <= 0 || cur.rxBytes < prev.rxBytes || cur.txBytes < prev.txBytes {
+			continue
+		}
+
+		rx, tx := float64(cur.rxBytes-prev.rxBytes), float64(cur.txBytes-prev.txBytes)
+		if rx > 0 && tx > 0 {
+			active++
+		}
+		totalBytes += rx + tx
+		txRates = append(txRates, tx/minutes)
+	}
+	if len(txRates) == 0 {
+		return
+	}
+
+	elapsed := network[len(network)-1].at.Sub(network[0].at).Minutes()
+	evidence.ActiveRatio = float64(active) / float64(len(txRates))
+	if elapsed > 0 {
+		evidence.AvgBytesPerMinute = totalBytes / elapsed
+	}
+	evidence.TxVariation = coefficientOfVariation(txRates)
+	evidence.SteadyNetwork = evidence.ActiveRatio >= miningActiveRatio &&
+		evidence.AvgBytesPerMinute < miningMaxBytesPerMinute &&
+		evidence.TxVariation < miningMaxTxVariation
+}
+
+func coefficientOfVariation(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	if mean == 0 {
+		return 0
+	}
+
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(variance/float64(len(values))) / mean
+}
+
+// scanPoolLookups는 지난 검사 이후의 CoreDNS 쿼리 로그(log 플러그인)에서 채굴 풀 도메인 조회를 찾아 VM별로 기록합니다.
+// 조회한 클라이언트 IP를 virt-launcher 파드 IP와 대조하여 VM을 찾습니다. (CoreDNS에 log 플러그인이 없으면 기록 없음)
+func (s *K8sService) scanPoolLookups() error {
+	ctx := s.baseContext()
+
+	launchers, err := s.clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		LabelSelector: "kubevirt.io=virt-launcher",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list virt-launcher pods: %v", err)
+	}
+	vmByIP := map[string]string{}
+	for _, pod := range launchers.Items {
+		vmName := pod.Labels["vm.kubevirt.io/name"]
+		if vmName == "" || pod.Status.PodIP == "" {
+			continue
+		}
+		vmByIP[pod.Status.PodIP] = pod.Namespace + "/" + vmName
+	}
+
+	selector := os.Getenv("MINING_DNS_LOG_SELECTOR")
+	if selector == "" {
+		selector = "k8s-app=kube-dns"
+	}
+	dnsPods, err := s.clientset.CoreV1().Pods(metav1.NamespaceSystem).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return fmt.Errorf("failed to list DNS pods: %v", err)
+	}
+
+	miningMu.Lock()
+	since := miningLastDNSScan
+	miningMu.Unlock()
+	if since.IsZero() {
+		since = time.Now().Add(-MiningDetectInterval())
+	}
+	scanStartedAt := time.Now()
+
+	domains := miningPoolDomains()
+	found := map[string]map[string]int{} // "namespace/vm" → 도메인 → 조회 수
+	limit := int64(miningDNSLogLimitBytes)
+	for _, pod := range dnsPods.Items {
+		sinceTime := metav1.NewTime(since)
+		raw, err := s.clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+			SinceTime:  &sinceTime,
+			LimitBytes: &limit,
+		}).DoRaw(ctx)
+		if err != nil {
+			s.log().Warn("mining detector: failed to read pod logs", "pod", pod.Name, "error", err)
+			continue
+		}
+
+		scanner := bufio.NewScanner(bytes.NewReader(raw))
+		for scanner.Scan() {
+			clientIP, domain, ok := parseDNSQueryLog(scanner.Text())
+			if !ok {
+				continue
+			}
+			key, ok := vmByIP[clientIP]
+			if !ok {
+				continue
+			}
+			pool, ok := matchPoolDomain(domain, domains)
+			if !ok {
+				continue
+			}
+			if found[key] == nil {
+				found[key] = map[string]int{}
+			}
+			found[key][pool]++
+		}
+	}
+
+	miningMu.Lock()
+	defer miningMu.Unlock()
+
+	miningLastDNSScan = scanStartedAt
+	for key, pools := range found {
+		if miningLookups[key] == nil {
+			miningLookups[key] = map[string]*models.PoolLookup{}
+		}
+		for pool, count := range pools {
+			lookup, ok := miningLookups[key][pool]
+			if !ok {
+				lookup = &models.PoolLookup{Domain: pool}
+				miningLookups[key][pool] = lookup
+			}
+			lookup.Count += count
+			lookup.LastSeen = scanStartedAt
+		}
+	}
+
+	// CPU 관측 구간보다 오래된 조회 기록은 제거
+	retention := time.Duration(cpuSaturationWindow()) * time.Minute
+	for key, lookups := range miningLookups {
+		for pool, lookup := range lookups {
+			if scanStartedAt.Sub(lookup.LastSeen) > retention {
+				delete(lookups, pool)
+			}
+		}
+		if len(lookups) == 0 {
+			delete(miningLookups, key)
+		}
+	}
+
+	return nil
+}
+
+// parseDNSQueryLog는 CoreDNS log 플러그인의 한 줄에서 클라이언트 IP와 조회 도메인을 꺼냅니다.
+// 예: [INFO] 10.244.1.5:53411 - 4321 "A IN pool.supportxmr.com. udp 48 false 512" NOERROR ...
+func parseDNSQueryLog(line string) (clientIP, domain string, ok bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 7 || fields[0] != "[INFO]" {
+		return "", "", false
+	}
+
+	addr := fields[1]
+	if i := strings.LastIndex(addr, ":"); i > 0 {
+		addr = addr[:i]
+	}
+	clientIP = strings.Trim(addr, "[]")
+
+	// "A IN domain. ..." 에서 세 번째 토큰이 도메인
+	if !strings.HasPrefix(fields[4], `"`) || fields[5] != "IN" {
+		return "", "", false
+	}
+	domain = strings.ToLower(strings.TrimSuffix(fields[6], "."))
+	if domain == "" {
+		return "", "", false
+	}
+	return clientIP, domain, true
+}
+
+// matchPoolDomain은 domain이 채굴 풀 도메인이거나 그 하위 도메인이면 풀 도메인을 반환합니다.
+func matchPoolDomain(domain string, pools []string) (string, bool) {
+	for _, pool := range pools {
+		if domain == pool || strings.HasSuffix(domain, "."+pool) {
+			return pool, true
+		}
+	}
+	return "", false
+}
+
+func poolLookupsOf(key string) []models.PoolLookup {
+	miningMu.Lock()
+	defer miningMu.Unlock()
+
+	result := []models.PoolLookup{}
+	for _, lookup := range miningLookups[key] {
+		result = append(result, *lookup)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Count > result[j].Count })
+	return result
+}
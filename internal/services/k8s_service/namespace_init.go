@@ -0,0 +1,60 @@
+package k8s_service
+
+import (
+	"fmt"
+	"sync"
+)
+
+// 네임스페이스별 초기화 잠금 (같은 사용자의 첫 VM 생성이 동시에 들어와도 client-init은 한 번만 적용)
+var (
+	namespaceInitMu    sync.Mutex
+	namespaceInitLocks = map[string]*sync.Mutex{}
+)
+
+func namespaceInitLock(namespace string) *sync.Mutex {
+	namespaceInitMu.Lock()
+	defer namespaceInitMu.Unlock()
+
+	lock, ok := namespaceInitLocks[namespace]
+	if !ok {
+		lock = &sync.Mutex{}
+		namespaceInitLocks[namespace] = lock
+	}
+	return lock
+}
+
+// initUserNamespace는 initDir의 client-init 매니페스트(네임스페이스, 기본 정책)를 적용합니다.
+// 같은 네임스페이스의 초기화는 직렬화되며, DB에 초기화 완료로 기록된 사용자 네임스페이스는 적용을 건너뜁니다.
+// 반환하는 리소스는 초기화 완료를 기록하지 못한 경우에만 채워지며, 호출자는 실패 시 이 리소스만 롤백합니다.
+// (초기화가 기록된 네임스페이스는 다른 생성 요청이 이미 사용 중일 수 있으므로 롤백하지 않음)
+func (s *K8sService) initUserNamespace(initDir, namespace string) ([]CreatedResource, error) {
+	lock := namespaceInitLock(namespace)
+	lock.Lock()
+	defer lock.Unlock()
+
+	userService := s.userService
+	user, errUser := userService.FetchUserByNamespace(namespace)
+	if errUser == nil && user.NamespaceInitialized {
+		return nil, nil
+	}
+
+	created, err := s.applyManifests(initDir, s.clientInitReplacements(namespace), namespace, true)
+	if err != nil {
+		return created, fmt.Errorf("failed to apply client-init manifests: %v", err)
+	}
+	// VM 파드/일반 파드 쿼터 (네임스페이스와 함께 삭제되므로 롤백 대상에 넣지 않음)
+	if err := s.applyWorkloadQuotas(namespace); err != nil {
+		return created, err
+	}
+
+	// 사용자 소유가 아닌 네임스페이스(샌드박스 도구)는 기록하지 않고 매번 적용
+	if errUser != nil {
+		return created, nil
+	}
+	if err := userService.MarkNamespaceInitialized(namespace); err != nil {
+		s.log().Warn("failed to mark namespace as initialized", "namespace", namespace, "error", err)
+		return created, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,106 @@
+package k8s_service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"vm-controller/internal/naming"
+)
+
+// MaxVMNameLength는 사용자가 정할 수 있는 VM 이름의 최대 길이입니다.
+// VM 이름은 라벨 값(vm.kubevirt.io/name 셀렉터, vmNameLabel)과 게스트 hostname에 그대로 쓰이므로 63자를 넘을 수 없습니다.
+// 접두사/접미사가 붙는 파생 리소스 이름은 63자를 넘으면 naming.ResourceName이 줄여서 해시를 붙입니다.
+const MaxVMNameLength = 63
+
+// DNS 호스트 이름 제한 (label 63자, 전체 253자)
+const (
+	maxDNSLabelLength = 63
+	maxDNSNameLength  = 253
+)
+
+var vmNameRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// VM 디스크(DataVolume)에 VM 이름을 남기는 라벨. 이름이 줄어든 디스크도 VM을 찾을 수 있게 함
+const vmNameLabel = "vm-controller/vm-name"
+
+// 디스크 이름 접미사 (라벨이 없는 이전 디스크에서 VM 이름을 역산할 때 사용)
+const vmDiskSuffix = "-disk"
+
+// VMResourceNames는 VM 하나에서 파생되는 쿠버네티스 리소스 이름입니다.
+// 템플릿 렌더링(vmReplacements), 삭제(DeleteVMWithPolicy), 조회와 워치독이 모두 이 값을 사용합니다.
+type VMResourceNames struct {
+	VirtualMachine  string // VirtualMachine (VM 이름 그대로)
+	SSHService      string // SSH 접속용 NodePort Service
+	WebService      string // Ingress 백엔드 Service
+	Ingress         string
+	CloudInitSecret string // cloud-init userdata Secret
+	Disk            string // 루트 디스크 DataVolume (PVC 이름과 같음)
+	Export          string // VirtualMachineExport
+	ExportToken     string // VirtualMachineExport 토큰 Secret
+}
+
+// VMNames는 VM 이름에서 파생 리소스 이름을 만듭니다.
+// 짧은 이름은 기존 규칙(vps-access-<vm>, <vm>-disk 등)과 같고, 63자를 넘으면 잘라서 해시를 붙입니다.
+func VMNames(vmName string) VMResourceNames {
+	export := naming.ResourceName("", vmName, "-export")
+
+	return VMResourceNames{
+		VirtualMachine:  vmName,
+		SSHService:      naming.ResourceName("vps-access-", vmName, ""),
+		WebService:      naming.ResourceName("vps-web-", vmName, ""),
+		Ingress:         naming.ResourceName("vm-ingress-", vmName, ""),
+		CloudInitSecret: naming.ResourceName("", vmName, "-cloud-init-userdata"),
+		Disk:            naming.ResourceName("", vmName, vmDiskSuffix),
+		Export:          export,
+		ExportToken:     naming.ResourceName("", export, "-token"),
+	}
+}
+
+// replacements는 템플릿의 리소스 이름 자리표시자 값을 반환합니다.
+func (n VMResourceNames) replacements() map[string]string {
+	return map[string]string{
+		"{{SSH_SERVICE_NAME}}":       n.SSHService,
+		"{{WEB_SERVICE_NAME}}":       n.WebService,
+		"{{INGRESS_NAME}}":           n.Ingress,
+		"{{CLOUD_INIT_SECRET_NAME}}": n.CloudInitSecret,
+		"{{DISK_NAME}}":              n.Disk,
+	}
+}
+
+// vmNameFromDisk는 디스크(DataVolume)의 라벨이나 이름에서 VM 이름을 찾습니다. VM 디스크가 아니면 false를 반환합니다.
+func vmNameFromDisk(name string, labels map[string]string) (string, bool) {
+	if vmName := labels[vmNameLabel]; vmName != "" && VMNames(vmName).Disk == name {
+		return vmName, true
+	}
+	if strings.HasSuffix(name, vmDiskSuffix) {
+		return strings.TrimSuffix(name, vmDiskSuffix), true
+	}
+	return "", false
+}
+
+// ValidateVMName은 VM 이름이 DNS-1123 label 형식이고 MaxVMNameLength 이하인지 확인합니다.
+func ValidateVMName(name string) error {
+	if name == "" {
+		return fmt.Errorf("VM name is required")
+	}
+	if len(name) > MaxVMNameLength {
+		return fmt.Errorf("VM name is too long: %d characters (maximum is %d)", len(name), MaxVMNameLength)
+	}
+	if !vmNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid VM name %q: use lowercase letters, digits and '-', starting and ending with a letter or digit", name)
+	}
+	return nil
+}
+
+// ValidateDNSHost는 VM 웹 주소(Ingress host)가 DNS 이름 길이 제한(label 63자, 전체 253자) 안에 있는지 확인합니다.
+func ValidateDNSHost(host string) error {
+	if len(host) > maxDNSNameLength {
+		return fmt.Errorf("host name is too long: %d characters (maximum is %d)", len(host), maxDNSNameLength)
+	}
+	for _, label := range strings.Split(host, ".") {
+		if len(label) > maxDNSLabelLength {
+			return fmt.Errorf("host name label %q is too long: %d characters (maximum is %d)", label, len(label), maxDNSLabelLength)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package k8s_service
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"vm-controller/internal/models"
+)
+
+// VM당 연결할 수 있는 보조 NIC 최대 개수
+const MaxSecondaryNetworks = 4
+
+// ValidateVmNetwork는 보조 NIC 설정을 검사합니다. (고정 IP는 CIDR 형식, 허용된 네트워크에서만)
+func ValidateVmNetwork(network *models.Network, address string) error {
+	if address == "" {
+		return nil
+	}
+	if !network.AllowStaticIP {
+		return fmt.Errorf("network %s does not allow static addresses", network.Name)
+	}
+	if _, _, err := net.ParseCIDR(address); err != nil {
+		return fmt.Errorf("invalid address %s (expected CIDR, e.g. 10.10.0.5/24)", address)
+	}
+	return nil
+}
+
+// secondaryNetworkName은 VM spec에서 사용할 보조 NIC 이름입니다. (net1, net2, ...)
+func secondaryNetworkName(index int) string {
+	return fmt.Sprintf("net%d", index+1)
+}
+
+// networkReplacements는 보조 NIC를 VM spec(interfaces/networks)과 cloud-init network config로 렌더링합니다.
+// 각 항목은 YAML에 그대로 들어갈 수 있도록 한 줄짜리 JSON(flow style)으로 만듭니다.
+func networkReplacements(vmInfo *VMInfo) map[string]string {
+	var interfaces, networks []string
+
+	// 기본 NIC는 DHCP (KubeVirt masquerade), 보조 NIC는 MAC 주소로 매칭
+	ethernets := map[string]interface{}{
+		"primary": map[string]interface{}{
+			"match": map[string]string{"macaddress": vmInfo.MacAddress},
+			"dhcp4": true,
+		},
+	}
+
+	for i, network := range vmInfo.Networks {
+		name := secondaryNetworkName(i)
+
+		iface, _ := json.Marshal(map[string]interface{}{"name": name, "bridge": map[string]string{}, "macAddress": network.MacAddress})
+		interfaces = append(interfaces, "- "+string(iface))
+
+		multus, _ := json.Marshal(map[string]interface{}{"name": name, "multus": map[string]string{"networkName": network.NadRef}})
+		networks = append(networks, "- "+string(multus))
+
+		ethernet := map[string]interface{}{
+			"match": map[string]string{"macaddress": network.MacAddress},
+			"dhcp4": network.Address == "",
+		}
+		if network.Address != "" {
+			ethernet["addresses"] = []string{network.Address}
+		}
+		ethernets[name] = ethernet
+	}
+
+	networkData, _ := json.Marshal(map[string]interface{}{"version": 2, "ethernets": ethernets})
+
+	// 템플릿의 자리 표시자 들여쓰기에 맞춰 두 번째 항목부터 들여씀
+	return map[string]string{
+		"{{EXTRA_INTERFACES}}": strings.Join(interfaces, "\n"+strings.Repeat(" ", 12)),
+		"{{EXTRA_NETWORKS}}":   strings.Join(networks, "\n"+strings.Repeat(" ", 8)),
+		"{{NETWORK_DATA}}":     string(networkData),
+	}
+}
@@ -0,0 +1,196 @@
+package k8s_service
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"sort"
+	"time"
+	"vm-controller/internal/apperrors"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// 노드 풀 라벨 기본값 (NODE_POOL_LABEL로 변경, 라벨이 없는 노드는 풀에 속하지 않음)
+const defaultNodePoolLabel = "cloud.vm-controller.io/node-pool"
+
+// ErrNoSchedulablePool은 모든 노드가 유지보수 중인 풀에 속해 신규 VM을 배치할 수 없을 때 반환됩니다.
+var ErrNoSchedulablePool = apperrors.New(apperrors.CodeNoSchedulablePool, "no node pool is accepting new VMs (all pools are in maintenance)")
+
+func nodePoolLabel() string {
+	if label := os.Getenv("NODE_POOL_LABEL"); label != "" {
+		return label
+	}
+	return defaultNodePoolLabel
+}
+
+// NodePool은 관리자 API에 노출되는 노드 풀 상태입니다.
+type NodePool struct {
+	Name        string     `json:"name"`
+	Nodes       int        `json:"nodes"`
+	ReadyNodes  int        `json:"ready_nodes"` // Ready이고 cordon되지 않은 노드
+	VMs         int        `json:"vms"`         // 풀의 노드에서 실행 중인 VMI 수
+	Maintenance bool       `json:"maintenance"` // 신규 VM 배치 중지
+	Reason      string     `json:"reason,omitempty"`
+	Since       *time.Time `json:"since,omitempty"`
+}
+
+func nodeSchedulable(node *corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// ListNodePools는 NODE_POOL_LABEL 라벨로 노드를 묶어 풀별 노드/VM 수와 유지보수 상태를 반환합니다.
+// 노드가 없어진 풀이라도 유지보수 기록이 남아 있으면 함께 반환합니다.
+func (s *K8sService) ListNodePools() ([]NodePool, error) {
+	nodes, err := s.clientset.CoreV1().Nodes().List(s.baseContext(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %v", err)
+	}
+	vmis, err := s.ListVMIs()
+	if err != nil {
+		return nil, err
+	}
+	maintenances, err := s.nodePoolService.FetchMaintenances()
+	if err != nil {
+		return nil, err
+	}
+
+	label := nodePoolLabel()
+	pools := map[string]*NodePool{}
+	nodePools := map[string]string{}
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		name, ok := node.Labels[label]
+		if !ok {
+			continue
+		}
+		nodePools[node.Name] = name
+
+		pool, ok := pools[name]
+		if !ok {
+			pool = &NodePool{Name: name}
+			pools[name] = pool
+		}
+		pool.Nodes++
+		if nodeSchedulable(node) {
+			pool.ReadyNodes++
+		}
+	}
+
+	for _, vmi := range vmis {
+		if name, ok := nodePools[vmi.NodeName]; ok {
+			pools[name].VMs++
+		}
+	}
+
+	for _, maintenance := range maintenances {
+		pool, ok := pools[maintenance.Pool]
+		if !ok {
+			pool = &NodePool{Name: maintenance.Pool}
+			pools[maintenance.Pool] = pool
+		}
+		since := maintenance.CreatedAt
+		pool.Maintenance = true
+		pool.Reason = maintenance.Reason
+		pool.Since = &since
+	}
+
+	result := make([]NodePool, 0, len(pools))
+	for _, pool := range pools {
+		result = append(result, *pool)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+
+	return result, nil
+}
+
+// CheckNodePoolCapacity는 신규 VM을 배치할 수 있는 노드가 남아 있는지 확인합니다.
+// 유지보수 중이 아닌 풀의 노드와 풀 라벨이 없는 노드 중 하나라도 스케줄 가능하면 통과합니다.
+func (s *K8sService) CheckNodePoolCapacity() error {
+	pools, err := s.nodePoolService.MaintenancePools()
+	if err != nil {
+		return err
+	}
+	// 유지보수 중인 풀이 없으면 기존과 같이 스케줄러에 맡김
+	if len(pools) == 0 {
+		return nil
+	}
+
+	nodes, err := s.clientset.CoreV1().Nodes().List(s.baseContext(), metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list nodes: %v", err)
+	}
+
+	label := nodePoolLabel()
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if nodeSchedulable(node) && !slices.Contains(pools, node.Labels[label]) {
+			return nil
+		}
+	}
+
+	return ErrNoSchedulablePool
+}
+
+// maintenanceNodePools는 템플릿 렌더링에 사용할 유지보수 중인 풀 목록입니다.
+// 조회에 실패하면 VM 생성을 막지 않도록 제외 없이 렌더링합니다.
+func (s *K8sService) maintenanceNodePools() []string {
+	pools, err := s.nodePoolService.MaintenancePools()
+	if err != nil {
+		slog.Warn("node pools: failed to fetch maintenance, rendering without pool exclusion", "error", err)
+		return nil
+	}
+	return pools
+}
+
+// setNodePoolAffinity는 VirtualMachine 템플릿에 유지보수 중인 풀을 피하는 node affinity를 추가합니다.
+// 템플릿에 이미 있는 nodeSelectorTerms에는 조건을 덧붙입니다. (term은 OR, 조건은 AND로 평가됨)
+// 실행 중인 VMI에는 영향이 없고, 이후 시작/재생성되는 인스턴스부터 적용됩니다.
+func setNodePoolAffinity(objs []*unstructured.Unstructured, pools []string) {
+	if len(pools) == 0 {
+		return
+	}
+
+	values := make([]interface{}, 0, len(pools))
+	for _, pool := range pools {
+		values = append(values, pool)
+	}
+	expression := map[string]interface{}{
+		"key":      nodePoolLabel(),
+		"operator": "NotIn",
+		"values":   values,
+	}
+
+	path := []string{"spec", "template", "spec", "affinity", "nodeAffinity", "requiredDuringSchedulingIgnoredDuringExecution", "nodeSelectorTerms"}
+	for _, obj := range objs {
+		if obj.GetKind() != "VirtualMachine" {
+			continue
+		}
+
+		terms, _, _ := unstructured.NestedSlice(obj.Object, path...)
+		if len(terms) == 0 {
+			terms = []interface{}{map[string]interface{}{}}
+		}
+		for i, raw := range terms {
+			term, ok := raw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			expressions, _, _ := unstructured.NestedSlice(term, "matchExpressions")
+			term["matchExpressions"] = append(expressions, expression)
+			terms[i] = term
+		}
+		unstructured.SetNestedSlice(obj.Object, terms, path...)
+	}
+}
@@ -0,0 +1,341 @@
+package k8s_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+	"vm-controller/internal/config"
+
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// Operator 모드:
+// 각 VM을 UserVM 커스텀 리소스로 표현하고, 내장 컨트롤러가 이를 reconcile 합니다.
+// kubectl / GitOps 도구로 UserVM을 직접 관리할 수 있으며, REST API는 UserVM을 생성/수정하는 얇은 façade가 됩니다.
+// 이 파일은 UserVM 읽기/쓰기와 감시만 담당하고, reconcile(생성 검증, 쿼터, 승인)은 vm_lifecycle_service가 수행합니다.
+
+var gvrUserVM = schema.GroupVersionResource{Group: "cloud.vm-controller.io", Version: "v1alpha1", Resource: "uservms"}
+
+const userVMPasswordKey = "password"
+
+// 관리자가 승인한 생성 요청 ID (승인 대상 요금제의 UserVM은 이 요청이 승인된 경우에만 프로비저닝)
+const userVMApprovalAnnotation = "cloud.vm-controller.io/approval-id"
+
+// 여러 인스턴스 중 리더 한 대만 UserVM을 reconcile 하도록 쓰는 Lease
+const (
+	operatorLeaseName          = "vm-controller-operator"
+	operatorLeaseDuration      = 15 * time.Second
+	operatorLeaseRenewDeadline = 10 * time.Second
+	operatorLeaseRetryPeriod   = 2 * time.Second
+)
+
+// UserVM status.phase (VM 레코드가 생긴 뒤에는 VM 상태를 그대로 사용)
+const (
+	UserVMPhasePending         = "Pending"         // 일시적인 실패로 프로비저닝을 다시 시도하는 중
+	UserVMPhasePendingApproval = "PendingApproval" // 승인 대상 요금제: 관리자 승인 대기
+	UserVMPhaseRejected        = "Rejected"        // spec을 고치기 전에는 만들 수 없음 (검증, 쿼터, 이름 충돌)
+)
+
+// ErrUserVMExists는 같은 이름의 UserVM이 이미 있을 때 반환됩니다.
+var ErrUserVMExists = errors.New("VM name is already in use")
+
+// UserVMSpec은 UserVM 커스텀 리소스의 spec입니다.
+type UserVMSpec struct {
+	Namespace  string
+	Name       string
+	Image      string // 비우면 사용자의 VM 생성 기본값
+	Flavor     string // 비우면 사용자의 VM 생성 기본값
+	HostPrefix string
+	Running    bool
+
+	PasswordSecret    string // 비밀번호 Secret 이름 (spec.passwordSecretRef)
+	PasswordSecretKey string
+
+	ApprovalID uint      // 관리자가 승인한 생성 요청 (userVMApprovalAnnotation)
+	CreatedAt  time.Time // UserVM 생성 시각 (같은 이름으로 다시 만든 UserVM을 구분)
+}
+
+// StartOperator는 UserVM CRD를 설치하고 UserVM 변경을 감시합니다.
+// 변경은 모든 인스턴스가 큐에 쌓아 두고, Lease로 선출된 리더만 reconcile을 호출합니다. (리더가 바뀌어도 이벤트를 잃지 않음)
+func (s *K8sService) StartOperator(resync time.Duration, reconcile func(namespace, name string) error) error {
+	if err := s.installUserVMCRD(); err != nil {
+		return fmt.Errorf("failed to install UserVM CRD: %v", err)
+	}
+
+	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	factory := dynamicinformer.NewDynamicSharedInformerFactory(s.dynamicClient, resync)
+	informer := factory.ForResource(gvrUserVM).Informer()
+
+	enqueue := func(obj interface{}) {
+		if key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj); err == nil {
+			queue.Add(key)
+		}
+	}
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    enqueue,
+		UpdateFunc: func(_, newObj interface{}) { enqueue(newObj) },
+		DeleteFunc: enqueue,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to register UserVM event handler: %v", err)
+	}
+
+	lock, err := s.operatorLeaseLock()
+	if err != nil {
+		return err
+	}
+	election, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		Name:            operatorLeaseName,
+		LeaseDuration:   operatorLeaseDuration,
+		RenewDeadline:   operatorLeaseRenewDeadline,
+		RetryPeriod:     operatorLeaseRetryPeriod,
+		ReleaseOnCancel: true,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				s.log().Info("operator: acquired leadership, reconciling UserVMs", "lease", lock.Describe(), "identity", lock.Identity())
+				for s.processNextUserVM(ctx, queue, reconcile) {
+				}
+			},
+			OnStoppedLeading: func() {
+				s.log().Warn("operator: lost leadership, stopped reconciling UserVMs", "lease", lock.Describe(), "identity", lock.Identity())
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("invalid operator leader election config: %v", err)
+	}
+
+	stop := make(chan struct{})
+	factory.Start(stop)
+
+	go func() {
+		if !cache.WaitForCacheSync(stop, informer.HasSynced) {
+			s.log().Error("operator: failed to sync UserVM cache")
+			return
+		}
+		s.log().Info("operator: UserVM controller started", "lease", lock.Describe(), "identity", lock.Identity())
+
+		// Run은 리더십을 잃으면 반환하므로 다시 선출에 참여
+		for {
+			election.Run(context.Background())
+		}
+	}()
+
+	return nil
+}
+
+// installUserVMCRD는 UserVM CRD를 server-side apply로 설치합니다. 이전 버전이 설치한 CRD도 현재 스키마로 갱신됩니다.
+func (s *K8sService) installUserVMCRD() error {
+	objs, err := renderManifests("yaml-data/operator", nil, "")
+	if err != nil {
+		return err
+	}
+	for _, obj := range objs {
+		if action, detail := s.applyBootstrapObject(obj); action == BootstrapFailed || action == BootstrapSkipped {
+			return fmt.Errorf("%s %s: %s", obj.GetKind(), obj.GetName(), detail)
+		}
+	}
+	return nil
+}
+
+// operatorLeaseLock은 OPERATOR_LEASE_NAMESPACE의 Lease를 인스턴스(파드) 이름으로 잡는 lock입니다.
+func (s *K8sService) operatorLeaseLock() (*resourcelock.LeaseLock, error) {
+	identity, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read hostname for operator leader election: %v", err)
+	}
+	// 같은 파드 이름으로 재시작해도 이전 인스턴스와 구분되도록
+	identity += "_" + uuid.NewString()
+
+	return &resourcelock.LeaseLock{
+		LeaseMeta:  metav1.ObjectMeta{Name: operatorLeaseName, Namespace: config.Get().OperatorLeaseNamespace},
+		Client:     s.clientset.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{Identity: identity},
+	}, nil
+}
+
+// processNextUserVM은 큐에서 UserVM 하나를 꺼내 reconcile 합니다. 리더십을 잃었으면 항목을 큐에 되돌리고 false를 반환합니다.
+func (s *K8sService) processNextUserVM(ctx context.Context, queue workqueue.RateLimitingInterface, reconcile func(namespace, name string) error) bool {
+	item, shutdown := queue.Get()
+	if shutdown {
+		return false
+	}
+	defer queue.Done(item)
+
+	if ctx.Err() != nil {
+		// 처리 중인 항목을 다시 넣으면 Done 이후 큐로 돌아가 다음 리더가 처리
+		defer queue.Add(item)
+		return false
+	}
+
+	key := item.(string)
+	err := runRecovered("uservm.reconcile", key, func() error {
+		namespace, name, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			return err
+		}
+		return reconcile(namespace, name)
+	})
+
+	if err != nil {
+		s.log().Warn("operator: failed to reconcile UserVM", "key", key, "error", err)
+		queue.AddRateLimited(item)
+		return true
+	}
+
+	queue.Forget(item)
+	return true
+}
+
+// FetchUserVM은 UserVM spec을 조회합니다. UserVM이 없으면 nil을 반환합니다.
+func (s *K8sService) FetchUserVM(namespace, name string) (*UserVMSpec, error) {
+	obj, err := s.dynamicClient.Resource(gvrUserVM).Namespace(namespace).Get(s.baseContext(), name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return userVMSpecFrom(obj), nil
+}
+
+func userVMSpecFrom(obj *unstructured.Unstructured) *UserVMSpec {
+	spec := &UserVMSpec{Namespace: obj.GetNamespace(), Name: obj.GetName(), CreatedAt: obj.GetCreationTimestamp().Time}
+	spec.Image, _, _ = unstructured.NestedString(obj.Object, "spec", "image")
+	spec.Flavor, _, _ = unstructured.NestedString(obj.Object, "spec", "flavor")
+	spec.HostPrefix, _, _ = unstructured.NestedString(obj.Object, "spec", "hostPrefix")
+	spec.PasswordSecret, _, _ = unstructured.NestedString(obj.Object, "spec", "passwordSecretRef", "name")
+	spec.PasswordSecretKey, _, _ = unstructured.NestedString(obj.Object, "spec", "passwordSecretRef", "key")
+	if spec.PasswordSecretKey == "" {
+		spec.PasswordSecretKey = userVMPasswordKey
+	}
+
+	running, found, _ := unstructured.NestedBool(obj.Object, "spec", "running")
+	spec.Running = running || !found
+
+	if id, err := strconv.ParseUint(obj.GetAnnotations()[userVMApprovalAnnotation], 10, 64); err == nil {
+		spec.ApprovalID = uint(id)
+	}
+	return spec
+}
+
+// ReadUserVMPassword는 spec.passwordSecretRef가 가리키는 Secret에서 비밀번호를 읽습니다.
+func (s *K8sService) ReadUserVMPassword(spec *UserVMSpec) (string, error) {
+	secret, err := s.clientset.CoreV1().Secrets(spec.Namespace).Get(s.baseContext(), spec.PasswordSecret, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to read password secret %s: %v", spec.PasswordSecret, err)
+	}
+	password := string(secret.Data[spec.PasswordSecretKey])
+	if password == "" {
+		return "", fmt.Errorf("password secret %s has no key %s", spec.PasswordSecret, spec.PasswordSecretKey)
+	}
+	return password, nil
+}
+
+// UpdateUserVMStatus는 UserVM의 status 서브리소스를 갱신합니다. (실패는 로그만 남김)
+func (s *K8sService) UpdateUserVMStatus(namespace, name, phase string, nodePort int32, message string) {
+	patch := fmt.Sprintf(`{"status":{"phase":%q,"nodePort":%d,"message":%q}}`, phase, nodePort, message)
+	_, err := s.dynamicClient.Resource(gvrUserVM).Namespace(namespace).Patch(
+		s.baseContext(), name, types.MergePatchType, []byte(patch), metav1.PatchOptions{}, "status")
+	if err != nil && !apierrors.IsNotFound(err) {
+		s.log().Warn("operator: failed to update UserVM status", "namespace", namespace, "name", name, "error", err)
+	}
+}
+
+// ApplyUserVM은 REST API(façade)에서 UserVM과 비밀번호 Secret을 생성합니다.
+// 승인된 요청(spec.ApprovalID)이면 승인을 기다리던 기존 UserVM에 승인 ID만 표시합니다. 그 밖에 같은 이름이 있으면 ErrUserVMExists를 반환합니다.
+func (s *K8sService) ApplyUserVM(spec UserVMSpec, password string) error {
+	ctx := s.baseContext()
+	client := s.dynamicClient.Resource(gvrUserVM).Namespace(spec.Namespace)
+
+	annotations := map[string]interface{}{}
+	if spec.ApprovalID != 0 {
+		annotations[userVMApprovalAnnotation] = strconv.FormatUint(uint64(spec.ApprovalID), 10)
+	}
+
+	if _, err := client.Get(ctx, spec.Name, metav1.GetOptions{}); err == nil {
+		if spec.ApprovalID == 0 {
+			return ErrUserVMExists
+		}
+		patch := fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, userVMApprovalAnnotation, annotations[userVMApprovalAnnotation])
+		if _, err := client.Patch(ctx, spec.Name, types.MergePatchType, []byte(patch), metav1.PatchOptions{}); err != nil {
+			return fmt.Errorf("failed to mark UserVM as approved: %v", err)
+		}
+		return nil
+	} else if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to fetch UserVM: %v", err)
+	}
+
+	secretName := spec.Name + "-uservm-password"
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: spec.Namespace},
+		StringData: map[string]string{userVMPasswordKey: password},
+	}
+	if _, err := s.clientset.CoreV1().Secrets(spec.Namespace).Create(ctx, secret, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create password secret: %v", err)
+	}
+
+	userVM := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "cloud.vm-controller.io/v1alpha1",
+		"kind":       "UserVM",
+		"metadata": map[string]interface{}{
+			"name":        spec.Name,
+			"namespace":   spec.Namespace,
+			"annotations": annotations,
+		},
+		"spec": map[string]interface{}{
+			"image":             spec.Image,
+			"flavor":            spec.Flavor,
+			"hostPrefix":        spec.HostPrefix,
+			"running":           spec.Running,
+			"passwordSecretRef": map[string]interface{}{"name": secretName, "key": userVMPasswordKey},
+		},
+	}}
+
+	if _, err := client.Create(ctx, userVM, metav1.CreateOptions{}); err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			return ErrUserVMExists
+		}
+		return fmt.Errorf("failed to create UserVM: %v", err)
+	}
+	return nil
+}
+
+// SetUserVMRunning은 UserVM의 spec.running을 변경합니다.
+func (s *K8sService) SetUserVMRunning(namespace, name string, running bool) error {
+	patch := fmt.Sprintf(`{"spec":{"running":%t}}`, running)
+	_, err := s.dynamicClient.Resource(gvrUserVM).Namespace(namespace).Patch(
+		s.baseContext(), name, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to patch UserVM: %v", err)
+	}
+	return nil
+}
+
+// DeleteUserVM은 UserVM과 비밀번호 Secret을 삭제합니다. (VM 리소스 정리는 reconcile에서 수행)
+func (s *K8sService) DeleteUserVM(namespace, name string) error {
+	ctx := s.baseContext()
+
+	if err := s.dynamicClient.Resource(gvrUserVM).Namespace(namespace).Delete(ctx, name, deleteOptions("UserVM", nil)); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete UserVM: %v", err)
+	}
+	if err := s.clientset.CoreV1().Secrets(namespace).Delete(ctx, name+"-uservm-password", deleteOptions("Secret", nil)); err != nil && !apierrors.IsNotFound(err) {
+		s.log().Warn("operator: failed to delete password secret", "namespace", namespace, "name", name, "error", err)
+	}
+	return nil
+}
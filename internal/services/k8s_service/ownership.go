@@ -0,0 +1,47 @@
+package k8s_service
+
+import (
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// 플랫폼이 생성한 리소스임을 나타내는 라벨 (client-deployment 등 템플릿의 managed-by 라벨과 동일)
+const (
+	managedByLabel = "app.kubernetes.io/managed-by"
+	managedByValue = "vm-controller"
+	vmOwnerLabel   = "cloud.vm-controller.io/vm" // 리소스를 소유한 VM 이름
+)
+
+// ErrResourceConflict는 생성하려는 리소스와 같은 이름의 리소스가 다른 소유자의 것일 때 반환됩니다.
+var ErrResourceConflict = errors.New("resource already exists and is not owned by this resource set")
+
+// setOwnershipLabels는 생성할 객체에 플랫폼 소유 라벨을 붙입니다. vmName이 비어 있으면 VM 라벨은 생략합니다.
+func setOwnershipLabels(obj *unstructured.Unstructured, vmName string) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	labels[managedByLabel] = managedByValue
+	if vmName != "" {
+		labels[vmOwnerLabel] = vmName
+	}
+	obj.SetLabels(labels)
+}
+
+// checkOwnership은 이미 존재하는 객체가 생성하려던 객체와 같은 소유자의 것인지 확인합니다.
+// 플랫폼 라벨이 없거나 다른 VM의 라벨이 붙어 있으면 ErrResourceConflict를 반환합니다.
+func checkOwnership(existing, desired *unstructured.Unstructured) error {
+	have := existing.GetLabels()
+	want := desired.GetLabels()
+
+	for _, key := range []string{managedByLabel, vmOwnerLabel} {
+		if value, ok := want[key]; ok && have[key] != value {
+			return fmt.Errorf("%w: %s %s/%s has %s=%q (expected %q)",
+				ErrResourceConflict, existing.GetKind(), existing.GetNamespace(), existing.GetName(), key, have[key], value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package k8s_service
+
+import (
+	"context"
+	"fmt"
+	"vm-controller/internal/metrics"
+	"vm-controller/internal/models"
+)
+
+// putVMSubresource는 KubeVirt 서브리소스 API(restart, pause, unpause 등)를 호출합니다.
+// resource는 virtualmachines 또는 virtualmachineinstances 입니다.
+func (s *K8sService) putVMSubresource(ctx context.Context, resource, namespace, name, action string) (string, error) {
+	return s.putVMSubresourceBody(ctx, resource, namespace, name, action, []byte(`{}`))
+}
+
+// putVMSubresourceBody는 요청 본문이 필요한 서브리소스(addvolume, removevolume)를 호출합니다.
+func (s *K8sService) putVMSubresourceBody(ctx context.Context, resource, namespace, name, action string, body []byte) (string, error) {
+	path := fmt.Sprintf("/apis/subresources.kubevirt.io/v1/namespaces/%s/%s/%s/%s", namespace, resource, name, action)
+
+	err := s.clientset.Discovery().RESTClient().Put().
+		AbsPath(path).
+		SetHeader("Content-Type", "application/json").
+		Body(body).
+		Do(ctx).
+		Error()
+	if err != nil {
+		return path, fmt.Errorf("failed to %s VM: %w", action, err)
+	}
+
+	return path, nil
+}
+
+// PauseVM은 VM 인스턴스를 일시 정지합니다. 메모리 상태는 유지되고 vCPU만 멈춥니다.
+// 1. DB 상태를 Pausing으로 변경
+// 2. pause 서브리소스 호출
+// 3. Paused가 관측되면 DB 상태를 Paused로 변경
+func (s *K8sService) PauseVM(vm *models.VirtualMachine) error {
+	if err := s.vmService.UpdateVmStatus(vm.Name, models.VmStatusPausing); err != nil {
+		return fmt.Errorf("failed to update VM status to Pausing: %w", err)
+	}
+
+	path, err := s.putVMSubresource(s.baseContext(), "virtualmachineinstances", vm.Namespace, vm.Name, "pause")
+	if err != nil {
+		return err
+	}
+	s.recordVMPatch(vm.Name, "pause", path)
+
+	if err := s.waitForVMStatus(vm.Namespace, vm.Name, "Paused"); err != nil {
+		return fmt.Errorf("failed to wait for VM to pause: %v", err)
+	}
+
+	if err := s.vmService.UpdateVmStatus(vm.Name, models.VmStatusPaused); err != nil {
+		return fmt.Errorf("failed to update VM status to Paused: %w", err)
+	}
+
+	return nil
+}
+
+// UnpauseVM은 일시 정지된 VM 인스턴스를 다시 실행합니다.
+func (s *K8sService) UnpauseVM(vm *models.VirtualMachine) error {
+	path, err := s.putVMSubresource(s.baseContext(), "virtualmachineinstances", vm.Namespace, vm.Name, "unpause")
+	if err != nil {
+		return err
+	}
+	s.recordVMPatch(vm.Name, "unpause", path)
+
+	if err := s.waitForVMStatus(vm.Namespace, vm.Name, "Running"); err != nil {
+		return fmt.Errorf("failed to wait for VM to unpause: %v", err)
+	}
+
+	if err := s.vmService.UpdateVmStatus(vm.Name, models.VmStatusRunning); err != nil {
+		return fmt.Errorf("failed to update VM status to Running: %w", err)
+	}
+
+	return nil
+}
+
+// PauseVMAsync는 VM 일시 정지를 백그라운드로 실행합니다. (pause는 이미 정지된 경우 에러를 반환하므로 재시도하지 않음)
+func (s *K8sService) PauseVMAsync(vm *models.VirtualMachine, traceID string) uint {
+	return s.queueAsync(s.pauseVMOperation(vm), traceID)
+}
+
+func (s *K8sService) pauseVMOperation(vm *models.VirtualMachine) AsyncOperation {
+	return AsyncOperation{
+		Name:       "vm.pause",
+		Target:     "vm/" + vm.Name,
+		Tenant:     metrics.TenantLabelFor(vm.UserID),
+		Owner:      vm.UserID,
+		Run:        func() error { return s.PauseVM(vm) },
+		Compensate: s.markVMFailed(vm, "pause"),
+		OnSuccess:  s.markVMSucceeded(vm, "pause"),
+		payload:    asyncPayload{VM: vm.Name},
+	}
+}
+
+// UnpauseVMAsync는 VM 일시 정지 해제를 백그라운드로 실행합니다.
+func (s *K8sService) UnpauseVMAsync(vm *models.VirtualMachine, traceID string) uint {
+	return s.queueAsync(s.unpauseVMOperation(vm), traceID)
+}
+
+func (s *K8sService) unpauseVMOperation(vm *models.VirtualMachine) AsyncOperation {
+	return AsyncOperation{
+		Name:       "vm.unpause",
+		Target:     "vm/" + vm.Name,
+		Tenant:     metrics.TenantLabelFor(vm.UserID),
+		Owner:      vm.UserID,
+		Run:        func() error { return s.UnpauseVM(vm) },
+		Compensate: s.markVMFailed(vm, "unpause"),
+		OnSuccess:  s.markVMSucceeded(vm, "unpause"),
+		payload:    asyncPayload{VM: vm.Name},
+	}
+}
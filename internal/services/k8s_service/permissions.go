@@ -0,0 +1,177 @@
+package k8s_service
+
+import (
+	"context"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RequiredPermission은 컨트롤러가 수행하는 K8s API 작업 한 개입니다.
+// 사용자 네임스페이스마다 작업하므로 모든 네임스페이스(클러스터 범위)를 기준으로 확인합니다.
+type RequiredPermission struct {
+	Feature     string `json:"feature"` // 이 권한이 필요한 기능
+	Group       string `json:"group"`
+	Resource    string `json:"resource"`
+	Subresource string `json:"subresource,omitempty"`
+	Verb        string `json:"verb"`
+}
+
+// requiredPermissions는 컨트롤러가 호출하는 모든 API 작업 목록입니다.
+// 새 리소스/동작을 사용하는 코드를 추가하면 여기에도 추가해야 합니다.
+var requiredPermissions = []RequiredPermission{
+	// 사용자 네임스페이스 초기화 (yaml-data/client-init)
+	{"namespace", "", "namespaces", "", "list"},
+	{"namespace", "", "namespaces", "", "get"},
+	{"namespace", "", "namespaces", "", "create"},
+	{"namespace", "", "namespaces", "", "patch"},
+	{"namespace", "", "resourcequotas", "", "create"},
+	{"namespace", "", "limitranges", "", "create"},
+	{"namespace", "networking.k8s.io", "networkpolicies", "", "create"},
+
+	// VM 생성/시작/정지/삭제
+	{"vm", "kubevirt.io", "virtualmachines", "", "list"},
+	{"vm", "kubevirt.io", "virtualmachines", "", "get"},
+	{"vm", "kubevirt.io", "virtualmachines", "", "create"},
+	{"vm", "kubevirt.io", "virtualmachines", "", "patch"},
+	{"vm", "kubevirt.io", "virtualmachines", "", "delete"},
+	{"vm", "kubevirt.io", "virtualmachineinstances", "", "list"},
+	{"vm", "kubevirt.io", "virtualmachineinstances", "", "get"},
+	{"vm", "", "secrets", "", "create"},
+	{"vm", "", "secrets", "", "delete"},
+	{"vm", "", "services", "", "create"},
+	{"vm", "", "services", "", "get"},
+	{"vm", "", "services", "", "delete"},
+	{"vm", "networking.k8s.io", "ingresses", "", "create"},
+	{"vm", "networking.k8s.io", "ingresses", "", "get"},
+	{"vm", "networking.k8s.io", "ingresses", "", "delete"},
+
+	// 재시작 / 일시 정지 / 콘솔 (KubeVirt 서브리소스)
+	{"vm.lifecycle", "subresources.kubevirt.io", "virtualmachines", "restart", "update"},
+	{"vm.lifecycle", "subresources.kubevirt.io", "virtualmachines", "stop", "update"},
+	{"vm.lifecycle", "subresources.kubevirt.io", "virtualmachineinstances", "pause", "update"},
+	{"vm.lifecycle", "subresources.kubevirt.io", "virtualmachineinstances", "unpause", "update"},
+	{"vm.console", "subresources.kubevirt.io", "virtualmachineinstances", "console", "get"},
+	{"disk.hotplug", "subresources.kubevirt.io", "virtualmachines", "addvolume", "update"},
+	{"disk.hotplug", "subresources.kubevirt.io", "virtualmachines", "removevolume", "update"},
+	{"vm.console", "subresources.kubevirt.io", "virtualmachineinstances", "vnc", "get"},
+
+	// 라이브 마이그레이션 (노드 드레인)
+	{"vm.migrate", "kubevirt.io", "virtualmachineinstancemigrations", "", "create"},
+	{"vm.migrate", "kubevirt.io", "virtualmachineinstancemigrations", "", "list"},
+
+	// 디스크 (DataVolume, 업로드)
+	{"disk", "cdi.kubevirt.io", "datavolumes", "", "list"},
+	{"disk", "cdi.kubevirt.io", "datavolumes", "", "get"},
+	{"disk", "cdi.kubevirt.io", "datavolumes", "", "create"},
+	{"disk", "cdi.kubevirt.io", "datavolumes", "", "delete"},
+	{"disk.upload", "upload.cdi.kubevirt.io", "uploadtokenrequests", "", "create"},
+	{"disk.upload", "", "configmaps", "", "get"},
+
+	// 디스크 내보내기 / 스냅샷
+	{"disk.export", "export.kubevirt.io", "virtualmachineexports", "", "create"},
+	{"disk.export", "export.kubevirt.io", "virtualmachineexports", "", "get"},
+	{"disk.export", "", "secrets", "", "get"},
+	{"disk.snapshot", "snapshot.kubevirt.io", "virtualmachinesnapshots", "", "create"},
+	{"disk.snapshot", "snapshot.kubevirt.io", "virtualmachinesnapshots", "", "get"},
+	{"disk.snapshot", "snapshot.kubevirt.io", "virtualmachinesnapshots", "", "delete"},
+	{"disk.snapshot", "snapshot.kubevirt.io", "virtualmachinerestores", "", "create"},
+	{"disk.snapshot", "snapshot.kubevirt.io", "virtualmachinerestores", "", "get"},
+	{"disk.snapshot", "snapshot.kubevirt.io", "virtualmachinerestores", "", "delete"},
+
+	// 배포 / 빌드 / 크론잡 / 관리형 DB
+	{"deployment", "apps", "deployments", "", "create"},
+	{"deployment", "apps", "deployments", "", "get"},
+	{"deployment", "apps", "deployments", "", "patch"},
+	{"deployment", "apps", "deployments", "", "delete"},
+	{"deployment", "batch", "jobs", "", "create"},
+	{"deployment", "batch", "jobs", "", "list"},
+	{"deployment", "batch", "cronjobs", "", "create"},
+	{"deployment", "batch", "cronjobs", "", "patch"},
+	{"deployment", "batch", "cronjobs", "", "delete"},
+	{"deployment", "", "pods", "", "list"},
+	{"deployment", "", "pods", "log", "get"},
+	{"database", "apps", "statefulsets", "", "create"},
+	{"database", "apps", "statefulsets", "", "delete"},
+	{"database", "", "secrets", "", "update"},
+
+	// 사용량 / 용량 (CPU 포화, 스토리지 통계, VM 리소스 사용량)
+	{"metrics", "", "nodes", "", "list"},
+	{"metrics", "", "nodes", "proxy", "get"},
+	{"metrics", "", "persistentvolumeclaims", "", "list"},
+	{"metrics", "", "persistentvolumeclaims", "", "get"},
+	{"metrics", "", "pods", "", "list"},
+	{"metrics", "metrics.k8s.io", "pods", "", "get"},
+
+	// 관리자 정책 (ValidatingAdmissionPolicy)
+	{"admission", "admissionregistration.k8s.io", "validatingadmissionpolicies", "", "get"},
+	{"admission", "admissionregistration.k8s.io", "validatingadmissionpolicies", "", "create"},
+	{"admission", "admissionregistration.k8s.io", "validatingadmissionpolicies", "", "delete"},
+	{"admission", "admissionregistration.k8s.io", "validatingadmissionpolicybindings", "", "get"},
+	{"admission", "admissionregistration.k8s.io", "validatingadmissionpolicybindings", "", "create"},
+	{"admission", "admissionregistration.k8s.io", "validatingadmissionpolicybindings", "", "delete"},
+}
+
+// operator 모드(OPERATOR_MODE=true)에서만 필요한 권한
+var operatorPermissions = []RequiredPermission{
+	{"operator", "apiextensions.k8s.io", "customresourcedefinitions", "", "create"},
+	{"operator", "apiextensions.k8s.io", "customresourcedefinitions", "", "patch"},
+	{"operator", "cloud.vm-controller.io", "uservms", "", "get"},
+	{"operator", "cloud.vm-controller.io", "uservms", "", "create"},
+	{"operator", "cloud.vm-controller.io", "uservms", "", "list"},
+	{"operator", "cloud.vm-controller.io", "uservms", "", "watch"},
+	{"operator", "cloud.vm-controller.io", "uservms", "", "patch"},
+	{"operator", "cloud.vm-controller.io", "uservms", "status", "patch"},
+	{"operator", "coordination.k8s.io", "leases", "", "get"},
+	{"operator", "coordination.k8s.io", "leases", "", "create"},
+	{"operator", "coordination.k8s.io", "leases", "", "update"},
+}
+
+// PermissionCheck는 한 작업에 대한 SelfSubjectAccessReview 결과입니다.
+type PermissionCheck struct {
+	RequiredPermission
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason,omitempty"`
+	Error   string `json:"error,omitempty"` // 리뷰 요청 자체가 실패한 경우
+}
+
+// CheckPermissions는 컨트롤러의 서비스 계정으로 필요한 모든 작업을 SelfSubjectAccessReview로 확인합니다.
+// 개발 환경에서는 되지만 운영 환경에서 403이 나는 RBAC 차이를 찾는 데 사용합니다.
+func (s *K8sService) CheckPermissions(ctx context.Context, includeOperator bool) []PermissionCheck {
+	permissions := requiredPermissions
+	if includeOperator {
+		permissions = append(append([]RequiredPermission{}, requiredPermissions...), operatorPermissions...)
+	}
+
+	checks := make([]PermissionCheck, 0, len(permissions))
+	for _, permission := range permissions {
+		check := PermissionCheck{RequiredPermission: permission}
+
+		review := &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace:   metav1.NamespaceAll,
+					Group:       permission.Group,
+					Resource:    permission.Resource,
+					Subresource: permission.Subresource,
+					Verb:        permission.Verb,
+				},
+			},
+		}
+
+		result, err := s.clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+		if err != nil {
+			check.Error = err.Error()
+		} else {
+			check.Allowed = result.Status.Allowed
+			check.Reason = result.Status.Reason
+			if result.Status.EvaluationError != "" {
+				check.Error = result.Status.EvaluationError
+			}
+		}
+
+		checks = append(checks, check)
+	}
+
+	return checks
+}
@@ -0,0 +1,88 @@
+package k8s_service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"vm-controller/internal/models"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const podSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
+
+// Pod Security Standards 레벨 (느슨한 순)
+var podSecurityLevels = []string{"privileged", "baseline", "restricted"}
+
+// IsValidPodSecurityLevel은 PSS 레벨 값인지 확인합니다.
+func IsValidPodSecurityLevel(level string) bool {
+	for _, valid := range podSecurityLevels {
+		if level == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultPodSecurityLevel은 사용자 권한에 따른 네임스페이스 기본 PSS 레벨을 반환합니다.
+// POD_SECURITY_LEVEL_USER / POD_SECURITY_LEVEL_ADMIN 으로 변경할 수 있습니다.
+func defaultPodSecurityLevel(role string) string {
+	envKey, fallback := "POD_SECURITY_LEVEL_USER", "baseline"
+	if role == models.RoleAdmin {
+		envKey, fallback = "POD_SECURITY_LEVEL_ADMIN", "privileged"
+	}
+
+	if level := os.Getenv(envKey); IsValidPodSecurityLevel(level) {
+		return level
+	}
+	return fallback
+}
+
+// clientInitReplacements는 client-init 템플릿(네임스페이스, 기본 정책)의 치환 값을 만듭니다.
+func (s *K8sService) clientInitReplacements(userNamespace string) map[string]string {
+	role := models.RoleUser
+	if user, err := s.userService.FetchUserByNamespace(userNamespace); err == nil {
+		role = user.Role
+	}
+
+	return map[string]string{
+		"{{NAMESPACE}}":          userNamespace,
+		"{{POD_SECURITY_LEVEL}}": defaultPodSecurityLevel(role),
+	}
+}
+
+// GetPodSecurityLevel은 네임스페이스에 적용된 PSS enforce 레벨을 반환합니다. (라벨이 없으면 빈 문자열)
+func (s *K8sService) GetPodSecurityLevel(namespace string) (string, error) {
+	ns, err := s.clientset.CoreV1().Namespaces().Get(s.baseContext(), namespace, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get namespace %s: %v", namespace, err)
+	}
+
+	return ns.Labels[podSecurityEnforceLabel], nil
+}
+
+// SetPodSecurityLevel은 관리자가 네임스페이스의 PSS enforce 레벨을 변경하고 감사 로그를 남깁니다.
+func (s *K8sService) SetPodSecurityLevel(namespace, level string, actorId uint) error {
+	if !IsValidPodSecurityLevel(level) {
+		return fmt.Errorf("invalid pod security level: %s (privileged, baseline, restricted)", level)
+	}
+
+	previous, err := s.GetPodSecurityLevel(namespace)
+	if err != nil {
+		return err
+	}
+
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"labels": map[string]string{podSecurityEnforceLabel: level}},
+	})
+	if _, err := s.clientset.CoreV1().Namespaces().Patch(s.baseContext(), namespace, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to label namespace %s: %v", namespace, err)
+	}
+
+	if err := s.auditService.Record(&actorId, "namespace.pod-security.update", "namespace/"+namespace, fmt.Sprintf("%s -> %s", previous, level)); err != nil {
+		s.log().Warn("pod security: failed to record audit log", "namespace", namespace, "error", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package k8s_service
+
+import (
+	"context"
+	"time"
+	"vm-controller/internal/config"
+	"vm-controller/internal/models"
+)
+
+// K8sProvisioner는 VM 리소스의 생성과 시작/중지/삭제를 다루는 클러스터 의존 부분입니다.
+// VmLifecycleService와 VM 컨트롤러는 이 인터페이스만 사용하므로, 클러스터 없이 실행해야 하는 곳(테스트 등)에서는
+// k8sfake.Provisioner로 바꿔 주입합니다.
+type K8sProvisioner interface {
+	// ProvisionerWithContext는 호출이 ctx를 따르는 Provisioner를 반환합니다. (K8sService.WithContext)
+	ProvisionerWithContext(ctx context.Context) K8sProvisioner
+	// ProvisionerWithRequest는 ctx의 요청 ID만 물려받는 Provisioner를 반환합니다. (K8sService.WithRequest)
+	ProvisionerWithRequest(ctx context.Context) K8sProvisioner
+
+	CreateUserVM(userNamespace, vmName, password, dnsHost, manifestDir, flavorName, imageName string, vmPort int32, networks []models.VmNetwork, sshKeys []string, cloudInit string) (*VMInfo, error)
+	RollbackUserVM(vmInfo *VMInfo) error
+	CheckNodePoolCapacity() error
+	StartVM(vm *models.VirtualMachine) error
+	StopVM(vm *models.VirtualMachine) error
+	RestartVM(vm *models.VirtualMachine, timeout time.Duration) error
+	DeleteVM(vm *models.VirtualMachine) error
+
+	// 백그라운드 작업: 작업 ID를 반환하고 결과는 VM 상태로 반영
+	StartVMAsync(vm *models.VirtualMachine, traceID string) uint
+	StopVMAsync(vm *models.VirtualMachine, traceID string) uint
+	DeleteVMAsync(vm *models.VirtualMachine, traceID string) uint
+	DeleteVMWithPolicyAsync(vm *models.VirtualMachine, policy config.DeletePolicy, traceID string) uint
+
+	// Operator 모드의 UserVM 리소스
+	StartOperator(resync time.Duration, reconcile func(namespace, name string) error) error
+	FetchUserVM(namespace, name string) (*UserVMSpec, error)
+	ReadUserVMPassword(spec *UserVMSpec) (string, error)
+	UpdateUserVMStatus(namespace, name, phase string, nodePort int32, message string)
+	ApplyUserVM(spec UserVMSpec, password string) error
+	SetUserVMRunning(namespace, name string, running bool) error
+	DeleteUserVM(namespace, name string) error
+}
+
+var _ K8sProvisioner = (*K8sService)(nil)
+
+func (s *K8sService) ProvisionerWithContext(ctx context.Context) K8sProvisioner {
+	return s.WithContext(ctx)
+}
+
+func (s *K8sService) ProvisionerWithRequest(ctx context.Context) K8sProvisioner {
+	return s.WithRequest(ctx)
+}
@@ -0,0 +1,388 @@
+package k8s_service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+	"vm-controller/internal/config"
+	"vm-controller/internal/logger"
+	"vm-controller/internal/metrics"
+	"vm-controller/internal/models"
+	"vm-controller/internal/vmstate"
+)
+
+// 워커가 작업을 점유하는 시간. 실행 중에는 asyncLease/3마다 연장하고, 연장되지 않은 채 지나면(서버 종료 등) 다른 워커가 이어서 실행합니다.
+const asyncLease = time.Minute
+
+// 워커가 실행할 작업을 확인하는 간격 (같은 인스턴스의 RunAsync는 대기 중인 워커를 바로 깨움)
+const asyncPollInterval = 2 * time.Second
+
+// errOperationDone은 이어서 실행하려던 작업의 대상이 이미 삭제되어 남은 일이 없을 때 반환됩니다. (삭제 작업이 끝난 뒤 서버가 종료된 경우 등)
+var errOperationDone = errors.New("nothing left to do")
+
+// asyncPayload는 워커가 작업을 다시 만들 때 필요한 인자입니다. (operations.payload, JSON)
+// 레코드는 이름/ID로 다시 조회하므로 비밀번호 등 민감한 값은 저장하지 않습니다.
+type asyncPayload struct {
+	VM             string               `json:"vm,omitempty"`
+	Volume         string               `json:"volume,omitempty"`
+	Snapshot       string               `json:"snapshot,omitempty"`
+	Path           string               `json:"path,omitempty"`   // 업로드할 이미지 파일
+	Policy         *config.DeletePolicy `json:"policy,omitempty"` // 관리자가 지정한 삭제 옵션
+	DeploymentID   uint                 `json:"deployment_id,omitempty"`
+	Namespace      string               `json:"namespace,omitempty"`
+	DatabaseID     uint                 `json:"database_id,omitempty"`
+	FlavorChangeID uint                 `json:"flavor_change_id,omitempty"`
+}
+
+type asyncOperationBuilder func(s *K8sService, payload asyncPayload) (AsyncOperation, error)
+
+// asyncOperationBuilders는 작업 이름별로 기록된 인자에서 작업을 다시 만드는 함수입니다. (RunAsync로 넣는 작업은 모두 등록되어야 함)
+var asyncOperationBuilders = map[string]asyncOperationBuilder{
+	"vm.stop":          vmOperationBuilder((*K8sService).stopVMOperation),
+	"vm.start":         vmOperationBuilder((*K8sService).startVMOperation),
+	"vm.pause":         vmOperationBuilder((*K8sService).pauseVMOperation),
+	"vm.unpause":       vmOperationBuilder((*K8sService).unpauseVMOperation),
+	"vm.recreate":      vmOperationBuilder((*K8sService).recreateVMOperation),
+	"vm.delete":        buildDeleteVMOperation,
+	"vm.upload":        buildUploadDiskOperation,
+	"vm.restore":       buildRestoreSnapshotOperation,
+	"vm.flavor":        buildFlavorChangeOperation,
+	"vm.volume.attach": volumeOperationBuilder(false, (*K8sService).attachVolumeOperation),
+	"vm.volume.detach": volumeOperationBuilder(true, (*K8sService).detachVolumeOperation),
+	"deployment.build": buildDeploymentBuildOperation,
+	"database.create":  databaseOperationBuilder(false, (*K8sService).createDatabaseOperation),
+	"database.delete":  databaseOperationBuilder(true, (*K8sService).deleteDatabaseOperation),
+}
+
+// loadOperationVM은 작업 대상 VM을 다시 조회합니다. 없으면 deleting이면 errOperationDone, 아니면 에러를 반환합니다.
+func (s *K8sService) loadOperationVM(name string, deleting bool) (*models.VirtualMachine, error) {
+	vm, err := s.vmService.FetchVmName(name, true)
+	if err != nil {
+		return nil, err
+	}
+	if vm == nil {
+		if deleting {
+			return nil, errOperationDone
+		}
+		return nil, fmt.Errorf("vm %s not found", name)
+	}
+	return vm, nil
+}
+
+func vmOperationBuilder(build func(s *K8sService, vm *models.VirtualMachine) AsyncOperation) asyncOperationBuilder {
+	return func(s *K8sService, payload asyncPayload) (AsyncOperation, error) {
+		vm, err := s.loadOperationVM(payload.VM, false)
+		if err != nil {
+			return AsyncOperation{}, err
+		}
+		return build(s, vm), nil
+	}
+}
+
+func buildDeleteVMOperation(s *K8sService, payload asyncPayload) (AsyncOperation, error) {
+	vm, err := s.loadOperationVM(payload.VM, true)
+	if err != nil {
+		return AsyncOperation{}, err
+	}
+	return s.deleteVMOperation(vm, payload.Policy), nil
+}
+
+func buildUploadDiskOperation(s *K8sService, payload asyncPayload) (AsyncOperation, error) {
+	vm, err := s.loadOperationVM(payload.VM, false)
+	if err != nil {
+		return AsyncOperation{}, err
+	}
+	return s.uploadDiskOperation(vm, payload.Path), nil
+}
+
+func buildRestoreSnapshotOperation(s *K8sService, payload asyncPayload) (AsyncOperation, error) {
+	vm, err := s.loadOperationVM(payload.VM, false)
+	if err != nil {
+		return AsyncOperation{}, err
+	}
+	snapshot, err := s.snapshotService.FetchSnapshot(vm.Name, payload.Snapshot)
+	if err != nil {
+		return AsyncOperation{}, err
+	}
+	if snapshot == nil {
+		return AsyncOperation{}, fmt.Errorf("snapshot %s not found", payload.Snapshot)
+	}
+	return s.restoreSnapshotOperation(vm, snapshot), nil
+}
+
+func buildFlavorChangeOperation(s *K8sService, payload asyncPayload) (AsyncOperation, error) {
+	vm, err := s.loadOperationVM(payload.VM, false)
+	if err != nil {
+		return AsyncOperation{}, err
+	}
+	change, err := s.flavorChangeService.FetchChange(vm.Name, payload.FlavorChangeID)
+	if err != nil {
+		return AsyncOperation{}, err
+	}
+	to, err := GetFlavor(s.flavorService, change.ToFlavor)
+	if err != nil {
+		return AsyncOperation{}, err
+	}
+	return s.flavorChangeOperation(vm, change, to), nil
+}
+
+func volumeOperationBuilder(deleting bool, build func(s *K8sService, vm *models.VirtualMachine, volume *models.VolumeAttachment) AsyncOperation) asyncOperationBuilder {
+	return func(s *K8sService, payload asyncPayload) (AsyncOperation, error) {
+		vm, err := s.loadOperationVM(payload.VM, false)
+		if err != nil {
+			return AsyncOperation{}, err
+		}
+		volume, err := s.volumeService.FetchVolume(vm.Name, payload.Volume)
+		if err != nil {
+			return AsyncOperation{}, err
+		}
+		if volume == nil {
+			if deleting {
+				return AsyncOperation{}, errOperationDone
+			}
+			return AsyncOperation{}, fmt.Errorf("volume %s not found", payload.Volume)
+		}
+		return build(s, vm, volume), nil
+	}
+}
+
+func buildDeploymentBuildOperation(s *K8sService, payload asyncPayload) (AsyncOperation, error) {
+	deployment, err := s.deploymentService.FetchDeploymentById(payload.DeploymentID)
+	if err != nil {
+		return AsyncOperation{}, err
+	}
+	if deployment == nil {
+		return AsyncOperation{}, fmt.Errorf("deployment %d not found", payload.DeploymentID)
+	}
+	return s.buildDeploymentOperation(deployment, payload.Namespace), nil
+}
+
+func databaseOperationBuilder(deleting bool, build func(s *K8sService, database *models.ManagedDatabase) AsyncOperation) asyncOperationBuilder {
+	return func(s *K8sService, payload asyncPayload) (AsyncOperation, error) {
+		database, err := s.databaseService.FetchDatabaseById(payload.DatabaseID, true)
+		if err != nil {
+			return AsyncOperation{}, err
+		}
+		if database == nil {
+			if deleting {
+				return AsyncOperation{}, errOperationDone
+			}
+			return AsyncOperation{}, fmt.Errorf("database %d not found", payload.DatabaseID)
+		}
+		return build(s, database), nil
+	}
+}
+
+// rebuildOperation은 작업 기록의 이름과 인자로 작업을 다시 만듭니다.
+func (s *K8sService) rebuildOperation(operation *models.Operation) (AsyncOperation, error) {
+	build, ok := asyncOperationBuilders[operation.Name]
+	if !ok {
+		return AsyncOperation{}, fmt.Errorf("operation %s is not registered", operation.Name)
+	}
+
+	var payload asyncPayload
+	if err := json.Unmarshal([]byte(operation.Payload), &payload); err != nil {
+		return AsyncOperation{}, fmt.Errorf("invalid payload: %w", err)
+	}
+
+	op, err := build(s, payload)
+	if err != nil {
+		return AsyncOperation{}, err
+	}
+	if op.Tenant == "" {
+		op.Tenant = metrics.TenantNone
+	}
+	op.TraceID = operation.TraceID
+	op.RequestID = operation.RequestID
+	return op, nil
+}
+
+// operationInFlight는 대상에 대기 중이거나 실행 중인 작업이 있는지 확인합니다. (모든 서버 인스턴스 기준)
+// 확인하지 못하면 작업 중인 것으로 보아 converger 등이 이번 주기에 건드리지 않도록 합니다.
+func (s *K8sService) operationInFlight(target string) bool {
+	active, err := s.operationService.HasActiveOperation(target)
+	if err != nil {
+		slog.Warn("async: failed to check operations in flight", "target", target, "error", err)
+		return true
+	}
+	return active
+}
+
+func asyncWorkerHost() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		return "unknown"
+	}
+	return host
+}
+
+// wakeAsyncWorkers는 대기 중인 워커 하나를 깨웁니다. (모두 실행 중이면 다음 확인 주기에 실행)
+func (s *K8sService) wakeAsyncWorkers() {
+	select {
+	case s.asyncWake <- struct{}{}:
+	default:
+	}
+}
+
+// StartAsyncWorkers는 작업 큐(operations 테이블)를 처리하는 워커를 ASYNC_WORKERS개 시작합니다.
+// 서버 재시작 전에 남은 Queued 작업과, 점유한 워커가 사라져 lease가 만료된 Running 작업도 이어서 실행합니다.
+func (s *K8sService) StartAsyncWorkers() {
+	host := asyncWorkerHost()
+
+	for i := 0; i < asyncWorkerCount(); i++ {
+		worker := fmt.Sprintf("%s/%d", host, i)
+
+		go func() {
+			ticker := time.NewTicker(asyncPollInterval)
+			defer ticker.Stop()
+
+			for {
+				// 실행할 작업이 없을 때까지 이어서 실행
+				for s.runNextOperation(worker) {
+				}
+
+				select {
+				case <-s.asyncWake:
+				case <-ticker.C:
+				}
+			}
+		}()
+	}
+
+	// 역압 판단에 쓰는 큐 깊이 (다른 인스턴스가 넣은 작업 포함)
+	go func() {
+		ticker := time.NewTicker(asyncPollInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if depth, err := s.operationService.CountActiveOperations(); err == nil {
+				asyncDepth.Store(depth)
+			}
+		}
+	}()
+}
+
+// runNextOperation은 실행할 작업 하나를 점유하여 한 번 시도합니다. 실행할 작업이 없으면 false를 반환합니다.
+func (s *K8sService) runNextOperation(worker string) bool {
+	operation, err := s.operationService.ClaimOperation(worker, asyncLease)
+	if err != nil {
+		slog.Error("async: failed to claim operation", "worker", worker, "error", err)
+		return false
+	}
+	if operation == nil {
+		return false
+	}
+
+	// 작업 로그를 요청한 API 요청의 ID로 남김
+	bound := s.WithContext(logger.WithRequest(context.Background(), operation.RequestID, operation.TraceID))
+	bound.runOperation(worker, operation)
+	return true
+}
+
+// runOperation은 점유한 작업의 한 번의 시도를 실행합니다.
+// 실패하고 재시도가 남아 있으면 다음 시도 시각과 함께 Queued로 되돌려, 대기하는 동안 워커 슬롯을 다른 작업에 넘깁니다.
+func (s *K8sService) runOperation(worker string, operation *models.Operation) {
+	op, err := s.rebuildOperation(operation)
+	if errors.Is(err, errOperationDone) {
+		s.finishOperation(operation.ID, models.OperationSucceeded, "")
+		return
+	}
+	if err != nil {
+		slog.Error("async: operation cannot be resumed", "operation_id", operation.ID, "operation", operation.Name, "target", operation.Target, "error", err)
+		asyncOperationsTotal.Inc(operation.Name, "failed", metrics.TenantNone)
+		s.finishOperation(operation.ID, models.OperationFailed, "cannot be resumed: "+err.Error())
+		return
+	}
+
+	attempt := operation.Attempts
+	if attempt > op.MaxRetries+1 {
+		// 마지막으로 허용된 시도 중에 서버가 종료됨 (반복하면 안 되는 작업은 MaxRetries가 0)
+		s.failOperation(op, operation.ID, errors.New("interrupted by server restart"))
+		return
+	}
+	if attempt == 1 {
+		asyncQueueWaitSeconds.Add(time.Since(operation.CreatedAt).Seconds(), op.Name)
+		asyncQueueStarted.Inc(op.Name)
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go s.renewOperationLease(operation.ID, worker, stop)
+
+	// 요청이 끝난 뒤 다른 인스턴스에서 실행될 수도 있으므로 요청의 자식 span이 아닌 새 root span으로 기록 (시도마다 하나)
+	span := startAsyncSpan(op)
+
+	err = runRecovered(op.Name, op.Target, op.Run)
+	if err == nil {
+		clusterErrors.record(time.Now(), false)
+		asyncOperationsTotal.Inc(op.Name, "success", op.Tenant)
+		s.finishOperation(operation.ID, models.OperationSucceeded, "")
+		span.end("success", nil)
+
+		if op.OnSuccess != nil {
+			if errSuccess := runRecovered(op.Name+".success", op.Target, func() error {
+				op.OnSuccess()
+				return nil
+			}); errSuccess != nil {
+				slog.Error("async operation success hook failed", append(span.attrs(), "error", errSuccess.Error())...)
+			}
+		}
+		return
+	}
+	slog.Warn("async operation attempt failed", append(span.attrs(), "attempt", attempt, "error", err.Error())...)
+
+	// 상태 머신이 거부한 작업은 재시도/보상하지 않음 (다른 요청이 먼저 상태를 바꾼 경우)
+	var illegal *vmstate.IllegalTransitionError
+	if errors.As(err, &illegal) {
+		asyncOperationsTotal.Inc(op.Name, "rejected", op.Tenant)
+		s.finishOperation(operation.ID, models.OperationRejected, err.Error())
+		span.end("rejected", err)
+		return
+	}
+	// 클러스터 에러율 (역압 판단에 사용)
+	clusterErrors.record(time.Now(), true)
+
+	if attempt <= op.MaxRetries {
+		delay := asyncRetryBaseDelay * time.Duration(1<<(attempt-1))
+		slog.Info("async operation retrying", append(span.attrs(), "delay", delay.String(), "attempt", attempt+1, "max_retries", op.MaxRetries)...)
+		if errRetry := s.operationService.RetryOperation(operation.ID, err.Error(), time.Now().Add(delay)); errRetry != nil {
+			slog.Error("async: failed to requeue operation", "operation_id", operation.ID, "error", errRetry)
+		}
+		span.end("retrying", err)
+		return
+	}
+
+	s.failOperation(op, operation.ID, err)
+	span.end("failed", err)
+}
+
+// failOperation은 모든 시도가 실패한 작업을 Failed로 기록하고 보상 작업을 실행합니다.
+func (s *K8sService) failOperation(op AsyncOperation, id uint, err error) {
+	asyncOperationsTotal.Inc(op.Name, "failed", op.Tenant)
+	s.finishOperation(id, models.OperationFailed, err.Error())
+	compensate(op, err)
+}
+
+// renewOperationLease는 stop이 닫힐 때까지 작업의 점유를 연장합니다.
+func (s *K8sService) renewOperationLease(id uint, worker string, stop <-chan struct{}) {
+	ticker := time.NewTicker(asyncLease / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			renewed, err := s.operationService.RenewLease(id, worker, asyncLease)
+			if err != nil {
+				slog.Warn("async: failed to renew operation lease", "operation_id", id, "worker", worker, "error", err)
+			} else if !renewed {
+				slog.Warn("async: operation lease was taken over by another worker", "operation_id", id, "worker", worker)
+			}
+		}
+	}
+}
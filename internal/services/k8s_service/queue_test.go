@@ -0,0 +1,227 @@
+package k8s_service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"vm-controller/internal/db"
+	"vm-controller/internal/models"
+	operationservice "vm-controller/internal/services/operation_service"
+	vmservice "vm-controller/internal/services/vm_service"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestQueue는 메모리 SQLite DB를 작업 큐로 쓰는 K8sService입니다. (클러스터/Postgres 없이 워커 동작만 확인)
+func newTestQueue(t *testing.T) (*K8sService, *gorm.DB) {
+	t.Helper()
+
+	conn, err := gorm.Open(sqlite.Open("file:"+strings.ReplaceAll(t.Name(), "/", "_")+"?mode=memory&cache=shared"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.Migrate(conn); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	previous := db.DB
+	db.DB = conn
+	t.Cleanup(func() {
+		db.DB = previous
+		if sqlDB, err := conn.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	return newK8sService(Dependencies{
+		OperationService: operationservice.NewOperationService(),
+		VmService:        vmservice.NewVmService(),
+	}), conn
+}
+
+// testOperation은 run의 결과를 차례로 반환하는 작업을 test.op 이름으로 등록합니다.
+type testOperation struct {
+	results     []error
+	runs        int
+	compensated error
+	succeeded   bool
+}
+
+func registerTestOperation(t *testing.T, maxRetries int, results ...error) *testOperation {
+	t.Helper()
+	test := &testOperation{results: results}
+	asyncOperationBuilders["test.op"] = func(s *K8sService, payload asyncPayload) (AsyncOperation, error) {
+		return test.operation(payload.VM, maxRetries), nil
+	}
+	t.Cleanup(func() { delete(asyncOperationBuilders, "test.op") })
+	return test
+}
+
+func (test *testOperation) operation(name string, maxRetries int) AsyncOperation {
+	return AsyncOperation{
+		Name:   "test.op",
+		Target: "vm/" + name,
+		Run: func() error {
+			err := test.results[test.runs]
+			test.runs++
+			return err
+		},
+		Compensate: func(err error) { test.compensated = err },
+		OnSuccess:  func() { test.succeeded = true },
+		MaxRetries: maxRetries,
+		payload:    asyncPayload{VM: name},
+	}
+}
+
+func fetchTestOperation(t *testing.T, s *K8sService, id uint) *models.Operation {
+	t.Helper()
+	operation, err := s.operationService.FetchOperation(id)
+	if err != nil {
+		t.Fatalf("FetchOperation(%d): %v", id, err)
+	}
+	return operation
+}
+
+// 실패한 시도는 다음 시도 시각과 함께 Queued로 돌아가 워커를 놓고, 그 시각이 지나면 다시 점유되어야 함
+func TestRunAsyncRetriesFromQueue(t *testing.T) {
+	s, conn := newTestQueue(t)
+	test := registerTestOperation(t, 1, errors.New("apiserver unavailable"), nil)
+
+	id := s.RunAsync(test.operation("lab-1", 1))
+	if id == 0 {
+		t.Fatal("RunAsync returned 0, want a queued operation")
+	}
+	if operation := fetchTestOperation(t, s, id); operation.Status != models.OperationQueued || operation.Payload != `{"vm":"lab-1"}` {
+		t.Fatalf("queued = %+v, want Queued with the payload", operation)
+	}
+
+	// 같은 대상의 작업은 끝날 때까지 건너뜀
+	if skipped := s.RunAsync(test.operation("lab-1", 1)); skipped != 0 {
+		t.Errorf("duplicate RunAsync = %d, want 0 (skipped without trace id)", skipped)
+	}
+
+	if !s.runNextOperation("worker-a") {
+		t.Fatal("runNextOperation found nothing to run")
+	}
+	operation := fetchTestOperation(t, s, id)
+	if operation.Status != models.OperationQueued || operation.NextRunAt == nil || operation.Worker != "" || operation.Attempts != 1 {
+		t.Fatalf("after failed attempt = %+v, want Queued with a next run time and no worker", operation)
+	}
+	if operation.Error != "apiserver unavailable" {
+		t.Errorf("error = %q, want the failed attempt's reason", operation.Error)
+	}
+
+	// 재시도 시각 전에는 점유하지 않음
+	if s.runNextOperation("worker-a") {
+		t.Fatal("operation was claimed before its next run time")
+	}
+	conn.Model(&models.Operation{}).Where("id = ?", id).Update("next_run_at", time.Now().Add(-time.Second))
+
+	if !s.runNextOperation("worker-b") {
+		t.Fatal("runNextOperation did not claim the due retry")
+	}
+	operation = fetchTestOperation(t, s, id)
+	if operation.Status != models.OperationSucceeded || operation.Attempts != 2 || operation.Worker != "worker-b" {
+		t.Errorf("after retry = %+v, want Succeeded on the second attempt by worker-b", operation)
+	}
+	if !test.succeeded || test.compensated != nil {
+		t.Errorf("succeeded = %v, compensated = %v; want only OnSuccess", test.succeeded, test.compensated)
+	}
+}
+
+func TestRunAsyncCompensatesAfterLastAttempt(t *testing.T) {
+	s, _ := newTestQueue(t)
+	test := registerTestOperation(t, 0, errors.New("admission webhook denied the request"))
+
+	id := s.RunAsync(test.operation("lab-1", 0))
+	s.runNextOperation("worker-a")
+
+	if operation := fetchTestOperation(t, s, id); operation.Status != models.OperationFailed || operation.FinishedAt == nil {
+		t.Fatalf("operation = %+v, want Failed", operation)
+	}
+	if test.compensated == nil {
+		t.Error("Compensate was not called")
+	}
+
+	// 끝난 작업은 대상을 점유하지 않음
+	if s.operationInFlight("vm/lab-1") {
+		t.Error("target is still in flight after the operation failed")
+	}
+}
+
+// 점유한 워커가 lease를 갱신하지 못하면(서버 종료) 다른 워커가 이어서 실행하고,
+// 재시도할 수 없는 작업은 다시 실행하지 않고 실패로 보상해야 함
+func TestAbandonedOperationsAreResumed(t *testing.T) {
+	s, conn := newTestQueue(t)
+	operations := s.operationService
+
+	expired := time.Now().Add(-time.Second)
+	abandon := func(name string) uint {
+		operation := &models.Operation{
+			Name: "test.op", Target: "vm/" + name, Status: models.OperationRunning, Attempts: 1,
+			Payload: `{"vm":"` + name + `"}`, Worker: "crashed/0", LeaseUntil: &expired,
+		}
+		if err := operations.CreateActiveOperation(operation); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		return operation.ID
+	}
+
+	retryable := registerTestOperation(t, 2, nil)
+	id := abandon("lab-1")
+	s.runNextOperation("worker-a")
+	if operation := fetchTestOperation(t, s, id); operation.Status != models.OperationSucceeded || operation.Attempts != 2 {
+		t.Fatalf("resumed = %+v, want Succeeded on the second attempt", operation)
+	}
+	if retryable.runs != 1 {
+		t.Errorf("runs = %d, want 1", retryable.runs)
+	}
+
+	once := registerTestOperation(t, 0, nil)
+	id = abandon("lab-2")
+	s.runNextOperation("worker-a")
+	if operation := fetchTestOperation(t, s, id); operation.Status != models.OperationFailed || operation.Error != "interrupted by server restart" {
+		t.Fatalf("non-retryable = %+v, want Failed as interrupted", operation)
+	}
+	if once.runs != 0 || once.compensated == nil {
+		t.Errorf("runs = %d, compensated = %v; want no run and compensation", once.runs, once.compensated)
+	}
+
+	// lease가 남아 있는 작업은 다른 워커가 가져가지 않음
+	leaseUntil := time.Now().Add(time.Minute)
+	conn.Create(&models.Operation{Name: "test.op", Target: "vm/lab-3", Status: models.OperationRunning, Payload: `{}`, Worker: "alive/0", LeaseUntil: &leaseUntil})
+	if s.runNextOperation("worker-a") {
+		t.Error("claimed an operation whose lease has not expired")
+	}
+}
+
+func TestRebuildOperation(t *testing.T) {
+	s, _ := newTestQueue(t)
+
+	// 삭제 작업의 대상이 이미 없으면 남은 일이 없음
+	_, err := s.rebuildOperation(&models.Operation{Name: "vm.delete", Payload: `{"vm":"gone","policy":{"propagation":"Foreground"}}`})
+	if !errors.Is(err, errOperationDone) {
+		t.Errorf("vm.delete of a deleted VM: err = %v, want errOperationDone", err)
+	}
+	if _, err := s.rebuildOperation(&models.Operation{Name: "vm.stop", Payload: `{"vm":"gone"}`}); err == nil || errors.Is(err, errOperationDone) {
+		t.Errorf("vm.stop of a missing VM: err = %v, want a not found error", err)
+	}
+
+	// 등록되지 않은 작업(동기 작업의 점유 기록 등)은 이어서 실행할 수 없음
+	id := s.RunAsync(AsyncOperation{Name: "vm.unknown", Target: "vm/lab-1"})
+	if id != 0 {
+		t.Errorf("RunAsync of an unregistered operation = %d, want 0", id)
+	}
+	leaseUntil := time.Now().Add(-time.Second)
+	operation := &models.Operation{Name: "vm.restart", Target: "vm/lab-1", Status: models.OperationRunning, Attempts: 1, LeaseUntil: &leaseUntil}
+	if err := s.operationService.CreateActiveOperation(operation); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	s.runNextOperation("worker-a")
+	if got := fetchTestOperation(t, s, operation.ID); got.Status != models.OperationFailed || !strings.HasPrefix(got.Error, "cannot be resumed") {
+		t.Errorf("abandoned restart = %+v, want Failed as cannot be resumed", got)
+	}
+}
@@ -0,0 +1,186 @@
+package k8s_service
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"vm-controller/internal/metrics"
+	"vm-controller/internal/models"
+	"vm-controller/internal/vmstate"
+)
+
+// 클러스터에 VirtualMachine이 없는 Provisioning VM을 실패로 보기까지 기다리는 시간
+// (생성 작업이 도중에 죽은 경우. 생성 직후에는 목록에 아직 없을 수 있음)
+const reconcileProvisioningGrace = 30 * time.Minute
+
+// KubeVirt가 VM을 시작하지 못했음을 나타내는 printableStatus
+var failedPrintableStatuses = map[string]bool{
+	"CrashLoopBackOff":        true,
+	"ErrorUnschedulable":      true,
+	"ErrImagePull":            true,
+	"ImagePullBackOff":        true,
+	"ErrorPvcNotFound":        true,
+	"ErrorDataVolumeNotFound": true,
+	"DataVolumeError":         true,
+}
+
+var reconcileCorrections = metrics.NewCounterVec("vm_reconcile_corrections_total", "DB/cluster drift corrected by the VM reconciler.", "kind")
+
+// UnmanagedVM은 클러스터에는 있지만 DB에 (삭제되지 않은) VM 레코드가 없는 VirtualMachine입니다.
+type UnmanagedVM struct {
+	Namespace       string    `json:"namespace"`
+	Name            string    `json:"name"`
+	PrintableStatus string    `json:"printable_status"`
+	Managed         bool      `json:"managed"` // managed-by 라벨이 있으면 이 컨트롤러가 만들고 DB에서 잃어버린 리소스
+	FirstSeenAt     time.Time `json:"first_seen_at"`
+}
+
+var (
+	unmanagedMu        sync.Mutex
+	unmanagedVMs       = map[string]UnmanagedVM{}
+	unmanagedCheckedAt time.Time
+)
+
+// StartVMReconciler는 주기적으로 DB의 모든 VM을 클러스터의 VirtualMachine과 비교하여 어긋난 상태를 바로잡습니다.
+// converger는 목표 상태를 향해 작업을 실행하고, reconciler는 작업 goroutine이 죽어 DB에 남은 잘못된 상태를 고칩니다.
+func (s *K8sService) StartVMReconciler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			runRecovered("vm.reconciler", "*", func() error {
+				return s.reconcileVMs()
+			})
+		}
+	}()
+}
+
+func (s *K8sService) reconcileVMs() error {
+	vms, err := s.vmService.FetchUnconvergedVMs()
+	if err != nil {
+		return fmt.Errorf("failed to fetch VMs: %v", err)
+	}
+
+	observed, err := s.listObservedVMs()
+	if err != nil {
+		return err
+	}
+
+	known := make(map[string]bool, len(vms))
+	for i := range vms {
+		vm := &vms[i]
+		known[vm.Namespace+"/"+vm.Name] = true
+
+		// 실행 중인 작업이 있거나 삭제 중이면 건너뜀 (삭제는 converger가 재시도)
+		if s.operationInFlight("vm/"+vm.Name) || vm.DesiredState == models.VmDesiredDeleted {
+			continue
+		}
+
+		cluster, exists := observed[vm.Namespace+"/"+vm.Name]
+		if !exists {
+			if vm.Status != models.VmStatusProvisioning || time.Since(vm.CreatedAt) > reconcileProvisioningGrace {
+				s.reconcileVMStatus(vm, models.VmStatusFailed, "orphaned")
+			}
+			continue
+		}
+
+		if status, ok := observedVMStatus(cluster); ok {
+			s.reconcileVMStatus(vm, status, "status")
+		}
+	}
+
+	s.recordUnmanagedVMs(observed, known)
+	return nil
+}
+
+// observedVMStatus는 클러스터에서 관측한 상태를 DB 상태로 바꿉니다.
+// 시작/정지 중처럼 곧 바뀔 상태는 판단하지 않습니다. (ok = false)
+func observedVMStatus(cluster observedVM) (models.EnumVmStatus, bool) {
+	switch {
+	case failedPrintableStatuses[cluster.PrintableStatus]:
+		return models.VmStatusFailed, true
+	case cluster.PrintableStatus == "Running" && cluster.Running:
+		return models.VmStatusRunning, true
+	case cluster.PrintableStatus == "Paused" && cluster.Running:
+		return models.VmStatusPaused, true
+	case cluster.PrintableStatus == "Stopped" && !cluster.Running:
+		return models.VmStatusStopped, true
+	}
+	return "", false
+}
+
+// reconcileVMStatus는 DB 상태를 관측된 상태로 맞춥니다. 직접 전이가 허용되지 않으면 중간 상태를 거칩니다.
+func (s *K8sService) reconcileVMStatus(vm *models.VirtualMachine, status models.EnumVmStatus, kind string) {
+	if vm.Status == status {
+		return
+	}
+
+	path := vmstate.Path(vm.Status, status)
+	if path == nil {
+		slog.Warn("vm reconciler: no transition path", "vm", vm.Name, "from", vm.Status, "to", status)
+		return
+	}
+
+	for _, next := range path {
+		if err := s.vmService.UpdateVmStatus(vm.Name, next); err != nil {
+			slog.Error("vm reconciler: failed to update status", "vm", vm.Name, "status", next, "error", err)
+			return
+		}
+	}
+
+	slog.Info("vm reconciler: corrected status", "vm", vm.Name, "from", vm.Status, "to", status, "kind", kind)
+	reconcileCorrections.Inc(kind)
+}
+
+// recordUnmanagedVMs는 DB에 없는 클러스터 VM을 기록합니다. 자동으로 삭제하지 않고 관리자 확인용으로만 표시합니다.
+func (s *K8sService) recordUnmanagedVMs(observed map[string]observedVM, known map[string]bool) {
+	unmanagedMu.Lock()
+	defer unmanagedMu.Unlock()
+
+	now := time.Now()
+	current := map[string]UnmanagedVM{}
+	for key, cluster := range observed {
+		if known[key] {
+			continue
+		}
+
+		namespace, name, _ := strings.Cut(key, "/")
+		unmanaged, seen := unmanagedVMs[key]
+		if !seen {
+			unmanaged.FirstSeenAt = now
+			s.log().Warn("vm reconciler: found unmanaged cluster VM", "vm", key)
+			reconcileCorrections.Inc("unmanaged")
+		}
+		unmanaged.Namespace = namespace
+		unmanaged.Name = name
+		unmanaged.PrintableStatus = cluster.PrintableStatus
+		unmanaged.Managed = cluster.Managed
+		current[key] = unmanaged
+	}
+
+	unmanagedVMs = current
+	unmanagedCheckedAt = now
+}
+
+// ListUnmanagedVMs는 마지막 reconcile에서 발견한 DB에 없는 클러스터 VM과 확인 시각을 반환합니다.
+func (s *K8sService) ListUnmanagedVMs() ([]UnmanagedVM, time.Time) {
+	unmanagedMu.Lock()
+	defer unmanagedMu.Unlock()
+
+	result := make([]UnmanagedVM, 0, len(unmanagedVMs))
+	for _, unmanaged := range unmanagedVMs {
+		result = append(result, unmanaged)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		return result[i].Name < result[j].Name
+	})
+
+	return result, unmanagedCheckedAt
+}
@@ -0,0 +1,225 @@
+package k8s_service
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"vm-controller/internal/logger"
+	"vm-controller/internal/metrics"
+	"vm-controller/internal/models"
+	bundleservice "vm-controller/internal/services/bundle_service"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// maskedPassword는 렌더링 미리보기에서 비밀번호 대신 들어가는 값입니다.
+const maskedPassword = "********"
+
+// GenerateMACAddress는 로컬 관리(locally administered) 유니캐스트 MAC 주소를 생성합니다.
+func GenerateMACAddress() string {
+	mac := make([]byte, 6)
+	rand.Read(mac)
+	mac[0] = (mac[0] | 0x02) & 0xfe
+
+	return fmt.Sprintf("%02x:%02x:%02x:%02x:%02x:%02x", mac[0], mac[1], mac[2], mac[3], mac[4], mac[5])
+}
+
+// vmReplacements는 VM 템플릿(yaml-data/client-vm)에 치환할 값을 만듭니다.
+func vmReplacements(vmInfo *VMInfo) map[string]string {
+	replacements := map[string]string{
+		"{{NAMESPACE}}":   vmInfo.Namespace,
+		"{{NODEPORT}}":    fmt.Sprintf("%d", vmInfo.Port),
+		"{{VM_NAME}}":     vmInfo.Name,
+		"{{DNS_HOST}}":    vmInfo.DNSHost,
+		"{{PASSWORD}}":    vmInfo.Password,
+		"{{MAC_ADDRESS}}": vmInfo.MacAddress,
+
+		"{{SSH_AUTHORIZED_KEYS}}": sshKeysReplacement(vmInfo.SSHKeys),
+	}
+
+	for key, value := range VMNames(vmInfo.Name).replacements() {
+		replacements[key] = value
+	}
+	for key, value := range flavorReplacements(vmInfo.Flavor) {
+		replacements[key] = value
+	}
+	for key, value := range imageReplacements(vmInfo.Image) {
+		replacements[key] = value
+	}
+	for key, value := range networkReplacements(vmInfo) {
+		replacements[key] = value
+	}
+	return replacements
+}
+
+// sshKeysReplacement는 공개 키 목록을 cloud-init userdata 한 줄에 들어가는 YAML flow sequence(JSON 배열)로 만듭니다.
+func sshKeysReplacement(keys []string) string {
+	if keys == nil {
+		keys = []string{}
+	}
+	encoded, _ := json.Marshal(keys)
+	return string(encoded)
+}
+
+// checkSSHKeys는 템플릿에 주입할 공개 키가 한 줄짜리 authorized_keys 항목인지 확인합니다.
+func checkSSHKeys(keys []string) error {
+	for _, key := range keys {
+		if key == "" || strings.ContainsAny(key, "\r\n") || strings.Contains(key, "{{") {
+			return fmt.Errorf("invalid ssh public key")
+		}
+	}
+	return nil
+}
+
+// renderVMManifests는 VM 템플릿을 렌더링하고 사용자 cloud-config를 userdata에 병합합니다.
+// runStrategy가 true면 spec.running 을 runStrategy로 바꿉니다. 유지보수 중인 노드 풀은 node affinity로 제외합니다.
+func (s *K8sService) renderVMManifests(manifestDir string, vmInfo *VMInfo, runStrategy bool) ([]*unstructured.Unstructured, error) {
+	objs, err := renderManifests(manifestDir, vmReplacements(vmInfo), vmInfo.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := mergeCloudInit(objs, vmInfo.CloudInit); err != nil {
+		return nil, err
+	}
+	if runStrategy {
+		setRunStrategy(objs)
+	}
+	// 유지보수 중인 노드 풀에는 새 인스턴스를 배치하지 않음
+	setNodePoolAffinity(objs, s.maintenanceNodePools())
+	// 네임스페이스 쿼터에서 VM 파드를 일반 파드와 구분
+	setVMPriorityClass(objs)
+	// 재시도 중 이미 만들어진 리소스를 이 VM의 것으로 확인할 수 있도록 소유 라벨 부착
+	for _, obj := range objs {
+		setOwnershipLabels(obj, vmInfo.Name)
+	}
+	return objs, nil
+}
+
+// storedVMInfo는 DB에 저장된 VM 정보로 템플릿 치환에 쓸 VMInfo와 템플릿 디렉터리를 만듭니다.
+func (s *K8sService) storedVMInfo(vm *models.VirtualMachine) (*VMInfo, string, error) {
+	flavor, err := GetFlavor(s.flavorService, vm.Flavor)
+	if err != nil {
+		return nil, "", err
+	}
+	// 요금제가 수정되어도 기존 VM의 디스크는 생성 당시 크기를 유지
+	if vm.DiskGi > 0 {
+		flavor.DiskGi = vm.DiskGi
+	}
+
+	// 카탈로그에 없는 이미지(카탈로그 이전에 만든 VM)는 기본 이미지의 원본 디스크 사용
+	image, err := GetImage(s.imageService, vm.Image)
+	if err != nil {
+		if image, err = GetImage(s.imageService, DefaultImage); err != nil {
+			return nil, "", err
+		}
+	}
+	// 게스트 사용자는 생성 당시 값을 유지
+	image.SSHUser = VMSSHUser(vm)
+
+	vmInfo := &VMInfo{
+		Namespace:  vm.Namespace,
+		Name:       vm.Name,
+		Port:       vm.NodePort,
+		Password:   vm.Password,
+		DNSHost:    vm.DnsHost,
+		MacAddress: vm.MacAddress,
+		Flavor:     flavor,
+		Image:      image,
+		Networks:   vm.Networks,
+		SSHKeys:    vm.SSHKeys,
+		CloudInit:  vm.CloudInit,
+	}
+	// 생성 시 사용한 번들로 재생성
+	template := bundleservice.VMTemplate
+	if vm.Image == UploadImage {
+		template = bundleservice.UploadVMTemplate
+	}
+	manifestDir := s.bundleService.TemplateDir(vm.BundleChannel, template)
+
+	return vmInfo, manifestDir, nil
+}
+
+// RenderVMManifests는 VM에 적용되는 매니페스트를 클러스터에 적용하지 않고 렌더링합니다.
+// 비밀번호는 마스킹하며, MAC 주소가 아직 기록되지 않은 VM은 해당 필드가 비어 있습니다.
+func (s *K8sService) RenderVMManifests(vm *models.VirtualMachine) ([]map[string]interface{}, error) {
+	vmInfo, manifestDir, err := s.storedVMInfo(vm)
+	if err != nil {
+		return nil, err
+	}
+	vmInfo.Password = maskedPassword
+
+	objs, err := s.renderVMManifests(manifestDir, vmInfo, s.usesRunStrategy())
+	if err != nil {
+		return nil, err
+	}
+
+	manifests := make([]map[string]interface{}, 0, len(objs))
+	for _, obj := range objs {
+		manifests = append(manifests, obj.Object)
+	}
+	return manifests, nil
+}
+
+// RecreateVM은 DB에 저장된 식별 정보(MAC 주소, 호스트 이름, NodePort, DNS)로 VM 리소스를 다시 생성합니다.
+// 이미 존재하는 리소스(디스크 등)는 그대로 두고 사라진 리소스만 생성하므로 재시도해도 안전합니다.
+// 에러는 작업 기록(VM 이벤트)에 남으므로 비밀번호를 가려서 반환합니다.
+func (s *K8sService) RecreateVM(vm *models.VirtualMachine) error {
+	return logger.RedactError(s.recreateVM(vm), vm.Password)
+}
+
+func (s *K8sService) recreateVM(vm *models.VirtualMachine) error {
+	if vm.DnsHost == "" {
+		return fmt.Errorf("vm %s has no stored hostname", vm.Name)
+	}
+
+	// 식별 정보가 기록되기 전에 생성된 VM은 이번에 발급한 MAC 주소를 이후 재생성에서도 사용
+	if vm.MacAddress == "" {
+		vm.MacAddress = GenerateMACAddress()
+		if err := s.vmService.UpdateVmMacAddress(vm.Name, vm.MacAddress); err != nil {
+			return fmt.Errorf("failed to store mac address: %v", err)
+		}
+	}
+
+	vmInfo, manifestDir, err := s.storedVMInfo(vm)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkInjection(vmInfo.Namespace, vmInfo.Name, vmInfo.Password, vmInfo.DNSHost, manifestDir, vmInfo.Port); err != nil {
+		return err
+	}
+
+	objs, err := s.renderVMManifests(manifestDir, vmInfo, s.usesRunStrategy())
+	if err != nil {
+		return fmt.Errorf("failed to render vm resources: %v", err)
+	}
+
+	created, err := s.applyObjects(objs, true)
+	if err != nil {
+		return fmt.Errorf("failed to recreate vm resources: %v", err)
+	}
+	s.recordVMPatch(vm.Name, "recreate", fmt.Sprintf("recreated %d resources from %s with mac %s", len(created), manifestDir, vm.MacAddress))
+
+	return nil
+}
+
+// RecreateVMAsync는 VM 재생성을 백그라운드로 실행합니다.
+func (s *K8sService) RecreateVMAsync(vm *models.VirtualMachine, traceID string) uint {
+	return s.queueAsync(s.recreateVMOperation(vm), traceID)
+}
+
+func (s *K8sService) recreateVMOperation(vm *models.VirtualMachine) AsyncOperation {
+	return AsyncOperation{
+		Name:       "vm.recreate",
+		Target:     "vm/" + vm.Name,
+		Tenant:     metrics.TenantLabelFor(vm.UserID),
+		Owner:      vm.UserID,
+		Run:        func() error { return s.RecreateVM(vm) },
+		Compensate: s.markVMFailed(vm, "recreate"),
+		OnSuccess:  s.markVMSucceeded(vm, "recreate"),
+		MaxRetries: 2,
+		payload:    asyncPayload{VM: vm.Name},
+	}
+}
@@ -0,0 +1,136 @@
+package k8s_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+	"vm-controller/internal/logger"
+	"vm-controller/internal/models"
+	operationservice "vm-controller/internal/services/operation_service"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// 재시작 후 Running이 될 때까지 기다리는 기본 시간 (VM_RESTART_TIMEOUT, ROUTE_CREATE_TIMEOUT보다 짧아야 함)
+const defaultRestartTimeout = 45 * time.Second
+
+// ErrRestartTimeout은 재시작 요청은 전달되었지만 제한 시간 안에 Running으로 돌아오지 않았을 때 반환됩니다.
+// VM 상태는 Restarting으로 남고, 이후 converger가 관측된 상태를 반영합니다.
+var ErrRestartTimeout = errors.New("timeout waiting for VM to become Running after restart")
+
+// RestartTimeout은 재시작 대기 시간을 반환합니다.
+func RestartTimeout() time.Duration {
+	if timeout, err := time.ParseDuration(os.Getenv("VM_RESTART_TIMEOUT")); err == nil && timeout > 0 {
+		return timeout
+	}
+	return defaultRestartTimeout
+}
+
+// RestartVM은 KubeVirt restart 서브리소스로 VM을 재부팅하고, 새 인스턴스가 Running이 될 때까지 기다립니다.
+// 정지 후 시작과 달리 목표 상태(Running)는 바뀌지 않습니다.
+func (s *K8sService) RestartVM(vm *models.VirtualMachine, timeout time.Duration) error {
+	// converger/다른 인스턴스의 작업과 동시에 실행되지 않도록 작업 기록으로 대상 점유
+	// (이 서버가 종료되어 점유가 만료되면 워커가 이어서 실행할 수 없는 작업으로 실패 처리)
+	now := time.Now()
+	leaseUntil := now.Add(timeout + asyncLease)
+	operation := &models.Operation{
+		Name:       "vm.restart",
+		Target:     "vm/" + vm.Name,
+		UserID:     &vm.UserID,
+		Status:     models.OperationRunning,
+		Attempts:   1,
+		TraceID:    logger.TraceIDFrom(s.baseContext()),
+		RequestID:  logger.RequestIDFrom(s.baseContext()),
+		StartedAt:  &now,
+		Worker:     asyncWorkerHost(),
+		LeaseUntil: &leaseUntil,
+	}
+	if err := s.operationService.CreateActiveOperation(operation); err != nil {
+		if errors.Is(err, operationservice.ErrOperationInFlight) {
+			return fmt.Errorf("another operation is in flight for vm %s", vm.Name)
+		}
+		return fmt.Errorf("failed to record operation: %w", err)
+	}
+
+	err := s.restartVM(vm, timeout)
+	if err != nil {
+		s.finishOperation(operation.ID, models.OperationFailed, err.Error())
+		return err
+	}
+	s.finishOperation(operation.ID, models.OperationSucceeded, "")
+	return nil
+}
+
+// restartVM은 대상 점유 없이 VM을 재시작합니다. (이미 대상을 점유한 백그라운드 작업에서 호출)
+func (s *K8sService) restartVM(vm *models.VirtualMachine, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(s.baseContext(), timeout)
+	defer cancel()
+
+	// 재시작 전 인스턴스 UID (새 인스턴스가 뜬 것을 구분하기 위함)
+	var previousUID types.UID
+	if vmi, err := s.dynamicClient.Resource(gvrVMI).Namespace(vm.Namespace).Get(ctx, vm.Name, metav1.GetOptions{}); err == nil {
+		previousUID = vmi.GetUID()
+	} else if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get VM instance: %v", err)
+	}
+
+	previousStatus := vm.Status
+	if err := s.vmService.UpdateVmStatus(vm.Name, models.VmStatusRestarting); err != nil {
+		return fmt.Errorf("failed to update VM status to Restarting: %w", err)
+	}
+
+	path, err := s.putVMSubresource(ctx, "virtualmachines", vm.Namespace, vm.Name, "restart")
+	if err != nil {
+		// 재시작이 시작되지 않았으므로 이전 상태로 되돌림
+		if errStatus := s.vmService.UpdateVmStatus(vm.Name, previousStatus); errStatus != nil {
+			s.log().Error("restart: failed to revert VM status", "vm", vm.Name, "status", previousStatus, "error", errStatus)
+		}
+		return err
+	}
+	s.recordVMPatch(vm.Name, "restart", path)
+
+	if err := s.waitForRestartedVMI(ctx, vm, previousUID); err != nil {
+		return err
+	}
+
+	if err := s.vmService.UpdateVmStatus(vm.Name, models.VmStatusRunning); err != nil {
+		return fmt.Errorf("failed to update VM status to Running: %w", err)
+	}
+	return nil
+}
+
+// waitForRestartedVMI는 이전과 다른(UID가 바뀐) 인스턴스가 Running이 될 때까지 기다립니다.
+// 기존 인스턴스가 아직 남아 있는 동안 Running으로 오인하지 않기 위해 UID를 비교합니다.
+func (s *K8sService) waitForRestartedVMI(ctx context.Context, vm *models.VirtualMachine, previousUID types.UID) error {
+	ticker := time.NewTicker(3 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ErrRestartTimeout
+		case <-ticker.C:
+			vmi, err := s.dynamicClient.Resource(gvrVMI).Namespace(vm.Namespace).Get(ctx, vm.Name, metav1.GetOptions{})
+			if err != nil {
+				if apierrors.IsNotFound(err) || ctx.Err() != nil {
+					continue
+				}
+				return fmt.Errorf("failed to get VM instance: %v", err)
+			}
+
+			if vmi.GetUID() == previousUID {
+				continue
+			}
+
+			phase, _, _ := unstructured.NestedString(vmi.Object, "status", "phase")
+			if phase == "Running" {
+				return nil
+			}
+		}
+	}
+}
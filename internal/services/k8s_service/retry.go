@@ -0,0 +1,262 @@
+package k8s_service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"time"
+	"vm-controller/internal/logger"
+	"vm-controller/internal/metrics"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+// 쿠버네티스 API 호출 재시도 기본값
+const (
+	defaultK8sRetryAttempts  = 4                      // K8S_RETRY_MAX_ATTEMPTS (첫 시도 포함)
+	defaultK8sRetryBaseDelay = 200 * time.Millisecond // K8S_RETRY_BASE_DELAY
+	defaultK8sRetryMaxDelay  = 5 * time.Second        // K8S_RETRY_MAX_DELAY
+	defaultK8sRequestTimeout = 30 * time.Second       // K8S_REQUEST_TIMEOUT (시도 1회당)
+)
+
+var k8sRetriesTotal = metrics.NewCounterVec("k8s_request_retries_total", "Kubernetes API calls retried after a transient error.", "verb", "reason")
+
+// retryPolicy는 일시적인 API 서버 오류에 대한 재시도 횟수와 지수 백오프 간격입니다.
+type retryPolicy struct {
+	attempts  int
+	baseDelay time.Duration
+	maxDelay  time.Duration
+	timeout   time.Duration // 호출 1회 제한 시간 (0이면 호출자의 컨텍스트만 따름)
+}
+
+func loadRetryPolicy() retryPolicy {
+	policy := retryPolicy{
+		attempts:  intEnv("K8S_RETRY_MAX_ATTEMPTS", defaultK8sRetryAttempts),
+		baseDelay: defaultK8sRetryBaseDelay,
+		maxDelay:  defaultK8sRetryMaxDelay,
+		timeout:   defaultK8sRequestTimeout,
+	}
+	if policy.attempts < 1 {
+		policy.attempts = 1
+	}
+	if delay, err := time.ParseDuration(os.Getenv("K8S_RETRY_BASE_DELAY")); err == nil && delay > 0 {
+		policy.baseDelay = delay
+	}
+	if delay, err := time.ParseDuration(os.Getenv("K8S_RETRY_MAX_DELAY")); err == nil && delay > 0 {
+		policy.maxDelay = delay
+	}
+	if timeout, err := time.ParseDuration(os.Getenv("K8S_REQUEST_TIMEOUT")); err == nil && timeout >= 0 {
+		policy.timeout = timeout
+	}
+	return policy
+}
+
+// retryReason은 다시 시도해도 되는 오류면 그 사유를, 아니면 빈 문자열을 반환합니다.
+// conflict는 요청 본문에 resourceVersion이 없는 호출(Patch/Apply/Delete)에서만 재시도할 의미가 있습니다.
+func retryReason(err error, retryConflict bool) string {
+	switch {
+	case err == nil:
+		return ""
+	case apierrors.IsTooManyRequests(err):
+		return "too_many_requests"
+	case apierrors.IsServerTimeout(err), apierrors.IsTimeout(err):
+		return "timeout"
+	case apierrors.IsServiceUnavailable(err):
+		return "unavailable"
+	case apierrors.IsConflict(err) && retryConflict:
+		return "conflict"
+	case utilnet.IsConnectionReset(err), utilnet.IsConnectionRefused(err), utilnet.IsProbableEOF(err):
+		return "network"
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return "network"
+	}
+	return ""
+}
+
+// delay는 attempt번째 실패 후 기다릴 시간입니다. 서버가 Retry-After를 주면 그 값을 우선합니다.
+func (p retryPolicy) delay(attempt int, err error) time.Duration {
+	if seconds, ok := apierrors.SuggestsClientDelay(err); ok && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	delay := p.baseDelay << (attempt - 1)
+	if delay <= 0 || delay > p.maxDelay {
+		delay = p.maxDelay
+	}
+	return delay
+}
+
+// do는 fn을 정책에 따라 재시도합니다. 시도마다 제한 시간을 두며, 재시도할 수 없는 오류나 호출자 컨텍스트 취소는 그대로 반환합니다.
+func (p retryPolicy) do(ctx context.Context, verb string, retryConflict bool, fn func(ctx context.Context) error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = p.attempt(ctx, fn)
+		reason := retryReason(err, retryConflict)
+		if reason == "" || attempt >= p.attempts || ctx.Err() != nil {
+			return err
+		}
+
+		delay := p.delay(attempt, err)
+		k8sRetriesTotal.Inc(verb, reason)
+		logger.FromContext(ctx).Warn("k8s call failed, retrying", "verb", verb, "reason", reason, "delay", delay.String(), "attempt", attempt, "max_attempts", p.attempts, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (p retryPolicy) attempt(ctx context.Context, fn func(ctx context.Context) error) error {
+	if p.timeout <= 0 {
+		return fn(ctx)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	return fn(ctx)
+}
+
+// retryingDynamicClient는 dynamic client 호출을 일시적 오류(429, 타임아웃, 연결 끊김 등)에 한해 재시도합니다.
+// API 서버가 잠깐 불안정해도 VM 생성 전체가 실패해 롤백되지 않도록 NewK8sService에서 감쌉니다.
+type retryingDynamicClient struct {
+	client dynamic.Interface
+	policy retryPolicy
+}
+
+func newRetryingDynamicClient(client dynamic.Interface) dynamic.Interface {
+	return &retryingDynamicClient{client: client, policy: loadRetryPolicy()}
+}
+
+func (c *retryingDynamicClient) Resource(resource schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return &retryingResource{ResourceInterface: c.client.Resource(resource), resource: c.client.Resource(resource), policy: c.policy}
+}
+
+type retryingResource struct {
+	dynamic.ResourceInterface
+	resource dynamic.NamespaceableResourceInterface // Namespace 호출용 (Resource()가 반환한 값에만 설정됨)
+	policy   retryPolicy
+}
+
+func (r *retryingResource) Namespace(namespace string) dynamic.ResourceInterface {
+	return &retryingResource{ResourceInterface: r.resource.Namespace(namespace), policy: r.policy}
+}
+
+// Create는 응답을 받지 못한 시도(타임아웃, 연결 끊김) 뒤에 AlreadyExists가 나면
+// 앞선 시도가 실제로는 성공한 것으로 보고 생성된 객체를 반환합니다.
+func (r *retryingResource) Create(ctx context.Context, obj *unstructured.Unstructured, options metav1.CreateOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	var result *unstructured.Unstructured
+	unanswered := false
+	err := r.policy.do(ctx, "create", false, func(ctx context.Context) error {
+		var err error
+		result, err = r.ResourceInterface.Create(ctx, obj, options, subresources...)
+		if apierrors.IsAlreadyExists(err) && unanswered && obj.GetName() != "" && len(subresources) == 0 {
+			if existing, getErr := r.ResourceInterface.Get(ctx, obj.GetName(), metav1.GetOptions{}); getErr == nil {
+				result, err = existing, nil
+			}
+		}
+		reason := retryReason(err, false)
+		unanswered = reason == "network" || reason == "timeout"
+		return err
+	})
+	return result, err
+}
+
+func (r *retryingResource) Update(ctx context.Context, obj *unstructured.Unstructured, options metav1.UpdateOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	var result *unstructured.Unstructured
+	err := r.policy.do(ctx, "update", false, func(ctx context.Context) error {
+		var err error
+		result, err = r.ResourceInterface.Update(ctx, obj, options, subresources...)
+		return err
+	})
+	return result, err
+}
+
+func (r *retryingResource) UpdateStatus(ctx context.Context, obj *unstructured.Unstructured, options metav1.UpdateOptions) (*unstructured.Unstructured, error) {
+	var result *unstructured.Unstructured
+	err := r.policy.do(ctx, "update", false, func(ctx context.Context) error {
+		var err error
+		result, err = r.ResourceInterface.UpdateStatus(ctx, obj, options)
+		return err
+	})
+	return result, err
+}
+
+func (r *retryingResource) Delete(ctx context.Context, name string, options metav1.DeleteOptions, subresources ...string) error {
+	return r.policy.do(ctx, "delete", options.Preconditions == nil, func(ctx context.Context) error {
+		return r.ResourceInterface.Delete(ctx, name, options, subresources...)
+	})
+}
+
+func (r *retryingResource) DeleteCollection(ctx context.Context, options metav1.DeleteOptions, listOptions metav1.ListOptions) error {
+	return r.policy.do(ctx, "delete", options.Preconditions == nil, func(ctx context.Context) error {
+		return r.ResourceInterface.DeleteCollection(ctx, options, listOptions)
+	})
+}
+
+func (r *retryingResource) Get(ctx context.Context, name string, options metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	var result *unstructured.Unstructured
+	err := r.policy.do(ctx, "get", false, func(ctx context.Context) error {
+		var err error
+		result, err = r.ResourceInterface.Get(ctx, name, options, subresources...)
+		return err
+	})
+	return result, err
+}
+
+func (r *retryingResource) List(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	var result *unstructured.UnstructuredList
+	err := r.policy.do(ctx, "list", false, func(ctx context.Context) error {
+		var err error
+		result, err = r.ResourceInterface.List(ctx, opts)
+		return err
+	})
+	return result, err
+}
+
+// Watch는 호출자가 재연결을 처리하므로 재시도하지 않습니다.
+func (r *retryingResource) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	return r.ResourceInterface.Watch(ctx, opts)
+}
+
+func (r *retryingResource) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, options metav1.PatchOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	var result *unstructured.Unstructured
+	err := r.policy.do(ctx, "patch", true, func(ctx context.Context) error {
+		var err error
+		result, err = r.ResourceInterface.Patch(ctx, name, pt, data, options, subresources...)
+		return err
+	})
+	return result, err
+}
+
+func (r *retryingResource) Apply(ctx context.Context, name string, obj *unstructured.Unstructured, options metav1.ApplyOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	var result *unstructured.Unstructured
+	err := r.policy.do(ctx, "apply", true, func(ctx context.Context) error {
+		var err error
+		result, err = r.ResourceInterface.Apply(ctx, name, obj, options, subresources...)
+		return err
+	})
+	return result, err
+}
+
+func (r *retryingResource) ApplyStatus(ctx context.Context, name string, obj *unstructured.Unstructured, options metav1.ApplyOptions) (*unstructured.Unstructured, error) {
+	var result *unstructured.Unstructured
+	err := r.policy.do(ctx, "apply", true, func(ctx context.Context) error {
+		var err error
+		result, err = r.ResourceInterface.ApplyStatus(ctx, name, obj, options)
+		return err
+	})
+	return result, err
+}
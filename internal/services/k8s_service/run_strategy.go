@@ -0,0 +1,114 @@
+package k8s_service
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// KubeVirt VirtualMachine spec.runStrategy 값
+const (
+	RunStrategyAlways         = "Always"
+	RunStrategyHalted         = "Halted"
+	RunStrategyRerunOnFailure = "RerunOnFailure"
+)
+
+// KubeVirt v1.3부터 spec.running 이 deprecated 되어 runStrategy를 사용
+const runStrategyMinMajor, runStrategyMinMinor = 1, 3
+
+// runningStrategy는 실행 중인 VM에 사용할 runStrategy입니다. (VM_RUN_STRATEGY, 기본값 Always)
+// RerunOnFailure는 게스트 안에서 정상 종료하면 다시 켜지지 않습니다.
+func runningStrategy() string {
+	if os.Getenv("VM_RUN_STRATEGY") == RunStrategyRerunOnFailure {
+		return RunStrategyRerunOnFailure
+	}
+	return RunStrategyAlways
+}
+
+// usesRunStrategy는 클러스터의 KubeVirt 버전에서 spec.running 대신 runStrategy를 써야 하는지 반환합니다.
+// 버전을 감지하지 못하면 runStrategy를 사용합니다. (지원하는 모든 KubeVirt 버전에서 동작)
+func (s *K8sService) usesRunStrategy() bool {
+	version := s.GetClusterVersions(s.baseContext()).KubeVirt
+
+	var major, minor int
+	if _, err := fmt.Sscanf(strings.TrimPrefix(version, "v"), "%d.%d", &major, &minor); err != nil {
+		return true
+	}
+	return major > runStrategyMinMajor || (major == runStrategyMinMajor && minor >= runStrategyMinMinor)
+}
+
+// runStatePatch는 VM을 시작/정지하는 merge patch를 만듭니다.
+// KubeVirt는 running과 runStrategy를 함께 지정하면 거부하므로 사용하지 않는 필드는 지웁니다.
+func (s *K8sService) runStatePatch(running bool) []byte {
+	if !s.usesRunStrategy() {
+		return []byte(fmt.Sprintf(`{"spec":{"running":%t,"runStrategy":null}}`, running))
+	}
+
+	strategy := RunStrategyHalted
+	if running {
+		strategy = runningStrategy()
+	}
+	return []byte(fmt.Sprintf(`{"spec":{"running":null,"runStrategy":%q}}`, strategy))
+}
+
+// setRunStrategy는 렌더링한 VirtualMachine의 spec.running 을 runStrategy로 바꿉니다.
+// 템플릿 번들은 spec.running 으로 작성된 것도 그대로 사용할 수 있습니다.
+func setRunStrategy(objs []*unstructured.Unstructured) {
+	for _, obj := range objs {
+		if obj.GetKind() != "VirtualMachine" {
+			continue
+		}
+
+		running, found, _ := unstructured.NestedBool(obj.Object, "spec", "running")
+		if !found {
+			continue
+		}
+
+		strategy := RunStrategyHalted
+		if running {
+			strategy = runningStrategy()
+		}
+		unstructured.RemoveNestedField(obj.Object, "spec", "running")
+		unstructured.SetNestedField(obj.Object, strategy, "spec", "runStrategy")
+	}
+}
+
+// MigrateRunStrategy는 spec.running 으로 만들어진 기존 VM을 runStrategy로 옮깁니다.
+// 클러스터가 runStrategy를 쓰지 않는 버전이면 아무것도 하지 않습니다.
+func (s *K8sService) MigrateRunStrategy() error {
+	if !s.usesRunStrategy() {
+		return nil
+	}
+
+	ctx := s.baseContext()
+	list, err := s.dynamicClient.Resource(gvrVM).Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list virtual machines: %v", err)
+	}
+
+	migrated := 0
+	for _, item := range list.Items {
+		running, found, _ := unstructured.NestedBool(item.Object, "spec", "running")
+		if !found {
+			continue
+		}
+
+		patch := s.runStatePatch(running)
+		if _, err := s.dynamicClient.Resource(gvrVM).Namespace(item.GetNamespace()).Patch(
+			ctx, item.GetName(), types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			s.log().Warn("run strategy: failed to migrate VM", "namespace", item.GetNamespace(), "vm", item.GetName(), "error", err)
+			continue
+		}
+		s.recordVMPatch(item.GetName(), "run-strategy", string(patch))
+		migrated++
+	}
+
+	if migrated > 0 {
+		s.log().Info("run strategy: migrated VMs from spec.running to runStrategy", "count", migrated)
+	}
+	return nil
+}
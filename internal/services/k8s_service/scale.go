@@ -0,0 +1,112 @@
+package k8s_service
+
+import (
+	"fmt"
+	"time"
+	"vm-controller/internal/models"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// scaleDeployment는 웹 배포의 replicas를, 스케줄 작업의 suspend를 변경합니다.
+func (s *K8sService) scaleDeployment(deployment *models.Deployment, namespace string, replicas int) error {
+	ctx := s.baseContext()
+	name := DeploymentResourceName(deployment)
+
+	var gvr schema.GroupVersionResource
+	var patch []byte
+
+	if deployment.Type == models.DeploymentTypeCronJob {
+		gvr = schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"}
+		patch = []byte(fmt.Sprintf(`{"spec":{"suspend":%t}}`, replicas == 0))
+	} else {
+		gvr = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+		patch = []byte(fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas))
+	}
+
+	_, err := s.dynamicClient.Resource(gvr).Namespace(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to scale deployment %s: %v", name, err)
+	}
+
+	return nil
+}
+
+// PauseDeployment는 배포를 수동으로 0으로 스케일하고 상태를 Paused로 변경합니다.
+func (s *K8sService) PauseDeployment(deployment *models.Deployment, namespace string) error {
+	if err := s.scaleDeployment(deployment, namespace, 0); err != nil {
+		return err
+	}
+
+	return s.deploymentService.UpdateDeploymentStatus(deployment.ID, models.DeploymentStatusPaused)
+}
+
+// ResumeDeployment는 Paused/Sleeping 상태의 배포를 다시 1로 스케일합니다.
+func (s *K8sService) ResumeDeployment(deployment *models.Deployment, namespace string) error {
+	if err := s.scaleDeployment(deployment, namespace, 1); err != nil {
+		return err
+	}
+
+	// 깨어난 직후 다시 잠들지 않도록 활동 시각 갱신
+	s.deploymentService.RecordActivity(deployment.Domain)
+
+	return s.deploymentService.UpdateDeploymentStatus(deployment.ID, models.DeploymentStatusDeployed)
+}
+
+// WakeDeployment는 activator(인터셉터)가 Sleeping 배포로의 요청을 감지했을 때 호출됩니다.
+func (s *K8sService) WakeDeployment(deployment *models.Deployment) error {
+	user, err := s.userService.FetchUserById(fmt.Sprintf("%d", deployment.UserID), true)
+	if err != nil {
+		return fmt.Errorf("failed to fetch deployment owner: %v", err)
+	}
+
+	return s.ResumeDeployment(deployment, user.Namespace)
+}
+
+// StartIdleReaper는 주기적으로 유휴 배포를 찾아 0으로 스케일하는 백그라운드 루프를 시작합니다.
+func (s *K8sService) StartIdleReaper(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			// 한 번의 패닉으로 루프 전체가 멈추지 않도록 매 주기를 복구 래퍼로 실행
+			runRecovered("deployment.idle-reaper", "*", func() error {
+				s.sleepIdleDeployments()
+				return nil
+			})
+		}
+	}()
+}
+
+func (s *K8sService) sleepIdleDeployments() {
+	deployments, err := s.deploymentService.FetchIdleDeployments()
+	if err != nil {
+		s.log().Error("idle reaper: failed to fetch idle deployments", "error", err)
+		return
+	}
+
+	for i := range deployments {
+		deployment := &deployments[i]
+
+		user, err := s.userService.FetchUserById(fmt.Sprintf("%d", deployment.UserID), true)
+		if err != nil {
+			s.log().Error("idle reaper: failed to fetch deployment owner", "deployment_id", deployment.ID, "error", err)
+			continue
+		}
+
+		if err := s.scaleDeployment(deployment, user.Namespace, 0); err != nil {
+			s.log().Error("idle reaper: failed to scale down deployment", "deployment_id", deployment.ID, "error", err)
+			continue
+		}
+
+		if err := s.deploymentService.UpdateDeploymentStatus(deployment.ID, models.DeploymentStatusSleeping); err != nil {
+			s.log().Error("idle reaper: failed to update deployment status", "deployment_id", deployment.ID, "error", err)
+			continue
+		}
+
+		s.log().Info("idle reaper: deployment is now sleeping", "deployment_id", deployment.ID, "domain", deployment.Domain)
+	}
+}
@@ -0,0 +1,223 @@
+package k8s_service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+	"vm-controller/internal/metrics"
+	"vm-controller/internal/models"
+	"vm-controller/internal/naming"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	gvrVMSnapshot = schema.GroupVersionResource{Group: "snapshot.kubevirt.io", Version: "v1beta1", Resource: "virtualmachinesnapshots"}
+	gvrVMRestore  = schema.GroupVersionResource{Group: "snapshot.kubevirt.io", Version: "v1beta1", Resource: "virtualmachinerestores"}
+)
+
+// 복원 완료까지 기다리는 기본 시간 (VM_SNAPSHOT_RESTORE_TIMEOUT)
+const defaultSnapshotRestoreTimeout = 10 * time.Minute
+
+func snapshotRestoreTimeout() time.Duration {
+	if timeout, err := time.ParseDuration(os.Getenv("VM_SNAPSHOT_RESTORE_TIMEOUT")); err == nil && timeout > 0 {
+		return timeout
+	}
+	return defaultSnapshotRestoreTimeout
+}
+
+// CreateVMSnapshot은 VM 디스크의 VirtualMachineSnapshot을 생성합니다.
+// 실행 중인 VM은 게스트 에이전트가 있으면 파일시스템을 잠시 freeze 한 뒤 스냅샷을 찍습니다.
+func (s *K8sService) CreateVMSnapshot(vm *models.VirtualMachine, snapshot *models.Snapshot) error {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "snapshot.kubevirt.io/v1beta1",
+		"kind":       "VirtualMachineSnapshot",
+		"metadata": map[string]interface{}{
+			"name":      snapshot.Name,
+			"namespace": vm.Namespace,
+		},
+		"spec": map[string]interface{}{
+			"source": map[string]interface{}{
+				"apiGroup": "kubevirt.io",
+				"kind":     "VirtualMachine",
+				"name":     vm.Name,
+			},
+		},
+	}}
+
+	if _, err := s.dynamicClient.Resource(gvrVMSnapshot).Namespace(vm.Namespace).Create(s.baseContext(), obj, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create virtual machine snapshot: %v", err)
+	}
+	s.recordVMPatch(vm.Name, "snapshot", "created VirtualMachineSnapshot "+snapshot.Name)
+
+	return nil
+}
+
+// SyncVMSnapshotStatus는 생성 중인 스냅샷의 상태를 클러스터에서 읽어 DB에 반영합니다.
+// (Ready / Failed 가 된 스냅샷은 다시 조회하지 않음)
+func (s *K8sService) SyncVMSnapshotStatus(snapshot *models.Snapshot) error {
+	if snapshot.Status != models.SnapshotStatusInProgress {
+		return nil
+	}
+
+	obj, err := s.dynamicClient.Resource(gvrVMSnapshot).Namespace(snapshot.Namespace).Get(s.baseContext(), snapshot.Name, metav1.GetOptions{})
+	status, message := snapshot.Status, ""
+	switch {
+	case apierrors.IsNotFound(err):
+		status, message = models.SnapshotStatusFailed, "snapshot resource not found"
+	case err != nil:
+		return fmt.Errorf("failed to get virtual machine snapshot: %v", err)
+	default:
+		readyToUse, _, _ := unstructured.NestedBool(obj.Object, "status", "readyToUse")
+		phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+		if readyToUse {
+			status = models.SnapshotStatusReady
+		} else if phase == "Failed" {
+			status = models.SnapshotStatusFailed
+			message, _, _ = unstructured.NestedString(obj.Object, "status", "error", "message")
+		}
+	}
+
+	if status == snapshot.Status {
+		return nil
+	}
+	if err := s.snapshotService.UpdateSnapshotStatus(snapshot.ID, status, message); err != nil {
+		return err
+	}
+	snapshot.Status, snapshot.Message = status, message
+
+	return nil
+}
+
+// RestoreVMSnapshot은 정지된 VM의 디스크를 스냅샷 시점으로 되돌립니다.
+// 1. DB 상태를 Restoring으로 변경
+// 2. VirtualMachineRestore 생성 후 완료될 때까지 대기
+// 3. 완료되면 Restore 리소스를 정리하고 DB 상태를 Stopped로 변경 (시작은 사용자가 요청)
+func (s *K8sService) RestoreVMSnapshot(vm *models.VirtualMachine, snapshot *models.Snapshot) error {
+	if err := s.vmService.UpdateVmStatus(vm.Name, models.VmStatusRestoring); err != nil {
+		return fmt.Errorf("failed to update VM status to Restoring: %w", err)
+	}
+
+	ctx := s.baseContext()
+	name := naming.ResourceName("", snapshot.Name, fmt.Sprintf("-restore-%d", time.Now().Unix()))
+
+	restore := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "snapshot.kubevirt.io/v1beta1",
+		"kind":       "VirtualMachineRestore",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": vm.Namespace,
+		},
+		"spec": map[string]interface{}{
+			"target": map[string]interface{}{
+				"apiGroup": "kubevirt.io",
+				"kind":     "VirtualMachine",
+				"name":     vm.Name,
+			},
+			"virtualMachineSnapshotName": snapshot.Name,
+		},
+	}}
+
+	if _, err := s.dynamicClient.Resource(gvrVMRestore).Namespace(vm.Namespace).Create(ctx, restore, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create virtual machine restore: %v", err)
+	}
+	s.recordVMPatch(vm.Name, "restore", "created VirtualMachineRestore "+name)
+
+	if err := s.waitForVMRestore(vm.Namespace, name, snapshotRestoreTimeout()); err != nil {
+		return err
+	}
+
+	// 완료된 Restore 리소스는 더 이상 필요 없음 (스냅샷 삭제를 막지 않도록 정리)
+	if err := s.dynamicClient.Resource(gvrVMRestore).Namespace(vm.Namespace).Delete(ctx, name, deleteOptions("VirtualMachineRestore", nil)); err != nil && !apierrors.IsNotFound(err) {
+		s.log().Warn("snapshot: failed to clean up virtual machine restore", "restore", name, "error", err)
+	}
+
+	if err := s.snapshotService.MarkSnapshotRestored(snapshot.ID); err != nil {
+		s.log().Warn("snapshot: failed to record restore time", "snapshot", snapshot.Name, "error", err)
+	}
+
+	if err := s.vmService.UpdateVmStatus(vm.Name, models.VmStatusStopped); err != nil {
+		return fmt.Errorf("failed to update VM status to Stopped: %w", err)
+	}
+
+	return nil
+}
+
+// waitForVMRestore는 VirtualMachineRestore의 status.complete가 true가 될 때까지 5초 간격으로 폴링합니다.
+func (s *K8sService) waitForVMRestore(namespace, name string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(s.baseContext(), timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timeout waiting for restore %s to complete", name)
+		case <-ticker.C:
+			obj, err := s.dynamicClient.Resource(gvrVMRestore).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				if ctx.Err() != nil {
+					continue
+				}
+				return fmt.Errorf("failed to get virtual machine restore: %v", err)
+			}
+
+			if complete, _, _ := unstructured.NestedBool(obj.Object, "status", "complete"); complete {
+				return nil
+			}
+		}
+	}
+}
+
+// RestoreVMSnapshotAsync는 스냅샷 복원을 백그라운드로 실행합니다.
+// Restore 리소스 생성은 멱등하지 않으므로 재시도하지 않으며, 실패하면 VM을 Failed로 표시합니다.
+func (s *K8sService) RestoreVMSnapshotAsync(vm *models.VirtualMachine, snapshot *models.Snapshot, traceID string) uint {
+	return s.queueAsync(s.restoreSnapshotOperation(vm, snapshot), traceID)
+}
+
+func (s *K8sService) restoreSnapshotOperation(vm *models.VirtualMachine, snapshot *models.Snapshot) AsyncOperation {
+	return AsyncOperation{
+		Name:       "vm.restore",
+		Target:     "vm/" + vm.Name,
+		Tenant:     metrics.TenantLabelFor(vm.UserID),
+		Owner:      vm.UserID,
+		Run:        func() error { return s.RestoreVMSnapshot(vm, snapshot) },
+		Compensate: s.markVMFailed(vm, "restore"),
+		OnSuccess:  s.markVMSucceeded(vm, "restore"),
+		payload:    asyncPayload{VM: vm.Name, Snapshot: snapshot.Name},
+	}
+}
+
+// DeleteVMSnapshot은 VirtualMachineSnapshot(과 디스크 VolumeSnapshot)과 레코드를 삭제합니다.
+func (s *K8sService) DeleteVMSnapshot(snapshot *models.Snapshot) error {
+	err := s.dynamicClient.Resource(gvrVMSnapshot).Namespace(snapshot.Namespace).Delete(s.baseContext(), snapshot.Name, deleteOptions("VirtualMachineSnapshot", nil))
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete virtual machine snapshot: %v", err)
+	}
+
+	s.recordVMPatch(snapshot.VmName, "snapshot.delete", "deleted VirtualMachineSnapshot "+snapshot.Name)
+
+	return s.snapshotService.DeleteSnapshot(snapshot.ID)
+}
+
+// deleteVMSnapshots는 VM 삭제 시 남아 있는 스냅샷을 모두 삭제합니다. (스냅샷은 VM의 owner 관계가 없어 자동 삭제되지 않음)
+func (s *K8sService) deleteVMSnapshots(vm *models.VirtualMachine) error {
+	snapshots, err := s.snapshotService.FetchVmSnapshots(vm.Name)
+	if err != nil {
+		return err
+	}
+
+	for i := range snapshots {
+		if err := s.DeleteVMSnapshot(&snapshots[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package k8s_service
+
+import (
+	"fmt"
+	"os"
+	"time"
+	"vm-controller/internal/models"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// VM 정지 방식 (VM_STOP_MODE)
+const (
+	StopModeGraceful = "graceful" // 게스트 ACPI 종료 후 제한 시간이 지나면 강제 종료
+	StopModeForce    = "force"    // 즉시 강제 종료 (virtctl stop --force --grace-period=0)
+)
+
+// 게스트 종료를 기다리는 기본 시간 (VM_STOP_TIMEOUT)
+const defaultStopTimeout = 2 * time.Minute
+
+// StopMode는 설정된 VM 정지 방식을 반환합니다. (기본값 graceful)
+func StopMode() string {
+	if os.Getenv("VM_STOP_MODE") == StopModeForce {
+		return StopModeForce
+	}
+	return StopModeGraceful
+}
+
+// StopTimeout은 graceful 정지에서 강제 종료 전까지 기다리는 시간을 반환합니다.
+func StopTimeout() time.Duration {
+	if timeout, err := time.ParseDuration(os.Getenv("VM_STOP_TIMEOUT")); err == nil && timeout > 0 {
+		return timeout
+	}
+	return defaultStopTimeout
+}
+
+// forceStopVM은 stop 서브리소스를 gracePeriod 0으로 호출해 인스턴스를 즉시 종료합니다.
+// 인스턴스가 이미 없으면(정지 완료) 성공으로 처리합니다.
+func (s *K8sService) forceStopVM(vm *models.VirtualMachine, reason string) error {
+	path, err := s.putVMSubresourceBody(s.baseContext(), "virtualmachines", vm.Namespace, vm.Name, "stop", []byte(`{"gracePeriod":0}`))
+	if err != nil {
+		// KubeVirt는 인스턴스가 없으면 409(VM is not running)로 응답
+		if apierrors.IsNotFound(err) || apierrors.IsConflict(err) {
+			return nil
+		}
+		return err
+	}
+	s.recordVMPatch(vm.Name, "stop."+StopModeForce, fmt.Sprintf("%s (%s)", path, reason))
+	return nil
+}
@@ -0,0 +1,168 @@
+package k8s_service
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PVCUsage는 PVC 한 개의 할당 용량과 실제 사용량입니다.
+type PVCUsage struct {
+	Name          string `json:"name"`
+	CapacityBytes int64  `json:"capacity_bytes"`
+	UsedBytes     int64  `json:"used_bytes"` // kubelet 통계가 없으면 -1 (파드에 마운트되지 않은 PVC 등)
+}
+
+// NamespaceStorage는 네임스페이스의 PVC 용량/사용량 합계입니다.
+type NamespaceStorage struct {
+	Namespace     string     `json:"namespace"`
+	CapacityBytes int64      `json:"capacity_bytes"`
+	UsedBytes     int64      `json:"used_bytes"` // 통계가 있는 PVC만 합산
+	PVCs          []PVCUsage `json:"pvcs"`
+}
+
+// kubelet /stats/summary 응답 중 필요한 부분
+type kubeletSummary struct {
+	Pods []struct {
+		PodRef struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"podRef"`
+		CPU *struct {
+			UsageNanoCores *uint64 `json:"usageNanoCores"`
+		} `json:"cpu"`
+		Network *struct {
+			RxBytes *uint64 `json:"rxBytes"`
+			TxBytes *uint64 `json:"txBytes"`
+		} `json:"network"`
+		Volume []struct {
+			UsedBytes *int64 `json:"usedBytes"`
+			PVCRef    *struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"pvcRef"`
+		} `json:"volume"`
+	} `json:"pods"`
+}
+
+// 노드 통계 조회는 비용이 크므로 짧게 캐시
+const volumeStatsCacheTTL = time.Minute
+
+var (
+	volumeStatsMu     sync.Mutex
+	volumeStatsCache  map[string]int64
+	volumeStatsReadAt time.Time
+)
+
+// volumeUsage는 모든 노드의 kubelet 통계에서 "namespace/pvc" 별 사용량을 수집합니다.
+func (s *K8sService) volumeUsage() map[string]int64 {
+	volumeStatsMu.Lock()
+	defer volumeStatsMu.Unlock()
+
+	if volumeStatsCache != nil && time.Since(volumeStatsReadAt) < volumeStatsCacheTTL {
+		return volumeStatsCache
+	}
+
+	usage := map[string]int64{}
+
+	for _, summary := range s.readNodeSummaries() {
+		for _, pod := range summary.Pods {
+			for _, volume := range pod.Volume {
+				if volume.PVCRef == nil || volume.UsedBytes == nil {
+					continue
+				}
+				usage[volume.PVCRef.Namespace+"/"+volume.PVCRef.Name] = *volume.UsedBytes
+			}
+		}
+	}
+
+	volumeStatsCache = usage
+	volumeStatsReadAt = time.Now()
+	return usage
+}
+
+// readNodeSummaries는 모든 노드의 kubelet /stats/summary 를 읽습니다. 읽지 못한 노드는 건너뜁니다.
+func (s *K8sService) readNodeSummaries() []kubeletSummary {
+	ctx := s.baseContext()
+
+	nodes, err := s.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		s.log().Warn("node stats: failed to list nodes", "error", err)
+		return nil
+	}
+
+	var summaries []kubeletSummary
+	for _, node := range nodes.Items {
+		raw, err := s.clientset.CoreV1().RESTClient().Get().
+			AbsPath("/api/v1/nodes", node.Name, "proxy", "stats", "summary").
+			DoRaw(ctx)
+		if err != nil {
+			s.log().Warn("node stats: failed to read node stats", "node", node.Name, "error", err)
+			continue
+		}
+
+		var summary kubeletSummary
+		if err := json.Unmarshal(raw, &summary); err != nil {
+			continue
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return summaries
+}
+
+// GetNamespaceStorage는 네임스페이스의 PVC 할당 용량과 실제 사용량을 반환합니다.
+func (s *K8sService) GetNamespaceStorage(namespace string) (*NamespaceStorage, error) {
+	all, err := s.listStorage(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if storage, ok := all[namespace]; ok {
+		return storage, nil
+	}
+	return &NamespaceStorage{Namespace: namespace, PVCs: []PVCUsage{}}, nil
+}
+
+// ListStorageUsage는 관리자용으로 전체 네임스페이스의 스토리지 사용량을 반환합니다.
+func (s *K8sService) ListStorageUsage() (map[string]*NamespaceStorage, error) {
+	return s.listStorage(metav1.NamespaceAll)
+}
+
+func (s *K8sService) listStorage(namespace string) (map[string]*NamespaceStorage, error) {
+	pvcs, err := s.clientset.CoreV1().PersistentVolumeClaims(namespace).List(s.baseContext(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list persistent volume claims: %v", err)
+	}
+
+	usage := s.volumeUsage()
+	result := map[string]*NamespaceStorage{}
+
+	for _, pvc := range pvcs.Items {
+		storage, ok := result[pvc.Namespace]
+		if !ok {
+			storage = &NamespaceStorage{Namespace: pvc.Namespace, PVCs: []PVCUsage{}}
+			result[pvc.Namespace] = storage
+		}
+
+		// 바인딩 전이면 요청 용량 기준
+		quantity, ok := pvc.Status.Capacity["storage"]
+		if !ok {
+			quantity = pvc.Spec.Resources.Requests["storage"]
+		}
+
+		item := PVCUsage{Name: pvc.Name, CapacityBytes: quantity.Value(), UsedBytes: -1}
+		if used, ok := usage[pvc.Namespace+"/"+pvc.Name]; ok {
+			item.UsedBytes = used
+			storage.UsedBytes += used
+		}
+
+		storage.CapacityBytes += item.CapacityBytes
+		storage.PVCs = append(storage.PVCs, item)
+	}
+
+	return result, nil
+}
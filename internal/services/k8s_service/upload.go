@@ -0,0 +1,268 @@
+package k8s_service
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"vm-controller/internal/metrics"
+	"vm-controller/internal/models"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var gvrUploadTokenRequest = schema.GroupVersionResource{Group: "upload.cdi.kubevirt.io", Version: "v1beta1", Resource: "uploadtokenrequests"}
+
+// CDI upload proxy 기본 주소 (CDI_UPLOAD_PROXY_URL 로 변경 가능)
+const defaultUploadProxyURL = "https://cdi-uploadproxy.cdi.svc"
+
+// DiskImageFormat은 업로드된 이미지의 형식입니다.
+type DiskImageFormat string
+
+const (
+	DiskImageQcow2 DiskImageFormat = "qcow2"
+	DiskImageRaw   DiskImageFormat = "raw"
+)
+
+// UploadStagingPath는 청크 업로드를 이어받기 위해 이미지를 임시로 저장하는 경로입니다. (UPLOAD_STAGING_DIR)
+func UploadStagingPath(namespace, vmName string) string {
+	dir := os.Getenv("UPLOAD_STAGING_DIR")
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "vm-uploads")
+	}
+	return filepath.Join(dir, namespace+"_"+vmName+".img")
+}
+
+// AppendUploadChunk는 offset 위치에 청크를 이어 씁니다.
+// offset이 현재까지 받은 크기와 다르면 에러와 함께 현재 크기를 반환하여 클라이언트가 이어서 보낼 수 있게 합니다.
+func AppendUploadChunk(path string, offset int64, chunk io.Reader, maxBytes int64) (int64, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return 0, fmt.Errorf("failed to create staging directory: %v", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open staging file: %v", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+	if offset != info.Size() {
+		return info.Size(), fmt.Errorf("offset mismatch: expected %d", info.Size())
+	}
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return offset, err
+	}
+
+	// 쿼터를 넘는 데이터는 받지 않음 (+1 바이트로 초과 여부 판단)
+	written, err := io.Copy(file, io.LimitReader(chunk, maxBytes-offset+1))
+	size := offset + written
+	if err != nil {
+		return size, fmt.Errorf("failed to write chunk: %v", err)
+	}
+	if size > maxBytes {
+		file.Truncate(offset)
+		return offset, fmt.Errorf("image exceeds the maximum size of %d bytes", maxBytes)
+	}
+
+	return size, nil
+}
+
+// InspectDiskImage는 업로드된 이미지의 형식과 가상 디스크 크기를 검사합니다.
+// qcow2는 backing file / 암호화를 사용하지 않아야 하며, raw는 부팅 가능한 파티션 테이블(MBR/GPT)이 있어야 합니다.
+func InspectDiskImage(path string, maxVirtualBytes int64) (DiskImageFormat, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open image: %v", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	header := make([]byte, 512)
+	if _, err := io.ReadFull(file, header); err != nil {
+		return "", fmt.Errorf("image is too small")
+	}
+
+	if string(header[:4]) == "QFI\xfb" {
+		version := binary.BigEndian.Uint32(header[4:8])
+		backingFileOffset := binary.BigEndian.Uint64(header[8:16])
+		virtualSize := int64(binary.BigEndian.Uint64(header[24:32]))
+		cryptMethod := binary.BigEndian.Uint32(header[32:36])
+
+		switch {
+		case version != 2 && version != 3:
+			return "", fmt.Errorf("unsupported qcow2 version %d", version)
+		case backingFileOffset != 0:
+			return "", fmt.Errorf("qcow2 images with a backing file are not allowed")
+		case cryptMethod != 0:
+			return "", fmt.Errorf("encrypted qcow2 images are not supported")
+		case virtualSize > maxVirtualBytes:
+			return "", fmt.Errorf("virtual disk size %d bytes exceeds the limit of %d bytes", virtualSize, maxVirtualBytes)
+		}
+		return DiskImageQcow2, nil
+	}
+
+	// raw 이미지: 섹터 단위 크기 + MBR(또는 GPT의 protective MBR) 부트 시그니처
+	if info.Size()%512 != 0 {
+		return "", fmt.Errorf("raw image size must be a multiple of 512 bytes")
+	}
+	if header[510] != 0x55 || header[511] != 0xAA {
+		return "", fmt.Errorf("unrecognized image format (expected qcow2 or raw disk with a partition table)")
+	}
+	if info.Size() > maxVirtualBytes {
+		return "", fmt.Errorf("disk size %d bytes exceeds the limit of %d bytes", info.Size(), maxVirtualBytes)
+	}
+
+	return DiskImageRaw, nil
+}
+
+// ScanDiskImage는 UPLOAD_SCAN_COMMAND(예: "clamdscan --no-summary")가 설정된 경우 이미지를 검사합니다.
+func ScanDiskImage(path string) error {
+	command := strings.Fields(os.Getenv("UPLOAD_SCAN_COMMAND"))
+	if len(command) == 0 {
+		return nil
+	}
+
+	output, err := exec.Command(command[0], append(command[1:], path)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("image rejected by scanner: %s", strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// UploadDiskImage는 임시 저장된 이미지를 CDI upload proxy로 전송합니다. 완료되면 임시 파일을 삭제합니다.
+func (s *K8sService) UploadDiskImage(vm *models.VirtualMachine, path string) error {
+	ctx := s.baseContext()
+	pvcName := VMNames(vm.Name).Disk
+
+	tokenRequest := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "upload.cdi.kubevirt.io/v1beta1",
+		"kind":       "UploadTokenRequest",
+		"metadata": map[string]interface{}{
+			"name":      pvcName,
+			"namespace": vm.Namespace,
+		},
+		"spec": map[string]interface{}{
+			"pvcName": pvcName,
+		},
+	}}
+
+	response, err := s.dynamicClient.Resource(gvrUploadTokenRequest).Namespace(vm.Namespace).Create(ctx, tokenRequest, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to request upload token: %v", err)
+	}
+	token, _, _ := unstructured.NestedString(response.Object, "status", "token")
+	if token == "" {
+		return fmt.Errorf("upload token is empty")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open staged image: %v", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	proxyURL := os.Getenv("CDI_UPLOAD_PROXY_URL")
+	if proxyURL == "" {
+		proxyURL = defaultUploadProxyURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(proxyURL, "/")+"/v1beta1/upload", file)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = info.Size()
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: s.uploadProxyTLSConfig(ctx)}}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload image: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("upload proxy returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	s.recordVMPatch(vm.Name, "upload", fmt.Sprintf("uploaded %d bytes to %s", info.Size(), pvcName))
+
+	if err := os.Remove(path); err != nil {
+		s.log().Warn("upload: failed to remove staged image", "path", path, "error", err)
+	}
+
+	return nil
+}
+
+// uploadProxyTLSConfig는 CDI가 발급한 upload proxy 인증서의 CA를 신뢰하도록 설정합니다.
+// CA 번들을 찾지 못하면 시스템 인증서 저장소를 사용합니다.
+func (s *K8sService) uploadProxyTLSConfig(ctx context.Context) *tls.Config {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	bundle, err := s.clientset.CoreV1().ConfigMaps("cdi").Get(ctx, "cdi-uploadproxy-signer-bundle", metav1.GetOptions{})
+	if err != nil {
+		return config
+	}
+
+	pool := x509.NewCertPool()
+	if pool.AppendCertsFromPEM([]byte(bundle.Data["ca-bundle.crt"])) {
+		config.RootCAs = pool
+	}
+
+	return config
+}
+
+// UploadDiskImageAsync는 이미지 전송을 백그라운드로 실행합니다. 업로드 토큰은 매 시도마다 새로 발급하므로 재시도 가능합니다.
+func (s *K8sService) UploadDiskImageAsync(vm *models.VirtualMachine, path string, traceID string) uint {
+	return s.queueAsync(s.uploadDiskOperation(vm, path), traceID)
+}
+
+func (s *K8sService) uploadDiskOperation(vm *models.VirtualMachine, path string) AsyncOperation {
+	return AsyncOperation{
+		Name:       "vm.upload",
+		Target:     "vm/" + vm.Name,
+		Tenant:     metrics.TenantLabelFor(vm.UserID),
+		Owner:      vm.UserID,
+		Run:        func() error { return s.UploadDiskImage(vm, path) },
+		Compensate: s.markVMFailed(vm, "upload"),
+		OnSuccess:  s.markVMSucceeded(vm, "upload"),
+		MaxRetries: 2,
+		payload:    asyncPayload{VM: vm.Name, Path: path},
+	}
+}
+
+// GetDiskPhase는 VM 루트 디스크 DataVolume의 phase를 반환합니다. (UploadReady, Succeeded 등)
+func (s *K8sService) GetDiskPhase(vm *models.VirtualMachine) (string, error) {
+	obj, err := s.dynamicClient.Resource(gvrDataVolume).Namespace(vm.Namespace).Get(s.baseContext(), VMNames(vm.Name).Disk, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get data volume: %v", err)
+	}
+
+	phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+	return phase, nil
+}
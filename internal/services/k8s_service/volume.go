@@ -0,0 +1,262 @@
+package k8s_service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+	"vm-controller/internal/metrics"
+	"vm-controller/internal/models"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// 추가 디스크는 루트 디스크(yaml-data/client-vm/01-datavolume.yaml)와 같은 스토리지 클래스를 사용
+const volumeStorageClass = "local-path"
+
+// 핫플러그 디스크가 게스트에 연결/해제될 때까지 기다리는 시간 (빈 DataVolume 프로비저닝 포함)
+const volumeHotplugTimeout = 5 * time.Minute
+
+// AttachVMVolume은 빈 DataVolume을 만들고 실행 중인 VM에 핫플러그합니다.
+// VM 서브리소스(addvolume)로 연결하므로 VM 스펙에도 기록되어 재시작 후에도 유지됩니다.
+// 1. DataVolume 생성 (이미 있으면 재사용)
+// 2. addvolume 호출 (이미 연결되어 있으면 건너뜀)
+// 3. VMI volumeStatus가 Ready가 되면 DB 상태를 Attached로 변경
+func (s *K8sService) AttachVMVolume(vm *models.VirtualMachine, volume *models.VolumeAttachment) error {
+	ctx := s.baseContext()
+
+	dataVolume := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "cdi.kubevirt.io/v1beta1",
+		"kind":       "DataVolume",
+		"metadata": map[string]interface{}{
+			"name":      volume.Name,
+			"namespace": vm.Namespace,
+		},
+		"spec": map[string]interface{}{
+			"source": map[string]interface{}{
+				"blank": map[string]interface{}{},
+			},
+			"pvc": map[string]interface{}{
+				"accessModes":      []interface{}{"ReadWriteOnce"},
+				"storageClassName": volumeStorageClass,
+				"resources": map[string]interface{}{
+					"requests": map[string]interface{}{
+						"storage": fmt.Sprintf("%dGi", volume.SizeGi),
+					},
+				},
+			},
+		},
+	}}
+
+	_, err := s.dynamicClient.Resource(gvrDataVolume).Namespace(vm.Namespace).Create(ctx, dataVolume, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create data volume: %v", err)
+	}
+
+	attached, err := s.vmHasVolume(ctx, vm, volume.Name)
+	if err != nil {
+		return err
+	}
+	if !attached {
+		body, _ := json.Marshal(map[string]interface{}{
+			"name": volume.Name,
+			"disk": map[string]interface{}{
+				"name": volume.Name,
+				"disk": map[string]interface{}{"bus": "scsi"},
+			},
+			"volumeSource": map[string]interface{}{
+				"dataVolume": map[string]interface{}{
+					"name":         volume.Name,
+					"hotpluggable": true,
+				},
+			},
+		})
+
+		path, err := s.putVMSubresourceBody(ctx, "virtualmachines", vm.Namespace, vm.Name, "addvolume", body)
+		if err != nil {
+			return err
+		}
+		s.recordVMPatch(vm.Name, "volume.attach", fmt.Sprintf("%s %s (%dGi)", path, volume.Name, volume.SizeGi))
+	}
+
+	if err := s.waitForVolumeStatus(ctx, vm, volume.Name, true); err != nil {
+		return err
+	}
+
+	return s.volumeService.UpdateVolumeStatus(volume.ID, models.VolumeStatusAttached, "")
+}
+
+// AttachVMVolumeAsync는 추가 디스크 연결을 백그라운드로 실행합니다. 실패해도 VM 상태는 바꾸지 않습니다.
+func (s *K8sService) AttachVMVolumeAsync(vm *models.VirtualMachine, volume *models.VolumeAttachment, traceID string) uint {
+	return s.queueAsync(s.attachVolumeOperation(vm, volume), traceID)
+}
+
+func (s *K8sService) attachVolumeOperation(vm *models.VirtualMachine, volume *models.VolumeAttachment) AsyncOperation {
+	return AsyncOperation{
+		Name:       "vm.volume.attach",
+		Target:     "volume/" + volume.Name,
+		Tenant:     metrics.TenantLabelFor(vm.UserID),
+		Owner:      vm.UserID,
+		Run:        func() error { return s.AttachVMVolume(vm, volume) },
+		Compensate: s.markVolumeFailed(volume, "volume.attach"),
+		payload:    asyncPayload{VM: vm.Name, Volume: volume.Name},
+	}
+}
+
+// DetachVMVolume은 추가 디스크를 VM에서 분리하고 DataVolume과 레코드를 삭제합니다. (디스크 데이터도 삭제됨)
+// 1. DB 상태를 Detaching으로 변경
+// 2. VM 스펙에 남아 있으면 removevolume 호출 후 게스트에서 사라질 때까지 대기
+// 3. DataVolume과 레코드 삭제
+func (s *K8sService) DetachVMVolume(vm *models.VirtualMachine, volume *models.VolumeAttachment) error {
+	ctx := s.baseContext()
+	volumeService := s.volumeService
+
+	if err := volumeService.UpdateVolumeStatus(volume.ID, models.VolumeStatusDetaching, ""); err != nil {
+		return fmt.Errorf("failed to update volume status to Detaching: %w", err)
+	}
+
+	attached, err := s.vmHasVolume(ctx, vm, volume.Name)
+	if err != nil {
+		return err
+	}
+	if attached {
+		body, _ := json.Marshal(map[string]interface{}{"name": volume.Name})
+
+		path, err := s.putVMSubresourceBody(ctx, "virtualmachines", vm.Namespace, vm.Name, "removevolume", body)
+		if err != nil {
+			return err
+		}
+		s.recordVMPatch(vm.Name, "volume.detach", path+" "+volume.Name)
+
+		if err := s.waitForVolumeStatus(ctx, vm, volume.Name, false); err != nil {
+			return err
+		}
+	}
+
+	err = s.dynamicClient.Resource(gvrDataVolume).Namespace(vm.Namespace).Delete(ctx, volume.Name, deleteOptions("DataVolume", nil))
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete data volume: %v", err)
+	}
+
+	return volumeService.DeleteVolume(volume.ID)
+}
+
+// DetachVMVolumeAsync는 추가 디스크 분리를 백그라운드로 실행합니다.
+func (s *K8sService) DetachVMVolumeAsync(vm *models.VirtualMachine, volume *models.VolumeAttachment, traceID string) uint {
+	return s.queueAsync(s.detachVolumeOperation(vm, volume), traceID)
+}
+
+func (s *K8sService) detachVolumeOperation(vm *models.VirtualMachine, volume *models.VolumeAttachment) AsyncOperation {
+	return AsyncOperation{
+		Name:       "vm.volume.detach",
+		Target:     "volume/" + volume.Name,
+		Tenant:     metrics.TenantLabelFor(vm.UserID),
+		Owner:      vm.UserID,
+		Run:        func() error { return s.DetachVMVolume(vm, volume) },
+		Compensate: s.markVolumeFailed(volume, "volume.detach"),
+		payload:    asyncPayload{VM: vm.Name, Volume: volume.Name},
+	}
+}
+
+// vmHasVolume은 VM 스펙(spec.template.spec.volumes)에 volume이 포함되어 있는지 확인합니다. (VM 리소스가 없으면 false)
+func (s *K8sService) vmHasVolume(ctx context.Context, vm *models.VirtualMachine, name string) (bool, error) {
+	obj, err := s.dynamicClient.Resource(gvrVM).Namespace(vm.Namespace).Get(ctx, vm.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get VM: %v", err)
+	}
+
+	volumes, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "volumes")
+	for _, v := range volumes {
+		if entry, ok := v.(map[string]interface{}); ok && entry["name"] == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// waitForVolumeStatus는 VMI의 status.volumeStatus에서 핫플러그 디스크가 Ready가 되거나(ready=true)
+// 사라질 때까지(ready=false) 5초 간격으로 폴링합니다. VMI가 없으면(정지된 VM) 기다리지 않습니다.
+func (s *K8sService) waitForVolumeStatus(ctx context.Context, vm *models.VirtualMachine, name string, ready bool) error {
+	timeout := time.After(volumeHotplugTimeout)
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		obj, err := s.dynamicClient.Resource(gvrVMI).Namespace(vm.Namespace).Get(ctx, vm.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get VM instance: %v", err)
+		}
+
+		phase, found := "", false
+		statuses, _, _ := unstructured.NestedSlice(obj.Object, "status", "volumeStatus")
+		for _, v := range statuses {
+			if entry, ok := v.(map[string]interface{}); ok && entry["name"] == name {
+				phase, _, _ = unstructured.NestedString(entry, "phase")
+				found = true
+			}
+		}
+
+		if ready && found && phase == "Ready" {
+			return nil
+		}
+		if !ready && !found {
+			return nil
+		}
+
+		select {
+		case <-timeout:
+			return fmt.Errorf("timeout waiting for volume %s (phase: %s)", name, phase)
+		case <-ctx.Done():
+			return fmt.Errorf("stopped waiting for volume %s: %w", name, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// deleteVMVolumes는 VM 삭제 시 추가 디스크(DataVolume)와 레코드를 모두 삭제합니다.
+// VM 리소스가 이미 삭제된 뒤 호출되므로 핫플러그 해제는 하지 않습니다.
+func (s *K8sService) deleteVMVolumes(vm *models.VirtualMachine) error {
+	volumeService := s.volumeService
+
+	volumes, err := volumeService.FetchVmVolumes(vm.Name)
+	if err != nil {
+		return err
+	}
+
+	for _, volume := range volumes {
+		err := s.dynamicClient.Resource(gvrDataVolume).Namespace(vm.Namespace).Delete(s.baseContext(), volume.Name, deleteOptions("DataVolume", nil))
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete data volume %s: %v", volume.Name, err)
+		}
+		if err := volumeService.DeleteVolume(volume.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// markVolumeFailed는 추가 디스크 작업이 최종 실패했을 때 디스크 상태만 Failed로 바꿉니다. (VM은 계속 사용 가능)
+func (s *K8sService) markVolumeFailed(volume *models.VolumeAttachment, operation string) func(err error) {
+	return func(err error) {
+		s.vmEventService.Record(models.VmEvent{
+			VmName:    volume.VmName,
+			Type:      models.VmEventOperationFailed,
+			Operation: operation,
+			Detail:    err.Error(),
+		})
+
+		if errStatus := s.volumeService.UpdateVolumeStatus(volume.ID, models.VolumeStatusFailed, err.Error()); errStatus != nil {
+			slog.Error("async: failed to mark volume as Failed", "volume", volume.Name, "error", errStatus)
+		}
+	}
+}
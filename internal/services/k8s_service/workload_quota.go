@@ -0,0 +1,282 @@
+package k8s_service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ResourceQuota는 라벨로 파드를 고를 수 없으므로, VM 파드(virt-launcher)에 전용 PriorityClass를 붙여
+// PriorityClass scope로 VM 파드와 일반 파드의 쿼터를 나눕니다.
+// 일반 파드가 이 클래스를 쓰지 못하도록 deny-vm-priority-class admission 정책으로 막습니다.
+const (
+	vmPriorityClass   = "tenant-vm"
+	vmPodQuotaName    = "tenant-vm-pods"
+	plainPodQuotaName = "tenant-pods"
+	podLimitRangeName = "tenant-pod-defaults"
+	defaultPodCPU     = "100m"
+	defaultPodMemory  = "128Mi"
+)
+
+// VM 파드 PriorityClass가 설치되었는지 여부 (설치 전에는 클래스 없이 렌더링해야 파드 생성이 거부되지 않음)
+var vmPriorityClassReady atomic.Bool
+
+// workloadQuotaHard는 env 값으로 ResourceQuota hard 목록을 만듭니다. 값이 모두 비어 있으면 nil (쿼터 없음)
+func workloadQuotaHard(envs map[corev1.ResourceName]string) corev1.ResourceList {
+	hard := corev1.ResourceList{}
+	for name, env := range envs {
+		raw := strings.TrimSpace(os.Getenv(env))
+		if raw == "" {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(raw)
+		if err != nil {
+			slog.Warn("workload quota: ignoring invalid value", "env", env, "value", raw, "error", err)
+			continue
+		}
+		hard[name] = quantity
+	}
+	if len(hard) == 0 {
+		return nil
+	}
+	return hard
+}
+
+// vmPodQuotaHard는 네임스페이스의 VM 파드 전체에 적용되는 requests 한도입니다.
+// (WORKLOAD_QUOTA_VM_CPU, WORKLOAD_QUOTA_VM_MEMORY, WORKLOAD_QUOTA_VM_PODS)
+func vmPodQuotaHard() corev1.ResourceList {
+	return workloadQuotaHard(map[corev1.ResourceName]string{
+		corev1.ResourceRequestsCPU:    "WORKLOAD_QUOTA_VM_CPU",
+		corev1.ResourceRequestsMemory: "WORKLOAD_QUOTA_VM_MEMORY",
+		corev1.ResourcePods:           "WORKLOAD_QUOTA_VM_PODS",
+	})
+}
+
+// plainPodQuotaHard는 VM이 아닌 파드 전체에 적용되는 requests 한도입니다.
+// (WORKLOAD_QUOTA_POD_CPU, WORKLOAD_QUOTA_POD_MEMORY, WORKLOAD_QUOTA_POD_PODS)
+func plainPodQuotaHard() corev1.ResourceList {
+	return workloadQuotaHard(map[corev1.ResourceName]string{
+		corev1.ResourceRequestsCPU:    "WORKLOAD_QUOTA_POD_CPU",
+		corev1.ResourceRequestsMemory: "WORKLOAD_QUOTA_POD_MEMORY",
+		corev1.ResourcePods:           "WORKLOAD_QUOTA_POD_PODS",
+	})
+}
+
+func envQuantity(env, fallback string) resource.Quantity {
+	if raw := strings.TrimSpace(os.Getenv(env)); raw != "" {
+		if quantity, err := resource.ParseQuantity(raw); err == nil {
+			return quantity
+		}
+		slog.Warn("workload quota: ignoring invalid value", "env", env, "value", raw)
+	}
+	return resource.MustParse(fallback)
+}
+
+func workloadQuotaMeta(name, namespace string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      name,
+		Namespace: namespace,
+		Labels:    map[string]string{managedByLabel: managedByValue},
+	}
+}
+
+func priorityClassScope(operator corev1.ScopeSelectorOperator) *corev1.ScopeSelector {
+	return &corev1.ScopeSelector{
+		MatchExpressions: []corev1.ScopedResourceSelectorRequirement{{
+			ScopeName: corev1.ResourceQuotaScopePriorityClass,
+			Operator:  operator,
+			Values:    []string{vmPriorityClass},
+		}},
+	}
+}
+
+// ensureResourceQuota는 쿼터를 생성/갱신하고, hard가 비어 있으면 기존 쿼터를 삭제합니다.
+func (s *K8sService) ensureResourceQuota(namespace, name string, hard corev1.ResourceList, operator corev1.ScopeSelectorOperator) error {
+	ctx := s.baseContext()
+	quotas := s.clientset.CoreV1().ResourceQuotas(namespace)
+
+	existing, err := quotas.Get(ctx, name, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get resource quota %s: %v", name, err)
+	}
+	found := err == nil
+
+	if hard == nil {
+		if found {
+			if err := quotas.Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				return fmt.Errorf("failed to delete resource quota %s: %v", name, err)
+			}
+		}
+		return nil
+	}
+
+	spec := corev1.ResourceQuotaSpec{Hard: hard, ScopeSelector: priorityClassScope(operator)}
+	if !found {
+		quota := &corev1.ResourceQuota{ObjectMeta: workloadQuotaMeta(name, namespace), Spec: spec}
+		if _, err := quotas.Create(ctx, quota, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create resource quota %s: %v", name, err)
+		}
+		return nil
+	}
+
+	existing.Spec = spec
+	if _, err := quotas.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update resource quota %s: %v", name, err)
+	}
+	return nil
+}
+
+// ensurePodLimitRange는 requests를 지정하지 않은 컨테이너에 기본 requests를 채우는 LimitRange를 적용합니다.
+// requests 쿼터가 있는 네임스페이스에서는 requests가 없는 파드가 거부되므로 일반 파드를 위해 필요합니다.
+// LimitRange도 파드를 고를 수 없으므로 기본 limit이나 max는 두지 않습니다. (VM 파드의 CPU/메모리를 제한하게 됨)
+// virt-launcher 컨테이너는 항상 requests를 지정하므로 기본값의 영향을 받지 않습니다.
+func (s *K8sService) ensurePodLimitRange(namespace string, enabled bool) error {
+	ctx := s.baseContext()
+	limitRanges := s.clientset.CoreV1().LimitRanges(namespace)
+
+	existing, err := limitRanges.Get(ctx, podLimitRangeName, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get limit range: %v", err)
+	}
+	found := err == nil
+
+	if !enabled {
+		if found {
+			if err := limitRanges.Delete(ctx, podLimitRangeName, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				return fmt.Errorf("failed to delete limit range: %v", err)
+			}
+		}
+		return nil
+	}
+
+	spec := corev1.LimitRangeSpec{
+		Limits: []corev1.LimitRangeItem{{
+			Type: corev1.LimitTypeContainer,
+			DefaultRequest: corev1.ResourceList{
+				corev1.ResourceCPU:    envQuantity("WORKLOAD_POD_DEFAULT_CPU", defaultPodCPU),
+				corev1.ResourceMemory: envQuantity("WORKLOAD_POD_DEFAULT_MEMORY", defaultPodMemory),
+			},
+		}},
+	}
+	if !found {
+		limitRange := &corev1.LimitRange{ObjectMeta: workloadQuotaMeta(podLimitRangeName, namespace), Spec: spec}
+		if _, err := limitRanges.Create(ctx, limitRange, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create limit range: %v", err)
+		}
+		return nil
+	}
+
+	existing.Spec = spec
+	if _, err := limitRanges.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update limit range: %v", err)
+	}
+	return nil
+}
+
+// applyWorkloadQuotas는 네임스페이스에 VM 파드/일반 파드 쿼터와 기본 requests LimitRange를 맞춥니다.
+// env로 설정하지 않은 쿼터는 삭제하며, 쿼터가 하나도 없으면 LimitRange도 두지 않습니다.
+func (s *K8sService) applyWorkloadQuotas(namespace string) error {
+	vmHard, podHard := vmPodQuotaHard(), plainPodQuotaHard()
+
+	if err := s.ensureResourceQuota(namespace, vmPodQuotaName, vmHard, corev1.ScopeSelectorOpIn); err != nil {
+		return err
+	}
+	if err := s.ensureResourceQuota(namespace, plainPodQuotaName, podHard, corev1.ScopeSelectorOpNotIn); err != nil {
+		return err
+	}
+	return s.ensurePodLimitRange(namespace, vmHard != nil || podHard != nil)
+}
+
+// checkVMPriorityClass는 VM 파드용 PriorityClass(부트스트랩 번들)가 설치되었는지 확인합니다.
+func (s *K8sService) checkVMPriorityClass() error {
+	if _, err := s.clientset.SchedulingV1().PriorityClasses().Get(s.baseContext(), vmPriorityClass, metav1.GetOptions{}); err != nil {
+		return fmt.Errorf("priority class %s is not available (see startup bootstrap report): %v", vmPriorityClass, err)
+	}
+	vmPriorityClassReady.Store(true)
+	return nil
+}
+
+// EnsureWorkloadQuotas는 서버 시작 시 VM 파드 PriorityClass를 확인하고,
+// 기존 VM 템플릿에 클래스를 붙인 뒤 모든 테넌트 네임스페이스의 쿼터를 현재 설정에 맞춥니다.
+// PriorityClass는 Bootstrap이 설치하므로 그 이후에 호출해야 합니다.
+func (s *K8sService) EnsureWorkloadQuotas() error {
+	if err := s.checkVMPriorityClass(); err != nil {
+		return err
+	}
+	if err := s.migrateVMPriorityClass(); err != nil {
+		s.log().Warn("workload quota: failed to set priority class on existing VMs", "error", err)
+	}
+
+	namespaces, err := s.clientset.CoreV1().Namespaces().List(s.baseContext(), metav1.ListOptions{LabelSelector: tenantLabel + "=true"})
+	if err != nil {
+		return fmt.Errorf("failed to list tenant namespaces: %v", err)
+	}
+	for _, namespace := range namespaces.Items {
+		if err := s.applyWorkloadQuotas(namespace.Name); err != nil {
+			s.log().Warn("workload quota: failed to apply quota", "namespace", namespace.Name, "error", err)
+		}
+	}
+	return nil
+}
+
+// migrateVMPriorityClass는 클래스 도입 이전에 만들어진 플랫폼 VM 템플릿에 PriorityClass를 붙입니다.
+// 붙이지 않으면 재시작한 VM 파드가 일반 파드 쿼터로 계산됩니다. (실행 중인 VMI는 다음 시작부터 적용)
+func (s *K8sService) migrateVMPriorityClass() error {
+	ctx := s.baseContext()
+	list, err := s.dynamicClient.Resource(gvrVM).Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		LabelSelector: managedByLabel + "=" + managedByValue,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list virtual machines: %v", err)
+	}
+
+	patch, _ := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{"priorityClassName": vmPriorityClass},
+			},
+		},
+	})
+
+	for _, item := range list.Items {
+		current, _, _ := unstructured.NestedString(item.Object, "spec", "template", "spec", "priorityClassName")
+		if current != "" {
+			continue
+		}
+		if _, err := s.dynamicClient.Resource(gvrVM).Namespace(item.GetNamespace()).Patch(
+			ctx, item.GetName(), types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			s.log().Warn("workload quota: failed to patch VM", "namespace", item.GetNamespace(), "vm", item.GetName(), "error", err)
+			continue
+		}
+		s.recordVMPatch(item.GetName(), "priority-class", string(patch))
+	}
+	return nil
+}
+
+// setVMPriorityClass는 VirtualMachine 템플릿에 VM 파드 PriorityClass를 지정합니다.
+// 템플릿에 이미 클래스가 있으면 그대로 둡니다.
+func setVMPriorityClass(objs []*unstructured.Unstructured) {
+	if !vmPriorityClassReady.Load() {
+		return
+	}
+
+	path := []string{"spec", "template", "spec", "priorityClassName"}
+	for _, obj := range objs {
+		if obj.GetKind() != "VirtualMachine" {
+			continue
+		}
+		if current, _, _ := unstructured.NestedString(obj.Object, path...); current == "" {
+			unstructured.SetNestedField(obj.Object, vmPriorityClass, path...)
+		}
+	}
+}
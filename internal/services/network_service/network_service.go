@@ -0,0 +1,105 @@
+package networkservice
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"vm-controller/internal/db"
+	"vm-controller/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type NetworkService struct {
+}
+
+func NewNetworkService() *NetworkService {
+	return &NetworkService{}
+}
+
+var nameRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// FetchNetworks는 네트워크 카탈로그를 반환합니다. enabledOnly가 true면 신규 VM에서 선택 가능한 항목만 반환합니다.
+func (s *NetworkService) FetchNetworks(enabledOnly bool) ([]models.Network, error) {
+	db := db.GetDB()
+
+	var networks []models.Network
+
+	query := db.Order("name")
+	if enabledOnly {
+		query = query.Where("enabled = true")
+	}
+
+	if err := query.Find(&networks).Error; err != nil {
+		return nil, err
+	}
+
+	return networks, nil
+}
+
+func (s *NetworkService) FetchNetwork(name string) (*models.Network, error) {
+	db := db.GetDB()
+
+	var network models.Network
+
+	if err := db.Where("name = ?", name).First(&network).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &network, nil
+}
+
+type SaveNetworkParams struct {
+	Name          string
+	Description   string
+	NadNamespace  string
+	NadName       string
+	AllowStaticIP bool
+	Enabled       bool
+}
+
+// SaveNetwork는 카탈로그 항목을 추가하거나 (같은 이름이 있으면) 갱신합니다.
+func (s *NetworkService) SaveNetwork(params SaveNetworkParams) (*models.Network, error) {
+	for _, value := range []string{params.Name, params.NadNamespace, params.NadName} {
+		if !nameRegex.MatchString(value) {
+			return nil, fmt.Errorf("invalid name: %s (must be DNS-1123 compliant)", value)
+		}
+	}
+
+	db := db.GetDB()
+
+	network := models.Network{Name: params.Name}
+	if err := db.Where("name = ?", params.Name).FirstOrInit(&network).Error; err != nil {
+		return nil, err
+	}
+
+	network.Description = params.Description
+	network.NadNamespace = params.NadNamespace
+	network.NadName = params.NadName
+	network.AllowStaticIP = params.AllowStaticIP
+	network.Enabled = params.Enabled
+
+	if err := db.Save(&network).Error; err != nil {
+		return nil, err
+	}
+
+	return &network, nil
+}
+
+// DeleteNetwork는 카탈로그 항목을 삭제합니다. 이미 연결된 VM의 NIC에는 영향이 없습니다.
+func (s *NetworkService) DeleteNetwork(name string) error {
+	db := db.GetDB()
+
+	result := db.Where("name = ?", name).Delete(&models.Network{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("network %s not found", name)
+	}
+
+	return nil
+}
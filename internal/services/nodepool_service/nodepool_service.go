@@ -0,0 +1,74 @@
+package nodepoolservice
+
+import (
+	"errors"
+	"vm-controller/internal/db"
+	"vm-controller/internal/models"
+
+	"gorm.io/gorm/clause"
+)
+
+type NodePoolService struct {
+}
+
+func NewNodePoolService() *NodePoolService {
+	return &NodePoolService{}
+}
+
+var ErrNotInMaintenance = errors.New("node pool is not in maintenance")
+
+// FetchMaintenances는 유지보수 중인 노드 풀 목록을 반환합니다.
+func (s *NodePoolService) FetchMaintenances() ([]models.NodePoolMaintenance, error) {
+	db := db.GetDB()
+
+	var maintenances []models.NodePoolMaintenance
+	if err := db.Order("pool").Find(&maintenances).Error; err != nil {
+		return nil, err
+	}
+
+	return maintenances, nil
+}
+
+// MaintenancePools는 신규 VM을 배치하지 않을 노드 풀 이름을 반환합니다.
+func (s *NodePoolService) MaintenancePools() ([]string, error) {
+	db := db.GetDB()
+
+	var pools []string
+	if err := db.Model(&models.NodePoolMaintenance{}).Order("pool").Pluck("pool", &pools).Error; err != nil {
+		return nil, err
+	}
+
+	return pools, nil
+}
+
+// SetMaintenance는 노드 풀을 유지보수로 표시합니다. 이미 유지보수 중이면 사유만 갱신합니다.
+func (s *NodePoolService) SetMaintenance(pool, reason string, actorId *uint) (*models.NodePoolMaintenance, error) {
+	db := db.GetDB()
+
+	maintenance := &models.NodePoolMaintenance{Pool: pool, Reason: reason, ActorID: actorId}
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "pool"}},
+		DoUpdates: clause.AssignmentColumns([]string{"reason", "actor_id", "updated_at"}),
+	}).Create(maintenance).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return maintenance, nil
+}
+
+// ClearMaintenance는 노드 풀의 유지보수를 해제합니다. 유지보수 중이 아니면 ErrNotInMaintenance를 반환합니다.
+func (s *NodePoolService) ClearMaintenance(pool string) error {
+	db := db.GetDB()
+
+	// 같은 풀을 다시 유지보수로 표시할 수 있도록 영구 삭제 (pool unique index)
+	result := db.Unscoped().Where("pool = ?", pool).Delete(&models.NodePoolMaintenance{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotInMaintenance
+	}
+
+	return nil
+}
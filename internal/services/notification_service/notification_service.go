@@ -0,0 +1,85 @@
+package notificationservice
+
+import (
+	"log/slog"
+	"vm-controller/internal/coalesce"
+	"vm-controller/internal/db"
+	"vm-controller/internal/models"
+)
+
+type NotificationService struct {
+	// 관리자가 수정한 문구 조회를 합치고 짧게 캐시 (알림마다 DB를 조회하지 않도록)
+	overrideReads *coalesce.Group[map[string]models.NotificationTemplate]
+}
+
+func NewNotificationService() *NotificationService {
+	return &NotificationService{
+		overrideReads: coalesce.New[map[string]models.NotificationTemplate]("notification_templates"),
+	}
+}
+
+// Notify는 사용자에게 인앱 알림을 생성합니다.
+func (s *NotificationService) Notify(userId uint, title, message string) error {
+	db := db.GetDB()
+
+	notification := models.Notification{
+		UserID:  userId,
+		Title:   title,
+		Message: message,
+	}
+
+	if err := db.Create(&notification).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NotifyTemplate은 key의 알림 문구를 사용자의 언어로 만들어 인앱 알림을 생성합니다.
+func (s *NotificationService) NotifyTemplate(userId uint, key string, data Data) error {
+	title, message, err := s.Render(key, s.userLocale(userId), data)
+	if err != nil {
+		return err
+	}
+
+	return s.Notify(userId, title, message)
+}
+
+// userLocale은 사용자가 지정한 알림 언어입니다. 지정하지 않았거나 조회에 실패하면 빈 문자열(기본 언어)입니다.
+func (s *NotificationService) userLocale(userId uint) string {
+	db := db.GetDB()
+
+	var locale string
+	if err := db.Model(&models.User{}).Where("id = ?", userId).Select("locale").Scan(&locale).Error; err != nil {
+		slog.Warn("notification: failed to fetch user locale", "user_id", userId, "error", err)
+		return ""
+	}
+	return locale
+}
+
+func (s *NotificationService) FetchUserNotifications(userId string, unreadOnly bool) ([]models.Notification, error) {
+	db := db.GetDB()
+
+	var notifications []models.Notification
+
+	query := db.Where("user_id = ?", userId)
+	if unreadOnly {
+		query = query.Where("is_read = false")
+	}
+
+	if err := query.Order("created_at desc").Find(&notifications).Error; err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+func (s *NotificationService) MarkRead(userId string, notificationId uint) error {
+	db := db.GetDB()
+
+	if err := db.Model(&models.Notification{}).Where("id = ? AND user_id = ?", notificationId, userId).Update("is_read", true).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package notificationservice
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"vm-controller/internal/db"
+	"vm-controller/internal/models"
+
+	"gorm.io/gorm/clause"
+)
+
+// TemplateView는 관리자 화면에 표시하는 알림 종류/언어별 현재 문구입니다.
+type TemplateView struct {
+	Key         string   `json:"key"`
+	Description string   `json:"description"`
+	Variables   []string `json:"variables"`
+	Locale      string   `json:"locale"`
+	Template
+	Overridden bool     `json:"overridden"` // 관리자가 수정한 문구를 사용 중
+	Default    Template `json:"default"`    // 코드에 정의된 기본 문구
+}
+
+func overrideKey(key, locale string) string {
+	return key + "/" + locale
+}
+
+// overrides는 관리자가 수정한 문구를 "key/locale" 키로 반환합니다. 반환된 map은 호출자끼리 공유되므로 수정하면 안 됩니다.
+func (s *NotificationService) overrides() (map[string]models.NotificationTemplate, error) {
+	return s.overrideReads.Do("all", func() (map[string]models.NotificationTemplate, error) {
+		db := db.GetDB()
+
+		var rows []models.NotificationTemplate
+		if err := db.Find(&rows).Error; err != nil {
+			return nil, err
+		}
+
+		result := make(map[string]models.NotificationTemplate, len(rows))
+		for _, row := range rows {
+			result[overrideKey(row.Key, row.Locale)] = row
+		}
+		return result, nil
+	})
+}
+
+// ListTemplates는 모든 알림 종류와 언어의 현재 문구를 반환합니다.
+func (s *NotificationService) ListTemplates() ([]TemplateView, error) {
+	overrides, err := s.overrides()
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]TemplateView, 0, len(Templates)*len(Locales))
+	for _, def := range Templates {
+		for _, locale := range Locales {
+			view := TemplateView{
+				Key:         def.Key,
+				Description: def.Description,
+				Variables:   def.Variables,
+				Locale:      locale,
+				Template:    def.Locales[locale],
+				Default:     def.Locales[locale],
+			}
+			if row, ok := overrides[overrideKey(def.Key, locale)]; ok {
+				view.Template = Template{Title: row.Title, Message: row.Message}
+				view.Overridden = true
+			}
+			views = append(views, view)
+		}
+	}
+	return views, nil
+}
+
+// SetTemplate은 알림 문구를 수정합니다. 템플릿 문법이 틀렸거나 Variables에 없는 값을 참조하면 ErrInvalidTemplate을 반환합니다.
+// 다른 API 서버에는 READ_COALESCE_TTL 이후 반영됩니다.
+func (s *NotificationService) SetTemplate(key, locale string, tmpl Template, actorId uint) error {
+	def, ok := definition(key)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownTemplate, key)
+	}
+	if !IsLocale(locale) {
+		return fmt.Errorf("%w: %s", ErrUnknownLocale, locale)
+	}
+	if tmpl.Title == "" || tmpl.Message == "" {
+		return fmt.Errorf("%w: title and message are required", ErrInvalidTemplate)
+	}
+	if _, _, err := tmpl.render(def.sampleData()); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+
+	db := db.GetDB()
+
+	row := models.NotificationTemplate{Key: key, Locale: locale, Title: tmpl.Title, Message: tmpl.Message, UpdatedBy: &actorId}
+	if err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "template_key"}, {Name: "locale"}},
+		DoUpdates: clause.AssignmentColumns([]string{"title", "message", "updated_at", "updated_by"}),
+	}).Create(&row).Error; err != nil {
+		return err
+	}
+
+	s.overrideReads.Purge()
+	return nil
+}
+
+// ResetTemplate은 수정한 문구를 지워 기본 문구로 되돌립니다.
+func (s *NotificationService) ResetTemplate(key, locale string) error {
+	db := db.GetDB()
+
+	result := db.Where("template_key = ? AND locale = ?", key, locale).Delete(&models.NotificationTemplate{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTemplateNotFound
+	}
+
+	s.overrideReads.Purge()
+	return nil
+}
+
+// Render는 locale의 알림 문구를 만듭니다. locale이 비어 있거나 지원하지 않으면 DefaultLocale을 사용합니다.
+// 관리자가 수정한 문구를 렌더링하지 못하면 알림이 빠지지 않도록 기본 문구를 사용합니다.
+func (s *NotificationService) Render(key, locale string, data Data) (string, string, error) {
+	def, ok := definition(key)
+	if !ok {
+		return "", "", fmt.Errorf("%w: %s", ErrUnknownTemplate, key)
+	}
+	if !IsLocale(locale) {
+		locale = DefaultLocale()
+	}
+
+	overrides, err := s.overrides()
+	if err != nil {
+		slog.Warn("notification: failed to load template overrides", "error", err)
+	} else if row, ok := overrides[overrideKey(key, locale)]; ok {
+		title, message, err := Template{Title: row.Title, Message: row.Message}.render(data)
+		if err == nil {
+			return title, message, nil
+		}
+		slog.Warn("notification: failed to render template override, using default", "key", key, "locale", locale, "error", err)
+	}
+
+	title, message, err := def.Locales[locale].render(data)
+	if err != nil {
+		return "", "", errors.Join(ErrInvalidTemplate, err)
+	}
+	return title, message, nil
+}
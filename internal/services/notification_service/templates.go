@@ -0,0 +1,312 @@
+package notificationservice
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"text/template"
+)
+
+// 알림 언어
+const (
+	LocaleKorean  = "ko"
+	LocaleEnglish = "en"
+)
+
+// Locales는 알림 문구를 제공하는 언어입니다. 모든 템플릿은 각 언어의 기본 문구를 가집니다.
+var Locales = []string{LocaleKorean, LocaleEnglish}
+
+var (
+	ErrUnknownTemplate  = errors.New("unknown notification template")
+	ErrUnknownLocale    = errors.New("unsupported locale")
+	ErrInvalidTemplate  = errors.New("invalid notification template")
+	ErrTemplateNotFound = errors.New("notification template override not found")
+)
+
+// Data는 템플릿에 넣는 값입니다. 템플릿의 Variables를 모두 채워야 합니다.
+type Data map[string]any
+
+// Template은 한 언어의 알림 제목/본문입니다. text/template 문법으로 {{.vm}} 처럼 값을 넣습니다.
+type Template struct {
+	Title   string `json:"title"`
+	Message string `json:"message"`
+}
+
+// TemplateDefinition은 알림 종류별 기본 문구와 사용할 수 있는 값입니다.
+type TemplateDefinition struct {
+	Key         string              `json:"key"`
+	Description string              `json:"description"`
+	Variables   []string            `json:"variables"`
+	Locales     map[string]Template `json:"locales"`
+}
+
+// Templates는 서버가 보내는 모든 알림의 기본 문구입니다. 관리자는 DB에 저장한 문구로 언어별로 덮어쓸 수 있습니다.
+var Templates = []TemplateDefinition{
+	{
+		Key:         "vm.expire.stop",
+		Description: "VM stopped because its lease expired",
+		Variables:   []string{"vm", "delete_at"},
+		Locales: map[string]Template{
+			LocaleKorean:  {"VM 사용 기간 만료", "VM {{.vm}}의 사용 기간이 만료되어 정지되었습니다. {{.delete_at}} 이후 삭제되며, 그 전에 연장하면 다시 시작할 수 있습니다."},
+			LocaleEnglish: {"VM lease expired", "VM {{.vm}} was stopped because its lease expired. It will be deleted after {{.delete_at}}; extend the lease before then to start it again."},
+		},
+	},
+	{
+		Key:         "vm.expire.delete",
+		Description: "VM deleted after its lease expired",
+		Variables:   []string{"vm"},
+		Locales: map[string]Template{
+			LocaleKorean:  {"VM 사용 기간 만료", "VM {{.vm}}의 사용 기간이 만료되어 삭제되었습니다."},
+			LocaleEnglish: {"VM lease expired", "VM {{.vm}} was deleted because its lease expired."},
+		},
+	},
+	{
+		Key:         "vm.import_failed",
+		Description: "VM cleaned up because its disk image could not be prepared",
+		Variables:   []string{"vm", "reason"},
+		Locales: map[string]Template{
+			LocaleKorean:  {"VM {{.vm}} 디스크 준비 실패", "디스크 이미지 준비에 실패하여 VM을 정리했습니다. 원인: {{.reason}}"},
+			LocaleEnglish: {"Disk preparation failed for VM {{.vm}}", "The disk image could not be prepared, so the VM was cleaned up. Reason: {{.reason}}"},
+		},
+	},
+	{
+		Key:         "vm.mining_throttled",
+		Description: "VM paused by the mining detector until an administrator reviews it",
+		Variables:   []string{"vm"},
+		Locales: map[string]Template{
+			LocaleKorean:  {"채굴 의심 VM 일시 정지", "VM {{.vm}}에서 암호화폐 채굴로 의심되는 사용 패턴이 감지되어 관리자 검토 전까지 일시 정지되었습니다. 문의는 관리자에게 해 주세요."},
+			LocaleEnglish: {"VM paused for suspected mining", "VM {{.vm}} showed a usage pattern that looks like cryptocurrency mining and is paused until an administrator reviews it. Please contact an administrator."},
+		},
+	},
+	{
+		Key:         "vm.flavor.completed",
+		Description: "VM flavor change completed",
+		Variables:   []string{"vm", "from", "to"},
+		Locales: map[string]Template{
+			LocaleKorean:  {"VM 요금제 변경 완료", "VM {{.vm}}의 요금제가 {{.from}}에서 {{.to}}(으)로 변경되었습니다."},
+			LocaleEnglish: {"VM flavor changed", "VM {{.vm}} was changed from {{.from}} to {{.to}}."},
+		},
+	},
+	{
+		Key:         "vm.flavor.failed",
+		Description: "VM flavor change failed",
+		Variables:   []string{"vm", "to", "reason"},
+		Locales: map[string]Template{
+			LocaleKorean:  {"VM 요금제 변경 실패", "VM {{.vm}}의 요금제를 {{.to}}(으)로 변경하지 못했습니다: {{.reason}}"},
+			LocaleEnglish: {"VM flavor change failed", "VM {{.vm}} could not be changed to {{.to}}: {{.reason}}"},
+		},
+	},
+	{
+		Key:         "vm.flavor.missed",
+		Description: "Scheduled VM flavor change did not run within its window",
+		Variables:   []string{"vm", "to", "window_start", "window_end"},
+		Locales: map[string]Template{
+			LocaleKorean:  {"VM 요금제 변경 누락", "VM {{.vm}}의 {{.to}} 요금제 변경이 예약한 시간대({{.window_start}} ~ {{.window_end}}) 안에 실행되지 않았습니다. 다시 예약해 주세요."},
+			LocaleEnglish: {"VM flavor change missed", "The change of VM {{.vm}} to {{.to}} did not run within the scheduled window ({{.window_start}} - {{.window_end}}). Please schedule it again."},
+		},
+	},
+	{
+		Key:         "vm.transfer.removed",
+		Description: "An administrator transferred the user's VM to another user",
+		Variables:   []string{"vm"},
+		Locales: map[string]Template{
+			LocaleKorean:  {"VM 소유자 변경", "관리자가 VM {{.vm}}의 소유자를 다른 사용자로 변경했습니다."},
+			LocaleEnglish: {"VM transferred", "VM {{.vm}} has been transferred to another user by an administrator."},
+		},
+	},
+	{
+		Key:         "vm.transfer.received",
+		Description: "An administrator transferred a VM to the user",
+		Variables:   []string{"vm"},
+		Locales: map[string]Template{
+			LocaleKorean:  {"VM 소유자 변경", "관리자가 VM {{.vm}}의 소유자를 회원님으로 변경했습니다."},
+			LocaleEnglish: {"VM transferred", "VM {{.vm}} has been transferred to you by an administrator."},
+		},
+	},
+	{
+		Key:         "deployment.job_failed",
+		Description: "A scheduled job run failed",
+		Variables:   []string{"repo", "job"},
+		Locales: map[string]Template{
+			LocaleKorean:  {"스케줄 작업 실패", "스케줄 작업 {{.repo}}의 실행({{.job}})이 실패했습니다. 실행 이력에서 로그를 확인하세요."},
+			LocaleEnglish: {"Scheduled job failed", "A run ({{.job}}) of scheduled job {{.repo}} failed. Check the run history for logs."},
+		},
+	},
+	{
+		Key:         "quota.soft_limit",
+		Description: "Usage crossed the soft quota threshold",
+		Variables:   []string{"dimension", "used", "hard"},
+		Locales: map[string]Template{
+			LocaleKorean:  {"쿼터 경고: {{.dimension}}", "{{.dimension}} 쿼터가 거의 찼습니다: {{.hard}} 중 {{.used}} 사용 중 (쿼터 임박)"},
+			LocaleEnglish: {"Quota warning: {{.dimension}}", "{{.dimension}} quota almost full: {{.used}} of {{.hard}} used after this request"},
+		},
+	},
+	{
+		Key:         "approval.requested",
+		Description: "Sent to administrators when a VM needs approval",
+		Variables:   []string{"requester", "student_id", "flavor", "vm"},
+		Locales: map[string]Template{
+			LocaleKorean:  {"VM 생성 승인 요청", "{{.requester}}({{.student_id}})님이 {{.flavor}} 요금제 VM {{.vm}} 생성을 요청했습니다."},
+			LocaleEnglish: {"VM approval requested", "{{.requester}} ({{.student_id}}) requested VM {{.vm}} with flavor {{.flavor}}."},
+		},
+	},
+	{
+		Key:         "approval.approved",
+		Description: "VM request approved and the VM was created",
+		Variables:   []string{"vm"},
+		Locales: map[string]Template{
+			LocaleKorean:  {"VM 생성 승인", "{{.vm}} VM 생성 요청이 승인되어 VM이 생성되었습니다."},
+			LocaleEnglish: {"VM request approved", "Your request for VM {{.vm}} was approved and the VM has been created."},
+		},
+	},
+	{
+		Key:         "approval.create_failed",
+		Description: "VM request approved but creating the VM failed",
+		Variables:   []string{"vm"},
+		Locales: map[string]Template{
+			LocaleKorean:  {"VM 생성 실패", "{{.vm}} VM 생성이 승인되었으나 생성에 실패했습니다. 다시 요청해 주세요."},
+			LocaleEnglish: {"VM creation failed", "Your request for VM {{.vm}} was approved, but the VM could not be created. Please request it again."},
+		},
+	},
+	{
+		Key:         "approval.rejected",
+		Description: "VM request rejected (reason may be empty)",
+		Variables:   []string{"vm", "reason"},
+		Locales: map[string]Template{
+			LocaleKorean:  {"VM 생성 거절", "{{.vm}} VM 생성 요청이 거절되었습니다.{{if .reason}} 사유: {{.reason}}{{end}}"},
+			LocaleEnglish: {"VM request rejected", "Your request for VM {{.vm}} was rejected.{{if .reason}} Reason: {{.reason}}{{end}}"},
+		},
+	},
+	{
+		Key:         "report.received",
+		Description: "Sent to administrators when a user files an abuse report",
+		Variables:   []string{"report_id", "target_type", "target", "category"},
+		Locales: map[string]Template{
+			LocaleKorean:  {"악용 신고 접수", "신고 #{{.report_id}}: {{.target_type}} {{.target}} ({{.category}})"},
+			LocaleEnglish: {"Abuse report received", "Report #{{.report_id}}: {{.target_type}} {{.target}} ({{.category}})"},
+		},
+	},
+	{
+		Key:         "report.detected",
+		Description: "Sent to administrators when the mining detector files a report",
+		Variables:   []string{"report_id", "target_type", "target", "category"},
+		Locales: map[string]Template{
+			LocaleKorean:  {"채굴 의심 VM 탐지", "신고 #{{.report_id}}: {{.target_type}} {{.target}} ({{.category}})"},
+			LocaleEnglish: {"Suspected mining detected", "Report #{{.report_id}}: {{.target_type}} {{.target}} ({{.category}})"},
+		},
+	},
+	{
+		Key:         "report.resolved",
+		Description: "Sent to the reporter when action was taken",
+		Variables:   []string{"report_id"},
+		Locales: map[string]Template{
+			LocaleKorean:  {"신고 처리 완료", "신고 #{{.report_id}}에 대한 조치가 완료되었습니다."},
+			LocaleEnglish: {"Report resolved", "Action has been taken on report #{{.report_id}}."},
+		},
+	},
+	{
+		Key:         "report.dismissed",
+		Description: "Sent to the reporter when no action was needed",
+		Variables:   []string{"report_id"},
+		Locales: map[string]Template{
+			LocaleKorean:  {"신고 검토 완료", "신고 #{{.report_id}}를 검토한 결과 조치가 필요하지 않은 것으로 판단되었습니다."},
+			LocaleEnglish: {"Report reviewed", "Report #{{.report_id}} was reviewed and no action was needed."},
+		},
+	},
+	{
+		Key:         "report.vm_stopped",
+		Description: "Sent to the owner when a reported VM was stopped",
+		Variables:   []string{"category", "vm"},
+		Locales: map[string]Template{
+			LocaleKorean:  {"신고에 따른 조치", "신고({{.category}})로 인해 VM {{.vm}}가 정지되었습니다. 문의는 관리자에게 해 주세요."},
+			LocaleEnglish: {"Action taken on a report", "VM {{.vm}} was stopped because of a report ({{.category}}). Please contact an administrator."},
+		},
+	},
+	{
+		Key:         "report.deployment_paused",
+		Description: "Sent to the owner when a reported web deployment was paused",
+		Variables:   []string{"category", "domain"},
+		Locales: map[string]Template{
+			LocaleKorean:  {"신고에 따른 조치", "신고({{.category}})로 인해 웹 배포 {{.domain}}가 일시 중지되었습니다. 문의는 관리자에게 해 주세요."},
+			LocaleEnglish: {"Action taken on a report", "Web deployment {{.domain}} was paused because of a report ({{.category}}). Please contact an administrator."},
+		},
+	},
+	{
+		Key:         "report.user_suspended",
+		Description: "Sent to the owner when their account was suspended because of a report",
+		Variables:   []string{"category"},
+		Locales: map[string]Template{
+			LocaleKorean:  {"신고에 따른 조치", "신고({{.category}})로 인해 계정이 정지되었습니다. 문의는 관리자에게 해 주세요."},
+			LocaleEnglish: {"Action taken on a report", "Your account was suspended because of a report ({{.category}}). Please contact an administrator."},
+		},
+	},
+	{
+		Key:         "report.vm_released",
+		Description: "Sent to the owner when a VM paused by the mining detector was released",
+		Variables:   []string{"vm"},
+		Locales: map[string]Template{
+			LocaleKorean:  {"신고 검토 완료", "검토 결과 VM {{.vm}}의 일시 정지가 해제되었습니다."},
+			LocaleEnglish: {"Report reviewed", "After review, VM {{.vm}} is no longer paused."},
+		},
+	},
+}
+
+// DefaultLocale은 언어를 지정하지 않은 사용자와 웹훅에 쓰는 언어입니다. (NOTIFICATION_DEFAULT_LOCALE, 기본 ko)
+func DefaultLocale() string {
+	if locale := os.Getenv("NOTIFICATION_DEFAULT_LOCALE"); IsLocale(locale) {
+		return locale
+	}
+	return LocaleKorean
+}
+
+// IsLocale은 알림 문구를 제공하는 언어인지 확인합니다.
+func IsLocale(locale string) bool {
+	return slices.Contains(Locales, locale)
+}
+
+// definition은 key의 템플릿 정의를 찾습니다.
+func definition(key string) (*TemplateDefinition, bool) {
+	for i := range Templates {
+		if Templates[i].Key == key {
+			return &Templates[i], true
+		}
+	}
+	return nil, false
+}
+
+// render는 템플릿에 data를 넣은 제목/본문을 만듭니다. Variables에 없는 값을 참조하면 에러를 반환합니다.
+func (t Template) render(data Data) (string, string, error) {
+	title, err := execute(t.Title, data)
+	if err != nil {
+		return "", "", fmt.Errorf("title: %w", err)
+	}
+	message, err := execute(t.Message, data)
+	if err != nil {
+		return "", "", fmt.Errorf("message: %w", err)
+	}
+	return title, message, nil
+}
+
+func execute(text string, data Data) (string, error) {
+	tmpl, err := template.New("").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, map[string]any(data)); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// sampleData는 관리자가 저장하려는 템플릿을 검사할 때 쓰는 값입니다. (각 값은 "{이름}")
+func (d *TemplateDefinition) sampleData() Data {
+	data := Data{}
+	for _, name := range d.Variables {
+		data[name] = "{" + name + "}"
+	}
+	return data
+}
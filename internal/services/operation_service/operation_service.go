@@ -0,0 +1,223 @@
+package operationservice
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"time"
+	"vm-controller/internal/db"
+	"vm-controller/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrOperationNotFound = errors.New("operation not found")
+	ErrOperationInFlight = errors.New("another operation is in flight")
+)
+
+// 종료된 작업 기록 기본 보존 기간 (OPERATION_RETENTION)
+const defaultRetention = 7 * 24 * time.Hour
+
+type OperationService struct {
+}
+
+func NewOperationService() *OperationService {
+	return &OperationService{}
+}
+
+// CreateOperation은 작업을 기록합니다. (ID가 채워짐)
+func (s *OperationService) CreateOperation(operation *models.Operation) error {
+	db := db.GetDB()
+
+	return db.Create(operation).Error
+}
+
+// RecordFinished는 요청 안에서 동기적으로 끝난 작업(예: VM 생성)을 완료된 작업으로 기록합니다.
+// 비동기 작업과 같은 방식으로 결과를 조회할 수 있도록 합니다.
+func (s *OperationService) RecordFinished(name, target string, userID uint, traceID string) (*models.Operation, error) {
+	now := time.Now()
+	operation := &models.Operation{
+		Name:       name,
+		Target:     target,
+		UserID:     &userID,
+		Status:     models.OperationSucceeded,
+		Attempts:   1,
+		TraceID:    traceID,
+		StartedAt:  &now,
+		FinishedAt: &now,
+	}
+
+	if err := s.CreateOperation(operation); err != nil {
+		return nil, err
+	}
+	return operation, nil
+}
+
+// CreateActiveOperation은 대상을 점유하는 작업(Queued, 또는 동기 작업의 Running)을 기록합니다. (ID가 채워짐)
+// 같은 대상에 끝나지 않은 작업이 이미 있으면 ErrOperationInFlight를 반환합니다. (idx_operations_active_target)
+func (s *OperationService) CreateActiveOperation(operation *models.Operation) error {
+	db := db.GetDB()
+
+	if err := db.Create(operation).Error; err != nil {
+		// 고유 인덱스 위반 에러는 드라이버마다 달라서, 점유 중인 작업이 있는지 다시 확인
+		if active, errActive := s.HasActiveOperation(operation.Target); errActive == nil && active {
+			return ErrOperationInFlight
+		}
+		return err
+	}
+	return nil
+}
+
+// HasActiveOperation은 대상에 끝나지 않은(Queued/Running) 작업이 있는지 확인합니다.
+func (s *OperationService) HasActiveOperation(target string) (bool, error) {
+	db := db.GetDB()
+
+	var count int64
+	err := db.Model(&models.Operation{}).
+		Where("target = ? AND status IN ?", target, models.OperationActiveStatuses).
+		Count(&count).Error
+
+	return count > 0, err
+}
+
+// CountActiveOperations는 모든 서버 인스턴스의 Queued/Running 작업 수를 반환합니다. (역압 판단에 사용)
+func (s *OperationService) CountActiveOperations() (int64, error) {
+	db := db.GetDB()
+
+	var count int64
+	err := db.Model(&models.Operation{}).Where("status IN ?", models.OperationActiveStatuses).Count(&count).Error
+	return count, err
+}
+
+// ClaimOperation은 실행할 작업 하나를 worker가 lease 동안 점유하도록 표시하고 반환합니다. (없으면 nil)
+// 다음 시도 시각이 된 Queued 작업과, 점유한 워커가 lease를 갱신하지 못한(서버 종료 등) Running 작업이 대상입니다.
+// 여러 인스턴스의 워커가 같은 작업을 가져가지 않도록 SELECT ... FOR UPDATE SKIP LOCKED로 조회합니다.
+func (s *OperationService) ClaimOperation(worker string, lease time.Duration) (*models.Operation, error) {
+	db := db.GetDB()
+
+	var operation models.Operation
+	err := db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("(status = ? AND (next_run_at IS NULL OR next_run_at <= ?)) OR (status = ? AND lease_until < ?)",
+				models.OperationQueued, now, models.OperationRunning, now).
+			Order("id").
+			Take(&operation).Error; err != nil {
+			return err
+		}
+
+		leaseUntil := now.Add(lease)
+		operation.Status = models.OperationRunning
+		operation.Attempts++
+		operation.Worker = worker
+		operation.LeaseUntil = &leaseUntil
+		operation.NextRunAt = nil
+		if operation.StartedAt == nil {
+			operation.StartedAt = &now
+		}
+
+		return tx.Model(&models.Operation{}).Where("id = ?", operation.ID).Updates(map[string]any{
+			"status":      operation.Status,
+			"attempts":    operation.Attempts,
+			"worker":      worker,
+			"lease_until": leaseUntil,
+			"next_run_at": nil,
+			"started_at":  operation.StartedAt,
+		}).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &operation, nil
+}
+
+// RenewLease는 worker가 점유한 실행 중인 작업의 lease를 연장합니다. 다른 워커가 가져갔으면 false를 반환합니다.
+func (s *OperationService) RenewLease(id uint, worker string, lease time.Duration) (bool, error) {
+	db := db.GetDB()
+
+	result := db.Model(&models.Operation{}).
+		Where("id = ? AND worker = ? AND status = ?", id, worker, models.OperationRunning).
+		Update("lease_until", time.Now().Add(lease))
+
+	return result.RowsAffected > 0, result.Error
+}
+
+// RetryOperation은 실패한 시도의 사유를 기록하고 nextRunAt 이후 다시 실행하도록 Queued로 되돌립니다.
+// 대기하는 동안 워커 슬롯과 점유를 놓으므로, 다른 작업이나 다른 인스턴스의 워커가 실행할 수 있습니다.
+func (s *OperationService) RetryOperation(id uint, reason string, nextRunAt time.Time) error {
+	db := db.GetDB()
+
+	return db.Model(&models.Operation{}).Where("id = ?", id).Updates(map[string]any{
+		"status":      models.OperationQueued,
+		"error":       reason,
+		"next_run_at": nextRunAt,
+		"worker":      "",
+		"lease_until": nil,
+	}).Error
+}
+
+// FinishOperation은 작업의 최종 결과를 기록합니다. 성공이면 이전 시도의 실패 사유는 지웁니다.
+func (s *OperationService) FinishOperation(id uint, status models.EnumOperationStatus, reason string) error {
+	db := db.GetDB()
+
+	return db.Model(&models.Operation{}).Where("id = ?", id).Updates(map[string]any{
+		"status":      status,
+		"error":       reason,
+		"finished_at": time.Now(),
+		"lease_until": nil,
+	}).Error
+}
+
+// FetchOperation은 작업을 반환합니다.
+func (s *OperationService) FetchOperation(id uint) (*models.Operation, error) {
+	db := db.GetDB()
+
+	var operation models.Operation
+	if err := db.First(&operation, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOperationNotFound
+		}
+		return nil, err
+	}
+
+	return &operation, nil
+}
+
+// Retention은 종료된 작업 기록의 보존 기간을 반환합니다.
+func (s *OperationService) Retention() time.Duration {
+	if retention, err := time.ParseDuration(os.Getenv("OPERATION_RETENTION")); err == nil && retention > 0 {
+		return retention
+	}
+	return defaultRetention
+}
+
+// PurgeExpired는 종료 후 보존 기간이 지난 작업 기록을 삭제합니다.
+func (s *OperationService) PurgeExpired() (int64, error) {
+	db := db.GetDB()
+
+	result := db.Where("finished_at IS NOT NULL AND finished_at < ?", time.Now().Add(-s.Retention())).Delete(&models.Operation{})
+	return result.RowsAffected, result.Error
+}
+
+// StartRetentionCleanup은 주기적으로 보존 기간이 지난 기록을 삭제합니다.
+func (s *OperationService) StartRetentionCleanup(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if purged, err := s.PurgeExpired(); err != nil {
+				slog.Warn("operations: failed to purge expired records", "error", err)
+			} else if purged > 0 {
+				slog.Info("operations: purged expired records", "count", purged)
+			}
+		}
+	}()
+}
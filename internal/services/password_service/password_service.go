@@ -0,0 +1,196 @@
+package passwordservice
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"vm-controller/internal/db"
+
+	"github.com/spf13/cast"
+)
+
+// VM 비밀번호 제약 (k8s_service.checkInjection과 동일하게 8~16자)
+const (
+	minLength = 8
+	maxLength = 16
+
+	defaultLength         = 16
+	defaultMinEntropyBits = 80
+)
+
+const (
+	lowerChars = "abcdefghijkmnopqrstuvwxyz" // 혼동되는 l 제외
+	upperChars = "ABCDEFGHJKLMNPQRSTUVWXYZ"  // 혼동되는 I, O 제외
+	digitChars = "23456789"                  // 혼동되는 0, 1 제외
+	// cloud-init(YAML)과 chpasswd에 그대로 들어가도 안전한 특수문자만 사용 (':', 따옴표, 공백 제외)
+	symbolChars = "!#%+-=.?_^"
+)
+
+// Policy는 생성할 비밀번호의 정책입니다.
+type Policy struct {
+	Length         int     `json:"length"`           // 비밀번호 길이 (8~16)
+	Symbols        bool    `json:"symbols"`          // 특수문자 포함 여부
+	MinEntropyBits float64 `json:"min_entropy_bits"` // 최소 엔트로피 (비트)
+}
+
+// Generator는 정책에 맞는 비밀번호를 만드는 생성기입니다.
+// RegisterGenerator로 등록하고 VM_PASSWORD_GENERATOR로 선택합니다.
+type Generator interface {
+	Generate(policy Policy) (string, error)
+	// EntropyBits는 정책으로 생성되는 비밀번호의 엔트로피(비트)입니다.
+	EntropyBits(policy Policy) float64
+}
+
+var (
+	generators   = map[string]Generator{"random": RandomGenerator{}}
+	generatorsMu sync.RWMutex
+)
+
+// RegisterGenerator는 이름으로 비밀번호 생성기를 등록합니다.
+func RegisterGenerator(name string, generator Generator) {
+	generatorsMu.Lock()
+	defer generatorsMu.Unlock()
+
+	generators[name] = generator
+}
+
+type PasswordService struct {
+}
+
+func NewPasswordService() *PasswordService {
+	return &PasswordService{}
+}
+
+// Policy는 환경 변수(VM_PASSWORD_LENGTH, VM_PASSWORD_SYMBOLS, VM_PASSWORD_MIN_ENTROPY_BITS)로 설정된 정책을 반환합니다.
+func (s *PasswordService) Policy() Policy {
+	policy := Policy{
+		Length:         defaultLength,
+		Symbols:        true,
+		MinEntropyBits: defaultMinEntropyBits,
+	}
+
+	if length, err := cast.ToIntE(os.Getenv("VM_PASSWORD_LENGTH")); err == nil && length > 0 {
+		policy.Length = length
+	}
+	if symbols, err := cast.ToBoolE(os.Getenv("VM_PASSWORD_SYMBOLS")); err == nil && os.Getenv("VM_PASSWORD_SYMBOLS") != "" {
+		policy.Symbols = symbols
+	}
+	if bits, err := cast.ToFloat64E(os.Getenv("VM_PASSWORD_MIN_ENTROPY_BITS")); err == nil && bits > 0 {
+		policy.MinEntropyBits = bits
+	}
+
+	return policy
+}
+
+func (s *PasswordService) generator() (Generator, error) {
+	name := os.Getenv("VM_PASSWORD_GENERATOR")
+	if name == "" {
+		name = "random"
+	}
+
+	generatorsMu.RLock()
+	defer generatorsMu.RUnlock()
+
+	generator, ok := generators[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown password generator: %s", name)
+	}
+	return generator, nil
+}
+
+// Generate는 설정된 생성기와 정책으로 VM 비밀번호를 생성합니다.
+// 생성된 비밀번호는 암호화 저장이 보장될 때만 발급합니다. (SECRET_ENCRYPTION_KEY)
+func (s *PasswordService) Generate() (string, error) {
+	if !db.SecretEncryptionEnabled() {
+		return "", fmt.Errorf("password generation requires SECRET_ENCRYPTION_KEY to be configured")
+	}
+
+	policy := s.Policy()
+	if policy.Length < minLength || policy.Length > maxLength {
+		return "", fmt.Errorf("invalid password policy: length must be between %d and %d", minLength, maxLength)
+	}
+
+	generator, err := s.generator()
+	if err != nil {
+		return "", err
+	}
+
+	if bits := generator.EntropyBits(policy); bits < policy.MinEntropyBits {
+		return "", fmt.Errorf("invalid password policy: %.0f bits of entropy is below the required %.0f bits", bits, policy.MinEntropyBits)
+	}
+
+	return generator.Generate(policy)
+}
+
+// RandomGenerator는 문자 종류(소문자/대문자/숫자/특수문자)를 하나 이상씩 포함하는 무작위 비밀번호를 만듭니다.
+type RandomGenerator struct{}
+
+func (RandomGenerator) classes(policy Policy) []string {
+	classes := []string{lowerChars, upperChars, digitChars}
+	if policy.Symbols {
+		classes = append(classes, symbolChars)
+	}
+	return classes
+}
+
+func (g RandomGenerator) EntropyBits(policy Policy) float64 {
+	charset := 0
+	for _, class := range g.classes(policy) {
+		charset += len(class)
+	}
+	// 종류별 최소 1자 보장으로 줄어드는 엔트로피는 무시할 만큼 작음
+	return float64(policy.Length) * math.Log2(float64(charset))
+}
+
+func (g RandomGenerator) Generate(policy Policy) (string, error) {
+	classes := g.classes(policy)
+	if policy.Length < len(classes) {
+		return "", fmt.Errorf("password length %d is too short for %d character classes", policy.Length, len(classes))
+	}
+
+	charset := ""
+	for _, class := range classes {
+		charset += class
+	}
+
+	for {
+		password := make([]byte, policy.Length)
+		for i := range password {
+			c, err := randomChar(charset)
+			if err != nil {
+				return "", err
+			}
+			password[i] = c
+		}
+
+		// 모든 문자 종류를 포함하고, YAML 스칼라로 안전하도록 영숫자로 시작하는 경우에만 채택
+		if containsAll(string(password), classes) && isAlphanumeric(password[0]) {
+			return string(password), nil
+		}
+	}
+}
+
+func randomChar(charset string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+	if err != nil {
+		return 0, err
+	}
+	return charset[n.Int64()], nil
+}
+
+func containsAll(password string, classes []string) bool {
+	for _, class := range classes {
+		if !strings.ContainsAny(password, class) {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphanumeric(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,88 @@
+package preferenceservice
+
+import (
+	"errors"
+	"regexp"
+	"vm-controller/internal/db"
+	"vm-controller/internal/models"
+	sshkeyservice "vm-controller/internal/services/ssh_key_service"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PreferenceService struct {
+	sshKeyService *sshkeyservice.SSHKeyService
+}
+
+func NewPreferenceService(sshKeyService *sshkeyservice.SSHKeyService) *PreferenceService {
+	return &PreferenceService{sshKeyService: sshKeyService}
+}
+
+var ErrInvalidDNSBase = errors.New("dns_base must be a valid domain (e.g., team or team.lab)")
+
+// 호스트 접두사 뒤에 붙는 도메인 조각 (점으로 구분된 하나 이상의 DNS 라벨)
+var dnsBaseRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)*$`)
+
+// FetchPreference는 사용자의 VM 생성 기본값을 반환합니다. 저장된 값이 없으면 빈 기본값을 반환합니다.
+// 기본값 저장 이후 삭제된 SSH 키는 결과에서 제외됩니다.
+func (s *PreferenceService) FetchPreference(userId uint) (*models.VmPreference, error) {
+	db := db.GetDB()
+
+	var pref models.VmPreference
+	if err := db.Where("user_id = ?", userId).First(&pref).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &models.VmPreference{UserID: userId}, nil
+		}
+		return nil, err
+	}
+
+	if len(pref.SSHKeyIDs) > 0 {
+		var existing []uint
+		if err := db.Model(&models.SSHKey{}).Where("user_id = ? AND id IN ?", userId, pref.SSHKeyIDs).Pluck("id", &existing).Error; err != nil {
+			return nil, err
+		}
+
+		alive := map[uint]bool{}
+		for _, id := range existing {
+			alive[id] = true
+		}
+
+		keyIds := []uint{}
+		for _, id := range pref.SSHKeyIDs {
+			if alive[id] {
+				keyIds = append(keyIds, id)
+			}
+		}
+		pref.SSHKeyIDs = keyIds
+	}
+
+	return &pref, nil
+}
+
+// SavePreference는 사용자의 VM 생성 기본값을 저장합니다. (이미지/요금제 이름은 호출자가 카탈로그로 검증)
+// SSH 키는 사용자가 소유한 키만 지정할 수 있으며, 하나라도 없으면 sshkeyservice.ErrKeyNotFound를 반환합니다.
+func (s *PreferenceService) SavePreference(pref *models.VmPreference) (*models.VmPreference, error) {
+	if pref.DNSBase != "" && !dnsBaseRegex.MatchString(pref.DNSBase) {
+		return nil, ErrInvalidDNSBase
+	}
+	if _, err := s.sshKeyService.FetchUserKeysByIds(pref.UserID, pref.SSHKeyIDs); err != nil {
+		return nil, err
+	}
+	if pref.SSHKeyIDs == nil {
+		pref.SSHKeyIDs = []uint{}
+	}
+
+	db := db.GetDB()
+
+	// 사용자당 한 행만 유지 (동시에 저장해도 마지막 요청이 반영됨)
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"image", "flavor", "ssh_key_ids", "dns_base", "updated_at"}),
+	}).Create(pref).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return s.FetchPreference(pref.UserID)
+}
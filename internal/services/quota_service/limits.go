@@ -0,0 +1,186 @@
+package quotaservice
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/db"
+	"vm-controller/internal/models"
+	notificationservice "vm-controller/internal/services/notification_service"
+
+	"github.com/spf13/cast"
+)
+
+// Dimension은 쿼터가 적용되는 자원 종류입니다.
+type Dimension string
+
+const (
+	DimensionStorage   Dimension = "storage_gi" // VM 디스크 + 관리형 DB 볼륨 (GiB)
+	DimensionVMs       Dimension = "vms"        // VM 개수
+	DimensionDatabases Dimension = "databases"  // 관리형 데이터베이스 개수
+)
+
+// 각 차원의 설정 환경 변수와 기본 hard cap (0이면 제한 없음)
+var dimensionEnvs = map[Dimension]struct {
+	hardEnv string
+	softEnv string
+	hard    int
+}{
+	DimensionStorage:   {"USER_STORAGE_QUOTA_GI", "USER_STORAGE_QUOTA_SOFT_RATIO", defaultStorageQuotaGi},
+	DimensionVMs:       {"USER_VM_QUOTA", "USER_VM_QUOTA_SOFT_RATIO", 0},
+	DimensionDatabases: {"USER_DATABASE_QUOTA", "USER_DATABASE_QUOTA_SOFT_RATIO", 0},
+}
+
+// Dimensions는 preflight 응답 등에 표시되는 순서입니다.
+var Dimensions = []Dimension{DimensionStorage, DimensionVMs, DimensionDatabases}
+
+// Limit은 한 차원의 쿼터 설정입니다.
+// Hard를 넘는 요청은 거부하고, Hard*SoftRatio 이상이 되면 알림으로 경고만 합니다.
+type Limit struct {
+	Hard      int     `json:"hard"` // 0이면 제한 없음
+	SoftRatio float64 `json:"soft_ratio"`
+}
+
+// Soft는 경고를 시작하는 사용량입니다.
+func (l Limit) Soft() int {
+	return int(math.Ceil(float64(l.Hard) * l.SoftRatio))
+}
+
+// Limit은 환경 변수로 설정된 차원별 쿼터를 반환합니다.
+func (s *QuotaService) Limit(dimension Dimension) Limit {
+	env := dimensionEnvs[dimension]
+
+	limit := Limit{Hard: env.hard, SoftRatio: defaultSoftRatio}
+	if os.Getenv(env.hardEnv) != "" {
+		if hard, err := cast.ToIntE(os.Getenv(env.hardEnv)); err == nil && hard >= 0 {
+			limit.Hard = hard
+		}
+	}
+	// 스토리지는 쿼터 없이 운영할 수 없으므로 0 이하이면 기본값 사용
+	if dimension == DimensionStorage && limit.Hard <= 0 {
+		limit.Hard = defaultStorageQuotaGi
+	}
+	if ratio, err := cast.ToFloat64E(os.Getenv(env.softEnv)); err == nil && ratio > 0 && ratio <= 1 {
+		limit.SoftRatio = ratio
+	}
+
+	return limit
+}
+
+// Usage는 사용자의 차원별 현재 사용량을 계산합니다.
+func (s *QuotaService) Usage(userId uint, dimension Dimension) (int, error) {
+	db := db.GetDB()
+
+	switch dimension {
+	case DimensionStorage:
+		return s.StorageUsageGi(userId)
+	case DimensionVMs:
+		var count int64
+		if err := db.Model(&models.VirtualMachine{}).
+			Where("user_id = ? AND is_deleted = false", userId).
+			Count(&count).Error; err != nil {
+			return 0, err
+		}
+		return int(count), nil
+	case DimensionDatabases:
+		var count int64
+		if err := db.Model(&models.ManagedDatabase{}).
+			Where("user_id = ? AND is_deleted = false", userId).
+			Count(&count).Error; err != nil {
+			return 0, err
+		}
+		return int(count), nil
+	}
+
+	return 0, fmt.Errorf("unknown quota dimension: %s", dimension)
+}
+
+// Headroom은 한 차원의 쿼터 여유량입니다. (요청을 반영한 결과 포함)
+type Headroom struct {
+	Dimension Dimension `json:"dimension"`
+	Hard      int       `json:"hard"` // 0이면 제한 없음
+	Soft      int       `json:"soft"`
+	Used      int       `json:"used"`
+	Requested int       `json:"requested"`
+	Remaining int       `json:"remaining"` // 요청 반영 전 남은 양 (제한 없음이면 -1)
+
+	Exceeded bool   `json:"exceeded"`     // 요청 시 hard cap 초과 (거부)
+	SoftHit  bool   `json:"soft_reached"` // 요청 시 soft 임계치 이상 (경고)
+	Warning  string `json:"warning,omitempty"`
+}
+
+// crossesSoft는 이번 요청으로 soft 임계치를 처음 넘는지 확인합니다. (이미 넘은 상태에서는 알림 반복 안 함)
+func (h Headroom) crossesSoft() bool {
+	return h.SoftHit && h.Used < h.Soft
+}
+
+// Headroom은 requests(차원별 추가 요청량)를 반영한 쿼터 여유량을 계산합니다.
+func (s *QuotaService) Headroom(userId uint, requests map[Dimension]int) ([]Headroom, error) {
+	headrooms := make([]Headroom, 0, len(Dimensions))
+
+	for _, dimension := range Dimensions {
+		used, err := s.Usage(userId, dimension)
+		if err != nil {
+			return nil, err
+		}
+
+		limit := s.Limit(dimension)
+		h := Headroom{
+			Dimension: dimension,
+			Hard:      limit.Hard,
+			Used:      used,
+			Requested: requests[dimension],
+			Remaining: -1,
+		}
+
+		if limit.Hard > 0 {
+			h.Soft = limit.Soft()
+			h.Remaining = max(limit.Hard-used, 0)
+			h.Exceeded = h.Requested > 0 && used+h.Requested > limit.Hard
+			h.SoftHit = used+h.Requested >= h.Soft
+
+			switch {
+			case h.Exceeded:
+				h.Warning = fmt.Sprintf("%s quota exceeded: used %d + requested %d > quota %d (쿼터 초과)", dimension, used, h.Requested, limit.Hard)
+			case h.SoftHit:
+				h.Warning = fmt.Sprintf("%s quota almost full: %d of %d used after this request (쿼터 임박)", dimension, used+h.Requested, limit.Hard)
+			}
+		}
+
+		headrooms = append(headrooms, h)
+	}
+
+	return headrooms, nil
+}
+
+// Check는 hard cap을 넘는 차원이 있으면 QUOTA_EXCEEDED 에러를 반환합니다. soft 임계치는 거부하지 않습니다.
+func (s *QuotaService) Check(userId uint, requests map[Dimension]int) ([]Headroom, error) {
+	headrooms, err := s.Headroom(userId, requests)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, h := range headrooms {
+		if h.Exceeded {
+			return headrooms, apperrors.New(apperrors.CodeQuotaExceeded, h.Warning).WithDetail("headroom", h)
+		}
+	}
+
+	return headrooms, nil
+}
+
+// NotifySoftLimits는 생성이 끝난 뒤, 이번 요청으로 soft 임계치를 넘은 차원에 대해 알림을 보냅니다.
+func (s *QuotaService) NotifySoftLimits(userId uint, headrooms []Headroom) {
+	for _, h := range headrooms {
+		if !h.crossesSoft() || h.Exceeded {
+			continue
+		}
+
+		data := notificationservice.Data{"dimension": string(h.Dimension), "used": h.Used + h.Requested, "hard": h.Hard}
+		if err := s.notificationService.NotifyTemplate(userId, "quota.soft_limit", data); err != nil {
+			slog.Warn("quota: failed to notify soft limit warning", "user_id", userId, "error", err)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package quotaservice
+
+import (
+	"fmt"
+	"vm-controller/internal/db"
+	"vm-controller/internal/models"
+	notificationservice "vm-controller/internal/services/notification_service"
+)
+
+// 디스크 크기가 기록되지 않은 VM의 루트 디스크 크기 (기본 요금제와 동일)
+const VmDiskSizeGi = 20
+
+// 사용자별 기본 스토리지 쿼터 (GiB)
+const defaultStorageQuotaGi = 50
+
+// 쿼터 대비 이 비율 이상 사용하면 경고 (soft 임계치 기본값, 차원별로 변경 가능)
+const defaultSoftRatio = 0.8
+
+type QuotaService struct {
+	notificationService *notificationservice.NotificationService
+}
+
+func NewQuotaService(notificationService *notificationservice.NotificationService) *QuotaService {
+	return &QuotaService{notificationService: notificationService}
+}
+
+// StorageQuotaGi는 사용자별 스토리지 쿼터를 반환합니다. (USER_STORAGE_QUOTA_GI 환경 변수)
+func (s *QuotaService) StorageQuotaGi() int {
+	return s.Limit(DimensionStorage).Hard
+}
+
+// StorageUsageGi는 사용자가 현재 점유한 스토리지 총량을 계산합니다.
+// VM 루트 디스크, VM 추가 디스크, 관리형 데이터베이스 볼륨을 합산합니다.
+func (s *QuotaService) StorageUsageGi(userId uint) (int, error) {
+	db := db.GetDB()
+
+	var vmStorage int64
+	if err := db.Model(&models.VirtualMachine{}).
+		Where("user_id = ? AND is_deleted = false", userId).
+		Select("COALESCE(SUM(COALESCE(disk_gi, ?)), 0)", VmDiskSizeGi).
+		Scan(&vmStorage).Error; err != nil {
+		return 0, err
+	}
+
+	var databaseStorage int64
+	if err := db.Model(&models.ManagedDatabase{}).
+		Where("user_id = ? AND is_deleted = false", userId).
+		Select("COALESCE(SUM(storage_gi), 0)").
+		Scan(&databaseStorage).Error; err != nil {
+		return 0, err
+	}
+
+	var volumeStorage int64
+	if err := db.Model(&models.VolumeAttachment{}).
+		Where("user_id = ?", userId).
+		Select("COALESCE(SUM(size_gi), 0)").
+		Scan(&volumeStorage).Error; err != nil {
+		return 0, err
+	}
+
+	return int(vmStorage) + int(volumeStorage) + int(databaseStorage), nil
+}
+
+// CheckStorage는 requestGi 만큼의 스토리지를 추가로 할당할 수 있는지 확인합니다.
+func (s *QuotaService) CheckStorage(userId uint, requestGi int) error {
+	_, err := s.Check(userId, map[Dimension]int{DimensionStorage: requestGi})
+	return err
+}
+
+// StorageSummary는 사용자에게 보여줄 스토리지 쿼터 현황입니다.
+type StorageSummary struct {
+	QuotaGi   int    `json:"quota_gi"`
+	UsedGi    int    `json:"used_gi"` // 쿼터 기준 점유량 (VM 디스크 + 관리형 DB 볼륨)
+	NearQuota bool   `json:"near_quota"`
+	Warning   string `json:"warning,omitempty"`
+}
+
+// StorageSummary는 쿼터 대비 사용량과, 쿼터에 가까워졌을 때의 경고 문구를 반환합니다.
+func (s *QuotaService) StorageSummary(userId uint) (*StorageSummary, error) {
+	used, err := s.StorageUsageGi(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := s.Limit(DimensionStorage)
+	quota := limit.Hard
+	summary := &StorageSummary{QuotaGi: quota, UsedGi: used}
+
+	if used >= limit.Soft() {
+		summary.NearQuota = true
+		summary.Warning = fmt.Sprintf("storage quota almost full: %dGi of %dGi used, a new VM disk needs %dGi (스토리지 쿼터 임박)", used, quota, VmDiskSizeGi)
+	}
+
+	return summary, nil
+}
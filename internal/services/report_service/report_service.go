@@ -0,0 +1,306 @@
+package reportservice
+
+import (
+	"errors"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+	"vm-controller/internal/db"
+	"vm-controller/internal/models"
+	notificationservice "vm-controller/internal/services/notification_service"
+
+	"gorm.io/gorm"
+)
+
+// 사용자 한 명이 동시에 열어 둘 수 있는 신고 수 (신고 남용 방지)
+const maxOpenReports = 10
+
+// 자동 탐지 신고가 기각된 VM은 이 기간 동안 다시 신고하지 않음 (오탐 반복 방지)
+const detectionDismissCooldown = 7 * 24 * time.Hour
+
+type ReportService struct {
+	notificationService *notificationservice.NotificationService
+}
+
+func NewReportService(notificationService *notificationservice.NotificationService) *ReportService {
+	return &ReportService{notificationService: notificationService}
+}
+
+var (
+	ErrReportExists         = errors.New("you have already reported this target")
+	ErrTooManyReports       = errors.New("too many open reports, wait for them to be reviewed")
+	ErrReportTargetNotFound = errors.New("no VM or deployment matches the report")
+	ErrReportNotFound       = errors.New("report not found")
+	ErrReportClosed         = errors.New("report has already been handled")
+)
+
+// CreateReportParams는 사용자가 보내는 신고 내용입니다. 대상은 VM 이름 또는 호스트 이름으로 지정합니다.
+type CreateReportParams struct {
+	Category    string `json:"category" binding:"required"` // spam / crypto_mining / phishing / other
+	VmName      string `json:"vm_name"`
+	Hostname    string `json:"hostname"` // VM Ingress 호스트 또는 웹 배포 도메인 (URL도 허용)
+	Description string `json:"description"`
+}
+
+// normalizeHostname은 URL이나 포트가 붙은 입력에서 호스트 이름만 꺼냅니다.
+func normalizeHostname(raw string) string {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	if raw == "" {
+		return ""
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(u.Hostname(), ".")
+}
+
+// resolveTarget은 신고 대상을 찾아 report에 대상과 소유자를 채웁니다.
+func resolveTarget(tx *gorm.DB, report *models.AbuseReport, vmName string) error {
+	var vm models.VirtualMachine
+	query := tx.Where("is_deleted = ?", false)
+	if vmName != "" {
+		query = query.Where("name = ?", vmName)
+	} else {
+		query = query.Where("dns_host = ?", report.Hostname)
+	}
+	err := query.First(&vm).Error
+	if err == nil {
+		report.TargetType = models.ReportTargetVM
+		report.TargetName = vm.Name
+		report.TargetID = vm.ID
+		report.OwnerID = vm.UserID
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if vmName != "" {
+		return ErrReportTargetNotFound
+	}
+
+	var deployment models.Deployment
+	if err := tx.Where("domain = ?", report.Hostname).First(&deployment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrReportTargetNotFound
+		}
+		return err
+	}
+	report.TargetType = models.ReportTargetDeployment
+	report.TargetName = deployment.Domain
+	report.TargetID = deployment.ID
+	report.OwnerID = deployment.UserID
+	return nil
+}
+
+// CreateReport는 신고를 접수합니다. 같은 사용자가 같은 대상에 대해 처리 대기 중인 신고가 있으면 ErrReportExists를 반환합니다.
+func (s *ReportService) CreateReport(reporterId uint, params CreateReportParams) (*models.AbuseReport, error) {
+	db := db.GetDB()
+
+	report := &models.AbuseReport{
+		ReporterID:  reporterId,
+		Source:      models.ReportSourceUser,
+		Category:    params.Category,
+		Hostname:    normalizeHostname(params.Hostname),
+		Description: strings.TrimSpace(params.Description),
+		Status:      models.ReportStatusOpen,
+	}
+	if err := resolveTarget(db, report, strings.TrimSpace(params.VmName)); err != nil {
+		return nil, err
+	}
+
+	var open []models.AbuseReport
+	if err := db.Select("target_type", "target_id").
+		Where("reporter_id = ? AND status = ?", reporterId, models.ReportStatusOpen).
+		Find(&open).Error; err != nil {
+		return nil, err
+	}
+	for _, existing := range open {
+		if existing.TargetType == report.TargetType && existing.TargetID == report.TargetID {
+			return nil, ErrReportExists
+		}
+	}
+	if len(open) >= maxOpenReports {
+		return nil, ErrTooManyReports
+	}
+
+	if err := db.Create(report).Error; err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *ReportService) FetchReport(id uint) (*models.AbuseReport, error) {
+	db := db.GetDB()
+
+	var report models.AbuseReport
+	if err := db.First(&report, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReportNotFound
+		}
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+// FetchReports는 신고 목록을 최신순으로 조회합니다. status나 source가 비어 있으면 해당 조건으로 거르지 않습니다.
+func (s *ReportService) FetchReports(status, source string, limit int) ([]models.AbuseReport, error) {
+	db := db.GetDB()
+
+	var reports []models.AbuseReport
+
+	query := db.Order("created_at desc").Limit(limit)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if source != "" {
+		query = query.Where("source = ?", source)
+	}
+	if err := query.Find(&reports).Error; err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+// FetchUserReports는 사용자가 접수한 신고 목록을 최신순으로 조회합니다.
+func (s *ReportService) FetchUserReports(reporterId uint) ([]models.AbuseReport, error) {
+	db := db.GetDB()
+
+	var reports []models.AbuseReport
+
+	if err := db.Where("reporter_id = ?", reporterId).Order("created_at desc").Find(&reports).Error; err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+// FlagMining은 채굴 의심 VM을 자동 탐지 신고로 등록합니다.
+// 같은 VM에 처리 대기 중인 탐지 신고가 있으면 근거만 최신 값으로 바꾸고 created=false를 반환합니다.
+// 최근에 기각된 탐지 신고가 있으면 등록하지 않고 nil을 반환합니다.
+func (s *ReportService) FlagMining(vm *models.VirtualMachine, evidence *models.MiningEvidence) (report *models.AbuseReport, created bool, err error) {
+	db := db.GetDB()
+
+	var existing models.AbuseReport
+	err = db.Where("source = ? AND target_type = ? AND target_id = ? AND status = ?",
+		models.ReportSourceDetector, models.ReportTargetVM, vm.ID, models.ReportStatusOpen).
+		First(&existing).Error
+	if err == nil {
+		if err := db.Model(&existing).Update("evidence", evidence).Error; err != nil {
+			return nil, false, err
+		}
+		existing.Evidence = evidence
+		return &existing, false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, err
+	}
+
+	var dismissed int64
+	if err := db.Model(&models.AbuseReport{}).
+		Where("source = ? AND target_type = ? AND target_id = ? AND status = ? AND updated_at > ?",
+			models.ReportSourceDetector, models.ReportTargetVM, vm.ID, models.ReportStatusDismissed, time.Now().Add(-detectionDismissCooldown)).
+		Count(&dismissed).Error; err != nil {
+		return nil, false, err
+	}
+	if dismissed > 0 {
+		return nil, false, nil
+	}
+
+	report = &models.AbuseReport{
+		Source:      models.ReportSourceDetector,
+		Category:    models.ReportCategoryMining,
+		TargetType:  models.ReportTargetVM,
+		TargetName:  vm.Name,
+		TargetID:    vm.ID,
+		OwnerID:     vm.UserID,
+		Description: "자동 탐지: 지속적인 CPU 포화와 채굴 풀 통신 패턴",
+		Status:      models.ReportStatusOpen,
+		Evidence:    evidence,
+	}
+	if err := db.Create(report).Error; err != nil {
+		return nil, false, err
+	}
+	return report, true, nil
+}
+
+// MarkThrottled는 탐지 신고 대상 VM을 검토 전까지 격리했음을 기록합니다. (nil이면 해제)
+func (s *ReportService) MarkThrottled(id uint, at *time.Time) error {
+	db := db.GetDB()
+
+	return db.Model(&models.AbuseReport{}).Where("id = ?", id).Update("throttled_at", at).Error
+}
+
+// IsVMThrottled는 VM이 처리 대기 중인 탐지 신고로 격리되어 있는지 확인합니다.
+func (s *ReportService) IsVMThrottled(vmId uint) (bool, error) {
+	db := db.GetDB()
+
+	var count int64
+	err := db.Model(&models.AbuseReport{}).
+		Where("target_type = ? AND target_id = ? AND status = ? AND throttled_at IS NOT NULL", models.ReportTargetVM, vmId, models.ReportStatusOpen).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Close는 처리 대기 중인 신고를 조치 결과와 함께 종료합니다.
+// 대기 상태일 때만 변경하므로 두 관리자가 동시에 처리해도 한 번만 성공하고, 나머지는 ErrReportClosed를 받습니다.
+func (s *ReportService) Close(id uint, status models.EnumReportStatus, action string, reviewerId uint, note string) error {
+	db := db.GetDB()
+
+	result := db.Model(&models.AbuseReport{}).
+		Where("id = ? AND status = ?", id, models.ReportStatusOpen).
+		Updates(map[string]interface{}{"status": status, "action": action, "reviewer_id": reviewerId, "note": note})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrReportClosed
+	}
+
+	return nil
+}
+
+// Reopen은 조치가 실패한 신고를 다시 처리 대기 상태로 되돌립니다.
+func (s *ReportService) Reopen(id uint) error {
+	db := db.GetDB()
+
+	return db.Model(&models.AbuseReport{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"status": models.ReportStatusOpen, "action": "", "reviewer_id": nil, "note": ""}).Error
+}
+
+// NotifyAdmins는 새 신고를 모든 관리자에게 인앱 알림으로 알립니다. 알림 실패로 접수가 실패하지 않도록 에러는 로그만 남깁니다.
+func (s *ReportService) NotifyAdmins(report *models.AbuseReport) {
+	db := db.GetDB()
+
+	var admins []models.User
+	if err := db.Where("role = ?", models.RoleAdmin).Find(&admins).Error; err != nil {
+		slog.Warn("report: failed to fetch admins", "report_id", report.ID, "error", err)
+	}
+
+	key := "report.received"
+	if report.Source == models.ReportSourceDetector {
+		key = "report.detected"
+	}
+	data := notificationservice.Data{"report_id": report.ID, "target_type": report.TargetType, "target": report.TargetName, "category": report.Category}
+	for _, admin := range admins {
+		if err := s.notificationService.NotifyTemplate(admin.ID, key, data); err != nil {
+			slog.Warn("report: failed to notify admin", "admin_id", admin.ID, "report_id", report.ID, "error", err)
+		}
+	}
+}
+
+// Notify는 신고 처리 결과를 신고자나 대상 소유자에게 인앱 알림으로 전달합니다. (자동 탐지 신고의 신고자 0은 건너뜀)
+func (s *ReportService) Notify(userId uint, report *models.AbuseReport, key string, data notificationservice.Data) {
+	if userId == 0 {
+		return
+	}
+	if err := s.notificationService.NotifyTemplate(userId, key, data); err != nil {
+		slog.Warn("report: failed to notify user", "user_id", userId, "report_id", report.ID, "error", err)
+	}
+}
@@ -0,0 +1,101 @@
+package resourceservice
+
+import (
+	"fmt"
+	"sort"
+	"time"
+	databaseservice "vm-controller/internal/services/database_service"
+	deploymentservice "vm-controller/internal/services/deployment_service"
+	vmservice "vm-controller/internal/services/vm_service"
+)
+
+// 리소스 유형 구분자 (type discriminator)
+const (
+	ResourceTypeVM         = "vm"
+	ResourceTypeDeployment = "deployment"
+	ResourceTypeDatabase   = "database"
+)
+
+// Resource는 대시보드 홈 화면에서 사용하는 통합 리소스 항목입니다.
+// Data에는 유형별 원본 모델이 그대로 담깁니다.
+type Resource struct {
+	Type      string      `json:"type"`
+	ID        uint        `json:"id"`
+	Name      string      `json:"name"`
+	Status    string      `json:"status"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+type ResourcePage struct {
+	Items []Resource `json:"items"`
+	Total int        `json:"total"`
+	Page  int        `json:"page"`
+	Limit int        `json:"limit"`
+}
+
+type ResourceService struct {
+	vmService         *vmservice.VmService
+	deploymentService *deploymentservice.DeploymentService
+	databaseService   *databaseservice.DatabaseService
+}
+
+func NewResourceService(vmService *vmservice.VmService, deploymentService *deploymentservice.DeploymentService, databaseService *databaseservice.DatabaseService) *ResourceService {
+	return &ResourceService{vmService: vmService, deploymentService: deploymentService, databaseService: databaseService}
+}
+
+// FetchUserResources는 사용자의 모든 리소스를 최신순으로 합쳐 페이지 단위로 반환합니다.
+func (s *ResourceService) FetchUserResources(userId string, page, limit int) (*ResourcePage, error) {
+	var resources []Resource
+
+	vms, err := s.vmService.FetchUserVMs(userId, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch vms: %v", err)
+	}
+	for _, vm := range vms {
+		resources = append(resources, Resource{
+			Type: ResourceTypeVM, ID: vm.ID, Name: vm.Name, Status: string(vm.Status), CreatedAt: vm.CreatedAt, Data: vm,
+		})
+	}
+
+	deployments, err := s.deploymentService.FetchUserDeployments(userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch deployments: %v", err)
+	}
+	for _, deployment := range deployments {
+		resources = append(resources, Resource{
+			Type: ResourceTypeDeployment, ID: deployment.ID, Name: deployment.Domain, Status: deployment.Status, CreatedAt: deployment.CreatedAt, Data: deployment,
+		})
+	}
+
+	databases, err := s.databaseService.FetchUserDatabases(userId, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch databases: %v", err)
+	}
+	for _, database := range databases {
+		resources = append(resources, Resource{
+			Type: ResourceTypeDatabase, ID: database.ID, Name: database.Name, Status: database.Status, CreatedAt: database.CreatedAt, Data: database,
+		})
+	}
+
+	sort.SliceStable(resources, func(i, j int) bool {
+		return resources[i].CreatedAt.After(resources[j].CreatedAt)
+	})
+
+	total := len(resources)
+	start := (page - 1) * limit
+	if start > total {
+		start = total
+	}
+	end := start + limit
+	if end > total {
+		end = total
+	}
+
+	return &ResourcePage{
+		Items: resources[start:end],
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	}, nil
+}
@@ -0,0 +1,107 @@
+package snapshotservice
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+	"vm-controller/internal/db"
+	"vm-controller/internal/models"
+	"vm-controller/internal/naming"
+
+	"github.com/spf13/cast"
+	"gorm.io/gorm"
+)
+
+type SnapshotService struct {
+}
+
+func NewSnapshotService() *SnapshotService {
+	return &SnapshotService{}
+}
+
+// VM당 보관할 수 있는 스냅샷 수 기본값 (VM_SNAPSHOT_LIMIT)
+const defaultSnapshotLimit = 5
+
+// ErrSnapshotLimit은 VM의 스냅샷 수가 상한에 도달했을 때 반환됩니다.
+var ErrSnapshotLimit = errors.New("snapshot limit reached for this VM")
+
+// Limit은 VM당 스냅샷 상한을 반환합니다.
+func (s *SnapshotService) Limit() int {
+	if limit, err := cast.ToIntE(os.Getenv("VM_SNAPSHOT_LIMIT")); err == nil && limit > 0 {
+		return limit
+	}
+	return defaultSnapshotLimit
+}
+
+// SnapshotName은 VirtualMachineSnapshot 리소스 이름을 생성합니다.
+func (s *SnapshotService) SnapshotName(vmName string) string {
+	return naming.ResourceName("", vmName, fmt.Sprintf("-snap-%d", time.Now().Unix()))
+}
+
+// CreateSnapshot은 스냅샷 레코드를 저장합니다. VM의 스냅샷 수가 상한에 도달했으면 ErrSnapshotLimit을 반환합니다.
+func (s *SnapshotService) CreateSnapshot(snapshot *models.Snapshot) error {
+	db := db.GetDB()
+
+	var count int64
+	if err := db.Model(&models.Snapshot{}).Where("vm_name = ?", snapshot.VmName).Count(&count).Error; err != nil {
+		return err
+	}
+	if int(count) >= s.Limit() {
+		return ErrSnapshotLimit
+	}
+
+	return db.Create(snapshot).Error
+}
+
+// FetchVmSnapshots는 VM의 스냅샷을 최신순으로 반환합니다.
+func (s *SnapshotService) FetchVmSnapshots(vmName string) ([]models.Snapshot, error) {
+	db := db.GetDB()
+
+	var snapshots []models.Snapshot
+	if err := db.Where("vm_name = ?", vmName).Order("id desc").Find(&snapshots).Error; err != nil {
+		return nil, err
+	}
+
+	return snapshots, nil
+}
+
+// FetchSnapshot은 VM의 스냅샷 한 개를 반환합니다. (없으면 nil)
+func (s *SnapshotService) FetchSnapshot(vmName, name string) (*models.Snapshot, error) {
+	db := db.GetDB()
+
+	var snapshot models.Snapshot
+	if err := db.Where("vm_name = ? AND name = ?", vmName, name).First(&snapshot).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
+
+// UpdateSnapshotStatus는 클러스터에서 관측된 스냅샷 상태를 반영합니다.
+func (s *SnapshotService) UpdateSnapshotStatus(id uint, status models.EnumSnapshotStatus, message string) error {
+	db := db.GetDB()
+
+	updates := map[string]interface{}{"status": status, "message": message}
+	if status == models.SnapshotStatusReady {
+		updates["ready_at"] = time.Now()
+	}
+
+	return db.Model(&models.Snapshot{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// MarkSnapshotRestored는 스냅샷으로 복원한 시각을 기록합니다.
+func (s *SnapshotService) MarkSnapshotRestored(id uint) error {
+	db := db.GetDB()
+
+	return db.Model(&models.Snapshot{}).Where("id = ?", id).Update("last_restored_at", time.Now()).Error
+}
+
+func (s *SnapshotService) DeleteSnapshot(id uint) error {
+	db := db.GetDB()
+
+	return db.Delete(&models.Snapshot{}, id).Error
+}
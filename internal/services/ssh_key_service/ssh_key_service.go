@@ -0,0 +1,139 @@
+package sshkeyservice
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"vm-controller/internal/db"
+	"vm-controller/internal/models"
+
+	"golang.org/x/crypto/ssh"
+)
+
+type SSHKeyService struct {
+}
+
+func NewSSHKeyService() *SSHKeyService {
+	return &SSHKeyService{}
+}
+
+// 사용자당 등록할 수 있는 최대 키 수
+const MaxKeysPerUser = 20
+
+var (
+	ErrInvalidKey   = errors.New("invalid ssh public key")
+	ErrDuplicateKey = errors.New("ssh public key is already registered")
+	ErrKeyLimit     = fmt.Errorf("at most %d ssh keys can be registered", MaxKeysPerUser)
+	ErrKeyNotFound  = errors.New("ssh key not found")
+)
+
+// ParsePublicKey는 authorized_keys 한 줄을 검사하고 정규화된 키와 SHA256 지문을 반환합니다.
+// 옵션(command= 등)이 붙은 키는 VM 안에서 동작이 달라지므로 허용하지 않습니다.
+func (s *SSHKeyService) ParsePublicKey(raw string) (string, string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || strings.ContainsAny(raw, "\r\n") {
+		return "", "", ErrInvalidKey
+	}
+
+	key, comment, options, _, err := ssh.ParseAuthorizedKey([]byte(raw))
+	if err != nil || len(options) > 0 {
+		return "", "", ErrInvalidKey
+	}
+
+	normalized := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key)))
+	if comment = strings.TrimSpace(comment); comment != "" {
+		normalized += " " + comment
+	}
+
+	return normalized, ssh.FingerprintSHA256(key), nil
+}
+
+// CreateKey는 사용자의 SSH 공개 키를 등록합니다.
+func (s *SSHKeyService) CreateKey(userId uint, name, publicKey string) (*models.SSHKey, error) {
+	db := db.GetDB()
+
+	normalized, fingerprint, err := s.ParsePublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var count int64
+	if err := db.Model(&models.SSHKey{}).Where("user_id = ?", userId).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count >= MaxKeysPerUser {
+		return nil, ErrKeyLimit
+	}
+
+	var duplicated int64
+	if err := db.Model(&models.SSHKey{}).Where("user_id = ? AND fingerprint = ?", userId, fingerprint).Count(&duplicated).Error; err != nil {
+		return nil, err
+	}
+	if duplicated > 0 {
+		return nil, ErrDuplicateKey
+	}
+
+	key := &models.SSHKey{
+		UserID:      userId,
+		Name:        name,
+		PublicKey:   normalized,
+		Fingerprint: fingerprint,
+	}
+
+	if err := db.Create(key).Error; err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func (s *SSHKeyService) FetchUserKeys(userId string) ([]models.SSHKey, error) {
+	db := db.GetDB()
+
+	var keys []models.SSHKey
+	if err := db.Where("user_id = ?", userId).Order("created_at").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// FetchUserKeysByIds는 사용자가 소유한 키를 ID로 조회합니다. 하나라도 없으면 ErrKeyNotFound를 반환합니다.
+func (s *SSHKeyService) FetchUserKeysByIds(userId uint, ids []uint) ([]models.SSHKey, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	db := db.GetDB()
+
+	var keys []models.SSHKey
+	if err := db.Where("user_id = ? AND id IN ?", userId, ids).Order("id").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+
+	unique := map[uint]bool{}
+	for _, id := range ids {
+		unique[id] = true
+	}
+	if len(keys) != len(unique) {
+		return nil, ErrKeyNotFound
+	}
+
+	return keys, nil
+}
+
+// DeleteKey는 사용자의 키를 삭제합니다. 이미 생성된 VM에 주입된 키는 제거되지 않습니다.
+func (s *SSHKeyService) DeleteKey(userId string, keyId uint) error {
+	db := db.GetDB()
+
+	// 같은 키를 다시 등록할 수 있도록 영구 삭제 (지문 unique index)
+	result := db.Unscoped().Where("user_id = ? AND id = ?", userId, keyId).Delete(&models.SSHKey{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrKeyNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,255 @@
+package statsservice
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"time"
+	"vm-controller/internal/coalesce"
+	"vm-controller/internal/db"
+	"vm-controller/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StatsService struct {
+	reads *coalesce.Group[*PublicStats] // 공개 통계 캐시 (PUBLIC_STATS_CACHE_TTL)
+}
+
+func NewStatsService() *StatsService {
+	return &StatsService{reads: coalesce.NewWithTTL[*PublicStats]("public_stats", CacheTTL())}
+}
+
+// 공개 통계 지표 이름
+const (
+	MetricVMsRunning        = "vms_running"
+	MetricVMsTotal          = "vms_total"
+	MetricDeploymentsHosted = "deployments_hosted"
+	MetricDatabases         = "databases"
+	MetricUsers             = "users"
+	MetricUptime            = "uptime_seconds"
+)
+
+// Metric은 공개할 수 있는 집계 지표입니다. 모두 플랫폼 전체 합계이며 사용자/리소스를 식별할 수 있는 값은 포함하지 않습니다.
+type Metric struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Metrics는 지원하는 지표 목록입니다. (응답/관리자 화면에 표시되는 순서)
+var Metrics = []Metric{
+	{MetricVMsRunning, "Number of VMs currently running"},
+	{MetricVMsTotal, "Number of VMs (running or stopped)"},
+	{MetricDeploymentsHosted, "Number of web deployments being served"},
+	{MetricDatabases, "Number of managed databases"},
+	{MetricUsers, "Number of registered users"},
+	{MetricUptime, "Seconds since the API server started"},
+}
+
+// PUBLIC_STATS가 비어 있을 때 공개하는 지표
+var defaultPublicMetrics = []string{MetricVMsRunning, MetricDeploymentsHosted, MetricUptime}
+
+// 공개 통계 기본 캐시 시간 (PUBLIC_STATS_CACHE_TTL)
+const defaultCacheTTL = 5 * time.Minute
+
+var ErrUnknownMetric = errors.New("unknown metric")
+
+// API 서버 시작 시각 (uptime_seconds)
+var startedAt = time.Now()
+
+// CacheTTL은 공개 통계를 캐시하는 시간입니다. (PUBLIC_STATS_CACHE_TTL, 기본 5m)
+func CacheTTL() time.Duration {
+	raw := os.Getenv("PUBLIC_STATS_CACHE_TTL")
+	if raw == "" {
+		return defaultCacheTTL
+	}
+	ttl, err := time.ParseDuration(raw)
+	if err != nil || ttl <= 0 {
+		log.Printf("Invalid PUBLIC_STATS_CACHE_TTL: %q, using default %s", raw, defaultCacheTTL)
+		return defaultCacheTTL
+	}
+	return ttl
+}
+
+// PublicStats는 로그인 없이 제공하는 집계 통계입니다.
+type PublicStats struct {
+	Metrics     map[string]int64 `json:"metrics"`      // 공개로 지정된 지표만 포함
+	GeneratedAt time.Time        `json:"generated_at"` // 집계 시각 (캐시된 응답은 이 시각의 값)
+}
+
+// MetricSetting은 관리자 화면에 표시하는 지표별 공개 설정입니다.
+type MetricSetting struct {
+	Metric
+	Public     bool  `json:"public"`
+	Overridden bool  `json:"overridden"` // 관리자가 지정함 (아니면 PUBLIC_STATS 기본값)
+	Value      int64 `json:"value"`      // 현재 값 (공개 전에 확인용)
+}
+
+// defaultPublic은 관리자가 지정하지 않은 지표의 공개 여부입니다. (PUBLIC_STATS, 쉼표로 구분)
+func defaultPublic() map[string]bool {
+	names := defaultPublicMetrics
+	if raw := os.Getenv("PUBLIC_STATS"); raw != "" {
+		names = nil
+		for _, name := range strings.Split(raw, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+
+	public := map[string]bool{}
+	for _, name := range names {
+		if !isKnownMetric(name) {
+			log.Printf("Unknown metric %q in PUBLIC_STATS is ignored", name)
+			continue
+		}
+		public[name] = true
+	}
+	return public
+}
+
+func isKnownMetric(name string) bool {
+	return slices.ContainsFunc(Metrics, func(m Metric) bool { return m.Name == name })
+}
+
+// publicMetrics는 지표별 공개 여부와 관리자 지정 여부를 반환합니다.
+func (s *StatsService) publicMetrics() (map[string]bool, map[string]bool, error) {
+	db := db.GetDB()
+
+	var settings []models.PublicStatSetting
+	if err := db.Find(&settings).Error; err != nil {
+		return nil, nil, err
+	}
+
+	public := defaultPublic()
+	overridden := map[string]bool{}
+	for _, setting := range settings {
+		public[setting.Metric] = setting.Public
+		overridden[setting.Metric] = true
+	}
+	return public, overridden, nil
+}
+
+// PublicStats는 공개로 지정된 지표를 집계합니다. 결과는 PUBLIC_STATS_CACHE_TTL 동안 캐시되며 동시 요청은 한 번만 집계합니다.
+// 반환 값은 여러 요청이 공유하므로 수정하면 안 됩니다.
+func (s *StatsService) PublicStats() (*PublicStats, error) {
+	return s.reads.Do("public", func() (*PublicStats, error) {
+		public, _, err := s.publicMetrics()
+		if err != nil {
+			return nil, err
+		}
+
+		names := []string{}
+		for _, metric := range Metrics {
+			if public[metric.Name] {
+				names = append(names, metric.Name)
+			}
+		}
+
+		values, err := s.Collect(names)
+		if err != nil {
+			return nil, err
+		}
+		return &PublicStats{Metrics: values, GeneratedAt: time.Now()}, nil
+	})
+}
+
+// Settings는 모든 지표의 공개 설정과 현재 값을 반환합니다.
+func (s *StatsService) Settings() ([]MetricSetting, error) {
+	public, overridden, err := s.publicMetrics()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(Metrics))
+	for _, metric := range Metrics {
+		names = append(names, metric.Name)
+	}
+	values, err := s.Collect(names)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := make([]MetricSetting, 0, len(Metrics))
+	for _, metric := range Metrics {
+		settings = append(settings, MetricSetting{
+			Metric:     metric,
+			Public:     public[metric.Name],
+			Overridden: overridden[metric.Name],
+			Value:      values[metric.Name],
+		})
+	}
+	return settings, nil
+}
+
+// SetPublic은 지표별 공개 여부를 저장하고 공개 통계 캐시를 비웁니다. 알 수 없는 지표가 있으면 아무것도 바꾸지 않습니다.
+func (s *StatsService) SetPublic(updates map[string]bool, actorID uint) error {
+	for name := range updates {
+		if !isKnownMetric(name) {
+			return fmt.Errorf("%w: %s", ErrUnknownMetric, name)
+		}
+	}
+
+	db := db.GetDB()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for name, public := range updates {
+			setting := models.PublicStatSetting{Metric: name, Public: public, UpdatedBy: &actorID}
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "metric"}},
+				DoUpdates: clause.AssignmentColumns([]string{"public", "updated_at", "updated_by"}),
+			}).Create(&setting).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.reads.Purge()
+	return nil
+}
+
+// Collect는 names의 지표를 집계합니다.
+func (s *StatsService) Collect(names []string) (map[string]int64, error) {
+	db := db.GetDB()
+
+	values := make(map[string]int64, len(names))
+	for _, name := range names {
+		var count int64
+		var err error
+
+		switch name {
+		case MetricVMsRunning:
+			err = db.Model(&models.VirtualMachine{}).
+				Where("is_deleted = false AND status = ?", models.VmStatusRunning).Count(&count).Error
+		case MetricVMsTotal:
+			err = db.Model(&models.VirtualMachine{}).Where("is_deleted = false").Count(&count).Error
+		case MetricDeploymentsHosted:
+			// 유휴 상태로 잠든 배포도 요청이 오면 깨어나므로 서비스 중으로 봄
+			err = db.Model(&models.Deployment{}).
+				Where("type = ? AND status IN ?", models.DeploymentTypeWeb, []string{models.DeploymentStatusDeployed, models.DeploymentStatusSleeping}).
+				Count(&count).Error
+		case MetricDatabases:
+			err = db.Model(&models.ManagedDatabase{}).Where("is_deleted = false").Count(&count).Error
+		case MetricUsers:
+			err = db.Model(&models.User{}).Count(&count).Error
+		case MetricUptime:
+			count = int64(time.Since(startedAt).Seconds())
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrUnknownMetric, name)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to collect %s: %w", name, err)
+		}
+		values[name] = count
+	}
+
+	return values, nil
+}
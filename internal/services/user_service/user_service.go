@@ -1,34 +1,43 @@
 package userservice
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"sync"
+	"time"
 	"vm-controller/internal/db"
 	"vm-controller/internal/models"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type UserService struct {
+	ctx context.Context // WithContext로 묶은 요청 컨텍스트 (기본 인스턴스는 nil)
 }
 
-var (
-	userService *UserService
-	once        sync.Once
-)
+func NewUserService() *UserService {
+	return &UserService{}
+}
 
-func GetUserService() *UserService {
-	once.Do(func() {
-		userService = &UserService{}
-	})
+// WithContext는 쿼리가 ctx를 따르는 UserService를 반환합니다. 요청이 취소되면 진행 중인 쿼리도 중단됩니다.
+func (s *UserService) WithContext(ctx context.Context) *UserService {
+	scoped := *s
+	scoped.ctx = ctx
+	return &scoped
+}
 
-	return userService
+// getDB는 WithContext로 묶인 컨텍스트가 있으면 그 컨텍스트를 사용하는 DB 핸들을 반환합니다.
+func (s *UserService) getDB() *gorm.DB {
+	if s.ctx == nil {
+		return db.GetDB()
+	}
+	return db.GetDB().WithContext(s.ctx)
 }
 
 // AuthenticateUser 함수는 학번과 비밀번호를 받아 유저를 인증하고 반환합니다.
 func (s *UserService) AuthenticateUser(studentID, password string) (*models.User, error) {
-	database := db.GetDB()
+	database := s.getDB()
 
 	var user models.User
 	// 학번으로 유저 찾기
@@ -45,7 +54,7 @@ func (s *UserService) AuthenticateUser(studentID, password string) (*models.User
 }
 
 func (s *UserService) FetchUserById(userId string, concealPassword bool) (*models.User, error) {
-	database := db.GetDB()
+	database := s.getDB()
 
 	var user models.User
 
@@ -61,7 +70,7 @@ func (s *UserService) FetchUserById(userId string, concealPassword bool) (*model
 }
 
 func (s *UserService) FetchUserByStudentId(studentId string) (*models.User, error) {
-	database := db.GetDB()
+	database := s.getDB()
 
 	var user models.User
 
@@ -72,6 +81,126 @@ func (s *UserService) FetchUserByStudentId(studentId string) (*models.User, erro
 	return &user, nil
 }
 
+// FetchAllUsers는 관리자용으로 전체 사용자 목록을 반환합니다. (비밀번호 해시 제외)
+func (s *UserService) FetchAllUsers() ([]models.User, error) {
+	database := s.getDB()
+
+	var users []models.User
+
+	if err := database.Omit("password_hash").Order("id").Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// UpdateUserRole은 사용자의 권한을 변경합니다. 권한 검사(middleware.Authorize)는 매 요청 DB를 조회하므로 즉시 반영됩니다.
+func (s *UserService) UpdateUserRole(userId uint, role string) error {
+	database := s.getDB()
+
+	result := database.Model(&models.User{}).Where("id = ?", userId).Update("role", role)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("사용자를 찾을 수 없습니다")
+	}
+
+	return nil
+}
+
+// UpdateVmLeaseDays는 사용자별 VM 사용 기간(일)을 변경합니다. nil이면 기본 정책(VM_LEASE_DAYS)을 따릅니다.
+// 이후 생성하는 VM과 연장 한도에만 적용되며, 기존 VM의 만료 시각은 바꾸지 않습니다.
+func (s *UserService) UpdateVmLeaseDays(userId uint, days *int) error {
+	database := s.getDB()
+
+	result := database.Model(&models.User{}).Where("id = ?", userId).Update("vm_lease_days", days)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("사용자를 찾을 수 없습니다")
+	}
+
+	return nil
+}
+
+// UpdateLocale은 사용자의 알림 언어를 변경합니다. 빈 문자열이면 기본 언어(NOTIFICATION_DEFAULT_LOCALE)를 따릅니다.
+func (s *UserService) UpdateLocale(userId uint, locale string) error {
+	database := s.getDB()
+
+	result := database.Model(&models.User{}).Where("id = ?", userId).Update("locale", locale)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("사용자를 찾을 수 없습니다")
+	}
+
+	return nil
+}
+
+// SuspendUser는 사용자 계정을 정지합니다. 인증 미들웨어가 매 요청 DB를 조회하므로 발급된 토큰도 즉시 거부됩니다.
+func (s *UserService) SuspendUser(userId uint, reason string) error {
+	database := s.getDB()
+
+	result := database.Model(&models.User{}).Where("id = ?", userId).
+		Updates(map[string]interface{}{"suspended_at": time.Now(), "suspended_reason": reason})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("사용자를 찾을 수 없습니다")
+	}
+
+	return nil
+}
+
+// UnsuspendUser는 계정 정지를 해제합니다.
+func (s *UserService) UnsuspendUser(userId uint) error {
+	database := s.getDB()
+
+	result := database.Model(&models.User{}).Where("id = ?", userId).
+		Updates(map[string]interface{}{"suspended_at": nil, "suspended_reason": ""})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("사용자를 찾을 수 없습니다")
+	}
+
+	return nil
+}
+
+// FetchUserByNamespace는 K8s 네임스페이스로 소유 사용자를 조회합니다. (operator 모드에서 UserVM 소유자 판별용)
+func (s *UserService) FetchUserByNamespace(namespace string) (*models.User, error) {
+	database := s.getDB()
+
+	var user models.User
+
+	if err := database.Where("namespace = ?", namespace).First(&user).Error; err != nil {
+		return nil, err
+	}
+
+	user.PasswordHash = ""
+	return &user, nil
+}
+
+// MarkNamespaceInitialized는 사용자 네임스페이스의 초기화(client-init) 적용이 끝났음을 기록합니다.
+func (s *UserService) MarkNamespaceInitialized(namespace string) error {
+	database := s.getDB()
+
+	result := database.Model(&models.User{}).Where("namespace = ?", namespace).Update("namespace_initialized", true)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("사용자를 찾을 수 없습니다")
+	}
+
+	return nil
+}
+
 type CreateUserParams struct {
 	StudentId string
 	Password  string
@@ -88,7 +217,7 @@ func (s *UserService) generateNamespace(userId string) string {
 }
 
 func (s *UserService) CreateUser(params CreateUserParams) (*models.User, error) {
-	database := db.GetDB()
+	database := s.getDB()
 
 	hashedPassword, err := models.HashPassword(params.Password)
 
@@ -0,0 +1,143 @@
+package vmeventservice
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+	"vm-controller/internal/db"
+	"vm-controller/internal/models"
+	"vm-controller/internal/vmstate"
+)
+
+type VmEventService struct {
+}
+
+func NewVmEventService() *VmEventService {
+	return &VmEventService{}
+}
+
+// SubscribeTransitions는 상태 머신의 전이 이벤트를 스트림에 기록하도록 등록합니다.
+// 서버 시작 시 한 번만 호출합니다.
+func (s *VmEventService) SubscribeTransitions() {
+	vmstate.OnTransition(func(t vmstate.Transition) {
+		s.Record(models.VmEvent{
+			VmName:     t.VmName,
+			Type:       models.VmEventStatusTransition,
+			FromStatus: string(t.From),
+			ToStatus:   string(t.To),
+		})
+	})
+}
+
+// Record는 이벤트를 추가합니다. 이벤트 기록 실패가 VM 작업을 막지 않도록 에러는 로그만 남깁니다.
+func (s *VmEventService) Record(event models.VmEvent) {
+	db := db.GetDB()
+
+	if err := db.Create(&event).Error; err != nil {
+		slog.Warn("vm events: failed to record event", "type", event.Type, "vm", event.VmName, "error", err)
+	}
+}
+
+// RecordOperation은 사용자의 작업 요청을 기록합니다.
+func (s *VmEventService) RecordOperation(vmName, operation string, actorId uint) {
+	s.Record(models.VmEvent{
+		VmName:    vmName,
+		Type:      models.VmEventOperationRequested,
+		Operation: operation,
+		ActorID:   &actorId,
+	})
+}
+
+// FetchVmEvents는 VM의 이벤트를 발생 순서대로 반환합니다.
+func (s *VmEventService) FetchVmEvents(vmName string) ([]models.VmEvent, error) {
+	db := db.GetDB()
+
+	var events []models.VmEvent
+	if err := db.Where("vm_name = ?", vmName).Order("id asc").Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// 사용자에게 보여주는 이벤트 종류 (패치 내용/관측 기록은 관리자용)
+var historyEventTypes = []models.EnumVmEventType{
+	models.VmEventOperationRequested,
+	models.VmEventOperationSucceeded,
+	models.VmEventOperationFailed,
+	models.VmEventStatusTransition,
+}
+
+// FetchVmHistory는 VM의 작업 요청/결과와 상태 전이를 최신순으로 최대 limit개 반환합니다.
+// 같은 이름으로 이전에 있던 VM의 이벤트가 섞이지 않도록 since(VM 생성 시각) 이후만 조회합니다.
+func (s *VmEventService) FetchVmHistory(vmName string, since time.Time, limit int) ([]models.VmEvent, error) {
+	db := db.GetDB()
+
+	var events []models.VmEvent
+	if err := db.Where("vm_name = ? AND type IN ? AND created_at >= ?", vmName, historyEventTypes, since).Order("id desc").Limit(limit).Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// ReplayResult는 이벤트 스트림을 재생하여 재구성한 VM 상태입니다.
+type ReplayResult struct {
+	Events           []models.VmEvent `json:"events"`
+	ReplayedStatus   string           `json:"replayed_status"`   // 전이 이벤트로 재구성한 DB 상태
+	LastObserved     string           `json:"last_observed"`     // 마지막으로 관측된 클러스터 상태
+	PendingOperation string           `json:"pending_operation"` // 요청 후 완료/실패 기록이 없는 작업
+	Anomalies        []string         `json:"anomalies"`         // 상태 머신 규칙 위반 등 이상 징후
+}
+
+// Replay는 이벤트를 순서대로 재생하여 VM이 현재 상태에 이르게 된 과정을 재구성합니다.
+func (s *VmEventService) Replay(vmName string) (*ReplayResult, error) {
+	events, err := s.FetchVmEvents(vmName)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReplayResult{Events: events, Anomalies: []string{}}
+
+	for _, event := range events {
+		switch event.Type {
+		case models.VmEventOperationRequested:
+			if result.PendingOperation != "" {
+				result.Anomalies = append(result.Anomalies, fmt.Sprintf("event %d: %s requested while %s still pending", event.ID, event.Operation, result.PendingOperation))
+			}
+			result.PendingOperation = event.Operation
+		case models.VmEventStatusObserved:
+			result.LastObserved = event.ToStatus
+		case models.VmEventStatusTransition:
+			if result.ReplayedStatus != "" && result.ReplayedStatus != event.FromStatus {
+				result.Anomalies = append(result.Anomalies, fmt.Sprintf("event %d: transition from %s but replayed status is %s", event.ID, event.FromStatus, result.ReplayedStatus))
+			}
+			if !vmstate.CanTransition(models.EnumVmStatus(event.FromStatus), models.EnumVmStatus(event.ToStatus)) {
+				result.Anomalies = append(result.Anomalies, fmt.Sprintf("event %d: illegal transition %s -> %s", event.ID, event.FromStatus, event.ToStatus))
+			}
+			result.ReplayedStatus = event.ToStatus
+			if isTerminalFor(result.PendingOperation, event.ToStatus) {
+				result.PendingOperation = ""
+			}
+		case models.VmEventOperationFailed, models.VmEventOperationSucceeded:
+			result.PendingOperation = ""
+		}
+	}
+
+	return result, nil
+}
+
+// isTerminalFor는 상태 전이가 작업의 완료를 의미하는지 확인합니다.
+func isTerminalFor(operation, status string) bool {
+	switch operation {
+	case "create", "start", "restart", "unpause":
+		return status == string(models.VmStatusRunning)
+	case "pause":
+		return status == string(models.VmStatusPaused)
+	case "stop", "restore":
+		return status == string(models.VmStatusStopped)
+	case "delete":
+		return status == string(models.VmStatusDeleted)
+	}
+	return false
+}
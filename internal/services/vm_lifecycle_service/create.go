@@ -0,0 +1,411 @@
+package vmlifecycleservice
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/config"
+	"vm-controller/internal/logger"
+	"vm-controller/internal/models"
+	"vm-controller/internal/saga"
+	approvalservice "vm-controller/internal/services/approval_service"
+	k8s_service "vm-controller/internal/services/k8s_service"
+	quotaservice "vm-controller/internal/services/quota_service"
+	sshkeyservice "vm-controller/internal/services/ssh_key_service"
+	vm_service "vm-controller/internal/services/vm_service"
+)
+
+type CreateParams struct {
+	VmName        string `json:"vm_name"`
+	VmSSHPassword string `json:"vm_ssh_password"`
+	VmImage       string `json:"vm_image"`  // 이미지 이름 (기본값 ubuntu-22.04, GET /api/vm/images)
+	VmFlavor      string `json:"vm_flavor"` // 요금제 이름 (기본값 standard, GET /api/vm/flavors)
+	VmHostPrefix  string `json:"vm_host_prefix"`
+
+	Networks []NetworkParams `json:"networks"` // 보조 NIC (관리자가 등록한 네트워크)
+
+	// true면 플랫폼이 정책(VM_PASSWORD_*)에 맞는 비밀번호를 생성하여 응답으로 한 번만 반환 (vm_ssh_password와 함께 사용 불가)
+	GeneratePassword bool `json:"generate_password"`
+
+	SSHKeyIDs []uint `json:"ssh_key_ids"` // cloud-init으로 주입할 등록 SSH 공개 키 (/api/ssh-keys)
+
+	// 추가 cloud-config (packages, package_update, package_upgrade, runcmd, write_files, timezone, locale 만 허용)
+	// 플랫폼 userdata에 병합되며 runcmd는 플랫폼 명령 뒤에 실행됩니다.
+	CloudInit string `json:"cloud_init"`
+
+	Approved            bool `json:"-"` // 관리자 승인을 거친 요청 (요청 본문으로 받지 않으며 승인 처리에서만 설정)
+	ApprovalID          uint `json:"-"` // 승인된 요청 ID (Operator 모드: UserVM에 표시하여 reconcile이 승인 여부를 확인)
+	CredentialsRevealed bool `json:"-"` // 승인 요청 응답으로 자동 생성 비밀번호를 이미 전달함
+
+	fromOperator bool // UserVM reconcile에서 호출 (Operator 모드에서도 UserVM을 만들지 않고 직접 프로비저닝)
+}
+
+type NetworkParams struct {
+	Network string `json:"network"`           // 카탈로그의 네트워크 이름
+	Address string `json:"address,omitempty"` // 고정 IP (CIDR), 비우면 DHCP
+}
+
+// CreateResult는 생성 요청의 결과입니다. Accepted나 Approval이 있으면 VM은 아직 만들어지지 않았습니다.
+type CreateResult struct {
+	VM          *models.VirtualMachine // 생성된 VM (Accepted면 이름/네임스페이스/DNS만 채워짐)
+	OperationID uint                   // 생성 작업 기록 (기록 실패 시 0)
+	Password    string                 // 자동 생성한 비밀번호 (generate_password 요청 시에만, 다시 조회할 수 없음)
+	Warning     string                 // 스토리지 쿼터 근접 경고
+
+	Accepted bool               // Operator 모드: UserVM만 만들고 프로비저닝은 reconcile 컨트롤러가 수행
+	Approval *models.VmApproval // 승인 대상 요금제: 관리자 승인 대기 중인 요청
+}
+
+// ApplyDefaults는 생성 요청에서 생략한 항목을 사용자의 VM 생성 기본값으로 채웁니다.
+// ssh_key_ids는 필드 자체가 없을 때만 채우며, 빈 배열을 보내면 키 없이 생성합니다.
+func (s *VmLifecycleService) ApplyDefaults(userId uint, req *CreateParams) error {
+	pref, err := s.preferenceService.FetchPreference(userId)
+	if err != nil {
+		return err
+	}
+
+	if req.VmImage == "" {
+		req.VmImage = pref.Image
+	}
+	if req.VmFlavor == "" {
+		req.VmFlavor = pref.Flavor
+	}
+	if req.SSHKeyIDs == nil {
+		req.SSHKeyIDs = pref.SSHKeyIDs
+	}
+	if req.VmHostPrefix == "" && pref.DNSBase != "" && req.VmName != "" {
+		req.VmHostPrefix = req.VmName + "." + pref.DNSBase
+	}
+
+	return nil
+}
+
+// QuotaRequest는 VM 1대를 생성할 때 차원별로 추가되는 쿼터 사용량입니다.
+// 요금제를 찾을 수 없으면 기본 디스크 크기로 계산합니다. (요금제 오류는 검증 단계에서 따로 보고)
+func (s *VmLifecycleService) QuotaRequest(flavorName string) map[quotaservice.Dimension]int {
+	diskGi := quotaservice.VmDiskSizeGi
+	if flavor, err := k8s_service.GetFlavor(s.flavorService, flavorName); err == nil {
+		diskGi = flavor.DiskGi
+	}
+
+	return map[quotaservice.Dimension]int{
+		quotaservice.DimensionStorage: diskGi,
+		quotaservice.DimensionVMs:     1,
+	}
+}
+
+// ValidateOperatorParams는 Operator 모드에서 UserVM spec으로 표현할 수 없는 생성 요청 값을 거부합니다.
+// 조용히 무시하면 요청과 다른 VM이 만들어지므로 CodeInvalidRequest로 알립니다.
+func ValidateOperatorParams(req CreateParams) error {
+	if len(req.SSHKeyIDs) > 0 || req.CloudInit != "" {
+		return errors.New("ssh_key_ids and cloud_init are not supported in operator mode")
+	}
+	if len(req.Networks) > 0 {
+		return errors.New("networks are not supported in operator mode")
+	}
+	return nil
+}
+
+// ValidateCreateParams는 클러스터/DB를 변경하지 않고 확인할 수 있는 생성 요청 값을 검사합니다.
+func (s *VmLifecycleService) ValidateCreateParams(req CreateParams) ([]models.VmNetwork, error) {
+	// VM 이름은 라벨 값과 hostname으로 쓰이므로 최대 길이를 알려줌 (k8s_service.MaxVMNameLength)
+	if err := k8s_service.ValidateVMName(req.VmName); err != nil {
+		return nil, err
+	}
+
+	// VmHostPrefix가 유효한 도메인 형식(예: prefix.domain.com)인지 검사합니다.
+	// 도메인 네임으로 사용될 것이므로 DNS 규약을 준수해야 합니다.
+	if matched, _ := regexp.MatchString(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)+$`, req.VmHostPrefix); !matched {
+		return nil, fmt.Errorf("VmHostPrefix must be in a valid domain format (e.g., prefix.domain.com)")
+	}
+	if err := k8s_service.ValidateDNSHost(req.VmHostPrefix + os.Getenv("HOSTNAME")); err != nil {
+		return nil, err
+	}
+
+	if _, err := k8s_service.GetFlavor(s.flavorService, req.VmFlavor); err != nil {
+		return nil, err
+	}
+
+	if _, err := k8s_service.GetImage(s.imageService, req.VmImage); err != nil {
+		return nil, err
+	}
+
+	if _, err := k8s_service.ParseCloudInit(req.CloudInit); err != nil {
+		return nil, err
+	}
+
+	return s.buildVmNetworks(req.Networks)
+}
+
+// buildVmNetworks는 요청한 보조 NIC를 카탈로그와 대조하고, 재생성 시에도 유지할 MAC 주소를 발급합니다.
+func (s *VmLifecycleService) buildVmNetworks(params []NetworkParams) ([]models.VmNetwork, error) {
+	if len(params) > k8s_service.MaxSecondaryNetworks {
+		return nil, fmt.Errorf("at most %d additional networks are allowed", k8s_service.MaxSecondaryNetworks)
+	}
+
+	networks := make([]models.VmNetwork, 0, len(params))
+	for _, param := range params {
+		network, err := s.networkService.FetchNetwork(param.Network)
+		if err != nil {
+			return nil, err
+		}
+		if network == nil || !network.Enabled {
+			return nil, fmt.Errorf("network %s is not available", param.Network)
+		}
+
+		if err := k8s_service.ValidateVmNetwork(network, param.Address); err != nil {
+			return nil, err
+		}
+
+		networks = append(networks, models.VmNetwork{
+			Network:    network.Name,
+			NadRef:     network.NadNamespace + "/" + network.NadName,
+			MacAddress: k8s_service.GenerateMACAddress(),
+			Address:    param.Address,
+		})
+	}
+
+	return networks, nil
+}
+
+// Create는 사용자에게 배정된 매니페스트 번들(stable/canary)의 template으로 VM을 생성하고 DB에 등록합니다.
+// Operator 모드에서는 UserVM만 만들고(Accepted), 승인 대상 요금제는 승인 요청만 저장합니다(Approval).
+// UserVM reconcile도 이 함수로 프로비저닝하므로 kubectl로 만든 UserVM에도 같은 검증/쿼터/승인 규칙이 적용됩니다.
+func (s *VmLifecycleService) Create(user *models.User, req CreateParams, template, traceID string) (*CreateResult, error) {
+	if err := s.checkBackpressure(); err != nil {
+		return nil, err
+	}
+
+	operatorFacade := config.Get().OperatorMode && !req.fromOperator
+	if operatorFacade {
+		if err := ValidateOperatorParams(req); err != nil {
+			return nil, newError(KindInvalid, err.Error(), err)
+		}
+	}
+
+	// 쿼터 확인 (스토리지는 관리형 데이터베이스 볼륨과 합산, hard cap 초과 시 거부)
+	headrooms, err := s.quotaService.Check(user.ID, s.QuotaRequest(req.VmFlavor))
+	if apperrors.CodeOf(err) == apperrors.CodeQuotaExceeded {
+		return nil, newError(KindForbidden, err.Error(), err)
+	}
+	if err != nil {
+		return nil, newError(KindInternal, "Failed to check quota", err)
+	}
+
+	networks, err := s.ValidateCreateParams(req)
+	if err != nil {
+		return nil, newError(KindInvalid, err.Error(), err)
+	}
+
+	keys, err := s.sshKeyService.FetchUserKeysByIds(user.ID, req.SSHKeyIDs)
+	if err != nil {
+		if errors.Is(err, sshkeyservice.ErrKeyNotFound) {
+			return nil, newError(KindInvalid, err.Error(), err)
+		}
+		return nil, newError(KindInternal, "Failed to fetch ssh keys", err)
+	}
+	var sshKeys []string
+	for _, key := range keys {
+		sshKeys = append(sshKeys, key.PublicKey)
+	}
+
+	// 비밀번호 자동 생성: DB에는 암호화되어 저장되므로 생성 응답이 비밀번호를 확인할 수 있는 유일한 기회
+	generatedPassword := ""
+	if req.GeneratePassword {
+		if req.VmSSHPassword != "" {
+			return nil, newError(KindInvalid, "vm_ssh_password and generate_password cannot be used together", nil)
+		}
+
+		generatedPassword, err = s.passwordService.Generate()
+		if err != nil {
+			s.log().Error("failed to generate password", "vm", req.VmName, "error", err.Error())
+			return nil, newError(KindUnavailable, "Password generation is not available", err)
+		}
+		req.VmSSHPassword = generatedPassword
+	}
+
+	hostname := req.VmHostPrefix + os.Getenv("HOSTNAME")
+
+	// 승인 대상 요금제는 요청만 저장하고, 관리자가 승인하면 저장된 요청으로 다시 이 경로를 실행 (Operator 모드 포함)
+	if flavor, _ := k8s_service.GetFlavor(s.flavorService, req.VmFlavor); flavor.RequiresApproval() && !req.Approved {
+		approval, err := s.requestApproval(user, req, template, generatedPassword)
+		if err != nil {
+			return nil, err
+		}
+		return &CreateResult{Approval: approval, Password: generatedPassword}, nil
+	}
+
+	// Operator 모드: UserVM만 생성하고 실제 프로비저닝은 reconcile 컨트롤러가 이 경로를 다시 실행하여 수행
+	if operatorFacade {
+		err := s.k8s().ApplyUserVM(k8s_service.UserVMSpec{
+			Namespace:  user.Namespace,
+			Name:       req.VmName,
+			Image:      req.VmImage,
+			Flavor:     req.VmFlavor,
+			HostPrefix: req.VmHostPrefix,
+			Running:    true,
+			ApprovalID: req.ApprovalID,
+		}, req.VmSSHPassword)
+		if errors.Is(err, k8s_service.ErrUserVMExists) {
+			return nil, newError(KindConflict, err.Error(), err).withCode(apperrors.CodeNameTaken)
+		}
+		if err != nil {
+			return nil, newError(KindInternal, "Failed to create VM", err)
+		}
+		return &CreateResult{
+			VM:       &models.VirtualMachine{Name: req.VmName, Namespace: user.Namespace, DnsHost: hostname},
+			Password: generatedPassword,
+			Accepted: true,
+		}, nil
+	}
+
+	// 모든 노드 풀이 유지보수 중이면 스케줄되지 않을 VM을 만들지 않음
+	if err := s.k8s().CheckNodePoolCapacity(); err != nil {
+		if errors.Is(err, k8s_service.ErrNoSchedulablePool) {
+			return nil, newError(KindUnavailable, err.Error(), err)
+		}
+		s.log().Warn("failed to check node pool capacity", "vm", req.VmName, "error", err.Error())
+	}
+
+	bundle := s.bundleService.Assign(user)
+	manifestDir := s.bundleService.TemplateDir(bundle.Channel, template)
+
+	// 검증 단계에서 확인한 카탈로그 항목 (생략된 이름은 기본값으로 해석)
+	flavor, _ := k8s_service.GetFlavor(s.flavorService, req.VmFlavor)
+	image, _ := k8s_service.GetImage(s.imageService, req.VmImage)
+
+	// 단계마다 되돌리는 방법을 등록하여, 이후 단계가 실패하면 DB 레코드와 클러스터 리소스를 함께 되돌림
+	sg := saga.New("vm.create", "vm", req.VmName, "user_id", user.ID, "request_id", logger.RequestIDFrom(s.ctx))
+	defer sg.Rollback()
+
+	// 1. NodePort 할당 + DB 레코드 생성 (Provisioning): 클러스터에 적용하기 전에 이름과 포트를 선점
+	record, err := s.vmService.ReserveUserVM(vm_service.CreateVmParams{
+		VmName:        req.VmName,
+		VmPassword:    req.VmSSHPassword,
+		VmImage:       image.Name,
+		SSHUser:       image.SSHUser,
+		VmFlavor:      flavor.Name,
+		DiskGi:        flavor.DiskGi,
+		DnsHost:       hostname,
+		Networks:      networks,
+		SSHKeys:       sshKeys,
+		CloudInit:     req.CloudInit,
+		Namespace:     user.Namespace,
+		UserID:        user.ID,
+		BundleChannel: bundle.Channel,
+		BundleVersion: bundle.Version,
+
+		CredentialsRevealed: generatedPassword != "" || req.CredentialsRevealed,
+		ExpiresAt:           s.vmService.LeaseExpiry(user),
+	})
+	if err != nil {
+		if errors.Is(err, vm_service.ErrVmNameTaken) {
+			return nil, newError(KindConflict, err.Error(), err)
+		}
+		if errors.Is(err, vm_service.ErrPortsExhausted) {
+			return nil, newError(KindUnavailable, err.Error(), err)
+		}
+		return nil, newError(KindInternal, "Failed to get available port", err)
+	}
+	sg.Compensate("discard vm record", func() error { return s.vmService.DiscardUserVM(record) })
+
+	// 2. 매니페스트 적용: 실패하면 k8s_service가 적용한 리소스를 되돌리므로 여기서는 DB 레코드만 삭제
+	vm, err := s.k8s().CreateUserVM(user.Namespace,
+		req.VmName, req.VmSSHPassword, hostname, manifestDir, req.VmFlavor, req.VmImage, record.NodePort, networks, sshKeys, req.CloudInit)
+
+	if err != nil {
+		s.bundleService.RecordAttempt(req.VmName, user.ID, bundle, err)
+		// 같은 이름의 리소스가 다른 소유자의 것이면 재시도로 해결되지 않으므로 사유를 알림
+		if errors.Is(err, k8s_service.ErrResourceConflict) {
+			return nil, newError(KindConflict, err.Error(), err)
+		}
+		return nil, newError(KindInternal, "Failed to create VM", err)
+	}
+
+	sg.Compensate("delete vm resources", func() error { return s.provisioner.RollbackUserVM(vm) })
+
+	// 3. Running으로 갱신: 실패하면 클러스터 리소스와 DB 레코드를 모두 되돌림
+	if err := s.vmService.CompleteUserVM(record, vm.MacAddress); err != nil {
+		s.log().Error("failed to complete vm", "vm", req.VmName, "error", err.Error())
+		s.bundleService.RecordAttempt(req.VmName, user.ID, bundle, err)
+		return nil, newError(KindInternal, "Failed to create VM", err)
+	}
+	sg.Complete()
+
+	s.vmEventService.RecordOperation(req.VmName, "create", user.ID)
+	s.bundleService.RecordAttempt(req.VmName, user.ID, bundle, nil)
+	s.quotaService.NotifySoftLimits(user.ID, headrooms)
+
+	result := &CreateResult{VM: record, Password: generatedPassword}
+	// 리소스 생성은 요청 안에서 끝나므로 완료된 작업으로 기록 (디스크 준비는 VM 상태로 확인)
+	if operation, err := s.operationService.RecordFinished("vm.create", "vm/"+req.VmName, user.ID, traceID); err == nil {
+		result.OperationID = operation.ID
+	}
+	// 쿼터에 가까워졌으면 다음 디스크 작업이 실패하기 전에 미리 경고
+	if summary, err := s.quotaService.StorageSummary(user.ID); err == nil && summary.NearQuota {
+		result.Warning = summary.Warning
+	}
+
+	return result, nil
+}
+
+// requestApproval은 승인 대상 요금제의 생성 요청을 PendingApproval 상태로 저장하고 관리자에게 알립니다.
+// 자동 생성한 비밀번호는 승인 후 다시 만들지 않도록 요청과 함께 저장하며, 이 응답에서만 한 번 반환합니다.
+// 비밀번호는 요청 본문과 분리하여 암호화된 별도 컬럼에 저장합니다.
+func (s *VmLifecycleService) requestApproval(user *models.User, req CreateParams, template, generatedPassword string) (*models.VmApproval, error) {
+	if existing, err := s.vms().FetchVmName(req.VmName, false); err == nil && existing != nil {
+		return nil, newError(KindConflict, "VM name is already in use", nil).withCode(apperrors.CodeNameTaken)
+	}
+
+	password := req.VmSSHPassword
+	req.GeneratePassword = false
+	req.VmSSHPassword = ""
+	params, err := json.Marshal(req)
+	if err != nil {
+		return nil, newError(KindInternal, "Failed to request approval", err)
+	}
+
+	approvalService := s.approvalService
+	approval := &models.VmApproval{
+		UserID:   user.ID,
+		VmName:   req.VmName,
+		VmFlavor: req.VmFlavor,
+		Template: template,
+		Params:   string(params),
+
+		Password:          password,
+		PasswordGenerated: generatedPassword != "",
+	}
+
+	if err := approvalService.CreateApproval(approval); err != nil {
+		if errors.Is(err, approvalservice.ErrApprovalExists) {
+			return nil, newError(KindConflict, err.Error(), err)
+		}
+		return nil, newError(KindInternal, "Failed to request approval", err)
+	}
+
+	s.vmEventService.RecordOperation(req.VmName, "approval.request", user.ID)
+	approvalService.NotifyReviewers(approval, user)
+
+	return approval, nil
+}
+
+// ApprovalParams는 저장된 생성 요청을 복원하고 승인된 요청으로 표시합니다.
+func ApprovalParams(approval *models.VmApproval) (CreateParams, error) {
+	var req CreateParams
+	if err := json.Unmarshal([]byte(approval.Params), &req); err != nil {
+		return req, fmt.Errorf("failed to decode approval %d: %v", approval.ID, err)
+	}
+	// 비밀번호를 분리하기 전에 저장된 요청은 본문에 비밀번호가 남아 있음
+	if approval.Password != "" {
+		req.VmSSHPassword = approval.Password
+	}
+	req.Approved = true
+	req.ApprovalID = approval.ID
+	req.CredentialsRevealed = approval.PasswordGenerated
+
+	return req, nil
+}
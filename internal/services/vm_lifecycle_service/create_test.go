@@ -0,0 +1,209 @@
+package vmlifecycleservice
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"vm-controller/internal/config"
+	"vm-controller/internal/db"
+	"vm-controller/internal/models"
+	approvalservice "vm-controller/internal/services/approval_service"
+	bundleservice "vm-controller/internal/services/bundle_service"
+	flavorservice "vm-controller/internal/services/flavor_service"
+	imageservice "vm-controller/internal/services/image_service"
+	k8s_service "vm-controller/internal/services/k8s_service"
+	"vm-controller/internal/services/k8s_service/k8sfake"
+	networkservice "vm-controller/internal/services/network_service"
+	notificationservice "vm-controller/internal/services/notification_service"
+	operationservice "vm-controller/internal/services/operation_service"
+	passwordservice "vm-controller/internal/services/password_service"
+	preferenceservice "vm-controller/internal/services/preference_service"
+	quotaservice "vm-controller/internal/services/quota_service"
+	sshkeyservice "vm-controller/internal/services/ssh_key_service"
+	userservice "vm-controller/internal/services/user_service"
+	vmeventservice "vm-controller/internal/services/vm_event_service"
+	vm_service "vm-controller/internal/services/vm_service"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestService는 메모리 SQLite DB와 k8sfake.Provisioner로 구성한 서비스입니다. (클러스터/Postgres 없이 실행)
+func newTestService(t *testing.T) (*VmLifecycleService, *k8sfake.Provisioner, *gorm.DB) {
+	t.Helper()
+
+	t.Setenv("HOSTNAME", "")
+	t.Setenv("OPERATOR_MODE", "false")
+	config.Load()
+	t.Cleanup(func() { config.Load() })
+
+	conn, err := gorm.Open(sqlite.Open("file:"+strings.ReplaceAll(t.Name(), "/", "_")+"?mode=memory&cache=shared"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.Migrate(conn); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	previous := db.DB
+	db.DB = conn
+	t.Cleanup(func() {
+		db.DB = previous
+		if sqlDB, err := conn.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	notificationService := notificationservice.NewNotificationService()
+	sshKeyService := sshkeyservice.NewSSHKeyService()
+	deps := Dependencies{
+		ApprovalService:   approvalservice.NewApprovalService(notificationService),
+		BundleService:     bundleservice.NewBundleService(),
+		FlavorService:     flavorservice.NewFlavorService(),
+		ImageService:      imageservice.NewImageService(),
+		NetworkService:    networkservice.NewNetworkService(),
+		OperationService:  operationservice.NewOperationService(),
+		PasswordService:   passwordservice.NewPasswordService(),
+		PreferenceService: preferenceservice.NewPreferenceService(sshKeyService),
+		QuotaService:      quotaservice.NewQuotaService(notificationService),
+		SSHKeyService:     sshKeyService,
+		UserService:       userservice.NewUserService(),
+		VmEventService:    vmeventservice.NewVmEventService(),
+		VmService:         vm_service.NewVmService(),
+	}
+	if err := deps.FlavorService.SeedDefaults(); err != nil {
+		t.Fatalf("seed flavors: %v", err)
+	}
+	if err := deps.ImageService.SeedDefaults(); err != nil {
+		t.Fatalf("seed images: %v", err)
+	}
+
+	provisioner := k8sfake.NewProvisioner()
+	s := NewVmLifecycleService(provisioner, deps)
+	s.backpressure = func() k8s_service.BackpressureStatus { return k8s_service.BackpressureStatus{} }
+	return s, provisioner, conn
+}
+
+func newTestUser(t *testing.T, conn *gorm.DB) *models.User {
+	t.Helper()
+	user := &models.User{UserStudentId: "20240001", Namespace: "user-20240001"}
+	if err := conn.Create(user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return user
+}
+
+func testCreateParams(name string) CreateParams {
+	return CreateParams{
+		VmName:        name,
+		VmSSHPassword: "Passw0rd!23",
+		VmHostPrefix:  name + ".example.com",
+		SSHKeyIDs:     []uint{},
+	}
+}
+
+func TestCreate(t *testing.T) {
+	s, provisioner, conn := newTestService(t)
+	user := newTestUser(t, conn)
+
+	result, err := s.Create(user, testCreateParams("lab-1"), bundleservice.VMTemplate, "")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if result.VM == nil || result.VM.Status != models.VmStatusRunning {
+		t.Fatalf("VM = %+v, want a Running record", result.VM)
+	}
+
+	state, ok := provisioner.VMs[user.Namespace+"/lab-1"]
+	if !ok || !state.Running || state.Info.Port != result.VM.NodePort {
+		t.Fatalf("cluster VM = %+v, want running on NodePort %d", state, result.VM.NodePort)
+	}
+	if result.VM.MacAddress != state.Info.MacAddress {
+		t.Errorf("recorded MAC = %q, want %q", result.VM.MacAddress, state.Info.MacAddress)
+	}
+
+	// 같은 이름은 클러스터에 적용하기 전에 거부
+	_, err = s.Create(user, testCreateParams("lab-1"), bundleservice.VMTemplate, "")
+	if KindOf(err) != KindConflict {
+		t.Fatalf("duplicate Create: kind = %v, want KindConflict (err = %v)", KindOf(err), err)
+	}
+	if len(provisioner.Calls) != 1 {
+		t.Errorf("calls = %v, want only the first create", provisioner.Calls)
+	}
+}
+
+// 매니페스트 적용이 실패하면 provisioner가 적용한 리소스를 되돌리고, 서비스는 선점한 DB 레코드를 삭제해야 함
+func TestCreateRollsBackWhenProvisioningFails(t *testing.T) {
+	s, provisioner, conn := newTestService(t)
+	user := newTestUser(t, conn)
+	provisioner.Fail[k8sfake.OpCreate] = errors.New("admission webhook denied the request")
+
+	_, err := s.Create(user, testCreateParams("lab-1"), bundleservice.VMTemplate, "")
+	if KindOf(err) != KindInternal {
+		t.Fatalf("kind = %v, want KindInternal (err = %v)", KindOf(err), err)
+	}
+
+	if vm, _ := s.vms().FetchVmNameIncludingDeleted("lab-1"); vm != nil {
+		t.Errorf("VM record %+v was kept, want it discarded", vm)
+	}
+	if len(provisioner.RolledBack) != 2 || provisioner.RolledBack[0].Kind != "Service" {
+		t.Errorf("rolled back = %+v, want the Service and then the VirtualMachine", provisioner.RolledBack)
+	}
+}
+
+// 리소스 생성 이후 DB 갱신이 실패하면 클러스터 리소스(RollbackUserVM)와 DB 레코드를 모두 되돌려야 함
+func TestCreateRollsBackWhenCompletionFails(t *testing.T) {
+	s, provisioner, conn := newTestService(t)
+	user := newTestUser(t, conn)
+
+	conn.Callback().Update().Before("gorm:update").Register("test:fail_vm_update", func(tx *gorm.DB) {
+		if tx.Statement.Table == "virtual_machines" {
+			tx.AddError(errors.New("connection reset"))
+		}
+	})
+
+	_, err := s.Create(user, testCreateParams("lab-1"), bundleservice.VMTemplate, "")
+	if KindOf(err) != KindInternal {
+		t.Fatalf("kind = %v, want KindInternal (err = %v)", KindOf(err), err)
+	}
+
+	want := []string{k8sfake.OpCreate + " " + user.Namespace + "/lab-1", k8sfake.OpRollback + " " + user.Namespace + "/lab-1"}
+	if strings.Join(provisioner.Calls, ",") != strings.Join(want, ",") {
+		t.Errorf("calls = %v, want %v", provisioner.Calls, want)
+	}
+	if _, ok := provisioner.VMs[user.Namespace+"/lab-1"]; ok {
+		t.Error("cluster VM was kept, want it rolled back")
+	}
+	if vm, _ := s.vms().FetchVmNameIncludingDeleted("lab-1"); vm != nil {
+		t.Errorf("VM record %+v was kept, want it discarded", vm)
+	}
+}
+
+// Operator 모드에서는 UserVM만 만들고, 표현할 수 없는 항목은 거부해야 함
+func TestCreateOperatorMode(t *testing.T) {
+	s, provisioner, conn := newTestService(t)
+	user := newTestUser(t, conn)
+	t.Setenv("OPERATOR_MODE", "true")
+	config.Load()
+
+	req := testCreateParams("lab-1")
+	req.VmFlavor = "standard"
+	result, err := s.Create(user, req, bundleservice.VMTemplate, "")
+	if err != nil || !result.Accepted {
+		t.Fatalf("Create = %+v, %v; want accepted", result, err)
+	}
+	uvm, ok := provisioner.UserVMs[user.Namespace+"/lab-1"]
+	if !ok || uvm.Spec.Flavor != "standard" || !uvm.Spec.Running {
+		t.Fatalf("UserVM = %+v, want a running standard UserVM", uvm)
+	}
+	if len(provisioner.VMs) != 0 {
+		t.Errorf("cluster VMs = %v, want none until reconcile", provisioner.VMs)
+	}
+
+	req = testCreateParams("lab-2")
+	req.Networks = []NetworkParams{{Network: "lab-net"}}
+	if _, err := s.Create(user, req, bundleservice.VMTemplate, ""); KindOf(err) != KindInvalid {
+		t.Errorf("networks: kind = %v, want KindInvalid (err = %v)", KindOf(err), err)
+	}
+}
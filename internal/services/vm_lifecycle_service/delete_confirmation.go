@@ -0,0 +1,63 @@
+package vmlifecycleservice
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	sync "sync"
+	"time"
+)
+
+// 삭제 확인 토큰 유효 시간
+const deleteConfirmationTTL = 5 * time.Minute
+
+type deleteConfirmation struct {
+	token     string
+	expiresAt time.Time
+}
+
+// "userID/vmName" 별로 마지막에 발급한 삭제 확인 토큰 (1회용)
+var deleteConfirmations sync.Map
+
+func deleteConfirmationKey(userID uint, vmName string) string {
+	return fmt.Sprintf("%d/%s", userID, vmName)
+}
+
+// IssueDeleteConfirmation은 Delete의 confirm 값으로 쓸 수 있는 1회용 토큰을 발급합니다.
+// 호출자는 먼저 FetchOwned로 요청자가 VM에 접근할 수 있는지 확인해야 합니다.
+func IssueDeleteConfirmation(userID uint, vmName string) (string, time.Time, error) {
+	tokenBytes := make([]byte, 16)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", time.Time{}, err
+	}
+
+	confirmation := deleteConfirmation{
+		token:     hex.EncodeToString(tokenBytes),
+		expiresAt: time.Now().Add(deleteConfirmationTTL),
+	}
+	deleteConfirmations.Store(deleteConfirmationKey(userID, vmName), confirmation)
+
+	return confirmation.token, confirmation.expiresAt, nil
+}
+
+// confirmDelete는 confirm 값이 VM 이름이거나 유효한 확인 토큰인지 확인합니다. 토큰은 사용하면 폐기됩니다.
+func confirmDelete(userID uint, vmName, confirm string) bool {
+	if confirm == "" {
+		return false
+	}
+	if confirm == vmName {
+		return true
+	}
+
+	value, ok := deleteConfirmations.Load(deleteConfirmationKey(userID, vmName))
+	if !ok {
+		return false
+	}
+	confirmation := value.(deleteConfirmation)
+	if time.Now().After(confirmation.expiresAt) || subtle.ConstantTimeCompare([]byte(confirm), []byte(confirmation.token)) != 1 {
+		return false
+	}
+
+	return deleteConfirmations.CompareAndDelete(deleteConfirmationKey(userID, vmName), value)
+}
@@ -0,0 +1,114 @@
+package vmlifecycleservice
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"vm-controller/internal/apperrors"
+)
+
+// ErrorKind는 요청이 거부되거나 실패한 유형입니다. REST와 gRPC는 이 값으로 각자의 상태 코드를 정합니다.
+type ErrorKind int
+
+const (
+	KindInvalid     ErrorKind = iota // 요청 값 오류 (400, InvalidArgument)
+	KindNotFound                     // VM이 없거나 요청자가 접근할 수 없음 (404, NotFound)
+	KindForbidden                    // 쿼터 초과 등 정책상 거부 (403, PermissionDenied)
+	KindConflict                     // 이름 중복, 현재 상태에서 허용되지 않는 요청 (409, FailedPrecondition)
+	KindUnavailable                  // 일시적으로 처리할 수 없음 (503, Unavailable)
+	KindTimeout                      // 작업이 제한 시간 안에 끝나지 않음 (504, DeadlineExceeded)
+	KindInternal                     // 서버 내부 오류 (500, Internal)
+	KindBusy                         // 작업 큐 과부하로 새 작업을 받지 않음 (429 + Retry-After, ResourceExhausted)
+)
+
+// Error는 호출자에게 그대로 보여줄 수 있는 메시지와 유형을 가진 에러입니다.
+// 내부 오류의 원인(Err)은 로그용이며 메시지에 포함하지 않습니다.
+type Error struct {
+	Kind    ErrorKind
+	Code    apperrors.Code // API 응답 코드 (비어 있으면 원인 에러의 코드 또는 Kind로 결정)
+	Message string
+	Err     error
+
+	RetryAfter time.Duration // KindBusy: 다시 시도할 수 있을 때까지의 시간
+	Reason     string        // KindBusy: 과부하 사유 (queue_depth / error_rate)
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+func newError(kind ErrorKind, message string, err error) *Error {
+	return &Error{Kind: kind, Message: message, Err: err}
+}
+
+// withCode는 API 응답 코드를 지정합니다.
+func (e *Error) withCode(code apperrors.Code) *Error {
+	e.Code = code
+	return e
+}
+
+// ErrVMNotFound는 VM이 없거나 요청자에게 권한이 없을 때 반환됩니다. (다른 사용자의 VM은 존재 여부도 알리지 않음)
+var ErrVMNotFound = &Error{Kind: KindNotFound, Code: apperrors.CodeVMNotFound, Message: "VM not found"}
+
+// RetryAfterOf는 KindBusy 에러의 재시도 대기 시간과 과부하 사유를 반환합니다.
+func RetryAfterOf(err error) (time.Duration, string) {
+	var lifecycleErr *Error
+	if errors.As(err, &lifecycleErr) {
+		return lifecycleErr.RetryAfter, lifecycleErr.Reason
+	}
+	return 0, ""
+}
+
+// KindOf는 err의 유형을 반환합니다. Error가 아니면 KindInternal입니다.
+func KindOf(err error) ErrorKind {
+	var lifecycleErr *Error
+	if errors.As(err, &lifecycleErr) {
+		return lifecycleErr.Kind
+	}
+	return KindInternal
+}
+
+// MessageOf는 호출자에게 보여줄 메시지를 반환합니다. Error가 아니면 fallback입니다.
+func MessageOf(err error, fallback string) string {
+	var lifecycleErr *Error
+	if errors.As(err, &lifecycleErr) {
+		return lifecycleErr.Message
+	}
+	return fallback
+}
+
+// CodeOf는 API 응답에 쓸 에러 코드를 반환합니다.
+// 지정된 코드가 없으면 원인 에러의 코드(쿼터 초과, 포트 고갈 등)를, 그것도 없으면 Kind에 대응하는 일반 코드를 씁니다.
+func CodeOf(err error) apperrors.Code {
+	var lifecycleErr *Error
+	if errors.As(err, &lifecycleErr) && lifecycleErr.Code != "" {
+		return lifecycleErr.Code
+	}
+	if code := apperrors.CodeOf(err); code != "" {
+		return code
+	}
+
+	switch KindOf(err) {
+	case KindInvalid:
+		return apperrors.CodeInvalidRequest
+	case KindNotFound:
+		return apperrors.CodeVMNotFound
+	case KindForbidden:
+		return apperrors.CodeForbidden
+	case KindConflict:
+		return apperrors.CodeConflict
+	case KindUnavailable:
+		return apperrors.CodeUnavailable
+	case KindTimeout:
+		return apperrors.CodeTimeout
+	case KindBusy:
+		return apperrors.CodeServerBusy
+	}
+	return apperrors.CodeInternal
+}
@@ -0,0 +1,187 @@
+package vmlifecycleservice
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"vm-controller/internal/models"
+	approvalservice "vm-controller/internal/services/approval_service"
+	bundleservice "vm-controller/internal/services/bundle_service"
+	k8s_service "vm-controller/internal/services/k8s_service"
+)
+
+// StartOperator는 UserVM 컨트롤러를 시작합니다. (Operator 모드)
+// UserVM은 REST 생성 요청과 같은 Create 경로로 프로비저닝하므로 검증, 쿼터, 승인 규칙이 그대로 적용되고,
+// 만들 수 없는 spec은 UserVM status(Rejected, PendingApproval)로 알립니다.
+func (s *VmLifecycleService) StartOperator(resync time.Duration) error {
+	return s.provisioner.StartOperator(resync, s.reconcileUserVM)
+}
+
+// reconcileUserVM은 UserVM 한 개를 DB의 VM 레코드 및 목표 상태와 일치시킵니다.
+func (s *VmLifecycleService) reconcileUserVM(namespace, name string) error {
+	spec, err := s.k8s().FetchUserVM(namespace, name)
+	if err != nil {
+		return err
+	}
+
+	vm, err := s.vms().FetchVmName(name, false)
+	if err != nil {
+		return err
+	}
+
+	if spec == nil {
+		// UserVM 삭제됨 -> VM 삭제
+		if vm == nil || vm.Namespace != namespace {
+			return nil
+		}
+		if err := s.vms().SetDesiredState(vm.Name, models.VmDesiredDeleted); err != nil {
+			return err
+		}
+		s.async().DeleteVMAsync(vm, "")
+		return nil
+	}
+
+	if vm == nil {
+		vm, err = s.provisionUserVM(spec)
+		if vm == nil {
+			// 승인 대기, 거부(status에 기록) 또는 재시도할 실패
+			return err
+		}
+	} else if vm.Namespace != namespace {
+		// 같은 이름의 VM이 다른 사용자에게 이미 존재
+		s.k8s().UpdateUserVMStatus(namespace, name, k8s_service.UserVMPhaseRejected, 0, "VM name is already in use")
+		return nil
+	}
+
+	desired := models.VmDesiredStopped
+	if spec.Running {
+		desired = models.VmDesiredRunning
+	}
+	if vm.DesiredState != desired {
+		if err := s.vms().SetDesiredState(vm.Name, desired); err != nil {
+			return err
+		}
+		vm.DesiredState = desired
+
+		// converger 주기를 기다리지 않고 즉시 반영
+		if desired == models.VmDesiredRunning {
+			s.async().StartVMAsync(vm, "")
+		} else {
+			s.async().StopVMAsync(vm, "")
+		}
+	}
+
+	s.k8s().UpdateUserVMStatus(namespace, name, string(vm.Status), vm.NodePort, "")
+	return nil
+}
+
+// provisionUserVM은 UserVM spec을 네임스페이스 소유자의 생성 요청으로 바꿔 Create로 VM을 만듭니다.
+// VM을 만들지 못하면 UserVM status를 갱신하고 nil VM을 반환하며, 다시 시도할 실패만 에러로 반환합니다.
+func (s *VmLifecycleService) provisionUserVM(spec *k8s_service.UserVMSpec) (*models.VirtualMachine, error) {
+	reject := func(message string) (*models.VirtualMachine, error) {
+		s.k8s().UpdateUserVMStatus(spec.Namespace, spec.Name, k8s_service.UserVMPhaseRejected, 0, message)
+		return nil, nil
+	}
+
+	user, err := s.userService.FetchUserByNamespace(spec.Namespace)
+	if err != nil || user == nil {
+		return reject(fmt.Sprintf("no user owns namespace %s", spec.Namespace))
+	}
+
+	password, err := s.k8s().ReadUserVMPassword(spec)
+	if err != nil {
+		return reject(err.Error())
+	}
+
+	// UserVM spec에는 SSH 키 / cloud-init / 보조 NIC가 없으므로 기본값의 SSH 키도 쓰지 않음
+	req := CreateParams{
+		VmName:        spec.Name,
+		VmSSHPassword: password,
+		VmImage:       spec.Image,
+		VmFlavor:      spec.Flavor,
+		VmHostPrefix:  spec.HostPrefix,
+		SSHKeyIDs:     []uint{},
+		fromOperator:  true,
+	}
+	if err := s.ApplyDefaults(user.ID, &req); err != nil {
+		return nil, err
+	}
+
+	if spec.ApprovalID != 0 {
+		if err := s.checkUserVMApproval(spec.ApprovalID, user, req); err != nil {
+			return reject(err.Error())
+		}
+		req.Approved = true
+		req.ApprovalID = spec.ApprovalID
+	} else if reviewed, err := s.reviewedUserVMApproval(user, spec); err != nil {
+		return nil, err
+	} else if reviewed != nil {
+		// 거절된 요청을 resync마다 다시 요청하지 않음 (UserVM을 다시 만들면 새로 요청)
+		return reject(fmt.Sprintf("approval %d is %s: %s", reviewed.ID, reviewed.Status, reviewed.Reason))
+	}
+
+	result, err := s.Create(user, req, bundleservice.VMTemplate, "")
+	if err != nil {
+		phase, retry := userVMFailure(err)
+		s.k8s().UpdateUserVMStatus(spec.Namespace, spec.Name, phase, 0, MessageOf(err, "Failed to create VM"))
+		if retry {
+			return nil, err
+		}
+		return nil, nil
+	}
+	if result.Approval != nil {
+		s.k8s().UpdateUserVMStatus(spec.Namespace, spec.Name, k8s_service.UserVMPhasePendingApproval, 0,
+			fmt.Sprintf("waiting for admin approval (approval %d)", result.Approval.ID))
+		return nil, nil
+	}
+	return result.VM, nil
+}
+
+// checkUserVMApproval은 UserVM에 표시된 승인이 이 사용자의 같은 VM 요청을 승인한 것인지 확인합니다.
+// 사용자가 어노테이션을 직접 붙여 승인을 건너뛰지 못하도록 DB의 승인 기록과 대조합니다.
+func (s *VmLifecycleService) checkUserVMApproval(id uint, user *models.User, req CreateParams) error {
+	approval, err := s.approvalService.FetchApproval(id)
+	if err != nil {
+		return fmt.Errorf("approval %d not found", id)
+	}
+	if approval.UserID != user.ID || approval.VmName != req.VmName || approval.VmFlavor != req.VmFlavor {
+		return fmt.Errorf("approval %d was not granted for this VM", id)
+	}
+	if approval.Status != models.ApprovalStatusApproved {
+		return fmt.Errorf("approval %d is %s", id, approval.Status)
+	}
+	return nil
+}
+
+// reviewedUserVMApproval은 이 UserVM이 만들어진 뒤 요청되어 거절되었거나 생성에 실패한 승인 요청을 찾습니다.
+func (s *VmLifecycleService) reviewedUserVMApproval(user *models.User, spec *k8s_service.UserVMSpec) (*models.VmApproval, error) {
+	approvals, err := s.approvalService.FetchUserApprovals(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range approvals {
+		approval := &approvals[i]
+		if approval.VmName != spec.Name || approval.CreatedAt.Before(spec.CreatedAt) {
+			continue
+		}
+		if approval.Status == models.ApprovalStatusRejected || approval.Status == models.ApprovalStatusFailed {
+			return approval, nil
+		}
+		// 최신 요청이 대기 중이거나 승인됨
+		return nil, nil
+	}
+	return nil, nil
+}
+
+// userVMFailure는 생성 실패를 UserVM status.phase로 바꿉니다.
+// spec을 고쳐야 하는 실패(검증, 쿼터, 이름 충돌)는 Rejected로 두고 다시 시도하지 않으며, 대기 중인 승인 요청이 있으면 승인을 기다립니다.
+func userVMFailure(err error) (phase string, retry bool) {
+	if errors.Is(err, approvalservice.ErrApprovalExists) {
+		return k8s_service.UserVMPhasePendingApproval, false
+	}
+	switch KindOf(err) {
+	case KindInvalid, KindForbidden, KindConflict, KindNotFound:
+		return k8s_service.UserVMPhaseRejected, false
+	}
+	return k8s_service.UserVMPhasePending, true
+}
@@ -0,0 +1,306 @@
+package vmlifecycleservice
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/config"
+	"vm-controller/internal/logger"
+	"vm-controller/internal/models"
+	"vm-controller/internal/policy"
+	approvalservice "vm-controller/internal/services/approval_service"
+	bundleservice "vm-controller/internal/services/bundle_service"
+	flavorservice "vm-controller/internal/services/flavor_service"
+	imageservice "vm-controller/internal/services/image_service"
+	k8s_service "vm-controller/internal/services/k8s_service"
+	networkservice "vm-controller/internal/services/network_service"
+	operationservice "vm-controller/internal/services/operation_service"
+	passwordservice "vm-controller/internal/services/password_service"
+	preferenceservice "vm-controller/internal/services/preference_service"
+	quotaservice "vm-controller/internal/services/quota_service"
+	sshkeyservice "vm-controller/internal/services/ssh_key_service"
+	userservice "vm-controller/internal/services/user_service"
+	vmeventservice "vm-controller/internal/services/vm_event_service"
+	vm_service "vm-controller/internal/services/vm_service"
+	"vm-controller/internal/vmstate"
+)
+
+// VmLifecycleService는 VM 생성과 시작/정지/재시작/삭제 요청을 처리합니다.
+// REST 컨트롤러와 gRPC 서버가 같은 검증/권한/상태 전이 규칙을 쓰도록 전송 계층과 분리되어 있으며,
+// 실패는 *Error(유형 + 메시지)로 반환하여 호출자가 각자의 상태 코드로 바꿉니다.
+type VmLifecycleService struct {
+	provisioner       k8s_service.K8sProvisioner
+	approvalService   *approvalservice.ApprovalService
+	bundleService     *bundleservice.BundleService
+	flavorService     *flavorservice.FlavorService
+	imageService      *imageservice.ImageService
+	networkService    *networkservice.NetworkService
+	operationService  *operationservice.OperationService
+	passwordService   *passwordservice.PasswordService
+	preferenceService *preferenceservice.PreferenceService
+	quotaService      *quotaservice.QuotaService
+	sshKeyService     *sshkeyservice.SSHKeyService
+	userService       *userservice.UserService
+	vmEventService    *vmeventservice.VmEventService
+	vmService         *vm_service.VmService
+
+	// backpressure는 작업 큐 과부하 여부입니다. (k8s_service.GetBackpressure)
+	backpressure func() k8s_service.BackpressureStatus
+
+	ctx context.Context // WithContext로 묶은 요청 컨텍스트 (기본 인스턴스는 nil)
+}
+
+// Dependencies는 VmLifecycleService가 요청 검증, 쿼터/승인 확인, VM 기록에 쓰는 서비스입니다.
+type Dependencies struct {
+	ApprovalService   *approvalservice.ApprovalService
+	BundleService     *bundleservice.BundleService
+	FlavorService     *flavorservice.FlavorService
+	ImageService      *imageservice.ImageService
+	NetworkService    *networkservice.NetworkService
+	OperationService  *operationservice.OperationService
+	PasswordService   *passwordservice.PasswordService
+	PreferenceService *preferenceservice.PreferenceService
+	QuotaService      *quotaservice.QuotaService
+	SSHKeyService     *sshkeyservice.SSHKeyService
+	UserService       *userservice.UserService
+	VmEventService    *vmeventservice.VmEventService
+	VmService         *vm_service.VmService
+}
+
+func NewVmLifecycleService(provisioner k8s_service.K8sProvisioner, deps Dependencies) *VmLifecycleService {
+	return &VmLifecycleService{
+		provisioner:       provisioner,
+		approvalService:   deps.ApprovalService,
+		bundleService:     deps.BundleService,
+		flavorService:     deps.FlavorService,
+		imageService:      deps.ImageService,
+		networkService:    deps.NetworkService,
+		operationService:  deps.OperationService,
+		passwordService:   deps.PasswordService,
+		preferenceService: deps.PreferenceService,
+		quotaService:      deps.QuotaService,
+		sshKeyService:     deps.SSHKeyService,
+		userService:       deps.UserService,
+		vmEventService:    deps.VmEventService,
+		vmService:         deps.VmService,
+		backpressure:      k8s_service.GetBackpressure,
+	}
+}
+
+// WithContext는 DB 쿼리와 K8s 요청이 ctx를 따르는 서비스를 반환합니다.
+// 백그라운드 작업(RunAsync)은 요청이 끝나도 계속 실행되므로 ctx의 영향을 받지 않습니다.
+func (s *VmLifecycleService) WithContext(ctx context.Context) *VmLifecycleService {
+	scoped := *s
+	scoped.ctx = ctx
+	return &scoped
+}
+
+func (s *VmLifecycleService) vms() *vm_service.VmService {
+	if s.ctx == nil {
+		return s.vmService
+	}
+	return s.vmService.WithContext(s.ctx)
+}
+
+func (s *VmLifecycleService) k8s() k8s_service.K8sProvisioner {
+	if s.ctx == nil {
+		return s.provisioner
+	}
+	return s.provisioner.ProvisionerWithContext(s.ctx)
+}
+
+// async는 백그라운드 작업을 시작할 Provisioner입니다. 작업은 요청 ID만 물려받고 요청의 취소는 따르지 않습니다.
+func (s *VmLifecycleService) async() k8s_service.K8sProvisioner {
+	if s.ctx == nil {
+		return s.provisioner
+	}
+	return s.provisioner.ProvisionerWithRequest(s.ctx)
+}
+
+// log는 요청 ID와 trace id가 붙은 로거입니다.
+func (s *VmLifecycleService) log() *slog.Logger {
+	return logger.FromContext(s.ctx)
+}
+
+// FetchOwned는 subject가 action을 할 수 있는 VM을 조회합니다. 없거나 권한이 없으면 ErrVMNotFound를 반환합니다.
+func (s *VmLifecycleService) FetchOwned(subject policy.Subject, action policy.Action, vmName string) (*models.VirtualMachine, error) {
+	vm, err := s.vms().FetchVmName(vmName, false)
+	if err != nil {
+		return nil, newError(KindInternal, "Failed to fetch VM", err)
+	}
+	if vm == nil || policy.Authorize(subject, action, policy.Resource{Type: policy.ResourceVM, OwnerID: &vm.UserID}) != nil {
+		return nil, ErrVMNotFound
+	}
+	return vm, nil
+}
+
+// checkBackpressure는 작업 큐가 과부하이면 새 작업을 만드는 요청(생성/시작/재시작)을 KindBusy로 거부합니다.
+// REST는 Backpressure 미들웨어가 더 일찍 거부하지만, gRPC 등 다른 호출 경로도 같은 기준을 따르도록 서비스에서 확인합니다.
+func (s *VmLifecycleService) checkBackpressure() error {
+	status := s.backpressure()
+	if !status.Overloaded {
+		return nil
+	}
+	return &Error{
+		Kind:       KindBusy,
+		Code:       apperrors.CodeServerBusy,
+		Message:    "Server is busy, please retry later",
+		RetryAfter: status.RetryAfter,
+		Reason:     status.Reason,
+	}
+}
+
+func illegalStateError(vm *models.VirtualMachine) *Error {
+	return newError(KindConflict, "현재 상태("+string(vm.Status)+")에서는 요청을 처리할 수 없습니다.", nil).withCode(apperrors.CodeInvalidState)
+}
+
+// Stop은 VM의 목표 상태를 Stopped로 저장하고 정지 작업을 시작합니다. 작업 ID를 함께 반환합니다. (Operator 모드는 0)
+func (s *VmLifecycleService) Stop(subject policy.Subject, vmName, traceID string) (*models.VirtualMachine, uint, error) {
+	vm, err := s.FetchOwned(subject, policy.ActionWrite, vmName)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	operationID, err := s.stop(vm, subject.UserID, traceID)
+	return vm, operationID, err
+}
+
+// StopAsAdmin은 관리자 조치(신고 처리 등)로 소유자와 관계없이 VM을 정지합니다.
+func (s *VmLifecycleService) StopAsAdmin(actorID uint, vmName, traceID string) (*models.VirtualMachine, uint, error) {
+	vm, err := s.vms().FetchVmName(vmName, false)
+	if err != nil {
+		return nil, 0, newError(KindInternal, "Failed to fetch VM", err)
+	}
+	if vm == nil {
+		return nil, 0, ErrVMNotFound
+	}
+
+	operationID, err := s.stop(vm, actorID, traceID)
+	return vm, operationID, err
+}
+
+func (s *VmLifecycleService) stop(vm *models.VirtualMachine, actorID uint, traceID string) (uint, error) {
+	// 현재 상태에서 허용되지 않는 요청은 거부 (예: Provisioning 중 정지)
+	if !vmstate.CanTransition(vm.Status, models.VmStatusStopping) {
+		return 0, illegalStateError(vm)
+	}
+
+	s.vmEventService.RecordOperation(vm.Name, "stop", actorID)
+
+	// Operator 모드: UserVM spec만 변경 (reconcile 컨트롤러가 목표 상태를 반영)
+	if config.Get().OperatorMode {
+		if err := s.k8s().SetUserVMRunning(vm.Namespace, vm.Name, false); err != nil {
+			return 0, newError(KindInternal, "Failed to update VM", err)
+		}
+		return 0, nil
+	}
+
+	// 목표 상태를 먼저 저장 (작업이 중단되어도 converger가 이어서 수렴시킴)
+	if err := s.vms().SetDesiredState(vm.Name, models.VmDesiredStopped); err != nil {
+		return 0, newError(KindInternal, "Failed to update VM", err)
+	}
+	return s.async().StopVMAsync(vm, traceID), nil
+}
+
+// Start는 VM의 목표 상태를 Running으로 저장하고 시작 작업을 시작합니다. 사용 기간이 만료된 VM은 연장 후에만 시작할 수 있습니다.
+func (s *VmLifecycleService) Start(subject policy.Subject, vmName, traceID string) (*models.VirtualMachine, uint, error) {
+	if err := s.checkBackpressure(); err != nil {
+		return nil, 0, err
+	}
+
+	vm, err := s.FetchOwned(subject, policy.ActionWrite, vmName)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if !vmstate.CanTransition(vm.Status, models.VmStatusRunning) {
+		return vm, 0, illegalStateError(vm)
+	}
+	// 만료되어 정지된 VM은 연장(POST /api/vm/:name/extend)한 뒤에만 시작 가능
+	if vm.ExpiresAt != nil && !vm.ExpiresAt.After(time.Now()) {
+		return vm, 0, newError(KindConflict, "VM 사용 기간이 만료되었습니다. 연장한 뒤 다시 시작하세요.", nil).withCode(apperrors.CodeInvalidState)
+	}
+
+	s.vmEventService.RecordOperation(vm.Name, "start", subject.UserID)
+
+	if config.Get().OperatorMode {
+		if err := s.k8s().SetUserVMRunning(vm.Namespace, vm.Name, true); err != nil {
+			return vm, 0, newError(KindInternal, "Failed to update VM", err)
+		}
+		return vm, 0, nil
+	}
+
+	if err := s.vms().SetDesiredState(vm.Name, models.VmDesiredRunning); err != nil {
+		return vm, 0, newError(KindInternal, "Failed to update VM", err)
+	}
+	return vm, s.async().StartVMAsync(vm, traceID), nil
+}
+
+// Delete는 VM 삭제를 요청합니다. 실수로 인한 삭제를 막기 위해 confirm(VM 이름 또는 확인 토큰)이 필요하며,
+// force 없이는 실행 중(Running, Paused)인 VM을 삭제하지 않습니다.
+func (s *VmLifecycleService) Delete(subject policy.Subject, vmName, confirm string, force bool, traceID string) (*models.VirtualMachine, uint, error) {
+	vm, err := s.FetchOwned(subject, policy.ActionWrite, vmName)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if !confirmDelete(subject.UserID, vm.Name, confirm) {
+		return vm, 0, newError(KindInvalid, "confirm must be the VM name or a valid confirmation token", nil)
+	}
+	if !force && (vm.Status == models.VmStatusRunning || vm.Status == models.VmStatusPaused) {
+		return vm, 0, newError(KindConflict, "VM is running. Stop it first or set force to true", nil).withCode(apperrors.CodeInvalidState)
+	}
+
+	s.vmEventService.RecordOperation(vm.Name, "delete", subject.UserID)
+
+	if config.Get().OperatorMode {
+		if err := s.k8s().DeleteUserVM(vm.Namespace, vm.Name); err != nil {
+			return vm, 0, newError(KindInternal, "Failed to update VM", err)
+		}
+		return vm, 0, nil
+	}
+
+	if err := s.vms().SetDesiredState(vm.Name, models.VmDesiredDeleted); err != nil {
+		return vm, 0, newError(KindInternal, "Failed to update VM", err)
+	}
+	return vm, s.async().DeleteVMAsync(vm, traceID), nil
+}
+
+// Restart는 VM을 재부팅하고 Running으로 돌아올 때까지(최대 VM_RESTART_TIMEOUT) 기다립니다.
+// 제한 시간을 넘기면 KindTimeout을 반환하며, 재부팅은 계속 진행되고 상태는 converger가 반영합니다.
+func (s *VmLifecycleService) Restart(subject policy.Subject, vmName string) (*models.VirtualMachine, error) {
+	if err := s.checkBackpressure(); err != nil {
+		return nil, err
+	}
+
+	vm, err := s.FetchOwned(subject, policy.ActionWrite, vmName)
+	if err != nil {
+		return nil, err
+	}
+
+	// 현재 상태에서 허용되지 않는 요청은 거부 (예: 정지된 VM 재시작)
+	if !vmstate.CanTransition(vm.Status, models.VmStatusRestarting) {
+		return vm, illegalStateError(vm)
+	}
+
+	s.vmEventService.RecordOperation(vm.Name, "restart", subject.UserID)
+
+	if err := s.k8s().RestartVM(vm, k8s_service.RestartTimeout()); err != nil {
+		if errors.Is(err, k8s_service.ErrRestartTimeout) {
+			return vm, newError(KindTimeout, "VM did not report Running in time, it is still restarting", err)
+		}
+
+		var illegal *vmstate.IllegalTransitionError
+		if errors.As(err, &illegal) {
+			return vm, newError(KindConflict, err.Error(), err)
+		}
+
+		s.log().Error("failed to restart vm", "vm", vm.Name, "error", err.Error())
+		return vm, newError(KindInternal, "Failed to restart VM", err)
+	}
+
+	vm.Status = models.VmStatusRunning
+	return vm, nil
+}
@@ -0,0 +1,132 @@
+package vmlifecycleservice
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/models"
+	"vm-controller/internal/policy"
+	approvalservice "vm-controller/internal/services/approval_service"
+	bundleservice "vm-controller/internal/services/bundle_service"
+	k8s_service "vm-controller/internal/services/k8s_service"
+)
+
+// 작업 큐가 과부하이면 생성/시작/재시작은 DB나 클러스터를 건드리기 전에 KindBusy로 거부되어야 함 (gRPC 경로 포함)
+func TestBackpressureRejectsNewWork(t *testing.T) {
+	s := &VmLifecycleService{
+		backpressure: func() k8s_service.BackpressureStatus {
+			return k8s_service.BackpressureStatus{Overloaded: true, Reason: "queue_depth", RetryAfter: 30 * time.Second}
+		},
+	}
+	subject := policy.Subject{UserID: 1}
+
+	_, createErr := s.Create(nil, CreateParams{}, "", "")
+	_, _, startErr := s.Start(subject, "vm", "")
+	_, restartErr := s.Restart(subject, "vm")
+
+	for name, err := range map[string]error{"create": createErr, "start": startErr, "restart": restartErr} {
+		if KindOf(err) != KindBusy {
+			t.Errorf("%s: kind = %v, want KindBusy (err = %v)", name, KindOf(err), err)
+			continue
+		}
+		if CodeOf(err) != apperrors.CodeServerBusy {
+			t.Errorf("%s: code = %s, want %s", name, CodeOf(err), apperrors.CodeServerBusy)
+		}
+		if retryAfter, reason := RetryAfterOf(err); retryAfter != 30*time.Second || reason != "queue_depth" {
+			t.Errorf("%s: retry after = %v, reason = %q", name, retryAfter, reason)
+		}
+	}
+}
+
+// UserVM spec을 고쳐야 하는 실패는 Rejected로 두고 다시 시도하지 않으며, 일시적인 실패만 다시 시도해야 함
+func TestUserVMFailure(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		phase string
+		retry bool
+	}{
+		{"pending approval", newError(KindConflict, "pending", approvalservice.ErrApprovalExists), k8s_service.UserVMPhasePendingApproval, false},
+		{"invalid spec", newError(KindInvalid, "invalid flavor", nil), k8s_service.UserVMPhaseRejected, false},
+		{"quota", newError(KindForbidden, "quota exceeded", nil), k8s_service.UserVMPhaseRejected, false},
+		{"name taken", newError(KindConflict, "name taken", nil).withCode(apperrors.CodeNameTaken), k8s_service.UserVMPhaseRejected, false},
+		{"busy", newError(KindBusy, "busy", nil), k8s_service.UserVMPhasePending, true},
+		{"internal", errors.New("db down"), k8s_service.UserVMPhasePending, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			phase, retry := userVMFailure(tt.err)
+			if phase != tt.phase || retry != tt.retry {
+				t.Errorf("userVMFailure = %s, %v; want %s, %v", phase, retry, tt.phase, tt.retry)
+			}
+		})
+	}
+}
+
+func TestValidateOperatorParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     CreateParams
+		wantErr bool
+	}{
+		{"plain", CreateParams{VmName: "lab", SSHKeyIDs: []uint{}}, false},
+		{"ssh keys", CreateParams{SSHKeyIDs: []uint{1}}, true},
+		{"cloud-init", CreateParams{CloudInit: "#cloud-config"}, true},
+		{"networks", CreateParams{Networks: []NetworkParams{{Network: "lab-net"}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateOperatorParams(tt.req); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateOperatorParams = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// 정지/삭제는 목표 상태를 먼저 저장하고 provisioner의 백그라운드 작업으로 클러스터에 반영해야 함
+func TestStopAndDelete(t *testing.T) {
+	s, provisioner, conn := newTestService(t)
+	user := newTestUser(t, conn)
+	if _, err := s.Create(user, testCreateParams("lab-1"), bundleservice.VMTemplate, ""); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	owner := policy.Subject{UserID: user.ID}
+
+	// 다른 사용자의 VM은 없는 것으로 응답
+	if _, _, err := s.Stop(policy.Subject{UserID: user.ID + 1}, "lab-1", ""); !errors.Is(err, ErrVMNotFound) {
+		t.Fatalf("Stop by another user: err = %v, want ErrVMNotFound", err)
+	}
+
+	vm, operationID, err := s.Stop(owner, "lab-1", "")
+	if err != nil || operationID == 0 {
+		t.Fatalf("Stop = %d, %v; want an operation", operationID, err)
+	}
+	if provisioner.VMs[vm.Namespace+"/lab-1"].Running {
+		t.Error("cluster VM is still running after Stop")
+	}
+	if stored, _ := s.vms().FetchVmName("lab-1", false); stored.DesiredState != models.VmDesiredStopped {
+		t.Errorf("desired state = %s, want %s", stored.DesiredState, models.VmDesiredStopped)
+	}
+
+	// 확인 값이 다르거나, 실행 중(상태는 작업이 완료되어야 바뀜)인데 force가 없으면 거부
+	if _, _, err := s.Delete(owner, "lab-1", "lab-2", true, ""); KindOf(err) != KindInvalid {
+		t.Errorf("Delete with wrong confirm: kind = %v, want KindInvalid", KindOf(err))
+	}
+	if _, _, err := s.Delete(owner, "lab-1", "lab-1", false, ""); CodeOf(err) != apperrors.CodeInvalidState {
+		t.Errorf("Delete running VM without force: code = %s, want %s", CodeOf(err), apperrors.CodeInvalidState)
+	}
+
+	if _, _, err := s.Delete(owner, "lab-1", "lab-1", true, ""); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, ok := provisioner.VMs[vm.Namespace+"/lab-1"]; ok {
+		t.Error("cluster VM was kept after Delete")
+	}
+	if stored, _ := s.vms().FetchVmName("lab-1", false); stored != nil && stored.DesiredState != models.VmDesiredDeleted {
+		t.Errorf("desired state = %s, want %s", stored.DesiredState, models.VmDesiredDeleted)
+	}
+}
@@ -0,0 +1,101 @@
+package vmservice
+
+import (
+	"errors"
+	"os"
+	"time"
+	"vm-controller/internal/models"
+
+	"github.com/spf13/cast"
+)
+
+// 만료 후 삭제까지의 기본 유예 기간 (정지된 상태로 보관)
+const defaultExpiryDeleteAfterDays = 14
+
+var (
+	ErrNoLease       = errors.New("VM has no expiry")
+	ErrLeaseTooLong  = errors.New("requested extension exceeds the lease policy")
+	ErrLeaseDisabled = errors.New("lease extension is not available for this user")
+)
+
+// LeaseDays는 사용자에게 적용되는 VM 사용 기간(일)을 반환합니다. 0이면 만료 없음.
+// 사용자별 값(User.VmLeaseDays)이 없으면 VM_LEASE_DAYS 환경 변수를 사용합니다. (기본 0)
+func (vmService *VmService) LeaseDays(user *models.User) int {
+	if user.VmLeaseDays != nil {
+		return max(*user.VmLeaseDays, 0)
+	}
+	return max(cast.ToInt(os.Getenv("VM_LEASE_DAYS")), 0)
+}
+
+// LeaseExpiry는 지금 생성하는 VM의 만료 시각을 반환합니다. 만료 정책이 없으면 nil.
+func (vmService *VmService) LeaseExpiry(user *models.User) *time.Time {
+	days := vmService.LeaseDays(user)
+	if days == 0 {
+		return nil
+	}
+
+	expiresAt := time.Now().AddDate(0, 0, days)
+	return &expiresAt
+}
+
+// ExpiryDeleteAfter는 만료된 VM을 정지 상태로 보관하는 기간입니다. (VM_EXPIRY_DELETE_AFTER_DAYS, 기본 14일)
+func (vmService *VmService) ExpiryDeleteAfter() time.Duration {
+	days := defaultExpiryDeleteAfterDays
+	if value := os.Getenv("VM_EXPIRY_DELETE_AFTER_DAYS"); value != "" {
+		if parsed, err := cast.ToIntE(value); err == nil && parsed >= 0 {
+			days = parsed
+		}
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// ExtendLease는 VM의 만료 시각을 days일 연장합니다.
+// 만료된 VM은 지금부터 연장하며, 연장 후 만료 시각이 지금부터 사용자 사용 기간을 넘을 수 없습니다. (학기 단위 정책 우회 방지)
+func (vmService *VmService) ExtendLease(vm *models.VirtualMachine, user *models.User, days int) (*time.Time, error) {
+	defer vmService.invalidateReads()
+
+	db := vmService.getDB()
+
+	if vm.ExpiresAt == nil {
+		return nil, ErrNoLease
+	}
+	leaseDays := vmService.LeaseDays(user)
+	if leaseDays == 0 {
+		return nil, ErrLeaseDisabled
+	}
+
+	now := time.Now()
+	base := *vm.ExpiresAt
+	if base.Before(now) {
+		base = now
+	}
+
+	expiresAt := base.AddDate(0, 0, days)
+	if expiresAt.After(now.AddDate(0, 0, leaseDays)) {
+		return nil, ErrLeaseTooLong
+	}
+
+	if err := db.Model(&models.VirtualMachine{}).Where("name = ? AND is_deleted = false", vm.Name).Update("expires_at", expiresAt).Error; err != nil {
+		return nil, err
+	}
+
+	vm.ExpiresAt = &expiresAt
+	return &expiresAt, nil
+}
+
+// FetchExpiredVMs는 만료 시각이 지난 (삭제되지 않은) VM 목록을 반환합니다.
+func (vmService *VmService) FetchExpiredVMs(now time.Time) ([]models.VirtualMachine, error) {
+	db := vmService.getDB()
+
+	var vms []models.VirtualMachine
+
+	if err := db.Where("is_deleted = false AND expires_at IS NOT NULL AND expires_at <= ?", now).Order("expires_at").Find(&vms).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range vms {
+		vms[i].Password = ""
+	}
+
+	return vms, nil
+}
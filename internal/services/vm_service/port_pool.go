@@ -0,0 +1,35 @@
+package vmservice
+
+import (
+	"vm-controller/internal/db"
+	"vm-controller/internal/metrics"
+	"vm-controller/internal/models"
+)
+
+// VM SSH 접속에 할당하는 NodePort 범위
+const (
+	PortRangeStart = 30003
+	PortRangeEnd   = 30300
+)
+
+// PortPoolSize는 할당 가능한 NodePort 개수입니다.
+const PortPoolSize = PortRangeEnd - PortRangeStart + 1
+
+func init() {
+	metrics.NewGaugeFunc("vm_port_pool_size", "NodePorts available for VM SSH access.", func() float64 { return PortPoolSize })
+	// 스크랩 시점에 사용 중인 포트 수를 조회 (DB 미초기화 시 0)
+	metrics.NewGaugeFunc("vm_port_pool_used", "NodePorts currently assigned to non-deleted VMs.", func() float64 {
+		conn := db.Current()
+		if conn == nil {
+			return 0
+		}
+		var used int64
+		if err := conn.Model(&models.VirtualMachine{}).
+			Where("is_deleted = ? AND node_port BETWEEN ? AND ?", false, PortRangeStart, PortRangeEnd).
+			Distinct("node_port").
+			Count(&used).Error; err != nil {
+			return 0
+		}
+		return float64(used)
+	})
+}
@@ -0,0 +1,60 @@
+package vmservice
+
+import (
+	"sync"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/models"
+)
+
+var ErrVmNameTaken = apperrors.New(apperrors.CodeNameTaken, "vm name is already in use")
+
+// NodePort를 고른 뒤 레코드를 저장하기 전에 다른 생성 요청이 같은 포트를 고르지 않도록 직렬화
+var reserveMu sync.Mutex
+
+// ReserveUserVM은 사용 가능한 NodePort를 할당하고 VM 레코드를 Provisioning 상태로 생성합니다.
+// 클러스터 리소스를 만들기 전에 호출하여 이름과 포트를 선점하며, params.VmSSHPort 대신 할당한 포트를 사용합니다.
+// 삭제된 VM을 포함하여 같은 이름의 레코드가 있으면 ErrVmNameTaken을 반환합니다.
+func (vmService *VmService) ReserveUserVM(params CreateVmParams) (*models.VirtualMachine, error) {
+	reserveMu.Lock()
+	defer reserveMu.Unlock()
+
+	var count int64
+	if err := vmService.getDB().Unscoped().Model(&models.VirtualMachine{}).Where("name = ?", params.VmName).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, ErrVmNameTaken
+	}
+
+	port, err := vmService.GetAvailablePort()
+	if err != nil {
+		return nil, err
+	}
+	params.VmSSHPort = int32(port)
+
+	return vmService.CreateUserVM(params)
+}
+
+// CompleteUserVM은 클러스터 리소스 생성이 끝난 VM의 MAC 주소를 기록하고 Running으로 전이합니다.
+func (vmService *VmService) CompleteUserVM(vm *models.VirtualMachine, macAddress string) error {
+	defer vmService.invalidateReads()
+
+	if err := vmService.UpdateVmMacAddress(vm.Name, macAddress); err != nil {
+		return err
+	}
+	if err := vmService.UpdateVmStatus(vm.Name, models.VmStatusRunning); err != nil {
+		return err
+	}
+
+	vm.MacAddress = macAddress
+	vm.Status = models.VmStatusRunning
+	return nil
+}
+
+// DiscardUserVM은 생성에 실패한 VM 레코드를 영구 삭제하여 이름과 NodePort를 반환합니다.
+// ReserveUserVM으로 만든 레코드를 롤백할 때만 사용합니다.
+func (vmService *VmService) DiscardUserVM(vm *models.VirtualMachine) error {
+	defer vmService.invalidateReads()
+
+	return vmService.getDB().Unscoped().Delete(&models.VirtualMachine{}, vm.ID).Error
+}
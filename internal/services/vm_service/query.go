@@ -0,0 +1,142 @@
+package vmservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"vm-controller/internal/models"
+	"vm-controller/internal/vmstate"
+)
+
+// 한 페이지에 반환할 수 있는 최대 VM 수
+const MaxVmPageLimit = 100
+
+// 정렬 가능한 필드와 컬럼 (앞에 '-'를 붙이면 내림차순)
+var vmSortColumns = map[string]string{
+	"name":       "name",
+	"status":     "status",
+	"flavor":     "flavor",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"expires_at": "expires_at",
+}
+
+// VmQuery는 VM 목록 조회 조건입니다. 사용자 목록(GET /api/vm/fetch)과 관리자 목록이 같은 조건을 사용합니다.
+type VmQuery struct {
+	UserID   *uint                 // nil이면 모든 사용자 (관리자 목록)
+	Statuses []models.EnumVmStatus // 비어 있으면 모든 상태
+	Search   string                // 이름 부분 일치 (대소문자 무시)
+	Sort     string                // vmSortColumns의 키, 앞에 '-'면 내림차순 (기본 id 순)
+	Page     int                   // 1부터 시작 (Limit이 0이면 무시)
+	Limit    int                   // 0이면 전체 반환
+
+	WithOwner bool // 소유자 정보 포함 (관리자 목록)
+}
+
+// VmPage는 조회 조건에 맞는 VM 한 페이지와 전체 개수입니다.
+type VmPage struct {
+	VMs   []models.VirtualMachine
+	Total int64
+	Page  int
+	Limit int
+}
+
+// Validate는 조회 조건을 검사하고 기본값을 채웁니다.
+func (q *VmQuery) Validate() error {
+	if q.Limit < 0 || q.Limit > MaxVmPageLimit {
+		return fmt.Errorf("limit must be between 1 and %d", MaxVmPageLimit)
+	}
+	if q.Page < 0 {
+		return fmt.Errorf("page must be 1 or greater")
+	}
+	if q.Page == 0 {
+		q.Page = 1
+	}
+	if q.Sort != "" {
+		if _, ok := vmSortColumns[strings.TrimPrefix(q.Sort, "-")]; !ok {
+			return fmt.Errorf("unknown sort field %q", strings.TrimPrefix(q.Sort, "-"))
+		}
+	}
+	for _, status := range q.Statuses {
+		if !vmstate.IsValid(status) {
+			return fmt.Errorf("unknown status %q", status)
+		}
+	}
+	return nil
+}
+
+// invalidateReads는 VM 레코드를 바꾼 뒤 캐시된 목록 조회 결과를 버립니다.
+func (vmService *VmService) invalidateReads() {
+	vmService.reads.Purge()
+}
+
+// QueryVMs는 삭제되지 않은 VM을 조건에 맞게 조회합니다. 비밀번호는 항상 제외합니다.
+// 같은 조건의 동시 조회는 한 번만 실행되고 결과는 READ_COALESCE_TTL 동안 공유되므로, 반환 값을 수정하면 안 됩니다.
+func (vmService *VmService) QueryVMs(q VmQuery) (*VmPage, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+
+	key, err := json.Marshal(q)
+	if err != nil {
+		return nil, err
+	}
+	// 먼저 들어온 요청이 취소되어도 합류한 요청은 결과를 받도록 취소와 분리
+	detached := vmService
+	if vmService.ctx != nil {
+		detached = vmService.WithContext(context.WithoutCancel(vmService.ctx))
+	}
+	return vmService.reads.Do(string(key), func() (*VmPage, error) {
+		return detached.queryVMs(q)
+	})
+}
+
+func (vmService *VmService) queryVMs(q VmQuery) (*VmPage, error) {
+	db := vmService.getDB()
+
+	query := db.Model(&models.VirtualMachine{}).Where("is_deleted = false")
+	if q.UserID != nil {
+		query = query.Where("user_id = ?", *q.UserID)
+	}
+	if len(q.Statuses) > 0 {
+		query = query.Where("status IN ?", q.Statuses)
+	}
+	if search := strings.TrimSpace(q.Search); search != "" {
+		// LIKE 와일드카드는 문자 그대로 검색
+		escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(strings.ToLower(search))
+		query = query.Where("LOWER(name) LIKE ?", "%"+escaped+"%")
+	}
+
+	page := &VmPage{Page: q.Page, Limit: q.Limit}
+	if err := query.Count(&page.Total).Error; err != nil {
+		return nil, err
+	}
+
+	if q.Sort != "" {
+		direction := "ASC"
+		if strings.HasPrefix(q.Sort, "-") {
+			direction = "DESC"
+		}
+		query = query.Order(vmSortColumns[strings.TrimPrefix(q.Sort, "-")] + " " + direction)
+	}
+	query = query.Order("id")
+
+	if q.Limit > 0 {
+		query = query.Offset((q.Page - 1) * q.Limit).Limit(q.Limit)
+	}
+	if q.WithOwner {
+		query = query.Preload("User")
+	}
+
+	if err := query.Find(&page.VMs).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range page.VMs {
+		page.VMs[i].Password = ""
+		page.VMs[i].User.PasswordHash = ""
+	}
+
+	return page, nil
+}
@@ -1,33 +1,56 @@
 package vmservice
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"sync"
+	"time"
+	"vm-controller/internal/apperrors"
+	"vm-controller/internal/coalesce"
 	"vm-controller/internal/db"
 	"vm-controller/internal/models"
+	"vm-controller/internal/vmstate"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type VmService struct {
+	// 같은 조건의 동시 목록 조회를 합치고 짧게 캐시 (여러 탭/대시보드의 폴링)
+	reads *coalesce.Group[*VmPage]
+
+	ctx context.Context // WithContext로 묶은 요청 컨텍스트 (기본 인스턴스는 nil)
 }
 
-var (
-	vmService *VmService
-	once      sync.Once
-)
+var ErrCredentialsRevealed = errors.New("credentials have already been revealed")
 
-func GetVmService() *VmService {
-	once.Do(func() {
-		vmService = &VmService{}
-	})
+// ErrPortsExhausted는 NodePort 범위의 포트가 모두 사용 중일 때 반환됩니다.
+var ErrPortsExhausted = apperrors.New(apperrors.CodePortExhausted, "no available ports")
 
+func NewVmService() *VmService {
+	vmService := &VmService{reads: coalesce.New[*VmPage]("vm_query")}
+	// 다른 서비스가 바꾼 상태도 바로 보이도록 상태 전이 시 캐시를 비움
+	vmstate.OnTransition(func(vmstate.Transition) { vmService.reads.Purge() })
 	return vmService
 }
 
+// WithContext는 쿼리가 ctx를 따르는 VmService를 반환합니다. 요청이 취소되면 진행 중인 쿼리도 중단됩니다.
+func (vmService *VmService) WithContext(ctx context.Context) *VmService {
+	scoped := *vmService
+	scoped.ctx = ctx
+	return &scoped
+}
+
+// getDB는 WithContext로 묶인 컨텍스트가 있으면 그 컨텍스트를 사용하는 DB 핸들을 반환합니다.
+func (vmService *VmService) getDB() *gorm.DB {
+	if vmService.ctx == nil {
+		return db.GetDB()
+	}
+	return db.GetDB().WithContext(vmService.ctx)
+}
+
 func (vmService *VmService) FetchUserVMs(userId string, containPassword bool) ([]models.VirtualMachine, error) {
-	db := db.GetDB()
+	db := vmService.getDB()
 
 	var vms []models.VirtualMachine
 
@@ -45,8 +68,44 @@ func (vmService *VmService) FetchUserVMs(userId string, containPassword bool) ([
 	return vms, nil
 }
 
+// FetchAllVMs는 관리자용으로 모든 사용자의 VM을 소유자 정보와 함께 반환합니다.
+func (vmService *VmService) FetchAllVMs(containPassword bool) ([]models.VirtualMachine, error) {
+	db := vmService.getDB()
+
+	var vms []models.VirtualMachine
+
+	if err := db.Preload("User").Where("is_deleted = false").Order("id").Find(&vms).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range vms {
+		vms[i].User.PasswordHash = ""
+		if !containPassword {
+			vms[i].Password = ""
+		}
+	}
+
+	return vms, nil
+}
+
+// EachVMBatch는 삭제되지 않은 VM을 id 순서로 batchSize개씩 읽어 fn에 넘깁니다. (전체 목록을 메모리에 올리지 않음)
+// 비밀번호는 항상 비웁니다. fn이 에러를 반환하면 중단하고 그 에러를 반환합니다.
+func (vmService *VmService) EachVMBatch(batchSize int, fn func(vms []models.VirtualMachine) error) error {
+	db := vmService.getDB()
+
+	var batch []models.VirtualMachine
+	return db.Preload("User").Where("is_deleted = false").Order("id").
+		FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+			for i := range batch {
+				batch[i].User.PasswordHash = ""
+				batch[i].Password = ""
+			}
+			return fn(batch)
+		}).Error
+}
+
 func (vmService *VmService) FetchVmName(vmName string, containPassword bool) (*models.VirtualMachine, error) {
-	db := db.GetDB()
+	db := vmService.getDB()
 
 	var vm models.VirtualMachine
 
@@ -67,8 +126,27 @@ func (vmService *VmService) FetchVmName(vmName string, containPassword bool) (*m
 	return &vm, nil
 }
 
+// FetchVmNameIncludingDeleted는 삭제된 VM까지 포함하여 조회합니다. (관리자 진단용, 비밀번호 제외)
+func (vmService *VmService) FetchVmNameIncludingDeleted(vmName string) (*models.VirtualMachine, error) {
+	db := vmService.getDB()
+
+	var vm models.VirtualMachine
+
+	if err := db.Where("name = ?", vmName).First(&vm).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	vm.Password = ""
+	return &vm, nil
+}
+
 func (vmService *VmService) CreateUserVM(params CreateVmParams) (*models.VirtualMachine, error) {
-	db := db.GetDB()
+	defer vmService.invalidateReads()
+
+	db := vmService.getDB()
 
 	vm := models.VirtualMachine{
 		Name:      params.VmName,
@@ -77,7 +155,27 @@ func (vmService *VmService) CreateUserVM(params CreateVmParams) (*models.Virtual
 		NodePort:  params.VmSSHPort,
 		UserID:    params.UserID,
 		Image:     params.VmImage,
+		SSHUser:   params.SSHUser,
+		Flavor:    params.VmFlavor,
+		DiskGi:    params.DiskGi,
 		Status:    models.VmStatusProvisioning,
+
+		DnsHost:    params.DnsHost,
+		MacAddress: params.MacAddress,
+		Networks:   params.Networks,
+		SSHKeys:    params.SSHKeys,
+		CloudInit:  params.CloudInit,
+
+		DesiredState: models.VmDesiredRunning,
+		ExpiresAt:    params.ExpiresAt,
+
+		BundleChannel: params.BundleChannel,
+		BundleVersion: params.BundleVersion,
+	}
+
+	if params.CredentialsRevealed {
+		now := time.Now()
+		vm.CredentialsRevealedAt = &now
 	}
 
 	if err := db.Create(&vm).Error; err != nil {
@@ -87,30 +185,172 @@ func (vmService *VmService) CreateUserVM(params CreateVmParams) (*models.Virtual
 	return &vm, nil
 }
 
+// UpdateVmStatus는 상태 머신(vmstate)에 정의된 전이만 허용하여 VM 상태를 변경합니다.
+// 허용되지 않은 전이는 *vmstate.IllegalTransitionError를 반환합니다.
 func (vmService *VmService) UpdateVmStatus(vmName string, status models.EnumVmStatus) error {
-	db := db.GetDB()
+	return vmService.transitionVmStatus(vmName, status, false)
+}
+
+// SetDesiredState는 VM의 목표 상태를 설정합니다. 실제 반영은 converger 또는 즉시 실행되는 작업이 수행합니다.
+// 삭제가 요청된 VM의 목표 상태는 되돌릴 수 없습니다.
+func (vmService *VmService) SetDesiredState(vmName string, desired models.EnumVmDesiredState) error {
+	defer vmService.invalidateReads()
+
+	db := vmService.getDB()
+
+	return db.Model(&models.VirtualMachine{}).
+		Where("name = ? AND desired_state <> ?", vmName, models.VmDesiredDeleted).
+		Update("desired_state", desired).Error
+}
+
+// FetchUnconvergedVMs는 converger가 확인해야 할 VM 목록을 반환합니다.
+// 삭제가 끝나지 않은 VM(is_deleted = true 이지만 상태가 Deleted가 아닌 경우)도 포함합니다.
+func (vmService *VmService) FetchUnconvergedVMs() ([]models.VirtualMachine, error) {
+	db := vmService.getDB()
+
+	var vms []models.VirtualMachine
+	if err := db.Where("status IS NULL OR status <> ?", models.VmStatusDeleted).Order("id").Find(&vms).Error; err != nil {
+		return nil, err
+	}
+
+	return vms, nil
+}
+
+// MarkVmFailed는 백그라운드 작업이 최종 실패했을 때 VM을 Failed로 표시합니다.
+// 삭제 도중 실패한 VM(is_deleted = true)도 남은 리소스 확인을 위해 상태를 갱신합니다.
+func (vmService *VmService) MarkVmFailed(vmName string) error {
+	return vmService.transitionVmStatus(vmName, models.VmStatusFailed, true)
+}
+
+// MarkVmDeleted는 VM 리소스 삭제가 모두 끝난 뒤 상태를 Deleted로 변경합니다.
+func (vmService *VmService) MarkVmDeleted(vmName string) error {
+	return vmService.transitionVmStatus(vmName, models.VmStatusDeleted, true)
+}
+
+func (vmService *VmService) transitionVmStatus(vmName string, status models.EnumVmStatus, includeDeleted bool) error {
+	defer vmService.invalidateReads()
+
+	db := vmService.getDB()
 
-	if err := db.Model(&models.VirtualMachine{}).Where("name = ? AND is_deleted = false", vmName).Update("status", status).Error; err != nil {
+	var from models.EnumVmStatus
+	var userId uint
+	err := db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("name = ?", vmName)
+		if !includeDeleted {
+			query = query.Where("is_deleted = false")
+		}
+
+		var vm models.VirtualMachine
+		if err := query.Select("id", "status", "user_id").First(&vm).Error; err != nil {
+			return err
+		}
+		from = vm.Status
+		userId = vm.UserID
+
+		if err := vmstate.Check(vmName, from, status); err != nil {
+			return err
+		}
+
+		return tx.Model(&models.VirtualMachine{}).Where("id = ?", vm.ID).Update("status", status).Error
+	})
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("vm %s not found", vmName)
+	}
+	if err != nil {
 		return err
 	}
 
+	vmstate.Emit(vmName, userId, from, status)
 	return nil
 }
 
+// UpdateVmMacAddress는 MAC 주소가 기록되지 않은 (이전에 생성된) VM에 새로 발급한 MAC 주소를 저장합니다.
+func (vmService *VmService) UpdateVmMacAddress(vmName, macAddress string) error {
+	defer vmService.invalidateReads()
+
+	db := vmService.getDB()
+
+	return db.Model(&models.VirtualMachine{}).Where("name = ?", vmName).Update("mac_address", macAddress).Error
+}
+
+// UpdateVmFlavor는 요금제 변경이 클러스터에 반영된 뒤 VM의 요금제를 저장합니다. (디스크 크기는 바뀌지 않음)
+func (vmService *VmService) UpdateVmFlavor(vmName, flavor string) error {
+	defer vmService.invalidateReads()
+
+	db := vmService.getDB()
+
+	return db.Model(&models.VirtualMachine{}).Where("name = ?", vmName).Update("flavor", flavor).Error
+}
+
+// TransferVm은 VM과 VM에 딸린 추가 디스크/스냅샷의 소유자를 userId로 바꿉니다.
+// 클러스터 리소스는 원래 네임스페이스에 그대로 두므로 VM은 재시작되지 않습니다.
+func (vmService *VmService) TransferVm(vmName string, userId uint) error {
+	defer vmService.invalidateReads()
+
+	db := vmService.getDB()
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.VirtualMachine{}).Where("name = ? AND is_deleted = false", vmName).Update("user_id", userId)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("vm %s not found", vmName)
+		}
+
+		if err := tx.Model(&models.VolumeAttachment{}).Where("vm_name = ?", vmName).Update("user_id", userId).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Snapshot{}).Where("vm_name = ?", vmName).Update("user_id", userId).Error
+	})
+}
+
+// RevealCredentials는 VM 비밀번호를 한 번만 반환합니다.
+// 조건부 업데이트로 기록하므로 동시에 요청해도 한 요청만 비밀번호를 받고, 이후에는 ErrCredentialsRevealed를 반환합니다.
+func (vmService *VmService) RevealCredentials(vmName string) (string, error) {
+	db := vmService.getDB()
+
+	result := db.Model(&models.VirtualMachine{}).
+		Where("name = ? AND is_deleted = false AND credentials_revealed_at IS NULL", vmName).
+		Update("credentials_revealed_at", time.Now())
+	if result.Error != nil {
+		return "", result.Error
+	}
+	if result.RowsAffected == 0 {
+		return "", ErrCredentialsRevealed
+	}
+
+	vm, err := vmService.FetchVmName(vmName, true)
+	if err != nil {
+		return "", err
+	}
+	if vm == nil {
+		return "", fmt.Errorf("vm %s not found", vmName)
+	}
+
+	return vm.Password, nil
+}
+
 func (vmService *VmService) DeleteVm(vmName string) error {
-	db := db.GetDB()
+	defer vmService.invalidateReads()
+
+	db := vmService.getDB()
 
-	if err := db.Model(&models.VirtualMachine{}).Where("name = ? AND is_deleted = false", vmName).Update("is_deleted", true).Error; err != nil {
+	if err := db.Model(&models.VirtualMachine{}).Where("name = ? AND is_deleted = false", vmName).Updates(map[string]interface{}{
+		"is_deleted":    true,
+		"desired_state": models.VmDesiredDeleted,
+	}).Error; err != nil {
 		return err
 	}
 
 	return nil
 }
 
-// GetLowestPort는 사용 가능한 가장 낮은 NodePort를 반환합니다 (30003 ~ 30300).
-// GetLowestPort returns the lowest available NodePort (30003 ~ 30300).
+// GetLowestPort는 사용 가능한 가장 낮은 NodePort를 반환합니다 (PortRangeStart ~ PortRangeEnd).
+// GetLowestPort returns the lowest available NodePort (PortRangeStart ~ PortRangeEnd).
 func (vmService *VmService) GetAvailablePort() (int, error) {
-	db := db.GetDB()
+	db := vmService.getDB()
 
 	// 사용 중인 포트 목록 조회
 	var usedPorts []int
@@ -127,17 +367,17 @@ func (vmService *VmService) GetAvailablePort() (int, error) {
 	}
 
 	// 가장 낮은 가용 포트 탐색 (Find lowest available port)
-	for port := 30003; port <= 30300; port++ {
+	for port := PortRangeStart; port <= PortRangeEnd; port++ {
 		if !portMap[port] {
 			return port, nil
 		}
 	}
 
-	return 0, fmt.Errorf("no available ports in range 30003-30300 (가용 포트 없음)")
+	return 0, fmt.Errorf("%w in range %d-%d (가용 포트 없음)", ErrPortsExhausted, PortRangeStart, PortRangeEnd)
 }
 
 func (vmService *VmService) IsPortAvailable(port int) (bool, error) {
-	db := db.GetDB()
+	db := vmService.getDB()
 
 	var vm models.VirtualMachine
 
@@ -1,11 +1,29 @@
 package vmservice
 
+import (
+	"time"
+	"vm-controller/internal/models"
+)
+
 type CreateVmParams struct {
 	Namespace  string
 	VmName     string
 	VmPassword string
 	DnsHost    string
+	MacAddress string
+	Networks   []models.VmNetwork
+	SSHKeys    []string
+	CloudInit  string
 	VmSSHPort  int32
 	VmImage    string
+	SSHUser    string // 이미지의 기본 SSH 사용자
+	VmFlavor   string
+	DiskGi     int
 	UserID     uint
+
+	BundleChannel string
+	BundleVersion string
+
+	CredentialsRevealed bool       // 생성 응답으로 비밀번호를 이미 전달함 (자동 생성 비밀번호)
+	ExpiresAt           *time.Time // 사용 기간 만료 시각 (LeaseExpiry)
 }
@@ -0,0 +1,108 @@
+package volumeservice
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+	"vm-controller/internal/db"
+	"vm-controller/internal/models"
+	"vm-controller/internal/naming"
+
+	"github.com/spf13/cast"
+	"gorm.io/gorm"
+)
+
+type VolumeService struct {
+}
+
+func NewVolumeService() *VolumeService {
+	return &VolumeService{}
+}
+
+// VM당 추가 디스크 수 기본값 (VM_VOLUME_LIMIT)
+const defaultVolumeLimit = 4
+
+// 추가 디스크 한 개의 최대 크기 기본값 (VM_VOLUME_MAX_SIZE_GI)
+const defaultVolumeMaxSizeGi = 50
+
+// ErrVolumeLimit은 VM의 추가 디스크 수가 상한에 도달했을 때 반환됩니다.
+var ErrVolumeLimit = errors.New("volume limit reached for this VM")
+
+// Limit은 VM당 추가 디스크 상한을 반환합니다.
+func (s *VolumeService) Limit() int {
+	if limit, err := cast.ToIntE(os.Getenv("VM_VOLUME_LIMIT")); err == nil && limit > 0 {
+		return limit
+	}
+	return defaultVolumeLimit
+}
+
+// MaxSizeGi는 추가 디스크 한 개의 최대 크기를 반환합니다.
+func (s *VolumeService) MaxSizeGi() int {
+	if size, err := cast.ToIntE(os.Getenv("VM_VOLUME_MAX_SIZE_GI")); err == nil && size > 0 {
+		return size
+	}
+	return defaultVolumeMaxSizeGi
+}
+
+// VolumeName은 DataVolume 이름을 생성합니다.
+func (s *VolumeService) VolumeName(vmName string) string {
+	return naming.ResourceName("", vmName, fmt.Sprintf("-vol-%d", time.Now().Unix()))
+}
+
+// CreateVolume은 추가 디스크 레코드를 저장합니다. VM의 추가 디스크 수가 상한에 도달했으면 ErrVolumeLimit을 반환합니다.
+func (s *VolumeService) CreateVolume(volume *models.VolumeAttachment) error {
+	db := db.GetDB()
+
+	var count int64
+	if err := db.Model(&models.VolumeAttachment{}).Where("vm_name = ?", volume.VmName).Count(&count).Error; err != nil {
+		return err
+	}
+	if int(count) >= s.Limit() {
+		return ErrVolumeLimit
+	}
+
+	return db.Create(volume).Error
+}
+
+// FetchVmVolumes는 VM의 추가 디스크를 연결 순서대로 반환합니다.
+func (s *VolumeService) FetchVmVolumes(vmName string) ([]models.VolumeAttachment, error) {
+	db := db.GetDB()
+
+	var volumes []models.VolumeAttachment
+	if err := db.Where("vm_name = ?", vmName).Order("id").Find(&volumes).Error; err != nil {
+		return nil, err
+	}
+
+	return volumes, nil
+}
+
+// FetchVolume은 VM의 추가 디스크 한 개를 반환합니다. (없으면 nil)
+func (s *VolumeService) FetchVolume(vmName, name string) (*models.VolumeAttachment, error) {
+	db := db.GetDB()
+
+	var volume models.VolumeAttachment
+	if err := db.Where("vm_name = ? AND name = ?", vmName, name).First(&volume).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &volume, nil
+}
+
+// UpdateVolumeStatus는 추가 디스크의 상태와 실패 사유를 변경합니다.
+func (s *VolumeService) UpdateVolumeStatus(id uint, status models.EnumVolumeStatus, message string) error {
+	db := db.GetDB()
+
+	return db.Model(&models.VolumeAttachment{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"status": status, "message": message}).Error
+}
+
+// DeleteVolume은 추가 디스크 레코드를 삭제합니다.
+func (s *VolumeService) DeleteVolume(id uint) error {
+	db := db.GetDB()
+
+	return db.Delete(&models.VolumeAttachment{}, id).Error
+}
@@ -0,0 +1,129 @@
+package version
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// 빌드 시 -ldflags 로 주입됩니다.
+//
+//	go build -ldflags "-X vm-controller/internal/version.Version=1.4.0 \
+//	  -X vm-controller/internal/version.Commit=$(git rev-parse HEAD) \
+//	  -X vm-controller/internal/version.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/server
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildDate = ""
+)
+
+// BuildInfo는 실행 중인 바이너리의 빌드 정보입니다.
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"build_date,omitempty"`
+	Modified  bool   `json:"modified,omitempty"` // 커밋되지 않은 변경이 포함된 빌드
+	GoVersion string `json:"go_version"`
+}
+
+// Build는 빌드 정보를 반환합니다. ldflags로 커밋이 주입되지 않았으면 Go 빌드 정보(vcs.revision)를 사용합니다.
+func Build() BuildInfo {
+	info := BuildInfo{Version: Version, Commit: Commit, BuildDate: BuildDate, GoVersion: runtime.Version()}
+
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range buildInfo.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = setting.Value
+				}
+			case "vcs.time":
+				if info.BuildDate == "" {
+					info.BuildDate = setting.Value
+				}
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+	}
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+
+	return info
+}
+
+// ManifestBundleDir는 VM/배포 템플릿(매니페스트 번들) 디렉터리입니다. (실행 위치 기준)
+const ManifestBundleDir = "yaml-data"
+
+// BundleInfo는 매니페스트 번들의 버전입니다.
+// Version은 번들의 VERSION 파일 값이고, Digest는 번들 전체 내용의 해시로 VERSION을 올리지 않은 수정도 구분합니다.
+type BundleInfo struct {
+	Version string `json:"version"`
+	Digest  string `json:"digest"`
+}
+
+var (
+	bundleMu    sync.Mutex
+	bundleCache = map[string]BundleInfo{}
+)
+
+// Bundle은 dir의 매니페스트 번들 버전을 반환합니다. 번들은 실행 중에 바뀌지 않으므로 한 번만 계산합니다.
+func Bundle(dir string) BundleInfo {
+	bundleMu.Lock()
+	defer bundleMu.Unlock()
+
+	if info, ok := bundleCache[dir]; ok {
+		return info
+	}
+
+	info := BundleInfo{Version: "unversioned", Digest: "unknown"}
+	if raw, err := os.ReadFile(filepath.Join(dir, "VERSION")); err == nil && strings.TrimSpace(string(raw)) != "" {
+		info.Version = strings.TrimSpace(string(raw))
+	}
+	if digest, err := digestDir(dir); err == nil {
+		info.Digest = digest
+	}
+
+	bundleCache[dir] = info
+	return info
+}
+
+// digestDir은 디렉터리의 모든 파일 경로와 내용을 정렬된 순서로 해시합니다.
+func digestDir(dir string) (string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(files)
+
+	hash := sha256.New()
+	for _, file := range files {
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+		rel, _ := filepath.Rel(dir, file)
+		hash.Write([]byte(filepath.ToSlash(rel) + "\x00"))
+		hash.Write(raw)
+		hash.Write([]byte{0})
+	}
+
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil))[:12], nil
+}
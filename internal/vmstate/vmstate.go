@@ -0,0 +1,137 @@
+package vmstate
+
+import (
+	"fmt"
+	"sync"
+	"time"
+	"vm-controller/internal/metrics"
+	"vm-controller/internal/models"
+)
+
+// VM 상태 전이 규칙
+//
+//	Provisioning -> Running | Failed | Deleted
+//	Running      -> Stopping | Restarting | Pausing | Failed | Deleted
+//	Restarting   -> Running | Stopping | Failed | Deleted
+//	Pausing      -> Paused | Running | Stopping | Failed | Deleted
+//	Paused       -> Running | Stopping | Failed | Deleted
+//	Stopping     -> Stopped | Running | Failed | Deleted
+//	Stopped      -> Running | Restoring | Failed | Deleted
+//	Restoring    -> Stopped | Failed | Deleted
+//	Failed       -> Running | Restarting | Stopping | Stopped | Restoring | Deleted
+//	Deleted      -> (없음)
+//
+// 같은 상태로의 전이는 항상 허용됩니다. (멱등)
+var transitions = map[models.EnumVmStatus][]models.EnumVmStatus{
+	models.VmStatusProvisioning: {models.VmStatusRunning, models.VmStatusFailed, models.VmStatusDeleted},
+	models.VmStatusRunning:      {models.VmStatusStopping, models.VmStatusRestarting, models.VmStatusPausing, models.VmStatusFailed, models.VmStatusDeleted},
+	models.VmStatusRestarting:   {models.VmStatusRunning, models.VmStatusStopping, models.VmStatusFailed, models.VmStatusDeleted},
+	models.VmStatusPausing:      {models.VmStatusPaused, models.VmStatusRunning, models.VmStatusStopping, models.VmStatusFailed, models.VmStatusDeleted},
+	models.VmStatusPaused:       {models.VmStatusRunning, models.VmStatusStopping, models.VmStatusFailed, models.VmStatusDeleted},
+	models.VmStatusStopping:     {models.VmStatusStopped, models.VmStatusRunning, models.VmStatusFailed, models.VmStatusDeleted},
+	models.VmStatusStopped:      {models.VmStatusRunning, models.VmStatusRestoring, models.VmStatusFailed, models.VmStatusDeleted},
+	models.VmStatusRestoring:    {models.VmStatusStopped, models.VmStatusFailed, models.VmStatusDeleted},
+	models.VmStatusFailed:       {models.VmStatusRunning, models.VmStatusRestarting, models.VmStatusStopping, models.VmStatusStopped, models.VmStatusRestoring, models.VmStatusDeleted},
+	models.VmStatusDeleted:      {},
+}
+
+var transitionsTotal = metrics.NewCounterVec("vm_status_transitions_total", "VM status transitions.", "from", "to", "tenant")
+
+// IllegalTransitionError는 허용되지 않은 상태 전이를 시도했을 때 반환됩니다.
+type IllegalTransitionError struct {
+	VmName string
+	From   models.EnumVmStatus
+	To     models.EnumVmStatus
+}
+
+func (e *IllegalTransitionError) Error() string {
+	return fmt.Sprintf("illegal VM status transition for %s: %s -> %s", e.VmName, e.From, e.To)
+}
+
+// Transition은 상태 전이 이벤트입니다.
+type Transition struct {
+	VmName string
+	From   models.EnumVmStatus
+	To     models.EnumVmStatus
+	At     time.Time
+}
+
+// IsValid는 알려진 상태 값인지 확인합니다.
+func IsValid(status models.EnumVmStatus) bool {
+	_, ok := transitions[status]
+	return ok
+}
+
+// CanTransition은 from -> to 전이가 허용되는지 확인합니다.
+// 상태가 비어 있는(마이그레이션 이전) 레코드는 어떤 상태로든 전이할 수 있습니다.
+func CanTransition(from, to models.EnumVmStatus) bool {
+	if !IsValid(to) {
+		return false
+	}
+	if from == to || from == "" {
+		return true
+	}
+
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Path는 from에서 to로 가는 허용된 전이 순서를 반환합니다. (to 포함, 최대 한 단계 경유)
+// 클러스터에서 관측한 상태를 DB에 맞출 때 직접 전이가 허용되지 않는 경우에 사용합니다. (예: Running -> Stopping -> Stopped)
+// 경로가 없으면 nil을 반환합니다.
+func Path(from, to models.EnumVmStatus) []models.EnumVmStatus {
+	if CanTransition(from, to) {
+		return []models.EnumVmStatus{to}
+	}
+
+	for _, via := range transitions[from] {
+		if via != models.VmStatusDeleted && CanTransition(via, to) {
+			return []models.EnumVmStatus{via, to}
+		}
+	}
+	return nil
+}
+
+// Check는 전이가 허용되지 않으면 IllegalTransitionError를 반환합니다.
+func Check(vmName string, from, to models.EnumVmStatus) error {
+	if !CanTransition(from, to) {
+		return &IllegalTransitionError{VmName: vmName, From: from, To: to}
+	}
+	return nil
+}
+
+var (
+	listenersMu sync.RWMutex
+	listeners   []func(Transition)
+)
+
+// OnTransition은 상태 전이 이벤트를 받을 리스너를 등록합니다.
+func OnTransition(listener func(Transition)) {
+	listenersMu.Lock()
+	defer listenersMu.Unlock()
+
+	listeners = append(listeners, listener)
+}
+
+// Emit은 DB에 전이가 반영된 뒤 호출되어 등록된 리스너에 이벤트를 전달합니다.
+// 같은 상태로의 전이는 이벤트를 발생시키지 않습니다. userId는 메트릭 테넌트 라벨에만 사용됩니다.
+func Emit(vmName string, userId uint, from, to models.EnumVmStatus) {
+	if from == to {
+		return
+	}
+
+	transitionsTotal.Inc(string(from), string(to), metrics.TenantLabelFor(userId))
+
+	event := Transition{VmName: vmName, From: from, To: to, At: time.Now()}
+
+	listenersMu.RLock()
+	defer listenersMu.RUnlock()
+
+	for _, listener := range listeners {
+		listener(event)
+	}
+}
@@ -0,0 +1,4 @@
+// Package vmcontrollerv1은 vm_controller.proto에서 생성한 gRPC API 코드입니다.
+package vmcontrollerv1
+
+//go:generate protoc -I . --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative vm_controller.proto
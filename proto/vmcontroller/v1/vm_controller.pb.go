@@ -0,0 +1,1199 @@
+// vm-controller gRPC API (v1)
+//
+// REST API(/api/v1)와 같은 서비스 계층을 사용하는 내부 자동화용 API입니다.
+// 모든 호출은 metadata "authorization: Bearer <token>" (REST 로그인 토큰과 동일)이 필요합니다.
+//
+// 코드 생성: go generate ./proto/... (protoc, protoc-gen-go, protoc-gen-go-grpc 필요)
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.28.3
+// source: vm_controller.proto
+
+package vmcontrollerv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type VM struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Id                  uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Namespace           string                 `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Status              string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	DesiredState        string                 `protobuf:"bytes,5,opt,name=desired_state,json=desiredState,proto3" json:"desired_state,omitempty"`
+	Image               string                 `protobuf:"bytes,6,opt,name=image,proto3" json:"image,omitempty"`
+	SshUser             string                 `protobuf:"bytes,7,opt,name=ssh_user,json=sshUser,proto3" json:"ssh_user,omitempty"`
+	Flavor              string                 `protobuf:"bytes,8,opt,name=flavor,proto3" json:"flavor,omitempty"`
+	DiskGi              int32                  `protobuf:"varint,9,opt,name=disk_gi,json=diskGi,proto3" json:"disk_gi,omitempty"`
+	NodePort            int32                  `protobuf:"varint,10,opt,name=node_port,json=nodePort,proto3" json:"node_port,omitempty"`
+	DnsHost             string                 `protobuf:"bytes,11,opt,name=dns_host,json=dnsHost,proto3" json:"dns_host,omitempty"`
+	MacAddress          string                 `protobuf:"bytes,12,opt,name=mac_address,json=macAddress,proto3" json:"mac_address,omitempty"`
+	Networks            []*VMNetwork           `protobuf:"bytes,13,rep,name=networks,proto3" json:"networks,omitempty"`
+	SshKeys             []string               `protobuf:"bytes,14,rep,name=ssh_keys,json=sshKeys,proto3" json:"ssh_keys,omitempty"`
+	BundleChannel       string                 `protobuf:"bytes,15,opt,name=bundle_channel,json=bundleChannel,proto3" json:"bundle_channel,omitempty"`
+	BundleVersion       string                 `protobuf:"bytes,16,opt,name=bundle_version,json=bundleVersion,proto3" json:"bundle_version,omitempty"`
+	CredentialsRevealed bool                   `protobuf:"varint,17,opt,name=credentials_revealed,json=credentialsRevealed,proto3" json:"credentials_revealed,omitempty"`
+	ExpiresAt           *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // 비어 있으면 만료 없음
+	CreatedAt           *timestamppb.Timestamp `protobuf:"bytes,19,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt           *timestamppb.Timestamp `protobuf:"bytes,20,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *VM) Reset() {
+	*x = VM{}
+	mi := &file_vm_controller_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VM) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VM) ProtoMessage() {}
+
+func (x *VM) ProtoReflect() protoreflect.Message {
+	mi := &file_vm_controller_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VM.ProtoReflect.Descriptor instead.
+func (*VM) Descriptor() ([]byte, []int) {
+	return file_vm_controller_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *VM) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *VM) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *VM) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *VM) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *VM) GetDesiredState() string {
+	if x != nil {
+		return x.DesiredState
+	}
+	return ""
+}
+
+func (x *VM) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *VM) GetSshUser() string {
+	if x != nil {
+		return x.SshUser
+	}
+	return ""
+}
+
+func (x *VM) GetFlavor() string {
+	if x != nil {
+		return x.Flavor
+	}
+	return ""
+}
+
+func (x *VM) GetDiskGi() int32 {
+	if x != nil {
+		return x.DiskGi
+	}
+	return 0
+}
+
+func (x *VM) GetNodePort() int32 {
+	if x != nil {
+		return x.NodePort
+	}
+	return 0
+}
+
+func (x *VM) GetDnsHost() string {
+	if x != nil {
+		return x.DnsHost
+	}
+	return ""
+}
+
+func (x *VM) GetMacAddress() string {
+	if x != nil {
+		return x.MacAddress
+	}
+	return ""
+}
+
+func (x *VM) GetNetworks() []*VMNetwork {
+	if x != nil {
+		return x.Networks
+	}
+	return nil
+}
+
+func (x *VM) GetSshKeys() []string {
+	if x != nil {
+		return x.SshKeys
+	}
+	return nil
+}
+
+func (x *VM) GetBundleChannel() string {
+	if x != nil {
+		return x.BundleChannel
+	}
+	return ""
+}
+
+func (x *VM) GetBundleVersion() string {
+	if x != nil {
+		return x.BundleVersion
+	}
+	return ""
+}
+
+func (x *VM) GetCredentialsRevealed() bool {
+	if x != nil {
+		return x.CredentialsRevealed
+	}
+	return false
+}
+
+func (x *VM) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *VM) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *VM) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type VMNetwork struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Network       string                 `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	MacAddress    string                 `protobuf:"bytes,2,opt,name=mac_address,json=macAddress,proto3" json:"mac_address,omitempty"`
+	Address       string                 `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VMNetwork) Reset() {
+	*x = VMNetwork{}
+	mi := &file_vm_controller_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VMNetwork) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VMNetwork) ProtoMessage() {}
+
+func (x *VMNetwork) ProtoReflect() protoreflect.Message {
+	mi := &file_vm_controller_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VMNetwork.ProtoReflect.Descriptor instead.
+func (*VMNetwork) Descriptor() ([]byte, []int) {
+	return file_vm_controller_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *VMNetwork) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *VMNetwork) GetMacAddress() string {
+	if x != nil {
+		return x.MacAddress
+	}
+	return ""
+}
+
+func (x *VMNetwork) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+type ListVMsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Page          int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`        // 1부터, 0이면 전체
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`      // page가 있고 0이면 20
+	Statuses      []string               `protobuf:"bytes,3,rep,name=statuses,proto3" json:"statuses,omitempty"` // 비어 있으면 모든 상태
+	Query         string                 `protobuf:"bytes,4,opt,name=query,proto3" json:"query,omitempty"`       // 이름 부분 일치
+	Sort          string                 `protobuf:"bytes,5,opt,name=sort,proto3" json:"sort,omitempty"`         // 예: -created_at
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVMsRequest) Reset() {
+	*x = ListVMsRequest{}
+	mi := &file_vm_controller_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVMsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVMsRequest) ProtoMessage() {}
+
+func (x *ListVMsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vm_controller_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVMsRequest.ProtoReflect.Descriptor instead.
+func (*ListVMsRequest) Descriptor() ([]byte, []int) {
+	return file_vm_controller_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListVMsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListVMsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListVMsRequest) GetStatuses() []string {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *ListVMsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *ListVMsRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+type ListVMsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Vms           []*VM                  `protobuf:"bytes,1,rep,name=vms,proto3" json:"vms,omitempty"`
+	Total         int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	HasMore       bool                   `protobuf:"varint,5,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVMsResponse) Reset() {
+	*x = ListVMsResponse{}
+	mi := &file_vm_controller_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVMsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVMsResponse) ProtoMessage() {}
+
+func (x *ListVMsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vm_controller_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVMsResponse.ProtoReflect.Descriptor instead.
+func (*ListVMsResponse) Descriptor() ([]byte, []int) {
+	return file_vm_controller_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListVMsResponse) GetVms() []*VM {
+	if x != nil {
+		return x.Vms
+	}
+	return nil
+}
+
+func (x *ListVMsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListVMsResponse) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListVMsResponse) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListVMsResponse) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
+type GetVMRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVMRequest) Reset() {
+	*x = GetVMRequest{}
+	mi := &file_vm_controller_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVMRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVMRequest) ProtoMessage() {}
+
+func (x *GetVMRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vm_controller_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVMRequest.ProtoReflect.Descriptor instead.
+func (*GetVMRequest) Descriptor() ([]byte, []int) {
+	return file_vm_controller_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetVMRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type CreateVMRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	VmName           string                 `protobuf:"bytes,1,opt,name=vm_name,json=vmName,proto3" json:"vm_name,omitempty"`
+	VmSshPassword    string                 `protobuf:"bytes,2,opt,name=vm_ssh_password,json=vmSshPassword,proto3" json:"vm_ssh_password,omitempty"`
+	VmImage          string                 `protobuf:"bytes,3,opt,name=vm_image,json=vmImage,proto3" json:"vm_image,omitempty"`    // 비어 있으면 사용자 기본값
+	VmFlavor         string                 `protobuf:"bytes,4,opt,name=vm_flavor,json=vmFlavor,proto3" json:"vm_flavor,omitempty"` // 비어 있으면 사용자 기본값
+	VmHostPrefix     string                 `protobuf:"bytes,5,opt,name=vm_host_prefix,json=vmHostPrefix,proto3" json:"vm_host_prefix,omitempty"`
+	Networks         []*CreateVMNetwork     `protobuf:"bytes,6,rep,name=networks,proto3" json:"networks,omitempty"`
+	GeneratePassword bool                   `protobuf:"varint,7,opt,name=generate_password,json=generatePassword,proto3" json:"generate_password,omitempty"`
+	SshKeyIds        []uint64               `protobuf:"varint,8,rep,packed,name=ssh_key_ids,json=sshKeyIds,proto3" json:"ssh_key_ids,omitempty"` // 비어 있으면 사용자 기본값
+	CloudInit        string                 `protobuf:"bytes,9,opt,name=cloud_init,json=cloudInit,proto3" json:"cloud_init,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CreateVMRequest) Reset() {
+	*x = CreateVMRequest{}
+	mi := &file_vm_controller_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateVMRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateVMRequest) ProtoMessage() {}
+
+func (x *CreateVMRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vm_controller_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateVMRequest.ProtoReflect.Descriptor instead.
+func (*CreateVMRequest) Descriptor() ([]byte, []int) {
+	return file_vm_controller_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateVMRequest) GetVmName() string {
+	if x != nil {
+		return x.VmName
+	}
+	return ""
+}
+
+func (x *CreateVMRequest) GetVmSshPassword() string {
+	if x != nil {
+		return x.VmSshPassword
+	}
+	return ""
+}
+
+func (x *CreateVMRequest) GetVmImage() string {
+	if x != nil {
+		return x.VmImage
+	}
+	return ""
+}
+
+func (x *CreateVMRequest) GetVmFlavor() string {
+	if x != nil {
+		return x.VmFlavor
+	}
+	return ""
+}
+
+func (x *CreateVMRequest) GetVmHostPrefix() string {
+	if x != nil {
+		return x.VmHostPrefix
+	}
+	return ""
+}
+
+func (x *CreateVMRequest) GetNetworks() []*CreateVMNetwork {
+	if x != nil {
+		return x.Networks
+	}
+	return nil
+}
+
+func (x *CreateVMRequest) GetGeneratePassword() bool {
+	if x != nil {
+		return x.GeneratePassword
+	}
+	return false
+}
+
+func (x *CreateVMRequest) GetSshKeyIds() []uint64 {
+	if x != nil {
+		return x.SshKeyIds
+	}
+	return nil
+}
+
+func (x *CreateVMRequest) GetCloudInit() string {
+	if x != nil {
+		return x.CloudInit
+	}
+	return ""
+}
+
+type CreateVMNetwork struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Network       string                 `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	Address       string                 `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateVMNetwork) Reset() {
+	*x = CreateVMNetwork{}
+	mi := &file_vm_controller_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateVMNetwork) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateVMNetwork) ProtoMessage() {}
+
+func (x *CreateVMNetwork) ProtoReflect() protoreflect.Message {
+	mi := &file_vm_controller_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateVMNetwork.ProtoReflect.Descriptor instead.
+func (*CreateVMNetwork) Descriptor() ([]byte, []int) {
+	return file_vm_controller_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreateVMNetwork) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *CreateVMNetwork) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+type CreateVMResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Vm            *VM                    `protobuf:"bytes,1,opt,name=vm,proto3" json:"vm,omitempty"` // 승인 대기 중이면 비어 있음
+	OperationId   uint64                 `protobuf:"varint,2,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+	Password      string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`                        // generate_password 요청 시에만 (다시 조회할 수 없음)
+	Warning       string                 `protobuf:"bytes,4,opt,name=warning,proto3" json:"warning,omitempty"`                          // 스토리지 쿼터 근접 경고
+	ApprovalId    uint64                 `protobuf:"varint,5,opt,name=approval_id,json=approvalId,proto3" json:"approval_id,omitempty"` // 관리자 승인 대기 중인 요청
+	Accepted      bool                   `protobuf:"varint,6,opt,name=accepted,proto3" json:"accepted,omitempty"`                       // Operator 모드: UserVM만 생성되고 프로비저닝은 비동기로 진행
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateVMResponse) Reset() {
+	*x = CreateVMResponse{}
+	mi := &file_vm_controller_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateVMResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateVMResponse) ProtoMessage() {}
+
+func (x *CreateVMResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vm_controller_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateVMResponse.ProtoReflect.Descriptor instead.
+func (*CreateVMResponse) Descriptor() ([]byte, []int) {
+	return file_vm_controller_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CreateVMResponse) GetVm() *VM {
+	if x != nil {
+		return x.Vm
+	}
+	return nil
+}
+
+func (x *CreateVMResponse) GetOperationId() uint64 {
+	if x != nil {
+		return x.OperationId
+	}
+	return 0
+}
+
+func (x *CreateVMResponse) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *CreateVMResponse) GetWarning() string {
+	if x != nil {
+		return x.Warning
+	}
+	return ""
+}
+
+func (x *CreateVMResponse) GetApprovalId() uint64 {
+	if x != nil {
+		return x.ApprovalId
+	}
+	return 0
+}
+
+func (x *CreateVMResponse) GetAccepted() bool {
+	if x != nil {
+		return x.Accepted
+	}
+	return false
+}
+
+type VMActionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	VmName        string                 `protobuf:"bytes,1,opt,name=vm_name,json=vmName,proto3" json:"vm_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VMActionRequest) Reset() {
+	*x = VMActionRequest{}
+	mi := &file_vm_controller_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VMActionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VMActionRequest) ProtoMessage() {}
+
+func (x *VMActionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vm_controller_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VMActionRequest.ProtoReflect.Descriptor instead.
+func (*VMActionRequest) Descriptor() ([]byte, []int) {
+	return file_vm_controller_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *VMActionRequest) GetVmName() string {
+	if x != nil {
+		return x.VmName
+	}
+	return ""
+}
+
+type DeleteVMRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	VmName        string                 `protobuf:"bytes,1,opt,name=vm_name,json=vmName,proto3" json:"vm_name,omitempty"`
+	Confirm       string                 `protobuf:"bytes,2,opt,name=confirm,proto3" json:"confirm,omitempty"` // VM 이름 또는 삭제 확인 토큰
+	Force         bool                   `protobuf:"varint,3,opt,name=force,proto3" json:"force,omitempty"`    // 실행 중인 VM도 삭제
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteVMRequest) Reset() {
+	*x = DeleteVMRequest{}
+	mi := &file_vm_controller_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteVMRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteVMRequest) ProtoMessage() {}
+
+func (x *DeleteVMRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vm_controller_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteVMRequest.ProtoReflect.Descriptor instead.
+func (*DeleteVMRequest) Descriptor() ([]byte, []int) {
+	return file_vm_controller_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DeleteVMRequest) GetVmName() string {
+	if x != nil {
+		return x.VmName
+	}
+	return ""
+}
+
+func (x *DeleteVMRequest) GetConfirm() string {
+	if x != nil {
+		return x.Confirm
+	}
+	return ""
+}
+
+func (x *DeleteVMRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+type VMActionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Vm            *VM                    `protobuf:"bytes,1,opt,name=vm,proto3" json:"vm,omitempty"`
+	OperationId   uint64                 `protobuf:"varint,2,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"` // Operator 모드와 재시작에서는 0
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VMActionResponse) Reset() {
+	*x = VMActionResponse{}
+	mi := &file_vm_controller_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VMActionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VMActionResponse) ProtoMessage() {}
+
+func (x *VMActionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vm_controller_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VMActionResponse.ProtoReflect.Descriptor instead.
+func (*VMActionResponse) Descriptor() ([]byte, []int) {
+	return file_vm_controller_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *VMActionResponse) GetVm() *VM {
+	if x != nil {
+		return x.Vm
+	}
+	return nil
+}
+
+func (x *VMActionResponse) GetOperationId() uint64 {
+	if x != nil {
+		return x.OperationId
+	}
+	return 0
+}
+
+type GetMeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMeRequest) Reset() {
+	*x = GetMeRequest{}
+	mi := &file_vm_controller_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMeRequest) ProtoMessage() {}
+
+func (x *GetMeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vm_controller_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMeRequest.ProtoReflect.Descriptor instead.
+func (*GetMeRequest) Descriptor() ([]byte, []int) {
+	return file_vm_controller_proto_rawDescGZIP(), []int{11}
+}
+
+type User struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	StudentId     string                 `protobuf:"bytes,3,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
+	Email         string                 `protobuf:"bytes,4,opt,name=email,proto3" json:"email,omitempty"`
+	Namespace     string                 `protobuf:"bytes,5,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Role          string                 `protobuf:"bytes,6,opt,name=role,proto3" json:"role,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_vm_controller_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_vm_controller_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_vm_controller_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *User) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *User) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *User) GetStudentId() string {
+	if x != nil {
+		return x.StudentId
+	}
+	return ""
+}
+
+func (x *User) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *User) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *User) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *User) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+var File_vm_controller_proto protoreflect.FileDescriptor
+
+const file_vm_controller_proto_rawDesc = "" +
+	"\n" +
+	"\x13vm_controller.proto\x12\x0fvmcontroller.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc3\x05\n" +
+	"\x02VM\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1c\n" +
+	"\tnamespace\x18\x03 \x01(\tR\tnamespace\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12#\n" +
+	"\rdesired_state\x18\x05 \x01(\tR\fdesiredState\x12\x14\n" +
+	"\x05image\x18\x06 \x01(\tR\x05image\x12\x19\n" +
+	"\bssh_user\x18\a \x01(\tR\asshUser\x12\x16\n" +
+	"\x06flavor\x18\b \x01(\tR\x06flavor\x12\x17\n" +
+	"\adisk_gi\x18\t \x01(\x05R\x06diskGi\x12\x1b\n" +
+	"\tnode_port\x18\n" +
+	" \x01(\x05R\bnodePort\x12\x19\n" +
+	"\bdns_host\x18\v \x01(\tR\adnsHost\x12\x1f\n" +
+	"\vmac_address\x18\f \x01(\tR\n" +
+	"macAddress\x126\n" +
+	"\bnetworks\x18\r \x03(\v2\x1a.vmcontroller.v1.VMNetworkR\bnetworks\x12\x19\n" +
+	"\bssh_keys\x18\x0e \x03(\tR\asshKeys\x12%\n" +
+	"\x0ebundle_channel\x18\x0f \x01(\tR\rbundleChannel\x12%\n" +
+	"\x0ebundle_version\x18\x10 \x01(\tR\rbundleVersion\x121\n" +
+	"\x14credentials_revealed\x18\x11 \x01(\bR\x13credentialsRevealed\x129\n" +
+	"\n" +
+	"expires_at\x18\x12 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x129\n" +
+	"\n" +
+	"created_at\x18\x13 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x14 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"`\n" +
+	"\tVMNetwork\x12\x18\n" +
+	"\anetwork\x18\x01 \x01(\tR\anetwork\x12\x1f\n" +
+	"\vmac_address\x18\x02 \x01(\tR\n" +
+	"macAddress\x12\x18\n" +
+	"\aaddress\x18\x03 \x01(\tR\aaddress\"\x80\x01\n" +
+	"\x0eListVMsRequest\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x1a\n" +
+	"\bstatuses\x18\x03 \x03(\tR\bstatuses\x12\x14\n" +
+	"\x05query\x18\x04 \x01(\tR\x05query\x12\x12\n" +
+	"\x04sort\x18\x05 \x01(\tR\x04sort\"\x93\x01\n" +
+	"\x0fListVMsResponse\x12%\n" +
+	"\x03vms\x18\x01 \x03(\v2\x13.vmcontroller.v1.VMR\x03vms\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x03R\x05total\x12\x12\n" +
+	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\x12\x19\n" +
+	"\bhas_more\x18\x05 \x01(\bR\ahasMore\"\"\n" +
+	"\fGetVMRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\xda\x02\n" +
+	"\x0fCreateVMRequest\x12\x17\n" +
+	"\avm_name\x18\x01 \x01(\tR\x06vmName\x12&\n" +
+	"\x0fvm_ssh_password\x18\x02 \x01(\tR\rvmSshPassword\x12\x19\n" +
+	"\bvm_image\x18\x03 \x01(\tR\avmImage\x12\x1b\n" +
+	"\tvm_flavor\x18\x04 \x01(\tR\bvmFlavor\x12$\n" +
+	"\x0evm_host_prefix\x18\x05 \x01(\tR\fvmHostPrefix\x12<\n" +
+	"\bnetworks\x18\x06 \x03(\v2 .vmcontroller.v1.CreateVMNetworkR\bnetworks\x12+\n" +
+	"\x11generate_password\x18\a \x01(\bR\x10generatePassword\x12\x1e\n" +
+	"\vssh_key_ids\x18\b \x03(\x04R\tsshKeyIds\x12\x1d\n" +
+	"\n" +
+	"cloud_init\x18\t \x01(\tR\tcloudInit\"E\n" +
+	"\x0fCreateVMNetwork\x12\x18\n" +
+	"\anetwork\x18\x01 \x01(\tR\anetwork\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\tR\aaddress\"\xcd\x01\n" +
+	"\x10CreateVMResponse\x12#\n" +
+	"\x02vm\x18\x01 \x01(\v2\x13.vmcontroller.v1.VMR\x02vm\x12!\n" +
+	"\foperation_id\x18\x02 \x01(\x04R\voperationId\x12\x1a\n" +
+	"\bpassword\x18\x03 \x01(\tR\bpassword\x12\x18\n" +
+	"\awarning\x18\x04 \x01(\tR\awarning\x12\x1f\n" +
+	"\vapproval_id\x18\x05 \x01(\x04R\n" +
+	"approvalId\x12\x1a\n" +
+	"\baccepted\x18\x06 \x01(\bR\baccepted\"*\n" +
+	"\x0fVMActionRequest\x12\x17\n" +
+	"\avm_name\x18\x01 \x01(\tR\x06vmName\"Z\n" +
+	"\x0fDeleteVMRequest\x12\x17\n" +
+	"\avm_name\x18\x01 \x01(\tR\x06vmName\x12\x18\n" +
+	"\aconfirm\x18\x02 \x01(\tR\aconfirm\x12\x14\n" +
+	"\x05force\x18\x03 \x01(\bR\x05force\"Z\n" +
+	"\x10VMActionResponse\x12#\n" +
+	"\x02vm\x18\x01 \x01(\v2\x13.vmcontroller.v1.VMR\x02vm\x12!\n" +
+	"\foperation_id\x18\x02 \x01(\x04R\voperationId\"\x0e\n" +
+	"\fGetMeRequest\"\xd4\x01\n" +
+	"\x04User\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x1d\n" +
+	"\n" +
+	"student_id\x18\x03 \x01(\tR\tstudentId\x12\x14\n" +
+	"\x05email\x18\x04 \x01(\tR\x05email\x12\x1c\n" +
+	"\tnamespace\x18\x05 \x01(\tR\tnamespace\x12\x12\n" +
+	"\x04role\x18\x06 \x01(\tR\x04role\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt2\xa9\x04\n" +
+	"\tVMService\x12L\n" +
+	"\aListVMs\x12\x1f.vmcontroller.v1.ListVMsRequest\x1a .vmcontroller.v1.ListVMsResponse\x12;\n" +
+	"\x05GetVM\x12\x1d.vmcontroller.v1.GetVMRequest\x1a\x13.vmcontroller.v1.VM\x12O\n" +
+	"\bCreateVM\x12 .vmcontroller.v1.CreateVMRequest\x1a!.vmcontroller.v1.CreateVMResponse\x12N\n" +
+	"\aStartVM\x12 .vmcontroller.v1.VMActionRequest\x1a!.vmcontroller.v1.VMActionResponse\x12M\n" +
+	"\x06StopVM\x12 .vmcontroller.v1.VMActionRequest\x1a!.vmcontroller.v1.VMActionResponse\x12O\n" +
+	"\bDeleteVM\x12 .vmcontroller.v1.DeleteVMRequest\x1a!.vmcontroller.v1.VMActionResponse\x12P\n" +
+	"\tRestartVM\x12 .vmcontroller.v1.VMActionRequest\x1a!.vmcontroller.v1.VMActionResponse2L\n" +
+	"\vUserService\x12=\n" +
+	"\x05GetMe\x12\x1d.vmcontroller.v1.GetMeRequest\x1a\x15.vmcontroller.v1.UserB4Z2vm-controller/proto/vmcontroller/v1;vmcontrollerv1b\x06proto3"
+
+var (
+	file_vm_controller_proto_rawDescOnce sync.Once
+	file_vm_controller_proto_rawDescData []byte
+)
+
+func file_vm_controller_proto_rawDescGZIP() []byte {
+	file_vm_controller_proto_rawDescOnce.Do(func() {
+		file_vm_controller_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_vm_controller_proto_rawDesc), len(file_vm_controller_proto_rawDesc)))
+	})
+	return file_vm_controller_proto_rawDescData
+}
+
+var file_vm_controller_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_vm_controller_proto_goTypes = []any{
+	(*VM)(nil),                    // 0: vmcontroller.v1.VM
+	(*VMNetwork)(nil),             // 1: vmcontroller.v1.VMNetwork
+	(*ListVMsRequest)(nil),        // 2: vmcontroller.v1.ListVMsRequest
+	(*ListVMsResponse)(nil),       // 3: vmcontroller.v1.ListVMsResponse
+	(*GetVMRequest)(nil),          // 4: vmcontroller.v1.GetVMRequest
+	(*CreateVMRequest)(nil),       // 5: vmcontroller.v1.CreateVMRequest
+	(*CreateVMNetwork)(nil),       // 6: vmcontroller.v1.CreateVMNetwork
+	(*CreateVMResponse)(nil),      // 7: vmcontroller.v1.CreateVMResponse
+	(*VMActionRequest)(nil),       // 8: vmcontroller.v1.VMActionRequest
+	(*DeleteVMRequest)(nil),       // 9: vmcontroller.v1.DeleteVMRequest
+	(*VMActionResponse)(nil),      // 10: vmcontroller.v1.VMActionResponse
+	(*GetMeRequest)(nil),          // 11: vmcontroller.v1.GetMeRequest
+	(*User)(nil),                  // 12: vmcontroller.v1.User
+	(*timestamppb.Timestamp)(nil), // 13: google.protobuf.Timestamp
+}
+var file_vm_controller_proto_depIdxs = []int32{
+	1,  // 0: vmcontroller.v1.VM.networks:type_name -> vmcontroller.v1.VMNetwork
+	13, // 1: vmcontroller.v1.VM.expires_at:type_name -> google.protobuf.Timestamp
+	13, // 2: vmcontroller.v1.VM.created_at:type_name -> google.protobuf.Timestamp
+	13, // 3: vmcontroller.v1.VM.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 4: vmcontroller.v1.ListVMsResponse.vms:type_name -> vmcontroller.v1.VM
+	6,  // 5: vmcontroller.v1.CreateVMRequest.networks:type_name -> vmcontroller.v1.CreateVMNetwork
+	0,  // 6: vmcontroller.v1.CreateVMResponse.vm:type_name -> vmcontroller.v1.VM
+	0,  // 7: vmcontroller.v1.VMActionResponse.vm:type_name -> vmcontroller.v1.VM
+	13, // 8: vmcontroller.v1.User.created_at:type_name -> google.protobuf.Timestamp
+	2,  // 9: vmcontroller.v1.VMService.ListVMs:input_type -> vmcontroller.v1.ListVMsRequest
+	4,  // 10: vmcontroller.v1.VMService.GetVM:input_type -> vmcontroller.v1.GetVMRequest
+	5,  // 11: vmcontroller.v1.VMService.CreateVM:input_type -> vmcontroller.v1.CreateVMRequest
+	8,  // 12: vmcontroller.v1.VMService.StartVM:input_type -> vmcontroller.v1.VMActionRequest
+	8,  // 13: vmcontroller.v1.VMService.StopVM:input_type -> vmcontroller.v1.VMActionRequest
+	9,  // 14: vmcontroller.v1.VMService.DeleteVM:input_type -> vmcontroller.v1.DeleteVMRequest
+	8,  // 15: vmcontroller.v1.VMService.RestartVM:input_type -> vmcontroller.v1.VMActionRequest
+	11, // 16: vmcontroller.v1.UserService.GetMe:input_type -> vmcontroller.v1.GetMeRequest
+	3,  // 17: vmcontroller.v1.VMService.ListVMs:output_type -> vmcontroller.v1.ListVMsResponse
+	0,  // 18: vmcontroller.v1.VMService.GetVM:output_type -> vmcontroller.v1.VM
+	7,  // 19: vmcontroller.v1.VMService.CreateVM:output_type -> vmcontroller.v1.CreateVMResponse
+	10, // 20: vmcontroller.v1.VMService.StartVM:output_type -> vmcontroller.v1.VMActionResponse
+	10, // 21: vmcontroller.v1.VMService.StopVM:output_type -> vmcontroller.v1.VMActionResponse
+	10, // 22: vmcontroller.v1.VMService.DeleteVM:output_type -> vmcontroller.v1.VMActionResponse
+	10, // 23: vmcontroller.v1.VMService.RestartVM:output_type -> vmcontroller.v1.VMActionResponse
+	12, // 24: vmcontroller.v1.UserService.GetMe:output_type -> vmcontroller.v1.User
+	17, // [17:25] is the sub-list for method output_type
+	9,  // [9:17] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_vm_controller_proto_init() }
+func file_vm_controller_proto_init() {
+	if File_vm_controller_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_vm_controller_proto_rawDesc), len(file_vm_controller_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_vm_controller_proto_goTypes,
+		DependencyIndexes: file_vm_controller_proto_depIdxs,
+		MessageInfos:      file_vm_controller_proto_msgTypes,
+	}.Build()
+	File_vm_controller_proto = out.File
+	file_vm_controller_proto_goTypes = nil
+	file_vm_controller_proto_depIdxs = nil
+}
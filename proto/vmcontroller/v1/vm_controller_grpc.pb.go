@@ -0,0 +1,476 @@
+// vm-controller gRPC API (v1)
+//
+// REST API(/api/v1)와 같은 서비스 계층을 사용하는 내부 자동화용 API입니다.
+// 모든 호출은 metadata "authorization: Bearer <token>" (REST 로그인 토큰과 동일)이 필요합니다.
+//
+// 코드 생성: go generate ./proto/... (protoc, protoc-gen-go, protoc-gen-go-grpc 필요)
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.28.3
+// source: vm_controller.proto
+
+package vmcontrollerv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	VMService_ListVMs_FullMethodName   = "/vmcontroller.v1.VMService/ListVMs"
+	VMService_GetVM_FullMethodName     = "/vmcontroller.v1.VMService/GetVM"
+	VMService_CreateVM_FullMethodName  = "/vmcontroller.v1.VMService/CreateVM"
+	VMService_StartVM_FullMethodName   = "/vmcontroller.v1.VMService/StartVM"
+	VMService_StopVM_FullMethodName    = "/vmcontroller.v1.VMService/StopVM"
+	VMService_DeleteVM_FullMethodName  = "/vmcontroller.v1.VMService/DeleteVM"
+	VMService_RestartVM_FullMethodName = "/vmcontroller.v1.VMService/RestartVM"
+)
+
+// VMServiceClient is the client API for VMService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// VMService는 VM 생성/조회/수명 주기 작업을 제공합니다.
+type VMServiceClient interface {
+	// 요청자의 VM 목록 (GET /api/v1/vm/fetch)
+	ListVMs(ctx context.Context, in *ListVMsRequest, opts ...grpc.CallOption) (*ListVMsResponse, error)
+	// VM 상세 (GET /api/v1/vm/:name)
+	GetVM(ctx context.Context, in *GetVMRequest, opts ...grpc.CallOption) (*VM, error)
+	// VM 생성 (POST /api/v1/vm/create). 승인이 필요한 요금제는 approval_id만 채워서 반환
+	CreateVM(ctx context.Context, in *CreateVMRequest, opts ...grpc.CallOption) (*CreateVMResponse, error)
+	// 백그라운드 작업으로 시작/정지/삭제하고 작업 ID를 반환 (GET /api/v1/operations/:id)
+	StartVM(ctx context.Context, in *VMActionRequest, opts ...grpc.CallOption) (*VMActionResponse, error)
+	StopVM(ctx context.Context, in *VMActionRequest, opts ...grpc.CallOption) (*VMActionResponse, error)
+	DeleteVM(ctx context.Context, in *DeleteVMRequest, opts ...grpc.CallOption) (*VMActionResponse, error)
+	// 재부팅 후 Running이 될 때까지 기다림 (제한 시간 초과 시 DEADLINE_EXCEEDED, 재부팅은 계속 진행)
+	RestartVM(ctx context.Context, in *VMActionRequest, opts ...grpc.CallOption) (*VMActionResponse, error)
+}
+
+type vMServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewVMServiceClient(cc grpc.ClientConnInterface) VMServiceClient {
+	return &vMServiceClient{cc}
+}
+
+func (c *vMServiceClient) ListVMs(ctx context.Context, in *ListVMsRequest, opts ...grpc.CallOption) (*ListVMsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListVMsResponse)
+	err := c.cc.Invoke(ctx, VMService_ListVMs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vMServiceClient) GetVM(ctx context.Context, in *GetVMRequest, opts ...grpc.CallOption) (*VM, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VM)
+	err := c.cc.Invoke(ctx, VMService_GetVM_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vMServiceClient) CreateVM(ctx context.Context, in *CreateVMRequest, opts ...grpc.CallOption) (*CreateVMResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateVMResponse)
+	err := c.cc.Invoke(ctx, VMService_CreateVM_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vMServiceClient) StartVM(ctx context.Context, in *VMActionRequest, opts ...grpc.CallOption) (*VMActionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VMActionResponse)
+	err := c.cc.Invoke(ctx, VMService_StartVM_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vMServiceClient) StopVM(ctx context.Context, in *VMActionRequest, opts ...grpc.CallOption) (*VMActionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VMActionResponse)
+	err := c.cc.Invoke(ctx, VMService_StopVM_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vMServiceClient) DeleteVM(ctx context.Context, in *DeleteVMRequest, opts ...grpc.CallOption) (*VMActionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VMActionResponse)
+	err := c.cc.Invoke(ctx, VMService_DeleteVM_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vMServiceClient) RestartVM(ctx context.Context, in *VMActionRequest, opts ...grpc.CallOption) (*VMActionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VMActionResponse)
+	err := c.cc.Invoke(ctx, VMService_RestartVM_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// VMServiceServer is the server API for VMService service.
+// All implementations must embed UnimplementedVMServiceServer
+// for forward compatibility.
+//
+// VMService는 VM 생성/조회/수명 주기 작업을 제공합니다.
+type VMServiceServer interface {
+	// 요청자의 VM 목록 (GET /api/v1/vm/fetch)
+	ListVMs(context.Context, *ListVMsRequest) (*ListVMsResponse, error)
+	// VM 상세 (GET /api/v1/vm/:name)
+	GetVM(context.Context, *GetVMRequest) (*VM, error)
+	// VM 생성 (POST /api/v1/vm/create). 승인이 필요한 요금제는 approval_id만 채워서 반환
+	CreateVM(context.Context, *CreateVMRequest) (*CreateVMResponse, error)
+	// 백그라운드 작업으로 시작/정지/삭제하고 작업 ID를 반환 (GET /api/v1/operations/:id)
+	StartVM(context.Context, *VMActionRequest) (*VMActionResponse, error)
+	StopVM(context.Context, *VMActionRequest) (*VMActionResponse, error)
+	DeleteVM(context.Context, *DeleteVMRequest) (*VMActionResponse, error)
+	// 재부팅 후 Running이 될 때까지 기다림 (제한 시간 초과 시 DEADLINE_EXCEEDED, 재부팅은 계속 진행)
+	RestartVM(context.Context, *VMActionRequest) (*VMActionResponse, error)
+	mustEmbedUnimplementedVMServiceServer()
+}
+
+// UnimplementedVMServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedVMServiceServer struct{}
+
+func (UnimplementedVMServiceServer) ListVMs(context.Context, *ListVMsRequest) (*ListVMsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListVMs not implemented")
+}
+func (UnimplementedVMServiceServer) GetVM(context.Context, *GetVMRequest) (*VM, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVM not implemented")
+}
+func (UnimplementedVMServiceServer) CreateVM(context.Context, *CreateVMRequest) (*CreateVMResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateVM not implemented")
+}
+func (UnimplementedVMServiceServer) StartVM(context.Context, *VMActionRequest) (*VMActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartVM not implemented")
+}
+func (UnimplementedVMServiceServer) StopVM(context.Context, *VMActionRequest) (*VMActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopVM not implemented")
+}
+func (UnimplementedVMServiceServer) DeleteVM(context.Context, *DeleteVMRequest) (*VMActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteVM not implemented")
+}
+func (UnimplementedVMServiceServer) RestartVM(context.Context, *VMActionRequest) (*VMActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestartVM not implemented")
+}
+func (UnimplementedVMServiceServer) mustEmbedUnimplementedVMServiceServer() {}
+func (UnimplementedVMServiceServer) testEmbeddedByValue()                   {}
+
+// UnsafeVMServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to VMServiceServer will
+// result in compilation errors.
+type UnsafeVMServiceServer interface {
+	mustEmbedUnimplementedVMServiceServer()
+}
+
+func RegisterVMServiceServer(s grpc.ServiceRegistrar, srv VMServiceServer) {
+	// If the following call pancis, it indicates UnimplementedVMServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&VMService_ServiceDesc, srv)
+}
+
+func _VMService_ListVMs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVMsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VMServiceServer).ListVMs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VMService_ListVMs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VMServiceServer).ListVMs(ctx, req.(*ListVMsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VMService_GetVM_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVMRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VMServiceServer).GetVM(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VMService_GetVM_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VMServiceServer).GetVM(ctx, req.(*GetVMRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VMService_CreateVM_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateVMRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VMServiceServer).CreateVM(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VMService_CreateVM_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VMServiceServer).CreateVM(ctx, req.(*CreateVMRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VMService_StartVM_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VMActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VMServiceServer).StartVM(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VMService_StartVM_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VMServiceServer).StartVM(ctx, req.(*VMActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VMService_StopVM_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VMActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VMServiceServer).StopVM(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VMService_StopVM_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VMServiceServer).StopVM(ctx, req.(*VMActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VMService_DeleteVM_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteVMRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VMServiceServer).DeleteVM(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VMService_DeleteVM_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VMServiceServer).DeleteVM(ctx, req.(*DeleteVMRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VMService_RestartVM_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VMActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VMServiceServer).RestartVM(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VMService_RestartVM_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VMServiceServer).RestartVM(ctx, req.(*VMActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// VMService_ServiceDesc is the grpc.ServiceDesc for VMService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var VMService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "vmcontroller.v1.VMService",
+	HandlerType: (*VMServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListVMs",
+			Handler:    _VMService_ListVMs_Handler,
+		},
+		{
+			MethodName: "GetVM",
+			Handler:    _VMService_GetVM_Handler,
+		},
+		{
+			MethodName: "CreateVM",
+			Handler:    _VMService_CreateVM_Handler,
+		},
+		{
+			MethodName: "StartVM",
+			Handler:    _VMService_StartVM_Handler,
+		},
+		{
+			MethodName: "StopVM",
+			Handler:    _VMService_StopVM_Handler,
+		},
+		{
+			MethodName: "DeleteVM",
+			Handler:    _VMService_DeleteVM_Handler,
+		},
+		{
+			MethodName: "RestartVM",
+			Handler:    _VMService_RestartVM_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "vm_controller.proto",
+}
+
+const (
+	UserService_GetMe_FullMethodName = "/vmcontroller.v1.UserService/GetMe"
+)
+
+// UserServiceClient is the client API for UserService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// UserService는 요청자 계정 정보를 제공합니다.
+type UserServiceClient interface {
+	GetMe(ctx context.Context, in *GetMeRequest, opts ...grpc.CallOption) (*User, error)
+}
+
+type userServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUserServiceClient(cc grpc.ClientConnInterface) UserServiceClient {
+	return &userServiceClient{cc}
+}
+
+func (c *userServiceClient) GetMe(ctx context.Context, in *GetMeRequest, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, UserService_GetMe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UserServiceServer is the server API for UserService service.
+// All implementations must embed UnimplementedUserServiceServer
+// for forward compatibility.
+//
+// UserService는 요청자 계정 정보를 제공합니다.
+type UserServiceServer interface {
+	GetMe(context.Context, *GetMeRequest) (*User, error)
+	mustEmbedUnimplementedUserServiceServer()
+}
+
+// UnimplementedUserServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedUserServiceServer struct{}
+
+func (UnimplementedUserServiceServer) GetMe(context.Context, *GetMeRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMe not implemented")
+}
+func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
+func (UnimplementedUserServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeUserServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UserServiceServer will
+// result in compilation errors.
+type UnsafeUserServiceServer interface {
+	mustEmbedUnimplementedUserServiceServer()
+}
+
+func RegisterUserServiceServer(s grpc.ServiceRegistrar, srv UserServiceServer) {
+	// If the following call pancis, it indicates UnimplementedUserServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&UserService_ServiceDesc, srv)
+}
+
+func _UserService_GetMe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetMe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetMe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetMe(ctx, req.(*GetMeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UserService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "vmcontroller.v1.UserService",
+	HandlerType: (*UserServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetMe",
+			Handler:    _UserService_GetMe_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "vm_controller.proto",
+}